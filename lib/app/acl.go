@@ -22,6 +22,7 @@ import (
 
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/pack"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/users"
 
@@ -178,11 +179,11 @@ func (r *ApplicationsACL) CreateApp(locator loc.Locator, reader io.Reader, label
 // CreateAppWithManifest creates a new application from the specified package bytes (reader)
 // and an optional set of package labels using locator as destination for the
 // resulting package, with supplied manifest
-func (r *ApplicationsACL) CreateAppWithManifest(locator loc.Locator, manifest []byte, reader io.Reader, labels map[string]string) (*Application, error) {
+func (r *ApplicationsACL) CreateAppWithManifest(locator loc.Locator, manifest []byte, reader io.Reader, labels map[string]string, options ...pack.PackageOption) (*Application, error) {
 	if err := r.check(locator.Repository, teleservices.VerbCreate); err != nil {
 		return nil, trace.Wrap(err)
 	}
-	return r.applications.CreateAppWithManifest(locator, manifest, reader, labels)
+	return r.applications.CreateAppWithManifest(locator, manifest, reader, labels, options...)
 }
 
 func (r *ApplicationsACL) UpsertApp(locator loc.Locator, reader io.Reader, labels map[string]string) (*Application, error) {
@@ -24,9 +24,11 @@ import (
 	"io"
 	"time"
 
+	"github.com/gravitational/gravity/lib/app/delta"
 	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/pack/signing"
 	"github.com/gravitational/gravity/lib/schema"
 	"github.com/gravitational/gravity/lib/storage"
 
@@ -80,8 +82,9 @@ type Applications interface {
 
 	// CreateAppWithManifest creates a new application from the specified package bytes (reader)
 	// and an optional set of package labels using locator as destination for the
-	// resulting package, with supplied manifest
-	CreateAppWithManifest(locator loc.Locator, manifest []byte, reader io.Reader, labels map[string]string) (*Application, error)
+	// resulting package, with supplied manifest.
+	// Options can specify additional package attributes, like a signature
+	CreateAppWithManifest(locator loc.Locator, manifest []byte, reader io.Reader, labels map[string]string, options ...pack.PackageOption) (*Application, error)
 
 	// UpsertApp creates a new application or updates the existing one
 	UpsertApp(locator loc.Locator, reader io.Reader, labels map[string]string) (*Application, error)
@@ -272,6 +275,13 @@ type InstallerRequest struct {
 	CACert string `json:"ca_cert,omitempty"`
 	// EncryptionKey is encryption key to encrypt installer packages with
 	EncryptionKey string `json:"encryption_key,omitempty"`
+	// Signer optionally signs all installer packages.
+	// Only applies to installers generated locally
+	Signer *signing.Signer `json:"-"`
+	// DeltaBase optionally specifies the cluster image to omit the packages
+	// and container image layers of from the installer.
+	// Only applies to installers generated locally
+	DeltaBase *delta.Base `json:"-"`
 }
 
 // Check validates this request
@@ -282,6 +292,12 @@ func (r InstallerRequest) Check() error {
 	if r.EncryptionKey != "" && r.CACert == "" {
 		return trace.BadParameter("CACert is required when EncryptionKey is provided")
 	}
+	if r.DeltaBase != nil && r.EncryptionKey != "" {
+		return trace.BadParameter("delta installers can not be encrypted")
+	}
+	if r.DeltaBase != nil && r.DeltaBase.Application.IsEqualTo(r.Application) {
+		return trace.BadParameter("delta base %v is the same as the application", r.Application)
+	}
 	return nil
 }
 
@@ -32,6 +32,7 @@ import (
 	serviceapi "github.com/gravitational/gravity/lib/app/api"
 	"github.com/gravitational/gravity/lib/httplib"
 	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/pack"
 	"github.com/gravitational/gravity/lib/storage"
 	helmutils "github.com/gravitational/gravity/lib/utils/helm"
 
@@ -402,8 +403,8 @@ func (c *Client) CreateApp(locator loc.Locator, reader io.Reader, labels map[str
 	return c.createApp(locator, nil, reader, labels, false)
 }
 
-func (c *Client) CreateAppWithManifest(locator loc.Locator, manifest []byte, reader io.Reader, labels map[string]string) (*app.Application, error) {
-	return c.createApp(locator, manifest, reader, labels, false)
+func (c *Client) CreateAppWithManifest(locator loc.Locator, manifest []byte, reader io.Reader, labels map[string]string, options ...pack.PackageOption) (*app.Application, error) {
+	return c.createApp(locator, manifest, reader, labels, false, options...)
 }
 
 // POST app/v1/applications/:repository_id
@@ -411,7 +412,7 @@ func (c *Client) UpsertApp(locator loc.Locator, reader io.Reader, labels map[str
 	return c.createApp(locator, nil, reader, labels, true)
 }
 
-func (c *Client) createApp(locator loc.Locator, manifest []byte, reader io.Reader, labels map[string]string, upsert bool) (*app.Application, error) {
+func (c *Client) createApp(locator loc.Locator, manifest []byte, reader io.Reader, labels map[string]string, upsert bool, options ...pack.PackageOption) (*app.Application, error) {
 	file := roundtrip.File{
 		Name:     "package",
 		Filename: locator.String(),
@@ -428,6 +429,17 @@ func (c *Client) createApp(locator loc.Locator, manifest []byte, reader io.Reade
 	if len(manifest) != 0 {
 		params.Set("manifest", string(manifest))
 	}
+	var pkg storage.Package
+	for _, option := range options {
+		option(&pkg)
+	}
+	if pkg.Signature != nil {
+		signatureJSON, err := json.Marshal(pkg.Signature)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		params.Set("signature", string(signatureJSON))
+	}
 
 	out, err := c.PostForm(c.Endpoint("applications", locator.Repository), params, file)
 	if err != nil {
@@ -0,0 +1,317 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package delta implements delta cluster images that only carry the packages
+and container image layers missing from a previous version of the image.
+
+A delta is computed against the base cluster image: packages the base image
+contains with identical contents are removed altogether and container image
+layers found in the registries of the base applications are removed from the
+application packages. Application packages with removed layers are marked with
+pack.DeltaBaseLabel so the missing layers can be restored from a cluster that
+has the base image.
+*/
+package delta
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/schema"
+
+	dockerarchive "github.com/docker/docker/pkg/archive"
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+)
+
+// Base indexes the contents of the cluster image a delta is computed against
+type Base struct {
+	// Application is the locator of the base cluster image
+	Application loc.Locator
+	// packages maps locators of the base image packages to their checksums
+	packages map[loc.Locator]string
+	// layers is the set of container image layer digests of the base image
+	layers map[string]struct{}
+}
+
+// NewBase indexes the cluster image specified with locator along with
+// all its dependencies found in packages
+func NewBase(packages pack.PackageService, locator loc.Locator) (*Base, error) {
+	apps, err := getApps(packages, locator)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	base := &Base{
+		Application: locator,
+		packages:    make(map[loc.Locator]string),
+		layers:      make(map[string]struct{}),
+	}
+	for _, app := range apps {
+		base.packages[app.envelope.Locator] = app.envelope.SHA512
+		for _, dependency := range app.manifest.AllPackageDependencies() {
+			envelope, err := packages.ReadPackageEnvelope(dependency)
+			if err != nil {
+				if trace.IsNotFound(err) {
+					// The base can be a delta image itself
+					continue
+				}
+				return nil, trace.Wrap(err)
+			}
+			base.packages[envelope.Locator] = envelope.SHA512
+		}
+		err = forEachLayer(packages, app.envelope.Locator, func(digest string, _ *tar.Header, _ io.Reader) error {
+			base.layers[digest] = struct{}{}
+			return nil
+		})
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	return base, nil
+}
+
+// Strip removes the packages and the container image layers present in base
+// from the specified package service
+func Strip(packages pack.PackageService, base Base, logger logrus.FieldLogger) error {
+	return pack.ForeachPackage(packages, func(envelope pack.PackageEnvelope) error {
+		logger := logger.WithField("package", envelope.Locator)
+		if checksum, ok := base.packages[envelope.Locator]; ok && checksum == envelope.SHA512 {
+			logger.Info("Package is present in the base image.")
+			return trace.Wrap(packages.DeletePackage(envelope.Locator))
+		}
+		if !isApp(envelope) {
+			return nil
+		}
+		return trace.Wrap(stripApp(packages, envelope, base, logger))
+	})
+}
+
+// IsDelta returns true if the package described by envelope
+// is an application package with container image layers removed
+func IsDelta(envelope pack.PackageEnvelope) bool {
+	return envelope.RuntimeLabels[pack.DeltaBaseLabel] != ""
+}
+
+// stripApp removes container image layers present in base from
+// the application package specified with envelope
+func stripApp(packages pack.PackageService, envelope pack.PackageEnvelope, base Base, logger logrus.FieldLogger) error {
+	_, reader, err := packages.ReadPackage(envelope.Locator)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer reader.Close()
+
+	file, err := ioutil.TempFile("", "delta")
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	defer func() {
+		file.Close()
+		os.Remove(file.Name())
+	}()
+
+	var removed int
+	err = rewrite(reader, file, func(header *tar.Header, tarball *tar.Writer, r io.Reader) error {
+		if digest, ok := layerDigest(header); ok {
+			if _, exists := base.layers[digest]; exists {
+				removed++
+				return nil
+			}
+		}
+		return trace.Wrap(copyEntry(tarball, header, r))
+	}, nil)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if removed == 0 {
+		return nil
+	}
+	logger.Infof("Removed %v container image layers present in the base image.", removed)
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	labels := make(map[string]string, len(envelope.RuntimeLabels)+1)
+	for label, value := range envelope.RuntimeLabels {
+		labels[label] = value
+	}
+	labels[pack.DeltaBaseLabel] = base.Application.String()
+	options := append(envelope.Options(), pack.WithLabels(labels))
+	_, err = packages.UpsertPackage(envelope.Locator, file, options...)
+	return trace.Wrap(err)
+}
+
+// getApps returns the application specified with locator along with
+// its base and dependency applications found in packages
+func getApps(packages pack.PackageService, locator loc.Locator) (apps []application, err error) {
+	seen := make(map[loc.Locator]struct{})
+	queue := []loc.Locator{locator}
+	for len(queue) != 0 {
+		locator := queue[0]
+		queue = queue[1:]
+		if _, ok := seen[locator]; ok {
+			continue
+		}
+		seen[locator] = struct{}{}
+		envelope, err := packages.ReadPackageEnvelope(locator)
+		if err != nil {
+			if trace.IsNotFound(err) && len(apps) != 0 {
+				// The base can be a delta image itself
+				continue
+			}
+			return nil, trace.Wrap(err)
+		}
+		if !isApp(*envelope) {
+			return nil, trace.BadParameter("%v is not an application package", locator)
+		}
+		manifest, err := schema.ParseManifestYAMLNoValidate(envelope.Manifest)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		apps = append(apps, application{envelope: *envelope, manifest: *manifest})
+		if base := manifest.Base(); base != nil {
+			queue = append(queue, *base)
+		}
+		queue = append(queue, manifest.Dependencies.GetApps()...)
+	}
+	return apps, nil
+}
+
+// forEachLayer invokes fn for every container image layer
+// in the application package specified with locator
+func forEachLayer(packages pack.PackageService, locator loc.Locator, fn func(digest string, header *tar.Header, r io.Reader) error) error {
+	_, reader, err := packages.ReadPackage(locator)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer reader.Close()
+	source, err := dockerarchive.DecompressStream(reader)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer source.Close()
+	tarball := tar.NewReader(source)
+	for {
+		header, err := tarball.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if digest, ok := layerDigest(header); ok {
+			if err := fn(digest, header, tarball); err != nil {
+				return trace.Wrap(err)
+			}
+		}
+	}
+}
+
+// rewrite writes the application package read from r as a compressed
+// tarball to w invoking fn to write each entry.
+// If specified, finish is invoked to add entries after the last one
+func rewrite(r io.Reader, w io.Writer, fn func(header *tar.Header, tarball *tar.Writer, r io.Reader) error, finish func(*tar.Writer) error) error {
+	source, err := dockerarchive.DecompressStream(r)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer source.Close()
+	output, err := dockerarchive.CompressStream(w, dockerarchive.Gzip)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	tarball := tar.NewWriter(output)
+	reader := tar.NewReader(source)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if err := fn(header, tarball, reader); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if finish != nil {
+		if err := finish(tarball); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if err := tarball.Close(); err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(output.Close())
+}
+
+// copyEntry writes the tarball entry given with header and r to tarball
+func copyEntry(tarball *tar.Writer, header *tar.Header, r io.Reader) error {
+	if err := tarball.WriteHeader(header); err != nil {
+		return trace.Wrap(err)
+	}
+	_, err := io.Copy(tarball, r)
+	return trace.Wrap(err)
+}
+
+// layerDigest returns the digest of the container image layer
+// if header describes the layer data file in the application registry
+func layerDigest(header *tar.Header) (digest string, ok bool) {
+	if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+		return "", false
+	}
+	match := layerPath.FindStringSubmatch(entryName(header))
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// isLink returns true if header describes a link file
+// in the application registry
+func isLink(header *tar.Header) bool {
+	name := entryName(header)
+	return strings.HasPrefix(name, repositoriesDir) && strings.HasSuffix(name, "/link")
+}
+
+// entryName returns the normalized path of the tarball entry
+func entryName(header *tar.Header) string {
+	return strings.TrimPrefix(strings.TrimPrefix(header.Name, "./"), "/")
+}
+
+func isApp(envelope pack.PackageEnvelope) bool {
+	return envelope.Type != "" && len(envelope.Manifest) != 0
+}
+
+type application struct {
+	envelope pack.PackageEnvelope
+	manifest schema.Manifest
+}
+
+// layerPath matches the paths of layer data files in the registry
+// of an application package
+var layerPath = regexp.MustCompile(`^registry/docker/registry/v2/blobs/sha256/[0-9a-f]{2}/([0-9a-f]{64})/data$`)
+
+// repositoriesDir is the directory with image repositories in the registry
+// of an application package
+const repositoriesDir = "registry/docker/registry/v2/repositories/"
@@ -0,0 +1,226 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delta
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/schema"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/testutils"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+	. "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDelta(t *testing.T) { TestingT(t) }
+
+type DeltaSuite struct {
+	// base is the package service with the base cluster image
+	base pack.PackageService
+	// image is the package service with the new version of the cluster image
+	image pack.PackageService
+}
+
+var _ = Suite(&DeltaSuite{})
+
+func (s *DeltaSuite) SetUpTest(c *C) {
+	s.base = newPackages(c)
+	createPackage(c, s.base, "gravitational.io/planet:0.0.1", "planet")
+	createApp(c, s.base, "gravitational.io/kubernetes:0.0.1", schema.KindRuntime, "",
+		[]string{"gravitational.io/planet:0.0.1"}, "runtime-layer-1", "runtime-layer-2")
+	createApp(c, s.base, "gravitational.io/app:0.0.1", schema.KindBundle,
+		"gravitational.io/kubernetes:0.0.1", nil, "app-layer-1", "app-layer-2")
+
+	s.image = newPackages(c)
+	createPackage(c, s.image, "gravitational.io/planet:0.0.1", "planet")
+	createApp(c, s.image, "gravitational.io/kubernetes:0.0.1", schema.KindRuntime, "",
+		[]string{"gravitational.io/planet:0.0.1"}, "runtime-layer-1", "runtime-layer-2")
+	createApp(c, s.image, "gravitational.io/app:0.0.2", schema.KindBundle,
+		"gravitational.io/kubernetes:0.0.1", nil,
+		"app-layer-1", "runtime-layer-2", "app-layer-3")
+}
+
+func (s *DeltaSuite) TestStripsPackagesPresentInBase(c *C) {
+	base, err := NewBase(s.base, loc.MustParseLocator("gravitational.io/app:0.0.1"))
+	c.Assert(err, IsNil)
+	c.Assert(Strip(s.image, *base, logrus.StandardLogger()), IsNil)
+
+	for _, locator := range []string{"gravitational.io/planet:0.0.1", "gravitational.io/kubernetes:0.0.1"} {
+		_, err := s.image.ReadPackageEnvelope(loc.MustParseLocator(locator))
+		c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%v should have been removed.", locator))
+	}
+
+	envelope, err := s.image.ReadPackageEnvelope(loc.MustParseLocator("gravitational.io/app:0.0.2"))
+	c.Assert(err, IsNil)
+	c.Assert(IsDelta(*envelope), Equals, true)
+	c.Assert(envelope.RuntimeLabels[pack.DeltaBaseLabel], Equals, "gravitational.io/app:0.0.1")
+	c.Assert(len(envelope.Manifest), Not(Equals), 0)
+	c.Assert(readLayers(c, s.image, envelope.Locator), DeepEquals, []string{"app-layer-3"})
+}
+
+func (s *DeltaSuite) TestReconstructsDeltaPackages(c *C) {
+	base, err := NewBase(s.base, loc.MustParseLocator("gravitational.io/app:0.0.1"))
+	c.Assert(err, IsNil)
+	c.Assert(Strip(s.image, *base, logrus.StandardLogger()), IsNil)
+
+	locator := loc.MustParseLocator("gravitational.io/app:0.0.2")
+	stripped, err := s.image.ReadPackageEnvelope(locator)
+	c.Assert(err, IsNil)
+
+	packages := NewPackages(s.image, s.base)
+	envelope, reader, err := packages.ReadPackage(locator)
+	c.Assert(err, IsNil)
+	defer reader.Close()
+	c.Assert(envelope.SHA512, Equals, stripped.SHA512)
+
+	restored := newPackages(c)
+	c.Assert(restored.UpsertRepository("gravitational.io", time.Time{}), IsNil)
+	_, err = restored.CreatePackage(locator, reader)
+	c.Assert(err, IsNil)
+	c.Assert(readLayers(c, restored, locator), DeepEquals,
+		[]string{"app-layer-1", "app-layer-3", "runtime-layer-2"})
+}
+
+func (s *DeltaSuite) TestFailsWithoutBase(c *C) {
+	base, err := NewBase(s.base, loc.MustParseLocator("gravitational.io/app:0.0.1"))
+	c.Assert(err, IsNil)
+	c.Assert(Strip(s.image, *base, logrus.StandardLogger()), IsNil)
+
+	_, _, err = NewPackages(s.image, newPackages(c)).ReadPackage(
+		loc.MustParseLocator("gravitational.io/app:0.0.2"))
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%v", err))
+}
+
+func (s *DeltaSuite) TestVerifiesDeltaChecksum(c *C) {
+	base, err := NewBase(s.base, loc.MustParseLocator("gravitational.io/app:0.0.1"))
+	c.Assert(err, IsNil)
+	c.Assert(Strip(s.image, *base, logrus.StandardLogger()), IsNil)
+
+	_, reader, err := s.image.ReadPackage(loc.MustParseLocator("gravitational.io/app:0.0.2"))
+	c.Assert(err, IsNil)
+	defer reader.Close()
+	data, err := ioutil.ReadAll(reader)
+	c.Assert(err, IsNil)
+
+	envelope := pack.PackageEnvelope{
+		Locator:       loc.MustParseLocator("gravitational.io/app:0.0.2"),
+		SHA512:        "checksum",
+		RuntimeLabels: map[string]string{pack.DeltaBaseLabel: "gravitational.io/app:0.0.1"},
+	}
+	err = Reconstruct(bytes.NewReader(data), ioutil.Discard, envelope, s.base)
+	c.Assert(trace.IsCompareFailed(err), Equals, true, Commentf("%v", err))
+}
+
+func newPackages(c *C) pack.PackageService {
+	_, packages := testutils.NewPackageService(c)
+	return packages
+}
+
+func createPackage(c *C, packages pack.PackageService, locator, data string) {
+	c.Assert(packages.UpsertRepository("gravitational.io", time.Time{}), IsNil)
+	_, err := packages.CreatePackage(loc.MustParseLocator(locator), bytes.NewBufferString(data))
+	c.Assert(err, IsNil)
+}
+
+// createApp creates an application package with the specified
+// base application, package dependencies and container image layers
+func createApp(c *C, packages pack.PackageService, locator, kind, base string, dependencies []string, layers ...string) {
+	app := loc.MustParseLocator(locator)
+	manifest := schema.Manifest{
+		Header: schema.Header{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       kind,
+				APIVersion: schema.APIVersionV2,
+			},
+			Metadata: schema.Metadata{
+				Name:            app.Name,
+				ResourceVersion: app.Version,
+				Repository:      app.Repository,
+			},
+		},
+	}
+	if base != "" {
+		manifest.SetBase(loc.MustParseLocator(base))
+	}
+	for _, dependency := range dependencies {
+		manifest.Dependencies.Packages = append(manifest.Dependencies.Packages,
+			schema.Dependency{Locator: loc.MustParseLocator(dependency)})
+	}
+	manifestBytes, err := json.Marshal(manifest)
+	c.Assert(err, IsNil)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tarball := tar.NewWriter(gz)
+	writeFile(c, tarball, "resources/app.yaml", string(manifestBytes))
+	for _, layer := range layers {
+		digest := fmt.Sprintf("%x", sha256.Sum256([]byte(layer)))
+		writeFile(c, tarball, fmt.Sprintf("registry/docker/registry/v2/blobs/sha256/%v/%v/data",
+			digest[:2], digest), layer)
+		writeFile(c, tarball, fmt.Sprintf("registry/docker/registry/v2/repositories/%v/_layers/sha256/%v/link",
+			app.Name, digest), "sha256:"+digest)
+	}
+	c.Assert(tarball.Close(), IsNil)
+	c.Assert(gz.Close(), IsNil)
+
+	c.Assert(packages.UpsertRepository(app.Repository, time.Time{}), IsNil)
+	_, err = packages.CreatePackage(app, &buf,
+		pack.WithManifest(string(storage.AppUser), manifestBytes))
+	c.Assert(err, IsNil)
+}
+
+func writeFile(c *C, tarball *tar.Writer, name, data string) {
+	c.Assert(tarball.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(data)),
+		Typeflag: tar.TypeReg,
+	}), IsNil)
+	_, err := tarball.Write([]byte(data))
+	c.Assert(err, IsNil)
+}
+
+// readLayers returns the sorted contents of the container image layers
+// in the application package specified with locator
+func readLayers(c *C, packages pack.PackageService, locator loc.Locator) (layers []string) {
+	err := forEachLayer(packages, locator, func(_ string, _ *tar.Header, r io.Reader) error {
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		layers = append(layers, string(data))
+		return nil
+	})
+	c.Assert(err, IsNil)
+	sort.Strings(layers)
+	return layers
+}
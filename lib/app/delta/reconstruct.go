@@ -0,0 +1,184 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delta
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha512"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+)
+
+// NewPackages returns a package service that reads packages from packages
+// and reconstructs delta application packages using the base cluster image
+// from base
+func NewPackages(packages, base pack.PackageService) *Packages {
+	return &Packages{
+		PackageService: packages,
+		base:           base,
+	}
+}
+
+// Packages is a package service that restores the container image layers
+// missing from delta application packages on read
+type Packages struct {
+	pack.PackageService
+	// base is the package service with the base cluster image
+	base pack.PackageService
+}
+
+// ReadPackage returns the contents of the package specified with locator.
+// Delta application packages are reconstructed while the returned envelope
+// retains the checksum and the signature of the delta package
+func (r *Packages) ReadPackage(locator loc.Locator) (*pack.PackageEnvelope, io.ReadCloser, error) {
+	envelope, reader, err := r.PackageService.ReadPackage(locator)
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	if !IsDelta(*envelope) {
+		return envelope, reader, nil
+	}
+	defer reader.Close()
+
+	file, err := ioutil.TempFile("", "delta")
+	if err != nil {
+		return nil, nil, trace.ConvertSystemError(err)
+	}
+	cleanup := func() {
+		os.Remove(file.Name())
+	}
+	err = Reconstruct(reader, file, *envelope, r.base)
+	if err == nil {
+		envelope.SizeBytes, err = file.Seek(0, io.SeekCurrent)
+	}
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		file.Close()
+		cleanup()
+		return nil, nil, trace.Wrap(err)
+	}
+	return envelope, &utils.CleanupReadCloser{
+		ReadCloser: file,
+		Cleanup:    cleanup,
+	}, nil
+}
+
+// Reconstruct writes the delta application package read from r to w
+// restoring the container image layers missing from it with the layers of the
+// base cluster image from packages.
+//
+// The contents of the delta package are verified against the checksum
+// in envelope. Returns trace.NotFound if the base cluster image is not
+// available in packages
+func Reconstruct(r io.Reader, w io.Writer, envelope pack.PackageEnvelope, packages pack.PackageService) error {
+	baseLocator, err := loc.ParseLocator(envelope.RuntimeLabels[pack.DeltaBaseLabel])
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	hasher := sha512.New()
+	reader := io.TeeReader(r, hasher)
+	present := make(map[string]struct{})
+	referenced := make(map[string]struct{})
+	err = rewrite(reader, w, func(header *tar.Header, tarball *tar.Writer, r io.Reader) error {
+		if digest, ok := layerDigest(header); ok {
+			present[digest] = struct{}{}
+		}
+		if !isLink(header) {
+			return trace.Wrap(copyEntry(tarball, header, r))
+		}
+		var buf bytes.Buffer
+		err := copyEntry(tarball, header, io.TeeReader(r, &buf))
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		digest := strings.TrimPrefix(strings.TrimSpace(buf.String()), "sha256:")
+		referenced[digest] = struct{}{}
+		return nil
+	}, func(tarball *tar.Writer) error {
+		for digest := range present {
+			delete(referenced, digest)
+		}
+		if len(referenced) == 0 {
+			return nil
+		}
+		return trace.Wrap(restoreLayers(tarball, referenced, envelope.Locator, *baseLocator, packages))
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	// Consume the rest of the package so the checksum covers all of it
+	if _, err := io.Copy(ioutil.Discard, reader); err != nil {
+		return trace.Wrap(err)
+	}
+	checksum := fmt.Sprintf("%x", hasher.Sum(nil)[:sha512.Size/2])
+	if checksum != envelope.SHA512 {
+		return trace.CompareFailed("delta package %v checksum mismatch: expected %v, got %v",
+			envelope.Locator, envelope.SHA512, checksum)
+	}
+	return nil
+}
+
+// restoreLayers writes the container image layers specified with missing
+// from the base cluster image in packages to tarball
+func restoreLayers(tarball *tar.Writer, missing map[string]struct{}, locator, baseLocator loc.Locator, packages pack.PackageService) error {
+	apps, err := getApps(packages, baseLocator)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.NotFound("delta package %v requires the base cluster image %v "+
+				"which is not available", locator, baseLocator)
+		}
+		return trace.Wrap(err)
+	}
+	for _, app := range apps {
+		if len(missing) == 0 {
+			return nil
+		}
+		err := forEachLayer(packages, app.envelope.Locator, func(digest string, header *tar.Header, r io.Reader) error {
+			if _, ok := missing[digest]; !ok {
+				return nil
+			}
+			delete(missing, digest)
+			return trace.Wrap(copyEntry(tarball, header, r))
+		})
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if len(missing) != 0 {
+		digests := make([]string, 0, len(missing))
+		for digest := range missing {
+			digests = append(digests, digest)
+		}
+		sort.Strings(digests)
+		return trace.NotFound("container image layers %v of delta package %v "+
+			"are missing from the base cluster image %v", digests, locator, baseLocator)
+	}
+	return nil
+}
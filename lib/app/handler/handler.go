@@ -538,12 +538,14 @@ func (h *WebHandler) createApp(w http.ResponseWriter, req *http.Request,
 	var labelsMap string
 	var upsertS string
 	var manifestS string
+	var signatureJSON string
 
 	err := form.Parse(req,
 		form.FileSlice("package", &files),
 		form.String("labels", &labelsMap),
 		form.String("upsert", &upsertS),
 		form.String("manifest", &manifestS),
+		form.String("signature", &signatureJSON),
 	)
 	if err != nil {
 		return trace.Wrap(err)
@@ -576,12 +578,21 @@ func (h *WebHandler) createApp(w http.ResponseWriter, req *http.Request,
 		return trace.Wrap(err)
 	}
 
+	var options []pack.PackageOption
+	if signatureJSON != "" {
+		var signature storage.PackageSignature
+		if err := json.Unmarshal([]byte(signatureJSON), &signature); err != nil {
+			return trace.BadParameter("invalid package signature: %v", err)
+		}
+		options = append(options, pack.WithSignature(&signature))
+	}
+
 	var application *app.Application
 	if upsert {
 		application, err = context.applications.UpsertApp(*locator, reader, labels)
 	} else {
 		if len(manifestS) != 0 {
-			application, err = context.applications.CreateAppWithManifest(*locator, []byte(manifestS), reader, labels)
+			application, err = context.applications.CreateAppWithManifest(*locator, []byte(manifestS), reader, labels, options...)
 		} else {
 			application, err = context.applications.CreateApp(*locator, reader, labels)
 		}
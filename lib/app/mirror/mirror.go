@@ -0,0 +1,318 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package mirror implements incremental mirroring of applications
+along with all their dependencies between application services,
+for example from an Ops Center into a local package directory
+or into another Ops Center.
+*/
+package mirror
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/gravitational/gravity/lib/app"
+	"github.com/gravitational/gravity/lib/app/service"
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+)
+
+// Config defines the mirror configuration
+type Config struct {
+	// SrcPack is the package service to mirror packages from
+	SrcPack pack.PackageService
+	// SrcApp is the application service to mirror applications from
+	SrcApp app.Applications
+	// DstPack is the package service to mirror packages into
+	DstPack pack.PackageService
+	// DstApp is the application service to mirror applications into
+	DstApp app.Applications
+	// Filter selects the applications to mirror
+	Filter Filter
+	// Progress is optional progress reporter
+	Progress pack.ProgressReporter
+	// Verifier optionally verifies signatures of the mirrored packages
+	Verifier pack.Verifier
+	// FieldLogger is used for logging
+	logrus.FieldLogger
+}
+
+// CheckAndSetDefaults validates the configuration and sets defaults
+func (r *Config) CheckAndSetDefaults() error {
+	if r.SrcPack == nil {
+		return trace.BadParameter("missing SrcPack")
+	}
+	if r.SrcApp == nil {
+		return trace.BadParameter("missing SrcApp")
+	}
+	if r.DstPack == nil {
+		return trace.BadParameter("missing DstPack")
+	}
+	if r.DstApp == nil {
+		return trace.BadParameter("missing DstApp")
+	}
+	if r.FieldLogger == nil {
+		r.FieldLogger = logrus.WithField(trace.Component, "mirror")
+	}
+	return nil
+}
+
+// Filter selects the applications to mirror
+type Filter struct {
+	// Apps selects applications by name and version.
+	// If empty, all user applications are selected
+	Apps []Selector
+	// Labels selects applications that have all of the specified labels
+	Labels map[string]string
+	// Runtimes additionally selects runtime applications if Apps is empty
+	Runtimes bool
+}
+
+// Matches returns true if the specified application is selected by this filter
+func (r Filter) Matches(application app.Application) bool {
+	for key, value := range r.Labels {
+		if application.PackageEnvelope.RuntimeLabels[key] != value {
+			return false
+		}
+	}
+	if len(r.Apps) == 0 {
+		switch storage.AppType(application.PackageEnvelope.Type) {
+		case storage.AppUser:
+			return true
+		case storage.AppRuntime:
+			return r.Runtimes
+		default:
+			return false
+		}
+	}
+	for _, selector := range r.Apps {
+		if selector.Matches(application.Package) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSelector parses the application selector in the name[:constraint]
+// format, for example "app" or "app:>=1.0 <2.0"
+func ParseSelector(s string) (*Selector, error) {
+	parts := strings.SplitN(s, ":", 2)
+	selector := Selector{Name: strings.TrimSpace(parts[0])}
+	if selector.Name == "" {
+		return nil, trace.BadParameter("application name can not be empty: %q", s)
+	}
+	if len(parts) == 2 {
+		versions, err := loc.ParseVersionConstraint(parts[1])
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		selector.Versions = versions
+	}
+	return &selector, nil
+}
+
+// Selector selects applications by name and version
+type Selector struct {
+	// Name is the application name
+	Name string
+	// Versions optionally restricts the selected versions
+	Versions *loc.VersionConstraint
+}
+
+// Matches returns true if the application specified with locator is selected
+func (r Selector) Matches(locator loc.Locator) bool {
+	if locator.Name != r.Name {
+		return false
+	}
+	return r.Versions == nil || r.Versions.Check(locator.Version)
+}
+
+// String returns a textual representation of this selector
+func (r Selector) String() string {
+	if r.Versions == nil {
+		return r.Name
+	}
+	return r.Name + ":" + r.Versions.String()
+}
+
+// Plan describes what is to be mirrored
+type Plan struct {
+	// Apps lists applications missing from the destination, dependencies first
+	Apps []loc.Locator
+	// Packages lists packages missing from the destination
+	Packages []loc.Locator
+	// Existing lists selected applications already present in the destination
+	Existing []loc.Locator
+}
+
+// IsEmpty returns true if there's nothing to mirror
+func (r Plan) IsEmpty() bool {
+	return len(r.Apps) == 0 && len(r.Packages) == 0
+}
+
+// New returns a new mirror for the provided configuration
+func New(config Config) (*Mirror, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &Mirror{Config: config}, nil
+}
+
+// Mirror mirrors applications between application services
+type Mirror struct {
+	// Config is the mirror configuration
+	Config
+}
+
+// Plan selects the applications to mirror and resolves their dependencies.
+// Applications and packages already present in the destination are not
+// mirrored again
+func (r *Mirror) Plan(ctx context.Context) (*Plan, error) {
+	repositories, err := r.SrcPack.GetRepositories()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var apps []app.Application
+	for _, repository := range repositories {
+		items, err := r.SrcApp.ListApps(app.ListAppsRequest{
+			Repository: repository,
+		})
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		apps = append(apps, items...)
+	}
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].Package.String() < apps[j].Package.String()
+	})
+	plan := &Plan{}
+	seenApps := make(map[loc.Locator]struct{})
+	seenPackages := make(map[loc.Locator]struct{})
+	for _, application := range apps {
+		if service.IsMetadataPackage(application.PackageEnvelope) || !r.Filter.Matches(application) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		application := application
+		dependencies, err := app.GetDependencies(&application, r.SrcApp)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		for _, locator := range dependencies.Packages {
+			if _, ok := seenPackages[locator]; ok {
+				continue
+			}
+			seenPackages[locator] = struct{}{}
+			exists, err := r.hasPackage(locator)
+			if err != nil {
+				return nil, trace.Wrap(err)
+			}
+			if !exists {
+				plan.Packages = append(plan.Packages, locator)
+			}
+		}
+		for _, locator := range append(dependencies.Apps, application.Package) {
+			if _, ok := seenApps[locator]; ok {
+				continue
+			}
+			seenApps[locator] = struct{}{}
+			exists, err := r.hasApp(locator)
+			if err != nil {
+				return nil, trace.Wrap(err)
+			}
+			switch {
+			case !exists:
+				plan.Apps = append(plan.Apps, locator)
+			case locator.IsEqualTo(application.Package):
+				plan.Existing = append(plan.Existing, locator)
+			}
+		}
+	}
+	return plan, nil
+}
+
+// Run mirrors the packages and applications from the specified plan
+func (r *Mirror) Run(ctx context.Context, plan Plan) error {
+	for _, locator := range plan.Packages {
+		if err := ctx.Err(); err != nil {
+			return trace.Wrap(err)
+		}
+		r.Infof("Mirroring package %v.", locator)
+		_, err := service.PullPackage(service.PackagePullRequest{
+			FieldLogger: r.FieldLogger,
+			SrcPack:     r.SrcPack,
+			DstPack:     r.DstPack,
+			Package:     locator,
+			Progress:    r.Progress,
+			Verifier:    r.Verifier,
+		})
+		if err != nil && !trace.IsAlreadyExists(err) {
+			return trace.Wrap(err)
+		}
+	}
+	for _, locator := range plan.Apps {
+		if err := ctx.Err(); err != nil {
+			return trace.Wrap(err)
+		}
+		r.Infof("Mirroring application %v.", locator)
+		_, err := service.PullApp(service.AppPullRequest{
+			FieldLogger: r.FieldLogger,
+			SrcPack:     r.SrcPack,
+			DstPack:     r.DstPack,
+			SrcApp:      r.SrcApp,
+			DstApp:      r.DstApp,
+			Package:     locator,
+			Progress:    r.Progress,
+			Verifier:    r.Verifier,
+		})
+		if err != nil && !trace.IsAlreadyExists(err) {
+			return trace.Wrap(err)
+		}
+	}
+	return nil
+}
+
+func (r *Mirror) hasPackage(locator loc.Locator) (bool, error) {
+	_, err := r.DstPack.ReadPackageEnvelope(locator)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return false, nil
+		}
+		return false, trace.Wrap(err)
+	}
+	return true, nil
+}
+
+func (r *Mirror) hasApp(locator loc.Locator) (bool, error) {
+	application, err := r.DstApp.GetApp(locator)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return false, nil
+		}
+		return false, trace.Wrap(err)
+	}
+	// Metadata packages only describe applications on remote clusters
+	return !service.IsMetadataPackage(application.PackageEnvelope), nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mirror
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gravitational/gravity/lib/app"
+	"github.com/gravitational/gravity/lib/app/service"
+	apptest "github.com/gravitational/gravity/lib/app/service/test"
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/storage"
+
+	. "gopkg.in/check.v1"
+)
+
+func TestMirror(t *testing.T) { TestingT(t) }
+
+type MirrorSuite struct {
+	srcPack pack.PackageService
+	srcApp  app.Applications
+	dstPack pack.PackageService
+	dstApp  app.Applications
+}
+
+var _ = Suite(&MirrorSuite{})
+
+func (s *MirrorSuite) SetUpTest(c *C) {
+	_, s.srcPack, s.srcApp = service.NewTestServices(c)
+	_, s.dstPack, s.dstApp = service.NewTestServices(c)
+
+	apptest.CreatePackage(s.srcPack, loc.MustParseLocator("gravitational.io/planet:0.0.1"), nil, c)
+	apptest.CreateRuntimeApplication(s.srcApp, c)
+	for _, locator := range []string{
+		"example.com/app:0.0.1",
+		"example.com/app:0.0.2",
+		"example.com/app:1.0.0",
+		"example.com/other:0.0.1",
+	} {
+		apptest.CreateDummyApplication(s.srcApp, loc.MustParseLocator(locator), c)
+	}
+}
+
+func (s *MirrorSuite) TestMirrorsSelectedApps(c *C) {
+	selector, err := ParseSelector("app:<1.0")
+	c.Assert(err, IsNil)
+	mirror, err := New(Config{
+		SrcPack: s.srcPack,
+		SrcApp:  s.srcApp,
+		DstPack: s.dstPack,
+		DstApp:  s.dstApp,
+		Filter:  Filter{Apps: []Selector{*selector}},
+	})
+	c.Assert(err, IsNil)
+
+	plan, err := mirror.Plan(context.TODO())
+	c.Assert(err, IsNil)
+	c.Assert(plan.Packages, DeepEquals, []loc.Locator{
+		loc.MustParseLocator("gravitational.io/planet:0.0.1"),
+	})
+	c.Assert(plan.Apps, DeepEquals, []loc.Locator{
+		loc.MustParseLocator("gravitational.io/kubernetes:0.0.1"),
+		loc.MustParseLocator("example.com/app:0.0.1"),
+		loc.MustParseLocator("example.com/app:0.0.2"),
+	})
+	c.Assert(plan.Existing, HasLen, 0)
+
+	c.Assert(mirror.Run(context.TODO(), *plan), IsNil)
+	for _, locator := range plan.Apps {
+		_, err := s.dstApp.GetApp(locator)
+		c.Assert(err, IsNil)
+	}
+	_, err = s.dstApp.GetApp(loc.MustParseLocator("example.com/app:1.0.0"))
+	c.Assert(err, NotNil)
+
+	plan, err = mirror.Plan(context.TODO())
+	c.Assert(err, IsNil)
+	c.Assert(plan.IsEmpty(), Equals, true)
+	c.Assert(plan.Existing, DeepEquals, []loc.Locator{
+		loc.MustParseLocator("example.com/app:0.0.1"),
+		loc.MustParseLocator("example.com/app:0.0.2"),
+	})
+}
+
+func (s *MirrorSuite) TestFiltersApps(c *C) {
+	newApp := func(locator string, appType storage.AppType, labels map[string]string) app.Application {
+		return app.Application{
+			Package: loc.MustParseLocator(locator),
+			PackageEnvelope: pack.PackageEnvelope{
+				Type:          string(appType),
+				RuntimeLabels: labels,
+			},
+		}
+	}
+	user := newApp("example.com/app:1.2.0", storage.AppUser, map[string]string{"channel": "stable"})
+	runtime := newApp("gravitational.io/kubernetes:5.5.0", storage.AppRuntime, nil)
+	system := newApp("gravitational.io/dns-app:0.3.0", storage.AppService, nil)
+
+	all := Filter{}
+	c.Assert(all.Matches(user), Equals, true)
+	c.Assert(all.Matches(runtime), Equals, false)
+	c.Assert(all.Matches(system), Equals, false)
+
+	withRuntimes := Filter{Runtimes: true}
+	c.Assert(withRuntimes.Matches(runtime), Equals, true)
+
+	selector, err := ParseSelector("kubernetes:>=5.0 <6.0")
+	c.Assert(err, IsNil)
+	byName := Filter{Apps: []Selector{*selector}}
+	c.Assert(byName.Matches(runtime), Equals, true)
+	c.Assert(byName.Matches(user), Equals, false)
+
+	byLabel := Filter{Labels: map[string]string{"channel": "stable"}}
+	c.Assert(byLabel.Matches(user), Equals, true)
+	byLabel.Labels["channel"] = "beta"
+	c.Assert(byLabel.Matches(user), Equals, false)
+
+	_, err = ParseSelector(":1.0.0")
+	c.Assert(err, NotNil)
+}
@@ -537,8 +537,8 @@ func (r *applications) UpsertApp(locator loc.Locator, reader io.Reader, labels m
 // CreateAppWithManifest new application from the specified package bytes (reader)
 // and an optional set of package labels using locator as destination for the
 // resulting package, with supplied manifest
-func (r *applications) CreateAppWithManifest(locator loc.Locator, manifest []byte, reader io.Reader, labels map[string]string) (*appservice.Application, error) {
-	return r.createApp(locator, reader, manifest, labels, "", false)
+func (r *applications) CreateAppWithManifest(locator loc.Locator, manifest []byte, reader io.Reader, labels map[string]string, options ...pack.PackageOption) (*appservice.Application, error) {
+	return r.createApp(locator, reader, manifest, labels, "", false, options...)
 }
 
 // CreateApp creates a new application from the specified package bytes (reader)
@@ -568,12 +568,17 @@ func (r *applications) CreateApp(locator loc.Locator, reader io.Reader, labels m
 	return r.CreateAppWithManifest(locator, manifest, packageBytes, labels)
 }
 
-func (r *applications) createApp(locator loc.Locator, packageBytes io.Reader, manifestBytes []byte, labels map[string]string, email string, upsert bool) (*appservice.Application, error) {
+func (r *applications) createApp(locator loc.Locator, packageBytes io.Reader, manifestBytes []byte, labels map[string]string, email string, upsert bool, extraOptions ...pack.PackageOption) (*appservice.Application, error) {
 	manifest, err := r.resolveManifest(manifestBytes)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
 
+	if deps := manifest.Dependencies.Constrained(); len(deps) != 0 {
+		return nil, trace.BadParameter("application %v has dependencies %v with "+
+			"unresolved version constraints", locator, deps)
+	}
+
 	err = r.Packages.UpsertRepository(locator.Repository, time.Time{})
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -590,6 +595,7 @@ func (r *applications) createApp(locator loc.Locator, packageBytes io.Reader, ma
 		pack.WithCreatedBy(email),
 		pack.WithHidden(manifest.Metadata.Hidden),
 	}
+	options = append(options, extraOptions...)
 
 	var envelope *pack.PackageEnvelope
 	if upsert {
@@ -41,7 +41,7 @@ type chartsSuite struct {
 var _ = check.Suite(&chartsSuite{})
 
 func (s *chartsSuite) SetUpTest(c *check.C) {
-	s.backend, s.pack, s.apps = setupServices(c)
+	s.backend, s.pack, s.apps = NewTestServices(c)
 }
 
 func (s *chartsSuite) TestIndex(c *check.C) {
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	appservice "github.com/gravitational/gravity/lib/app"
+	"github.com/gravitational/gravity/lib/app/delta"
 	"github.com/gravitational/gravity/lib/archive"
 	"github.com/gravitational/gravity/lib/blob/fs"
 	"github.com/gravitational/gravity/lib/constants"
@@ -92,6 +93,8 @@ func (r *applications) getClusterInstaller(
 //  * import {web-assets,gravity,dns,teleport,planet-master,planet-node,application}
 //    packages from application package service into local package service running
 //    in ./packages
+//  * if requested, remove the packages and container image layers
+//    already present in the delta base
 //
 func (r *applications) GetAppInstaller(req appservice.InstallerRequest) (installer io.ReadCloser, err error) {
 	if err := req.Check(); err != nil {
@@ -176,6 +179,22 @@ func (r *applications) GetAppInstaller(req appservice.InstallerRequest) (install
 		return nil, trace.Wrap(err)
 	}
 
+	if req.DeltaBase != nil {
+		r.Infof("Removing contents of %v from installer packages.", req.DeltaBase.Application)
+		err = delta.Strip(localPackages, *req.DeltaBase, r.FieldLogger)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+
+	if req.Signer != nil {
+		r.Infof("Signing installer packages with key %v.", req.Signer.KeyID())
+		err = req.Signer.SignPackages(localBackend)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+
 	reader, writer := io.Pipe()
 	go func() {
 		uploadScript, err := renderUploadScript(*app)
@@ -270,7 +289,11 @@ func pullPackages(locators []loc.Locator, localPackages pack.PackageService, rem
 		if err != nil {
 			return trace.Wrap(err)
 		}
-		_, err = localPackages.CreatePackage(envelope.Locator, reader, pack.WithLabels(envelope.RuntimeLabels))
+		options := []pack.PackageOption{pack.WithLabels(envelope.RuntimeLabels)}
+		if envelope.Signature != nil {
+			options = append(options, pack.WithSignature(envelope.Signature))
+		}
+		_, err = localPackages.CreatePackage(envelope.Locator, reader, options...)
 		if err != nil {
 			return trace.Wrap(err)
 		}
@@ -290,7 +313,11 @@ func pullApplications(locators []loc.Locator, localApps *applications, remoteApp
 		defer reader.Close()
 
 		var labels map[string]string
-		_, err = localApps.CreateAppWithManifest(envelope.Locator, envelope.Manifest, reader, labels)
+		var options []pack.PackageOption
+		if envelope.Signature != nil {
+			options = append(options, pack.WithSignature(envelope.Signature))
+		}
+		_, err = localApps.CreateAppWithManifest(envelope.Locator, envelope.Manifest, reader, labels, options...)
 		if err != nil && !trace.IsAlreadyExists(err) {
 			return trace.Wrap(err)
 		}
@@ -27,15 +27,15 @@ import (
 	. "gopkg.in/check.v1"
 )
 
-func init() {
+func TestService(t *testing.T) {
+	// testing flags are only parsed once the tests start running
 	if testing.Verbose() {
 		log.SetOutput(os.Stderr)
 		log.SetLevel(log.InfoLevel)
 	}
+	TestingT(t)
 }
 
-func TestService(t *testing.T) { TestingT(t) }
-
 type ManifestSuite struct{}
 
 var _ = Suite(&ManifestSuite{})
@@ -18,11 +18,16 @@ package service
 
 import (
 	"context"
+	"crypto/sha512"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/gravitational/gravity/lib/app"
+	"github.com/gravitational/gravity/lib/app/delta"
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/pack"
@@ -53,6 +58,8 @@ type PackagePullRequest struct {
 	Upsert bool
 	// MetadataOnly allows to pull only package metadata without body
 	MetadataOnly bool
+	// Verifier optionally verifies the signature of the pulled package
+	Verifier pack.Verifier
 }
 
 // CheckAndSetDefaults checks the package pull request and sets some defaults
@@ -90,6 +97,8 @@ type AppPullRequest struct {
 	// If < 0, the number of tasks is unrestricted.
 	// If in [0,1], the tasks are executed sequentially.
 	Parallel int
+	// Verifier optionally verifies signatures of the pulled packages
+	Verifier pack.Verifier
 }
 
 // CheckAndSetDefaults checks the app pull request and sets some defaults
@@ -113,6 +122,7 @@ func (r *AppPullRequest) Clone(locator loc.Locator) AppPullRequest {
 		Progress:     r.Progress,
 		Parallel:     r.Parallel,
 		MetadataOnly: r.MetadataOnly,
+		Verifier:     r.Verifier,
 	}
 }
 
@@ -135,6 +145,7 @@ func pullPackageHandler(loc loc.Locator, req AppPullRequest, state *pullState) f
 			Upsert:       req.Upsert,
 			Progress:     req.Progress,
 			MetadataOnly: req.MetadataOnly,
+			Verifier:     req.Verifier,
 		}, state)
 		if !trace.IsAlreadyExists(err) {
 			return trace.Wrap(err)
@@ -189,6 +200,11 @@ func pullPackage(req PackagePullRequest) (*pack.PackageEnvelope, error) {
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	defer reader.Close()
+
+	if err := verifyPackage(req.Verifier, *env); err != nil {
+		return nil, trace.Wrap(err)
+	}
 
 	if req.Progress != nil {
 		reader = utils.TeeReadCloser(reader, &pack.ProgressWriter{
@@ -196,7 +212,6 @@ func pullPackage(req PackagePullRequest) (*pack.PackageEnvelope, error) {
 			R:    req.Progress,
 		})
 	}
-	defer reader.Close()
 
 	err = req.DstPack.UpsertRepository(env.Locator.Repository, time.Time{})
 	if err != nil {
@@ -213,17 +228,28 @@ func pullPackage(req PackagePullRequest) (*pack.PackageEnvelope, error) {
 		}
 	}
 
+	options := []pack.PackageOption{pack.WithLabels(req.Labels)}
+	if env.Signature != nil && !req.MetadataOnly {
+		options = append(options, pack.WithSignature(env.Signature))
+	}
+
+	srcEnv := env
 	if req.Upsert {
-		env, err = req.DstPack.UpsertPackage(
-			env.Locator, reader, pack.WithLabels(req.Labels))
+		env, err = req.DstPack.UpsertPackage(env.Locator, reader, options...)
 	} else {
-		env, err = req.DstPack.CreatePackage(
-			env.Locator, reader, pack.WithLabels(req.Labels))
+		env, err = req.DstPack.CreatePackage(env.Locator, reader, options...)
 	}
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
 
+	if err := verifyChecksum(req.Verifier, req.MetadataOnly, *srcEnv, *env); err != nil {
+		if errDelete := req.DstPack.DeletePackage(env.Locator); errDelete != nil {
+			req.Warnf("Failed to delete package %v: %v.", env.Locator, errDelete)
+		}
+		return nil, trace.Wrap(err)
+	}
+
 	return env, nil
 }
 
@@ -262,6 +288,13 @@ func pullApp(req AppPullRequest, state *pullState) (*app.Application, error) {
 
 	application, err := req.SrcApp.GetApp(req.Package)
 	if err != nil {
+		if trace.IsNotFound(err) {
+			// Delta installers omit applications the destination already has
+			if _, errDst := req.DstApp.GetApp(req.Package); errDst == nil {
+				req.Infof("Application %v already exists.", req.Package)
+				return nil, trace.AlreadyExists("app %v already exists", req.Package)
+			}
+		}
 		return nil, trace.Wrap(err)
 	}
 
@@ -312,6 +345,11 @@ func pullApp(req AppPullRequest, state *pullState) (*app.Application, error) {
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	defer reader.Close()
+
+	if err := verifyPackage(req.Verifier, *env); err != nil {
+		return nil, trace.Wrap(err)
+	}
 
 	if req.Progress != nil {
 		reader = utils.TeeReadCloser(reader, &pack.ProgressWriter{
@@ -319,13 +357,35 @@ func pullApp(req AppPullRequest, state *pullState) (*app.Application, error) {
 			R:    req.Progress,
 		})
 	}
-	defer reader.Close()
 
 	if req.Upsert {
-		application, err = req.DstApp.UpsertApp(env.Locator, reader, req.Labels)
+		// Upserting repackages the application so the original signature
+		// does not apply to the resulting package, verify the contents
+		// against the signed checksum before repackaging
+		var verified io.ReadCloser
+		verified, err = verifyContents(req.Verifier, req.MetadataOnly, *env, reader)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		defer verified.Close()
+		application, err = req.DstApp.UpsertApp(env.Locator, verified, req.Labels)
 	} else {
+		var options []pack.PackageOption
+		if env.Signature != nil && !req.MetadataOnly && !delta.IsDelta(*env) {
+			// The signature of a delta package does not apply to
+			// the reconstructed package
+			options = append(options, pack.WithSignature(env.Signature))
+		}
 		application, err = req.DstApp.CreateAppWithManifest(
-			env.Locator, env.Manifest, reader, req.Labels)
+			env.Locator, env.Manifest, reader, req.Labels, options...)
+		if err == nil {
+			err = verifyChecksum(req.Verifier, req.MetadataOnly, *env, application.PackageEnvelope)
+			if err != nil {
+				if errDelete := req.DstPack.DeletePackage(env.Locator); errDelete != nil {
+					req.Warnf("Failed to delete application %v: %v.", env.Locator, errDelete)
+				}
+			}
+		}
 	}
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -334,6 +394,74 @@ func pullApp(req AppPullRequest, state *pullState) (*app.Application, error) {
 	return application, nil
 }
 
+// verifyPackage verifies the signature of the package described
+// by the specified envelope if verifier is set
+func verifyPackage(verifier pack.Verifier, envelope pack.PackageEnvelope) error {
+	if verifier == nil {
+		return nil
+	}
+	return trace.Wrap(verifier.Verify(envelope))
+}
+
+// verifyChecksum makes sure that the contents of the package pulled into
+// the destination service match the checksum the signature has been
+// verified for.
+//
+// Encrypted packages are signed in their encrypted form and are stored
+// decrypted, so their checksums are not expected to match.
+// Likewise, delta packages are verified against their checksum
+// when they are reconstructed
+func verifyChecksum(verifier pack.Verifier, metadataOnly bool, src, dst pack.PackageEnvelope) error {
+	if verifier == nil || metadataOnly || src.Encrypted || delta.IsDelta(src) {
+		return nil
+	}
+	if src.SHA512 != dst.SHA512 {
+		return trace.CompareFailed("package %v checksum mismatch: expected %v, got %v",
+			src.Locator, src.SHA512, dst.SHA512)
+	}
+	return nil
+}
+
+// verifyContents makes sure that the package contents read from reader
+// match the checksum the signature has been verified for.
+// The contents are buffered in a temporary file which is removed
+// when the returned reader is closed
+func verifyContents(verifier pack.Verifier, metadataOnly bool, src pack.PackageEnvelope, reader io.Reader) (io.ReadCloser, error) {
+	if verifier == nil || metadataOnly || src.Encrypted || delta.IsDelta(src) {
+		return ioutil.NopCloser(reader), nil
+	}
+	f, err := ioutil.TempFile("", "package")
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	cleanup := func() {
+		f.Close()
+		os.Remove(f.Name())
+	}
+	hasher := sha512.New()
+	_, err = io.Copy(io.MultiWriter(f, hasher), reader)
+	if err != nil {
+		cleanup()
+		return nil, trace.Wrap(err)
+	}
+	hash := fmt.Sprintf("%x", hasher.Sum(nil)[:sha512.Size/2])
+	if hash != src.SHA512 {
+		cleanup()
+		return nil, trace.CompareFailed("package %v checksum mismatch: expected %v, got %v",
+			src.Locator, src.SHA512, hash)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, trace.ConvertSystemError(err)
+	}
+	return &utils.CleanupReadCloser{
+		ReadCloser: f,
+		Cleanup: func() {
+			os.Remove(f.Name())
+		},
+	}, nil
+}
+
 // PullAppDeps downloads only dependencies for an application described by the provided manifest
 func PullAppDeps(req AppPullRequest, manifest schema.Manifest) error {
 	state := newPullState()
@@ -18,20 +18,16 @@ package service
 
 import (
 	"bytes"
-	"path/filepath"
+	"crypto/sha512"
+	"fmt"
+	"io/ioutil"
 	"time"
 
 	"github.com/gravitational/gravity/lib/app"
 	apptest "github.com/gravitational/gravity/lib/app/service/test"
-	"github.com/gravitational/gravity/lib/blob/fs"
 	"github.com/gravitational/gravity/lib/compare"
-	"github.com/gravitational/gravity/lib/defaults"
-	"github.com/gravitational/gravity/lib/helm"
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/pack"
-	"github.com/gravitational/gravity/lib/pack/localpack"
-	"github.com/gravitational/gravity/lib/storage"
-	"github.com/gravitational/gravity/lib/storage/keyval"
 
 	"github.com/gravitational/trace"
 	log "github.com/sirupsen/logrus"
@@ -48,8 +44,8 @@ type PullerSuite struct {
 var _ = Suite(&PullerSuite{})
 
 func (s *PullerSuite) SetUpTest(c *C) {
-	_, s.srcPack, s.srcApp = setupServices(c)
-	_, s.dstPack, s.dstApp = setupServices(c)
+	_, s.srcPack, s.srcApp = NewTestServices(c)
+	_, s.dstPack, s.dstApp = NewTestServices(c)
 	err := s.srcPack.UpsertRepository("example.com", time.Time{})
 	c.Assert(err, IsNil)
 	err = s.dstPack.UpsertRepository("example.com", time.Time{})
@@ -85,6 +81,24 @@ func (s *PullerSuite) TestPullPackage(c *C) {
 	c.Assert(trace.IsAlreadyExists(err), Equals, true)
 }
 
+func (s *PullerSuite) TestVerifiesContents(c *C) {
+	data := []byte("data")
+	envelope := pack.PackageEnvelope{
+		Locator: loc.MustParseLocator("example.com/package:0.0.1"),
+		SHA512:  fmt.Sprintf("%x", sha512.Sum512(data))[:sha512.Size],
+	}
+
+	reader, err := verifyContents(acceptingVerifier{}, false, envelope, bytes.NewReader(data))
+	c.Assert(err, IsNil)
+	contents, err := ioutil.ReadAll(reader)
+	c.Assert(err, IsNil)
+	c.Assert(reader.Close(), IsNil)
+	c.Assert(contents, DeepEquals, data)
+
+	_, err = verifyContents(acceptingVerifier{}, false, envelope, bytes.NewReader([]byte("tampered")))
+	c.Assert(trace.IsCompareFailed(err), Equals, true, Commentf("expected CompareFailed, got %v", err))
+}
+
 func (s *PullerSuite) TestPullApp(c *C) {
 	s.pullApp(c, 0)
 }
@@ -157,41 +171,6 @@ dependencies:
 	c.Assert(trace.IsAlreadyExists(err), Equals, true)
 }
 
-func setupServices(c *C) (storage.Backend, pack.PackageService, *applications) {
-	dir := c.MkDir()
-
-	backend, err := keyval.NewBolt(keyval.BoltConfig{
-		Path: filepath.Join(dir, "bolt.db"),
-	})
-	c.Assert(err, IsNil)
-
-	objects, err := fs.New(dir)
-	c.Assert(err, IsNil)
-
-	packService, err := localpack.New(localpack.Config{
-		Backend:     backend,
-		UnpackedDir: filepath.Join(dir, defaults.UnpackedDir),
-		Objects:     objects,
-	})
-	c.Assert(err, IsNil)
-
-	charts, err := helm.NewRepository(helm.Config{
-		Packages: packService,
-		Backend:  backend,
-	})
-	c.Assert(err, IsNil)
-
-	appService, err := New(Config{
-		Backend:  backend,
-		StateDir: filepath.Join(dir, defaults.ImportDir),
-		Packages: packService,
-		Charts:   charts,
-	})
-	c.Assert(err, IsNil)
-
-	return backend, packService, appService
-}
-
 func locators(envelopes []pack.PackageEnvelope) []loc.Locator {
 	out := make([]loc.Locator, 0, len(envelopes))
 	for _, env := range envelopes {
@@ -205,3 +184,11 @@ type packagesByName []loc.Locator
 func (r packagesByName) Len() int           { return len(r) }
 func (r packagesByName) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
 func (r packagesByName) Less(i, j int) bool { return r[i].String() < r[j].String() }
+
+// acceptingVerifier is a package verifier that accepts any package
+type acceptingVerifier struct{}
+
+// Verify accepts the package described by the specified envelope
+func (acceptingVerifier) Verify(pack.PackageEnvelope) error {
+	return nil
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package service
+
+import (
+	"path/filepath"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/helm"
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/testutils"
+
+	check "gopkg.in/check.v1"
+)
+
+// NewTestServices returns the backend, the package and the application services
+// with the data stored in a temporary directory that can be used in tests
+func NewTestServices(c *check.C) (storage.Backend, pack.PackageService, *applications) {
+	backend, packages := testutils.NewPackageService(c)
+	charts, err := helm.NewRepository(helm.Config{
+		Packages: packages,
+		Backend:  backend,
+	})
+	c.Assert(err, check.IsNil)
+	apps, err := New(Config{
+		Backend:  backend,
+		StateDir: filepath.Join(c.MkDir(), defaults.ImportDir),
+		Packages: packages,
+		Charts:   charts,
+	})
+	c.Assert(err, check.IsNil)
+	return backend, packages, apps
+}
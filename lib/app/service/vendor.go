@@ -630,12 +630,20 @@ func makeRewriteDepsFunc(setPackages []loc.Locator) resources.ManifestRewriteFun
 		}
 		return l
 	}
+	// rewriteDep rewrites the dependency to an exact version which also
+	// replaces its version constraint, if any
+	rewriteDep := func(dep *schema.Dependency) {
+		locator := rewrite(dep.Locator)
+		if locator != dep.Locator {
+			*dep = schema.Dependency{Locator: locator}
+		}
+	}
 	return func(m *schema.Manifest) error {
 		for i := range m.Dependencies.Packages {
-			m.Dependencies.Packages[i].Locator = rewrite(m.Dependencies.Packages[i].Locator)
+			rewriteDep(&m.Dependencies.Packages[i])
 		}
 		for i := range m.Dependencies.Apps {
-			m.Dependencies.Apps[i].Locator = rewrite(m.Dependencies.Apps[i].Locator)
+			rewriteDep(&m.Dependencies.Apps[i])
 		}
 		if m.SystemOptions != nil && m.SystemOptions.Dependencies.Runtime != nil {
 			m.SystemOptions.Dependencies.Runtime.Locator =
@@ -653,6 +661,10 @@ func makeRewriteDepsFunc(setPackages []loc.Locator) resources.ManifestRewriteFun
 // packages (base, packages, apps) and rewrites versions accordingly
 func makeRewritePackagesMetadataFunc(packages pack.PackageService) resources.ManifestRewriteFunc {
 	return func(m *schema.Manifest) error {
+		if deps := m.Dependencies.Constrained(); len(deps) != 0 {
+			return trace.BadParameter("dependencies %v specify version constraints "+
+				"that have not been resolved, use tele build to resolve them", deps)
+		}
 		base := m.Base()
 		if base != nil {
 			newLoc, err := pack.ProcessMetadata(packages, base)
@@ -0,0 +1,91 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/gravitational/gravity/lib/defaults"
+
+	"github.com/gravitational/trace"
+)
+
+// NewFilesystemTarget returns a target that stores backups in the
+// specified directory which can be local or a mounted network filesystem
+func NewFilesystemTarget(dir string) (*filesystemTarget, error) {
+	if dir == "" {
+		dir = "."
+	}
+	return &filesystemTarget{dir: dir}, nil
+}
+
+// Put writes the backup to the file with the specified name.
+// The file is written atomically so a failed backup does not
+// leave partial data behind
+func (t *filesystemTarget) Put(ctx context.Context, name string, data io.Reader) error {
+	if err := os.MkdirAll(t.dir, defaults.SharedDirMask); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	f, err := ioutil.TempFile(t.dir, fmt.Sprintf(".%v", name))
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	defer os.Remove(f.Name())
+	_, err = io.Copy(f, data)
+	if err != nil {
+		f.Close()
+		return trace.Wrap(err)
+	}
+	if err := f.Close(); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	// ioutil.TempFile creates files only accessible by the owner,
+	// keep the backups readable as the files created with os.Create
+	if err := os.Chmod(f.Name(), defaults.SharedReadMask); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	err = os.Rename(f.Name(), filepath.Join(t.dir, name))
+	return trace.ConvertSystemError(err)
+}
+
+// Get opens the backup file with the specified name
+func (t *filesystemTarget) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(t.dir, name))
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	return f, nil
+}
+
+// Close is a no-op for the filesystem target
+func (t *filesystemTarget) Close() error {
+	return nil
+}
+
+// String returns the target's textual representation
+func (t *filesystemTarget) String() string {
+	return fmt.Sprintf("directory %v", t.dir)
+}
+
+type filesystemTarget struct {
+	dir string
+}
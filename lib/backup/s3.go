@@ -0,0 +1,143 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/gravitational/gravity/lib/defaults"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/gravitational/trace"
+)
+
+// S3Config defines the configuration of the S3 backup target
+type S3Config struct {
+	// Bucket is the bucket to store backups in
+	Bucket string
+	// Prefix is the key prefix for backups within the bucket
+	Prefix string
+	// Region is the bucket region
+	Region string
+	// Endpoint is an optional endpoint of an S3-compatible object
+	// store, e.g. a Minio server
+	Endpoint string
+	// S3 is optional S3 API client
+	S3 s3iface.S3API
+}
+
+// CheckAndSetDefaults validates the config and sets defaults
+func (c *S3Config) CheckAndSetDefaults() error {
+	if c.Bucket == "" {
+		return trace.BadParameter("missing S3 bucket")
+	}
+	c.Prefix = strings.Trim(c.Prefix, "/")
+	if c.Region == "" {
+		c.Region = defaults.AWSRegion
+	}
+	if c.S3 == nil {
+		config := &aws.Config{
+			Region: aws.String(c.Region),
+		}
+		if c.Endpoint != "" {
+			// S3-compatible stores usually do not support
+			// virtual-hosted-style bucket addressing
+			config.Endpoint = aws.String(c.Endpoint)
+			config.S3ForcePathStyle = aws.Bool(true)
+		}
+		session, err := session.NewSession(config)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		c.S3 = s3.New(session)
+	}
+	return nil
+}
+
+// NewS3Target returns a target that stores backups in an S3 bucket
+func NewS3Target(config S3Config) (*s3Target, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &s3Target{
+		S3Config: config,
+		uploader: s3manager.NewUploaderWithClient(config.S3),
+	}, nil
+}
+
+// Put uploads the backup to the object with the specified name
+func (t *s3Target) Put(ctx context.Context, name string, data io.Reader) error {
+	_, err := t.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(t.Bucket),
+		Key:    aws.String(t.key(name)),
+		Body:   data,
+	})
+	return trace.Wrap(convertS3Error(err))
+}
+
+// Get returns the contents of the object with the specified name
+func (t *s3Target) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	out, err := t.S3.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(t.Bucket),
+		Key:    aws.String(t.key(name)),
+	})
+	if err != nil {
+		return nil, trace.Wrap(convertS3Error(err))
+	}
+	return out.Body, nil
+}
+
+// Close is a no-op for the S3 target
+func (t *s3Target) Close() error {
+	return nil
+}
+
+// String returns the target's textual representation
+func (t *s3Target) String() string {
+	return fmt.Sprintf("s3://%v/%v", t.Bucket, t.Prefix)
+}
+
+func (t *s3Target) key(name string) string {
+	return path.Join(t.Prefix, name)
+}
+
+type s3Target struct {
+	S3Config
+	uploader *s3manager.Uploader
+}
+
+func convertS3Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	if awsErr, ok := err.(awserr.Error); ok {
+		switch awsErr.Code() {
+		case s3.ErrCodeNoSuchKey, s3.ErrCodeNoSuchBucket:
+			return trace.NotFound("%v", awsErr.Message())
+		}
+	}
+	return err
+}
@@ -0,0 +1,354 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backup implements the cluster controller that takes
+// application backups according to the configured backup schedules
+// and stores them in the cluster BLOB storage
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gravitational/gravity/lib/app"
+	"github.com/gravitational/gravity/lib/app/hooks"
+	"github.com/gravitational/gravity/lib/blob"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/schema"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/systeminfo"
+	"github.com/gravitational/gravity/lib/utils"
+
+	dockerarchive "github.com/docker/docker/pkg/archive"
+	teleutils "github.com/gravitational/teleport/lib/utils"
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Config defines the backup scheduler configuration
+type Config struct {
+	// Operator is the cluster operator service
+	Operator ops.Operator
+	// Backend is the cluster backend
+	Backend storage.Backend
+	// Apps is the cluster application service used to run backup hooks
+	Apps app.Applications
+	// Objects is the cluster BLOB storage backups are stored in
+	Objects blob.Objects
+	// StateDir is the directory to collect backup data in before it is
+	// uploaded to the BLOB storage. It must be accessible under the same
+	// path both for this process and for the backup hook job
+	StateDir string
+	// Clock is used to schedule backups, can be overridden in tests
+	Clock clockwork.Clock
+	// FieldLogger is used for logging
+	logrus.FieldLogger
+}
+
+// CheckAndSetDefaults validates the config and sets defaults
+func (c *Config) CheckAndSetDefaults() error {
+	if c.Operator == nil {
+		return trace.BadParameter("missing Operator")
+	}
+	if c.Backend == nil {
+		return trace.BadParameter("missing Backend")
+	}
+	if c.Apps == nil {
+		return trace.BadParameter("missing Apps")
+	}
+	if c.Objects == nil {
+		return trace.BadParameter("missing Objects")
+	}
+	if c.StateDir == "" {
+		return trace.BadParameter("missing StateDir")
+	}
+	if c.Clock == nil {
+		c.Clock = clockwork.NewRealClock()
+	}
+	if c.FieldLogger == nil {
+		c.FieldLogger = logrus.WithField(trace.Component, "backup")
+	}
+	return nil
+}
+
+// Scheduler periodically takes cluster backups according to
+// the configured backup schedules and enforces their retention
+type Scheduler struct {
+	Config
+	// started is the time the scheduler has started. It is used as a
+	// reference point for schedules that have not produced backups yet
+	started time.Time
+}
+
+// New returns a new backup scheduler
+func New(config Config) (*Scheduler, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &Scheduler{
+		Config:  config,
+		started: config.Clock.Now().UTC(),
+	}, nil
+}
+
+// Run checks the backup schedules periodically until the context is cancelled
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.Info("Starting backup scheduler.")
+	ticker := time.NewTicker(defaults.BackupScheduleCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.check(ctx); err != nil {
+				s.Warnf("Failed to check backup schedules: %v.", trace.DebugReport(err))
+			}
+		case <-ctx.Done():
+			s.Info("Stopping backup scheduler.")
+			return nil
+		}
+	}
+}
+
+// check takes backups for all schedules that are due
+func (s *Scheduler) check(ctx context.Context) error {
+	schedules, err := s.Backend.GetBackupSchedules()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if len(schedules) == 0 {
+		return nil
+	}
+	backups, err := s.Backend.GetBackups()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	now := s.Clock.Now().UTC()
+	for _, schedule := range schedules {
+		due, err := isDue(schedule, backups, s.started, now)
+		if err != nil {
+			s.Warnf("Invalid backup schedule %v: %v.", schedule.GetName(), err)
+			continue
+		}
+		if !due {
+			continue
+		}
+		backup, err := s.takeBackup(ctx, schedule)
+		if err != nil {
+			s.Errorf("Failed to take backup for schedule %v: %v.",
+				schedule.GetName(), trace.DebugReport(err))
+			continue
+		}
+		s.Infof("Taken %v.", backup)
+		if err := s.enforceRetention(schedule); err != nil {
+			s.Warnf("Failed to enforce retention for schedule %v: %v.",
+				schedule.GetName(), trace.DebugReport(err))
+		}
+	}
+	return nil
+}
+
+// takeBackup runs the application backup hook on this node, uploads
+// the collected data to the BLOB storage and records the backup
+func (s *Scheduler) takeBackup(ctx context.Context, schedule storage.BackupSchedule) (*storage.Backup, error) {
+	cluster, err := s.Operator.GetLocalSite()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	node, err := findLocalServer(*cluster)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	id, err := teleutils.CryptoRandomHex(3)
+	if err != nil {
+		return nil, trace.Wrap(err, "failed to generate random ID")
+	}
+	backupDir := filepath.Join(s.StateDir, id)
+	if err := os.MkdirAll(backupDir, defaults.SharedDirMask); err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(backupDir); err != nil {
+			s.Warnf("Failed to remove backup directory %v: %v.", backupDir, err)
+		}
+	}()
+
+	req := app.HookRunRequest{
+		Application: cluster.App.Package,
+		Hook:        schema.HookBackup,
+		Timeout:     schedule.GetTimeout(),
+		Volumes: []v1.Volume{{
+			Name: hooks.VolumeBackup,
+			VolumeSource: v1.VolumeSource{
+				HostPath: &v1.HostPathVolumeSource{
+					Path: backupDir,
+				},
+			},
+		}},
+		VolumeMounts: []v1.VolumeMount{{
+			Name:      hooks.VolumeBackup,
+			MountPath: hooks.ContainerBackupDir,
+		}},
+		NodeSelector: map[string]string{
+			defaults.KubernetesHostnameLabel: node.KubeNodeID(),
+		},
+	}
+	s.Infof("Running backup hook for %v on %v.", cluster.App.Package, node.KubeNodeID())
+	ref, err := app.StreamAppHook(ctx, s.Apps, req, utils.NopWriteCloser(ioutil.Discard))
+	if ref != nil {
+		defer func() {
+			err := s.Apps.DeleteAppHookJob(ctx, app.DeleteAppHookJobRequest{
+				HookRef: *ref,
+			})
+			if err != nil {
+				s.Warnf("Failed to delete hook %v: %v.", ref, trace.DebugReport(err))
+			}
+		}()
+	}
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	tarball, err := dockerarchive.Tar(backupDir, dockerarchive.Gzip)
+	if err != nil {
+		return nil, trace.Wrap(err, "failed to compress the backup directory %v", backupDir)
+	}
+	defer tarball.Close()
+	envelope, err := s.Objects.WriteBLOB(tarball)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	created := s.Clock.Now().UTC()
+	backup, err := s.Backend.CreateBackup(storage.Backup{
+		Name:        backupName(schedule.GetName(), created),
+		Schedule:    schedule.GetName(),
+		Application: cluster.App.Package,
+		SHA512:      envelope.SHA512,
+		SizeBytes:   envelope.SizeBytes,
+		Created:     created,
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return backup, nil
+}
+
+// enforceRetention removes the oldest backups taken according to the
+// specified schedule so that at most the configured number of them is kept
+func (s *Scheduler) enforceRetention(schedule storage.BackupSchedule) error {
+	backups, err := s.Backend.GetBackups()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	expired := expiredBackups(schedule, backups)
+	for _, backup := range expired {
+		s.Infof("Removing expired %v.", backup)
+		if err := s.Backend.DeleteBackup(backup.Name); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	// only remove the data that is not referenced by the remaining backups
+	inUse := make(map[string]bool)
+	for _, backup := range backups {
+		if !isExpired(backup, expired) {
+			inUse[backup.SHA512] = true
+		}
+	}
+	for _, backup := range expired {
+		if inUse[backup.SHA512] {
+			continue
+		}
+		err := s.Objects.DeleteBLOB(backup.SHA512)
+		if err != nil && !trace.IsNotFound(err) {
+			return trace.Wrap(err)
+		}
+		inUse[backup.SHA512] = true
+	}
+	return nil
+}
+
+// isDue returns true if a backup for the provided schedule should be taken
+// at the specified time. backups is the list of all backups in the cluster,
+// and since is the reference point used when the schedule has not yet
+// produced any backups
+func isDue(schedule storage.BackupSchedule, backups []storage.Backup, since, now time.Time) (bool, error) {
+	cron, err := utils.ParseCronSchedule(schedule.GetSchedule())
+	if err != nil {
+		return false, trace.Wrap(err)
+	}
+	last := since
+	for _, backup := range backups {
+		if backup.Schedule == schedule.GetName() && backup.Created.After(last) {
+			last = backup.Created
+		}
+	}
+	next := cron.Next(last)
+	return !next.IsZero() && !now.Before(next), nil
+}
+
+// expiredBackups returns the backups taken according to the provided schedule
+// that exceed its retention, oldest first. backups are expected to be sorted
+// by creation time
+func expiredBackups(schedule storage.BackupSchedule, backups []storage.Backup) (expired []storage.Backup) {
+	var taken []storage.Backup
+	for _, backup := range backups {
+		if backup.Schedule == schedule.GetName() {
+			taken = append(taken, backup)
+		}
+	}
+	if len(taken) <= schedule.GetRetention() {
+		return nil
+	}
+	return taken[:len(taken)-schedule.GetRetention()]
+}
+
+func isExpired(backup storage.Backup, expired []storage.Backup) bool {
+	for _, b := range expired {
+		if b.Name == backup.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func backupName(schedule string, created time.Time) string {
+	return fmt.Sprintf("%v-%v", schedule, created.Format("20060102150405"))
+}
+
+// findLocalServer returns the cluster server this process is running on.
+// The cluster controller runs in the host network namespace so the server
+// is looked up by the advertise address among the local interfaces
+func findLocalServer(cluster ops.Site) (*storage.Server, error) {
+	ifaces, err := systeminfo.NetworkInterfaces()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	for _, iface := range ifaces {
+		for _, server := range cluster.ClusterState.Servers {
+			if server.AdvertiseIP == iface.IPv4 {
+				return &server, nil
+			}
+		}
+	}
+	return nil, trace.NotFound("failed to find local server among cluster nodes")
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gravitational/gravity/lib/storage"
+
+	"gopkg.in/check.v1"
+)
+
+func TestBackup(t *testing.T) { check.TestingT(t) }
+
+type SchedulerSuite struct{}
+
+var _ = check.Suite(&SchedulerSuite{})
+
+func (s *SchedulerSuite) TestIsDue(c *check.C) {
+	schedule := storage.NewBackupSchedule("nightly", storage.BackupScheduleSpecV2{
+		Schedule: "0 2 * * *",
+	})
+	started := time.Date(2018, time.June, 15, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		backups []storage.Backup
+		now     time.Time
+		due     bool
+		comment string
+	}{
+		{
+			now:     time.Date(2018, time.June, 15, 23, 0, 0, 0, time.UTC),
+			due:     false,
+			comment: "no backups, not yet time",
+		},
+		{
+			now:     time.Date(2018, time.June, 16, 2, 0, 0, 0, time.UTC),
+			due:     true,
+			comment: "no backups, time has come",
+		},
+		{
+			backups: []storage.Backup{
+				{Name: "nightly-1", Schedule: "nightly",
+					Created: time.Date(2018, time.June, 16, 2, 0, 30, 0, time.UTC)},
+			},
+			now:     time.Date(2018, time.June, 16, 2, 1, 0, 0, time.UTC),
+			due:     false,
+			comment: "backup has already been taken",
+		},
+		{
+			backups: []storage.Backup{
+				{Name: "nightly-1", Schedule: "nightly",
+					Created: time.Date(2018, time.June, 16, 2, 0, 30, 0, time.UTC)},
+				{Name: "weekly-1", Schedule: "weekly",
+					Created: time.Date(2018, time.June, 17, 2, 0, 30, 0, time.UTC)},
+			},
+			now:     time.Date(2018, time.June, 17, 2, 1, 0, 0, time.UTC),
+			due:     true,
+			comment: "backups of other schedules are ignored",
+		},
+	}
+	for _, tc := range testCases {
+		comment := check.Commentf(tc.comment)
+		due, err := isDue(schedule, tc.backups, started, tc.now)
+		c.Assert(err, check.IsNil, comment)
+		c.Assert(due, check.Equals, tc.due, comment)
+	}
+}
+
+func (s *SchedulerSuite) TestExpiredBackups(c *check.C) {
+	schedule := storage.NewBackupSchedule("nightly", storage.BackupScheduleSpecV2{
+		Schedule:  "@daily",
+		Retention: 2,
+	})
+	backups := []storage.Backup{
+		{Name: "nightly-1", Schedule: "nightly"},
+		{Name: "manual"},
+		{Name: "nightly-2", Schedule: "nightly"},
+		{Name: "nightly-3", Schedule: "nightly"},
+	}
+	c.Assert(expiredBackups(schedule, backups), check.DeepEquals, []storage.Backup{
+		{Name: "nightly-1", Schedule: "nightly"},
+	})
+	c.Assert(expiredBackups(schedule, backups[:3]), check.IsNil)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"path"
+
+	"github.com/gravitational/gravity/lib/defaults"
+
+	"github.com/gravitational/trace"
+	"golang.org/x/crypto/ssh"
+)
+
+// SFTPConfig defines the configuration of the SFTP backup target
+type SFTPConfig struct {
+	// Addr is the SSH server address
+	Addr string
+	// User is the SSH user
+	User string
+	// Password is the optional SSH password
+	Password string
+	// IdentityFile is the optional path to the SSH private key
+	IdentityFile string
+	// HostKey is the expected SHA256 fingerprint of the server's host key
+	// as printed by 'ssh-keygen -l', e.g. SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8
+	HostKey string
+	// Insecure disables host key verification
+	Insecure bool
+	// Dir is the remote directory to store backups in
+	Dir string
+}
+
+// CheckAndSetDefaults validates the config and sets defaults
+func (c *SFTPConfig) CheckAndSetDefaults() error {
+	if c.Addr == "" {
+		return trace.BadParameter("missing SFTP server address")
+	}
+	if _, _, err := net.SplitHostPort(c.Addr); err != nil {
+		c.Addr = net.JoinHostPort(c.Addr, "22")
+	}
+	if c.User == "" {
+		c.User = defaults.SSHUser
+	}
+	if c.Password == "" && c.IdentityFile == "" {
+		return trace.BadParameter("either password or identity file must be provided")
+	}
+	if c.HostKey == "" && !c.Insecure {
+		return trace.BadParameter("host key fingerprint must be provided unless " +
+			"host key verification is disabled")
+	}
+	if c.Dir == "" {
+		c.Dir = "."
+	}
+	return nil
+}
+
+// NewSFTPTarget returns a target that stores backups on a remote
+// server accessible via SFTP
+func NewSFTPTarget(config SFTPConfig) (*sftpTarget, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var auth []ssh.AuthMethod
+	if config.IdentityFile != "" {
+		keyBytes, err := ioutil.ReadFile(config.IdentityFile)
+		if err != nil {
+			return nil, trace.ConvertSystemError(err)
+		}
+		signer, err := ssh.ParsePrivateKey(keyBytes)
+		if err != nil {
+			return nil, trace.Wrap(err, "failed to parse SSH key %v", config.IdentityFile)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if config.Password != "" {
+		auth = append(auth, ssh.Password(config.Password))
+	}
+	hostKeyCallback := ssh.InsecureIgnoreHostKey()
+	if !config.Insecure {
+		hostKeyCallback = func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			if fingerprint := ssh.FingerprintSHA256(key); fingerprint != config.HostKey {
+				return trace.AccessDenied("host key fingerprint mismatch for %v: expected %v, got %v",
+					hostname, config.HostKey, fingerprint)
+			}
+			return nil
+		}
+	}
+	client, err := ssh.Dial("tcp", config.Addr, &ssh.ClientConfig{
+		User:            config.User,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         defaults.DialTimeout,
+	})
+	if err != nil {
+		return nil, trace.Wrap(err, "failed to connect to %v", config.Addr)
+	}
+	sftp, err := newSFTPClient(client)
+	if err != nil {
+		client.Close()
+		return nil, trace.Wrap(err)
+	}
+	return &sftpTarget{
+		SFTPConfig: config,
+		ssh:        client,
+		sftp:       sftp,
+	}, nil
+}
+
+// Put writes the backup to the remote file with the specified name
+func (t *sftpTarget) Put(ctx context.Context, name string, data io.Reader) error {
+	f, err := t.sftp.create(t.path(name))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	_, err = io.Copy(f, data)
+	if err != nil {
+		f.Close()
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(f.Close())
+}
+
+// Get opens the remote backup file with the specified name
+func (t *sftpTarget) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	f, err := t.sftp.open(t.path(name))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return f, nil
+}
+
+// Close closes the SFTP session
+func (t *sftpTarget) Close() error {
+	t.sftp.Close()
+	return trace.Wrap(t.ssh.Close())
+}
+
+// String returns the target's textual representation
+func (t *sftpTarget) String() string {
+	return fmt.Sprintf("sftp://%v@%v/%v", t.User, t.Addr, t.Dir)
+}
+
+func (t *sftpTarget) path(name string) string {
+	return path.Join(t.Dir, name)
+}
+
+type sftpTarget struct {
+	SFTPConfig
+	ssh  *ssh.Client
+	sftp *sftpClient
+}
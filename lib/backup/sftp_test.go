@@ -0,0 +1,369 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gravitational/trace"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/ssh"
+	"gopkg.in/check.v1"
+)
+
+type SFTPSuite struct {
+	dir    string
+	server *testSSHServer
+}
+
+var _ = check.Suite(&SFTPSuite{})
+
+func (s *SFTPSuite) SetUpTest(c *check.C) {
+	s.dir = c.MkDir()
+	c.Assert(os.Mkdir(filepath.Join(s.dir, "backups"), 0755), check.IsNil)
+	s.server = newTestSSHServer(c, s.dir)
+}
+
+func (s *SFTPSuite) TearDownTest(c *check.C) {
+	s.server.Close()
+}
+
+func (s *SFTPSuite) TestPutGet(c *check.C) {
+	// Serve reads in small chunks to exercise short reads
+	s.server.maxRead = 1000
+	target := s.newTarget(c, s.server.hostKey())
+	defer target.Close()
+
+	// Exceed the maximum data size of a single request
+	data := make([]byte, 3*maxDataSize+17)
+	_, err := rand.Read(data)
+	c.Assert(err, check.IsNil)
+
+	err = target.Put(context.TODO(), "data.tar.gz", bytes.NewReader(data))
+	c.Assert(err, check.IsNil)
+	stored, err := ioutil.ReadFile(filepath.Join(s.dir, "backups", "data.tar.gz"))
+	c.Assert(err, check.IsNil)
+	c.Assert(stored, check.DeepEquals, data)
+	c.Assert(s.server.maxWrite() <= maxDataSize, check.Equals, true,
+		check.Commentf("write of %v bytes exceeds the maximum data size", s.server.maxWrite()))
+
+	rc, err := target.Get(context.TODO(), "data.tar.gz")
+	c.Assert(err, check.IsNil)
+	read, err := ioutil.ReadAll(rc)
+	c.Assert(err, check.IsNil)
+	c.Assert(rc.Close(), check.IsNil)
+	c.Assert(read, check.DeepEquals, data)
+
+	// Overwrite with shorter contents to make sure the file is truncated
+	err = target.Put(context.TODO(), "data.tar.gz", bytes.NewReader(testBackupData))
+	c.Assert(err, check.IsNil)
+	stored, err = ioutil.ReadFile(filepath.Join(s.dir, "backups", "data.tar.gz"))
+	c.Assert(err, check.IsNil)
+	c.Assert(stored, check.DeepEquals, testBackupData)
+}
+
+func (s *SFTPSuite) TestMissingFile(c *check.C) {
+	target := s.newTarget(c, s.server.hostKey())
+	defer target.Close()
+
+	_, err := target.Get(context.TODO(), "missing.tar.gz")
+	c.Assert(trace.IsNotFound(err), check.Equals, true, check.Commentf("%v", err))
+
+	err = target.Put(context.TODO(), "missing/data.tar.gz", bytes.NewReader(testBackupData))
+	c.Assert(trace.IsNotFound(err), check.Equals, true, check.Commentf("%v", err))
+}
+
+func (s *SFTPSuite) TestRejectsUnknownHostKey(c *check.C) {
+	_, err := NewSFTPTarget(SFTPConfig{
+		Addr:     s.server.addr(),
+		User:     "backup",
+		Password: "secret",
+		HostKey:  "SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8",
+		Dir:      "backups",
+	})
+	c.Assert(err, check.NotNil)
+}
+
+func (s *SFTPSuite) newTarget(c *check.C, hostKey string) Target {
+	target, err := NewSFTPTarget(SFTPConfig{
+		Addr:     s.server.addr(),
+		User:     "backup",
+		Password: "secret",
+		HostKey:  hostKey,
+		Dir:      "backups",
+	})
+	c.Assert(err, check.IsNil)
+	return target
+}
+
+// testSSHServer is an in-process SSH server that serves the SFTP subsystem
+// from a local directory.
+// The SFTP server is implemented independently of the client after
+// https://tools.ietf.org/html/draft-ietf-secsh-filexfer-02
+type testSSHServer struct {
+	sync.Mutex
+	listener net.Listener
+	config   *ssh.ServerConfig
+	signer   ssh.Signer
+	root     string
+	// maxRead limits the size of data returned for a single read request
+	maxRead int
+	// writes records the sizes of the write requests
+	writes []int
+}
+
+func newTestSSHServer(c *check.C, root string) *testSSHServer {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	c.Assert(err, check.IsNil)
+	signer, err := ssh.NewSignerFromKey(key)
+	c.Assert(err, check.IsNil)
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(meta ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if meta.User() == "backup" && string(password) == "secret" {
+				return nil, nil
+			}
+			return nil, trace.AccessDenied("access denied")
+		},
+	}
+	config.AddHostKey(signer)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, check.IsNil)
+	server := &testSSHServer{
+		listener: listener,
+		config:   config,
+		signer:   signer,
+		root:     root,
+	}
+	go server.serve()
+	return server
+}
+
+func (r *testSSHServer) addr() string {
+	return r.listener.Addr().String()
+}
+
+func (r *testSSHServer) hostKey() string {
+	return ssh.FingerprintSHA256(r.signer.PublicKey())
+}
+
+func (r *testSSHServer) maxWrite() (max int) {
+	r.Lock()
+	defer r.Unlock()
+	for _, size := range r.writes {
+		if size > max {
+			max = size
+		}
+	}
+	return max
+}
+
+func (r *testSSHServer) Close() error {
+	return r.listener.Close()
+}
+
+func (r *testSSHServer) serve() {
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			return
+		}
+		go r.serveConn(conn)
+	}
+}
+
+func (r *testSSHServer) serveConn(conn net.Conn) {
+	_, channels, requests, err := ssh.NewServerConn(conn, r.config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go func() {
+			for req := range requests {
+				isSFTP := req.Type == "subsystem" && len(req.Payload) > 4 &&
+					string(req.Payload[4:]) == "sftp"
+				req.Reply(isSFTP, nil)
+				if isSFTP {
+					go r.serveSFTP(channel)
+				}
+			}
+		}()
+	}
+}
+
+func (r *testSSHServer) serveSFTP(channel ssh.Channel) {
+	defer channel.Close()
+	handles := make(map[string]*os.File)
+	defer func() {
+		for _, f := range handles {
+			f.Close()
+		}
+	}()
+	for {
+		var length uint32
+		if err := binary.Read(channel, binary.BigEndian, &length); err != nil {
+			return
+		}
+		packet := make([]byte, length)
+		if _, err := io.ReadFull(channel, packet); err != nil {
+			return
+		}
+		typ, payload := packet[0], &sftpReader{buf: packet[1:]}
+		if typ == fxpInit {
+			writeSFTPPacket(channel, fxpVersion, uint32Bytes(sftpVersion))
+			continue
+		}
+		id := payload.uint32()
+		switch typ {
+		case fxpOpen:
+			path := filepath.Join(r.root, payload.string())
+			pflags := payload.uint32()
+			flags := os.O_RDONLY
+			if pflags&fxfWrite != 0 {
+				flags = os.O_WRONLY
+			}
+			if pflags&fxfCreate != 0 {
+				flags |= os.O_CREATE
+			}
+			if pflags&fxfTruncate != 0 {
+				flags |= os.O_TRUNC
+			}
+			f, err := os.OpenFile(path, flags, 0644)
+			if err != nil {
+				writeSFTPStatus(channel, id, statusCode(err), err.Error())
+				continue
+			}
+			handle := string(uint32Bytes(uint32(len(handles))))
+			handles[handle] = f
+			writeSFTPPacket(channel, fxpHandle, uint32Bytes(id), stringBytes(handle))
+		case fxpRead:
+			f := handles[payload.string()]
+			offset := payload.uint64()
+			size := int(payload.uint32())
+			if r.maxRead > 0 && size > r.maxRead {
+				size = r.maxRead
+			}
+			data := make([]byte, size)
+			n, err := f.ReadAt(data, int64(offset))
+			if n == 0 && err == io.EOF {
+				writeSFTPStatus(channel, id, fxEOF, "end of file")
+				continue
+			}
+			if n == 0 && err != nil {
+				writeSFTPStatus(channel, id, statusCode(err), err.Error())
+				continue
+			}
+			writeSFTPPacket(channel, fxpData, uint32Bytes(id), stringBytes(string(data[:n])))
+		case fxpWrite:
+			f := handles[payload.string()]
+			offset := payload.uint64()
+			data := payload.string()
+			r.Lock()
+			r.writes = append(r.writes, len(data))
+			r.Unlock()
+			if _, err := f.WriteAt([]byte(data), int64(offset)); err != nil {
+				writeSFTPStatus(channel, id, statusCode(err), err.Error())
+				continue
+			}
+			writeSFTPStatus(channel, id, fxOK, "")
+		case fxpClose:
+			handle := payload.string()
+			handles[handle].Close()
+			delete(handles, handle)
+			writeSFTPStatus(channel, id, fxOK, "")
+		default:
+			// SSH_FX_OP_UNSUPPORTED
+			writeSFTPStatus(channel, id, 8, "unsupported operation")
+		}
+	}
+}
+
+func statusCode(err error) uint32 {
+	switch {
+	case os.IsNotExist(err):
+		return fxNoSuchFile
+	case os.IsPermission(err):
+		return fxPermissionDenied
+	}
+	// SSH_FX_FAILURE
+	return 4
+}
+
+func writeSFTPStatus(w io.Writer, id, code uint32, message string) {
+	writeSFTPPacket(w, fxpStatus, uint32Bytes(id), uint32Bytes(code),
+		stringBytes(message), stringBytes(""))
+}
+
+func writeSFTPPacket(w io.Writer, typ byte, fields ...[]byte) {
+	payload := []byte{typ}
+	for _, field := range fields {
+		payload = append(payload, field...)
+	}
+	w.Write(append(uint32Bytes(uint32(len(payload))), payload...))
+}
+
+func uint32Bytes(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, v)
+	return b
+}
+
+func stringBytes(s string) []byte {
+	return append(uint32Bytes(uint32(len(s))), s...)
+}
+
+// sftpReader decodes the fields of an SFTP request
+type sftpReader struct {
+	buf []byte
+}
+
+func (r *sftpReader) uint32() uint32 {
+	v := binary.BigEndian.Uint32(r.buf)
+	r.buf = r.buf[4:]
+	return v
+}
+
+func (r *sftpReader) uint64() uint64 {
+	v := binary.BigEndian.Uint64(r.buf)
+	r.buf = r.buf[8:]
+	return v
+}
+
+func (r *sftpReader) string() string {
+	length := r.uint32()
+	s := string(r.buf[:length])
+	r.buf = r.buf[length:]
+	return s
+}
@@ -0,0 +1,311 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"encoding/binary"
+	"io"
+	"sync"
+
+	"github.com/gravitational/trace"
+	"golang.org/x/crypto/ssh"
+)
+
+// sftpClient implements the subset of the SFTP protocol (version 3)
+// required to upload and download files:
+//
+// https://tools.ietf.org/html/draft-ietf-secsh-filexfer-02
+//
+// Requests are issued sequentially, one at a time.
+type sftpClient struct {
+	sync.Mutex
+	session *ssh.Session
+	w       io.WriteCloser
+	r       io.Reader
+	nextID  uint32
+}
+
+func newSFTPClient(client *ssh.Client) (*sftpClient, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	w, err := session.StdinPipe()
+	if err != nil {
+		session.Close()
+		return nil, trace.Wrap(err)
+	}
+	r, err := session.StdoutPipe()
+	if err != nil {
+		session.Close()
+		return nil, trace.Wrap(err)
+	}
+	if err := session.RequestSubsystem("sftp"); err != nil {
+		session.Close()
+		return nil, trace.Wrap(err, "failed to start SFTP subsystem")
+	}
+	c := &sftpClient{session: session, w: w, r: r}
+	if err := c.init(); err != nil {
+		session.Close()
+		return nil, trace.Wrap(err)
+	}
+	return c, nil
+}
+
+// Close terminates the SFTP session
+func (c *sftpClient) Close() error {
+	c.w.Close()
+	return c.session.Close()
+}
+
+func (c *sftpClient) init() error {
+	// SSH_FXP_INIT does not have a request ID
+	if err := c.send(fxpInit, newPacket().uint32(sftpVersion)); err != nil {
+		return trace.Wrap(err)
+	}
+	typ, _, err := c.recv()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if typ != fxpVersion {
+		return trace.BadParameter("unexpected SFTP packet type %v", typ)
+	}
+	return nil
+}
+
+// create opens the remote file for writing, creating or truncating it
+func (c *sftpClient) create(path string) (*sftpFile, error) {
+	return c.openFile(path, fxfWrite|fxfCreate|fxfTruncate)
+}
+
+// open opens the remote file for reading
+func (c *sftpClient) open(path string) (*sftpFile, error) {
+	return c.openFile(path, fxfRead)
+}
+
+func (c *sftpClient) openFile(path string, flags uint32) (*sftpFile, error) {
+	typ, data, err := c.request(fxpOpen, newPacket().string(path).uint32(flags).uint32(0))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if typ != fxpHandle {
+		return nil, trace.BadParameter("unexpected SFTP packet type %v", typ)
+	}
+	handle, _, err := parseString(data)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &sftpFile{client: c, path: path, handle: handle}, nil
+}
+
+// request sends the request with the specified payload and returns
+// the response type and payload following the request ID
+func (c *sftpClient) request(typ byte, payload *packet) (byte, []byte, error) {
+	c.Lock()
+	defer c.Unlock()
+	c.nextID++
+	id := c.nextID
+	req := newPacket().uint32(id)
+	req.buf = append(req.buf, payload.buf...)
+	if err := c.send(typ, req); err != nil {
+		return 0, nil, trace.Wrap(err)
+	}
+	respType, data, err := c.recv()
+	if err != nil {
+		return 0, nil, trace.Wrap(err)
+	}
+	if len(data) < 4 || binary.BigEndian.Uint32(data) != id {
+		return 0, nil, trace.BadParameter("unexpected SFTP response")
+	}
+	data = data[4:]
+	if respType == fxpStatus {
+		return respType, data, statusError(data)
+	}
+	return respType, data, nil
+}
+
+func (c *sftpClient) send(typ byte, payload *packet) error {
+	header := make([]byte, 5)
+	binary.BigEndian.PutUint32(header, uint32(len(payload.buf)+1))
+	header[4] = typ
+	if _, err := c.w.Write(append(header, payload.buf...)); err != nil {
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+func (c *sftpClient) recv() (byte, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(c.r, header); err != nil {
+		return 0, nil, trace.Wrap(err)
+	}
+	length := binary.BigEndian.Uint32(header)
+	if length < 1 || length > maxPacketSize {
+		return 0, nil, trace.BadParameter("invalid SFTP packet length %v", length)
+	}
+	data := make([]byte, length-1)
+	if _, err := io.ReadFull(c.r, data); err != nil {
+		return 0, nil, trace.Wrap(err)
+	}
+	return header[4], data, nil
+}
+
+// sftpFile is a remote file opened via SFTP
+type sftpFile struct {
+	client *sftpClient
+	path   string
+	handle string
+	offset uint64
+}
+
+// Read reads the next chunk of the remote file
+func (f *sftpFile) Read(p []byte) (int, error) {
+	if len(p) > maxDataSize {
+		p = p[:maxDataSize]
+	}
+	typ, data, err := f.client.request(fxpRead,
+		newPacket().string(f.handle).uint64(f.offset).uint32(uint32(len(p))))
+	if err != nil {
+		return 0, err
+	}
+	if typ != fxpData {
+		return 0, trace.BadParameter("unexpected SFTP packet type %v", typ)
+	}
+	chunk, _, err := parseString(data)
+	if err != nil {
+		return 0, trace.Wrap(err)
+	}
+	n := copy(p, chunk)
+	f.offset += uint64(n)
+	return n, nil
+}
+
+// Write writes the data to the remote file
+func (f *sftpFile) Write(p []byte) (written int, err error) {
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > maxDataSize {
+			chunk = chunk[:maxDataSize]
+		}
+		_, _, err := f.client.request(fxpWrite,
+			newPacket().string(f.handle).uint64(f.offset).string(string(chunk)))
+		if err != nil {
+			return written, trace.Wrap(err)
+		}
+		f.offset += uint64(len(chunk))
+		written += len(chunk)
+		p = p[len(chunk):]
+	}
+	return written, nil
+}
+
+// Close closes the remote file handle
+func (f *sftpFile) Close() error {
+	_, _, err := f.client.request(fxpClose, newPacket().string(f.handle))
+	return trace.Wrap(err)
+}
+
+// statusError converts the SSH_FXP_STATUS payload into an error.
+// Returns nil for successful status and io.EOF for end of file
+func statusError(data []byte) error {
+	if len(data) < 4 {
+		return trace.BadParameter("malformed SFTP status")
+	}
+	code := binary.BigEndian.Uint32(data)
+	message, _, _ := parseString(data[4:])
+	switch code {
+	case fxOK:
+		return nil
+	case fxEOF:
+		return io.EOF
+	case fxNoSuchFile:
+		return trace.NotFound("SFTP: %v", message)
+	case fxPermissionDenied:
+		return trace.AccessDenied("SFTP: %v", message)
+	}
+	return trace.BadParameter("SFTP error %v: %v", code, message)
+}
+
+func parseString(data []byte) (string, []byte, error) {
+	if len(data) < 4 {
+		return "", nil, trace.BadParameter("malformed SFTP packet")
+	}
+	length := binary.BigEndian.Uint32(data)
+	if uint32(len(data)-4) < length {
+		return "", nil, trace.BadParameter("malformed SFTP packet")
+	}
+	return string(data[4 : 4+length]), data[4+length:], nil
+}
+
+// packet builds SFTP packet payloads
+type packet struct {
+	buf []byte
+}
+
+func newPacket() *packet {
+	return &packet{}
+}
+
+func (p *packet) uint32(v uint32) *packet {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	p.buf = append(p.buf, b[:]...)
+	return p
+}
+
+func (p *packet) uint64(v uint64) *packet {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	p.buf = append(p.buf, b[:]...)
+	return p
+}
+
+func (p *packet) string(s string) *packet {
+	p.uint32(uint32(len(s)))
+	p.buf = append(p.buf, s...)
+	return p
+}
+
+const (
+	sftpVersion = 3
+
+	fxpInit    = 1
+	fxpVersion = 2
+	fxpOpen    = 3
+	fxpClose   = 4
+	fxpRead    = 5
+	fxpWrite   = 6
+	fxpStatus  = 101
+	fxpHandle  = 102
+	fxpData    = 103
+
+	fxfRead     = 0x01
+	fxfWrite    = 0x02
+	fxfCreate   = 0x08
+	fxfTruncate = 0x10
+
+	fxOK               = 0
+	fxEOF              = 1
+	fxNoSuchFile       = 2
+	fxPermissionDenied = 3
+
+	// maxDataSize is the maximum size of data in a single read or write
+	// request that all servers are required to support
+	maxDataSize = 32768
+	// maxPacketSize limits the size of a response packet
+	maxPacketSize = 256 * 1024
+)
@@ -0,0 +1,190 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+)
+
+// Target is a storage location backup tarballs can be written to
+// and restored from
+type Target interface {
+	// Put writes the backup read from data under the specified name
+	Put(ctx context.Context, name string, data io.Reader) error
+	// Get returns the contents of the backup with the specified name
+	Get(ctx context.Context, name string) (io.ReadCloser, error)
+	// Close releases resources held by the target
+	Close() error
+	// String returns the target's textual representation
+	String() string
+}
+
+// ParseTarget returns the target for the provided backup location
+// and the name of the backup within it.
+//
+// The location is either a path on the local (possibly mounted) filesystem,
+// optionally prefixed with file://, or a URL in one of the following formats:
+//
+//   s3://bucket/prefix/backup.tar.gz?region=us-west-2&endpoint=http://minio:9000
+//   sftp://user@host:22/var/backups/backup.tar.gz?identity=/root/.ssh/id_rsa&host_key=SHA256:...
+//
+// Only locations with an explicit s3:// or sftp:// prefix are parsed as URLs,
+// so filesystem paths are used verbatim.
+//
+// S3 credentials are taken from the environment in the same way
+// as for other AWS tools.
+//
+// If passphrase is not empty, backups are encrypted before they are written
+// to the target and decrypted when read from it
+func ParseTarget(location, passphrase string) (target Target, name string, err error) {
+	if strings.HasPrefix(location, schemeS3+"://") || strings.HasPrefix(location, schemeSFTP+"://") {
+		target, name, err = parseURLTarget(location)
+	} else {
+		target, name, err = parseFilesystemTarget(location)
+	}
+	if err != nil {
+		return nil, "", trace.Wrap(err)
+	}
+	if passphrase != "" {
+		target = NewEncryptedTarget(target, passphrase)
+	}
+	return target, name, nil
+}
+
+// parseFilesystemTarget returns the target for the backup at the specified path
+func parseFilesystemTarget(location string) (Target, string, error) {
+	dir, name := filepath.Split(strings.TrimPrefix(location, schemeFile+"://"))
+	if name == "" {
+		return nil, "", trace.BadParameter("backup location %q does not specify file name", location)
+	}
+	target, err := NewFilesystemTarget(dir)
+	if err != nil {
+		return nil, "", trace.Wrap(err)
+	}
+	return target, name, nil
+}
+
+// parseURLTarget returns the target for the backup at the specified S3 or SFTP URL
+func parseURLTarget(location string) (target Target, name string, err error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, "", trace.Wrap(err, "invalid backup location %q", location)
+	}
+	dir, name := path.Split(u.Path)
+	if name == "" {
+		return nil, "", trace.BadParameter("backup location %q does not specify file name", location)
+	}
+	query := u.Query()
+	switch u.Scheme {
+	case schemeS3:
+		target, err = NewS3Target(S3Config{
+			Bucket:   u.Host,
+			Prefix:   dir,
+			Region:   query.Get("region"),
+			Endpoint: query.Get("endpoint"),
+		})
+	case schemeSFTP:
+		config := SFTPConfig{
+			Addr:         u.Host,
+			Dir:          dir,
+			IdentityFile: query.Get("identity"),
+			HostKey:      query.Get("host_key"),
+		}
+		if u.User != nil {
+			config.User = u.User.Username()
+			config.Password, _ = u.User.Password()
+		}
+		if insecure := query.Get("insecure"); insecure != "" {
+			config.Insecure, err = strconv.ParseBool(insecure)
+			if err != nil {
+				return nil, "", trace.BadParameter("invalid insecure flag %q", insecure)
+			}
+		}
+		target, err = NewSFTPTarget(config)
+	default:
+		return nil, "", trace.BadParameter("unsupported backup location scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, "", trace.Wrap(err)
+	}
+	return target, name, nil
+}
+
+// NewEncryptedTarget returns a target that encrypts backups with
+// the specified passphrase before handing them over to the provided target
+func NewEncryptedTarget(target Target, passphrase string) Target {
+	return &encryptedTarget{
+		Target:     target,
+		passphrase: passphrase,
+	}
+}
+
+// Put encrypts and writes the backup under the specified name
+func (t *encryptedTarget) Put(ctx context.Context, name string, data io.Reader) error {
+	encrypted, err := utils.EncryptPGP(data, t.passphrase)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer encrypted.Close()
+	return trace.Wrap(t.Target.Put(ctx, name, encrypted))
+}
+
+// Get returns the decrypted contents of the backup with the specified name
+func (t *encryptedTarget) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	rc, err := t.Target.Get(ctx, name)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	decrypted, err := utils.DecryptPGP(rc, t.passphrase)
+	if err != nil {
+		rc.Close()
+		return nil, trace.Wrap(err, "failed to decrypt backup %v", name)
+	}
+	return &readCloser{Reader: decrypted, Closer: rc}, nil
+}
+
+// String returns the target's textual representation
+func (t *encryptedTarget) String() string {
+	return "encrypted " + t.Target.String()
+}
+
+type encryptedTarget struct {
+	Target
+	passphrase string
+}
+
+// readCloser combines a reader with the closer of the underlying stream
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+const (
+	schemeFile = "file"
+	schemeS3   = "s3"
+	schemeSFTP = "sftp"
+)
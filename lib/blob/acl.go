@@ -91,6 +91,65 @@ func (a *ObjectsACL) GetBLOBEnvelope(hash string) (*Envelope, error) {
 	return a.objects.GetBLOBEnvelope(hash)
 }
 
+// GetBLOBIndex returns the chunk index of the BLOB
+func (a *ObjectsACL) GetBLOBIndex(hash string) (*Index, error) {
+	chunked, err := a.chunked(teleservices.VerbRead)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return chunked.GetBLOBIndex(hash)
+}
+
+// OpenChunk opens the chunk by hash and returns reader object
+func (a *ObjectsACL) OpenChunk(hash string) (ReadSeekCloser, error) {
+	chunked, err := a.chunked(teleservices.VerbRead)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return chunked.OpenChunk(hash)
+}
+
+// GetMissingChunks returns the subset of the specified chunks
+// not present in the storage
+func (a *ObjectsACL) GetMissingChunks(hashes []string) ([]string, error) {
+	chunked, err := a.chunked(teleservices.VerbRead)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return chunked.GetMissingChunks(hashes)
+}
+
+// WriteChunk writes chunk to storage
+func (a *ObjectsACL) WriteChunk(data io.Reader) (*Chunk, error) {
+	chunked, err := a.chunked(teleservices.VerbCreate)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return chunked.WriteChunk(data)
+}
+
+// WriteBLOBIndex creates the BLOB from the chunks present in the storage
+func (a *ObjectsACL) WriteBLOBIndex(index Index) (*Envelope, error) {
+	chunked, err := a.chunked(teleservices.VerbCreate)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return chunked.WriteBLOBIndex(index)
+}
+
+// chunked checks the permissions and returns the underlying storage
+// if it supports chunks
+func (a *ObjectsACL) chunked(action string) (ChunkedObjects, error) {
+	if err := a.check(action); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	chunked, ok := a.objects.(ChunkedObjects)
+	if !ok {
+		return nil, trace.NotImplemented("storage does not support chunks")
+	}
+	return chunked, nil
+}
+
 // check checks whether the user has the requested permissions
 func (a *ObjectsACL) check(action string) error {
 	// first check the access to all repositories
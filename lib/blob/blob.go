@@ -54,3 +54,40 @@ type Objects interface {
 	// GetBLOBEnvelope returns BLOB envelope
 	GetBLOBEnvelope(hash string) (*Envelope, error)
 }
+
+// Chunk describes a single content-defined chunk of a BLOB
+type Chunk struct {
+	// SHA512 is the half SHA512 hash of the chunk
+	SHA512 string `json:"sha512"`
+	// SizeBytes is the chunk size in bytes
+	SizeBytes int64 `json:"size_bytes"`
+}
+
+// Index describes a BLOB stored as a sequence of chunks
+type Index struct {
+	// Envelope describes the BLOB
+	Envelope
+	// Chunks lists the BLOB chunks in order
+	Chunks []Chunk `json:"chunks"`
+}
+
+// ChunkedObjects is implemented by BLOB storages that split objects
+// into content-defined chunks and store identical chunks only once.
+// It allows to replicate only the chunks missing on the receiving side
+type ChunkedObjects interface {
+	Objects
+	// GetBLOBIndex returns the chunk index of the BLOB
+	GetBLOBIndex(hash string) (*Index, error)
+	// OpenChunk opens the chunk by hash and returns reader object
+	OpenChunk(hash string) (ReadSeekCloser, error)
+	// GetMissingChunks returns the subset of the specified chunks
+	// not present in the storage
+	GetMissingChunks(hashes []string) ([]string, error)
+	// WriteChunk writes chunk to storage, on success
+	// returns the chunk with its hash
+	WriteChunk(data io.Reader) (*Chunk, error)
+	// WriteBLOBIndex creates the BLOB from the chunks already
+	// present in the storage. The BLOB hash and size are verified
+	// against the index
+	WriteBLOBIndex(index Index) (*Envelope, error)
+}
@@ -0,0 +1,484 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chunked
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gravitational/gravity/lib/blob"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+)
+
+// Config defines the configuration of the chunked BLOB storage
+type Config struct {
+	// Path is the storage directory
+	Path string
+	// Params defines the chunking parameters
+	Params Params
+}
+
+// CheckAndSetDefaults validates the config and sets defaults
+func (c *Config) CheckAndSetDefaults() error {
+	if c.Path == "" {
+		return trace.BadParameter("missing Path parameter")
+	}
+	return trace.Wrap(c.Params.CheckAndSetDefaults())
+}
+
+// New returns a new BLOB storage that splits objects into
+// content-defined chunks on the local filesystem.
+//
+// Each chunk is stored once, addressed by its hash, and each object
+// is described by the index listing its chunks in order.
+// Objects share the chunks with identical content so storing a new
+// version of a large file mostly reuses the chunks of the previous one
+func New(config Config) (*Objects, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	o := &Objects{Config: config}
+	for _, d := range []string{o.tempDir(), o.chunkDir(), o.indexDir()} {
+		if err := os.MkdirAll(d, defaults.SharedDirMask); err != nil {
+			return nil, trace.ConvertSystemError(err)
+		}
+	}
+	return o, nil
+}
+
+// Objects is the chunked BLOB storage
+type Objects struct {
+	Config
+	// RWMutex prevents pruning the chunks while
+	// objects referencing them are being written
+	sync.RWMutex
+}
+
+// Close closes the storage
+func (o *Objects) Close() error {
+	return nil
+}
+
+// GetBLOBs returns a list of BLOBs in the storage
+func (o *Objects) GetBLOBs() ([]string, error) {
+	return listFiles(o.indexDir())
+}
+
+// WriteBLOB splits the object into chunks and writes them
+// to the storage, returns object envelope
+func (o *Objects) WriteBLOB(data io.Reader) (*blob.Envelope, error) {
+	o.RLock()
+	defer o.RUnlock()
+	hasher := sha512.New()
+	chunker := newChunker(io.TeeReader(data, hasher), o.Params)
+	var index blob.Index
+	for {
+		data, err := chunker.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		chunk, err := o.writeChunk(data)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		index.Chunks = append(index.Chunks, *chunk)
+		index.SizeBytes += chunk.SizeBytes
+	}
+	index.SHA512 = hashString(hasher.Sum(nil))
+	envelope, err := o.writeIndex(index)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return envelope, nil
+}
+
+// GetBLOBEnvelope returns object envelope identified by hash
+func (o *Objects) GetBLOBEnvelope(hash string) (*blob.Envelope, error) {
+	index, err := o.GetBLOBIndex(hash)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &index.Envelope, nil
+}
+
+// OpenBLOB opens object identified by hash and returns reader
+func (o *Objects) OpenBLOB(hash string) (blob.ReadSeekCloser, error) {
+	index, err := o.GetBLOBIndex(hash)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return newReader(o, *index), nil
+}
+
+// DeleteBLOB deletes object from the storage along with
+// the chunks not referenced by other objects
+func (o *Objects) DeleteBLOB(hash string) error {
+	o.Lock()
+	defer o.Unlock()
+	index, err := o.GetBLOBIndex(hash)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if err := os.Remove(o.indexPath(hash)); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	referenced, err := o.referencedChunks()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	for _, chunk := range index.Chunks {
+		if referenced[chunk.SHA512] {
+			continue
+		}
+		err := os.Remove(o.chunkPath(chunk.SHA512))
+		if err != nil && !os.IsNotExist(err) {
+			return trace.ConvertSystemError(err)
+		}
+		// the same chunk may be listed several times
+		referenced[chunk.SHA512] = true
+	}
+	return nil
+}
+
+// QuarantineBLOB moves the index of the corrupted object into the
+// quarantine directory along with the chunks that fail verification.
+// The valid chunks are kept as they may be shared with other objects
+func (o *Objects) QuarantineBLOB(hash string) error {
+	if err := checkHash(hash); err != nil {
+		return trace.Wrap(err)
+	}
+	o.Lock()
+	defer o.Unlock()
+	if err := os.MkdirAll(o.quarantineDir(), defaults.SharedDirMask); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	index, err := o.GetBLOBIndex(hash)
+	if err != nil && !trace.IsNotFound(err) {
+		log.Warnf("Failed to read index of %v: %v.", hash, err)
+	}
+	if index != nil {
+		for _, chunk := range index.Chunks {
+			if err := o.verifyChunk(chunk); err == nil || trace.IsNotFound(err) {
+				continue
+			}
+			err := quarantineFile(o.chunkPath(chunk.SHA512), filepath.Join(o.quarantineDir(), chunk.SHA512))
+			if err != nil && !trace.IsNotFound(err) {
+				return trace.Wrap(err)
+			}
+		}
+	}
+	err = quarantineFile(o.indexPath(hash), filepath.Join(o.quarantineDir(), hash+".index"))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+// PruneQuarantine removes the indexes and chunks quarantined before the specified time
+func (o *Objects) PruneQuarantine(before time.Time) error {
+	o.Lock()
+	defer o.Unlock()
+	return trace.Wrap(utils.RemoveOlderThan(o.quarantineDir(), before))
+}
+
+// quarantineFile moves the file at path into the quarantine directory
+// and records the time it has been quarantined for pruning
+func quarantineFile(path, quarantinePath string) error {
+	if err := os.Rename(path, quarantinePath); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	now := time.Now()
+	if err := os.Chtimes(quarantinePath, now, now); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	return nil
+}
+
+// GetBLOBIndex returns the chunk index of the object
+func (o *Objects) GetBLOBIndex(hash string) (*blob.Index, error) {
+	if err := checkHash(hash); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	data, err := ioutil.ReadFile(o.indexPath(hash))
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	var index blob.Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, trace.Wrap(err, "failed to parse index of %v", hash)
+	}
+	return &index, nil
+}
+
+// OpenChunk opens the chunk by hash and returns reader
+func (o *Objects) OpenChunk(hash string) (blob.ReadSeekCloser, error) {
+	if err := checkHash(hash); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	f, err := os.Open(o.chunkPath(hash))
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	return f, nil
+}
+
+// GetMissingChunks returns the subset of the specified chunks
+// not present in the storage
+func (o *Objects) GetMissingChunks(hashes []string) (missing []string, err error) {
+	seen := make(map[string]bool, len(hashes))
+	for _, hash := range hashes {
+		if err := checkHash(hash); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		_, err := os.Stat(o.chunkPath(hash))
+		if err == nil {
+			continue
+		}
+		if !os.IsNotExist(err) {
+			return nil, trace.ConvertSystemError(err)
+		}
+		missing = append(missing, hash)
+	}
+	return missing, nil
+}
+
+// WriteChunk writes chunk to the storage
+func (o *Objects) WriteChunk(data io.Reader) (*blob.Chunk, error) {
+	hash, size, err := o.writeFile(data, o.chunkPath)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &blob.Chunk{SHA512: hash, SizeBytes: size}, nil
+}
+
+// WriteBLOBIndex creates the object from the chunks present in the storage.
+// The object size and hash are verified against the index
+func (o *Objects) WriteBLOBIndex(index blob.Index) (*blob.Envelope, error) {
+	o.RLock()
+	defer o.RUnlock()
+	if err := checkHash(index.SHA512); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	hasher := sha512.New()
+	reader := newReader(o, index)
+	defer reader.Close()
+	size, err := io.Copy(hasher, reader)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if size != index.SizeBytes {
+		return nil, trace.BadParameter("size mismatch for %v: expected %v, got %v",
+			index.SHA512, index.SizeBytes, size)
+	}
+	if hash := hashString(hasher.Sum(nil)); hash != index.SHA512 {
+		return nil, trace.BadParameter("hash mismatch for %v: got %v", index.SHA512, hash)
+	}
+	envelope, err := o.writeIndex(index)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return envelope, nil
+}
+
+// writeChunk writes the chunk unless the storage already has it
+func (o *Objects) writeChunk(data []byte) (*blob.Chunk, error) {
+	chunk := blob.Chunk{
+		SHA512:    hashData(data),
+		SizeBytes: int64(len(data)),
+	}
+	_, err := os.Stat(o.chunkPath(chunk.SHA512))
+	if err == nil {
+		return &chunk, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, trace.ConvertSystemError(err)
+	}
+	if _, _, err := o.writeFile(bytes.NewReader(data), o.chunkPath); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &chunk, nil
+}
+
+// writeIndex writes the object index and returns its envelope
+func (o *Objects) writeIndex(index blob.Index) (*blob.Envelope, error) {
+	index.Modified = time.Now().UTC()
+	data, err := json.Marshal(index)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	_, _, err = o.writeFile(bytes.NewReader(data), func(string) string {
+		return o.indexPath(index.SHA512)
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &index.Envelope, nil
+}
+
+// writeFile writes data to a temporary file and moves it
+// to the path determined by the hash of the data
+func (o *Objects) writeFile(data io.Reader, getPath func(hash string) string) (hash string, size int64, err error) {
+	f, err := ioutil.TempFile(o.tempDir(), "chunk")
+	if err != nil {
+		return "", 0, trace.ConvertSystemError(err)
+	}
+	defer func() {
+		f.Close()
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}()
+	hasher := sha512.New()
+	size, err = io.Copy(io.MultiWriter(f, hasher), data)
+	if err != nil {
+		return "", 0, trace.Wrap(err)
+	}
+	if err = f.Close(); err != nil {
+		return "", 0, trace.ConvertSystemError(err)
+	}
+	hash = hashString(hasher.Sum(nil))
+	targetPath := getPath(hash)
+	if err = os.MkdirAll(filepath.Dir(targetPath), defaults.SharedDirMask); err != nil {
+		return "", 0, trace.ConvertSystemError(err)
+	}
+	if err = os.Rename(f.Name(), targetPath); err != nil {
+		return "", 0, trace.ConvertSystemError(err)
+	}
+	return hash, size, nil
+}
+
+// verifyChunk checks that the chunk data matches its size and hash
+func (o *Objects) verifyChunk(chunk blob.Chunk) error {
+	data, err := ioutil.ReadFile(o.chunkPath(chunk.SHA512))
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	if int64(len(data)) != chunk.SizeBytes || hashData(data) != chunk.SHA512 {
+		return trace.CompareFailed("chunk %v is corrupted", chunk.SHA512)
+	}
+	return nil
+}
+
+// referencedChunks returns the set of chunks referenced by the objects
+func (o *Objects) referencedChunks() (map[string]bool, error) {
+	hashes, err := o.GetBLOBs()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	referenced := make(map[string]bool)
+	for _, hash := range hashes {
+		index, err := o.GetBLOBIndex(hash)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		for _, chunk := range index.Chunks {
+			referenced[chunk.SHA512] = true
+		}
+	}
+	return referenced, nil
+}
+
+func (o *Objects) tempDir() string {
+	return filepath.Join(o.Path, "tmp")
+}
+
+func (o *Objects) chunkDir() string {
+	return filepath.Join(o.Path, "chunks")
+}
+
+func (o *Objects) indexDir() string {
+	return IndexDir(o.Path)
+}
+
+func (o *Objects) quarantineDir() string {
+	return filepath.Join(o.Path, "quarantine")
+}
+
+// IndexDir returns the directory with object indexes
+// of the chunked storage in the specified directory
+func IndexDir(path string) string {
+	return filepath.Join(path, "indexes")
+}
+
+// chunkPath returns the path of the chunk, chunks are grouped
+// in directories by the first 3 characters of the hash, same
+// as objects in the filesystem storage
+func (o *Objects) chunkPath(hash string) string {
+	return filepath.Join(o.chunkDir(), hash[0:3], hash)
+}
+
+func (o *Objects) indexPath(hash string) string {
+	return filepath.Join(o.indexDir(), hash[0:3], hash)
+}
+
+// listFiles returns the sorted names of all files in the directory tree
+func listFiles(dir string) ([]string, error) {
+	var out []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			log.Warningf("error while traversing %v: %v", dir, err)
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+		out = append(out, info.Name())
+		return nil
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func checkHash(hash string) error {
+	if len(hash) < 3 || filepath.Base(hash) != hash {
+		return trace.BadParameter("invalid hash %q", hash)
+	}
+	return nil
+}
+
+func hashData(data []byte) string {
+	hash := sha512.Sum512(data)
+	return hashString(hash[:])
+}
+
+// hashString returns the half SHA512 hash in the format used for BLOBs
+func hashString(hash []byte) string {
+	return fmt.Sprintf("%x", hash[:sha512.Size/2])
+}
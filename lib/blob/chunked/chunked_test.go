@@ -0,0 +1,283 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chunked
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/gravitational/gravity/lib/blob"
+	"github.com/gravitational/gravity/lib/blob/fs"
+	"github.com/gravitational/gravity/lib/blob/suite"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+	. "gopkg.in/check.v1"
+)
+
+func TestChunked(t *testing.T) { TestingT(t) }
+
+type ChunkedSuite struct {
+	suite   suite.BLOBSuite
+	objects *Objects
+}
+
+var _ = Suite(&ChunkedSuite{})
+
+func (s *ChunkedSuite) SetUpTest(c *C) {
+	log.SetOutput(os.Stderr)
+	s.objects = newObjects(c)
+	s.suite.Objects = s.objects
+}
+
+func (s *ChunkedSuite) TestBLOB(c *C) {
+	s.suite.BLOB(c)
+}
+
+func (s *ChunkedSuite) TestBLOBSeek(c *C) {
+	s.suite.BLOBSeek(c)
+}
+
+func (s *ChunkedSuite) TestBLOBWriteTwice(c *C) {
+	s.suite.BLOBWriteTwice(c)
+}
+
+func (s *ChunkedSuite) TestBLOBList(c *C) {
+	s.suite.BLOBList(c)
+}
+
+func (s *ChunkedSuite) TestChunkBoundaries(c *C) {
+	data := randomData(64 * 1024)
+	chunks := chunkData(c, data)
+	var joined []byte
+	for _, chunk := range chunks {
+		c.Assert(len(chunk) <= testParams.MaxSize, Equals, true)
+		joined = append(joined, chunk...)
+	}
+	c.Assert(joined, DeepEquals, data)
+	c.Assert(len(chunks) > len(data)/testParams.MaxSize, Equals, true)
+
+	// inserting data at the beginning only changes the first few chunks
+	// until the boundaries synchronize again
+	shifted := chunkData(c, append([]byte("inserted"), data...))
+	c.Assert(sharedChunks(chunks, shifted) >= len(chunks)*9/10, Equals, true,
+		Commentf("only %v of %v chunks shared", sharedChunks(chunks, shifted), len(chunks)))
+}
+
+func (s *ChunkedSuite) TestDeduplication(c *C) {
+	data := randomData(64 * 1024)
+	e1, err := s.objects.WriteBLOB(bytes.NewReader(data))
+	c.Assert(err, IsNil)
+	c.Assert(e1.SHA512, Equals, utils.MustSHA512Half(data))
+	chunksBefore := s.chunkCount(c)
+
+	modified := append(append([]byte{}, data...), []byte("appended")...)
+	e2, err := s.objects.WriteBLOB(bytes.NewReader(modified))
+	c.Assert(err, IsNil)
+	// only the last chunk is new
+	c.Assert(s.chunkCount(c), Equals, chunksBefore+1)
+	s.assertContents(c, e2.SHA512, modified)
+
+	c.Assert(s.objects.DeleteBLOB(e2.SHA512), IsNil)
+	c.Assert(s.chunkCount(c), Equals, chunksBefore)
+	s.assertContents(c, e1.SHA512, data)
+
+	c.Assert(s.objects.DeleteBLOB(e1.SHA512), IsNil)
+	c.Assert(s.chunkCount(c), Equals, 0)
+	c.Assert(trace.IsNotFound(s.objects.DeleteBLOB(e1.SHA512)), Equals, true)
+}
+
+func (s *ChunkedSuite) TestSeekAcrossChunks(c *C) {
+	data := randomData(16 * 1024)
+	e, err := s.objects.WriteBLOB(bytes.NewReader(data))
+	c.Assert(err, IsNil)
+	r, err := s.objects.OpenBLOB(e.SHA512)
+	c.Assert(err, IsNil)
+	defer r.Close()
+	for _, offset := range []int64{5000, 100, 16*1024 - 10, 0} {
+		_, err = r.Seek(offset, io.SeekStart)
+		c.Assert(err, IsNil)
+		buf := make([]byte, 10)
+		_, err = io.ReadFull(r, buf)
+		c.Assert(err, IsNil)
+		c.Assert(buf, DeepEquals, data[offset:offset+10])
+	}
+	pos, err := r.Seek(-10, io.SeekEnd)
+	c.Assert(err, IsNil)
+	c.Assert(pos, Equals, int64(len(data)-10))
+}
+
+func (s *ChunkedSuite) TestCopyTransfersMissingChunks(c *C) {
+	data := randomData(64 * 1024)
+	e, err := s.objects.WriteBLOB(bytes.NewReader(data))
+	c.Assert(err, IsNil)
+
+	dst := &countingObjects{Objects: newObjects(c)}
+	_, err = dst.WriteBLOB(bytes.NewReader(data[:32*1024]))
+	c.Assert(err, IsNil)
+
+	envelope, err := blob.Copy(dst, s.objects, e.SHA512)
+	c.Assert(err, IsNil)
+	c.Assert(envelope.SHA512, Equals, e.SHA512)
+	index, err := s.objects.GetBLOBIndex(e.SHA512)
+	c.Assert(err, IsNil)
+	c.Assert(dst.chunks > 0, Equals, true)
+	c.Assert(dst.chunks < len(index.Chunks), Equals, true)
+	reader, err := dst.OpenBLOB(e.SHA512)
+	c.Assert(err, IsNil)
+	defer reader.Close()
+	out, err := ioutil.ReadAll(reader)
+	c.Assert(err, IsNil)
+	c.Assert(out, DeepEquals, data)
+
+	// the index is verified against the chunks
+	index.SHA512 = utils.MustSHA512Half([]byte("other data"))
+	_, err = dst.WriteBLOBIndex(*index)
+	c.Assert(trace.IsBadParameter(err), Equals, true)
+}
+
+func (s *ChunkedSuite) TestMigrate(c *C) {
+	legacy, err := fs.New(c.MkDir())
+	c.Assert(err, IsNil)
+	data := [][]byte{randomData(8 * 1024), randomData(100)}
+	for _, d := range data {
+		_, err := legacy.WriteBLOB(bytes.NewReader(d))
+		c.Assert(err, IsNil)
+	}
+
+	result, err := Migrate(MigrateConfig{From: legacy, To: s.objects})
+	c.Assert(err, IsNil)
+	c.Assert(result.Objects, Equals, 2)
+	c.Assert(result.SizeBytes, Equals, int64(8*1024+100))
+
+	hashes, err := legacy.GetBLOBs()
+	c.Assert(err, IsNil)
+	c.Assert(hashes, HasLen, 0)
+	for _, d := range data {
+		s.assertContents(c, utils.MustSHA512Half(d), d)
+	}
+
+	// migrate back
+	result, err = Migrate(MigrateConfig{From: s.objects, To: legacy})
+	c.Assert(err, IsNil)
+	c.Assert(result.Objects, Equals, 2)
+	c.Assert(s.chunkCount(c), Equals, 0)
+}
+
+func (s *ChunkedSuite) TestQuarantine(c *C) {
+	data := randomData(64 * 1024)
+	e, err := s.objects.WriteBLOB(bytes.NewReader(data))
+	c.Assert(err, IsNil)
+	c.Assert(blob.Verify(s.objects, e.SHA512), IsNil)
+	healthy := newObjects(c)
+	_, err = blob.Copy(healthy, s.objects, e.SHA512)
+	c.Assert(err, IsNil)
+
+	index, err := s.objects.GetBLOBIndex(e.SHA512)
+	c.Assert(err, IsNil)
+	corrupted := index.Chunks[1].SHA512
+	err = ioutil.WriteFile(s.objects.chunkPath(corrupted), []byte("garbage"), 0644)
+	c.Assert(err, IsNil)
+	err = blob.Verify(s.objects, e.SHA512)
+	c.Assert(trace.IsCompareFailed(err), Equals, true, Commentf("%v", err))
+
+	// only the index and the corrupted chunk are quarantined
+	chunks := s.chunkCount(c)
+	c.Assert(s.objects.QuarantineBLOB(e.SHA512), IsNil)
+	c.Assert(s.chunkCount(c), Equals, chunks-1)
+	err = blob.Verify(s.objects, e.SHA512)
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%v", err))
+	quarantined, err := listFiles(s.objects.quarantineDir())
+	c.Assert(err, IsNil)
+	c.Assert(quarantined, HasLen, 2)
+
+	// the object is repaired from a healthy copy
+	_, err = blob.Copy(s.objects, healthy, e.SHA512)
+	c.Assert(err, IsNil)
+	c.Assert(blob.Verify(s.objects, e.SHA512), IsNil)
+	s.assertContents(c, e.SHA512, data)
+}
+
+func (s *ChunkedSuite) assertContents(c *C, hash string, data []byte) {
+	reader, err := s.objects.OpenBLOB(hash)
+	c.Assert(err, IsNil)
+	defer reader.Close()
+	out, err := ioutil.ReadAll(reader)
+	c.Assert(err, IsNil)
+	c.Assert(out, DeepEquals, data)
+}
+
+func (s *ChunkedSuite) chunkCount(c *C) int {
+	chunks, err := listFiles(s.objects.chunkDir())
+	c.Assert(err, IsNil)
+	return len(chunks)
+}
+
+func newObjects(c *C) *Objects {
+	objects, err := New(Config{Path: c.MkDir(), Params: testParams})
+	c.Assert(err, IsNil)
+	return objects
+}
+
+func chunkData(c *C, data []byte) (chunks [][]byte) {
+	chunker := newChunker(bytes.NewReader(data), testParams)
+	for {
+		chunk, err := chunker.next()
+		if err == io.EOF {
+			return chunks
+		}
+		c.Assert(err, IsNil)
+		chunks = append(chunks, chunk)
+	}
+}
+
+func sharedChunks(a, b [][]byte) (shared int) {
+	hashes := make(map[string]bool)
+	for _, chunk := range a {
+		hashes[hashData(chunk)] = true
+	}
+	for _, chunk := range b {
+		if hashes[hashData(chunk)] {
+			shared++
+		}
+	}
+	return shared
+}
+
+func randomData(size int) []byte {
+	data := make([]byte, size)
+	rand.Read(data)
+	return data
+}
+
+// countingObjects counts the chunks written to the storage
+type countingObjects struct {
+	*Objects
+	chunks int
+}
+
+func (r *countingObjects) WriteChunk(data io.Reader) (*blob.Chunk, error) {
+	r.chunks++
+	return r.Objects.WriteChunk(data)
+}
+
+var testParams = Params{MinSize: 64, AvgSize: 256, MaxSize: 1024}
@@ -0,0 +1,151 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chunked
+
+import (
+	"io"
+	"math/bits"
+
+	"github.com/gravitational/trace"
+)
+
+// Params defines the content-defined chunking parameters
+type Params struct {
+	// MinSize is the minimum chunk size in bytes
+	MinSize int
+	// AvgSize is the average chunk size in bytes, must be a power of two
+	AvgSize int
+	// MaxSize is the maximum chunk size in bytes
+	MaxSize int
+}
+
+// CheckAndSetDefaults validates the parameters and sets defaults
+func (p *Params) CheckAndSetDefaults() error {
+	if p.MinSize == 0 {
+		p.MinSize = DefaultMinChunkSize
+	}
+	if p.AvgSize == 0 {
+		p.AvgSize = DefaultAvgChunkSize
+	}
+	if p.MaxSize == 0 {
+		p.MaxSize = DefaultMaxChunkSize
+	}
+	if bits.OnesCount(uint(p.AvgSize)) != 1 {
+		return trace.BadParameter("average chunk size should be a power of two, got %v", p.AvgSize)
+	}
+	if p.MinSize <= 0 || p.MinSize > p.AvgSize || p.AvgSize > p.MaxSize {
+		return trace.BadParameter("chunk sizes should satisfy 0 < min <= avg <= max, got %v, %v, %v",
+			p.MinSize, p.AvgSize, p.MaxSize)
+	}
+	return nil
+}
+
+// chunker splits the stream into content-defined chunks.
+//
+// Chunk boundaries are selected with a gear rolling hash over the
+// data so that the insertion or removal of data only affects the
+// chunks around the change and the rest of the chunks are shared
+// between different versions of the same file
+type chunker struct {
+	reader io.Reader
+	params Params
+	mask   uint64
+	buf    []byte
+	eof    bool
+}
+
+func newChunker(reader io.Reader, params Params) *chunker {
+	return &chunker{
+		reader: reader,
+		params: params,
+		// the highest bits of the gear hash depend on the longest
+		// window of data so use them to select boundaries
+		mask: uint64(params.AvgSize-1) << uint(64-bits.TrailingZeros(uint(params.AvgSize))),
+		buf:  make([]byte, 0, params.MaxSize),
+	}
+}
+
+// next returns the next chunk of data or io.EOF if there is no more data.
+// The returned slice is only valid until the next call
+func (r *chunker) next() ([]byte, error) {
+	if err := r.fill(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if len(r.buf) == 0 {
+		return nil, io.EOF
+	}
+	size := r.cutPoint(r.buf)
+	chunk := make([]byte, size)
+	copy(chunk, r.buf)
+	r.buf = r.buf[:copy(r.buf, r.buf[size:])]
+	return chunk, nil
+}
+
+// fill reads data until the buffer holds the maximum chunk size
+// or the end of stream is reached
+func (r *chunker) fill() error {
+	if r.eof || len(r.buf) == cap(r.buf) {
+		return nil
+	}
+	n, err := io.ReadFull(r.reader, r.buf[len(r.buf):cap(r.buf)])
+	r.buf = r.buf[:len(r.buf)+n]
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		r.eof = true
+		return nil
+	}
+	return trace.Wrap(err)
+}
+
+// cutPoint returns the size of the first chunk in data
+func (r *chunker) cutPoint(data []byte) int {
+	if len(data) <= r.params.MinSize {
+		return len(data)
+	}
+	var hash uint64
+	for i := r.params.MinSize; i < len(data); i++ {
+		hash = (hash << 1) + gearTable[data[i]]
+		if hash&r.mask == 0 {
+			return i + 1
+		}
+	}
+	return len(data)
+}
+
+// gearTable maps bytes to random values for the rolling hash.
+// The values must never change as they define chunk boundaries
+var gearTable [256]uint64
+
+func init() {
+	// splitmix64 with a fixed seed
+	seed := uint64(0x6772617669747921)
+	for i := range gearTable {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		gearTable[i] = z ^ (z >> 31)
+	}
+}
+
+const (
+	// DefaultMinChunkSize is the default minimum chunk size
+	DefaultMinChunkSize = 256 * 1024
+	// DefaultAvgChunkSize is the default average chunk size
+	DefaultAvgChunkSize = 1024 * 1024
+	// DefaultMaxChunkSize is the default maximum chunk size
+	DefaultMaxChunkSize = 4 * 1024 * 1024
+)
@@ -0,0 +1,89 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chunked
+
+import (
+	"github.com/gravitational/gravity/lib/blob"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+)
+
+// MigrateConfig defines the configuration of the BLOB storage migration
+type MigrateConfig struct {
+	// From is the storage to move the objects from
+	From blob.Objects
+	// To is the storage to move the objects to
+	To blob.Objects
+	// FieldLogger is used for logging
+	log.FieldLogger
+}
+
+// CheckAndSetDefaults validates the config and sets defaults
+func (c *MigrateConfig) CheckAndSetDefaults() error {
+	if c.From == nil {
+		return trace.BadParameter("missing From")
+	}
+	if c.To == nil {
+		return trace.BadParameter("missing To")
+	}
+	if c.FieldLogger == nil {
+		c.FieldLogger = log.StandardLogger()
+	}
+	return nil
+}
+
+// MigrateResult describes the outcome of the migration
+type MigrateResult struct {
+	// Objects is the number of objects moved
+	Objects int
+	// SizeBytes is the total size of the objects moved
+	SizeBytes int64
+}
+
+// Migrate moves all objects from one storage to another, for example
+// from the filesystem storage into the chunked storage and back.
+//
+// Each object is removed from the source storage only after it
+// has been written to the target storage and its hash verified,
+// so the migration can be safely restarted if interrupted
+func Migrate(config MigrateConfig) (*MigrateResult, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	hashes, err := config.From.GetBLOBs()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var result MigrateResult
+	for _, hash := range hashes {
+		envelope, err := blob.Copy(config.To, config.From, hash)
+		if err != nil {
+			return nil, trace.Wrap(err, "failed to migrate %v", hash)
+		}
+		if err := config.From.DeleteBLOB(hash); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		config.Debugf("Migrated %v.", envelope.SHA512)
+		result.Objects++
+		result.SizeBytes += envelope.SizeBytes
+	}
+	if result.Objects != 0 {
+		config.Infof("Migrated %v objects (%v bytes).", result.Objects, result.SizeBytes)
+	}
+	return &result, nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chunked
+
+import (
+	"io"
+	"sort"
+
+	"github.com/gravitational/gravity/lib/blob"
+
+	"github.com/gravitational/trace"
+)
+
+// reader reads the object by reading its chunks in order
+type reader struct {
+	objects *Objects
+	index   blob.Index
+	// offsets lists the offsets of the chunks within the object
+	offsets []int64
+	// pos is the current position within the object
+	pos int64
+	// current is the chunk being read, nil if no chunk is open
+	current io.ReadCloser
+	// end is the offset of the end of the current chunk
+	end int64
+}
+
+func newReader(objects *Objects, index blob.Index) *reader {
+	offsets := make([]int64, 0, len(index.Chunks))
+	var offset int64
+	for _, chunk := range index.Chunks {
+		offsets = append(offsets, offset)
+		offset += chunk.SizeBytes
+	}
+	return &reader{
+		objects: objects,
+		index:   index,
+		offsets: offsets,
+	}
+}
+
+// Read reads data from the object
+func (r *reader) Read(p []byte) (int, error) {
+	for {
+		if r.pos >= r.index.SizeBytes {
+			return 0, io.EOF
+		}
+		if r.current == nil {
+			if err := r.openChunk(); err != nil {
+				return 0, trace.Wrap(err)
+			}
+		}
+		n, err := r.current.Read(p)
+		r.pos += int64(n)
+		if err == io.EOF {
+			r.closeChunk()
+			if r.pos < r.end {
+				return n, trace.BadParameter("chunk at offset %v of %v is truncated",
+					r.pos, r.index.SHA512)
+			}
+			if n == 0 {
+				continue
+			}
+			return n, nil
+		}
+		if err != nil {
+			return n, trace.Wrap(err)
+		}
+		return n, nil
+	}
+}
+
+// Seek sets the position for the next read
+func (r *reader) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = r.pos + offset
+	case io.SeekEnd:
+		pos = r.index.SizeBytes + offset
+	default:
+		return 0, trace.BadParameter("invalid whence %v", whence)
+	}
+	if pos < 0 {
+		return 0, trace.BadParameter("negative position %v", pos)
+	}
+	if pos != r.pos {
+		r.closeChunk()
+		r.pos = pos
+	}
+	return pos, nil
+}
+
+// Close closes the reader
+func (r *reader) Close() error {
+	r.closeChunk()
+	return nil
+}
+
+// openChunk opens the chunk at the current position
+func (r *reader) openChunk() error {
+	i := sort.Search(len(r.offsets), func(i int) bool {
+		return r.offsets[i] > r.pos
+	}) - 1
+	chunk := r.index.Chunks[i]
+	f, err := r.objects.OpenChunk(chunk.SHA512)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if _, err := f.Seek(r.pos-r.offsets[i], io.SeekStart); err != nil {
+		f.Close()
+		return trace.Wrap(err)
+	}
+	// limit the read to the chunk size recorded in the index
+	r.end = r.offsets[i] + chunk.SizeBytes
+	r.current = &chunkReader{
+		Reader: io.LimitReader(f, r.end-r.pos),
+		Closer: f,
+	}
+	return nil
+}
+
+func (r *reader) closeChunk() {
+	if r.current != nil {
+		r.current.Close()
+		r.current = nil
+	}
+}
+
+type chunkReader struct {
+	io.Reader
+	io.Closer
+}
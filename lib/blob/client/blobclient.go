@@ -171,8 +171,12 @@ func (c *Client) WriteBLOB(data io.Reader) (*blob.Envelope, error) {
 }
 
 func (c *Client) OpenBLOB(hash string) (blob.ReadSeekCloser, error) {
-	endpoint := c.Endpoint("blobs", hash)
+	return c.openFile(c.Endpoint("blobs", hash))
+}
 
+// openFile checks that the file at the specified endpoint
+// exists and returns a reader for it
+func (c *Client) openFile(endpoint string) (blob.ReadSeekCloser, error) {
 	_, err := telehttplib.ConvertResponse(c.RoundTrip(func() (*http.Response, error) {
 		req, err := http.NewRequest("HEAD", endpoint, nil)
 		if err != nil {
@@ -187,6 +191,69 @@ func (c *Client) OpenBLOB(hash string) (blob.ReadSeekCloser, error) {
 	return c.OpenFile(context.TODO(), endpoint, url.Values{})
 }
 
+// GetBLOBIndex returns the chunk index of the BLOB
+func (c *Client) GetBLOBIndex(hash string) (*blob.Index, error) {
+	out, err := c.Get(c.Endpoint("blobs", hash, "index"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var index blob.Index
+	if err := json.Unmarshal(out.Bytes(), &index); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &index, nil
+}
+
+// WriteBLOBIndex creates the BLOB from the chunks already present in the storage
+func (c *Client) WriteBLOBIndex(index blob.Index) (*blob.Envelope, error) {
+	out, err := c.PostJSON(c.Endpoint("indexes"), index)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var envelope blob.Envelope
+	if err := json.Unmarshal(out.Bytes(), &envelope); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &envelope, nil
+}
+
+// OpenChunk opens the chunk by hash and returns reader object
+func (c *Client) OpenChunk(hash string) (blob.ReadSeekCloser, error) {
+	return c.openFile(c.Endpoint("chunks", hash))
+}
+
+// WriteChunk writes chunk to storage
+func (c *Client) WriteChunk(data io.Reader) (*blob.Chunk, error) {
+	file := roundtrip.File{
+		Name:     "file",
+		Filename: "file",
+		Reader:   data,
+	}
+	out, err := c.PostForm(c.Endpoint("chunks"), url.Values{}, file)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var chunk blob.Chunk
+	if err := json.Unmarshal(out.Bytes(), &chunk); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &chunk, nil
+}
+
+// GetMissingChunks returns the subset of the specified chunks
+// not present in the storage
+func (c *Client) GetMissingChunks(hashes []string) ([]string, error) {
+	out, err := c.PostJSON(c.Endpoint("chunks", "missing"), hashes)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var missing []string
+	if err := json.Unmarshal(out.Bytes(), &missing); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return missing, nil
+}
+
 // PostForm is a generic method that issues http POST request to the server
 func (c *Client) PostForm(
 	endpoint string,
@@ -197,6 +264,11 @@ func (c *Client) PostForm(
 		c.Client.PostForm(context.TODO(), endpoint, vals, files...))
 }
 
+// PostJSON issues http POST request with JSON body to the server
+func (c *Client) PostJSON(endpoint string, data interface{}) (*roundtrip.Response, error) {
+	return telehttplib.ConvertResponse(c.Client.PostJSON(context.TODO(), endpoint, data))
+}
+
 // Get issues http GET request to the server
 func (c *Client) Get(u string, params url.Values) (*roundtrip.Response, error) {
 	return telehttplib.ConvertResponse(c.Client.Get(context.TODO(), u, params))
@@ -18,6 +18,7 @@ package cluster
 
 import (
 	"io"
+	"reflect"
 	"sort"
 	"time"
 
@@ -47,6 +48,10 @@ type Config struct {
 	// WriteFactor defines how many ack peer writes should be acknowledged
 	// before write is considered successfull
 	WriteFactor int
+	// ReplicationFactor defines how many peers store each object.
+	// The peers are selected with rendezvous hashing of the object hash.
+	// If 0, every object is replicated to all peers
+	ReplicationFactor int
 	// ID is a peer local ID
 	ID string
 	// AdvertiseAddr is peer advertise address
@@ -68,6 +73,7 @@ type Config struct {
 // New returns cluster BLOB storage that takes care of replication
 // of BLOBs across cluster of nodes. It is designed for small clusters O(10)
 // and small amount of objects managed O(100)
+// unless ReplicationFactor limits the number of peers storing each object
 func New(config Config) (blob.Objects, error) {
 	if config.Local == nil {
 		return nil, trace.BadParameter("missing parameter Local")
@@ -87,6 +93,13 @@ func New(config Config) (blob.Objects, error) {
 	if config.WriteFactor < 1 {
 		config.WriteFactor = defaults.WriteFactor
 	}
+	if config.ReplicationFactor < 0 {
+		return nil, trace.BadParameter("ReplicationFactor can not be negative")
+	}
+	if config.ReplicationFactor != 0 && config.ReplicationFactor < config.WriteFactor {
+		return nil, trace.BadParameter("ReplicationFactor(%v) can not be less than WriteFactor(%v)",
+			config.ReplicationFactor, config.WriteFactor)
+	}
 	if config.Clock == nil {
 		config.Clock = clockwork.NewRealClock()
 	}
@@ -108,11 +121,18 @@ func New(config Config) (blob.Objects, error) {
 		"addr":          config.AdvertiseAddr,
 	})
 
-	c := &cluster{Config: config, close: close, cancelFn: cancelFn, Entry: entry}
+	c := &cluster{
+		Config:       config,
+		close:        close,
+		cancelFn:     cancelFn,
+		Entry:        entry,
+		missingSince: make(map[string]time.Time),
+	}
 	if !c.TestMode {
 		go c.periodically("heartbeat", c.heartbeat)
 		go c.periodically("purgeDeleted", c.purgeDeletedObjects)
 		go c.periodically("fetchNew", c.fetchNewObjects)
+		go c.periodically("rebalance", c.rebalance)
 	}
 
 	return c, nil
@@ -123,6 +143,11 @@ type cluster struct {
 	Config
 	close    context.Context
 	cancelFn context.CancelFunc
+	// missingSince maps objects not yet replicated to this peer
+	// to the time they have first been detected as missing
+	missingSince map[string]time.Time
+	// members is the list of active peer IDs as last seen by the rebalancer
+	members []string
 }
 
 func (c *cluster) Close() error {
@@ -219,8 +244,16 @@ func (c *cluster) fetchNewObjects() error {
 	if err != nil {
 		return trace.Wrap(err)
 	}
+	peers, err := c.getPeers(nil)
+	if err != nil {
+		return trace.Wrap(err)
+	}
 	var missingObjects []string
 	for _, hash := range objects {
+		// only fetch the objects this peer is responsible for
+		if !hasPeer(c.owners(hash, peers), c.ID) {
+			continue
+		}
 		f, err := c.Local.OpenBLOB(hash)
 		if err == nil {
 			f.Close()
@@ -228,17 +261,53 @@ func (c *cluster) fetchNewObjects() error {
 			missingObjects = append(missingObjects, hash)
 		}
 	}
+	c.trackMissing(missingObjects)
+	defer c.reportReplicationLag()
 	for _, hash := range missingObjects {
 		c.Infof("Found missing object %v.", hash)
 		err = c.fetchObject(hash)
 		if err != nil {
 			c.Warningf("Failed to fetch object(%v) %v.", hash, trace.DebugReport(err))
+			blobFetchFailures.Inc()
 			return trace.Wrap(err)
 		}
+		delete(c.missingSince, hash)
 	}
 	return nil
 }
 
+// trackMissing records the objects currently missing on this peer.
+// Objects no longer missing are forgotten
+func (c *cluster) trackMissing(hashes []string) {
+	missingSince := make(map[string]time.Time, len(hashes))
+	for _, hash := range hashes {
+		since, ok := c.missingSince[hash]
+		if !ok {
+			since = c.Clock.Now().UTC()
+		}
+		missingSince[hash] = since
+	}
+	c.missingSince = missingSince
+}
+
+// replicationLag returns the number of objects not yet replicated to this peer
+// and how long the oldest of them has been missing
+func (c *cluster) replicationLag() (missing int, lag time.Duration) {
+	now := c.Clock.Now().UTC()
+	for _, since := range c.missingSince {
+		if now.Sub(since) > lag {
+			lag = now.Sub(since)
+		}
+	}
+	return len(c.missingSince), lag
+}
+
+func (c *cluster) reportReplicationLag() {
+	missing, lag := c.replicationLag()
+	blobMissingObjects.Set(float64(missing))
+	blobReplicationLag.Set(lag.Seconds())
+}
+
 func (c *cluster) fetchObject(hash string) error {
 	peerIDs, err := c.Backend.GetObjectPeers(hash)
 	if err != nil {
@@ -260,18 +329,14 @@ func (c *cluster) fetchObject(hash string) error {
 			errors = append(errors, err)
 			continue
 		}
-		f, err := objects.OpenBLOB(hash)
+		// only the chunks missing locally are fetched
+		// if both peers use chunked storage
+		envelope, err := blob.Copy(c.Local, objects, hash)
 		if err != nil {
 			c.Errorf("Failure to fetch %v from %v: %v.", hash, p, trace.DebugReport(err))
 			errors = append(errors, err)
 			continue
 		}
-		defer f.Close()
-		envelope, err := c.Local.WriteBLOB(f)
-		if err != nil {
-			errors = append(errors, err)
-			continue
-		}
 		c.Infof("Successfully fetched %v from %v.", envelope, p)
 		err = c.Backend.UpsertObjectPeers(hash, []string{c.ID}, 0)
 		if err != nil {
@@ -309,28 +374,19 @@ func (c *cluster) WriteBLOB(data io.Reader) (*blob.Envelope, error) {
 		}
 		return envelope, nil
 	}
-	f, err := c.Local.OpenBLOB(envelope.SHA512)
-	if err != nil {
-		return nil, trace.Wrap(err)
-	}
-	defer f.Close()
-
 	var errors []error
-	for _, p := range peers {
+	// push the object to the peers responsible for it first
+	for _, p := range c.owners(envelope.SHA512, peers) {
 		if p.ID == c.ID {
 			continue
 		}
-		_, err := f.Seek(0, 0)
-		if err != nil {
-			return nil, trace.Wrap(err)
-		}
 		peerClient, err := c.GetPeer(p)
 		if err != nil {
 			c.Infof("%v returned error: %v", p, err)
 			errors = append(errors, err)
 			continue
 		}
-		_, err = peerClient.WriteBLOB(f)
+		_, err = blob.Copy(peerClient, c.Local, envelope.SHA512)
 		if err != nil {
 			c.Infof("%v returned error: %v", p, err)
 			errors = append(errors, err)
@@ -371,7 +427,9 @@ func (c *cluster) OpenBLOB(hash string) (blob.ReadSeekCloser, error) {
 		return nil, trace.ConnectionProblem(nil, "no peers found")
 	}
 	var reader blob.ReadSeekCloser
-	for _, p := range peers {
+	// the object is read from the local storage if available, otherwise
+	// it is streamed from one of the peers responsible for it
+	for _, p := range c.byPlacement(hash, peers) {
 		objects, err := c.getObjects(p)
 		if err != nil {
 			c.Warnf("%v returned %v", p, err)
@@ -387,6 +445,110 @@ func (c *cluster) OpenBLOB(hash string) (blob.ReadSeekCloser, error) {
 	return nil, trace.NotFound("failed to find any peer with hash(%v)", hash)
 }
 
+// owners returns the peers responsible for the object with the specified hash
+func (c *cluster) owners(hash string, peers []storage.Peer) []storage.Peer {
+	return owners(hash, peers, c.ReplicationFactor)
+}
+
+// byPlacement orders the peers so that the local peer goes first,
+// followed by the owners of the object with the specified hash
+func (c *cluster) byPlacement(hash string, peers []storage.Peer) []storage.Peer {
+	out := make([]storage.Peer, 0, len(peers))
+	if hasPeer(peers, c.ID) {
+		out = append(out, c.localPeer())
+	}
+	return append(out, owners(hash, c.withoutSelf(peers), 0)...)
+}
+
+// rebalance removes the local copies of objects this peer is no longer
+// responsible for, once all the owners of an object have replicated it.
+// The objects owned by this peer are fetched by fetchNewObjects
+func (c *cluster) rebalance() error {
+	if c.ReplicationFactor == 0 {
+		return nil
+	}
+	peers, err := c.getPeers(nil)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	members := peerIDs(peers)
+	sort.Strings(members)
+	if !reflect.DeepEqual(members, c.members) {
+		c.Infof("Peers changed from %v to %v, rebalancing objects.", c.members, members)
+		c.members = members
+	}
+	hashes, err := c.Local.GetBLOBs()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	for _, hash := range hashes {
+		owners := c.owners(hash, peers)
+		if hasPeer(owners, c.ID) {
+			continue
+		}
+		ids, err := c.Backend.GetObjectPeers(hash)
+		if err != nil {
+			if trace.IsNotFound(err) {
+				// object has been deleted, purgeDeletedObjects
+				// will take care of it
+				continue
+			}
+			return trace.Wrap(err)
+		}
+		if !replicated(owners, ids) || !c.ownersHold(hash, owners) {
+			c.Debugf("Keeping %v until it is replicated to %v.", hash, peerIDs(owners))
+			continue
+		}
+		if err := c.Backend.DeleteObjectPeers(hash, []string{c.ID}); err != nil {
+			return trace.Wrap(err)
+		}
+		// peers may disagree on the membership, so make sure
+		// another peer has not released its copy at the same time
+		ids, err = c.Backend.GetObjectPeers(hash)
+		if err != nil && !trace.IsNotFound(err) {
+			return trace.Wrap(err)
+		}
+		if !replicated(owners, ids) {
+			c.Infof("Keeping %v released concurrently by one of %v.", hash, peerIDs(owners))
+			if err := c.Backend.UpsertObjectPeers(hash, []string{c.ID}, 0); err != nil {
+				return trace.Wrap(err)
+			}
+			continue
+		}
+		c.Infof("Removing %v replicated to %v.", hash, peerIDs(owners))
+		if err := c.Local.DeleteBLOB(hash); err != nil && !trace.IsNotFound(err) {
+			return trace.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// replicated returns true if all owners are in the list of peers
+// that store the object
+func replicated(owners []storage.Peer, ids []string) bool {
+	for _, p := range owners {
+		if !utils.StringInSlice(ids, p.ID) {
+			return false
+		}
+	}
+	return true
+}
+
+// ownersHold returns true if all owners have the object in their storage
+func (c *cluster) ownersHold(hash string, owners []storage.Peer) bool {
+	for _, p := range owners {
+		objects, err := c.getObjects(p)
+		if err == nil {
+			_, err = objects.GetBLOBEnvelope(hash)
+		}
+		if err != nil {
+			c.Debugf("Failed to confirm %v holds %v: %v.", p.ID, hash, err)
+			return false
+		}
+	}
+	return true
+}
+
 // DeleteBLOB deletes BLOB from the storage
 func (c *cluster) DeleteBLOB(hash string) error {
 	return trace.Wrap(c.Backend.DeleteObject(hash))
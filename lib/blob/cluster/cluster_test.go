@@ -339,6 +339,10 @@ func (s *RPCSuite) TestCleanup(c *C) {
 	s.clusterSuite.Cleanup(c)
 }
 
+func (s *RPCSuite) TestReplicationLag(c *C) {
+	s.clusterSuite.ReplicationLag(c)
+}
+
 type clusterSuite struct {
 	objects []*cluster
 	clients []blob.Objects
@@ -402,3 +406,20 @@ func (s *clusterSuite) Cleanup(c *C) {
 		c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%#v", err))
 	}
 }
+
+func (s *clusterSuite) ReplicationLag(c *C) {
+	peer := s.objects[0]
+
+	peer.trackMissing([]string{"object-1", "object-2"})
+	s.clock.Advance(time.Minute)
+	// object-1 has been replicated, object-3 is new
+	peer.trackMissing([]string{"object-2", "object-3"})
+	missing, lag := peer.replicationLag()
+	c.Assert(missing, Equals, 2)
+	c.Assert(lag, Equals, time.Minute)
+
+	peer.trackMissing(nil)
+	missing, lag = peer.replicationLag()
+	c.Assert(missing, Equals, 0)
+	c.Assert(lag, Equals, time.Duration(0))
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	blobMissingObjects = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gravity_blob_missing_objects",
+		Help: "Number of objects not yet replicated to this peer",
+	})
+	blobReplicationLag = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gravity_blob_replication_lag_seconds",
+		Help: "Time the oldest object not yet replicated to this peer has been missing",
+	})
+	blobFetchFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gravity_blob_fetch_failures_total",
+		Help: "Number of failed attempts to fetch a missing object from other peers",
+	})
+	blobCorruptedObjects = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gravity_blob_corrupted_objects_total",
+		Help: "Number of corrupted objects found on this peer",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(blobMissingObjects, blobReplicationLag, blobFetchFailures, blobCorruptedObjects)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sort"
+
+	"github.com/gravitational/gravity/lib/storage"
+)
+
+// owners returns the peers responsible for storing the object with the
+// specified hash, in the order of preference.
+//
+// The peers are selected with rendezvous (highest random weight) hashing:
+// every peer is ranked by a hash of its ID and the object hash, and the
+// count top ranked peers own the object. When a peer joins or leaves,
+// only the objects it owns (or is about to own) change their placement.
+// If count is 0 or exceeds the number of peers, all peers are returned
+func owners(hash string, peers []storage.Peer, count int) []storage.Peer {
+	ranked := make([]rankedPeer, 0, len(peers))
+	for _, p := range peers {
+		ranked = append(ranked, rankedPeer{Peer: p, weight: weight(p.ID, hash)})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].weight == ranked[j].weight {
+			return ranked[i].ID < ranked[j].ID
+		}
+		return ranked[i].weight > ranked[j].weight
+	})
+	if count <= 0 || count > len(ranked) {
+		count = len(ranked)
+	}
+	out := make([]storage.Peer, 0, count)
+	for _, p := range ranked[:count] {
+		out = append(out, p.Peer)
+	}
+	return out
+}
+
+// weight returns the rank of the peer for the object with the specified hash
+func weight(peerID, hash string) uint64 {
+	h := sha256.New()
+	h.Write([]byte(peerID))
+	h.Write([]byte{0})
+	h.Write([]byte(hash))
+	return binary.BigEndian.Uint64(h.Sum(nil))
+}
+
+type rankedPeer struct {
+	storage.Peer
+	weight uint64
+}
+
+// hasPeer returns true if the peer with the specified ID is in the list
+func hasPeer(peers []storage.Peer, id string) bool {
+	for _, p := range peers {
+		if p.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// peerIDs returns the IDs of the specified peers
+func peerIDs(peers []storage.Peer) []string {
+	out := make([]string, 0, len(peers))
+	for _, p := range peers {
+		out = append(out, p.ID)
+	}
+	return out
+}
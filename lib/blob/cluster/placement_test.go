@@ -0,0 +1,295 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gravitational/gravity/lib/blob"
+	"github.com/gravitational/gravity/lib/blob/fs"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/storage/keyval"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	. "gopkg.in/check.v1"
+)
+
+type PlacementSuite struct {
+	objects []*cluster
+	clock   clockwork.FakeClock
+}
+
+var _ = Suite(&PlacementSuite{})
+
+const (
+	placementPeers    = 4
+	replicationFactor = 2
+)
+
+func (s *PlacementSuite) SetUpTest(c *C) {
+	s.clock = clockwork.NewFakeClockAt(time.Now().UTC())
+	b, err := keyval.NewBolt(keyval.BoltConfig{
+		Clock: s.clock,
+		Path:  filepath.Join(c.MkDir(), "bolt.db"),
+	})
+	c.Assert(err, IsNil)
+
+	peers := make([]blob.Objects, placementPeers)
+	getPeer := func(p storage.Peer) (blob.Objects, error) {
+		id, err := strconv.Atoi(p.ID)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return peers[id], nil
+	}
+
+	s.objects = make([]*cluster, placementPeers)
+	for i := range s.objects {
+		peers[i], err = fs.New(c.MkDir())
+		c.Assert(err, IsNil)
+		obj, err := New(Config{
+			Local:             peers[i],
+			WriteFactor:       1,
+			ReplicationFactor: replicationFactor,
+			Backend:           b,
+			GetPeer:           getPeer,
+			HeartbeatPeriod:   heartbeatPeriod,
+			MissedHeartbeats:  missedHeartbeats,
+			Clock:             s.clock,
+			ID:                fmt.Sprintf("%v", i),
+			AdvertiseAddr:     "https://localhost",
+			TestMode:          true,
+			GracePeriod:       gracePeriod,
+		})
+		c.Assert(err, IsNil)
+		s.objects[i] = obj.(*cluster)
+		c.Assert(s.objects[i].heartbeat(), IsNil)
+	}
+}
+
+func (s *PlacementSuite) TearDownTest(c *C) {
+	for _, obj := range s.objects {
+		obj.Close()
+	}
+}
+
+func (s *PlacementSuite) TestOwnersAreStable(c *C) {
+	var peers []storage.Peer
+	for i := 0; i < 5; i++ {
+		peers = append(peers, storage.Peer{ID: fmt.Sprintf("peer-%v", i)})
+	}
+	moved := 0
+	for i := 0; i < 100; i++ {
+		hash := fmt.Sprintf("object-%v", i)
+		before := owners(hash, peers, replicationFactor)
+		c.Assert(before, HasLen, replicationFactor)
+		c.Assert(owners(hash, peers, replicationFactor), DeepEquals, before)
+
+		// only the objects owned by the removed peer change placement
+		after := owners(hash, peers[1:], replicationFactor)
+		if !hasPeer(before, peers[0].ID) {
+			c.Assert(after, DeepEquals, before)
+			continue
+		}
+		moved++
+		c.Assert(hasPeer(after, peers[0].ID), Equals, false)
+	}
+	c.Assert(moved > 0 && moved < 100, Equals, true, Commentf("moved %v", moved))
+	c.Assert(owners("object", peers, 0), HasLen, len(peers))
+}
+
+func (s *PlacementSuite) TestReplicatesToOwners(c *C) {
+	data := []byte("hello, there, cluster!")
+	envelope, err := s.objects[0].WriteBLOB(bytes.NewReader(data))
+	c.Assert(err, IsNil)
+	s.converge(c)
+
+	holders := s.holders(c, envelope.SHA512)
+	c.Assert(holders, DeepEquals, s.ownerIDs(c, envelope.SHA512))
+	ids, err := s.objects[0].Backend.GetObjectPeers(envelope.SHA512)
+	c.Assert(err, IsNil)
+	c.Assert(ids, DeepEquals, holders)
+
+	// every peer can read the object, including the ones not storing it
+	for _, o := range s.objects {
+		f, err := o.OpenBLOB(envelope.SHA512)
+		c.Assert(err, IsNil)
+		out, err := ioutil.ReadAll(f)
+		c.Assert(err, IsNil)
+		c.Assert(f.Close(), IsNil)
+		c.Assert(string(out), Equals, string(data))
+	}
+}
+
+func (s *PlacementSuite) TestRebalancesWhenPeerLeaves(c *C) {
+	envelope, err := s.objects[0].WriteBLOB(bytes.NewReader([]byte("data")))
+	c.Assert(err, IsNil)
+	s.converge(c)
+
+	// one of the owners stops sending heartbeats
+	owner, err := strconv.Atoi(s.ownerIDs(c, envelope.SHA512)[0])
+	c.Assert(err, IsNil)
+	s.objects = append(s.objects[:owner], s.objects[owner+1:]...)
+	s.clock.Advance(missedHeartbeats*heartbeatPeriod + time.Second)
+	for _, o := range s.objects {
+		c.Assert(o.heartbeat(), IsNil)
+	}
+	s.converge(c)
+
+	owners := s.ownerIDs(c, envelope.SHA512)
+	c.Assert(owners, HasLen, replicationFactor)
+	c.Assert(utils.StringInSlice(owners, strconv.Itoa(owner)), Equals, false)
+	c.Assert(s.holders(c, envelope.SHA512), DeepEquals, owners)
+}
+
+func (s *PlacementSuite) TestKeepsCopyUntilOwnersHoldObject(c *C) {
+	writer, hash, owners := s.writeToNonOwner(c, []byte("data"))
+	for _, o := range s.objects {
+		c.Assert(o.fetchNewObjects(), IsNil)
+	}
+
+	// one of the owners loses its copy while still listed as a holder
+	c.Assert(s.peer(c, owners[0]).Local.DeleteBLOB(hash), IsNil)
+	c.Assert(writer.rebalance(), IsNil)
+	c.Assert(s.holders(c, hash), HasLen, replicationFactor)
+	c.Assert(utils.StringInSlice(s.holders(c, hash), writer.ID), Equals, true)
+
+	s.converge(c)
+	c.Assert(s.holders(c, hash), DeepEquals, owners)
+}
+
+func (s *PlacementSuite) TestKeepsCopyReleasedConcurrently(c *C) {
+	writer, hash, owners := s.writeToNonOwner(c, []byte("data"))
+	for _, o := range s.objects {
+		c.Assert(o.fetchNewObjects(), IsNil)
+	}
+
+	// an owner with a diverged view of membership
+	// releases its copy at the same time as the writer
+	backend := writer.Backend
+	writer.Backend = &releasingBackend{
+		Backend: backend,
+		release: func() error {
+			return backend.DeleteObjectPeers(hash, owners[:1])
+		},
+	}
+	c.Assert(writer.rebalance(), IsNil)
+	c.Assert(utils.StringInSlice(s.holders(c, hash), writer.ID), Equals, true)
+	ids, err := backend.GetObjectPeers(hash)
+	c.Assert(err, IsNil)
+	expected := []string{owners[1], writer.ID}
+	sort.Strings(ids)
+	sort.Strings(expected)
+	c.Assert(ids, DeepEquals, expected)
+}
+
+func (s *PlacementSuite) TestValidatesReplicationFactor(c *C) {
+	config := s.objects[0].Config
+	config.WriteFactor = 3
+	config.ReplicationFactor = 2
+	_, err := New(config)
+	c.Assert(trace.IsBadParameter(err), Equals, true)
+}
+
+// writeToNonOwner writes the data using the first peer not owning it
+// and returns the writer along with the hash and the owners of the object
+func (s *PlacementSuite) writeToNonOwner(c *C, data []byte) (*cluster, string, []string) {
+	scratch, err := fs.New(c.MkDir())
+	c.Assert(err, IsNil)
+	envelope, err := scratch.WriteBLOB(bytes.NewReader(data))
+	c.Assert(err, IsNil)
+
+	owners := s.ownerIDs(c, envelope.SHA512)
+	for _, o := range s.objects {
+		if utils.StringInSlice(owners, o.ID) {
+			continue
+		}
+		_, err := o.WriteBLOB(bytes.NewReader(data))
+		c.Assert(err, IsNil)
+		return o, envelope.SHA512, owners
+	}
+	c.Fatalf("No peer outside of the owners %v.", owners)
+	return nil, "", nil
+}
+
+// peer returns the active peer with the specified ID
+func (s *PlacementSuite) peer(c *C, id string) *cluster {
+	for _, o := range s.objects {
+		if o.ID == id {
+			return o
+		}
+	}
+	c.Fatalf("No peer %v.", id)
+	return nil
+}
+
+// releasingBackend simulates another peer releasing
+// its copy of an object right before this peer does
+type releasingBackend struct {
+	storage.Backend
+	release func() error
+}
+
+// DeleteObjectPeers releases the copy of the other peer
+// before deleting the specified peers
+func (b *releasingBackend) DeleteObjectPeers(hash string, peers []string) error {
+	if err := b.release(); err != nil {
+		return trace.Wrap(err)
+	}
+	return b.Backend.DeleteObjectPeers(hash, peers)
+}
+
+// converge runs replication and rebalancing on all peers
+func (s *PlacementSuite) converge(c *C) {
+	for _, o := range s.objects {
+		c.Assert(o.fetchNewObjects(), IsNil)
+	}
+	for _, o := range s.objects {
+		c.Assert(o.rebalance(), IsNil)
+	}
+}
+
+// holders returns the IDs of the active peers storing the object locally
+func (s *PlacementSuite) holders(c *C, hash string) (ids []string) {
+	for _, o := range s.objects {
+		f, err := o.Local.OpenBLOB(hash)
+		if trace.IsNotFound(err) {
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert(f.Close(), IsNil)
+		ids = append(ids, o.ID)
+	}
+	return ids
+}
+
+// ownerIDs returns the sorted IDs of the owners of the object
+func (s *PlacementSuite) ownerIDs(c *C, hash string) []string {
+	peers, err := s.objects[0].getPeers(nil)
+	c.Assert(err, IsNil)
+	ids := peerIDs(s.objects[0].owners(hash, peers))
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/gravitational/gravity/lib/blob"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+)
+
+// Scrubber verifies the integrity of the objects stored by the peer
+type Scrubber interface {
+	// Scrub verifies the objects stored locally against their hashes.
+	// Corrupted copies are quarantined and fetched again from other peers.
+	// The returned report is also saved in the backend
+	Scrub(ctx context.Context) (*storage.BLOBScrubReport, error)
+}
+
+// Scrub verifies the objects stored locally against their hashes.
+// Corrupted copies are quarantined and fetched again from other peers
+func (c *cluster) Scrub(ctx context.Context) (*storage.BLOBScrubReport, error) {
+	hashes, err := c.Local.GetBLOBs()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	report := storage.BLOBScrubReport{
+		PeerID:  c.ID,
+		Started: c.Clock.Now().UTC(),
+	}
+	c.Infof("Verifying %v objects.", len(hashes))
+	for _, hash := range hashes {
+		select {
+		case <-ctx.Done():
+			return nil, trace.Wrap(ctx.Err())
+		default:
+		}
+		err := blob.Verify(c.Local, hash)
+		if trace.IsNotFound(err) {
+			// deleted in the meantime
+			continue
+		}
+		report.Checked++
+		if err == nil {
+			continue
+		}
+		if !trace.IsCompareFailed(err) {
+			return nil, trace.Wrap(err)
+		}
+		c.Warnf("Found corrupted object %v: %v.", hash, err)
+		blobCorruptedObjects.Inc()
+		report.Corrupted = append(report.Corrupted, hash)
+		if err := c.quarantine(hash); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if err := c.fetchObject(hash); err != nil {
+			c.Warnf("Failed to repair object %v: %v.", hash, trace.DebugReport(err))
+			continue
+		}
+		report.Repaired = append(report.Repaired, hash)
+	}
+	report.Completed = c.Clock.Now().UTC()
+	if err := c.Backend.UpsertBLOBScrubReport(report); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	c.Infof("Completed %v.", report)
+	if err := c.pruneQuarantine(); err != nil {
+		c.Warnf("Failed to prune quarantined objects: %v.", trace.DebugReport(err))
+	}
+	if err := c.pruneScrubReports(); err != nil {
+		c.Warnf("Failed to prune scrub reports: %v.", trace.DebugReport(err))
+	}
+	return &report, nil
+}
+
+// pruneQuarantine removes the objects quarantined longer
+// than the retention period from the local storage
+func (c *cluster) pruneQuarantine() error {
+	quarantine, ok := c.Local.(blob.Quarantine)
+	if !ok {
+		return nil
+	}
+	before := c.Clock.Now().Add(-defaults.BLOBQuarantineRetention)
+	return trace.Wrap(quarantine.PruneQuarantine(before))
+}
+
+// pruneScrubReports removes the reports of the peers that have not sent
+// a heartbeat within the retention period or are no longer registered
+func (c *cluster) pruneScrubReports() error {
+	peers, err := c.Backend.GetPeers()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	lastHeartbeats := make(map[string]time.Time, len(peers))
+	for _, peer := range peers {
+		lastHeartbeats[peer.ID] = peer.LastHeartbeat
+	}
+	reports, err := c.Backend.GetBLOBScrubReports()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	for _, report := range reports {
+		if report.PeerID == c.ID {
+			continue
+		}
+		lastHeartbeat, ok := lastHeartbeats[report.PeerID]
+		if ok && c.Clock.Now().UTC().Sub(lastHeartbeat) < defaults.BLOBScrubReportRetention {
+			continue
+		}
+		c.Infof("Removing scrub report of departed peer %v.", report.PeerID)
+		err := c.Backend.DeleteBLOBScrubReport(report.PeerID)
+		if err != nil && !trace.IsNotFound(err) {
+			return trace.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// quarantine removes the corrupted object from the local storage
+func (c *cluster) quarantine(hash string) error {
+	if quarantine, ok := c.Local.(blob.Quarantine); ok {
+		return trace.Wrap(quarantine.QuarantineBLOB(hash))
+	}
+	return trace.Wrap(c.Local.DeleteBLOB(hash))
+}
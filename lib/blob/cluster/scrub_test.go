@@ -0,0 +1,190 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gravitational/gravity/lib/blob"
+	"github.com/gravitational/gravity/lib/blob/fs"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/storage/keyval"
+
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	. "gopkg.in/check.v1"
+)
+
+type ScrubSuite struct {
+	objects []*cluster
+	dirs    []string
+}
+
+var _ = Suite(&ScrubSuite{})
+
+func (s *ScrubSuite) SetUpTest(c *C) {
+	clock := clockwork.NewFakeClockAt(time.Now().UTC())
+	b, err := keyval.NewBolt(keyval.BoltConfig{
+		Clock: clock,
+		Path:  filepath.Join(c.MkDir(), "bolt.db"),
+	})
+	c.Assert(err, IsNil)
+
+	peers := make([]blob.Objects, 2)
+	getPeer := func(p storage.Peer) (blob.Objects, error) {
+		id, err := strconv.Atoi(p.ID)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return peers[id], nil
+	}
+
+	s.objects = make([]*cluster, len(peers))
+	s.dirs = make([]string, len(peers))
+	for i := range peers {
+		s.dirs[i] = c.MkDir()
+		peers[i], err = fs.New(s.dirs[i])
+		c.Assert(err, IsNil)
+		obj, err := New(Config{
+			Local:            peers[i],
+			Backend:          b,
+			GetPeer:          getPeer,
+			HeartbeatPeriod:  heartbeatPeriod,
+			MissedHeartbeats: missedHeartbeats,
+			Clock:            clock,
+			ID:               fmt.Sprintf("%v", i),
+			AdvertiseAddr:    "https://localhost",
+			TestMode:         true,
+			GracePeriod:      gracePeriod,
+		})
+		c.Assert(err, IsNil)
+		s.objects[i] = obj.(*cluster)
+		c.Assert(s.objects[i].heartbeat(), IsNil)
+	}
+}
+
+func (s *ScrubSuite) TearDownTest(c *C) {
+	for _, obj := range s.objects {
+		obj.Close()
+	}
+}
+
+func (s *ScrubSuite) TestRepairsCorruptedObjects(c *C) {
+	peer := s.objects[1]
+	data := []byte("hello, there, cluster!")
+	envelope, err := s.objects[0].WriteBLOB(bytes.NewReader(data))
+	c.Assert(err, IsNil)
+	other, err := s.objects[0].WriteBLOB(bytes.NewReader([]byte("other")))
+	c.Assert(err, IsNil)
+	c.Assert(peer.fetchNewObjects(), IsNil)
+
+	report, err := peer.Scrub(context.TODO())
+	c.Assert(err, IsNil)
+	c.Assert(report.Checked, Equals, 2)
+	c.Assert(report.Corrupted, HasLen, 0)
+
+	// flip the data of one copy and truncate the other
+	path := s.blobPath(1, envelope.SHA512)
+	c.Assert(ioutil.WriteFile(path, []byte("hello, there, clusteR!"), 0644), IsNil)
+	c.Assert(os.Truncate(s.blobPath(1, other.SHA512), 2), IsNil)
+
+	report, err = peer.Scrub(context.TODO())
+	c.Assert(err, IsNil)
+	c.Assert(report.Checked, Equals, 2)
+	c.Assert(report.Corrupted, DeepEquals, []string{envelope.SHA512, other.SHA512})
+	c.Assert(report.Repaired, DeepEquals, report.Corrupted)
+
+	_, err = os.Stat(filepath.Join(s.dirs[1], "quarantine", envelope.SHA512))
+	c.Assert(err, IsNil)
+	f, err := peer.Local.OpenBLOB(envelope.SHA512)
+	c.Assert(err, IsNil)
+	out, err := ioutil.ReadAll(f)
+	c.Assert(err, IsNil)
+	c.Assert(f.Close(), IsNil)
+	c.Assert(string(out), Equals, string(data))
+	c.Assert(blob.Verify(peer.Local, other.SHA512), IsNil)
+
+	saved, err := peer.Backend.GetBLOBScrubReport(peer.ID)
+	c.Assert(err, IsNil)
+	c.Assert(saved.Corrupted, DeepEquals, report.Corrupted)
+	c.Assert(saved.Unrepaired(), HasLen, 0)
+}
+
+func (s *ScrubSuite) TestReportsUnrepairedObjects(c *C) {
+	peer := s.objects[0]
+	envelope, err := peer.WriteBLOB(bytes.NewReader([]byte("data")))
+	c.Assert(err, IsNil)
+	// the only copy is corrupted
+	c.Assert(ioutil.WriteFile(s.blobPath(0, envelope.SHA512), []byte("date"), 0644), IsNil)
+
+	report, err := peer.Scrub(context.TODO())
+	c.Assert(err, IsNil)
+	c.Assert(report.Corrupted, DeepEquals, []string{envelope.SHA512})
+	c.Assert(report.Unrepaired(), DeepEquals, []string{envelope.SHA512})
+}
+
+func (s *ScrubSuite) TestPrunesQuarantine(c *C) {
+	peer := s.objects[0]
+	envelope, err := peer.WriteBLOB(bytes.NewReader([]byte("data")))
+	c.Assert(err, IsNil)
+	c.Assert(ioutil.WriteFile(s.blobPath(0, envelope.SHA512), []byte("date"), 0644), IsNil)
+
+	_, err = peer.Scrub(context.TODO())
+	c.Assert(err, IsNil)
+	quarantined := filepath.Join(s.dirs[0], "quarantine", envelope.SHA512)
+	_, err = os.Stat(quarantined)
+	c.Assert(err, IsNil)
+
+	peer.Clock.(clockwork.FakeClock).Advance(defaults.BLOBQuarantineRetention + time.Hour)
+	_, err = peer.Scrub(context.TODO())
+	c.Assert(err, IsNil)
+	_, err = os.Stat(quarantined)
+	c.Assert(os.IsNotExist(err), Equals, true)
+}
+
+func (s *ScrubSuite) TestPrunesReportsOfDepartedPeers(c *C) {
+	_, err := s.objects[0].Scrub(context.TODO())
+	c.Assert(err, IsNil)
+	_, err = s.objects[1].Scrub(context.TODO())
+	c.Assert(err, IsNil)
+	reports, err := s.objects[1].Backend.GetBLOBScrubReports()
+	c.Assert(err, IsNil)
+	c.Assert(reports, HasLen, 2)
+
+	// only the second peer keeps sending heartbeats
+	s.objects[1].Clock.(clockwork.FakeClock).Advance(defaults.BLOBScrubReportRetention + time.Hour)
+	c.Assert(s.objects[1].heartbeat(), IsNil)
+	_, err = s.objects[1].Scrub(context.TODO())
+	c.Assert(err, IsNil)
+
+	reports, err = s.objects[1].Backend.GetBLOBScrubReports()
+	c.Assert(err, IsNil)
+	c.Assert(reports, HasLen, 1)
+	c.Assert(reports[0].PeerID, Equals, s.objects[1].ID)
+}
+
+func (s *ScrubSuite) blobPath(peer int, hash string) string {
+	return filepath.Join(s.dirs[peer], "blobs", hash[:3], hash)
+}
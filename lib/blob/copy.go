@@ -0,0 +1,96 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob
+
+import (
+	"github.com/gravitational/trace"
+)
+
+// Copy copies the BLOB identified by hash from one storage to another.
+// If both storages support chunks, only the chunks missing in the
+// destination storage are transferred
+func Copy(dst, src Objects, hash string) (*Envelope, error) {
+	srcChunked, ok := src.(ChunkedObjects)
+	if ok {
+		if dstChunked, ok := dst.(ChunkedObjects); ok {
+			envelope, err := copyChunks(dstChunked, srcChunked, hash)
+			if err == nil {
+				return envelope, nil
+			}
+			// either side may be backed by storage that does not
+			// support chunks or run a version without chunk API,
+			// fall back to copying the whole object
+			if !trace.IsNotImplemented(err) && !trace.IsNotFound(err) {
+				return nil, trace.Wrap(err)
+			}
+		}
+	}
+	reader, err := src.OpenBLOB(hash)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	defer reader.Close()
+	envelope, err := dst.WriteBLOB(reader)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if envelope.SHA512 != hash {
+		return nil, trace.BadParameter("hash mismatch for %v: got %v", hash, envelope.SHA512)
+	}
+	return envelope, nil
+}
+
+func copyChunks(dst, src ChunkedObjects, hash string) (*Envelope, error) {
+	index, err := src.GetBLOBIndex(hash)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	hashes := make([]string, 0, len(index.Chunks))
+	for _, chunk := range index.Chunks {
+		hashes = append(hashes, chunk.SHA512)
+	}
+	missing, err := dst.GetMissingChunks(hashes)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	for _, hash := range missing {
+		if err := copyChunk(dst, src, hash); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	envelope, err := dst.WriteBLOBIndex(*index)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return envelope, nil
+}
+
+func copyChunk(dst, src ChunkedObjects, hash string) error {
+	reader, err := src.OpenChunk(hash)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer reader.Close()
+	chunk, err := dst.WriteChunk(reader)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if chunk.SHA512 != hash {
+		return trace.BadParameter("hash mismatch for chunk %v: got %v", hash, chunk.SHA512)
+	}
+	return nil
+}
@@ -24,9 +24,11 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"time"
 
 	"github.com/gravitational/gravity/lib/blob"
 	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/utils"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/gravitational/trace"
@@ -57,6 +59,10 @@ func (o *objects) blobDir() string {
 	return filepath.Join(o.dir, "blobs")
 }
 
+func (o *objects) quarantineDir() string {
+	return filepath.Join(o.dir, "quarantine")
+}
+
 // hashDir helps us to organize the blobs in the folder -
 // instead of putting all blobs in one folder, we
 // will put them in 4096 folders, groping by first 3 strings
@@ -173,3 +179,27 @@ func (o *objects) DeleteBLOB(hash string) error {
 	}
 	return nil
 }
+
+// QuarantineBLOB moves the corrupted BLOB out of the storage
+// into the quarantine directory
+func (o *objects) QuarantineBLOB(hash string) error {
+	if err := os.MkdirAll(o.quarantineDir(), defaults.SharedDirMask); err != nil {
+		return trace.Wrap(err)
+	}
+	path := filepath.Join(o.quarantineDir(), hash)
+	err := os.Rename(filepath.Join(o.hashDir(hash), hash), path)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	// record the time the object has been quarantined for pruning
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	return nil
+}
+
+// PruneQuarantine removes the BLOBs quarantined before the specified time
+func (o *objects) PruneQuarantine(before time.Time) error {
+	return trace.Wrap(utils.RemoveOlderThan(o.quarantineDir(), before))
+}
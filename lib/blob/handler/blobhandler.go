@@ -29,6 +29,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/gravitational/form"
 	"github.com/gravitational/roundtrip"
+	telehttplib "github.com/gravitational/teleport/lib/httplib"
 	"github.com/gravitational/trace"
 	"github.com/julienschmidt/httprouter"
 )
@@ -80,6 +81,12 @@ func New(cfg Config) (*Server, error) {
 		h.GET(handler.prefix+"/blobs/:hash/envelope", h.needsAuth(h.getBLOBEnvelope, handler.objects))
 		h.HEAD(handler.prefix+"/blobs/:hash", h.needsAuth(h.getBLOB, handler.objects))
 		h.POST(handler.prefix+"/blobs", h.needsAuth(h.createBLOB, handler.objects))
+		h.GET(handler.prefix+"/blobs/:hash/index", h.needsAuth(h.getBLOBIndex, handler.objects))
+		h.POST(handler.prefix+"/indexes", h.needsAuth(h.createBLOBIndex, handler.objects))
+		h.GET(handler.prefix+"/chunks/:hash", h.needsAuth(h.getChunk, handler.objects))
+		h.HEAD(handler.prefix+"/chunks/:hash", h.needsAuth(h.getChunk, handler.objects))
+		h.POST(handler.prefix+"/chunks", h.needsAuth(h.createChunk, handler.objects))
+		h.POST(handler.prefix+"/chunks/missing", h.needsAuth(h.getMissingChunks, handler.objects))
 	}
 
 	h.NotFound = h.notFound
@@ -165,6 +172,107 @@ func (s *Server) createBLOB(w http.ResponseWriter, r *http.Request, p httprouter
 	return nil
 }
 
+func (s *Server) getBLOBIndex(w http.ResponseWriter, r *http.Request, p httprouter.Params, objects blob.Objects) error {
+	chunked, err := chunkedObjects(objects)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	index, err := chunked.GetBLOBIndex(p.ByName("hash"))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, index)
+	return nil
+}
+
+func (s *Server) createBLOBIndex(w http.ResponseWriter, r *http.Request, p httprouter.Params, objects blob.Objects) error {
+	chunked, err := chunkedObjects(objects)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	var index blob.Index
+	if err := telehttplib.ReadJSON(r, &index); err != nil {
+		return trace.Wrap(err)
+	}
+	envelope, err := chunked.WriteBLOBIndex(index)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, envelope)
+	return nil
+}
+
+func (s *Server) getChunk(w http.ResponseWriter, r *http.Request, p httprouter.Params, objects blob.Objects) error {
+	chunked, err := chunkedObjects(objects)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	hash := p.ByName("hash")
+	reader, err := chunked.OpenChunk(hash)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer reader.Close()
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename=%v`, hash))
+	http.ServeContent(w, r, hash, time.Now(), reader)
+	return nil
+}
+
+func (s *Server) createChunk(w http.ResponseWriter, r *http.Request, p httprouter.Params, objects blob.Objects) error {
+	chunked, err := chunkedObjects(objects)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	var files form.Files
+	err = form.Parse(r,
+		form.FileSlice("file", &files),
+	)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if len(files) != 1 {
+		return trace.BadParameter("expected a single file parameter but got %d", len(files))
+	}
+	defer func() {
+		if err := files.Close(); err != nil {
+			log.Errorf("failed to close files: %v", trace.DebugReport(err))
+		}
+	}()
+	chunk, err := chunked.WriteChunk(files[0])
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, chunk)
+	return nil
+}
+
+func (s *Server) getMissingChunks(w http.ResponseWriter, r *http.Request, p httprouter.Params, objects blob.Objects) error {
+	chunked, err := chunkedObjects(objects)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	var hashes []string
+	if err := telehttplib.ReadJSON(r, &hashes); err != nil {
+		return trace.Wrap(err)
+	}
+	missing, err := chunked.GetMissingChunks(hashes)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, missing)
+	return nil
+}
+
+// chunkedObjects returns the storage as chunked storage
+// or an error if the storage does not support chunks
+func chunkedObjects(objects blob.Objects) (blob.ChunkedObjects, error) {
+	chunked, ok := objects.(blob.ChunkedObjects)
+	if !ok {
+		return nil, trace.NotImplemented("storage does not support chunks")
+	}
+	return chunked, nil
+}
+
 func (s *Server) needsAuth(fn authHandle, objects blob.Objects) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
 		log.WithFields(log.Fields{
@@ -188,7 +296,7 @@ func (s *Server) needsAuth(fn authHandle, objects blob.Objects) httprouter.Handl
 
 		acl := blob.WithPermissions(objects, s.cfg.Users, user.GetName(), checker)
 		if err := fn(w, r, p, acl); err != nil {
-			if !trace.IsNotFound(err) && !trace.IsAlreadyExists(err) {
+			if !trace.IsNotFound(err) && !trace.IsAlreadyExists(err) && !trace.IsNotImplemented(err) {
 				log.Errorf("handler error: %v", trace.DebugReport(err))
 			}
 			trace.WriteError(w, err)
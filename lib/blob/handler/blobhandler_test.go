@@ -17,13 +17,17 @@ limitations under the License.
 package handler
 
 import (
+	"bytes"
 	"crypto/tls"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/gravitational/gravity/lib/blob"
+	"github.com/gravitational/gravity/lib/blob/chunked"
 	"github.com/gravitational/gravity/lib/blob/client"
 	"github.com/gravitational/gravity/lib/blob/fs"
 	"github.com/gravitational/gravity/lib/blob/suite"
@@ -33,6 +37,7 @@ import (
 	"github.com/gravitational/gravity/lib/users/usersservice"
 
 	"github.com/gravitational/roundtrip"
+	"github.com/gravitational/trace"
 	"github.com/jonboulle/clockwork"
 	log "github.com/sirupsen/logrus"
 	. "gopkg.in/check.v1"
@@ -90,6 +95,12 @@ func (s *HandlerSuite) SetUpTest(c *C) {
 	err = s.users.UpsertUser(s.adminUser)
 	c.Assert(err, IsNil)
 
+	s.webServer, s.suite.Objects = s.newServer(c, objects)
+}
+
+// newServer returns a new server for the specified storage
+// and a client for it
+func (s *HandlerSuite) newServer(c *C, objects blob.Objects) (*httptest.Server, *client.Client) {
 	webHandler, err := New(Config{
 		Users:   s.users,
 		Local:   objects,
@@ -98,12 +109,12 @@ func (s *HandlerSuite) SetUpTest(c *C) {
 	c.Assert(err, IsNil)
 	mux := http.NewServeMux()
 	mux.Handle("/objects/", webHandler)
-	s.webServer = httptest.NewServer(mux)
+	webServer := httptest.NewServer(mux)
 
 	// for regular test, let's be admins, so tests
 	// won't be affected by auth issues
-	s.suite.Objects, err = client.NewAuthenticatedClient(
-		s.webServer.URL, s.adminUser.GetName(), "admin-password",
+	clt, err := client.NewAuthenticatedClient(
+		webServer.URL, s.adminUser.GetName(), "admin-password",
 		roundtrip.HTTPClient(&http.Client{
 			Transport: &http.Transport{
 				TLSClientConfig: &tls.Config{
@@ -111,6 +122,7 @@ func (s *HandlerSuite) SetUpTest(c *C) {
 				}}}),
 	)
 	c.Assert(err, IsNil)
+	return webServer, clt
 }
 
 func (s *HandlerSuite) TestBLOB(c *C) {
@@ -128,3 +140,52 @@ func (s *HandlerSuite) TestBLOBWriteTwice(c *C) {
 func (s *HandlerSuite) TestBLOBList(c *C) {
 	s.suite.BLOBList(c)
 }
+
+func (s *HandlerSuite) TestCopyChunks(c *C) {
+	local, err := chunked.New(chunked.Config{Path: c.MkDir()})
+	c.Assert(err, IsNil)
+	remote, err := chunked.New(chunked.Config{Path: c.MkDir()})
+	c.Assert(err, IsNil)
+	server, clt := s.newServer(c, remote)
+	defer server.Close()
+
+	data := bytes.Repeat([]byte("chunked data "), 1024*1024)
+	envelope, err := local.WriteBLOB(bytes.NewReader(data))
+	c.Assert(err, IsNil)
+
+	_, err = blob.Copy(clt, local, envelope.SHA512)
+	c.Assert(err, IsNil)
+	index, err := clt.GetBLOBIndex(envelope.SHA512)
+	c.Assert(err, IsNil)
+	localIndex, err := local.GetBLOBIndex(envelope.SHA512)
+	c.Assert(err, IsNil)
+	c.Assert(index.Chunks, DeepEquals, localIndex.Chunks)
+
+	reader, err := clt.OpenBLOB(envelope.SHA512)
+	c.Assert(err, IsNil)
+	defer reader.Close()
+	out, err := ioutil.ReadAll(reader)
+	c.Assert(err, IsNil)
+	c.Assert(out, DeepEquals, data)
+}
+
+func (s *HandlerSuite) TestCopyFallsBackToBLOB(c *C) {
+	local, err := chunked.New(chunked.Config{Path: c.MkDir()})
+	c.Assert(err, IsNil)
+
+	data := []byte("hello, blob")
+	envelope, err := local.WriteBLOB(bytes.NewReader(data))
+	c.Assert(err, IsNil)
+
+	// the server uses the filesystem storage without chunk support
+	_, err = blob.Copy(s.suite.Objects, local, envelope.SHA512)
+	c.Assert(err, IsNil)
+	_, err = s.suite.Objects.(*client.Client).GetBLOBIndex(envelope.SHA512)
+	c.Assert(trace.IsNotImplemented(err), Equals, true, Commentf("%v", err))
+
+	c.Assert(local.DeleteBLOB(envelope.SHA512), IsNil)
+	_, err = blob.Copy(local, s.suite.Objects, envelope.SHA512)
+	c.Assert(err, IsNil)
+	_, err = local.GetBLOBIndex(envelope.SHA512)
+	c.Assert(err, IsNil)
+}
@@ -0,0 +1,306 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package s3 implements BLOB storage in an S3-compatible object store.
+//
+// BLOBs are stored as objects named after their hashes under the configured
+// prefix, so any number of package service instances can share the bucket
+// without keeping package data locally.
+package s3
+
+import (
+	"crypto/sha512"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gravitational/gravity/lib/blob"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+)
+
+// Config defines the configuration of the S3 BLOB storage
+type Config struct {
+	// Bucket is the bucket to store BLOBs in
+	Bucket string
+	// Prefix is the key prefix for BLOBs within the bucket
+	Prefix string
+	// Region is the bucket region
+	Region string
+	// Endpoint is an optional endpoint of an S3-compatible object
+	// store, e.g. a Minio server
+	Endpoint string
+	// TempDir is the directory for BLOBs being uploaded.
+	// Defaults to the system temporary directory
+	TempDir string
+	// S3 is optional S3 API client
+	S3 s3iface.S3API
+}
+
+// CheckAndSetDefaults validates the config and sets defaults
+func (c *Config) CheckAndSetDefaults() error {
+	if c.Bucket == "" {
+		return trace.BadParameter("missing S3 bucket")
+	}
+	c.Prefix = strings.Trim(c.Prefix, "/")
+	if c.Region == "" {
+		c.Region = defaults.AWSRegion
+	}
+	if c.TempDir != "" {
+		if err := os.MkdirAll(c.TempDir, defaults.SharedDirMask); err != nil {
+			return trace.ConvertSystemError(err)
+		}
+	}
+	if c.S3 == nil {
+		config := &aws.Config{
+			Region: aws.String(c.Region),
+		}
+		if c.Endpoint != "" {
+			// S3-compatible stores usually do not support
+			// virtual-hosted-style bucket addressing
+			config.Endpoint = aws.String(c.Endpoint)
+			config.S3ForcePathStyle = aws.Bool(true)
+		}
+		session, err := session.NewSession(config)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		c.S3 = s3.New(session)
+	}
+	return nil
+}
+
+// New returns a new BLOB storage that keeps BLOBs in an S3 bucket
+func New(config Config) (blob.Objects, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &objects{
+		Config:   config,
+		uploader: s3manager.NewUploaderWithClient(config.S3),
+	}, nil
+}
+
+type objects struct {
+	Config
+	uploader *s3manager.Uploader
+}
+
+// Close is a no-op for the S3 storage
+func (o *objects) Close() error {
+	return nil
+}
+
+// GetBLOBs returns a list of BLOBs in the storage
+func (o *objects) GetBLOBs() ([]string, error) {
+	var out []string
+	prefix := o.listPrefix()
+	err := o.S3.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(o.Bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, object := range page.Contents {
+			hash := strings.TrimPrefix(aws.StringValue(object.Key), prefix)
+			if hash != "" && !strings.Contains(hash, "/") {
+				out = append(out, hash)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, trace.Wrap(utils.ConvertS3Error(err))
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// WriteBLOB writes object to the storage, returns object envelope
+func (o *objects) WriteBLOB(data io.Reader) (*blob.Envelope, error) {
+	// the object key is the hash of the data, so the data is
+	// spooled to a temporary file first to compute the hash
+	f, err := ioutil.TempFile(o.TempDir, "blob")
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	defer func() {
+		f.Close()
+		if err := os.Remove(f.Name()); err != nil {
+			log.WithError(err).Warnf("Failed to remove %v.", f.Name())
+		}
+	}()
+
+	hasher := sha512.New()
+	if _, err := io.Copy(io.MultiWriter(f, hasher), data); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	hash := fmt.Sprintf("%x", hasher.Sum(nil)[:sha512.Size/2])
+
+	envelope, err := o.GetBLOBEnvelope(hash)
+	if err == nil {
+		return envelope, nil
+	}
+	if !trace.IsNotFound(err) {
+		return nil, trace.Wrap(err)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	_, err = o.uploader.Upload(&s3manager.UploadInput{
+		Bucket: aws.String(o.Bucket),
+		Key:    aws.String(o.key(hash)),
+		Body:   f,
+	})
+	if err != nil {
+		return nil, trace.Wrap(utils.ConvertS3Error(err))
+	}
+	return o.GetBLOBEnvelope(hash)
+}
+
+// GetBLOBEnvelope returns the BLOB information identified by hash
+func (o *objects) GetBLOBEnvelope(hash string) (*blob.Envelope, error) {
+	out, err := o.S3.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(o.Bucket),
+		Key:    aws.String(o.key(hash)),
+	})
+	if err != nil {
+		return nil, trace.Wrap(utils.ConvertS3Error(err))
+	}
+	return &blob.Envelope{
+		SizeBytes: aws.Int64Value(out.ContentLength),
+		SHA512:    hash,
+		Modified:  aws.TimeValue(out.LastModified).UTC(),
+	}, nil
+}
+
+// OpenBLOB opens the BLOB identified by hash and returns reader
+func (o *objects) OpenBLOB(hash string) (blob.ReadSeekCloser, error) {
+	envelope, err := o.GetBLOBEnvelope(hash)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &reader{
+		objects: o,
+		key:     o.key(hash),
+		size:    envelope.SizeBytes,
+	}, nil
+}
+
+// DeleteBLOB deletes BLOB from the storage
+func (o *objects) DeleteBLOB(hash string) error {
+	// deleting a missing object is not an error in S3
+	if _, err := o.GetBLOBEnvelope(hash); err != nil {
+		return trace.Wrap(err)
+	}
+	_, err := o.S3.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(o.Bucket),
+		Key:    aws.String(o.key(hash)),
+	})
+	return trace.Wrap(utils.ConvertS3Error(err))
+}
+
+// key returns the key of the object with the specified hash
+func (o *objects) key(hash string) string {
+	return path.Join(o.Prefix, hash)
+}
+
+// listPrefix returns the common prefix of the object keys
+func (o *objects) listPrefix() string {
+	if o.Prefix == "" {
+		return ""
+	}
+	return o.Prefix + "/"
+}
+
+// reader reads the object with ranged requests
+// so it can be repositioned without downloading
+// the preceding data
+type reader struct {
+	*objects
+	key    string
+	size   int64
+	offset int64
+	body   io.ReadCloser
+}
+
+// Read reads the object data starting at the current offset
+func (r *reader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	if r.body == nil {
+		out, err := r.S3.GetObject(&s3.GetObjectInput{
+			Bucket: aws.String(r.Bucket),
+			Key:    aws.String(r.key),
+			Range:  aws.String(fmt.Sprintf("bytes=%v-", r.offset)),
+		})
+		if err != nil {
+			return 0, trace.Wrap(utils.ConvertS3Error(err))
+		}
+		r.body = out.Body
+	}
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	return n, err
+}
+
+// Seek sets the offset for the next Read
+func (r *reader) Seek(offset int64, whence int) (int64, error) {
+	var position int64
+	switch whence {
+	case io.SeekStart:
+		position = offset
+	case io.SeekCurrent:
+		position = r.offset + offset
+	case io.SeekEnd:
+		position = r.size + offset
+	default:
+		return 0, trace.BadParameter("invalid whence: %v", whence)
+	}
+	if position < 0 {
+		return 0, trace.BadParameter("negative position: %v", position)
+	}
+	if position != r.offset {
+		r.closeBody()
+		r.offset = position
+	}
+	return position, nil
+}
+
+// Close closes the reader
+func (r *reader) Close() error {
+	r.closeBody()
+	return nil
+}
+
+func (r *reader) closeBody() {
+	if r.body != nil {
+		r.body.Close()
+		r.body = nil
+	}
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gravitational/gravity/lib/blob/suite"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/gravitational/trace"
+	. "gopkg.in/check.v1"
+)
+
+func TestS3(t *testing.T) { TestingT(t) }
+
+type S3Suite struct {
+	server *httptest.Server
+	store  *fakeS3
+	suite  suite.BLOBSuite
+}
+
+var _ = Suite(&S3Suite{})
+
+func (s *S3Suite) SetUpTest(c *C) {
+	s.store = newFakeS3()
+	s.server = httptest.NewServer(s.store)
+	session, err := session.NewSession(&aws.Config{
+		Region:           aws.String("us-east-1"),
+		Endpoint:         aws.String(s.server.URL),
+		S3ForcePathStyle: aws.Bool(true),
+		Credentials:      credentials.NewStaticCredentials("id", "secret", ""),
+	})
+	c.Assert(err, IsNil)
+	s.suite.Objects, err = New(Config{
+		Bucket:  "bucket",
+		Prefix:  "/opscenter/packages/",
+		TempDir: c.MkDir(),
+		S3:      s3.New(session),
+	})
+	c.Assert(err, IsNil)
+}
+
+func (s *S3Suite) TearDownTest(c *C) {
+	s.server.Close()
+}
+
+func (s *S3Suite) TestBLOB(c *C) {
+	s.suite.BLOB(c)
+}
+
+func (s *S3Suite) TestBLOBList(c *C) {
+	s.suite.BLOBList(c)
+}
+
+func (s *S3Suite) TestBLOBSeek(c *C) {
+	s.suite.BLOBSeek(c)
+}
+
+func (s *S3Suite) TestBLOBWriteTwice(c *C) {
+	s.suite.BLOBWriteTwice(c)
+}
+
+func (s *S3Suite) TestStoresUnderPrefix(c *C) {
+	envelope, err := s.suite.Objects.WriteBLOB(bytes.NewBufferString("data"))
+	c.Assert(err, IsNil)
+	c.Assert(s.store.keys(), DeepEquals, []string{
+		fmt.Sprintf("bucket/opscenter/packages/%v", envelope.SHA512),
+	})
+}
+
+func (s *S3Suite) TestReadsFromOffset(c *C) {
+	envelope, err := s.suite.Objects.WriteBLOB(bytes.NewBufferString("hello, world"))
+	c.Assert(err, IsNil)
+	r, err := s.suite.Objects.OpenBLOB(envelope.SHA512)
+	c.Assert(err, IsNil)
+	defer r.Close()
+
+	_, err = r.Seek(7, 0)
+	c.Assert(err, IsNil)
+	out, err := ioutil.ReadAll(r)
+	c.Assert(err, IsNil)
+	c.Assert(string(out), Equals, "world")
+
+	size, err := r.Seek(0, 2)
+	c.Assert(err, IsNil)
+	c.Assert(size, Equals, envelope.SizeBytes)
+}
+
+func (s *S3Suite) TestMissingBLOB(c *C) {
+	_, err := s.suite.Objects.GetBLOBEnvelope("missing")
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%v", err))
+	err = s.suite.Objects.DeleteBLOB("missing")
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%v", err))
+}
+
+// fakeS3 is a minimal in-memory stand-in for an S3-compatible object store
+// that supports path-style object requests and listing
+type fakeS3 struct {
+	sync.Mutex
+	objects map[string]fakeObject
+}
+
+type fakeObject struct {
+	data     []byte
+	modified time.Time
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{objects: make(map[string]fakeObject)}
+}
+
+func (s *fakeS3) keys() (keys []string) {
+	s.Lock()
+	defer s.Unlock()
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	if r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2" {
+		s.list(w, key, r.URL.Query().Get("prefix"))
+		return
+	}
+	object, exists := s.objects[key]
+	switch r.Method {
+	case http.MethodPut:
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		s.objects[key] = fakeObject{
+			data:     data,
+			modified: time.Now().UTC().Truncate(time.Second),
+		}
+	case http.MethodHead:
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(object.data)))
+		w.Header().Set("Last-Modified", object.modified.Format(http.TimeFormat))
+	case http.MethodGet:
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+			return
+		}
+		http.ServeContent(w, r, key, object.modified, bytes.NewReader(object.data))
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *fakeS3) list(w http.ResponseWriter, bucket, prefix string) {
+	type content struct {
+		Key          string
+		Size         int
+		LastModified time.Time
+	}
+	result := struct {
+		XMLName     xml.Name `xml:"ListBucketResult"`
+		Name        string
+		Prefix      string
+		KeyCount    int
+		IsTruncated bool
+		Contents    []content
+	}{Name: bucket, Prefix: prefix}
+	for key, object := range s.objects {
+		key = strings.TrimPrefix(key, bucket+"/")
+		if strings.HasPrefix(key, prefix) {
+			result.Contents = append(result.Contents, content{
+				Key:          key,
+				Size:         len(object.data),
+				LastModified: object.modified,
+			})
+		}
+	}
+	result.KeyCount = len(result.Contents)
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(result)
+}
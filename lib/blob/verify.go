@@ -0,0 +1,71 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob
+
+import (
+	"crypto/sha512"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gravitational/trace"
+)
+
+// Quarantine is implemented by storages that can set corrupted
+// objects aside instead of deleting them
+type Quarantine interface {
+	// QuarantineBLOB moves the object identified by hash out of the storage
+	QuarantineBLOB(hash string) error
+	// PruneQuarantine removes the objects quarantined before the specified time
+	PruneQuarantine(before time.Time) error
+}
+
+// Verify reads the BLOB identified by hash and checks that its
+// size and hash match the envelope.
+// Returns trace.NotFound if the BLOB does not exist and
+// trace.CompareFailed if the BLOB is corrupted or can not be read
+func Verify(objects Objects, hash string) error {
+	envelope, err := objects.GetBLOBEnvelope(hash)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.Wrap(err)
+		}
+		return trace.CompareFailed("failed to read envelope of %v: %v", hash, err)
+	}
+	reader, err := objects.OpenBLOB(hash)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.Wrap(err)
+		}
+		return trace.CompareFailed("failed to open %v: %v", hash, err)
+	}
+	defer reader.Close()
+	hasher := sha512.New()
+	size, err := io.Copy(hasher, reader)
+	if err != nil {
+		return trace.CompareFailed("failed to read %v: %v", hash, err)
+	}
+	if size != envelope.SizeBytes {
+		return trace.CompareFailed("size mismatch for %v: expected %v bytes, got %v",
+			hash, envelope.SizeBytes, size)
+	}
+	actual := fmt.Sprintf("%x", hasher.Sum(nil)[:sha512.Size/2])
+	if actual != hash {
+		return trace.CompareFailed("hash mismatch for %v: got %v", hash, actual)
+	}
+	return nil
+}
@@ -71,6 +71,10 @@ func Build(ctx context.Context, builder *Builder) error {
 		if err != nil {
 			return trace.Wrap(err)
 		}
+		err = builder.ResolveDependencies()
+		if err != nil {
+			return trace.Wrap(err)
+		}
 		err = builder.SyncPackageCache(runtimeVersion)
 		if err != nil {
 			if trace.IsNotFound(err) {
@@ -81,6 +85,12 @@ func Build(ctx context.Context, builder *Builder) error {
 	}
 
 	builder.NextStep("Embedding application container images")
+	if builder.Manifest.Kind == schema.KindApplication {
+		err = builder.ResolveDependencies()
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	}
 	vendorDir, err := ioutil.TempDir("", "vendor")
 	if err != nil {
 		return trace.Wrap(err)
@@ -99,6 +109,16 @@ func Build(ctx context.Context, builder *Builder) error {
 	}
 
 	builder.NextStep("Generating the cluster snapshot")
+	if builder.DeltaFrom != "" {
+		builder.PrintSubStep("Computing delta against %v", builder.DeltaFrom)
+		err = builder.LoadDeltaBase()
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if builder.Signer != nil {
+		builder.PrintSubStep("Signing packages with key %v", builder.Signer.KeyID())
+	}
 	installer, err := builder.GenerateInstaller(*application)
 	if err != nil {
 		return trace.Wrap(err)
@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/gravitational/gravity/lib/app"
+	"github.com/gravitational/gravity/lib/app/delta"
 	"github.com/gravitational/gravity/lib/app/service"
 	blobfs "github.com/gravitational/gravity/lib/blob/fs"
 	"github.com/gravitational/gravity/lib/constants"
@@ -37,6 +38,7 @@ import (
 	"github.com/gravitational/gravity/lib/pack"
 	"github.com/gravitational/gravity/lib/pack/layerpack"
 	"github.com/gravitational/gravity/lib/pack/localpack"
+	"github.com/gravitational/gravity/lib/pack/signing"
 	"github.com/gravitational/gravity/lib/schema"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/storage/keyval"
@@ -87,6 +89,11 @@ type Config struct {
 	utils.Progress
 	// Silent suppresses all std output when set to true
 	Silent bool
+	// Signer optionally signs all packages in the generated installer
+	Signer *signing.Signer
+	// DeltaFrom is the optional path to the installer tarball or
+	// the locator of the cluster image to build a delta installer against
+	DeltaFrom string
 }
 
 // CheckAndSetDefaults validates builder config and fills in defaults
@@ -204,6 +211,11 @@ type Builder struct {
 	Packages pack.PackageService
 	// Apps is the application service based on the layered package service
 	Apps app.Applications
+	// DeltaBase is the cluster image to build a delta installer against
+	DeltaBase *delta.Base
+	// DependencyLock records how the manifest dependency constraints
+	// have been resolved
+	DependencyLock *DependencyLock
 }
 
 // Locator returns locator of the application that's being built
@@ -304,6 +316,17 @@ func (b *Builder) Vendor(ctx context.Context, dir string) (io.ReadCloser, error)
 			return nil, trace.Wrap(err)
 		}
 	}
+	if b.DependencyLock != nil {
+		data, err := yaml.Marshal(b.DependencyLock)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		err = ioutil.WriteFile(filepath.Join(dir, defaults.ResourcesDir, defaults.DependencyLockFileName),
+			data, defaults.SharedReadMask)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
 	vendorer, err := service.NewVendorer(service.VendorerConfig{
 		DockerURL:   constants.DockerEngineURL,
 		RegistryURL: constants.DockerRegistry,
@@ -355,6 +378,85 @@ func (b *Builder) GenerateInstaller(application app.Application) (io.ReadCloser,
 	return b.Generator.Generate(b, application)
 }
 
+// LoadDeltaBase indexes the cluster image specified with DeltaFrom
+// which is either a path to its installer tarball or its locator
+// in the package cache
+func (b *Builder) LoadDeltaBase() (err error) {
+	if _, err := os.Stat(b.DeltaFrom); err == nil {
+		b.DeltaBase, err = b.loadDeltaBaseFromTarball(b.DeltaFrom)
+		return trace.Wrap(err)
+	}
+	locator, err := loc.ParseLocator(b.DeltaFrom)
+	if err != nil {
+		return trace.BadParameter("%v is neither an installer tarball "+
+			"nor a package locator", b.DeltaFrom)
+	}
+	b.DeltaBase, err = delta.NewBase(b.Packages, *locator)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.NotFound("%v is not found in the package cache, "+
+				"please specify the path to its installer tarball instead", locator)
+		}
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+// loadDeltaBaseFromTarball indexes the cluster image in the installer
+// tarball specified with path
+func (b *Builder) loadDeltaBaseFromTarball(path string) (*delta.Base, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	defer file.Close()
+	dir, err := ioutil.TempDir(b.Dir, "delta")
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	defer os.RemoveAll(dir)
+	err = archive.Untar(file, dir, &archive.TarOptions{NoLchown: true})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	manifestBytes, err := ioutil.ReadFile(filepath.Join(dir, defaults.ManifestFileName))
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	manifest, err := schema.ParseManifestYAMLNoValidate(manifestBytes)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	locator := manifest.Locator()
+	if locator.Repository == "" {
+		locator.Repository = defaults.SystemAccountOrg
+	}
+	backend, err := keyval.NewBolt(keyval.BoltConfig{
+		Path: filepath.Join(dir, defaults.GravityDBFile),
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	defer backend.Close()
+	objects, err := blobfs.New(filepath.Join(dir, defaults.PackagesDir))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	packages, err := localpack.New(localpack.Config{
+		Backend:     backend,
+		UnpackedDir: filepath.Join(dir, defaults.PackagesDir, defaults.UnpackedDir),
+		Objects:     objects,
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	base, err := delta.NewBase(packages, locator)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return base, nil
+}
+
 // WriteInstaller writes the provided installer tarball data to disk
 func (b *Builder) WriteInstaller(data io.ReadCloser) error {
 	f, err := os.Create(b.OutPath)
@@ -0,0 +1,198 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"sort"
+
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/schema"
+
+	"github.com/gravitational/trace"
+)
+
+// PackageSource is implemented by syncers that can list packages
+// available in the repository they synchronize from
+type PackageSource interface {
+	// PackageService returns the package service of the repository
+	PackageService() pack.PackageService
+}
+
+// DependencyLock records the versions the manifest dependency constraints
+// have been resolved to
+type DependencyLock struct {
+	// Dependencies is a list of resolved dependencies
+	Dependencies []LockedDependency `json:"dependencies"`
+}
+
+// LockedDependency describes a single resolved dependency
+type LockedDependency struct {
+	// Dependency is the dependency as specified in the manifest,
+	// e.g. "gravitational.io/app:~1.4"
+	Dependency string `json:"dependency"`
+	// Resolved is the locator of the version the dependency was resolved to
+	Resolved string `json:"resolved"`
+}
+
+// ResolveDependencies resolves version constraints of the manifest
+// dependencies to the latest matching versions available in the local
+// package cache and the remote package repository.
+//
+// Dependencies explicitly overridden with VendorReq.SetDeps are resolved
+// to the specified version. The remaining constraints can only be resolved
+// if the remote repository supports listing packages, otherwise the versions
+// found in the local cache might be outdated
+func (b *Builder) ResolveDependencies() error {
+	deps := b.Manifest.Dependencies.Constrained()
+	if len(deps) == 0 {
+		return nil
+	}
+	sources := []pack.PackageService{b.Env.Packages}
+	if unresolved := withoutOverrides(deps, b.VendorReq.SetDeps); len(unresolved) != 0 {
+		syncer, err := b.NewSyncer(b)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		source, ok := syncer.(PackageSource)
+		if !ok {
+			return trace.BadParameter("dependency version constraints %v can not be "+
+				"resolved because the package repository does not support listing "+
+				"packages, specify exact versions in the manifest or with --set-dep",
+				dependencies(unresolved))
+		}
+		sources = append(sources, source.PackageService())
+	}
+	lock, err := resolveDependencies(deps, b.VendorReq.SetDeps, sources)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	resolved := make(map[string]loc.Locator)
+	for _, dep := range lock.Dependencies {
+		locator, err := loc.ParseLocator(dep.Resolved)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		b.PrintSubStep("Resolved dependency %v to version %v", dep.Dependency, locator.Version)
+		resolved[dep.Dependency] = *locator
+	}
+	for _, deps := range [][]schema.Dependency{b.Manifest.Dependencies.Packages, b.Manifest.Dependencies.Apps} {
+		for i, dep := range deps {
+			if locator, ok := resolved[dep.String()]; ok {
+				deps[i] = schema.Dependency{Locator: locator}
+				// the vendored manifest is rewritten using the same mechanism
+				// as explicit --set-dep overrides
+				b.VendorReq.SetDeps = append(b.VendorReq.SetDeps, locator)
+			}
+		}
+	}
+	b.DependencyLock = lock
+	return nil
+}
+
+// resolveDependencies resolves each of the specified constrained dependencies
+// to the latest version satisfying its constraint found in any of the sources
+func resolveDependencies(deps []schema.Dependency, overrides []loc.Locator, sources []pack.PackageService) (*DependencyLock, error) {
+	var lock DependencyLock
+	for _, dep := range deps {
+		locator, err := resolveDependency(dep, overrides, sources)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		lock.Dependencies = append(lock.Dependencies, LockedDependency{
+			Dependency: dep.String(),
+			Resolved:   locator.String(),
+		})
+	}
+	sort.Slice(lock.Dependencies, func(i, j int) bool {
+		return lock.Dependencies[i].Dependency < lock.Dependencies[j].Dependency
+	})
+	return &lock, nil
+}
+
+// withoutOverrides returns the dependencies not overridden
+// with any of the specified locators
+func withoutOverrides(deps []schema.Dependency, overrides []loc.Locator) (out []schema.Dependency) {
+	for _, dep := range deps {
+		if findOverride(dep, overrides) == nil {
+			out = append(out, dep)
+		}
+	}
+	return out
+}
+
+// findOverride returns the locator overriding the specified dependency
+// or nil if the dependency is not overridden
+func findOverride(dep schema.Dependency, overrides []loc.Locator) *loc.Locator {
+	for _, override := range overrides {
+		if override.Repository == dep.Locator.Repository && override.Name == dep.Locator.Name {
+			return &override
+		}
+	}
+	return nil
+}
+
+// dependencies formats the specified dependencies as a list of strings
+func dependencies(deps []schema.Dependency) (out []string) {
+	for _, dep := range deps {
+		out = append(out, dep.String())
+	}
+	return out
+}
+
+func resolveDependency(dep schema.Dependency, overrides []loc.Locator, sources []pack.PackageService) (*loc.Locator, error) {
+	if override := findOverride(dep, overrides); override != nil {
+		return override, nil
+	}
+	constraint, err := loc.ParseVersionConstraint(dep.Constraint)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var latest *loc.Locator
+	for _, source := range sources {
+		locator, err := pack.FindLatestPackageCustom(pack.FindLatestPackageRequest{
+			Packages:   source,
+			Repository: dep.Locator.Repository,
+			Match: func(e pack.PackageEnvelope) bool {
+				return e.Locator.Name == dep.Locator.Name &&
+					constraint.Check(e.Locator.Version)
+			},
+		})
+		if err != nil {
+			if trace.IsNotFound(err) {
+				continue
+			}
+			return nil, trace.Wrap(err)
+		}
+		if latest == nil {
+			latest = locator
+			continue
+		}
+		newer, err := locator.IsNewerThan(*latest)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if newer {
+			latest = locator
+		}
+	}
+	if latest == nil {
+		return nil, trace.NotFound("no version of %v/%v satisfying %q found",
+			dep.Locator.Repository, dep.Locator.Name, dep.Constraint)
+	}
+	return latest, nil
+}
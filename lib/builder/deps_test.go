@@ -0,0 +1,117 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/localenv"
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/pack/localpack"
+	"github.com/gravitational/gravity/lib/schema"
+	"github.com/gravitational/gravity/lib/testutils"
+
+	"github.com/gravitational/trace"
+	check "gopkg.in/check.v1"
+)
+
+type DepsSuite struct{}
+
+var _ = check.Suite(&DepsSuite{})
+
+func (s *DepsSuite) TestResolvesLatestMatchingVersion(c *check.C) {
+	cache := newPackages(c, "gravitational.io/app:1.4.1", "gravitational.io/other:2.0.0")
+	remote := newPackages(c, "gravitational.io/app:1.4.3", "gravitational.io/app:1.5.0",
+		"gravitational.io/other:2.1.0", "gravitational.io/other:3.0.0")
+
+	lock, err := resolveDependencies([]schema.Dependency{
+		mustParseDependency(c, "gravitational.io/other:>=2.0 <3.0"),
+		mustParseDependency(c, "gravitational.io/app:~1.4"),
+	}, nil, []pack.PackageService{cache, remote})
+	c.Assert(err, check.IsNil)
+	c.Assert(lock, check.DeepEquals, &DependencyLock{
+		Dependencies: []LockedDependency{
+			{Dependency: "gravitational.io/app:~1.4", Resolved: "gravitational.io/app:1.4.3"},
+			{Dependency: "gravitational.io/other:>=2.0 <3.0", Resolved: "gravitational.io/other:2.1.0"},
+		},
+	})
+}
+
+func (s *DepsSuite) TestOverrideTakesPrecedence(c *check.C) {
+	cache := newPackages(c, "gravitational.io/app:1.4.1")
+
+	lock, err := resolveDependencies([]schema.Dependency{
+		mustParseDependency(c, "gravitational.io/app:~1.4"),
+	}, []loc.Locator{loc.MustParseLocator("gravitational.io/app:1.4.0")},
+		[]pack.PackageService{cache})
+	c.Assert(err, check.IsNil)
+	c.Assert(lock.Dependencies, check.DeepEquals, []LockedDependency{
+		{Dependency: "gravitational.io/app:~1.4", Resolved: "gravitational.io/app:1.4.0"},
+	})
+}
+
+func (s *DepsSuite) TestFailsWithoutMatchingVersion(c *check.C) {
+	cache := newPackages(c, "gravitational.io/app:1.3.0")
+
+	_, err := resolveDependencies([]schema.Dependency{
+		mustParseDependency(c, "gravitational.io/app:~1.4"),
+	}, nil, []pack.PackageService{cache})
+	c.Assert(trace.IsNotFound(err), check.Equals, true, check.Commentf("%v", err))
+}
+
+func (s *DepsSuite) TestRequiresListingRepository(c *check.C) {
+	b := &Builder{
+		Config: Config{
+			NewSyncer: func(*Builder) (Syncer, error) {
+				return &nonListingSyncer{}, nil
+			},
+		},
+		Env: &localenv.LocalEnvironment{
+			Packages: newPackages(c, "gravitational.io/app:1.4.1"),
+		},
+		Manifest: schema.Manifest{
+			Dependencies: schema.Dependencies{
+				Apps: []schema.Dependency{mustParseDependency(c, "gravitational.io/app:~1.4")},
+			},
+		},
+	}
+	err := b.ResolveDependencies()
+	c.Assert(trace.IsBadParameter(err), check.Equals, true, check.Commentf("%v", err))
+}
+
+// nonListingSyncer is a syncer that can not list packages
+type nonListingSyncer struct {
+	Syncer
+}
+
+func mustParseDependency(c *check.C, s string) schema.Dependency {
+	dep, err := schema.ParseDependency(s)
+	c.Assert(err, check.IsNil)
+	return *dep
+}
+
+func newPackages(c *check.C, locators ...string) *localpack.PackageServer {
+	_, packages := testutils.NewPackageService(c)
+	c.Assert(packages.UpsertRepository("gravitational.io", time.Time{}), check.IsNil)
+	for _, locator := range locators {
+		_, err := packages.CreatePackage(loc.MustParseLocator(locator), bytes.NewBufferString(locator))
+		c.Assert(err, check.IsNil)
+	}
+	return packages
+}
@@ -129,6 +129,11 @@ func NewPackSyncer(pack pack.PackageService, apps app.Applications, repo string)
 	}
 }
 
+// PackageService returns the package service the syncer pulls packages from
+func (s *packSyncer) PackageService() pack.PackageService {
+	return s.pack
+}
+
 // Sync pulls dependencies from the package/app service not available locally
 func (s *packSyncer) Sync(builder *Builder, runtimeVersion *semver.Version) error {
 	cacheApps, err := builder.Env.AppServiceLocal(localenv.AppConfig{})
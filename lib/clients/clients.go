@@ -32,10 +32,13 @@ import (
 	"github.com/gravitational/gravity/lib/httplib"
 	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/ops/opsclient"
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/pack/webpack"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/users"
 	"github.com/gravitational/license/authority"
 
+	"github.com/gravitational/roundtrip"
 	"github.com/gravitational/teleport/lib/reversetunnel"
 	"github.com/gravitational/trace"
 	"github.com/gravitational/ttlmap"
@@ -59,6 +62,8 @@ type ClusterClients struct {
 	opsClients *ttlmap.TTLMap
 	// appsClients is remote app services cache
 	appsClients *ttlmap.TTLMap
+	// packsClients is remote package services cache
+	packsClients *ttlmap.TTLMap
 	// kubeClients is remote Kubernetes clients cache
 	kubeClients *ttlmap.TTLMap
 }
@@ -79,6 +84,10 @@ func NewClusterClients(conf ClusterClientsConfig) (*ClusterClients, error) {
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	packsClients, err := ttlmap.New(defaults.ClientCacheSize)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
 	kubeClients, err := ttlmap.New(defaults.ClientCacheSize)
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -87,6 +96,7 @@ func NewClusterClients(conf ClusterClientsConfig) (*ClusterClients, error) {
 		ClusterClientsConfig: conf,
 		opsClients:           opsClients,
 		appsClients:          appsClients,
+		packsClients:         packsClients,
 		kubeClients:          kubeClients,
 	}, nil
 }
@@ -109,6 +119,15 @@ func (r *ClusterClients) AppsClient(clusterName string) (app.Applications, error
 	return r.newAppsClient(clusterName)
 }
 
+// PacksClient returns remote package service for the specified cluster
+func (r *ClusterClients) PacksClient(clusterName string) (pack.PackageService, error) {
+	client := r.getPacksClient(clusterName)
+	if client != nil {
+		return client, nil
+	}
+	return r.newPacksClient(clusterName)
+}
+
 // KubeClient returns Kubernetes API client for the specified cluster and user
 func (r *ClusterClients) KubeClient(operator ops.Operator, user ops.UserInfo, clusterName string) (*kubernetes.Clientset, error) {
 	client := r.getKubeClient(clusterName, user)
@@ -138,6 +157,16 @@ func (r *ClusterClients) getAppsClient(clusterName string) app.Applications {
 	return nil
 }
 
+func (r *ClusterClients) getPacksClient(clusterName string) pack.PackageService {
+	r.Lock()
+	defer r.Unlock()
+	clientI, ok := r.packsClients.Get(clusterName)
+	if ok {
+		return clientI.(pack.PackageService)
+	}
+	return nil
+}
+
 func (r *ClusterClients) getKubeClient(clusterName string, user ops.UserInfo) *kubernetes.Clientset {
 	r.Lock()
 	defer r.Unlock()
@@ -196,6 +225,30 @@ func (r *ClusterClients) newAppsClient(clusterName string) (app.Applications, er
 	return client, nil
 }
 
+func (r *ClusterClients) newPacksClient(clusterName string) (pack.PackageService, error) {
+	info, err := r.clientInfo(clusterName)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	clientI, ok := r.packsClients.Get(clusterName)
+	if ok {
+		return clientI.(pack.PackageService), nil
+	}
+
+	client, err := webpack.NewAuthenticatedClient(
+		info.url.String(), info.key.UserEmail, info.key.Token, roundtrip.HTTPClient(info.httpClient))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	r.packsClients.Set(clusterName, client, defaults.ClientCacheTTL)
+	return client, nil
+}
+
 func (r *ClusterClients) newKubeClient(operator ops.Operator, user ops.UserInfo, clusterName string) (*kubernetes.Clientset, error) {
 	remoteCluster, err := r.Tunnel.GetSite(clusterName)
 	if err != nil {
@@ -207,6 +207,10 @@ const (
 	// BlockingOperationEnvVar specifies whether to wait for operation to complete
 	BlockingOperationEnvVar = "GRAVITY_BLOCKING_OPERATION"
 
+	// ProgressFDEnvVar names the environment variable that specifies the file descriptor
+	// a command executed by the RPC agent can use to report structured progress
+	ProgressFDEnvVar = "GRAVITY_PROGRESS_FD"
+
 	// DockerRegistry is a default name for private docker registry
 	DockerRegistry = "leader.telekube.local:5000"
 
@@ -576,6 +580,10 @@ const (
 	// RPCAgentSyncPlanFunction requests deployed agents to synchronize local backend with cluster
 	RPCAgentSyncPlanFunction = "sync-plan"
 
+	// RPCAgentUpdateConfigFunction requests deployed agents to run automatic
+	// cluster configuration update operation on leader node
+	RPCAgentUpdateConfigFunction = "update-config"
+
 	// TelekubeMountDir is a directory where telekube mounts specific secrets
 	// and other configuration parameters
 	TelekubeMountDir = "/var/lib/telekube"
@@ -692,6 +700,26 @@ const (
 	//
 	// Used in audit events.
 	ServiceStatusChecker = "@statuschecker"
+	// ServiceBackupScheduler is the name of the service that periodically
+	// takes cluster backups according to the configured schedules.
+	//
+	// Used in audit events.
+	ServiceBackupScheduler = "@backupscheduler"
+	// ServiceBLOBScrubber is the name of the service that periodically
+	// verifies the integrity of the stored packages.
+	//
+	// Used in audit events.
+	ServiceBLOBScrubber = "@blobscrubber"
+	// ServicePackageGC is the name of the service that periodically
+	// removes unused packages from the Ops Center package storage.
+	//
+	// Used in audit events.
+	ServicePackageGC = "@packagegc"
+	// ServiceFleetExecutor is the name of the service that rolls out
+	// fleet operations across the clusters connected to the Ops Center.
+	//
+	// Used in audit events.
+	ServiceFleetExecutor = "@fleetexecutor"
 )
 
 var (
@@ -236,6 +236,10 @@ const (
 	// ProfilingInterval defines the frequency of taking state snapshots (debugging)
 	ProfilingInterval = 1 * time.Minute
 
+	// MetricsRefreshInterval is how often the cluster controller recomputes
+	// the metrics that require walking the whole backend
+	MetricsRefreshInterval = 1 * time.Minute
+
 	// HumanReasonableTimeout is amount of time certain command can run without producing any output
 	HumanReasonableTimeout = 3 * time.Second
 
@@ -367,6 +371,10 @@ const (
 	// PackagesDir is the place where we put all local packages
 	PackagesDir = "packages"
 
+	// DownloadsDir is the packages subdirectory with partially
+	// downloaded packages
+	DownloadsDir = "downloads"
+
 	// UpdateDir is the gravity subdirectory where update related data is stored
 	UpdateDir = "update"
 
@@ -486,6 +494,34 @@ const (
 	// MissedHeartbeats is the amount of missed heartbeats that will be considered as node failure
 	MissedHeartbeats = 30
 
+	// BLOBScrubInterval is the default interval between verifications
+	// of the integrity of the stored packages
+	BLOBScrubInterval = 7 * 24 * time.Hour
+
+	// BLOBScrubCheckInterval is how often the package service checks
+	// whether the stored packages are due for verification
+	BLOBScrubCheckInterval = 10 * time.Minute
+
+	// BLOBQuarantineRetention is how long the corrupted packages are kept
+	// in the quarantine directory before they are removed
+	BLOBQuarantineRetention = 30 * 24 * time.Hour
+
+	// BLOBScrubReportRetention is how long the verification report of
+	// a package service peer is kept after the peer has stopped sending heartbeats
+	BLOBScrubReportRetention = 7 * 24 * time.Hour
+
+	// PackageGCKeepVersions is the default number of the latest versions
+	// of each application retained in the Ops Center package storage
+	PackageGCKeepVersions = 3
+
+	// PackageGCInterval is the default interval between Ops Center
+	// package storage garbage collections
+	PackageGCInterval = 24 * time.Hour
+
+	// OrphanedBLOBGracePeriod is how long a BLOB not referenced by any package
+	// is kept before removal so the data of packages being uploaded is not removed
+	OrphanedBLOBGracePeriod = time.Hour
+
 	// GracePeriod is a period for GC not to delete undetected files
 	// to prevent accidental deletion
 	GracePeriod = 24 * time.Hour
@@ -529,6 +565,13 @@ const (
 	// InfluxDBAdminPassword is the InfluxDB admin user password
 	InfluxDBAdminPassword = "root"
 
+	// PrometheusServiceName is the name of Prometheus service
+	PrometheusServiceName = "prometheus-k8s"
+	// PrometheusName is the name of the Prometheus resource managed by Prometheus operator
+	PrometheusName = "k8s"
+	// AlertmanagerConfigSecret is the name of the secret with Alertmanager configuration
+	AlertmanagerConfigSecret = "alertmanager-main"
+
 	// WriteFactor is a default amount of acknowledged writes for object storage
 	// to be considered successfull
 	WriteFactor = 1
@@ -549,6 +592,10 @@ const (
 	// ManifestFileName is the name of the application manifest
 	ManifestFileName = "app.yaml"
 
+	// DependencyLockFileName is the name of the file that records versions
+	// the manifest dependency constraints were resolved to during build
+	DependencyLockFileName = "app.lock"
+
 	// RegistryDir is the name of the layers directory inside an application tarball
 	RegistryDir = "registry"
 
@@ -570,6 +617,22 @@ const (
 	// AppSyncInterval is how often app images are synced with the local registry
 	AppSyncInterval = 30 * time.Second
 
+	// BackupScheduleCheckInterval is how often the cluster controller checks
+	// whether a scheduled backup is due
+	BackupScheduleCheckInterval = 1 * time.Minute
+	// BackupRetention is the default number of scheduled backups to keep
+	BackupRetention = 7
+	// BackupsDir is the directory in the cluster state directory where
+	// scheduled backup hooks place their output
+	BackupsDir = "backups"
+
+	// FleetOperationCheckInterval is how often the Ops Center picks up
+	// fleet operations that are not being executed
+	FleetOperationCheckInterval = 1 * time.Minute
+	// FleetOperationPollInterval is how often the Ops Center polls the
+	// state of cluster operations started by a fleet operation
+	FleetOperationPollInterval = 10 * time.Second
+
 	// KubeSystemNamespace is the name of k8s namespace where all our system stuff goes
 	KubeSystemNamespace = "kube-system"
 	// MonitoringNamespace is the name of k8s namespace for the monitoring-related resources
@@ -867,6 +930,10 @@ const (
 	// RPCAgentSecretsPackage specifies the name of the RPC credentials package
 	RPCAgentSecretsPackage = "rpcagent-secrets"
 
+	// RPCAgentFileChunkSize specifies the size of a single chunk of data
+	// when transferring files to or from RPC agents
+	RPCAgentFileChunkSize = 64 * 1024
+
 	// ArchiveUID specifies the user ID to use for tarball items that do not exist on disk
 	ArchiveUID = 1000
 
@@ -876,6 +943,14 @@ const (
 	// EndpointsWaitTimeout specifies the timeout for waiting for system service endpoints
 	EndpointsWaitTimeout = 5 * time.Minute
 
+	// HealthGateTimeout specifies the maximum time to wait for the cluster
+	// to become healthy after a batch of nodes has been updated
+	HealthGateTimeout = 5 * time.Minute
+
+	// MaintenanceWindowCheckInterval specifies how often an operation queued
+	// until the maintenance window opens checks the window
+	MaintenanceWindowCheckInterval = 1 * time.Minute
+
 	// DrainErrorTimeout specifies the timeout for the initial failures of drain operation.
 	// Drain operation might experience transient errors (e.g. api server connect failures)
 	// in which case the timeout defines the maximum time frame to retry such failed attempts.
@@ -886,6 +961,11 @@ const (
 	// DrainTimeout defines the total drain operation timeout
 	DrainTimeout = 1 * time.Hour
 
+	// DrainBlockedTimeout is how long a drain retries pod evictions blocked
+	// by pod disruption budgets before taking the action configured by the
+	// drain policy
+	DrainBlockedTimeout = 10 * time.Minute
+
 	// TerminationWaitTimeout defines an amount of time above the Kubernetes
 	// TerminationGracePeriod to wait for a pod to be terminated. Kubernetes
 	// may take some amount of time to force kill a pod, which we want to
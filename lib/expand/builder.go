@@ -272,7 +272,7 @@ func (b *planBuilder) AddElectPhase(plan *storage.OperationPlan) {
 	plan.Phases = append(plan.Phases, phase)
 }
 
-func (p *Peer) getPlanBuilder(ctx operationContext) (*planBuilder, error) {
+func (p *Peer) getPlanBuilder(ctx operationContext, joiningNode storage.Server) (*planBuilder, error) {
 	application, err := ctx.Apps.GetApp(ctx.Cluster.App.Package)
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -310,20 +310,12 @@ func (p *Peer) getPlanBuilder(ctx operationContext) (*planBuilder, error) {
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
-	operation, err := ctx.Operator.GetSiteOperation(ctx.Operation.Key())
-	if err != nil {
-		return nil, trace.Wrap(err)
-	}
-	if len(operation.Servers) == 0 {
-		return nil, trace.NotFound("operation does not have servers: %v",
-			operation)
-	}
 	return &planBuilder{
 		Application:     *application,
 		Runtime:         *runtime,
 		TeleportPackage: *teleportPackage,
 		PlanetPackage:   *planetPackage,
-		JoiningNode:     operation.Servers[0],
+		JoiningNode:     joiningNode,
 		ClusterNodes:    storage.Servers(ctx.Cluster.ClusterState.Servers),
 		Peer:            ctx.Peer,
 		Master:          storage.Servers(ctx.Cluster.ClusterState.Servers).Masters()[0],
@@ -175,47 +175,59 @@ func formatClusterURL(addr string) string {
 }
 
 func (p *Peer) dialSite(addr string) (*operationContext, error) {
-	targetURL := formatClusterURL(addr)
-	httpClient := httplib.GetClient(true)
-	operator, err := opsclient.NewBearerClient(targetURL, p.Token, opsclient.HTTPClient(httpClient))
+	ctx, err := p.dialCluster(addr)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
-
-	packages, err := webpack.NewBearerClient(targetURL, p.Token, roundtrip.HTTPClient(httpClient))
+	installOp, _, err := ops.GetInstallOperation(ctx.Cluster.Key(), ctx.Operator)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
-	apps, err := client.NewBearerClient(targetURL, p.Token, client.HTTPClient(httpClient))
+	err = p.runLocalChecks(ctx.Cluster, *installOp)
 	if err != nil {
-		return nil, trace.Wrap(err)
+		return nil, utils.Abort(err) // stop retrying on failed checks
+	}
+	var operation *ops.SiteOperation
+	if p.OperationID == "" {
+		operation, err = p.createExpandOperation(ctx.Operator, ctx.Cluster)
+	} else {
+		operation, err = p.getExpandOperation(ctx.Operator, ctx.Cluster)
 	}
-	cluster, err := operator.GetLocalSite()
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
-	err = p.checkAndSetServerProfile(cluster.App)
+	creds, err := install.LoadRPCCredentials(p.Context, ctx.Packages, p.FieldLogger)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
-	installOp, _, err := ops.GetInstallOperation(cluster.Key(), operator)
+	ctx.Operation = *operation
+	ctx.Creds = *creds
+	return ctx, nil
+}
+
+// dialCluster connects to the cluster at the specified address and returns
+// the operation context without an operation
+func (p *Peer) dialCluster(addr string) (*operationContext, error) {
+	targetURL := formatClusterURL(addr)
+	httpClient := httplib.GetClient(true)
+	operator, err := opsclient.NewBearerClient(targetURL, p.Token, opsclient.HTTPClient(httpClient))
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
-	err = p.runLocalChecks(*cluster, *installOp)
+
+	packages, err := webpack.NewBearerClient(targetURL, p.Token, roundtrip.HTTPClient(httpClient))
 	if err != nil {
-		return nil, utils.Abort(err) // stop retrying on failed checks
+		return nil, trace.Wrap(err)
 	}
-	var operation *ops.SiteOperation
-	if p.OperationID == "" {
-		operation, err = p.createExpandOperation(operator, *cluster)
-	} else {
-		operation, err = p.getExpandOperation(operator, *cluster)
+	apps, err := client.NewBearerClient(targetURL, p.Token, client.HTTPClient(httpClient))
+	if err != nil {
+		return nil, trace.Wrap(err)
 	}
+	cluster, err := operator.GetLocalSite()
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
-	creds, err := install.LoadRPCCredentials(p.Context, packages, p.FieldLogger)
+	err = p.checkAndSetServerProfile(cluster.App)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -224,13 +236,11 @@ func (p *Peer) dialSite(addr string) (*operationContext, error) {
 		return nil, trace.Wrap(err)
 	}
 	return &operationContext{
-		Operator:  operator,
-		Packages:  packages,
-		Apps:      apps,
-		Peer:      peerURL.Host,
-		Operation: *operation,
-		Cluster:   *cluster,
-		Creds:     *creds,
+		Operator: operator,
+		Packages: packages,
+		Apps:     apps,
+		Peer:     peerURL.Host,
+		Cluster:  *cluster,
 	}, nil
 }
 
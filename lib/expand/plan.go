@@ -17,8 +17,15 @@ limitations under the License.
 package expand
 
 import (
+	"os"
+	"time"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/fsm"
+	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/schema"
 	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/utils"
 
 	"github.com/gravitational/trace"
 )
@@ -31,7 +38,15 @@ func (p *Peer) initOperationPlan(ctx operationContext) error {
 	if plan != nil {
 		return trace.AlreadyExists("plan is already initialized")
 	}
-	plan, err = p.getOperationPlan(ctx)
+	operation, err := ctx.Operator.GetSiteOperation(ctx.Operation.Key())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if len(operation.Servers) == 0 {
+		return trace.NotFound("operation does not have servers: %v",
+			operation)
+	}
+	plan, err = p.getOperationPlan(ctx, operation.Servers[0])
 	if err != nil {
 		return trace.Wrap(err)
 	}
@@ -43,8 +58,122 @@ func (p *Peer) initOperationPlan(ctx operationContext) error {
 	return nil
 }
 
-func (p *Peer) getOperationPlan(ctx operationContext) (*storage.OperationPlan, error) {
-	builder, err := p.getPlanBuilder(ctx)
+// DryRun connects to the cluster and generates the plan of the expand
+// operation this peer would execute along with the summary of its impact.
+// No operation is created and no changes are made to either the cluster
+// or this node
+func (p *Peer) DryRun() (plan *storage.OperationPlan, impact *fsm.PlanImpact, err error) {
+	for _, addr := range p.Peers {
+		var ctx *operationContext
+		ctx, err = p.dialCluster(addr)
+		if err != nil {
+			p.Infof("Failed connecting to cluster at %v: %v.", addr, err)
+			continue
+		}
+		plan, err = p.getDryRunPlan(*ctx)
+		if err != nil {
+			return nil, nil, trace.Wrap(err)
+		}
+		return plan, GetPlanImpact(*plan), nil
+	}
+	return nil, nil, trace.Wrap(err)
+}
+
+// getDryRunPlan generates the expand operation plan for this peer
+// using an operation that only exists in memory
+func (p *Peer) getDryRunPlan(ctx operationContext) (*storage.OperationPlan, error) {
+	joiningNode, err := p.newJoiningNode(ctx.Cluster)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	ctx.Operation = ops.SiteOperation{
+		AccountID:   ctx.Cluster.AccountID,
+		SiteDomain:  ctx.Cluster.Domain,
+		Type:        ops.OperationExpand,
+		Provisioner: schema.ProvisionerOnPrem,
+		Servers:     []storage.Server{*joiningNode},
+	}
+	plan, err := p.getOperationPlan(ctx, *joiningNode)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return plan, nil
+}
+
+// GetPlanImpact returns the summary of changes the specified expand
+// operation plan makes to the cluster
+func GetPlanImpact(plan storage.OperationPlan) *fsm.PlanImpact {
+	var impact fsm.PlanImpact
+	for _, phase := range fsm.FlattenPlan(&plan) {
+		if phase.Data == nil || phase.Data.Package == nil {
+			continue
+		}
+		var hook schema.HookType
+		switch phase.ID {
+		case PreHookPhase:
+			hook = schema.HookNodeAdding
+		case PostHookPhase:
+			hook = schema.HookNodeAdded
+		default:
+			continue
+		}
+		impact.Hooks = append(impact.Hooks, fsm.PlanHook{
+			Phase:   phase.ID,
+			Package: *phase.Data.Package,
+			Hook:    hook,
+		})
+	}
+	return &impact
+}
+
+// newJoiningNode returns the server record for this peer as it would be
+// registered with the expand operation once its agent has joined
+func (p *Peer) newJoiningNode(cluster ops.Site) (*storage.Server, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	profile, err := cluster.App.Manifest.NodeProfiles.ByName(p.Role)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var mounts []storage.Mount
+	for _, mount := range p.Mounts {
+		mounts = append(mounts, storage.Mount{Name: mount.Name, Source: mount.Source})
+	}
+	advertiseIP, _ := utils.SplitHostPort(p.AdvertiseAddr, "")
+	server := storage.Server{
+		AdvertiseIP: advertiseIP,
+		Hostname:    hostname,
+		Role:        p.Role,
+		Mounts:      mounts,
+		Provisioner: schema.ProvisionerOnPrem,
+		Created:     time.Now().UTC(),
+	}
+	if p.CloudMetadata != nil {
+		server.Nodename = p.CloudMetadata.NodeName
+		server.InstanceType = p.CloudMetadata.InstanceType
+		server.InstanceID = p.CloudMetadata.InstanceId
+	}
+	switch profile.ServiceRole {
+	case schema.ServiceRoleMaster, "":
+		masters := storage.Servers(cluster.ClusterState.Servers).Masters()
+		if len(masters) < defaults.MaxMasterNodes {
+			server.ClusterRole = string(schema.ServiceRoleMaster)
+		} else {
+			server.ClusterRole = string(schema.ServiceRoleNode)
+		}
+	case schema.ServiceRoleNode:
+		server.ClusterRole = string(schema.ServiceRoleNode)
+	default:
+		return nil, trace.BadParameter("unknown cluster role %q for node profile %q",
+			profile.ServiceRole, p.Role)
+	}
+	return &server, nil
+}
+
+func (p *Peer) getOperationPlan(ctx operationContext, joiningNode storage.Server) (*storage.OperationPlan, error) {
+	builder, err := p.getPlanBuilder(ctx, joiningNode)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
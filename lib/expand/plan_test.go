@@ -24,6 +24,7 @@ import (
 
 	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/fsm"
 	installphases "github.com/gravitational/gravity/lib/install/phases"
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/ops"
@@ -225,6 +226,40 @@ func (s *PlanSuite) TestPlan(c *check.C) {
 	}
 }
 
+func (s *PlanSuite) TestDryRunPlan(c *check.C) {
+	peer := &Peer{
+		PeerConfig: PeerConfig{
+			AdvertiseAddr: "10.10.0.3",
+			FieldLogger:   logrus.WithField(trace.Component, "join-suite"),
+			RuntimeConfig: proto.RuntimeConfig{
+				Role: s.joiningNode.Role,
+			},
+		},
+	}
+	plan, err := peer.getDryRunPlan(operationContext{
+		Operator: s.services.Operator,
+		Packages: s.services.Packages,
+		Apps:     s.services.Apps,
+		Peer:     fmt.Sprintf("%v:%v", s.masterNode.AdvertiseIP, defaults.GravitySiteNodePort),
+		Cluster:  *s.cluster,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(plan.OperationType, check.Equals, ops.OperationExpand)
+	c.Assert(plan.OperationID, check.Equals, "")
+
+	elect := plan.Phases[len(plan.Phases)-1]
+	c.Assert(elect.ID, check.Equals, ElectPhase)
+	c.Assert(elect.Data.Server.AdvertiseIP, check.Equals, "10.10.0.3")
+	c.Assert(elect.Data.Server.ClusterRole, check.Equals, string(schema.ServiceRoleMaster))
+
+	c.Assert(GetPlanImpact(*plan), check.DeepEquals, &fsm.PlanImpact{
+		Hooks: []fsm.PlanHook{
+			{Phase: PreHookPhase, Package: s.appPackage, Hook: schema.HookNodeAdding},
+			{Phase: PostHookPhase, Package: s.appPackage, Hook: schema.HookNodeAdded},
+		},
+	})
+}
+
 func (s *PlanSuite) verifyConfigurePhase(c *check.C, phase storage.OperationPhase) {
 	storage.DeepComparePhases(c, storage.OperationPhase{
 		ID: installphases.ConfigurePhase,
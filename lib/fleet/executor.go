@@ -0,0 +1,524 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleet
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gravitational/gravity/lib/app"
+	appservice "github.com/gravitational/gravity/lib/app/service"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/pack/signing"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus"
+)
+
+// Config defines the fleet executor configuration
+type Config struct {
+	// Backend is the Ops Center backend that stores fleet operations
+	Backend storage.Backend
+	// Operator is the Ops Center operator that routes requests
+	// to the remote clusters
+	Operator ops.Operator
+	// Packages is the Ops Center package service
+	Packages pack.PackageService
+	// Apps is the Ops Center application service
+	Apps app.Applications
+	// Clients provides access to the services of the remote clusters.
+	// If set, the update application is pushed into the clusters
+	// that do not have it before starting the update
+	Clients Clients
+	// Clock is used to schedule checks, can be overridden in tests
+	Clock clockwork.Clock
+	// CheckInterval is how often the executor picks up fleet operations
+	// that are not being executed
+	CheckInterval time.Duration
+	// PollInterval is how often the state of cluster operations is polled
+	PollInterval time.Duration
+	// FieldLogger is used for logging
+	logrus.FieldLogger
+}
+
+// Clients provides access to the services of the remote clusters
+type Clients interface {
+	// AppsClient returns the application service of the specified cluster
+	AppsClient(clusterName string) (app.Applications, error)
+	// PacksClient returns the package service of the specified cluster
+	PacksClient(clusterName string) (pack.PackageService, error)
+}
+
+// CheckAndSetDefaults validates the config and sets defaults
+func (c *Config) CheckAndSetDefaults() error {
+	if c.Backend == nil {
+		return trace.BadParameter("missing Backend")
+	}
+	if c.Operator == nil {
+		return trace.BadParameter("missing Operator")
+	}
+	if c.Clients != nil && (c.Packages == nil || c.Apps == nil) {
+		return trace.BadParameter("Clients require Packages and Apps")
+	}
+	if c.Clock == nil {
+		c.Clock = clockwork.NewRealClock()
+	}
+	if c.CheckInterval == 0 {
+		c.CheckInterval = defaults.FleetOperationCheckInterval
+	}
+	if c.PollInterval == 0 {
+		c.PollInterval = defaults.FleetOperationPollInterval
+	}
+	if c.FieldLogger == nil {
+		c.FieldLogger = logrus.WithField(trace.Component, "fleet")
+	}
+	return nil
+}
+
+// Executor executes fleet operations created in the Ops Center
+type Executor struct {
+	Config
+	// mu guards running
+	mu sync.Mutex
+	// running is the set of IDs of the fleet operations being executed
+	running map[string]struct{}
+	// wg tracks the fleet operations being executed
+	wg sync.WaitGroup
+}
+
+// New returns a new fleet executor
+func New(config Config) (*Executor, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &Executor{
+		Config:  config,
+		running: make(map[string]struct{}),
+	}, nil
+}
+
+// Run periodically picks up fleet operations that have not finished and
+// executes them until the context is cancelled.
+// Operations interrupted by a restart are resumed from their recorded state
+func (r *Executor) Run(ctx context.Context) error {
+	r.Info("Starting fleet executor.")
+	for {
+		if err := r.check(ctx); err != nil {
+			r.Warnf("Failed to check fleet operations: %v.", trace.DebugReport(err))
+		}
+		select {
+		case <-r.Clock.After(r.CheckInterval):
+		case <-ctx.Done():
+			r.Info("Stopping fleet executor.")
+			r.wg.Wait()
+			return nil
+		}
+	}
+}
+
+// check starts executing fleet operations that have not finished
+func (r *Executor) check(ctx context.Context) error {
+	accounts, err := r.Backend.GetAccounts()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	for _, account := range accounts {
+		operations, err := r.Backend.GetFleetOperations(account.ID)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		for _, operation := range operations {
+			if !IsFinished(operation) {
+				r.start(ctx, operation)
+			}
+		}
+	}
+	return nil
+}
+
+// start executes the specified fleet operation in the background
+// unless it is already being executed
+func (r *Executor) start(ctx context.Context, operation storage.FleetOperation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.running[operation.ID]; ok {
+		return
+	}
+	r.running[operation.ID] = struct{}{}
+	r.wg.Add(1)
+	go func() {
+		defer func() {
+			r.mu.Lock()
+			delete(r.running, operation.ID)
+			r.mu.Unlock()
+			r.wg.Done()
+		}()
+		logger := r.WithField("operation", operation.ID)
+		logger.Info("Executing fleet operation.")
+		if err := r.Execute(ctx, operation); err != nil {
+			logger.Warnf("Failed to execute fleet operation: %v.", trace.DebugReport(err))
+		}
+	}()
+}
+
+// Execute runs the fleet operation until all its waves have finished
+// or the context is cancelled.
+//
+// Waves are executed one after another. Within a wave, at most the
+// configured number of clusters are operated on at the same time.
+// If the operation stops on failure, no new cluster operations are started
+// after a cluster operation has failed and the remaining clusters are skipped
+func (r *Executor) Execute(ctx context.Context, operation storage.FleetOperation) error {
+	tracker := newTracker(operation, r.Backend)
+	if err := tracker.setState(ops.FleetStateInProgress, ""); err != nil {
+		return trace.Wrap(err)
+	}
+	for wave := 0; wave < tracker.waves(); wave++ {
+		if operation.StopOnFailure && tracker.failed() {
+			break
+		}
+		r.executeWave(ctx, operation, tracker, wave)
+		if ctx.Err() != nil {
+			// the operation is resumed the next time the executor runs
+			return trace.Wrap(ctx.Err())
+		}
+	}
+	return trace.Wrap(tracker.complete())
+}
+
+// executeWave executes cluster operations of the specified wave
+func (r *Executor) executeWave(ctx context.Context, operation storage.FleetOperation, tracker *tracker, wave int) {
+	concurrency := operation.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, cluster := range tracker.clusters(wave) {
+		if isClusterFinished(cluster) {
+			continue
+		}
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		// operations that have already been started are still followed
+		if cluster.State == ops.FleetClusterPending && operation.StopOnFailure && tracker.failed() {
+			<-semaphore
+			continue
+		}
+		wg.Add(1)
+		go func(cluster storage.FleetCluster) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			r.executeCluster(ctx, operation, tracker, cluster)
+		}(cluster)
+	}
+	wg.Wait()
+}
+
+// executeCluster starts the operation in the specified cluster unless it
+// has already been started and waits for it to finish
+func (r *Executor) executeCluster(ctx context.Context, operation storage.FleetOperation, tracker *tracker, cluster storage.FleetCluster) {
+	logger := r.WithFields(logrus.Fields{
+		"operation": operation.ID,
+		"cluster":   cluster.Name,
+	})
+	if cluster.OperationID == "" {
+		if cluster.State == ops.FleetClusterInProgress {
+			// the executor was interrupted before the cluster operation was recorded
+			// so it is unknown whether the operation has been created
+			tracker.setCluster(logger, cluster.Name, ops.FleetClusterFailed, "",
+				"interrupted while starting the cluster operation")
+			return
+		}
+		tracker.setCluster(logger, cluster.Name, ops.FleetClusterInProgress, "", "")
+		key, err := r.startOperation(ctx, operation, cluster.Name)
+		if key != nil {
+			cluster.OperationID = key.OperationID
+		}
+		if err != nil {
+			logger.Warnf("Failed to start cluster operation: %v.", trace.DebugReport(err))
+			tracker.setCluster(logger, cluster.Name, ops.FleetClusterFailed,
+				cluster.OperationID, trace.UserMessage(err))
+			return
+		}
+		tracker.setCluster(logger, cluster.Name, ops.FleetClusterInProgress, cluster.OperationID, "")
+	}
+	err := r.waitForOperation(ctx, ops.SiteOperationKey{
+		AccountID:   operation.AccountID,
+		SiteDomain:  cluster.Name,
+		OperationID: cluster.OperationID,
+	})
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		tracker.setCluster(logger, cluster.Name, ops.FleetClusterFailed,
+			cluster.OperationID, trace.UserMessage(err))
+		return
+	}
+	tracker.setCluster(logger, cluster.Name, ops.FleetClusterCompleted, cluster.OperationID, "")
+}
+
+// startOperation creates the operation in the specified cluster.
+// The operation is executed by the cluster automatically
+func (r *Executor) startOperation(ctx context.Context, operation storage.FleetOperation, clusterName string) (*ops.SiteOperationKey, error) {
+	switch operation.Type {
+	case ops.FleetOperationUpdate:
+		if err := r.pushApp(operation.AccountID, clusterName, operation.App); err != nil {
+			return nil, trace.Wrap(err, "failed to push %v into the cluster", operation.App)
+		}
+		key, err := r.Operator.CreateSiteAppUpdateOperation(ctx, ops.CreateSiteAppUpdateOperationRequest{
+			AccountID:   operation.AccountID,
+			SiteDomain:  clusterName,
+			App:         operation.App,
+			StartAgents: true,
+		})
+		return key, trace.Wrap(err)
+	case ops.FleetOperationUpdateConfig:
+		key, err := r.Operator.CreateUpdateConfigOperation(ctx, ops.CreateUpdateConfigOperationRequest{
+			ClusterKey: ops.SiteKey{
+				AccountID:  operation.AccountID,
+				SiteDomain: clusterName,
+			},
+			Config:      operation.Config,
+			StartAgents: true,
+		})
+		return key, trace.Wrap(err)
+	}
+	return nil, trace.BadParameter("unsupported fleet operation type %q", operation.Type)
+}
+
+// pushApp pulls the specified application with its dependencies from
+// the Ops Center into the cluster unless the cluster already has it
+func (r *Executor) pushApp(accountID, clusterName, application string) error {
+	if r.Clients == nil {
+		return nil
+	}
+	locator, err := loc.ParseLocator(application)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	apps, err := r.Clients.AppsClient(clusterName)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	_, err = apps.GetApp(*locator)
+	if err == nil {
+		return nil
+	}
+	if !trace.IsNotFound(err) {
+		return trace.Wrap(err)
+	}
+	packages, err := r.Clients.PacksClient(clusterName)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	keys, err := r.Operator.GetTrustedKeys(ops.SiteKey{
+		AccountID:  accountID,
+		SiteDomain: clusterName,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	verifier, err := signing.NewClusterVerifier(keys, clusterName)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	_, err = appservice.PullApp(appservice.AppPullRequest{
+		FieldLogger: r.WithField("cluster", clusterName),
+		SrcPack:     r.Packages,
+		DstPack:     packages,
+		SrcApp:      r.Apps,
+		DstApp:      apps,
+		Package:     *locator,
+		Verifier:    verifier,
+	})
+	if err != nil && !trace.IsAlreadyExists(err) {
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+// waitForOperation polls the specified cluster operation until it finishes.
+// Errors querying the operation are retried as the cluster might be
+// temporarily unreachable while it is being updated
+func (r *Executor) waitForOperation(ctx context.Context, key ops.SiteOperationKey) error {
+	for {
+		operation, err := r.Operator.GetSiteOperation(key)
+		if err != nil {
+			r.WithField("operation", key).Warnf("Failed to query cluster operation: %v.", err)
+		} else if operation.IsCompleted() {
+			return nil
+		} else if operation.IsFailed() {
+			message := "cluster operation failed"
+			progress, err := r.Operator.GetSiteOperationProgress(key)
+			if err == nil && progress.Message != "" {
+				message = progress.Message
+			}
+			return trace.Errorf("%v", message)
+		}
+		select {
+		case <-r.Clock.After(r.PollInterval):
+		case <-ctx.Done():
+			return trace.Wrap(ctx.Err())
+		}
+	}
+}
+
+// tracker keeps the state of a fleet operation and persists its changes
+type tracker struct {
+	// op is the fleet operation. Only the states of the operation
+	// and its clusters are modified after the tracker is created
+	op storage.FleetOperation
+	// mu guards the states of the operation and its clusters
+	mu sync.Mutex
+	// backend persists the operation
+	backend storage.FleetOperations
+}
+
+func newTracker(op storage.FleetOperation, backend storage.FleetOperations) *tracker {
+	op.Clusters = append([]storage.FleetCluster(nil), op.Clusters...)
+	return &tracker{
+		op:      op,
+		backend: backend,
+	}
+}
+
+// setState sets the state of the fleet operation
+func (t *tracker) setState(state, message string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.op.State = state
+	t.op.Message = message
+	return trace.Wrap(t.save())
+}
+
+// setCluster sets the state of the specified cluster.
+// Failure to persist the state is logged as the execution continues
+// with the state kept in memory
+func (t *tracker) setCluster(logger logrus.FieldLogger, name, state, operationID, message string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i := range t.op.Clusters {
+		if t.op.Clusters[i].Name == name {
+			t.op.Clusters[i].State = state
+			t.op.Clusters[i].OperationID = operationID
+			t.op.Clusters[i].Message = message
+		}
+	}
+	logger.WithField("state", state).Info("Cluster state changed.")
+	if err := t.save(); err != nil {
+		logger.Warnf("Failed to save fleet operation: %v.", trace.DebugReport(err))
+	}
+}
+
+// clusters returns the clusters of the specified wave
+func (t *tracker) clusters(wave int) (clusters []storage.FleetCluster) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, cluster := range t.op.Clusters {
+		if cluster.Wave == wave {
+			clusters = append(clusters, cluster)
+		}
+	}
+	return clusters
+}
+
+// waves returns the number of waves
+func (t *tracker) waves() (waves int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, cluster := range t.op.Clusters {
+		if cluster.Wave >= waves {
+			waves = cluster.Wave + 1
+		}
+	}
+	return waves
+}
+
+// failed returns true if any of the cluster operations has failed
+func (t *tracker) failed() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, cluster := range t.op.Clusters {
+		if cluster.State == ops.FleetClusterFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// complete marks the clusters that have not been operated on as skipped
+// and sets the final state of the fleet operation
+func (t *tracker) complete() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var failed, skipped int
+	for i, cluster := range t.op.Clusters {
+		switch cluster.State {
+		case ops.FleetClusterPending:
+			t.op.Clusters[i].State = ops.FleetClusterSkipped
+			t.op.Clusters[i].Message = "skipped after a failed cluster operation"
+			skipped++
+		case ops.FleetClusterFailed:
+			failed++
+		}
+	}
+	if failed == 0 {
+		t.op.State = ops.FleetStateCompleted
+		t.op.Message = ""
+		return trace.Wrap(t.save())
+	}
+	t.op.State = ops.FleetStateFailed
+	t.op.Message = fmt.Sprintf("%v of %v clusters failed", failed, len(t.op.Clusters))
+	if skipped != 0 {
+		t.op.Message = fmt.Sprintf("%v, %v skipped", t.op.Message, skipped)
+	}
+	return trace.Wrap(t.save())
+}
+
+// save persists the fleet operation. Must be called with the lock held
+func (t *tracker) save() error {
+	op, err := t.backend.UpdateFleetOperation(t.op)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	t.op.Updated = op.Updated
+	return nil
+}
+
+func isClusterFinished(cluster storage.FleetCluster) bool {
+	switch cluster.State {
+	case ops.FleetClusterCompleted, ops.FleetClusterFailed, ops.FleetClusterSkipped:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fleet implements operations rolled out by the Ops Center
+// across multiple clusters selected by labels.
+//
+// The selected clusters are split into waves executed one after another.
+// Each cluster in a wave is operated on with a regular cluster operation
+// (an application update or a configuration update) and the fleet operation
+// tracks the state of these operations
+package fleet
+
+import (
+	"sort"
+
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+	"github.com/pborman/uuid"
+)
+
+// NewOperation returns a new fleet operation for the specified request.
+// The clusters matching the request selector are sorted by name
+// and assigned to waves
+func NewOperation(req ops.CreateFleetOperationRequest, clusters []storage.Site) (*storage.FleetOperation, error) {
+	if err := req.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var names []string
+	for _, cluster := range clusters {
+		// the Ops Center cluster itself is not a part of the fleet
+		if cluster.Local || cluster.AccountID != req.AccountID {
+			continue
+		}
+		if !utils.MatchesLabels(cluster.Labels, req.Selector) {
+			continue
+		}
+		names = append(names, cluster.Domain)
+	}
+	if len(names) == 0 {
+		return nil, trace.NotFound("no clusters match selector %v", req.Selector)
+	}
+	sort.Strings(names)
+	waveSize := req.WaveSize
+	if waveSize == 0 {
+		waveSize = len(names)
+	}
+	concurrency := req.Concurrency
+	if concurrency == 0 || concurrency > waveSize {
+		concurrency = waveSize
+	}
+	op := &storage.FleetOperation{
+		ID:            uuid.New(),
+		AccountID:     req.AccountID,
+		Type:          req.Type,
+		State:         ops.FleetStateCreated,
+		Selector:      req.Selector,
+		App:           req.App,
+		Config:        req.Config,
+		WaveSize:      waveSize,
+		Concurrency:   concurrency,
+		StopOnFailure: req.StopOnFailure,
+	}
+	for i, name := range names {
+		op.Clusters = append(op.Clusters, storage.FleetCluster{
+			Name:  name,
+			Wave:  i / waveSize,
+			State: ops.FleetClusterPending,
+		})
+	}
+	return op, nil
+}
+
+// IsFinished returns true if the fleet operation has completed or failed
+func IsFinished(op storage.FleetOperation) bool {
+	return op.State == ops.FleetStateCompleted || op.State == ops.FleetStateFailed
+}
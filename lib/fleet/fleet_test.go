@@ -0,0 +1,313 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleet
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gravitational/gravity/lib/compare"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/storage/keyval"
+
+	"github.com/gravitational/trace"
+	"gopkg.in/check.v1"
+)
+
+func TestFleet(t *testing.T) { check.TestingT(t) }
+
+type FleetSuite struct {
+	backend storage.Backend
+}
+
+var _ = check.Suite(&FleetSuite{})
+
+func (s *FleetSuite) SetUpTest(c *check.C) {
+	var err error
+	s.backend, err = keyval.NewBolt(keyval.BoltConfig{
+		Path: filepath.Join(c.MkDir(), "bolt.db"),
+	})
+	c.Assert(err, check.IsNil)
+}
+
+func (s *FleetSuite) TearDownTest(c *check.C) {
+	if s.backend != nil {
+		s.backend.Close()
+	}
+}
+
+func (s *FleetSuite) TestSelectsClustersIntoWaves(c *check.C) {
+	clusters := []storage.Site{
+		{Domain: "opscenter", AccountID: "account", Local: true,
+			Labels: map[string]string{"env": "prod"}},
+		{Domain: "c", AccountID: "account", Labels: map[string]string{"env": "prod"}},
+		{Domain: "a", AccountID: "account", Labels: map[string]string{"env": "prod", "region": "us"}},
+		{Domain: "d", AccountID: "account", Labels: map[string]string{"env": "staging"}},
+		{Domain: "b", AccountID: "account", Labels: map[string]string{"env": "prod"}},
+		{Domain: "e", AccountID: "other", Labels: map[string]string{"env": "prod"}},
+	}
+	op, err := NewOperation(ops.CreateFleetOperationRequest{
+		AccountID:     "account",
+		Type:          ops.FleetOperationUpdate,
+		Selector:      map[string]string{"env": "prod"},
+		App:           "example.com/app:1.0.0",
+		WaveSize:      2,
+		StopOnFailure: true,
+	}, clusters)
+	c.Assert(err, check.IsNil)
+	c.Assert(op.State, check.Equals, ops.FleetStateCreated)
+	c.Assert(op.Concurrency, check.Equals, 2)
+	compare.DeepCompare(c, op.Clusters, []storage.FleetCluster{
+		{Name: "a", Wave: 0, State: ops.FleetClusterPending},
+		{Name: "b", Wave: 0, State: ops.FleetClusterPending},
+		{Name: "c", Wave: 1, State: ops.FleetClusterPending},
+	})
+
+	op, err = NewOperation(ops.CreateFleetOperationRequest{
+		AccountID: "account",
+		Type:      ops.FleetOperationUpdateConfig,
+		Config:    []byte("{}"),
+	}, clusters)
+	c.Assert(err, check.IsNil)
+	c.Assert(len(op.Clusters), check.Equals, 4)
+	c.Assert(op.WaveSize, check.Equals, 4)
+	c.Assert(op.Clusters[3].Wave, check.Equals, 0)
+
+	_, err = NewOperation(ops.CreateFleetOperationRequest{
+		AccountID: "account",
+		Type:      ops.FleetOperationUpdate,
+		Selector:  map[string]string{"env": "dev"},
+		App:       "example.com/app:1.0.0",
+	}, clusters)
+	c.Assert(trace.IsNotFound(err), check.Equals, true, check.Commentf("%v", err))
+
+	_, err = NewOperation(ops.CreateFleetOperationRequest{
+		AccountID: "account",
+		Type:      ops.FleetOperationUpdate,
+	}, clusters)
+	c.Assert(trace.IsBadParameter(err), check.Equals, true, check.Commentf("%v", err))
+}
+
+func (s *FleetSuite) TestExecutesWaves(c *check.C) {
+	operator := newFakeOperator()
+	op := s.newOperation(c, ops.FleetOperationUpdate, 2, 1, false, "a", "b", "c")
+
+	err := s.newExecutor(c, operator).Execute(context.TODO(), op)
+	c.Assert(err, check.IsNil)
+
+	out, err := s.backend.GetFleetOperation(op.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(out.State, check.Equals, ops.FleetStateCompleted)
+	for _, cluster := range out.Clusters {
+		c.Assert(cluster.State, check.Equals, ops.FleetClusterCompleted)
+		c.Assert(cluster.OperationID, check.Equals, "op-"+cluster.Name)
+	}
+	c.Assert(operator.started, check.DeepEquals, []string{"a", "b", "c"})
+	c.Assert(operator.maxRunning, check.Equals, 1)
+	for _, req := range operator.updates {
+		c.Assert(req.StartAgents, check.Equals, true)
+		c.Assert(req.App, check.Equals, "example.com/app:1.0.0")
+	}
+}
+
+func (s *FleetSuite) TestStopsOnFailure(c *check.C) {
+	operator := newFakeOperator()
+	operator.failed["b"] = true
+	op := s.newOperation(c, ops.FleetOperationUpdateConfig, 2, 2, true, "a", "b", "c", "d")
+
+	err := s.newExecutor(c, operator).Execute(context.TODO(), op)
+	c.Assert(err, check.IsNil)
+
+	out, err := s.backend.GetFleetOperation(op.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(out.State, check.Equals, ops.FleetStateFailed)
+	c.Assert(out.Message, check.Equals, "1 of 4 clusters failed, 2 skipped")
+	var states []string
+	for _, cluster := range out.Clusters {
+		states = append(states, cluster.State)
+	}
+	c.Assert(states, check.DeepEquals, []string{
+		ops.FleetClusterCompleted,
+		ops.FleetClusterFailed,
+		ops.FleetClusterSkipped,
+		ops.FleetClusterSkipped,
+	})
+	c.Assert(out.Clusters[1].Message, check.Equals, "operation on b failed")
+	c.Assert(len(operator.configs), check.Equals, 2)
+	for _, req := range operator.configs {
+		c.Assert(req.StartAgents, check.Equals, true)
+	}
+}
+
+func (s *FleetSuite) TestContinuesAfterFailure(c *check.C) {
+	operator := newFakeOperator()
+	operator.failed["a"] = true
+	op := s.newOperation(c, ops.FleetOperationUpdate, 1, 1, false, "a", "b")
+
+	err := s.newExecutor(c, operator).Execute(context.TODO(), op)
+	c.Assert(err, check.IsNil)
+
+	out, err := s.backend.GetFleetOperation(op.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(out.State, check.Equals, ops.FleetStateFailed)
+	c.Assert(out.Message, check.Equals, "1 of 2 clusters failed")
+	c.Assert(out.Clusters[1].State, check.Equals, ops.FleetClusterCompleted)
+}
+
+func (s *FleetSuite) TestResumesOperation(c *check.C) {
+	operator := newFakeOperator()
+	operator.operations["b"] = &ops.SiteOperation{
+		ID:         "op-b",
+		SiteDomain: "b",
+		State:      ops.OperationStateUpdateInProgress,
+	}
+	op := s.newOperation(c, ops.FleetOperationUpdate, 0, 0, true, "a", "b", "c")
+	op.State = ops.FleetStateInProgress
+	op.Clusters[0].State = ops.FleetClusterCompleted
+	op.Clusters[0].OperationID = "op-a"
+	op.Clusters[1].State = ops.FleetClusterInProgress
+	op.Clusters[1].OperationID = "op-b"
+	_, err := s.backend.UpdateFleetOperation(op)
+	c.Assert(err, check.IsNil)
+
+	err = s.newExecutor(c, operator).Execute(context.TODO(), op)
+	c.Assert(err, check.IsNil)
+
+	out, err := s.backend.GetFleetOperation(op.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(out.State, check.Equals, ops.FleetStateCompleted)
+	c.Assert(operator.started, check.DeepEquals, []string{"c"})
+}
+
+func (s *FleetSuite) newOperation(c *check.C, opType string, waveSize, concurrency int, stopOnFailure bool, names ...string) storage.FleetOperation {
+	var clusters []storage.Site
+	for _, name := range names {
+		clusters = append(clusters, storage.Site{Domain: name, AccountID: "account"})
+	}
+	op, err := NewOperation(ops.CreateFleetOperationRequest{
+		AccountID:     "account",
+		Type:          opType,
+		App:           "example.com/app:1.0.0",
+		Config:        []byte("{}"),
+		WaveSize:      waveSize,
+		Concurrency:   concurrency,
+		StopOnFailure: stopOnFailure,
+	}, clusters)
+	c.Assert(err, check.IsNil)
+	_, err = s.backend.CreateFleetOperation(*op)
+	c.Assert(err, check.IsNil)
+	return *op
+}
+
+func (s *FleetSuite) newExecutor(c *check.C, operator ops.Operator) *Executor {
+	executor, err := New(Config{
+		Backend:      s.backend,
+		Operator:     operator,
+		PollInterval: 10 * time.Millisecond,
+	})
+	c.Assert(err, check.IsNil)
+	return executor
+}
+
+func newFakeOperator() *fakeOperator {
+	return &fakeOperator{
+		failed:     make(map[string]bool),
+		operations: make(map[string]*ops.SiteOperation),
+	}
+}
+
+// fakeOperator creates cluster operations that finish
+// the first time their state is queried
+type fakeOperator struct {
+	ops.Operator
+	sync.Mutex
+	// failed lists clusters with failing operations
+	failed map[string]bool
+	// operations maps cluster names to their operations
+	operations map[string]*ops.SiteOperation
+	// started lists clusters in the order their operations were created
+	started []string
+	// updates lists created update requests
+	updates []ops.CreateSiteAppUpdateOperationRequest
+	// configs lists created configuration update requests
+	configs []ops.CreateUpdateConfigOperationRequest
+	// running is the number of running operations
+	running int
+	// maxRunning is the maximum number of operations running at the same time
+	maxRunning int
+}
+
+func (o *fakeOperator) CreateSiteAppUpdateOperation(ctx context.Context, req ops.CreateSiteAppUpdateOperationRequest) (*ops.SiteOperationKey, error) {
+	o.Lock()
+	defer o.Unlock()
+	o.updates = append(o.updates, req)
+	return o.start(req.AccountID, req.SiteDomain, ops.OperationUpdate), nil
+}
+
+func (o *fakeOperator) CreateUpdateConfigOperation(ctx context.Context, req ops.CreateUpdateConfigOperationRequest) (*ops.SiteOperationKey, error) {
+	o.Lock()
+	defer o.Unlock()
+	o.configs = append(o.configs, req)
+	return o.start(req.ClusterKey.AccountID, req.ClusterKey.SiteDomain, ops.OperationUpdateConfig), nil
+}
+
+func (o *fakeOperator) start(accountID, clusterName, opType string) *ops.SiteOperationKey {
+	o.started = append(o.started, clusterName)
+	o.operations[clusterName] = &ops.SiteOperation{
+		ID:         "op-" + clusterName,
+		AccountID:  accountID,
+		SiteDomain: clusterName,
+		Type:       opType,
+		State:      ops.OperationStateUpdateInProgress,
+	}
+	o.running++
+	if o.running > o.maxRunning {
+		o.maxRunning = o.running
+	}
+	key := o.operations[clusterName].Key()
+	return &key
+}
+
+func (o *fakeOperator) GetSiteOperation(key ops.SiteOperationKey) (*ops.SiteOperation, error) {
+	o.Lock()
+	defer o.Unlock()
+	operation, ok := o.operations[key.SiteDomain]
+	if !ok || operation.ID != key.OperationID {
+		return nil, trace.NotFound("operation %v not found", key)
+	}
+	if !operation.IsFinished() {
+		operation.State = ops.OperationStateCompleted
+		if o.failed[key.SiteDomain] {
+			operation.State = ops.OperationStateFailed
+		}
+		o.running--
+	}
+	out := *operation
+	return &out, nil
+}
+
+func (o *fakeOperator) GetSiteOperationProgress(key ops.SiteOperationKey) (*ops.ProgressEntry, error) {
+	return &ops.ProgressEntry{
+		SiteDomain:  key.SiteDomain,
+		OperationID: key.OperationID,
+		Message:     "operation on " + key.SiteDomain + " failed",
+	}, nil
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+)
+
+// phaseFunc executes or rolls back a single phase
+type phaseFunc func(ctx context.Context, phase storage.OperationPhase) error
+
+// runConcurrently invokes fn for the specified sibling phases concurrently
+// with at most concurrency invocations running at the same time.
+// Zero concurrency means no limit.
+//
+// A phase is only started once all sibling phases it requires (directly
+// or via its sub-phases) have finished successfully. If reverse is set,
+// the ordering is inverted: a phase is only started once all siblings that
+// require it have finished, which is the order phases are rolled back in.
+//
+// After the first failure no new phases are started, the phases already
+// running are waited for and the errors are returned as an aggregate
+func runConcurrently(ctx context.Context, phases []storage.OperationPhase, concurrency int, reverse bool, fn phaseFunc) error {
+	deps, err := siblingDependencies(phases, reverse)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if concurrency <= 0 || concurrency > len(phases) {
+		concurrency = len(phases)
+	}
+	type result struct {
+		index int
+		err   error
+	}
+	resultsCh := make(chan result, len(phases))
+	done := make([]bool, len(phases))
+	started := make([]bool, len(phases))
+	var errors []error
+	running, remaining := 0, len(phases)
+	for {
+		if len(errors) == 0 && ctx.Err() == nil {
+			for i, phase := range phases {
+				if running == concurrency {
+					break
+				}
+				if started[i] || !allDone(deps[i], done) {
+					continue
+				}
+				started[i] = true
+				running++
+				go func(i int, phase storage.OperationPhase) {
+					resultsCh <- result{index: i, err: fn(ctx, phase)}
+				}(i, phase)
+			}
+		}
+		if running == 0 {
+			break
+		}
+		result := <-resultsCh
+		running--
+		remaining--
+		if result.err != nil {
+			errors = append(errors, result.err)
+			continue
+		}
+		done[result.index] = true
+	}
+	if len(errors) != 0 {
+		return trace.NewAggregate(errors...)
+	}
+	if remaining != 0 {
+		return trace.Wrap(ctx.Err())
+	}
+	return nil
+}
+
+// siblingDependencies returns the indexes of sibling phases each of the
+// specified phases depends on. A phase depends on a sibling if it or any of its
+// sub-phases requires the sibling or any of the sibling's sub-phases.
+// If reverse is set, the dependencies are inverted.
+// Returns an error if the phases have circular dependencies
+func siblingDependencies(phases []storage.OperationPhase, reverse bool) ([][]int, error) {
+	deps := make([][]int, len(phases))
+	for i := range phases {
+		for _, required := range subtreeRequirements(phases[i]) {
+			for j, sibling := range phases {
+				if i == j || !isSameOrDescendant(required, sibling.ID) {
+					continue
+				}
+				if reverse {
+					deps[j] = append(deps[j], i)
+				} else {
+					deps[i] = append(deps[i], j)
+				}
+			}
+		}
+	}
+	// Make sure the phases can be ordered so the execution cannot deadlock
+	done := make([]bool, len(phases))
+	for resolved := 0; resolved < len(phases); {
+		var progress bool
+		for i := range phases {
+			if !done[i] && allDone(deps[i], done) {
+				done[i] = true
+				resolved++
+				progress = true
+			}
+		}
+		if !progress {
+			return nil, trace.BadParameter("phases of %q have circular dependencies",
+				path.Dir(phases[0].ID))
+		}
+	}
+	return deps, nil
+}
+
+// subtreeRequirements returns requirements of the phase and all its sub-phases
+func subtreeRequirements(phase storage.OperationPhase) (requires []string) {
+	requires = append(requires, phase.Requires...)
+	for _, subphase := range phase.Phases {
+		requires = append(requires, subtreeRequirements(subphase)...)
+	}
+	return requires
+}
+
+// isSameOrDescendant returns true if phase with the specified ID is either
+// the phase with the given parentID or one of its sub-phases
+func isSameOrDescendant(phaseID, parentID string) bool {
+	return phaseID == parentID || strings.HasPrefix(phaseID, parentID+"/")
+}
+
+func allDone(indexes []int, done []bool) bool {
+	for _, i := range indexes {
+		if !done[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// formatConcurrency returns a human-readable description of the concurrency limit
+func formatConcurrency(concurrency int) string {
+	if concurrency <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (at most %v at a time)", concurrency)
+}
+
+func newPhaseCounter(total int) *phaseCounter {
+	return &phaseCounter{total: total}
+}
+
+// phaseCounter counts phases completed within a concurrently executed group
+type phaseCounter struct {
+	sync.Mutex
+	total     int
+	completed int
+}
+
+// next increments the number of completed phases and returns the progress as text
+func (r *phaseCounter) next() string {
+	r.Lock()
+	defer r.Unlock()
+	r.completed++
+	return fmt.Sprintf("%v/%v", r.completed, r.total)
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/check.v1"
+)
+
+type ConcurrentSuite struct{}
+
+var _ = check.Suite(&ConcurrentSuite{})
+
+func (s *ConcurrentSuite) TestExecutesWithLimit(c *check.C) {
+	engine := newTestEngine(newParallelPlan(2, map[string][]string{
+		"/nodes/node-3": {"/nodes/node-1"},
+	}))
+	fsm := newTestFSM(c, engine)
+
+	err := fsm.ExecutePlan(context.TODO(), nil, false)
+	c.Assert(err, check.IsNil)
+
+	c.Assert(engine.maxRunning, check.Equals, 2)
+	c.Assert(IsCompleted(&engine.plan), check.Equals, true)
+	c.Assert(engine.index("done /nodes/node-1") < engine.index("start /nodes/node-3"), check.Equals, true,
+		check.Commentf("expected node-3 to start after node-1: %v", engine.events))
+}
+
+func (s *ConcurrentSuite) TestStopsOnFailure(c *check.C) {
+	engine := newTestEngine(newParallelPlan(1, nil))
+	engine.fail = "/nodes/node-1"
+	fsm := newTestFSM(c, engine)
+
+	err := fsm.ExecutePlan(context.TODO(), nil, false)
+	c.Assert(err, check.NotNil)
+
+	c.Assert(engine.events, check.DeepEquals, []string{"start /nodes/node-1", "done /nodes/node-1"})
+	phase, err := FindPhase(&engine.plan, "/nodes/node-2")
+	c.Assert(err, check.IsNil)
+	c.Assert(phase.IsUnstarted(), check.Equals, true)
+}
+
+func (s *ConcurrentSuite) TestRollsBackInReverseOrder(c *check.C) {
+	engine := newTestEngine(newParallelPlan(0, map[string][]string{
+		"/nodes/node-3": {"/nodes/node-1"},
+	}))
+	fsm := newTestFSM(c, engine)
+	c.Assert(fsm.ExecutePlan(context.TODO(), nil, false), check.IsNil)
+	engine.events = nil
+
+	err := fsm.RollbackPhase(context.TODO(), Params{PhaseID: "/nodes"})
+	c.Assert(err, check.IsNil)
+
+	for _, phase := range FlattenPlan(&engine.plan) {
+		if !phase.HasSubphases() {
+			c.Assert(phase.IsRolledBack(), check.Equals, true, check.Commentf(phase.ID))
+		}
+	}
+	c.Assert(engine.index("done /nodes/node-3") < engine.index("start /nodes/node-1"), check.Equals, true,
+		check.Commentf("expected node-1 to be rolled back after node-3: %v", engine.events))
+}
+
+func (s *ConcurrentSuite) TestRejectsCircularDependencies(c *check.C) {
+	engine := newTestEngine(newParallelPlan(0, map[string][]string{
+		"/nodes/node-1": {"/nodes/node-2"},
+		"/nodes/node-2": {"/nodes/node-1"},
+	}))
+	fsm := newTestFSM(c, engine)
+
+	err := fsm.ExecutePlan(context.TODO(), nil, false)
+	c.Assert(trace.IsBadParameter(err), check.Equals, true, check.Commentf("%v", err))
+	c.Assert(engine.events, check.HasLen, 0)
+}
+
+func newTestFSM(c *check.C, engine *testEngine) *FSM {
+	fsm, err := New(Config{Engine: engine})
+	c.Assert(err, check.IsNil)
+	return fsm
+}
+
+// newParallelPlan returns a plan with a single parallel phase with
+// the specified concurrency limit and four sub-phases
+func newParallelPlan(concurrency int, requires map[string][]string) storage.OperationPlan {
+	root := storage.OperationPhase{
+		ID:          "/nodes",
+		Parallel:    true,
+		Concurrency: concurrency,
+	}
+	for _, id := range []string{"/nodes/node-1", "/nodes/node-2", "/nodes/node-3", "/nodes/node-4"} {
+		root.Phases = append(root.Phases, storage.OperationPhase{
+			ID:       id,
+			Executor: "test",
+			Requires: requires[id],
+		})
+	}
+	return storage.OperationPlan{Phases: []storage.OperationPhase{root}}
+}
+
+func newTestEngine(plan storage.OperationPlan) *testEngine {
+	return &testEngine{plan: plan}
+}
+
+// testEngine is an in-memory FSM engine that records the order
+// phases are executed in
+type testEngine struct {
+	sync.Mutex
+	plan       storage.OperationPlan
+	fail       string
+	running    int
+	maxRunning int
+	events     []string
+}
+
+func (e *testEngine) GetExecutor(p ExecutorParams, _ Remote) (PhaseExecutor, error) {
+	return &testExecutor{
+		FieldLogger: logrus.WithField("phase", p.Phase.ID),
+		engine:      e,
+		phase:       p.Phase.ID,
+	}, nil
+}
+
+func (e *testEngine) ChangePhaseState(ctx context.Context, change StateChange) error {
+	e.Lock()
+	defer e.Unlock()
+	phase, err := FindPhase(&e.plan, change.Phase)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	phase.State = change.State
+	return nil
+}
+
+func (e *testEngine) GetPlan() (*storage.OperationPlan, error) {
+	e.Lock()
+	defer e.Unlock()
+	plan := e.plan
+	plan.Phases = copyPhases(e.plan.Phases)
+	return &plan, nil
+}
+
+func (e *testEngine) RunCommand(context.Context, RemoteRunner, storage.Server, Params) error {
+	return trace.NotImplemented("remote execution is not supported")
+}
+
+func (e *testEngine) Complete(error) error {
+	return nil
+}
+
+func (e *testEngine) run(phase string) error {
+	e.Lock()
+	e.running++
+	if e.running > e.maxRunning {
+		e.maxRunning = e.running
+	}
+	e.events = append(e.events, "start "+phase)
+	e.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	e.Lock()
+	defer e.Unlock()
+	e.running--
+	e.events = append(e.events, "done "+phase)
+	if phase == e.fail {
+		return trace.Errorf("phase %v failed", phase)
+	}
+	return nil
+}
+
+// index returns the position of the specified event or -1 if it has not been recorded
+func (e *testEngine) index(event string) int {
+	for i := range e.events {
+		if e.events[i] == event {
+			return i
+		}
+	}
+	return -1
+}
+
+type testExecutor struct {
+	logrus.FieldLogger
+	engine *testEngine
+	phase  string
+}
+
+func (e *testExecutor) PreCheck(context.Context) error  { return nil }
+func (e *testExecutor) PostCheck(context.Context) error { return nil }
+func (e *testExecutor) Execute(context.Context) error   { return e.engine.run(e.phase) }
+func (e *testExecutor) Rollback(context.Context) error  { return e.engine.run(e.phase) }
+
+func copyPhases(phases []storage.OperationPhase) []storage.OperationPhase {
+	result := make([]storage.OperationPhase, len(phases))
+	for i, phase := range phases {
+		result[i] = phase
+		result[i].Phases = copyPhases(phase.Phases)
+	}
+	return result
+}
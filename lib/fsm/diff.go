@@ -0,0 +1,324 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+	"gopkg.in/yaml.v2"
+)
+
+// PlanDiff describes the differences between two operation plans
+type PlanDiff struct {
+	// Attributes lists differences in plan-wide attributes, e.g. cluster servers
+	Attributes []ValueDiff `json:"attributes,omitempty"`
+	// Phases lists differences between individual phases
+	Phases []PhaseDiff `json:"phases,omitempty"`
+}
+
+// IsEmpty returns true if the compared plans are equivalent
+func (r PlanDiff) IsEmpty() bool {
+	return len(r.Attributes) == 0 && len(r.Phases) == 0
+}
+
+// PhaseDiff describes how a single phase differs between two plans
+type PhaseDiff struct {
+	// ID is the phase ID
+	ID string `json:"id"`
+	// Change is the type of change: phase has been added, removed or changed
+	Change string `json:"change"`
+	// Fields lists the differences in phase attributes and data.
+	// Only set for changed phases
+	Fields []ValueDiff `json:"fields,omitempty"`
+}
+
+// ValueDiff describes the difference in a single value
+type ValueDiff struct {
+	// Path is the path to the value, e.g. data.server.hostname
+	Path string `json:"path"`
+	// Old is the value in the first plan, nil if it is missing
+	Old interface{} `json:"old"`
+	// New is the value in the second plan, nil if it is missing
+	New interface{} `json:"new"`
+}
+
+// DiffPlans compares the specified plans phase by phase.
+//
+// Phases are matched by ID. Besides the phase attributes like the executor
+// and requirements, phase data such as packages and servers is compared
+// as well. Execution state (phase state, timestamps and errors) as well as
+// the operation identity are not taken into account
+func DiffPlans(old, new storage.OperationPlan) (*PlanDiff, error) {
+	var diff PlanDiff
+	err := diffValues("", planAttributes(old), planAttributes(new), &diff.Attributes)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	oldPhases := FlattenPlan(&old)
+	newPhases := make(map[string]*storage.OperationPhase)
+	for _, phase := range FlattenPlan(&new) {
+		newPhases[phase.ID] = phase
+	}
+	seen := make(map[string]bool)
+	for _, oldPhase := range oldPhases {
+		seen[oldPhase.ID] = true
+		newPhase, ok := newPhases[oldPhase.ID]
+		if !ok {
+			diff.Phases = append(diff.Phases, PhaseDiff{
+				ID:     oldPhase.ID,
+				Change: PhaseRemoved,
+			})
+			continue
+		}
+		var fields []ValueDiff
+		err := diffValues("", phaseAttributes(*oldPhase), phaseAttributes(*newPhase), &fields)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if len(fields) != 0 {
+			diff.Phases = append(diff.Phases, PhaseDiff{
+				ID:     oldPhase.ID,
+				Change: PhaseChanged,
+				Fields: fields,
+			})
+		}
+	}
+	for _, newPhase := range FlattenPlan(&new) {
+		if !seen[newPhase.ID] {
+			diff.Phases = append(diff.Phases, PhaseDiff{
+				ID:     newPhase.ID,
+				Change: PhaseAdded,
+			})
+		}
+	}
+	return &diff, nil
+}
+
+// ReadOperationPlan decodes the operation plan exported in either
+// JSON or YAML format from the provided reader
+func ReadOperationPlan(r io.Reader) (*storage.OperationPlan, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var plan storage.OperationPlan
+	if trimmed := bytes.TrimSpace(data); len(trimmed) != 0 && trimmed[0] == '{' {
+		err = json.Unmarshal(data, &plan)
+	} else {
+		err = yaml.Unmarshal(data, &plan)
+	}
+	if err != nil {
+		return nil, trace.Wrap(err, "failed to decode operation plan")
+	}
+	return &plan, nil
+}
+
+// FormatPlanDiffText outputs the plan differences in human-readable form.
+// oldName and newName identify the compared plans
+func FormatPlanDiffText(w io.Writer, diff PlanDiff, oldName, newName string) {
+	if diff.IsEmpty() {
+		fmt.Fprintln(w, "Plans are identical.")
+		return
+	}
+	fmt.Fprintf(w, "--- %v\n+++ %v\n", oldName, newName)
+	if len(diff.Attributes) != 0 {
+		fmt.Fprintln(w, "Plan attributes:")
+		formatValueDiffs(w, diff.Attributes)
+	}
+	for _, phase := range diff.Phases {
+		switch phase.Change {
+		case PhaseAdded:
+			fmt.Fprintf(w, "Phase %v: only in %v\n", phase.ID, newName)
+		case PhaseRemoved:
+			fmt.Fprintf(w, "Phase %v: only in %v\n", phase.ID, oldName)
+		default:
+			fmt.Fprintf(w, "Phase %v:\n", phase.ID)
+			formatValueDiffs(w, phase.Fields)
+		}
+	}
+}
+
+func formatValueDiffs(w io.Writer, diffs []ValueDiff) {
+	for _, diff := range diffs {
+		fmt.Fprintf(w, "  %v:\n", diff.Path)
+		fmt.Fprintf(w, "    - %v\n", formatValue(diff.Old))
+		fmt.Fprintf(w, "    + %v\n", formatValue(diff.New))
+	}
+}
+
+func formatValue(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "<none>"
+	case map[string]interface{}, []interface{}:
+		bytes, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprintf("%v", value)
+		}
+		return string(bytes)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// planAttributes returns the subset of plan-wide attributes to compare
+func planAttributes(plan storage.OperationPlan) interface{} {
+	return struct {
+		OperationType  string            `json:"operation_type"`
+		ClusterName    string            `json:"cluster_name"`
+		Servers        []storage.Server  `json:"servers"`
+		GravityPackage loc.Locator       `json:"gravity_package"`
+		DNSConfig      storage.DNSConfig `json:"dns_config"`
+	}{
+		OperationType:  plan.OperationType,
+		ClusterName:    plan.ClusterName,
+		Servers:        plan.Servers,
+		GravityPackage: plan.GravityPackage,
+		DNSConfig:      plan.DNSConfig,
+	}
+}
+
+// phaseAttributes returns the subset of phase attributes to compare.
+// Sub-phases are compared separately
+func phaseAttributes(phase storage.OperationPhase) interface{} {
+	return struct {
+		Executor    string                      `json:"executor"`
+		Description string                      `json:"description"`
+		Requires    []string                    `json:"requires"`
+		Parallel    bool                        `json:"parallel"`
+		Concurrency int                         `json:"concurrency"`
+		Data        *storage.OperationPhaseData `json:"data"`
+	}{
+		Executor:    phase.Executor,
+		Description: phase.Description,
+		Requires:    phase.Requires,
+		Parallel:    phase.Parallel,
+		Concurrency: phase.Concurrency,
+		Data:        phase.Data,
+	}
+}
+
+// diffValues compares the JSON representations of the provided values
+// and collects the differences into diffs
+func diffValues(path string, old, new interface{}, diffs *[]ValueDiff) error {
+	oldValue, err := toGeneric(old)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	newValue, err := toGeneric(new)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	diffGeneric(path, oldValue, newValue, diffs)
+	return nil
+}
+
+func diffGeneric(path string, old, new interface{}, diffs *[]ValueDiff) {
+	if isEmptyValue(old) && isEmptyValue(new) {
+		// plans decoded from different formats may represent
+		// empty lists and maps differently
+		return
+	}
+	switch oldValue := old.(type) {
+	case map[string]interface{}:
+		newValue, ok := new.(map[string]interface{})
+		if !ok {
+			break
+		}
+		var keys []string
+		for key := range oldValue {
+			keys = append(keys, key)
+		}
+		for key := range newValue {
+			if _, ok := oldValue[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			diffGeneric(joinPath(path, key), oldValue[key], newValue[key], diffs)
+		}
+		return
+	case []interface{}:
+		newValue, ok := new.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(oldValue) || i < len(newValue); i++ {
+			var oldItem, newItem interface{}
+			if i < len(oldValue) {
+				oldItem = oldValue[i]
+			}
+			if i < len(newValue) {
+				newItem = newValue[i]
+			}
+			diffGeneric(fmt.Sprintf("%v[%v]", path, i), oldItem, newItem, diffs)
+		}
+		return
+	}
+	if !reflect.DeepEqual(old, new) {
+		*diffs = append(*diffs, ValueDiff{Path: path, Old: old, New: new})
+	}
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func toGeneric(value interface{}) (result interface{}, err error) {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return result, nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return fmt.Sprintf("%v.%v", path, key)
+}
+
+const (
+	// PhaseAdded indicates that the phase is only present in the second plan
+	PhaseAdded = "added"
+	// PhaseRemoved indicates that the phase is only present in the first plan
+	PhaseRemoved = "removed"
+	// PhaseChanged indicates that the phase differs between the plans
+	PhaseChanged = "changed"
+)
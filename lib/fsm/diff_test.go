@@ -0,0 +1,128 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"gopkg.in/check.v1"
+)
+
+func TestFSM(t *testing.T) { check.TestingT(t) }
+
+type DiffSuite struct{}
+
+var _ = check.Suite(&DiffSuite{})
+
+func (s *DiffSuite) TestIdenticalPlans(c *check.C) {
+	plan := newTestPlan("1.0.0", "node-1")
+	other := newTestPlan("1.0.0", "node-1")
+	// execution state and operation identity are ignored
+	other.OperationID = "other-operation"
+	other.Phases[0].State = storage.OperationPhaseStateCompleted
+	diff, err := DiffPlans(plan, other)
+	c.Assert(err, check.IsNil)
+	c.Assert(diff.IsEmpty(), check.Equals, true, check.Commentf("%#v", diff))
+}
+
+func (s *DiffSuite) TestDiffPlans(c *check.C) {
+	old := newTestPlan("1.0.0", "node-1")
+	new := newTestPlan("1.0.1", "node-2")
+	new.Phases = append(new.Phases, storage.OperationPhase{ID: "/health"})
+	diff, err := DiffPlans(old, new)
+	c.Assert(err, check.IsNil)
+	c.Assert(diff, check.DeepEquals, &PlanDiff{
+		Attributes: []ValueDiff{
+			{Path: "servers[0].hostname", Old: "node-1", New: "node-2"},
+		},
+		Phases: []PhaseDiff{
+			{
+				ID:     "/masters/node-1",
+				Change: PhaseRemoved,
+			},
+			{
+				ID:     "/app",
+				Change: PhaseChanged,
+				Fields: []ValueDiff{
+					{Path: "data.package.version", Old: "1.0.0", New: "1.0.1"},
+				},
+			},
+			{
+				ID:     "/masters/node-2",
+				Change: PhaseAdded,
+			},
+			{
+				ID:     "/health",
+				Change: PhaseAdded,
+			},
+		},
+	})
+}
+
+func (s *DiffSuite) TestReadPlan(c *check.C) {
+	plan := newTestPlan("1.0.0", "node-1")
+	for _, format := range []func(*bytes.Buffer, storage.OperationPlan) error{
+		func(w *bytes.Buffer, plan storage.OperationPlan) error { return FormatOperationPlanJSON(w, plan) },
+		func(w *bytes.Buffer, plan storage.OperationPlan) error { return FormatOperationPlanYAML(w, plan) },
+	} {
+		var buf bytes.Buffer
+		c.Assert(format(&buf, plan), check.IsNil)
+		decoded, err := ReadOperationPlan(&buf)
+		c.Assert(err, check.IsNil)
+		diff, err := DiffPlans(plan, *decoded)
+		c.Assert(err, check.IsNil)
+		c.Assert(diff.IsEmpty(), check.Equals, true, check.Commentf("%#v", diff))
+	}
+}
+
+func newTestPlan(version, hostname string) storage.OperationPlan {
+	server := storage.Server{Hostname: hostname, AdvertiseIP: "192.168.1.1"}
+	return storage.OperationPlan{
+		OperationID:   "operation",
+		OperationType: "operation_update",
+		ClusterName:   "example.com",
+		Servers:       []storage.Server{server},
+		Phases: []storage.OperationPhase{
+			{
+				ID: "/masters",
+				Phases: []storage.OperationPhase{
+					{
+						ID:       "/masters/" + hostname,
+						Executor: "update-masters",
+						Data:     &storage.OperationPhaseData{Server: &server},
+					},
+				},
+			},
+			{
+				ID:       "/app",
+				Executor: "update-app",
+				Requires: []string{"/masters"},
+				Data: &storage.OperationPhaseData{
+					Package: &loc.Locator{
+						Repository: "gravitational.io",
+						Name:       "app",
+						Version:    version,
+					},
+				},
+			},
+		},
+	}
+}
@@ -22,6 +22,8 @@ import (
 	"path"
 
 	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/rpc"
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/utils"
 
@@ -99,11 +101,17 @@ type FSM struct {
 	preExecFn PhaseHookFn
 	// postExecFn is called after phase execution if set
 	postExecFn PhaseHookFn
+	// progressFn is called for progress reported by remotely executing phases if set
+	progressFn ProgressHookFn
 }
 
 // PhaseHookFn defines the phase hook function
 type PhaseHookFn func(context.Context, Params) error
 
+// ProgressHookFn defines the hook function that receives structured progress
+// reported by the phase executing on the specified server
+type ProgressHookFn func(context.Context, Params, storage.Server, pb.Progress) error
+
 // Config represents config
 type Config struct {
 	// Engine is the specific FSM engine
@@ -253,6 +261,9 @@ func (f *FSM) RollbackPhase(ctx context.Context, p Params) error {
 		}
 		return nil
 	}
+	if phase.Parallel {
+		return trace.Wrap(f.rollbackSubphasesConcurrently(ctx, p, *phase))
+	}
 	for i := len(phase.Phases) - 1; i >= 0; i-- {
 		p.PhaseID = phase.Phases[i].ID
 		err = f.RollbackPhase(ctx, p)
@@ -273,6 +284,12 @@ func (f *FSM) SetPostExec(fn PhaseHookFn) {
 	f.postExecFn = fn
 }
 
+// SetProgressHook sets the hook that's called for every progress update
+// reported by a phase executing on a remote server
+func (f *FSM) SetProgressHook(fn ProgressHookFn) {
+	f.progressFn = fn
+}
+
 // Close releases all FSM resources
 func (f *FSM) Close() error {
 	return trace.Wrap(f.Runner.Close())
@@ -345,9 +362,27 @@ func (f *FSM) executePhaseRemotely(ctx context.Context, p Params, phase storage.
 	p.Progress.NextStep("Executing %q on remote node %v", phase.ID,
 		server.Hostname)
 
+	ctx = withProgressHandler(ctx, func(progress *pb.Progress) {
+		f.reportProgress(ctx, p, server, *progress)
+	})
 	return f.RunCommand(ctx, f.Runner, server, p)
 }
 
+// reportProgress outputs the progress reported by the phase executing
+// on the specified server and passes it on to the progress hook
+func (f *FSM) reportProgress(ctx context.Context, p Params, server storage.Server, progress pb.Progress) {
+	if progress.Message != "" {
+		p.Progress.PrintSubStep("%v: %v", server.Hostname, progress.Message)
+	}
+	if f.progressFn == nil {
+		return
+	}
+	err := f.progressFn(ctx, p, server, progress)
+	if err != nil {
+		f.WithError(err).WithField("progress", progress.String()).Warn("Failed to report progress.")
+	}
+}
+
 // executePhaseLocally executes the specified operation phase on this server
 func (f *FSM) executePhaseLocally(ctx context.Context, p Params, phase storage.OperationPhase) error {
 	if !phase.HasSubphases() {
@@ -371,20 +406,51 @@ func (f *FSM) executeSubphasesSequentially(ctx context.Context, p Params, phase
 	return nil
 }
 
+// executeSubphasesConcurrently executes sub-phases of the specified phase
+// concurrently honoring the phase concurrency limit and the requirements
+// between the sub-phases
 func (f *FSM) executeSubphasesConcurrently(ctx context.Context, p Params, phase storage.OperationPhase) error {
-	errorsCh := make(chan error, len(phase.Phases))
-	for _, subphase := range phase.Phases {
-		go func(p Params, subphase storage.OperationPhase) {
+	p.Progress.NextStep("Executing %v phases of %q concurrently%v", len(phase.Phases),
+		phase.ID, formatConcurrency(phase.Concurrency))
+	counter := newPhaseCounter(len(phase.Phases))
+	return runConcurrently(ctx, phase.Phases, phase.Concurrency, false,
+		func(ctx context.Context, subphase storage.OperationPhase) error {
+			p := p
 			p.PhaseID = subphase.ID
 			err := f.ExecutePhase(ctx, p)
 			if err != nil {
-				logrus.Warnf("Failed to execute phase %q: %v.",
+				f.Warnf("Failed to execute phase %q: %v.",
 					p.PhaseID, trace.DebugReport(err))
+				return trace.Wrap(err, "failed to execute phase %q", p.PhaseID)
 			}
-			errorsCh <- trace.Wrap(err, "failed to execute phase %q", p.PhaseID)
-		}(p, subphase)
-	}
-	return utils.CollectErrors(ctx, errorsCh)
+			p.Progress.PrintSubStep("Phase %q completed (%v)", p.PhaseID, counter.next())
+			return nil
+		})
+}
+
+// rollbackSubphasesConcurrently rolls back sub-phases of the specified phase
+// concurrently honoring the phase concurrency limit. A sub-phase is rolled back
+// only after the sub-phases that require it have been rolled back.
+// Sub-phases that have not been executed are skipped
+func (f *FSM) rollbackSubphasesConcurrently(ctx context.Context, p Params, phase storage.OperationPhase) error {
+	p.Progress.NextStep("Rolling back phases of %q concurrently%v",
+		phase.ID, formatConcurrency(phase.Concurrency))
+	return runConcurrently(ctx, phase.Phases, phase.Concurrency, true,
+		func(ctx context.Context, subphase storage.OperationPhase) error {
+			if subphase.IsUnstarted() || subphase.IsRolledBack() {
+				f.Debugf("Skip rollback of phase %q in state %q.", subphase.ID, subphase.State)
+				return nil
+			}
+			p := p
+			p.PhaseID = subphase.ID
+			err := f.RollbackPhase(ctx, p)
+			if err != nil {
+				f.Warnf("Failed to rollback phase %q: %v.",
+					p.PhaseID, trace.DebugReport(err))
+				return trace.Wrap(err, "failed to rollback phase %q", p.PhaseID)
+			}
+			return nil
+		})
 }
 
 func (f *FSM) executeOnePhase(ctx context.Context, p Params, phase storage.OperationPhase) error {
@@ -393,9 +459,10 @@ func (f *FSM) executeOnePhase(ctx context.Context, p Params, phase storage.Opera
 		return trace.Wrap(err)
 	}
 	executor, err := f.GetExecutor(ExecutorParams{
-		Plan:     *plan,
-		Phase:    phase,
-		Progress: p.Progress,
+		Plan:  *plan,
+		Phase: phase,
+		// Forward progress to the agent if the phase is executed remotely
+		Progress: rpc.NewProgress(p.Progress, rpc.NewProgressReporter()),
 	}, f)
 	if err != nil {
 		return trace.Wrap(err)
@@ -0,0 +1,111 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/schema"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/tool/common"
+)
+
+// PlanImpact summarizes the changes an operation plan makes to the cluster
+type PlanImpact struct {
+	// DrainedNodes lists the nodes that are drained during the operation
+	DrainedNodes []storage.Server `json:"drained_nodes,omitempty"`
+	// RotatedPackages lists the configuration packages generated for nodes
+	RotatedPackages []RotatedPackage `json:"rotated_packages,omitempty"`
+	// Hooks lists the application hooks executed by the operation
+	Hooks []PlanHook `json:"hooks,omitempty"`
+}
+
+// RotatedPackage describes a configuration package rotated on a node
+type RotatedPackage struct {
+	// Server is the node the package is rotated on
+	Server storage.Server `json:"server"`
+	// Package is the new package locator
+	Package loc.Locator `json:"package"`
+}
+
+// PlanHook describes an application hook run by an operation phase
+type PlanHook struct {
+	// Phase is the ID of the phase that runs the hook
+	Phase string `json:"phase"`
+	// Package is the application package the hook belongs to
+	Package loc.Locator `json:"package"`
+	// Hook is the hook type
+	Hook schema.HookType `json:"hook"`
+}
+
+// AddDrainedNode records the specified server as drained unless
+// it has already been recorded
+func (r *PlanImpact) AddDrainedNode(server storage.Server) {
+	for _, node := range r.DrainedNodes {
+		if node.AdvertiseIP == server.AdvertiseIP {
+			return
+		}
+	}
+	r.DrainedNodes = append(r.DrainedNodes, server)
+}
+
+// AddRotatedPackage records the package rotated on the specified server
+// unless it has already been recorded
+func (r *PlanImpact) AddRotatedPackage(server storage.Server, pkg loc.Locator) {
+	for _, rotated := range r.RotatedPackages {
+		if rotated.Server.AdvertiseIP == server.AdvertiseIP && rotated.Package.IsEqualTo(pkg) {
+			return
+		}
+	}
+	r.RotatedPackages = append(r.RotatedPackages, RotatedPackage{
+		Server:  server,
+		Package: pkg,
+	})
+}
+
+// FormatPlanImpactText outputs the plan impact summary in human-readable form
+func FormatPlanImpactText(w io.Writer, impact PlanImpact) {
+	fmt.Fprintln(w, "Nodes to drain:")
+	if len(impact.DrainedNodes) == 0 {
+		fmt.Fprintln(w, "  none")
+	}
+	for _, node := range impact.DrainedNodes {
+		fmt.Fprintf(w, "  * %v (%v)\n", node.Hostname, node.AdvertiseIP)
+	}
+	fmt.Fprintln(w, "Packages to rotate:")
+	if len(impact.RotatedPackages) == 0 {
+		fmt.Fprintln(w, "  none")
+	} else {
+		var t tabwriter.Writer
+		t.Init(w, 0, 10, 5, ' ', 0)
+		common.PrintTableHeader(&t, []string{"Node", "Package"})
+		for _, rotated := range impact.RotatedPackages {
+			fmt.Fprintf(&t, "%v\t%v\n", rotated.Server.AdvertiseIP, rotated.Package)
+		}
+		t.Flush()
+	}
+	fmt.Fprintln(w, "Hooks to run:")
+	if len(impact.Hooks) == 0 {
+		fmt.Fprintln(w, "  none")
+	}
+	for _, hook := range impact.Hooks {
+		fmt.Fprintf(w, "  * %v hook of %v (phase %v)\n", hook.Hook, hook.Package, hook.Phase)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os/exec"
 	"path/filepath"
 	"sync"
@@ -29,6 +30,7 @@ import (
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/rpc"
 	rpcclient "github.com/gravitational/gravity/lib/rpc/client"
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
 	"github.com/gravitational/gravity/lib/schema"
 	"github.com/gravitational/gravity/lib/state"
 	"github.com/gravitational/gravity/lib/storage"
@@ -137,7 +139,11 @@ func (r *agentRunner) Run(ctx context.Context, server storage.Server, args ...st
 				args, serverName(server))
 		}
 		logger.Debug("Executing remotely: ", args)
-		err = agent.GravityCommand(ctx, logger, nil, args...)
+		var out io.Writer
+		if handler := progressHandlerFromContext(ctx); handler != nil {
+			out = &progressWriter{Writer: ioutil.Discard, handler: handler}
+		}
+		err = agent.GravityCommand(ctx, logger, out, args...)
 		return trace.Wrap(err)
 	default:
 		return trace.Errorf("internal error, canExecute=%v", canRun)
@@ -156,6 +162,36 @@ type agentRunner struct {
 	*agentCache
 }
 
+// withProgressHandler returns a new context that carries the specified
+// handler for progress reported by remote commands
+func withProgressHandler(ctx context.Context, handler progressHandler) context.Context {
+	return context.WithValue(ctx, progressHandlerKey{}, handler)
+}
+
+func progressHandlerFromContext(ctx context.Context) progressHandler {
+	handler, _ := ctx.Value(progressHandlerKey{}).(progressHandler)
+	return handler
+}
+
+// progressHandler receives structured progress reported by a remote command
+type progressHandler func(*pb.Progress)
+
+type progressHandlerKey struct{}
+
+// WriteProgress passes the progress update on to the handler.
+// Implements rpcclient.ProgressWriter
+func (r *progressWriter) WriteProgress(progress *pb.Progress) error {
+	r.handler(progress)
+	return nil
+}
+
+// progressWriter discards the command output and forwards the
+// structured progress to the handler
+type progressWriter struct {
+	io.Writer
+	handler progressHandler
+}
+
 func canExecuteOnServer(ctx context.Context, server storage.Server, runner RemoteRunner, log logrus.FieldLogger) (ExecutionCheck, error) {
 	err := systeminfo.HasInterface(server.AdvertiseIP)
 	if err == nil {
@@ -264,13 +300,14 @@ func IsMasterServer(server storage.Server) bool {
 	return server.ClusterRole == string(schema.ServiceRoleMaster)
 }
 
-// GetClientCredentials returns the RPC credentials for an update operation
+// GetClientCredentials returns the RPC credentials for an update operation.
+// The credentials are reloaded if the agent credentials are rotated
 func GetClientCredentials() (credentials.TransportCredentials, error) {
 	secretsDir, err := AgentSecretsDir()
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
-	creds, err := rpc.ClientCredentials(secretsDir)
+	creds, err := rpc.ReloadingClientCredentials(secretsDir)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -0,0 +1,174 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/tool/common"
+)
+
+// PhaseTiming describes the execution timing of a single operation phase
+type PhaseTiming struct {
+	// ID is the phase ID
+	ID string `json:"id"`
+	// Depth is the nesting level of the phase in the plan
+	Depth int `json:"-"`
+	// State is the phase state
+	State string `json:"state"`
+	// Started is the time the most recent attempt has started
+	Started time.Time `json:"started,omitempty"`
+	// Finished is the time the most recent attempt has finished
+	Finished time.Time `json:"finished,omitempty"`
+	// Duration is the duration of the most recent attempt
+	Duration time.Duration `json:"duration"`
+	// Attempts is the number of attempts to execute or roll back the phase
+	Attempts int `json:"attempts"`
+}
+
+// PlanTimeline returns the execution timing for all phases of the specified
+// plan in plan order. Durations of phases still running are computed relative
+// to the provided current time
+func PlanTimeline(plan storage.OperationPlan, now time.Time) (timeline []PhaseTiming) {
+	for _, phase := range plan.Phases {
+		timeline = append(timeline, phaseTimeline(phase, 0, now)...)
+	}
+	return timeline
+}
+
+func phaseTimeline(phase storage.OperationPhase, depth int, now time.Time) []PhaseTiming {
+	timeline := []PhaseTiming{{
+		ID:       phase.ID,
+		Depth:    depth,
+		State:    phase.GetState(),
+		Started:  phase.GetStartTime(),
+		Finished: phase.GetFinishTime(),
+		Duration: phase.GetDuration(now),
+		Attempts: phase.GetAttempts(),
+	}}
+	for _, subphase := range phase.Phases {
+		timeline = append(timeline, phaseTimeline(subphase, depth+1, now)...)
+	}
+	return timeline
+}
+
+// FormatPlanTimeline outputs the plan execution timeline as a text chart.
+// Each phase is displayed as a bar spanning the time of its most recent
+// attempt relative to the duration of the whole operation
+func FormatPlanTimeline(w io.Writer, plan storage.OperationPlan, now time.Time) {
+	timeline := PlanTimeline(plan, now)
+	start, end := timelineBounds(timeline, now)
+	var t tabwriter.Writer
+	t.Init(w, 0, 10, 3, ' ', 0)
+	common.PrintTableHeader(&t, []string{"Phase", "State", "Attempts", "Started", "Duration", "Timeline"})
+	for _, phase := range timeline {
+		fmt.Fprintf(&t, "%v%v\t%v\t%v\t%v\t%v\t|%v|\n",
+			strings.Repeat("  ", phase.Depth),
+			formatName(phase.ID),
+			formatState(phase.State),
+			phase.Attempts,
+			formatTimestampSeconds(phase.Started),
+			formatDuration(phase),
+			timelineBar(phase, start, end, now))
+	}
+	t.Flush()
+	if !start.IsZero() {
+		fmt.Fprintf(w, "\nOperation started %v, elapsed %v.\n",
+			start.Format(constants.HumanDateFormatSeconds), end.Sub(start).Truncate(time.Second))
+	}
+}
+
+// timelineBounds returns the time span of the operation covered
+// by the specified timeline
+func timelineBounds(timeline []PhaseTiming, now time.Time) (start, end time.Time) {
+	for _, phase := range timeline {
+		if phase.Started.IsZero() {
+			continue
+		}
+		if start.IsZero() || phase.Started.Before(start) {
+			start = phase.Started
+		}
+		finished := phase.Finished
+		if finished.IsZero() {
+			finished = now
+		}
+		if finished.After(end) {
+			end = finished
+		}
+	}
+	return start, end
+}
+
+// timelineBar renders the bar for the specified phase within the
+// operation time span [start, end]
+func timelineBar(phase PhaseTiming, start, end, now time.Time) string {
+	bar := []byte(strings.Repeat(" ", timelineWidth))
+	if phase.Started.IsZero() || !end.After(start) {
+		return string(bar)
+	}
+	finished := phase.Finished
+	if finished.IsZero() {
+		finished = now
+	}
+	span := float64(end.Sub(start))
+	from := int(float64(phase.Started.Sub(start)) / span * timelineWidth)
+	to := int(float64(finished.Sub(start)) / span * timelineWidth)
+	if from >= timelineWidth {
+		from = timelineWidth - 1
+	}
+	if to <= from {
+		// always display at least a single mark for a started phase
+		to = from + 1
+	}
+	if to > timelineWidth {
+		to = timelineWidth
+	}
+	mark := byte('#')
+	switch phase.State {
+	case storage.OperationPhaseStateInProgress:
+		mark = '>'
+	case storage.OperationPhaseStateFailed, storage.OperationPhaseStateRolledBack:
+		mark = 'x'
+	}
+	for i := from; i < to; i++ {
+		bar[i] = mark
+	}
+	return string(bar)
+}
+
+func formatDuration(phase PhaseTiming) string {
+	if phase.Started.IsZero() {
+		return "-"
+	}
+	return phase.Duration.Truncate(time.Second).String()
+}
+
+func formatTimestampSeconds(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(constants.HumanDateFormatSeconds)
+}
+
+// timelineWidth is the width of the timeline chart in characters
+const timelineWidth = 40
@@ -0,0 +1,90 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/gravitational/gravity/lib/storage"
+
+	"gopkg.in/check.v1"
+)
+
+type TimelineSuite struct{}
+
+var _ = check.Suite(&TimelineSuite{})
+
+func (s *TimelineSuite) TestResolvesPhaseTiming(c *check.C) {
+	start := time.Date(2019, time.January, 1, 10, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+	changelog := storage.PlanChangelog{
+		{PhaseID: "/masters/node-1", NewState: storage.OperationPhaseStateInProgress, Created: at(0)},
+		{PhaseID: "/masters/node-1", NewState: storage.OperationPhaseStateFailed, Created: at(10)},
+		// changes are not necessarily ordered
+		{PhaseID: "/masters/node-1", NewState: storage.OperationPhaseStateCompleted, Created: at(50)},
+		{PhaseID: "/masters/node-1", NewState: storage.OperationPhaseStateInProgress, Created: at(20)},
+		{PhaseID: "/app", NewState: storage.OperationPhaseStateInProgress, Created: at(60)},
+	}
+	plan := ResolvePlan(newTestPlan("1.0.0", "node-1"), changelog)
+
+	masters := plan.Phases[0]
+	node := masters.Phases[0]
+	c.Assert(node.Started, check.Equals, at(20))
+	c.Assert(node.Finished, check.Equals, at(50))
+	c.Assert(node.Attempts, check.Equals, 2)
+	c.Assert(masters.GetStartTime(), check.Equals, at(20))
+	c.Assert(masters.GetFinishTime(), check.Equals, at(50))
+	c.Assert(masters.GetDuration(at(100)), check.Equals, 30*time.Second)
+	c.Assert(masters.GetAttempts(), check.Equals, 2)
+
+	app := plan.Phases[1]
+	c.Assert(app.Started, check.Equals, at(60))
+	c.Assert(app.Finished.IsZero(), check.Equals, true)
+	c.Assert(app.Attempts, check.Equals, 1)
+	c.Assert(app.GetDuration(at(100)), check.Equals, 40*time.Second)
+}
+
+func (s *TimelineSuite) TestFormatsTimeline(c *check.C) {
+	start := time.Date(2019, time.January, 1, 10, 0, 0, 0, time.UTC)
+	plan := newTestPlan("1.0.0", "node-1")
+	node := &plan.Phases[0].Phases[0]
+	node.State = storage.OperationPhaseStateCompleted
+	node.Started = start
+	node.Finished = start.Add(time.Minute)
+	node.Attempts = 1
+	app := &plan.Phases[1]
+	app.State = storage.OperationPhaseStateInProgress
+	app.Started = start.Add(time.Minute)
+	app.Attempts = 1
+
+	var buf bytes.Buffer
+	FormatPlanTimeline(&buf, plan, start.Add(2*time.Minute))
+	bars := make(map[string]string)
+	for _, line := range strings.Split(buf.String(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !strings.Contains(line, "|") {
+			continue
+		}
+		bars[fields[0]] = line[strings.Index(line, "|"):]
+	}
+	half := strings.Repeat(" ", timelineWidth/2)
+	c.Assert(bars["node-1"], check.Equals, "|"+strings.Repeat("#", timelineWidth/2)+half+"|")
+	c.Assert(bars["app"], check.Equals, "|"+half+strings.Repeat(">", timelineWidth/2)+"|")
+	c.Assert(strings.Contains(buf.String(), "elapsed 2m0s"), check.Equals, true, check.Commentf(buf.String()))
+}
@@ -17,9 +17,16 @@ limitations under the License.
 package fsm
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/gravitational/gravity/lib/ops"
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
 	"github.com/gravitational/gravity/lib/schema"
 	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/utils"
 
 	"github.com/gravitational/trace"
 )
@@ -90,6 +97,43 @@ func FindPhase(plan *storage.OperationPlan, phaseID string) (*storage.OperationP
 	return nil, trace.NotFound("phase %q not found", phaseID)
 }
 
+// RemoteProgressEntry returns a progress entry for the progress reported
+// by the specified phase executing on the given server.
+// The completion reported by the phase is scaled to the share of the phase
+// in the overall operation progress.
+// Fields reported by the phase are appended to the message
+func RemoteProgressEntry(plan storage.OperationPlan, phase storage.OperationPhase, server storage.Server, progress pb.Progress) ops.ProgressEntry {
+	stepCompletion := 100 / utils.Max(len(plan.Phases), 1)
+	completion := utils.Min(utils.Max(int(progress.Completion), 0), 100)
+	return ops.ProgressEntry{
+		SiteDomain:  plan.ClusterName,
+		OperationID: plan.OperationID,
+		Completion:  stepCompletion*phase.Step + stepCompletion*completion/100,
+		Step:        phase.Step,
+		State:       ops.ProgressStateInProgress,
+		Message:     fmt.Sprintf("%v: %v", server.Hostname, formatProgressMessage(progress)),
+		Created:     time.Now().UTC(),
+	}
+}
+
+// formatProgressMessage returns the message of the specified progress
+// with its fields appended in sorted order
+func formatProgressMessage(progress pb.Progress) string {
+	if len(progress.Fields) == 0 {
+		return progress.Message
+	}
+	keys := make([]string, 0, len(progress.Fields))
+	for key := range progress.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fields := make([]string, 0, len(keys))
+	for _, key := range keys {
+		fields = append(fields, fmt.Sprintf("%v=%v", key, progress.Fields[key]))
+	}
+	return fmt.Sprintf("%v (%v)", progress.Message, strings.Join(fields, ", "))
+}
+
 // FlattenPlan returns a slice of pointers to all phases of the provided plan
 func FlattenPlan(plan *storage.OperationPlan) []*storage.OperationPhase {
 	var result []*storage.OperationPhase
@@ -113,7 +157,10 @@ func SplitServers(servers []storage.Server) (masters, nodes []storage.Server) {
 	return masters, nodes
 }
 
-// ResolvePlan applies changelog to the provided plan and returns the resulting plan
+// ResolvePlan applies changelog to the provided plan and returns the resulting plan.
+// Besides the phase state, the execution timing (start and finish times of the
+// most recent attempt as well as the number of attempts) is derived from the
+// changelog for each phase
 func ResolvePlan(plan storage.OperationPlan, changelog storage.PlanChangelog) *storage.OperationPlan {
 	allPhases := FlattenPlan(&plan)
 	for i, phase := range allPhases {
@@ -123,6 +170,8 @@ func ResolvePlan(plan storage.OperationPlan, changelog storage.PlanChangelog) *s
 			allPhases[i].Updated = latest.Created
 			allPhases[i].Error = latest.Error
 		}
+		allPhases[i].Started, allPhases[i].Finished, allPhases[i].Attempts =
+			changelog.PhaseTiming(phase.ID)
 	}
 	return &plan
 }
@@ -0,0 +1,81 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"gopkg.in/check.v1"
+)
+
+type UtilsSuite struct{}
+
+var _ = check.Suite(&UtilsSuite{})
+
+func (s *UtilsSuite) TestRemoteProgressEntryScalesCompletion(c *check.C) {
+	plan := storage.OperationPlan{
+		OperationID: "operation-1",
+		ClusterName: "example.com",
+		Phases: []storage.OperationPhase{
+			{ID: "/init", Step: 0},
+			{ID: "/masters", Step: 1},
+			{ID: "/nodes", Step: 2},
+			{ID: "/app", Step: 3},
+		},
+	}
+	server := storage.Server{Hostname: "node-1"}
+	var tests = []struct {
+		completion int32
+		expected   int
+		comment    string
+	}{
+		{completion: 0, expected: 25, comment: "phase started"},
+		{completion: 50, expected: 37, comment: "phase half-way"},
+		{completion: 100, expected: 50, comment: "phase completed"},
+		{completion: 150, expected: 50, comment: "completion is capped"},
+		{completion: -10, expected: 25, comment: "negative completion is ignored"},
+	}
+	for _, tt := range tests {
+		entry := RemoteProgressEntry(plan, plan.Phases[1], server, pb.Progress{
+			Completion: tt.completion,
+			Message:    "Draining node",
+		})
+		comment := check.Commentf(tt.comment)
+		c.Assert(entry.Completion, check.Equals, tt.expected, comment)
+		c.Assert(entry.Step, check.Equals, 1, comment)
+		c.Assert(entry.Message, check.Equals, "node-1: Draining node", comment)
+		c.Assert(entry.SiteDomain, check.Equals, "example.com", comment)
+		c.Assert(entry.OperationID, check.Equals, "operation-1", comment)
+	}
+}
+
+func (s *UtilsSuite) TestRemoteProgressEntryIncludesFields(c *check.C) {
+	plan := storage.OperationPlan{
+		Phases: []storage.OperationPhase{{ID: "/pull", Step: 0}},
+	}
+	entry := RemoteProgressEntry(plan, plan.Phases[0], storage.Server{Hostname: "node-1"}, pb.Progress{
+		Completion: 50,
+		Message:    "Pulled configured package",
+		Fields: map[string]string{
+			"total":  "4",
+			"pulled": "2",
+		},
+	})
+	c.Assert(entry.Completion, check.Equals, 50)
+	c.Assert(entry.Message, check.Equals, "node-1: Pulled configured package (pulled=2, total=4)")
+}
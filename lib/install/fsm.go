@@ -29,6 +29,7 @@ import (
 	"github.com/gravitational/gravity/lib/ops/opsclient"
 	"github.com/gravitational/gravity/lib/pack"
 	"github.com/gravitational/gravity/lib/rpc"
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/utils"
 
@@ -161,6 +162,7 @@ func NewFSM(config FSMConfig) (*fsm.FSM, error) {
 	// a phase executes
 	if config.ReportProgress {
 		fsm.SetPreExec(engine.UpdateProgress)
+		fsm.SetProgressHook(engine.ReportProgress)
 	}
 	return fsm, nil
 }
@@ -192,6 +194,21 @@ func (f *fsmEngine) UpdateProgress(ctx context.Context, p fsm.Params) error {
 	return nil
 }
 
+// ReportProgress creates a progress entry in the operator for the progress
+// reported by the phase executing on the specified server
+func (f *fsmEngine) ReportProgress(ctx context.Context, p fsm.Params, server storage.Server, progress pb.Progress) error {
+	plan, err := f.GetPlan()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	phase, err := fsm.FindPhase(plan, p.PhaseID)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	entry := fsm.RemoteProgressEntry(*plan, *phase, server, progress)
+	return trace.Wrap(f.Operator.CreateProgressEntry(f.OperationKey, entry))
+}
+
 // Complete marks the install operation as either completed or failed based
 // on the state of the operation plan
 func (f *fsmEngine) Complete(fsmErr error) error {
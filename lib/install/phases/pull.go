@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strconv"
 
 	"github.com/gravitational/gravity/lib/app"
 	"github.com/gravitational/gravity/lib/app/service"
@@ -29,8 +30,12 @@ import (
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/pack/signing"
+	"github.com/gravitational/gravity/lib/rpc"
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
 	"github.com/gravitational/gravity/lib/schema"
 	"github.com/gravitational/gravity/lib/state"
+	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/systeminfo"
 	"github.com/gravitational/gravity/lib/utils"
 
@@ -61,6 +66,15 @@ func NewPull(p fsm.ExecutorParams, operator ops.Operator, wizardPack, localPack
 		return nil, trace.Wrap(err)
 	}
 
+	keys, err := trustedKeys(p.Plan)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	verifier, err := signing.NewClusterVerifier(keys, p.Plan.ClusterName)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
 	logger := &fsm.Logger{
 		FieldLogger: logrus.WithFields(logrus.Fields{
 			constants.FieldPhase:       p.Phase.ID,
@@ -81,6 +95,7 @@ func NewPull(p fsm.ExecutorParams, operator ops.Operator, wizardPack, localPack
 		ServiceUser:    *serviceUser,
 		runtimePackage: *runtimePackage,
 		remote:         remote,
+		verifier:       verifier,
 	}, nil
 }
 
@@ -103,6 +118,9 @@ type pullExecutor struct {
 	remote fsm.Remote
 	// runtimePackage specifies the runtime container package to pull
 	runtimePackage loc.Locator
+	// verifier verifies signatures of the pulled packages.
+	// Nil if no trusted keys have been configured
+	verifier pack.Verifier
 }
 
 // Execute executes the pull phase
@@ -149,6 +167,7 @@ func (p *pullExecutor) pullUserApplication() error {
 		SrcApp:      p.WizardApps,
 		DstApp:      p.LocalApps,
 		Package:     *p.Phase.Data.Package,
+		Verifier:    p.verifier,
 	})
 	if err != nil {
 		return trace.Wrap(err)
@@ -213,16 +232,26 @@ func (p *pullExecutor) pullConfiguredPackages() (err error) {
 	if err != nil {
 		return trace.Wrap(err)
 	}
-	for _, e := range envelopes {
+	for i, e := range envelopes {
 		_, err := service.PullPackage(service.PackagePullRequest{
-			SrcPack: p.WizardPackages,
-			DstPack: p.LocalPackages,
-			Package: e.Locator,
-			Labels:  e.RuntimeLabels,
+			SrcPack:  p.WizardPackages,
+			DstPack:  p.LocalPackages,
+			Package:  e.Locator,
+			Labels:   e.RuntimeLabels,
+			Verifier: p.verifier,
 		})
 		if err != nil {
 			return trace.Wrap(err)
 		}
+		rpc.ReportProgress(p.Progress, pb.Progress{
+			Completion: int32(100 * (i + 1) / len(envelopes)),
+			Message:    "Pulled configured package",
+			Fields: map[string]string{
+				"package": e.Locator.String(),
+				"pulled":  strconv.Itoa(i + 1),
+				"total":   strconv.Itoa(len(envelopes)),
+			},
+		})
 		if isSecret(e) {
 			err := p.unpackSecrets(e)
 			if err != nil {
@@ -359,3 +388,29 @@ func (*pullExecutor) PostCheck(ctx context.Context) error {
 func isSecret(e pack.PackageEnvelope) bool {
 	return e.HasLabel(pack.PurposeLabel, pack.PurposePlanetSecrets)
 }
+
+// trustedKeys returns the keys trusted to sign packages from the
+// user-supplied cluster resources in the specified install plan
+func trustedKeys(plan storage.OperationPlan) (keys []storage.TrustedKey, err error) {
+	phase, err := fsm.FindPhase(&plan, GravityResourcesPhase)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, trace.Wrap(err)
+	}
+	if phase.Data == nil || phase.Data.Install == nil {
+		return nil, nil
+	}
+	for _, resource := range phase.Data.Install.GravityResources {
+		if storage.CanonicalKind(resource.Kind) != storage.KindTrustedKey {
+			continue
+		}
+		key, err := storage.UnmarshalTrustedKey(resource.Raw)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -18,9 +18,14 @@ package kubernetes
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/utils"
 
 	"github.com/cenkalti/backoff"
@@ -32,6 +37,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -53,6 +59,9 @@ func (d *drain) drainPods(ctx context.Context) error {
 		log.Warningf("error deleting pods: %v\npending pods: %v",
 			trace.DebugReport(err), formatPodList(pendingPods))
 	}
+	if err != nil && d.isAborted() {
+		return trace.Wrap(&DrainBlockedError{Blocked: d.getBlocked()})
+	}
 	return trace.Wrap(err)
 }
 
@@ -92,7 +101,9 @@ func (d *drain) evictPods(ctx context.Context, pods []v1.Pod, policyGroupVersion
 }
 
 func (d *drain) evictPodAndWait(ctx context.Context, pod v1.Pod, policyGroupVersion string) error {
-	b := backoff.NewExponentialBackOff()
+	// blocked evictions are retried until the policy timeout or
+	// until the drain times out if the policy is to wait
+	b := utils.NewUnlimitedExponentialBackOff()
 	err := utils.RetryWithInterval(ctx, b,
 		func() error {
 			err := d.evictPod(pod, policyGroupVersion)
@@ -102,13 +113,28 @@ func (d *drain) evictPodAndWait(ctx context.Context, pod v1.Pod, policyGroupVers
 			if errors.IsNotFound(trace.Unwrap(err)) {
 				return nil
 			} else if errors.IsTooManyRequests(trace.Unwrap(err)) {
-				return trace.Retry(err, "too many requests")
+				// eviction API responds with 429 if the eviction would
+				// violate a pod disruption budget
+				blocked := d.setBlocked(pod)
+				if d.policy.Action == storage.DrainActionWait || time.Since(blocked.Since) < d.policy.Timeout {
+					return trace.Retry(err, "too many requests")
+				}
+				return &backoff.PermanentError{Err: trace.LimitExceeded(
+					"eviction of pod %v has been blocked for over %v", formatPod(pod), d.policy.Timeout)}
 			}
 			return &backoff.PermanentError{Err: rigging.ConvertError(err)}
 		})
+	if err != nil && trace.IsLimitExceeded(err) {
+		if d.policy.Action == storage.DrainActionForce {
+			return trace.Wrap(d.forceDeletePod(ctx, pod))
+		}
+		d.setAborted()
+		return trace.Wrap(err)
+	}
 	if err != nil {
 		return trace.Wrap(err, "error evicting pod %v", formatPod(pod))
 	}
+	d.clearBlocked(pod)
 
 	// Set the timeout before pod termination based on how long we expect kubernetes to take
 	// with a safe margin of error
@@ -121,6 +147,30 @@ func (d *drain) evictPodAndWait(ctx context.Context, pod v1.Pod, policyGroupVers
 	if err != nil {
 		return trace.Wrap(err, "error waiting for pod %v to terminate", formatPod(pod))
 	}
+	d.addResult(func(r *DrainResult) {
+		r.Evicted = append(r.Evicted, formatPod(pod))
+	})
+	return nil
+}
+
+// forceDeletePod deletes the pod whose eviction has been blocked
+// by disruption budgets for longer than the policy allows
+func (d *drain) forceDeletePod(ctx context.Context, pod v1.Pod) error {
+	blocked := d.clearBlocked(pod)
+	log.WithFields(podFields(pod)).Warnf("Deleting pod with blocked eviction: %v.", blocked)
+	err := d.deletePod(pod)
+	if err != nil && !errors.IsNotFound(trace.Unwrap(err)) {
+		return rigging.ConvertError(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, terminationWaitPeriod(pod))
+	defer cancel()
+	_, err = waitForDelete(ctx, d.client.CoreV1(), []v1.Pod{pod}, usingEviction(false))
+	if err != nil {
+		return trace.Wrap(err, "error waiting for pod %v to terminate", formatPod(pod))
+	}
+	d.addResult(func(r *DrainResult) {
+		r.ForceDeleted = append(r.ForceDeleted, *blocked)
+	})
 	return nil
 }
 
@@ -132,7 +182,13 @@ func (d *drain) deletePods(ctx context.Context, pods []v1.Pod) error {
 		}
 	}
 	_, err := waitForDelete(ctx, d.client.CoreV1(), pods, usingEviction(false))
-	return trace.Wrap(err)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	d.addResult(func(r *DrainResult) {
+		r.Deleted = append(r.Deleted, formatPodList(pods)...)
+	})
+	return nil
 }
 
 func (d *drain) deletePod(pod v1.Pod) error {
@@ -210,8 +266,210 @@ func (d *drain) canDeletePod(pod v1.Pod) (remove bool, err error) {
 	return true, nil
 }
 
+// setBlocked records that the eviction of the specified pod is blocked
+// and returns the record. The disruption budgets that block the eviction
+// are looked up the first time the pod is blocked
+func (d *drain) setBlocked(pod v1.Pod) BlockedEviction {
+	d.mu.Lock()
+	blocked, ok := d.blocked[formatPod(pod)]
+	d.mu.Unlock()
+	if ok {
+		return blocked
+	}
+	budgets, err := d.getBlockingBudgets(pod)
+	if err != nil {
+		log.WithFields(podFields(pod)).Warnf("Failed to query disruption budgets: %v.", trace.DebugReport(err))
+	}
+	blocked = BlockedEviction{
+		Pod:     formatPod(pod),
+		Budgets: budgets,
+		Since:   time.Now(),
+	}
+	log.WithFields(podFields(pod)).Infof("Eviction blocked: %v.", blocked)
+	d.mu.Lock()
+	if d.blocked == nil {
+		d.blocked = make(map[string]BlockedEviction)
+	}
+	d.blocked[blocked.Pod] = blocked
+	d.mu.Unlock()
+	d.notifyBlocked()
+	return blocked
+}
+
+// clearBlocked removes the blocked eviction record for the specified pod
+// and returns it if the pod had been blocked
+func (d *drain) clearBlocked(pod v1.Pod) *BlockedEviction {
+	d.mu.Lock()
+	blocked, ok := d.blocked[formatPod(pod)]
+	delete(d.blocked, formatPod(pod))
+	d.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	d.notifyBlocked()
+	return &blocked
+}
+
+// getBlocked returns all currently blocked evictions sorted by pod
+func (d *drain) getBlocked() []BlockedEviction {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	result := make([]BlockedEviction, 0, len(d.blocked))
+	for _, blocked := range d.blocked {
+		result = append(result, blocked)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Pod < result[j].Pod
+	})
+	return result
+}
+
+func (d *drain) notifyBlocked() {
+	if d.onBlocked != nil {
+		d.onBlocked(d.getBlocked())
+	}
+}
+
+func (d *drain) setAborted() {
+	d.mu.Lock()
+	d.aborted = true
+	d.mu.Unlock()
+}
+
+func (d *drain) isAborted() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.aborted
+}
+
+func (d *drain) addResult(fn func(*DrainResult)) {
+	d.mu.Lock()
+	fn(&d.result)
+	d.mu.Unlock()
+}
+
+// getResult returns the outcome of the drain
+func (d *drain) getResult() *DrainResult {
+	d.mu.Lock()
+	result := d.result
+	d.mu.Unlock()
+	result.Blocked = d.getBlocked()
+	return &result
+}
+
+// getBlockingBudgets returns the disruption budgets that select the specified pod
+func (d *drain) getBlockingBudgets(pod v1.Pod) ([]string, error) {
+	budgets, err := d.client.PolicyV1beta1().PodDisruptionBudgets(pod.Namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, rigging.ConvertError(err)
+	}
+	return matchingBudgets(pod, budgets.Items), nil
+}
+
+// matchingBudgets returns descriptions of the budgets from the provided
+// list that select the specified pod
+func matchingBudgets(pod v1.Pod, budgets []policy.PodDisruptionBudget) (result []string) {
+	for _, budget := range budgets {
+		if budget.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(budget.Spec.Selector)
+		if err != nil {
+			log.Warnf("Invalid selector in disruption budget %v/%v: %v.",
+				budget.Namespace, budget.Name, err)
+			continue
+		}
+		// an empty selector matches no pods
+		if selector.Empty() || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		result = append(result, fmt.Sprintf("%v/%v (%v disruptions allowed, %v/%v pods healthy)",
+			budget.Namespace, budget.Name, budget.Status.PodDisruptionsAllowed,
+			budget.Status.CurrentHealthy, budget.Status.DesiredHealthy))
+	}
+	return result
+}
+
+// BlockedEviction describes a pod whose eviction is blocked
+// by pod disruption budgets
+type BlockedEviction struct {
+	// Pod is the pod name in namespace/name format
+	Pod string
+	// Budgets describes the disruption budgets that select the pod
+	Budgets []string
+	// Since is the time the eviction was first blocked
+	Since time.Time
+}
+
+// String returns a textual representation of this blocked eviction
+func (r BlockedEviction) String() string {
+	if len(r.Budgets) == 0 {
+		return fmt.Sprintf("pod %v blocked by disruption budget", r.Pod)
+	}
+	return fmt.Sprintf("pod %v blocked by disruption budget %v",
+		r.Pod, strings.Join(r.Budgets, ", "))
+}
+
+// DrainResult describes the outcome of a node drain
+type DrainResult struct {
+	// Evicted lists pods that have been evicted
+	Evicted []string
+	// Deleted lists pods that have been deleted on API servers
+	// without eviction support
+	Deleted []string
+	// ForceDeleted lists pods that have been deleted after their eviction
+	// remained blocked for longer than the drain policy allows
+	ForceDeleted []BlockedEviction
+	// Blocked lists pods whose eviction was still blocked when the drain ended
+	Blocked []BlockedEviction
+}
+
+// String returns a textual representation of this drain result
+func (r DrainResult) String() string {
+	var parts []string
+	if len(r.Evicted) != 0 {
+		parts = append(parts, fmt.Sprintf("evicted %v pod(s)", len(r.Evicted)))
+	}
+	if len(r.Deleted) != 0 {
+		parts = append(parts, fmt.Sprintf("deleted %v pod(s)", len(r.Deleted)))
+	}
+	for _, blocked := range r.ForceDeleted {
+		parts = append(parts, fmt.Sprintf("force-deleted %v", blocked))
+	}
+	for _, blocked := range r.Blocked {
+		parts = append(parts, fmt.Sprintf("eviction of %v", blocked))
+	}
+	if len(parts) == 0 {
+		return "no pods to remove"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// DrainBlockedError is returned when the drain has been aborted because
+// pod evictions remained blocked by disruption budgets
+type DrainBlockedError struct {
+	// Blocked lists the blocked evictions
+	Blocked []BlockedEviction
+}
+
+// Error returns the error message listing the blocked evictions
+func (e *DrainBlockedError) Error() string {
+	blocked := make([]string, 0, len(e.Blocked))
+	for _, eviction := range e.Blocked {
+		blocked = append(blocked, eviction.String())
+	}
+	return fmt.Sprintf("drain aborted, evictions are blocked: %v", strings.Join(blocked, "; "))
+}
+
+// IsDrainBlockedError returns true if the specified error
+// indicates that the drain has been aborted due to blocked evictions
+func IsDrainBlockedError(err error) bool {
+	_, ok := trace.Unwrap(err).(*DrainBlockedError)
+	return ok
+}
+
 type drain struct {
-	client   *kubernetes.Clientset
+	client   kubernetes.Interface
 	nodeName string
 	// gracePeriodSeconds defines the grace period for eviction.
 	// -1 means default grace period defined for a pod is used
@@ -219,6 +477,19 @@ type drain struct {
 	// timeout sets the timeout for the operation.
 	// zero value means no timeout
 	timeout time.Duration
+	// policy defines how evictions blocked by disruption budgets are handled
+	policy storage.DrainPolicy
+	// onBlocked is invoked with all blocked evictions every time they change
+	onBlocked func([]BlockedEviction)
+
+	// mu guards the fields below
+	mu sync.Mutex
+	// blocked maps pods to their blocked evictions
+	blocked map[string]BlockedEviction
+	// aborted is set when blocked evictions fail the drain
+	aborted bool
+	// result is the drain outcome
+	result DrainResult
 }
 
 // queryEvictionPolicyGroupVersion uses Discovery API to find out if the server supports eviction subresource.
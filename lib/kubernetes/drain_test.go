@@ -0,0 +1,98 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"github.com/gravitational/gravity/lib/storage"
+
+	. "gopkg.in/check.v1"
+	"k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type DrainSuite struct{}
+
+var _ = Suite(&DrainSuite{})
+
+func (s *DrainSuite) TestMatchesBlockingBudgets(c *C) {
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db-0",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "db", "tier": "backend"},
+		},
+	}
+	budgets := []policy.PodDisruptionBudget{
+		newBudget("db", map[string]string{"app": "db"}, 0, 2, 3),
+		newBudget("web", map[string]string{"app": "web"}, 1, 3, 2),
+		newBudget("backend", map[string]string{"tier": "backend"}, 1, 5, 4),
+		newBudget("empty", map[string]string{}, 0, 0, 0),
+	}
+	c.Assert(matchingBudgets(pod, budgets), DeepEquals, []string{
+		"default/db (0 disruptions allowed, 2/3 pods healthy)",
+		"default/backend (1 disruptions allowed, 5/4 pods healthy)",
+	})
+}
+
+func (s *DrainSuite) TestFormatsDrainOutcome(c *C) {
+	blocked := BlockedEviction{
+		Pod:     "default/db-0",
+		Budgets: []string{"default/db (0 disruptions allowed, 2/3 pods healthy)"},
+	}
+	c.Assert(DrainResult{}.String(), Equals, "no pods to remove")
+	c.Assert(DrainResult{
+		Evicted:      []string{"default/web-0", "default/web-1"},
+		ForceDeleted: []BlockedEviction{blocked},
+	}.String(), Equals, "evicted 2 pod(s); force-deleted pod default/db-0 "+
+		"blocked by disruption budget default/db (0 disruptions allowed, 2/3 pods healthy)")
+
+	err := &DrainBlockedError{Blocked: []BlockedEviction{blocked}}
+	c.Assert(err.Error(), Equals, "drain aborted, evictions are blocked: pod default/db-0 "+
+		"blocked by disruption budget default/db (0 disruptions allowed, 2/3 pods healthy)")
+	c.Assert(IsDrainBlockedError(err), Equals, true)
+}
+
+func (s *DrainSuite) TestDrainConfigDefaults(c *C) {
+	config := DrainConfig{NodeName: "node-1"}
+	c.Assert(config.CheckAndSetDefaults(), NotNil)
+
+	config.Client = &kubernetes.Clientset{}
+	c.Assert(config.CheckAndSetDefaults(), IsNil)
+	c.Assert(config.Policy.Action, Equals, storage.DrainActionWait)
+
+	config.Policy = storage.DrainPolicy{Action: "ignore"}
+	c.Assert(config.CheckAndSetDefaults(), NotNil)
+}
+
+func newBudget(name string, selector map[string]string, allowed, current, desired int32) policy.PodDisruptionBudget {
+	return policy.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: policy.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+		},
+		Status: policy.PodDisruptionBudgetStatus{
+			PodDisruptionsAllowed: allowed,
+			CurrentHealthy:        current,
+			DesiredHealthy:        desired,
+		},
+	}
+}
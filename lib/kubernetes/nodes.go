@@ -35,18 +35,65 @@ import (
 
 // Drain safely drains the specified node and uses Eviction API if supported on the api server.
 func Drain(ctx context.Context, client *kubernetes.Clientset, nodeName string) error {
-	err := SetUnschedulable(ctx, client.CoreV1().Nodes(), nodeName, true)
+	_, err := DrainNode(ctx, DrainConfig{
+		Client:   client,
+		NodeName: nodeName,
+	})
+	return trace.Wrap(err)
+}
+
+// DrainConfig defines the configuration of a node drain
+type DrainConfig struct {
+	// Client is the Kubernetes API client
+	Client kubernetes.Interface
+	// NodeName is the name of the node to drain
+	NodeName string
+	// Policy defines how evictions blocked by pod disruption budgets are handled
+	Policy storage.DrainPolicy
+	// OnBlocked is optionally invoked with the list of all blocked
+	// evictions every time the list changes
+	OnBlocked func([]BlockedEviction)
+}
+
+// CheckAndSetDefaults validates the config and sets default values
+func (r *DrainConfig) CheckAndSetDefaults() error {
+	if r.Client == nil {
+		return trace.BadParameter("missing Client")
+	}
+	if r.NodeName == "" {
+		return trace.BadParameter("missing NodeName")
+	}
+	if r.Policy.Action == "" {
+		r.Policy.Action = storage.DrainActionWait
+	}
+	if r.Policy.Timeout == 0 {
+		r.Policy.Timeout = defaults.DrainBlockedTimeout
+	}
+	return trace.Wrap(r.Policy.Check())
+}
+
+// DrainNode safely drains the specified node and uses Eviction API if supported on the api server.
+// Evictions blocked by pod disruption budgets are handled according to the configured policy.
+//
+// Returns the outcome of the drain which is also returned if the drain fails
+func DrainNode(ctx context.Context, config DrainConfig) (*DrainResult, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	err := SetUnschedulable(ctx, config.Client.CoreV1().Nodes(), config.NodeName, true)
 	if err != nil {
-		return trace.Wrap(err)
+		return nil, trace.Wrap(err)
 	}
 
-	d := drain{
-		client:             client,
-		nodeName:           nodeName,
+	d := &drain{
+		client:             config.Client,
+		nodeName:           config.NodeName,
 		gracePeriodSeconds: defaults.ResourceGracePeriod,
+		policy:             config.Policy,
+		onBlocked:          config.OnBlocked,
 	}
 	err = d.drainPods(ctx)
-	return trace.Wrap(err)
+	return d.getResult(), trace.Wrap(err)
 }
 
 // SetUnschedulable marks the specified node as unschedulable depending on the value of the specified flag.
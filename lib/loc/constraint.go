@@ -0,0 +1,70 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loc
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/gravitational/trace"
+)
+
+// ParseVersionConstraint parses the version range constraint specified with s,
+// for example "~1.4" or ">=2.0 <3.0".
+//
+// Space-separated constraints must all be satisfied, while constraints
+// separated with "||" are alternatives
+func ParseVersionConstraint(s string) (*VersionConstraint, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, trace.BadParameter("version constraint can not be empty")
+	}
+	constraints, err := semver.NewConstraint(andRe.ReplaceAllString(s, "$1, $2"))
+	if err != nil {
+		return nil, trace.BadParameter("invalid version constraint %q: %v", s, err)
+	}
+	return &VersionConstraint{
+		constraints: constraints,
+		raw:         s,
+	}, nil
+}
+
+// VersionConstraint is a semantic version range constraint
+type VersionConstraint struct {
+	constraints *semver.Constraints
+	raw         string
+}
+
+// Check returns true if the specified version satisfies the constraint.
+// Versions not in semver format never satisfy the constraint
+func (r VersionConstraint) Check(version string) bool {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+	return r.constraints.Check(v)
+}
+
+// String returns the constraint as it was specified
+func (r VersionConstraint) String() string {
+	return r.raw
+}
+
+// andRe matches whitespace between two constraints that should
+// both be satisfied
+var andRe = regexp.MustCompile(`([^\s,|])\s+([<>=!~^])`)
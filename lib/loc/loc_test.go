@@ -155,3 +155,41 @@ func (s *LocatorSuite) TestDeduplicate(c *C) {
 	}
 	c.Assert(uniq, compare.DeepEquals, expected)
 }
+
+func (s *LocatorSuite) TestVersionConstraints(c *C) {
+	tcs := []struct {
+		constraint string
+		matches    []string
+		mismatches []string
+	}{
+		{
+			constraint: "~1.4",
+			matches:    []string{"1.4.0", "1.4.7"},
+			mismatches: []string{"1.3.9", "1.5.0", "invalid"},
+		},
+		{
+			constraint: ">=2.0 <3.0",
+			matches:    []string{"2.0.0", "2.9.1"},
+			mismatches: []string{"1.9.9", "3.0.0"},
+		},
+		{
+			constraint: "1.0.0 || >= 5.5",
+			matches:    []string{"1.0.0", "5.5.1"},
+			mismatches: []string{"1.0.1", "5.4.0"},
+		},
+	}
+	for _, tc := range tcs {
+		constraint, err := ParseVersionConstraint(tc.constraint)
+		c.Assert(err, IsNil)
+		c.Assert(constraint.String(), Equals, tc.constraint)
+		for _, version := range tc.matches {
+			c.Assert(constraint.Check(version), Equals, true, Commentf("%v should match %v.", version, tc.constraint))
+		}
+		for _, version := range tc.mismatches {
+			c.Assert(constraint.Check(version), Equals, false, Commentf("%v should not match %v.", version, tc.constraint))
+		}
+	}
+
+	_, err := ParseVersionConstraint(">=2.0 <")
+	c.Assert(err, NotNil)
+}
@@ -382,7 +382,7 @@ func (env *LocalEnvironment) PackageService(opsCenterURL string, options ...http
 		return nil, trace.Wrap(err)
 	}
 
-	return client, nil
+	return client.WithCheckpointDir(env.downloadsDir()), nil
 }
 
 // CurrentLogin returns the login entry for the cluster this environment
@@ -413,8 +413,17 @@ func (env *LocalEnvironment) CurrentPackages(options ...httplib.ClientOption) (p
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
-	return newPackClient(*entry, entry.OpsCenterURL,
+	client, err := newPackClient(*entry, entry.OpsCenterURL,
 		roundtrip.HTTPClient(env.HTTPClient(options...)))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.WithCheckpointDir(env.downloadsDir()), nil
+}
+
+// downloadsDir returns the directory with partially downloaded packages
+func (env *LocalEnvironment) downloadsDir() string {
+	return filepath.Join(env.StateDir, defaults.PackagesDir, defaults.DownloadsDir)
 }
 
 // CurrentApps returns app service for the current login entry
@@ -620,7 +629,7 @@ func NewOpsClient(entry users.LoginEntry, opsCenterURL string, params ...opsclie
 	return client, trace.Wrap(err)
 }
 
-func newPackClient(entry users.LoginEntry, opsCenterURL string, params ...roundtrip.ClientParam) (client pack.PackageService, err error) {
+func newPackClient(entry users.LoginEntry, opsCenterURL string, params ...roundtrip.ClientParam) (client *webpack.Client, err error) {
 	if entry.Email != "" {
 		client, err = webpack.NewAuthenticatedClient(
 			opsCenterURL, entry.Email, entry.Password, params...)
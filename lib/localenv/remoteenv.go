@@ -196,17 +196,20 @@ func (w *RemoteEnvironment) clearWizardEntry() error {
 
 func (w *RemoteEnvironment) init(entry storage.LoginEntry) error {
 	var err error
+	var packages *webpack.Client
 	httpClient := httplib.GetClient(true)
 	if entry.Email != "" {
-		w.Packages, err = webpack.NewAuthenticatedClient(
+		packages, err = webpack.NewAuthenticatedClient(
 			entry.OpsCenterURL, entry.Email, entry.Password, roundtrip.HTTPClient(httpClient))
 	} else {
-		w.Packages, err = webpack.NewBearerClient(
+		packages, err = webpack.NewBearerClient(
 			entry.OpsCenterURL, entry.Password, roundtrip.HTTPClient(httpClient))
 	}
 	if err != nil {
 		return trace.Wrap(err)
 	}
+	w.Packages = packages.WithCheckpointDir(
+		filepath.Join(w.StateDir, defaults.PackagesDir, defaults.DownloadsDir))
 	if entry.Email != "" {
 		w.Apps, err = client.NewAuthenticatedClient(
 			entry.OpsCenterURL, entry.Email, entry.Password, client.HTTPClient(httpClient))
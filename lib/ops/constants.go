@@ -140,6 +140,26 @@ const (
 	OperationStateCompleted = "completed"
 	OperationStateFailed    = "failed"
 
+	// FleetOperationUpdate is the fleet operation that updates the
+	// application of the selected clusters
+	FleetOperationUpdate = "update"
+	// FleetOperationUpdateConfig is the fleet operation that changes
+	// the configuration of the selected clusters
+	FleetOperationUpdateConfig = "update_config"
+
+	// fleet operation states
+	FleetStateCreated    = "created"
+	FleetStateInProgress = "in_progress"
+	FleetStateCompleted  = "completed"
+	FleetStateFailed     = "failed"
+
+	// states of individual clusters within a fleet operation
+	FleetClusterPending    = "pending"
+	FleetClusterInProgress = "in_progress"
+	FleetClusterCompleted  = "completed"
+	FleetClusterFailed     = "failed"
+	FleetClusterSkipped    = "skipped"
+
 	// Teleport node labels
 	// AdvertiseIP defines a label with advertise IP address
 	AdvertiseIP = "advertise-ip"
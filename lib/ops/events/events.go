@@ -83,4 +83,9 @@ const (
 	ClusterDegraded = "cluster.degraded"
 	// ClusterActivated fires when cluster becomes healthy again.
 	ClusterActivated = "cluster.activated"
+
+	// PackagesVerified fires when the integrity of the stored packages has been verified.
+	PackagesVerified = "packages.verified"
+	// PackagesPruned fires when unused packages have been removed from the package storage.
+	PackagesPruned = "packages.pruned"
 )
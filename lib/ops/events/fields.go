@@ -127,4 +127,16 @@ const (
 	FieldTime = "time"
 	// FieldRoles contains roles of a new user.
 	FieldRoles = "roles"
+	// FieldPeer contains ID of the package service peer.
+	FieldPeer = "peer"
+	// FieldChecked contains the number of verified packages.
+	FieldChecked = "checked"
+	// FieldCorrupted contains the number of corrupted packages.
+	FieldCorrupted = "corrupted"
+	// FieldRepaired contains the number of repaired packages.
+	FieldRepaired = "repaired"
+	// FieldRemoved contains the number of removed packages.
+	FieldRemoved = "removed"
+	// FieldReclaimed contains the number of bytes reclaimed in the package storage.
+	FieldReclaimed = "reclaimed"
 )
@@ -0,0 +1,86 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ops
+
+import (
+	"context"
+	"time"
+
+	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+)
+
+// WaitForMaintenanceWindow makes sure the operation described with
+// operationType is allowed to start according to the cluster maintenance window.
+//
+// If the window is closed and its policy is to refuse operations, an error
+// is returned. If the policy is to queue operations, it blocks until the
+// window opens or the context expires
+func WaitForMaintenanceWindow(ctx context.Context, operator Operator, key SiteKey, operationType string, logger logrus.FieldLogger) error {
+	var lastNext time.Time
+	for {
+		window, err := operator.GetMaintenanceWindow(key)
+		if err != nil {
+			if trace.IsNotFound(err) {
+				return nil
+			}
+			return trace.Wrap(err)
+		}
+		now := time.Now().UTC()
+		if window.IsOpen(now) {
+			return nil
+		}
+		if window.GetPolicy() != storage.MaintenancePolicyQueue {
+			return trace.Wrap(storage.CheckMaintenanceWindow(window, operationType, now))
+		}
+		next := window.NextOpen(now)
+		if next.IsZero() {
+			return trace.Wrap(storage.CheckMaintenanceWindow(window, operationType, now))
+		}
+		if !next.Equal(lastNext) {
+			logger.Infof("Waiting for the maintenance window to open at %v to start %v.",
+				next.UTC().Format(constants.HumanDateFormat), operationType)
+			lastNext = next
+		}
+		// recheck the window periodically in case it has been updated
+		wait := next.Sub(now)
+		if wait > defaults.MaintenanceWindowCheckInterval {
+			wait = defaults.MaintenanceWindowCheckInterval
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return trace.Wrap(ctx.Err())
+		}
+	}
+}
+
+const (
+	// MaintenanceUpdate describes the cluster update operation
+	// subject to the maintenance window
+	MaintenanceUpdate = "cluster update"
+	// MaintenanceGarbageCollect describes the garbage collection operation
+	// subject to the maintenance window
+	MaintenanceGarbageCollect = "garbage collection"
+	// MaintenanceRotateCertificates describes the certificate rotation
+	// subject to the maintenance window
+	MaintenanceRotateCertificates = "certificate rotation"
+)
@@ -0,0 +1,105 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+)
+
+// ConvertConfig defines the configuration of alert conversion
+type ConvertConfig struct {
+	// From is the monitoring provider to convert alerts from
+	From Monitoring
+	// To is the monitoring provider to convert alerts to
+	To Monitoring
+	// DryRun only reports the alerts that would be converted
+	DryRun bool
+}
+
+// CheckAndSetDefaults validates the config
+func (c *ConvertConfig) CheckAndSetDefaults() error {
+	if c.From == nil {
+		return trace.BadParameter("missing From")
+	}
+	if c.To == nil {
+		return trace.BadParameter("missing To")
+	}
+	return nil
+}
+
+// ConvertResult describes the outcome of alert conversion
+type ConvertResult struct {
+	// Converted lists the alerts converted to the target provider
+	Converted []storage.Alert
+	// Skipped lists the alerts not supported by the target provider
+	Skipped []SkippedAlert
+	// Target is the converted alert target, nil if none was configured
+	Target storage.AlertTarget
+}
+
+// SkippedAlert is an alert that could not be converted
+type SkippedAlert struct {
+	// Alert is the skipped alert
+	Alert storage.Alert
+	// Reason describes why the alert was skipped
+	Reason string
+}
+
+// ConvertAlerts copies alerts and the alert target from one monitoring
+// provider to another. Alerts the target provider does not support
+// (for example, alerts without a Prometheus expression when converting
+// to Prometheus) are skipped and reported in the result
+func ConvertAlerts(config ConvertConfig) (*ConvertResult, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	alerts, err := config.From.GetAlerts()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var result ConvertResult
+	for _, alert := range alerts {
+		if err := config.To.CheckAlert(alert); err != nil {
+			result.Skipped = append(result.Skipped, SkippedAlert{
+				Alert:  alert,
+				Reason: trace.UserMessage(err),
+			})
+			continue
+		}
+		if !config.DryRun {
+			if err := config.To.UpdateAlert(alert); err != nil {
+				return nil, trace.Wrap(err, "failed to convert alert %q", alert.GetName())
+			}
+		}
+		result.Converted = append(result.Converted, alert)
+	}
+	targets, err := config.From.GetAlertTargets()
+	if err != nil && !trace.IsNotFound(err) {
+		return nil, trace.Wrap(err)
+	}
+	if len(targets) != 0 {
+		if !config.DryRun {
+			if err := config.To.UpdateAlertTarget(targets[0]); err != nil {
+				return nil, trace.Wrap(err, "failed to convert alert target")
+			}
+		}
+		result.Target = targets[0]
+	}
+	return &result, nil
+}
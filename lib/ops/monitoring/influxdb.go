@@ -23,25 +23,40 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/rigging"
 	"github.com/gravitational/roundtrip"
 	"github.com/gravitational/teleport/lib/httplib"
 	"github.com/gravitational/trace"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubelabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
+// influxDB is the monitoring provider that manages retention policies
+// in InfluxDB and alerts as Kapacitor tasks.
+//
+// Alerts and alert targets are stored as config maps in the monitoring
+// namespace which are picked up by the Kapacitor watcher
 type influxDB struct {
 	*roundtrip.Client
+	getClient func() (kubernetes.Interface, error)
 }
 
 // NewInfluxDB returns a new InfluxDB monitoring provider
-func NewInfluxDB() (Monitoring, error) {
+func NewInfluxDB(getClient func() (kubernetes.Interface, error)) (Monitoring, error) {
 	client, err := roundtrip.NewClient(
 		fmt.Sprintf("http://%v:%v", defaults.InfluxDBServiceAddr, defaults.InfluxDBServicePort), "",
 		roundtrip.BasicAuth(defaults.InfluxDBAdminUser, defaults.InfluxDBAdminPassword))
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
-	return &influxDB{Client: client}, nil
+	return &influxDB{Client: client, getClient: getClient}, nil
 }
 
 // GetRetentionPolicies returns a list of retention policies for the site
@@ -106,6 +121,214 @@ func (i *influxDB) UpdateRetentionPolicy(policy RetentionPolicy) error {
 	return trace.Wrap(err)
 }
 
+// CheckAlert makes sure the alert defines the Kapacitor formula
+func (i *influxDB) CheckAlert(alert storage.Alert) error {
+	if alert.GetFormula() == "" {
+		return trace.BadParameter("alert %q does not define a Kapacitor formula "+
+			"required by the %v monitoring provider", alert.GetName(), ProviderInfluxDB)
+	}
+	return nil
+}
+
+// GetAlerts returns a list of configured monitoring alerts
+func (i *influxDB) GetAlerts() (alerts []storage.Alert, err error) {
+	configMaps, err := i.configMaps()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	labels := kubelabels.Set{
+		constants.MonitoringType: constants.MonitoringTypeAlert,
+	}
+	options := metav1.ListOptions{
+		LabelSelector: labels.String(),
+	}
+	configmaps, err := configMaps.List(options)
+	if err != nil {
+		return nil, trace.Wrap(rigging.ConvertError(err))
+	}
+
+	var errors []error
+	alerts = make([]storage.Alert, 0, len(configmaps.Items))
+	for _, config := range configmaps.Items {
+		data, ok := config.Data[constants.ResourceSpecKey]
+		if !ok {
+			continue
+		}
+		alert, err := storage.UnmarshalAlert([]byte(data))
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+
+	if len(errors) != 0 {
+		return nil, trace.NewAggregate(errors...)
+	}
+
+	return alerts, nil
+}
+
+// UpdateAlert updates the specified monitoring alert
+func (i *influxDB) UpdateAlert(alert storage.Alert) error {
+	if err := i.CheckAlert(alert); err != nil {
+		return trace.Wrap(err)
+	}
+	configMaps, err := i.configMaps()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	data, err := storage.MarshalAlert(alert)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	labels := map[string]string{
+		constants.MonitoringType: constants.MonitoringTypeAlert,
+	}
+	return updateConfigMap(configMaps, alert.GetName(), defaults.MonitoringNamespace,
+		string(data), labels)
+}
+
+// DeleteAlert deletes the specified monitoring alert
+func (i *influxDB) DeleteAlert(name string) error {
+	configMaps, err := i.configMaps()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	labels := kubelabels.Set{
+		constants.MonitoringType: constants.MonitoringTypeAlert,
+	}
+	options := metav1.ListOptions{
+		LabelSelector: labels.String(),
+	}
+	configmaps, err := configMaps.List(options)
+	if err != nil {
+		return trace.Wrap(rigging.ConvertError(err))
+	}
+
+	var alert *v1.ConfigMap
+	for _, config := range configmaps.Items {
+		if config.Name == name {
+			alert = &config
+			break
+		}
+	}
+	if alert == nil {
+		return trace.NotFound("alert %q not found", name)
+	}
+
+	err = configMaps.Delete(name, nil)
+	return trace.Wrap(rigging.ConvertError(err))
+}
+
+// GetAlertTargets returns a list of configured monitoring alert targets
+func (i *influxDB) GetAlertTargets() (targets []storage.AlertTarget, err error) {
+	configMaps, err := i.configMaps()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	data, err := getConfigMap(configMaps, constants.AlertTargetConfigMap)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil, trace.NotFound("alert target not found")
+		}
+		return nil, trace.Wrap(err)
+	}
+
+	target, err := storage.UnmarshalAlertTarget([]byte(data))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	return []storage.AlertTarget{target}, nil
+}
+
+// UpdateAlertTarget updates the cluster monitoring alert target
+func (i *influxDB) UpdateAlertTarget(target storage.AlertTarget) error {
+	configMaps, err := i.configMaps()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	data, err := storage.MarshalAlertTarget(target)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	labels := map[string]string{
+		constants.MonitoringType: constants.MonitoringTypeAlertTarget,
+	}
+	return updateConfigMap(configMaps, constants.AlertTargetConfigMap,
+		defaults.MonitoringNamespace, string(data), labels)
+}
+
+// DeleteAlertTarget deletes the cluster monitoring alert target
+func (i *influxDB) DeleteAlertTarget() error {
+	configMaps, err := i.configMaps()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	err = rigging.ConvertError(configMaps.Delete(constants.AlertTargetConfigMap, nil))
+	if trace.IsNotFound(err) {
+		return trace.NotFound("no alert targets found")
+	}
+	return trace.Wrap(err)
+}
+
+func (i *influxDB) configMaps() (corev1.ConfigMapInterface, error) {
+	client, err := i.getClient()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.CoreV1().ConfigMaps(defaults.MonitoringNamespace), nil
+}
+
+func getConfigMap(client corev1.ConfigMapInterface, name string) (string, error) {
+	config, err := client.Get(name, metav1.GetOptions{})
+	if err != nil {
+		return "", trace.Wrap(rigging.ConvertError(err))
+	}
+
+	data, ok := config.Data[constants.ResourceSpecKey]
+	if !ok {
+		return "", trace.NotFound("no resource found")
+	}
+
+	return data, nil
+}
+
+func updateConfigMap(client corev1.ConfigMapInterface, name, namespace, data string, labels map[string]string) error {
+	config := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Data: map[string]string{
+			constants.ResourceSpecKey: data,
+		},
+	}
+
+	_, err := client.Create(config)
+	err = rigging.ConvertError(err)
+	if err == nil {
+		return nil
+	}
+
+	if !trace.IsAlreadyExists(err) {
+		return trace.Wrap(err)
+	}
+
+	_, err = client.Update(config)
+	return trace.Wrap(rigging.ConvertError(err))
+}
+
 // Get is like roundtrip.Client.Get but converts returned HTTP errors into trace errors
 func (i *influxDB) Get(endpoint string, params url.Values) (*roundtrip.Response, error) {
 	return httplib.ConvertResponse(i.Client.Get(context.TODO(), endpoint, params))
@@ -17,13 +17,17 @@ limitations under the License.
 package monitoring
 
 import (
+	"sync"
 	"time"
 
 	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/utils"
+
 	"github.com/gravitational/rigging"
 	"github.com/gravitational/trace"
-
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
@@ -33,6 +37,21 @@ type Monitoring interface {
 	GetRetentionPolicies() ([]RetentionPolicy, error)
 	// UpdateRetentionPolicy updates a retention policy
 	UpdateRetentionPolicy(RetentionPolicy) error
+	// GetAlerts returns the list of configured monitoring alerts
+	GetAlerts() ([]storage.Alert, error)
+	// UpdateAlert creates or updates the specified monitoring alert
+	UpdateAlert(storage.Alert) error
+	// DeleteAlert deletes the monitoring alert with the specified name
+	DeleteAlert(name string) error
+	// GetAlertTargets returns the list of configured monitoring alert targets
+	GetAlertTargets() ([]storage.AlertTarget, error)
+	// UpdateAlertTarget updates the monitoring alert target
+	UpdateAlertTarget(storage.AlertTarget) error
+	// DeleteAlertTarget deletes the monitoring alert target
+	DeleteAlertTarget() error
+	// CheckAlert returns an error if the alert cannot be
+	// represented with this provider
+	CheckAlert(storage.Alert) error
 }
 
 // RetentionPolicy represents a single retention policy
@@ -43,6 +62,59 @@ type RetentionPolicy struct {
 	Duration time.Duration `json:"duration"`
 }
 
+// Config defines the monitoring provider configuration
+type Config struct {
+	// Provider selects the monitoring provider: influxdb or prometheus.
+	// If unspecified, the provider is detected from the monitoring
+	// services deployed in the cluster
+	Provider string
+	// GetClient returns the Kubernetes client.
+	// If unspecified, the client is created from the default configuration
+	GetClient func() (kubernetes.Interface, error)
+}
+
+// CheckAndSetDefaults validates the config and sets defaults
+func (c *Config) CheckAndSetDefaults() error {
+	switch c.Provider {
+	case "", ProviderInfluxDB, ProviderPrometheus:
+	default:
+		return trace.BadParameter("unsupported monitoring provider %q, supported are: %v, %v",
+			c.Provider, ProviderInfluxDB, ProviderPrometheus)
+	}
+	if c.GetClient == nil {
+		c.GetClient = newClientGetter()
+	}
+	return nil
+}
+
+// New returns a new monitoring provider for the specified configuration
+func New(config Config) (Monitoring, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	switch config.Provider {
+	case ProviderInfluxDB:
+		return NewInfluxDB(config.GetClient)
+	case ProviderPrometheus:
+		return NewPrometheus(PrometheusConfig{GetClient: config.GetClient})
+	}
+	return &detector{getClient: config.GetClient}, nil
+}
+
+// DetectProvider returns the name of the monitoring provider
+// deployed in the cluster
+func DetectProvider(client corev1.CoreV1Interface) (string, error) {
+	_, err := client.Services(defaults.MonitoringNamespace).Get(
+		defaults.PrometheusServiceName, metav1.GetOptions{})
+	if err == nil {
+		return ProviderPrometheus, nil
+	}
+	if !trace.IsNotFound(rigging.ConvertError(err)) {
+		return "", trace.Wrap(rigging.ConvertError(err))
+	}
+	return ProviderInfluxDB, nil
+}
+
 // GetNamespace uses the provided Kubernetes client to determine namespace
 // where monitoring resources reside
 func GetNamespace(client corev1.CoreV1Interface) (string, error) {
@@ -58,3 +130,143 @@ func GetNamespace(client corev1.CoreV1Interface) (string, error) {
 	}
 	return "", trace.NotFound("service %q was not found", defaults.GrafanaServiceName)
 }
+
+// detector is the monitoring provider that delegates to the
+// provider detected in the cluster upon first use
+type detector struct {
+	sync.Mutex
+	getClient func() (kubernetes.Interface, error)
+	provider  Monitoring
+}
+
+// GetRetentionPolicies returns a list of retention policies
+func (r *detector) GetRetentionPolicies() ([]RetentionPolicy, error) {
+	provider, err := r.detect()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return provider.GetRetentionPolicies()
+}
+
+// UpdateRetentionPolicy updates a retention policy
+func (r *detector) UpdateRetentionPolicy(policy RetentionPolicy) error {
+	provider, err := r.detect()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return provider.UpdateRetentionPolicy(policy)
+}
+
+// GetAlerts returns the list of configured monitoring alerts
+func (r *detector) GetAlerts() ([]storage.Alert, error) {
+	provider, err := r.detect()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return provider.GetAlerts()
+}
+
+// UpdateAlert creates or updates the specified monitoring alert
+func (r *detector) UpdateAlert(alert storage.Alert) error {
+	provider, err := r.detect()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return provider.UpdateAlert(alert)
+}
+
+// DeleteAlert deletes the monitoring alert with the specified name
+func (r *detector) DeleteAlert(name string) error {
+	provider, err := r.detect()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return provider.DeleteAlert(name)
+}
+
+// GetAlertTargets returns the list of configured monitoring alert targets
+func (r *detector) GetAlertTargets() ([]storage.AlertTarget, error) {
+	provider, err := r.detect()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return provider.GetAlertTargets()
+}
+
+// UpdateAlertTarget updates the monitoring alert target
+func (r *detector) UpdateAlertTarget(target storage.AlertTarget) error {
+	provider, err := r.detect()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return provider.UpdateAlertTarget(target)
+}
+
+// DeleteAlertTarget deletes the monitoring alert target
+func (r *detector) DeleteAlertTarget() error {
+	provider, err := r.detect()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return provider.DeleteAlertTarget()
+}
+
+// CheckAlert returns an error if the alert cannot be represented with the provider
+func (r *detector) CheckAlert(alert storage.Alert) error {
+	provider, err := r.detect()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return provider.CheckAlert(alert)
+}
+
+// detect returns the monitoring provider deployed in the cluster.
+// The provider is detected once and cached
+func (r *detector) detect() (Monitoring, error) {
+	r.Lock()
+	defer r.Unlock()
+	if r.provider != nil {
+		return r.provider, nil
+	}
+	client, err := r.getClient()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	name, err := DetectProvider(client.CoreV1())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	provider, err := New(Config{Provider: name, GetClient: r.getClient})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	r.provider = provider
+	return provider, nil
+}
+
+// newClientGetter returns a function that creates the Kubernetes client
+// from the default configuration upon first use
+func newClientGetter() func() (kubernetes.Interface, error) {
+	var mu sync.Mutex
+	var client kubernetes.Interface
+	return func() (kubernetes.Interface, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if client != nil {
+			return client, nil
+		}
+		clientset, _, err := utils.GetKubeClient("")
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		client = clientset
+		return client, nil
+	}
+}
+
+const (
+	// ProviderInfluxDB is the InfluxDB/Kapacitor monitoring provider
+	ProviderInfluxDB = "influxdb"
+	// ProviderPrometheus is the Prometheus/Alertmanager monitoring provider
+	ProviderPrometheus = "prometheus"
+)
@@ -0,0 +1,581 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/ghodss/yaml"
+	"github.com/gravitational/rigging"
+	"github.com/gravitational/trace"
+	"github.com/prometheus/common/model"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubelabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PrometheusConfig defines the configuration of the Prometheus monitoring provider
+type PrometheusConfig struct {
+	// GetClient returns the Kubernetes client
+	GetClient func() (kubernetes.Interface, error)
+	// Namespace is the namespace with the monitoring resources
+	Namespace string
+	// Name is the name of the Prometheus resource
+	Name string
+	// AlertmanagerSecret is the name of the secret with Alertmanager configuration
+	AlertmanagerSecret string
+}
+
+// CheckAndSetDefaults validates the config and sets defaults
+func (c *PrometheusConfig) CheckAndSetDefaults() error {
+	if c.GetClient == nil {
+		return trace.BadParameter("missing GetClient")
+	}
+	if c.Namespace == "" {
+		c.Namespace = defaults.MonitoringNamespace
+	}
+	if c.Name == "" {
+		c.Name = defaults.PrometheusName
+	}
+	if c.AlertmanagerSecret == "" {
+		c.AlertmanagerSecret = defaults.AlertmanagerConfigSecret
+	}
+	return nil
+}
+
+// NewPrometheus returns a new monitoring provider backed by Prometheus
+// and Alertmanager managed with Prometheus operator.
+//
+// The metrics retention is configured on the Prometheus resource, alerts
+// are managed as PrometheusRule resources and the alert target is configured
+// as an email receiver in Alertmanager configuration
+func NewPrometheus(config PrometheusConfig) (Monitoring, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &prometheus{
+		PrometheusConfig: config,
+		client: &kubeClient{
+			getClient: config.GetClient,
+			namespace: config.Namespace,
+		},
+	}, nil
+}
+
+type prometheus struct {
+	PrometheusConfig
+	client prometheusClient
+}
+
+// GetRetentionPolicies returns the metrics retention policy.
+// Prometheus only supports a single retention policy
+func (p *prometheus) GetRetentionPolicies() ([]RetentionPolicy, error) {
+	data, err := p.client.getResource(prometheusesResource, p.Name)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var resource prometheusResource
+	if err := json.Unmarshal(data, &resource); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	retention := resource.Spec.Retention
+	if retention == "" {
+		retention = prometheusDefaultRetention
+	}
+	duration, err := model.ParseDuration(retention)
+	if err != nil {
+		return nil, trace.Wrap(err, "failed to parse Prometheus retention %q", retention)
+	}
+	return []RetentionPolicy{{
+		Name:     RetentionPolicyDefault,
+		Duration: time.Duration(duration),
+	}}, nil
+}
+
+// UpdateRetentionPolicy updates the metrics retention on the Prometheus resource
+func (p *prometheus) UpdateRetentionPolicy(policy RetentionPolicy) error {
+	if policy.Name != RetentionPolicyDefault {
+		return trace.BadParameter("%v monitoring provider only supports the %q retention policy",
+			ProviderPrometheus, RetentionPolicyDefault)
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"retention": model.Duration(policy.Duration).String(),
+		},
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(p.client.patchResource(prometheusesResource, p.Name, patch))
+}
+
+// CheckAlert makes sure the alert defines the Prometheus expression
+func (p *prometheus) CheckAlert(alert storage.Alert) error {
+	if alert.GetExpr() == "" {
+		return trace.BadParameter("alert %q does not define an expression "+
+			"required by the %v monitoring provider", alert.GetName(), ProviderPrometheus)
+	}
+	if alert.GetFor() != "" {
+		if _, err := model.ParseDuration(alert.GetFor()); err != nil {
+			return trace.BadParameter("invalid duration %q of alert %q: %v",
+				alert.GetFor(), alert.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// GetAlerts returns the list of alerts managed as PrometheusRule resources
+func (p *prometheus) GetAlerts() ([]storage.Alert, error) {
+	data, err := p.client.listResources(prometheusRulesResource, alertSelector().String())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var list prometheusRuleList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	alerts := make([]storage.Alert, 0, len(list.Items))
+	for _, rule := range list.Items {
+		alerts = append(alerts, rule.alert())
+	}
+	return alerts, nil
+}
+
+// UpdateAlert creates or updates the PrometheusRule resource for the specified alert
+func (p *prometheus) UpdateAlert(alert storage.Alert) error {
+	if err := p.CheckAlert(alert); err != nil {
+		return trace.Wrap(err)
+	}
+	rule := newPrometheusRule(alert, p.Namespace, p.Name)
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = p.client.createResource(prometheusRulesResource, data)
+	if err == nil || !trace.IsAlreadyExists(err) {
+		return trace.Wrap(err)
+	}
+	// Merge patch replaces the rule groups and the labels of the existing resource
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      rule.Metadata.Labels,
+			"annotations": rule.Metadata.Annotations,
+		},
+		"spec": rule.Spec,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(p.client.patchResource(prometheusRulesResource, alert.GetName(), patch))
+}
+
+// DeleteAlert deletes the PrometheusRule resource of the specified alert
+func (p *prometheus) DeleteAlert(name string) error {
+	err := p.client.deleteResource(prometheusRulesResource, name)
+	if trace.IsNotFound(err) {
+		return trace.NotFound("alert %q not found", name)
+	}
+	return trace.Wrap(err)
+}
+
+// GetAlertTargets returns the alert target configured as an Alertmanager receiver
+func (p *prometheus) GetAlertTargets() ([]storage.AlertTarget, error) {
+	config, err := p.getAlertmanagerConfig()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	receiver := config.findReceiver(alertTargetReceiver)
+	if receiver == nil {
+		return nil, trace.NotFound("alert target not found")
+	}
+	email, err := receiverEmail(receiver)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return []storage.AlertTarget{storage.NewAlertTarget(email)}, nil
+}
+
+// UpdateAlertTarget configures Alertmanager to send all alerts to
+// the email address of the specified alert target
+func (p *prometheus) UpdateAlertTarget(target storage.AlertTarget) error {
+	config, err := p.getAlertmanagerConfig()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	config.setReceiver(map[string]interface{}{
+		"name": alertTargetReceiver,
+		"email_configs": []interface{}{
+			map[string]interface{}{
+				"to":            target.GetEmail(),
+				"send_resolved": true,
+			},
+		},
+	})
+	config.setRouteReceiver(alertTargetReceiver)
+	return trace.Wrap(p.updateAlertmanagerConfig(config))
+}
+
+// DeleteAlertTarget removes the alert target receiver from Alertmanager configuration
+func (p *prometheus) DeleteAlertTarget() error {
+	config, err := p.getAlertmanagerConfig()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if config.findReceiver(alertTargetReceiver) == nil {
+		return trace.NotFound("no alert targets found")
+	}
+	config.removeReceiver(alertTargetReceiver)
+	config.setReceiver(map[string]interface{}{"name": nullReceiver})
+	config.setRouteReceiver(nullReceiver)
+	return trace.Wrap(p.updateAlertmanagerConfig(config))
+}
+
+func (p *prometheus) getAlertmanagerConfig() (alertmanagerConfig, error) {
+	secret, err := p.client.getSecret(p.AlertmanagerSecret)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	config := make(alertmanagerConfig)
+	data := secret.Data[alertmanagerConfigKey]
+	if len(data) == 0 {
+		return config, nil
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, trace.Wrap(err, "failed to parse Alertmanager configuration")
+	}
+	return config, nil
+}
+
+func (p *prometheus) updateAlertmanagerConfig(config alertmanagerConfig) error {
+	secret, err := p.client.getSecret(p.AlertmanagerSecret)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	secret.Data[alertmanagerConfigKey] = data
+	return trace.Wrap(p.client.updateSecret(secret))
+}
+
+// alertmanagerConfig is the Alertmanager configuration.
+// The configuration is kept in generic form so that the parts
+// not managed by gravity are preserved
+type alertmanagerConfig map[string]interface{}
+
+func (r alertmanagerConfig) receivers() []interface{} {
+	receivers, _ := r["receivers"].([]interface{})
+	return receivers
+}
+
+func (r alertmanagerConfig) findReceiver(name string) map[string]interface{} {
+	for _, item := range r.receivers() {
+		receiver, ok := item.(map[string]interface{})
+		if ok && receiver["name"] == name {
+			return receiver
+		}
+	}
+	return nil
+}
+
+// setReceiver adds the receiver or replaces the existing receiver with the same name
+func (r alertmanagerConfig) setReceiver(receiver map[string]interface{}) {
+	r.removeReceiver(receiver["name"].(string))
+	r["receivers"] = append(r.receivers(), receiver)
+}
+
+func (r alertmanagerConfig) removeReceiver(name string) {
+	var receivers []interface{}
+	for _, item := range r.receivers() {
+		if receiver, ok := item.(map[string]interface{}); ok && receiver["name"] == name {
+			continue
+		}
+		receivers = append(receivers, item)
+	}
+	r["receivers"] = receivers
+}
+
+// setRouteReceiver sets the receiver of the top-level route
+func (r alertmanagerConfig) setRouteReceiver(name string) {
+	route, ok := r["route"].(map[string]interface{})
+	if !ok {
+		route = make(map[string]interface{})
+		r["route"] = route
+	}
+	route["receiver"] = name
+}
+
+func receiverEmail(receiver map[string]interface{}) (string, error) {
+	configs, _ := receiver["email_configs"].([]interface{})
+	for _, item := range configs {
+		config, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if email, ok := config["to"].(string); ok && email != "" {
+			return email, nil
+		}
+	}
+	return "", trace.NotFound("receiver %v has no email configured", receiver["name"])
+}
+
+// prometheusResource is the subset of the Prometheus resource
+// of the Prometheus operator
+type prometheusResource struct {
+	Spec struct {
+		// Retention is the metrics retention, e.g. 30d
+		Retention string `json:"retention,omitempty"`
+	} `json:"spec"`
+}
+
+// prometheusRule is the PrometheusRule resource of the Prometheus operator
+type prometheusRule struct {
+	// APIVersion is the resource API version
+	APIVersion string `json:"apiVersion"`
+	// Kind is the resource kind
+	Kind string `json:"kind"`
+	// Metadata is the resource metadata
+	Metadata metav1.ObjectMeta `json:"metadata"`
+	// Spec is the resource specification
+	Spec prometheusRuleSpec `json:"spec"`
+}
+
+// prometheusRuleList is the list of PrometheusRule resources
+type prometheusRuleList struct {
+	// Items lists the resources
+	Items []prometheusRule `json:"items"`
+}
+
+// prometheusRuleSpec defines groups of alerting rules
+type prometheusRuleSpec struct {
+	// Groups lists the rule groups
+	Groups []prometheusRuleGroup `json:"groups"`
+}
+
+// prometheusRuleGroup is a named group of alerting rules
+type prometheusRuleGroup struct {
+	// Name is the group name
+	Name string `json:"name"`
+	// Rules lists the alerting rules
+	Rules []prometheusAlertingRule `json:"rules"`
+}
+
+// prometheusAlertingRule is a single Prometheus alerting rule
+type prometheusAlertingRule struct {
+	// Alert is the alert name
+	Alert string `json:"alert"`
+	// Expr is the PromQL expression
+	Expr string `json:"expr"`
+	// For is how long the expression should hold before the alert fires
+	For string `json:"for,omitempty"`
+	// Labels are the labels attached to the alert
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// newPrometheusRule returns a PrometheusRule resource with a single
+// alerting rule for the specified alert
+func newPrometheusRule(alert storage.Alert, namespace, prometheusName string) prometheusRule {
+	return prometheusRule{
+		APIVersion: prometheusOperatorAPIVersion,
+		Kind:       "PrometheusRule",
+		Metadata: metav1.ObjectMeta{
+			Name:      alert.GetName(),
+			Namespace: namespace,
+			Labels: map[string]string{
+				constants.MonitoringType: constants.MonitoringTypeAlert,
+				// labels Prometheus operator uses by default to select rules
+				"prometheus": prometheusName,
+				"role":       "alert-rules",
+			},
+			Annotations: map[string]string{
+				// keep the formula so the alert can be converted back
+				alertFormulaAnnotation: alert.GetFormula(),
+			},
+		},
+		Spec: prometheusRuleSpec{
+			Groups: []prometheusRuleGroup{{
+				Name: alert.GetName(),
+				Rules: []prometheusAlertingRule{{
+					Alert:  alert.GetName(),
+					Expr:   alert.GetExpr(),
+					For:    alert.GetFor(),
+					Labels: alert.GetLabels(),
+				}},
+			}},
+		},
+	}
+}
+
+// alert returns the alert resource defined by this rule
+func (r prometheusRule) alert() storage.Alert {
+	spec := storage.AlertSpecV2{
+		Formula: r.Metadata.Annotations[alertFormulaAnnotation],
+	}
+	for _, group := range r.Spec.Groups {
+		for _, rule := range group.Rules {
+			spec.Expr = rule.Expr
+			spec.For = rule.For
+			spec.Labels = rule.Labels
+			break
+		}
+	}
+	return storage.NewAlert(r.Metadata.Name, spec)
+}
+
+func alertSelector() kubelabels.Selector {
+	return kubelabels.SelectorFromSet(kubelabels.Set{
+		constants.MonitoringType: constants.MonitoringTypeAlert,
+	})
+}
+
+// prometheusClient defines the subset of Kubernetes API
+// used by the Prometheus monitoring provider
+type prometheusClient interface {
+	// getResource returns the Prometheus operator resource
+	getResource(resource, name string) ([]byte, error)
+	// listResources returns the list of Prometheus operator resources
+	// matching the label selector
+	listResources(resource, selector string) ([]byte, error)
+	// createResource creates a new Prometheus operator resource
+	createResource(resource string, data []byte) error
+	// patchResource applies the JSON merge patch to the Prometheus operator resource
+	patchResource(resource, name string, patch []byte) error
+	// deleteResource deletes the Prometheus operator resource
+	deleteResource(resource, name string) error
+	// getSecret returns the secret with the specified name
+	getSecret(name string) (*v1.Secret, error)
+	// updateSecret updates the specified secret
+	updateSecret(*v1.Secret) error
+}
+
+// kubeClient implements prometheusClient using the Kubernetes API
+type kubeClient struct {
+	getClient func() (kubernetes.Interface, error)
+	namespace string
+}
+
+func (r *kubeClient) getResource(resource, name string) ([]byte, error) {
+	client, err := r.getClient()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	data, err := client.CoreV1().RESTClient().Get().AbsPath(r.path(resource, name)).DoRaw()
+	return data, trace.Wrap(rigging.ConvertError(err))
+}
+
+func (r *kubeClient) listResources(resource, selector string) ([]byte, error) {
+	client, err := r.getClient()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	data, err := client.CoreV1().RESTClient().Get().AbsPath(r.path(resource, "")).
+		Param("labelSelector", selector).DoRaw()
+	return data, trace.Wrap(rigging.ConvertError(err))
+}
+
+func (r *kubeClient) createResource(resource string, data []byte) error {
+	client, err := r.getClient()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	_, err = client.CoreV1().RESTClient().Post().AbsPath(r.path(resource, "")).
+		SetHeader("Content-Type", "application/json").Body(data).DoRaw()
+	return trace.Wrap(rigging.ConvertError(err))
+}
+
+func (r *kubeClient) patchResource(resource, name string, patch []byte) error {
+	client, err := r.getClient()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	_, err = client.CoreV1().RESTClient().Patch(types.MergePatchType).
+		AbsPath(r.path(resource, name)).Body(patch).DoRaw()
+	return trace.Wrap(rigging.ConvertError(err))
+}
+
+func (r *kubeClient) deleteResource(resource, name string) error {
+	client, err := r.getClient()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	_, err = client.CoreV1().RESTClient().Delete().AbsPath(r.path(resource, name)).DoRaw()
+	return trace.Wrap(rigging.ConvertError(err))
+}
+
+func (r *kubeClient) getSecret(name string) (*v1.Secret, error) {
+	client, err := r.getClient()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	secret, err := client.CoreV1().Secrets(r.namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, trace.Wrap(rigging.ConvertError(err))
+	}
+	return secret, nil
+}
+
+func (r *kubeClient) updateSecret(secret *v1.Secret) error {
+	client, err := r.getClient()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	_, err = client.CoreV1().Secrets(r.namespace).Update(secret)
+	return trace.Wrap(rigging.ConvertError(err))
+}
+
+// path returns the API path of the Prometheus operator resource
+func (r *kubeClient) path(resource, name string) string {
+	path := fmt.Sprintf("/apis/%v/namespaces/%v/%v", prometheusOperatorAPIVersion, r.namespace, resource)
+	if name != "" {
+		path = fmt.Sprintf("%v/%v", path, name)
+	}
+	return path
+}
+
+const (
+	// RetentionPolicyDefault is the name of the default retention policy
+	RetentionPolicyDefault = "default"
+
+	// prometheusOperatorAPIVersion is the API group and version of Prometheus operator resources
+	prometheusOperatorAPIVersion = "monitoring.coreos.com/v1"
+	// prometheusesResource is the name of the Prometheus resource collection
+	prometheusesResource = "prometheuses"
+	// prometheusRulesResource is the name of the PrometheusRule resource collection
+	prometheusRulesResource = "prometheusrules"
+	// prometheusDefaultRetention is the retention Prometheus operator uses if none is set
+	prometheusDefaultRetention = "24h"
+	// alertmanagerConfigKey is the key in Alertmanager secret with the configuration
+	alertmanagerConfigKey = "alertmanager.yaml"
+	// alertTargetReceiver is the name of the Alertmanager receiver for the alert target
+	alertTargetReceiver = "gravity-alert-target"
+	// nullReceiver is the name of the Alertmanager receiver that discards alerts
+	nullReceiver = "null"
+	// alertFormulaAnnotation is the PrometheusRule annotation with the Kapacitor formula of the alert
+	alertFormulaAnnotation = "gravitational.io/alert-formula"
+)
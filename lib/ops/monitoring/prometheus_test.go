@@ -0,0 +1,284 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gravitational/gravity/lib/storage"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/ghodss/yaml"
+	"github.com/gravitational/trace"
+	"gopkg.in/check.v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMonitoring(t *testing.T) { check.TestingT(t) }
+
+type PrometheusSuite struct {
+	client *fakeClient
+	mon    *prometheus
+}
+
+var _ = check.Suite(&PrometheusSuite{})
+
+func (s *PrometheusSuite) SetUpTest(c *check.C) {
+	s.client = newFakeClient()
+	s.client.resources[prometheusesResource] = map[string][]byte{
+		"k8s": []byte(`{"metadata":{"name":"k8s"},"spec":{"replicas":1}}`),
+	}
+	s.mon = &prometheus{
+		PrometheusConfig: PrometheusConfig{
+			Namespace:          "monitoring",
+			Name:               "k8s",
+			AlertmanagerSecret: "alertmanager-main",
+		},
+		client: s.client,
+	}
+}
+
+func (s *PrometheusSuite) TestRetention(c *check.C) {
+	policies, err := s.mon.GetRetentionPolicies()
+	c.Assert(err, check.IsNil)
+	c.Assert(policies, check.DeepEquals, []RetentionPolicy{
+		{Name: RetentionPolicyDefault, Duration: 24 * time.Hour},
+	})
+
+	err = s.mon.UpdateRetentionPolicy(RetentionPolicy{Name: "long", Duration: time.Hour})
+	c.Assert(trace.IsBadParameter(err), check.Equals, true)
+
+	err = s.mon.UpdateRetentionPolicy(RetentionPolicy{
+		Name:     RetentionPolicyDefault,
+		Duration: 30 * 24 * time.Hour,
+	})
+	c.Assert(err, check.IsNil)
+	policies, err = s.mon.GetRetentionPolicies()
+	c.Assert(err, check.IsNil)
+	c.Assert(policies, check.DeepEquals, []RetentionPolicy{
+		{Name: RetentionPolicyDefault, Duration: 30 * 24 * time.Hour},
+	})
+}
+
+func (s *PrometheusSuite) TestAlerts(c *check.C) {
+	err := s.mon.UpdateAlert(storage.NewAlert("cpu", storage.AlertSpecV2{
+		Formula: "kapacitor formula",
+	}))
+	c.Assert(trace.IsBadParameter(err), check.Equals, true)
+
+	alert := storage.NewAlert("cpu", storage.AlertSpecV2{
+		Formula: "kapacitor formula",
+		Expr:    "cpu_usage > 90",
+		For:     "5m",
+		Labels:  map[string]string{"severity": "warning"},
+	})
+	c.Assert(s.mon.UpdateAlert(alert), check.IsNil)
+	alerts, err := s.mon.GetAlerts()
+	c.Assert(err, check.IsNil)
+	c.Assert(alerts, check.DeepEquals, []storage.Alert{alert})
+
+	updated := storage.NewAlert("cpu", storage.AlertSpecV2{
+		Expr: "cpu_usage > 95",
+	})
+	c.Assert(s.mon.UpdateAlert(updated), check.IsNil)
+	alerts, err = s.mon.GetAlerts()
+	c.Assert(err, check.IsNil)
+	c.Assert(alerts, check.HasLen, 1)
+	c.Assert(alerts[0].GetExpr(), check.Equals, "cpu_usage > 95")
+	c.Assert(alerts[0].GetFor(), check.Equals, "")
+	c.Assert(alerts[0].GetFormula(), check.Equals, "")
+
+	c.Assert(s.mon.DeleteAlert("cpu"), check.IsNil)
+	alerts, err = s.mon.GetAlerts()
+	c.Assert(err, check.IsNil)
+	c.Assert(alerts, check.HasLen, 0)
+	c.Assert(trace.IsNotFound(s.mon.DeleteAlert("cpu")), check.Equals, true)
+}
+
+func (s *PrometheusSuite) TestAlertTargets(c *check.C) {
+	s.client.secret.Data = map[string][]byte{
+		alertmanagerConfigKey: []byte(`global:
+  resolve_timeout: 5m
+route:
+  receiver: "null"
+  group_by: [job]
+receivers:
+- name: "null"
+`),
+	}
+	_, err := s.mon.GetAlertTargets()
+	c.Assert(trace.IsNotFound(err), check.Equals, true)
+
+	c.Assert(s.mon.UpdateAlertTarget(storage.NewAlertTarget("ops@example.com")), check.IsNil)
+	targets, err := s.mon.GetAlertTargets()
+	c.Assert(err, check.IsNil)
+	c.Assert(targets, check.HasLen, 1)
+	c.Assert(targets[0].GetEmail(), check.Equals, "ops@example.com")
+
+	config := s.alertmanagerConfig(c)
+	route := config["route"].(map[string]interface{})
+	c.Assert(route["receiver"], check.Equals, alertTargetReceiver)
+	c.Assert(route["group_by"], check.DeepEquals, []interface{}{"job"})
+	c.Assert(config["global"], check.DeepEquals, map[string]interface{}{"resolve_timeout": "5m"})
+
+	c.Assert(s.mon.DeleteAlertTarget(), check.IsNil)
+	_, err = s.mon.GetAlertTargets()
+	c.Assert(trace.IsNotFound(err), check.Equals, true)
+	config = s.alertmanagerConfig(c)
+	c.Assert(config["route"].(map[string]interface{})["receiver"], check.Equals, nullReceiver)
+	c.Assert(config.findReceiver(nullReceiver), check.NotNil)
+	c.Assert(trace.IsNotFound(s.mon.DeleteAlertTarget()), check.Equals, true)
+}
+
+func (s *PrometheusSuite) TestConvertAlerts(c *check.C) {
+	from := &memoryProvider{
+		alerts: []storage.Alert{
+			storage.NewAlert("cpu", storage.AlertSpecV2{Formula: "cpu formula", Expr: "cpu_usage > 90"}),
+			storage.NewAlert("disk", storage.AlertSpecV2{Formula: "disk formula"}),
+		},
+		target: storage.NewAlertTarget("ops@example.com"),
+	}
+
+	result, err := ConvertAlerts(ConvertConfig{From: from, To: s.mon, DryRun: true})
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Converted, check.DeepEquals, from.alerts[:1])
+	c.Assert(result.Skipped, check.HasLen, 1)
+	c.Assert(result.Skipped[0].Alert.GetName(), check.Equals, "disk")
+	alerts, err := s.mon.GetAlerts()
+	c.Assert(err, check.IsNil)
+	c.Assert(alerts, check.HasLen, 0)
+
+	result, err = ConvertAlerts(ConvertConfig{From: from, To: s.mon})
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Converted, check.HasLen, 1)
+	c.Assert(result.Target.GetEmail(), check.Equals, "ops@example.com")
+	alerts, err = s.mon.GetAlerts()
+	c.Assert(err, check.IsNil)
+	c.Assert(alerts, check.DeepEquals, from.alerts[:1])
+	targets, err := s.mon.GetAlertTargets()
+	c.Assert(err, check.IsNil)
+	c.Assert(targets[0].GetEmail(), check.Equals, "ops@example.com")
+}
+
+func (s *PrometheusSuite) alertmanagerConfig(c *check.C) alertmanagerConfig {
+	config := make(alertmanagerConfig)
+	err := yaml.Unmarshal(s.client.secret.Data[alertmanagerConfigKey], &config)
+	c.Assert(err, check.IsNil)
+	return config
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		resources: make(map[string]map[string][]byte),
+		secret: &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "alertmanager-main"},
+		},
+	}
+}
+
+// fakeClient keeps Prometheus operator resources in memory
+type fakeClient struct {
+	resources map[string]map[string][]byte
+	secret    *v1.Secret
+}
+
+func (r *fakeClient) getResource(resource, name string) ([]byte, error) {
+	data, ok := r.resources[resource][name]
+	if !ok {
+		return nil, trace.NotFound("%v %v not found", resource, name)
+	}
+	return data, nil
+}
+
+func (r *fakeClient) listResources(resource, selector string) ([]byte, error) {
+	var items []json.RawMessage
+	for _, data := range r.resources[resource] {
+		items = append(items, data)
+	}
+	return json.Marshal(map[string]interface{}{"items": items})
+}
+
+func (r *fakeClient) createResource(resource string, data []byte) error {
+	var meta struct {
+		Metadata metav1.ObjectMeta `json:"metadata"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return trace.Wrap(err)
+	}
+	if _, ok := r.resources[resource][meta.Metadata.Name]; ok {
+		return trace.AlreadyExists("%v %v already exists", resource, meta.Metadata.Name)
+	}
+	if r.resources[resource] == nil {
+		r.resources[resource] = make(map[string][]byte)
+	}
+	r.resources[resource][meta.Metadata.Name] = data
+	return nil
+}
+
+func (r *fakeClient) patchResource(resource, name string, patch []byte) error {
+	data, err := r.getResource(resource, name)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	data, err = jsonpatch.MergePatch(data, patch)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	r.resources[resource][name] = data
+	return nil
+}
+
+func (r *fakeClient) deleteResource(resource, name string) error {
+	if _, ok := r.resources[resource][name]; !ok {
+		return trace.NotFound("%v %v not found", resource, name)
+	}
+	delete(r.resources[resource], name)
+	return nil
+}
+
+func (r *fakeClient) getSecret(name string) (*v1.Secret, error) {
+	if name != r.secret.Name {
+		return nil, trace.NotFound("secret %v not found", name)
+	}
+	return r.secret.DeepCopy(), nil
+}
+
+func (r *fakeClient) updateSecret(secret *v1.Secret) error {
+	r.secret = secret.DeepCopy()
+	return nil
+}
+
+// memoryProvider is a monitoring provider that keeps alerts in memory
+type memoryProvider struct {
+	Monitoring
+	alerts []storage.Alert
+	target storage.AlertTarget
+}
+
+func (r *memoryProvider) GetAlerts() ([]storage.Alert, error) {
+	return r.alerts, nil
+}
+
+func (r *memoryProvider) GetAlertTargets() ([]storage.AlertTarget, error) {
+	if r.target == nil {
+		return nil, trace.NotFound("no alert targets found")
+	}
+	return []storage.AlertTarget{r.target}, nil
+}
@@ -802,6 +802,142 @@ func (o *OperatorACL) DeleteAlertTarget(key SiteKey) error {
 	return o.operator.DeleteAlertTarget(key)
 }
 
+// GetBackupSchedules returns the list of configured backup schedules
+func (o *OperatorACL) GetBackupSchedules(key SiteKey) ([]storage.BackupSchedule, error) {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindBackupSchedule, teleservices.VerbList); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return o.operator.GetBackupSchedules(key)
+}
+
+// UpsertBackupSchedule creates or updates the backup schedule
+func (o *OperatorACL) UpsertBackupSchedule(key SiteKey, schedule storage.BackupSchedule) error {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindBackupSchedule, teleservices.VerbCreate); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := o.ClusterAction(key.SiteDomain, storage.KindBackupSchedule, teleservices.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	return o.operator.UpsertBackupSchedule(key, schedule)
+}
+
+// DeleteBackupSchedule deletes the backup schedule specified with name
+func (o *OperatorACL) DeleteBackupSchedule(key SiteKey, name string) error {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindBackupSchedule, teleservices.VerbDelete); err != nil {
+		return trace.Wrap(err)
+	}
+	return o.operator.DeleteBackupSchedule(key, name)
+}
+
+// GetBackups returns the list of backups stored in the cluster
+func (o *OperatorACL) GetBackups(key SiteKey) ([]storage.Backup, error) {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindBackupSchedule, teleservices.VerbList); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return o.operator.GetBackups(key)
+}
+
+// GetBackupData returns the tarball of the backup specified with name
+func (o *OperatorACL) GetBackupData(key SiteKey, name string) (io.ReadCloser, error) {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindBackupSchedule, teleservices.VerbRead); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return o.operator.GetBackupData(key, name)
+}
+
+// GetMaintenanceWindow returns the cluster maintenance window
+func (o *OperatorACL) GetMaintenanceWindow(key SiteKey) (storage.MaintenanceWindow, error) {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindMaintenanceWindow, teleservices.VerbRead); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return o.operator.GetMaintenanceWindow(key)
+}
+
+// UpsertMaintenanceWindow creates or updates the cluster maintenance window
+func (o *OperatorACL) UpsertMaintenanceWindow(key SiteKey, window storage.MaintenanceWindow) error {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindMaintenanceWindow, teleservices.VerbCreate); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := o.ClusterAction(key.SiteDomain, storage.KindMaintenanceWindow, teleservices.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	return o.operator.UpsertMaintenanceWindow(key, window)
+}
+
+// DeleteMaintenanceWindow deletes the cluster maintenance window
+func (o *OperatorACL) DeleteMaintenanceWindow(key SiteKey) error {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindMaintenanceWindow, teleservices.VerbDelete); err != nil {
+		return trace.Wrap(err)
+	}
+	return o.operator.DeleteMaintenanceWindow(key)
+}
+
+// GetTrustedKeys returns the list of keys trusted to sign packages
+func (o *OperatorACL) GetTrustedKeys(key SiteKey) ([]storage.TrustedKey, error) {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindTrustedKey, teleservices.VerbList); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return o.operator.GetTrustedKeys(key)
+}
+
+// UpsertTrustedKey creates or updates the trusted key
+func (o *OperatorACL) UpsertTrustedKey(key SiteKey, trustedKey storage.TrustedKey) error {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindTrustedKey, teleservices.VerbCreate); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := o.ClusterAction(key.SiteDomain, storage.KindTrustedKey, teleservices.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	return o.operator.UpsertTrustedKey(key, trustedKey)
+}
+
+// DeleteTrustedKey deletes the trusted key specified with name
+func (o *OperatorACL) DeleteTrustedKey(key SiteKey, name string) error {
+	if err := o.ClusterAction(key.SiteDomain, storage.KindTrustedKey, teleservices.VerbDelete); err != nil {
+		return trace.Wrap(err)
+	}
+	return o.operator.DeleteTrustedKey(key, name)
+}
+
+// CreateFleetOperation creates a new operation that rolls out the change
+// across the clusters matching the selector in the request.
+// The user is required to have access to update each of the selected clusters
+func (o *OperatorACL) CreateFleetOperation(ctx context.Context, req CreateFleetOperationRequest) (*FleetOperationKey, error) {
+	if err := o.Action(storage.KindCluster, teleservices.VerbUpdate); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	clusters, err := o.operator.GetSites(req.AccountID)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	for _, cluster := range clusters {
+		if cluster.Local || !utils.MatchesLabels(cluster.Labels, req.Selector) {
+			continue
+		}
+		err := o.ClusterAction(cluster.Domain, storage.KindCluster, teleservices.VerbUpdate)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	return o.operator.CreateFleetOperation(ctx, req)
+}
+
+// GetFleetOperation returns the fleet operation specified with key
+func (o *OperatorACL) GetFleetOperation(key FleetOperationKey) (*storage.FleetOperation, error) {
+	if err := o.Action(storage.KindCluster, teleservices.VerbRead); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return o.operator.GetFleetOperation(key)
+}
+
+// GetFleetOperations returns fleet operations of the specified account
+func (o *OperatorACL) GetFleetOperations(accountID string) ([]storage.FleetOperation, error) {
+	if err := o.Action(storage.KindCluster, teleservices.VerbList); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return o.operator.GetFleetOperations(accountID)
+}
+
 // GetClusterEnvironmentVariables retrieves the cluster runtime environment variables
 func (o *OperatorACL) GetClusterEnvironmentVariables(key SiteKey) (storage.EnvironmentVariables, error) {
 	if err := o.ClusterAction(key.SiteDomain, storage.KindRuntimeEnvironment, teleservices.VerbList); err != nil {
@@ -130,6 +130,10 @@ type Operator interface {
 	RuntimeEnvironment
 	ClusterConfiguration
 	Audit
+	Backups
+	MaintenanceWindows
+	TrustedKeys
+	Fleet
 }
 
 // Accounts represents a collection of accounts in the portal
@@ -1314,6 +1318,11 @@ type CreateSiteAppUpdateOperationRequest struct {
 	App string `json:"package"`
 	// StartAgents specifies whether the operation will automatically start the update agents
 	StartAgents bool `json:"start_agents"`
+	// Strategy optionally defines how regular nodes are updated
+	Strategy *storage.UpdateStrategy `json:"strategy,omitempty"`
+	// DrainPolicy optionally defines how node drains handle pods
+	// whose eviction is blocked by pod disruption budgets
+	DrainPolicy *storage.DrainPolicy `json:"drain_policy,omitempty"`
 }
 
 // Check validates this request
@@ -1343,6 +1352,9 @@ type CreateUpdateEnvarsOperationRequest struct {
 	ClusterKey SiteKey `json:"cluster_key"`
 	// Env specifies the new cluster environment variables
 	Env map[string]string `json:"env"`
+	// Concurrency specifies the maximum number of regular nodes
+	// updated at the same time. Nodes are updated one by one if unspecified
+	Concurrency int `json:"concurrency,omitempty"`
 }
 
 // CreateUpdateConfigOperationRequest is a request
@@ -1352,6 +1364,11 @@ type CreateUpdateConfigOperationRequest struct {
 	ClusterKey SiteKey `json:"cluster_key"`
 	// Config specifies the new configuration as JSON-encoded payload
 	Config []byte `json:"config"`
+	// StartAgents specifies whether the operation will automatically start the update agents
+	StartAgents bool `json:"start_agents,omitempty"`
+	// Concurrency specifies the maximum number of regular nodes
+	// updated at the same time. Nodes are updated one by one if unspecified
+	Concurrency int `json:"concurrency,omitempty"`
 }
 
 // UpdateClusterEnvironRequest is a request
@@ -1861,6 +1878,133 @@ type SMTP interface {
 	DeleteSMTPConfig(SiteKey) error
 }
 
+// Backups defines the interface to manage scheduled cluster backups
+type Backups interface {
+	// GetBackupSchedules returns the list of configured backup schedules
+	GetBackupSchedules(SiteKey) ([]storage.BackupSchedule, error)
+	// UpsertBackupSchedule creates or updates the backup schedule
+	UpsertBackupSchedule(SiteKey, storage.BackupSchedule) error
+	// DeleteBackupSchedule deletes the backup schedule specified with name
+	DeleteBackupSchedule(key SiteKey, name string) error
+	// GetBackups returns the list of backups stored in the cluster
+	GetBackups(SiteKey) ([]storage.Backup, error)
+	// GetBackupData returns the tarball of the backup specified with name
+	GetBackupData(key SiteKey, name string) (io.ReadCloser, error)
+}
+
+// MaintenanceWindows defines the interface to manage the cluster maintenance window
+type MaintenanceWindows interface {
+	// GetMaintenanceWindow returns the cluster maintenance window
+	GetMaintenanceWindow(SiteKey) (storage.MaintenanceWindow, error)
+	// UpsertMaintenanceWindow creates or updates the cluster maintenance window
+	UpsertMaintenanceWindow(SiteKey, storage.MaintenanceWindow) error
+	// DeleteMaintenanceWindow deletes the cluster maintenance window
+	DeleteMaintenanceWindow(SiteKey) error
+}
+
+// TrustedKeys defines the interface to manage keys trusted to sign
+// packages pulled into the cluster
+type TrustedKeys interface {
+	// GetTrustedKeys returns the list of keys trusted to sign packages
+	GetTrustedKeys(SiteKey) ([]storage.TrustedKey, error)
+	// UpsertTrustedKey creates or updates the trusted key
+	UpsertTrustedKey(SiteKey, storage.TrustedKey) error
+	// DeleteTrustedKey deletes the trusted key specified with name
+	DeleteTrustedKey(key SiteKey, name string) error
+}
+
+// Fleet defines the interface to roll out operations across
+// multiple clusters connected to the Ops Center
+type Fleet interface {
+	// CreateFleetOperation creates a new operation that rolls out the change
+	// across the clusters matching the selector in the request
+	CreateFleetOperation(context.Context, CreateFleetOperationRequest) (*FleetOperationKey, error)
+	// GetFleetOperation returns the fleet operation specified with key
+	GetFleetOperation(FleetOperationKey) (*storage.FleetOperation, error)
+	// GetFleetOperations returns fleet operations of the specified account
+	GetFleetOperations(accountID string) ([]storage.FleetOperation, error)
+}
+
+// FleetOperationKey identifies a fleet operation
+type FleetOperationKey struct {
+	// AccountID is the ID of the account the operation belongs to
+	AccountID string `json:"account_id"`
+	// OperationID is the fleet operation ID
+	OperationID string `json:"operation_id"`
+}
+
+// Check makes sure the key is valid
+func (k FleetOperationKey) Check() error {
+	if k.AccountID == "" {
+		return trace.BadParameter("missing AccountID")
+	}
+	if k.OperationID == "" {
+		return trace.BadParameter("missing OperationID")
+	}
+	return nil
+}
+
+// String returns the key's textual representation
+func (k FleetOperationKey) String() string {
+	return fmt.Sprintf("fleet operation(%v/%v)", k.AccountID, k.OperationID)
+}
+
+// CreateFleetOperationRequest is a request to roll out an application
+// update or a configuration change across multiple clusters
+type CreateFleetOperationRequest struct {
+	// AccountID is the ID of the account the clusters belong to
+	AccountID string `json:"account_id"`
+	// Type is the fleet operation type
+	Type string `json:"type"`
+	// Selector selects the clusters with all of the specified labels.
+	// An empty selector matches all clusters
+	Selector map[string]string `json:"selector,omitempty"`
+	// App is the application package to update the clusters to.
+	// Required for the application update
+	App string `json:"app,omitempty"`
+	// Config is the JSON-encoded cluster configuration.
+	// Required for the configuration change
+	Config []byte `json:"config,omitempty"`
+	// WaveSize is the maximum number of clusters in a single wave.
+	// If unspecified, all clusters are operated on in a single wave
+	WaveSize int `json:"wave_size,omitempty"`
+	// Concurrency is the maximum number of clusters within a wave
+	// operated on at the same time. Defaults to the wave size
+	Concurrency int `json:"concurrency,omitempty"`
+	// StopOnFailure specifies whether the rollout stops after the wave
+	// with a failed cluster operation
+	StopOnFailure bool `json:"stop_on_failure"`
+}
+
+// Check makes sure the request is valid
+func (r CreateFleetOperationRequest) Check() error {
+	if r.AccountID == "" {
+		return trace.BadParameter("missing AccountID")
+	}
+	switch r.Type {
+	case FleetOperationUpdate:
+		if r.App == "" {
+			return trace.BadParameter("application update requires application package")
+		}
+		if _, err := loc.ParseLocator(r.App); err != nil {
+			return trace.Wrap(err)
+		}
+	case FleetOperationUpdateConfig:
+		if len(r.Config) == 0 {
+			return trace.BadParameter("configuration change requires cluster configuration")
+		}
+	default:
+		return trace.BadParameter("unsupported fleet operation type %q", r.Type)
+	}
+	if r.WaveSize < 0 {
+		return trace.BadParameter("wave size cannot be negative")
+	}
+	if r.Concurrency < 0 {
+		return trace.BadParameter("concurrency cannot be negative")
+	}
+	return nil
+}
+
 // Monitoring defines the interface to manage monitoring and metrics
 type Monitoring interface {
 	// GetRetentionPolicies returns a list of retention policies for the site
@@ -1131,6 +1131,181 @@ func (c *Client) DeleteAlertTarget(key ops.SiteKey) error {
 	return trace.Wrap(err)
 }
 
+// GetBackupSchedules returns the list of configured backup schedules
+func (c *Client) GetBackupSchedules(key ops.SiteKey) ([]storage.BackupSchedule, error) {
+	response, err := c.Get(c.Endpoint(
+		"accounts", key.AccountID, "sites", key.SiteDomain, "backupschedules"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	var items []json.RawMessage
+	if err = json.Unmarshal(response.Bytes(), &items); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	schedules := make([]storage.BackupSchedule, len(items))
+	for i, item := range items {
+		schedule, err := storage.UnmarshalBackupSchedule(item)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		schedules[i] = schedule
+	}
+	return schedules, nil
+}
+
+// UpsertBackupSchedule creates or updates the backup schedule
+func (c *Client) UpsertBackupSchedule(key ops.SiteKey, schedule storage.BackupSchedule) error {
+	bytes, err := storage.MarshalBackupSchedule(schedule)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	_, err = c.PutJSON(c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain,
+		"backupschedules", schedule.GetName()),
+		&UpsertResourceRawReq{Resource: bytes})
+	return trace.Wrap(err)
+}
+
+// DeleteBackupSchedule deletes the backup schedule specified with name
+func (c *Client) DeleteBackupSchedule(key ops.SiteKey, name string) error {
+	_, err := c.Delete(c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain, "backupschedules", name))
+	return trace.Wrap(err)
+}
+
+// GetBackups returns the list of backups stored in the cluster
+func (c *Client) GetBackups(key ops.SiteKey) ([]storage.Backup, error) {
+	response, err := c.Get(c.Endpoint(
+		"accounts", key.AccountID, "sites", key.SiteDomain, "backups"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var backups []storage.Backup
+	if err = json.Unmarshal(response.Bytes(), &backups); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return backups, nil
+}
+
+// GetBackupData returns the tarball of the backup specified with name
+func (c *Client) GetBackupData(key ops.SiteKey, name string) (io.ReadCloser, error) {
+	file, err := c.GetFile(c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain, "backups", name, "data"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return file.Body(), nil
+}
+
+// GetMaintenanceWindow returns the cluster maintenance window
+func (c *Client) GetMaintenanceWindow(key ops.SiteKey) (storage.MaintenanceWindow, error) {
+	response, err := c.Get(c.Endpoint(
+		"accounts", key.AccountID, "sites", key.SiteDomain, "maintenancewindow"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return storage.UnmarshalMaintenanceWindow(response.Bytes())
+}
+
+// UpsertMaintenanceWindow creates or updates the cluster maintenance window
+func (c *Client) UpsertMaintenanceWindow(key ops.SiteKey, window storage.MaintenanceWindow) error {
+	bytes, err := storage.MarshalMaintenanceWindow(window)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	_, err = c.PutJSON(c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain,
+		"maintenancewindow"), &UpsertResourceRawReq{Resource: bytes})
+	return trace.Wrap(err)
+}
+
+// DeleteMaintenanceWindow deletes the cluster maintenance window
+func (c *Client) DeleteMaintenanceWindow(key ops.SiteKey) error {
+	_, err := c.Delete(c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain, "maintenancewindow"))
+	return trace.Wrap(err)
+}
+
+// GetTrustedKeys returns the list of keys trusted to sign packages
+func (c *Client) GetTrustedKeys(key ops.SiteKey) ([]storage.TrustedKey, error) {
+	response, err := c.Get(c.Endpoint(
+		"accounts", key.AccountID, "sites", key.SiteDomain, "trustedkeys"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	var items []json.RawMessage
+	if err = json.Unmarshal(response.Bytes(), &items); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	keys := make([]storage.TrustedKey, len(items))
+	for i, item := range items {
+		trustedKey, err := storage.UnmarshalTrustedKey(item)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		keys[i] = trustedKey
+	}
+	return keys, nil
+}
+
+// UpsertTrustedKey creates or updates the trusted key
+func (c *Client) UpsertTrustedKey(key ops.SiteKey, trustedKey storage.TrustedKey) error {
+	bytes, err := storage.MarshalTrustedKey(trustedKey)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	_, err = c.PutJSON(c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain,
+		"trustedkeys", trustedKey.GetName()),
+		&UpsertResourceRawReq{Resource: bytes})
+	return trace.Wrap(err)
+}
+
+// DeleteTrustedKey deletes the trusted key specified with name
+func (c *Client) DeleteTrustedKey(key ops.SiteKey, name string) error {
+	_, err := c.Delete(c.Endpoint("accounts", key.AccountID, "sites", key.SiteDomain, "trustedkeys", name))
+	return trace.Wrap(err)
+}
+
+// CreateFleetOperation creates a new operation that rolls out the change
+// across the clusters matching the selector in the request
+func (c *Client) CreateFleetOperation(ctx context.Context, req ops.CreateFleetOperationRequest) (*ops.FleetOperationKey, error) {
+	out, err := c.PostJSON(c.Endpoint("accounts", req.AccountID, "fleetoperations"), req)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var key ops.FleetOperationKey
+	if err := json.Unmarshal(out.Bytes(), &key); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &key, nil
+}
+
+// GetFleetOperation returns the fleet operation specified with key
+func (c *Client) GetFleetOperation(key ops.FleetOperationKey) (*storage.FleetOperation, error) {
+	out, err := c.Get(c.Endpoint("accounts", key.AccountID, "fleetoperations", key.OperationID), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var op storage.FleetOperation
+	if err := json.Unmarshal(out.Bytes(), &op); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &op, nil
+}
+
+// GetFleetOperations returns fleet operations of the specified account
+func (c *Client) GetFleetOperations(accountID string) ([]storage.FleetOperation, error) {
+	out, err := c.Get(c.Endpoint("accounts", accountID, "fleetoperations"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var operations []storage.FleetOperation
+	if err := json.Unmarshal(out.Bytes(), &operations); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return operations, nil
+}
+
 // GetClusterEnvironmentVariables retrieves the cluster runtime environment variables
 func (c *Client) GetClusterEnvironmentVariables(key ops.SiteKey) (storage.EnvironmentVariables, error) {
 	response, err := c.Get(c.Endpoint(
@@ -0,0 +1,135 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opshandler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gravitational/gravity/lib/ops/opsclient"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/roundtrip"
+	telehttplib "github.com/gravitational/teleport/lib/httplib"
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	"github.com/julienschmidt/httprouter"
+)
+
+/* getBackupSchedules returns a list of backup schedules for the cluster
+
+     GET /portal/v1/accounts/:account_id/sites/:site_domain/backupschedules
+
+   Success Response:
+
+     []storage.BackupSchedule
+*/
+func (h *WebHandler) getBackupSchedules(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	schedules, err := context.Operator.GetBackupSchedules(siteKey(p))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, schedules)
+	return nil
+}
+
+/* upsertBackupSchedule creates or updates the specified backup schedule
+
+     PUT /portal/v1/accounts/:account_id/sites/:site_domain/backupschedules/:name
+
+   Success Response:
+
+     {
+       "message": "backup schedule updated"
+     }
+*/
+func (h *WebHandler) upsertBackupSchedule(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	var req opsclient.UpsertResourceRawReq
+	if err := telehttplib.ReadJSON(r, &req); err != nil {
+		return trace.Wrap(err)
+	}
+	schedule, err := storage.UnmarshalBackupSchedule(req.Resource)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if req.TTL != 0 {
+		schedule.SetTTL(clockwork.NewRealClock(), req.TTL)
+	}
+	err = context.Operator.UpsertBackupSchedule(siteKey(p), schedule)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, statusOK("backup schedule updated"))
+	return nil
+}
+
+/* deleteBackupSchedule deletes the specified backup schedule
+
+     DELETE /portal/v1/accounts/:account_id/sites/:site_domain/backupschedules/:name
+
+   Success Response:
+
+     {
+       "message": "backup schedule deleted"
+     }
+*/
+func (h *WebHandler) deleteBackupSchedule(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	err := context.Operator.DeleteBackupSchedule(siteKey(p), p.ByName("name"))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, statusOK("backup schedule deleted"))
+	return nil
+}
+
+/* getBackups returns a list of backups stored in the cluster
+
+     GET /portal/v1/accounts/:account_id/sites/:site_domain/backups
+
+   Success Response:
+
+     []storage.Backup
+*/
+func (h *WebHandler) getBackups(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	backups, err := context.Operator.GetBackups(siteKey(p))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, backups)
+	return nil
+}
+
+/* getBackupData streams the tarball of the specified backup
+
+     GET /portal/v1/accounts/:account_id/sites/:site_domain/backups/:name/data
+
+   Success Response:
+
+     binary stream with the backup tarball
+*/
+func (h *WebHandler) getBackupData(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	name := p.ByName("name")
+	data, err := context.Operator.GetBackupData(siteKey(p), name)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer data.Close()
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%v.tar.gz", name))
+	_, err = io.Copy(w, data)
+	return trace.Wrap(err)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opshandler
+
+import (
+	"net/http"
+
+	"github.com/gravitational/gravity/lib/ops"
+
+	"github.com/gravitational/roundtrip"
+	telehttplib "github.com/gravitational/teleport/lib/httplib"
+	"github.com/gravitational/trace"
+	"github.com/julienschmidt/httprouter"
+)
+
+/* createFleetOperation creates a new operation that rolls out the change
+   across the clusters matching the request selector
+
+     POST /portal/v1/accounts/:account_id/fleetoperations
+
+     {
+       "type": "update",
+       "selector": {"env": "staging"},
+       "app": "example.com/app:1.0.0",
+       "wave_size": 2,
+       "concurrency": 1,
+       "stop_on_failure": true
+     }
+
+   Success Response:
+
+     {
+       "account_id": "account id",
+       "operation_id": "operation id"
+     }
+*/
+func (h *WebHandler) createFleetOperation(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	var req ops.CreateFleetOperationRequest
+	if err := telehttplib.ReadJSON(r, &req); err != nil {
+		return trace.Wrap(err)
+	}
+	req.AccountID = p.ByName("account_id")
+	key, err := context.Operator.CreateFleetOperation(r.Context(), req)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, key)
+	return nil
+}
+
+/* getFleetOperation returns the specified fleet operation
+
+     GET /portal/v1/accounts/:account_id/fleetoperations/:operation_id
+
+   Success Response:
+
+     storage.FleetOperation
+*/
+func (h *WebHandler) getFleetOperation(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	operation, err := context.Operator.GetFleetOperation(ops.FleetOperationKey{
+		AccountID:   p.ByName("account_id"),
+		OperationID: p.ByName("operation_id"),
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, operation)
+	return nil
+}
+
+/* getFleetOperations returns fleet operations of the specified account
+
+     GET /portal/v1/accounts/:account_id/fleetoperations
+
+   Success Response:
+
+     []storage.FleetOperation
+*/
+func (h *WebHandler) getFleetOperations(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	operations, err := context.Operator.GetFleetOperations(p.ByName("account_id"))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, operations)
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opshandler
+
+import (
+	"net/http"
+
+	"github.com/gravitational/gravity/lib/ops/opsclient"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/roundtrip"
+	telehttplib "github.com/gravitational/teleport/lib/httplib"
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	"github.com/julienschmidt/httprouter"
+)
+
+/* getMaintenanceWindow returns the cluster maintenance window
+
+     GET /portal/v1/accounts/:account_id/sites/:site_domain/maintenancewindow
+
+   Success Response:
+
+     storage.MaintenanceWindow
+*/
+func (h *WebHandler) getMaintenanceWindow(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	window, err := context.Operator.GetMaintenanceWindow(siteKey(p))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, window)
+	return nil
+}
+
+/* upsertMaintenanceWindow creates or updates the cluster maintenance window
+
+     PUT /portal/v1/accounts/:account_id/sites/:site_domain/maintenancewindow
+
+   Success Response:
+
+     {
+       "message": "maintenance window updated"
+     }
+*/
+func (h *WebHandler) upsertMaintenanceWindow(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	var req opsclient.UpsertResourceRawReq
+	if err := telehttplib.ReadJSON(r, &req); err != nil {
+		return trace.Wrap(err)
+	}
+	window, err := storage.UnmarshalMaintenanceWindow(req.Resource)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if req.TTL != 0 {
+		window.SetTTL(clockwork.NewRealClock(), req.TTL)
+	}
+	err = context.Operator.UpsertMaintenanceWindow(siteKey(p), window)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, statusOK("maintenance window updated"))
+	return nil
+}
+
+/* deleteMaintenanceWindow deletes the cluster maintenance window
+
+     DELETE /portal/v1/accounts/:account_id/sites/:site_domain/maintenancewindow
+
+   Success Response:
+
+     {
+       "message": "maintenance window deleted"
+     }
+*/
+func (h *WebHandler) deleteMaintenanceWindow(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	err := context.Operator.DeleteMaintenanceWindow(siteKey(p))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, statusOK("maintenance window deleted"))
+	return nil
+}
@@ -238,6 +238,28 @@ func NewWebHandler(cfg WebHandlerConfig) (*WebHandler, error) {
 	h.PUT("/portal/v1/accounts/:account_id/sites/:site_domain/monitoring/alert-targets", h.needsAuth(h.updateAlertTarget))
 	h.DELETE("/portal/v1/accounts/:account_id/sites/:site_domain/monitoring/alert-targets", h.needsAuth(h.deleteAlertTarget))
 
+	// backups
+	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/backupschedules", h.needsAuth(h.getBackupSchedules))
+	h.PUT("/portal/v1/accounts/:account_id/sites/:site_domain/backupschedules/:name", h.needsAuth(h.upsertBackupSchedule))
+	h.DELETE("/portal/v1/accounts/:account_id/sites/:site_domain/backupschedules/:name", h.needsAuth(h.deleteBackupSchedule))
+	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/backups", h.needsAuth(h.getBackups))
+	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/backups/:name/data", h.needsAuth(h.getBackupData))
+
+	// maintenance window
+	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/maintenancewindow", h.needsAuth(h.getMaintenanceWindow))
+	h.PUT("/portal/v1/accounts/:account_id/sites/:site_domain/maintenancewindow", h.needsAuth(h.upsertMaintenanceWindow))
+	h.DELETE("/portal/v1/accounts/:account_id/sites/:site_domain/maintenancewindow", h.needsAuth(h.deleteMaintenanceWindow))
+
+	// trusted keys
+	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/trustedkeys", h.needsAuth(h.getTrustedKeys))
+	h.PUT("/portal/v1/accounts/:account_id/sites/:site_domain/trustedkeys/:name", h.needsAuth(h.upsertTrustedKey))
+	h.DELETE("/portal/v1/accounts/:account_id/sites/:site_domain/trustedkeys/:name", h.needsAuth(h.deleteTrustedKey))
+
+	// fleet operations
+	h.POST("/portal/v1/accounts/:account_id/fleetoperations", h.needsAuth(h.createFleetOperation))
+	h.GET("/portal/v1/accounts/:account_id/fleetoperations", h.needsAuth(h.getFleetOperations))
+	h.GET("/portal/v1/accounts/:account_id/fleetoperations/:operation_id", h.needsAuth(h.getFleetOperation))
+
 	// environment variables
 	h.GET("/portal/v1/accounts/:account_id/sites/:site_domain/envars", h.needsAuth(h.getEnvironmentVariables))
 	h.PUT("/portal/v1/accounts/:account_id/sites/:site_domain/envars", h.needsAuth(h.updateEnvironmentVariables))
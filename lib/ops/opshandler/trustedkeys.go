@@ -0,0 +1,96 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opshandler
+
+import (
+	"net/http"
+
+	"github.com/gravitational/gravity/lib/ops/opsclient"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/roundtrip"
+	telehttplib "github.com/gravitational/teleport/lib/httplib"
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	"github.com/julienschmidt/httprouter"
+)
+
+/* getTrustedKeys returns a list of keys trusted to sign packages
+
+     GET /portal/v1/accounts/:account_id/sites/:site_domain/trustedkeys
+
+   Success Response:
+
+     []storage.TrustedKey
+*/
+func (h *WebHandler) getTrustedKeys(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	keys, err := context.Operator.GetTrustedKeys(siteKey(p))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, keys)
+	return nil
+}
+
+/* upsertTrustedKey creates or updates the specified trusted key
+
+     PUT /portal/v1/accounts/:account_id/sites/:site_domain/trustedkeys/:name
+
+   Success Response:
+
+     {
+       "message": "trusted key updated"
+     }
+*/
+func (h *WebHandler) upsertTrustedKey(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	var req opsclient.UpsertResourceRawReq
+	if err := telehttplib.ReadJSON(r, &req); err != nil {
+		return trace.Wrap(err)
+	}
+	key, err := storage.UnmarshalTrustedKey(req.Resource)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if req.TTL != 0 {
+		key.SetTTL(clockwork.NewRealClock(), req.TTL)
+	}
+	err = context.Operator.UpsertTrustedKey(siteKey(p), key)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, statusOK("trusted key updated"))
+	return nil
+}
+
+/* deleteTrustedKey deletes the specified trusted key
+
+     DELETE /portal/v1/accounts/:account_id/sites/:site_domain/trustedkeys/:name
+
+   Success Response:
+
+     {
+       "message": "trusted key deleted"
+     }
+*/
+func (h *WebHandler) deleteTrustedKey(w http.ResponseWriter, r *http.Request, p httprouter.Params, context *HandlerContext) error {
+	err := context.Operator.DeleteTrustedKey(siteKey(p), p.ByName("name"))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roundtrip.ReplyJSON(w, http.StatusOK, statusOK("trusted key deleted"))
+	return nil
+}
@@ -356,7 +356,11 @@ func (r *Router) CreateUpdateEnvarsOperation(ctx context.Context, req ops.Create
 
 // CreateUpdateConfigOperation creates a new operation to update cluster configuration
 func (r *Router) CreateUpdateConfigOperation(ctx context.Context, req ops.CreateUpdateConfigOperationRequest) (*ops.SiteOperationKey, error) {
-	return r.Local.CreateUpdateConfigOperation(ctx, req)
+	client, err := r.RemoteClient(req.ClusterKey.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.CreateUpdateConfigOperation(ctx, req)
 }
 
 func (r *Router) GetSiteOperationLogs(key ops.SiteOperationKey) (io.ReadCloser, error) {
@@ -675,6 +679,121 @@ func (r *Router) DeleteAlertTarget(key ops.SiteKey) error {
 	return client.DeleteAlertTarget(key)
 }
 
+// GetBackupSchedules returns the list of configured backup schedules
+func (r *Router) GetBackupSchedules(key ops.SiteKey) ([]storage.BackupSchedule, error) {
+	client, err := r.RemoteClient(key.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.GetBackupSchedules(key)
+}
+
+// UpsertBackupSchedule creates or updates the backup schedule
+func (r *Router) UpsertBackupSchedule(key ops.SiteKey, schedule storage.BackupSchedule) error {
+	client, err := r.RemoteClient(key.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.UpsertBackupSchedule(key, schedule)
+}
+
+// DeleteBackupSchedule deletes the backup schedule specified with name
+func (r *Router) DeleteBackupSchedule(key ops.SiteKey, name string) error {
+	client, err := r.RemoteClient(key.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.DeleteBackupSchedule(key, name)
+}
+
+// GetBackups returns the list of backups stored in the cluster
+func (r *Router) GetBackups(key ops.SiteKey) ([]storage.Backup, error) {
+	client, err := r.RemoteClient(key.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.GetBackups(key)
+}
+
+// GetBackupData returns the tarball of the backup specified with name
+func (r *Router) GetBackupData(key ops.SiteKey, name string) (io.ReadCloser, error) {
+	client, err := r.RemoteClient(key.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.GetBackupData(key, name)
+}
+
+// GetMaintenanceWindow returns the cluster maintenance window
+func (r *Router) GetMaintenanceWindow(key ops.SiteKey) (storage.MaintenanceWindow, error) {
+	client, err := r.RemoteClient(key.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.GetMaintenanceWindow(key)
+}
+
+// UpsertMaintenanceWindow creates or updates the cluster maintenance window
+func (r *Router) UpsertMaintenanceWindow(key ops.SiteKey, window storage.MaintenanceWindow) error {
+	client, err := r.RemoteClient(key.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.UpsertMaintenanceWindow(key, window)
+}
+
+// DeleteMaintenanceWindow deletes the cluster maintenance window
+func (r *Router) DeleteMaintenanceWindow(key ops.SiteKey) error {
+	client, err := r.RemoteClient(key.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.DeleteMaintenanceWindow(key)
+}
+
+// GetTrustedKeys returns the list of keys trusted to sign packages
+func (r *Router) GetTrustedKeys(key ops.SiteKey) ([]storage.TrustedKey, error) {
+	client, err := r.RemoteClient(key.SiteDomain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return client.GetTrustedKeys(key)
+}
+
+// UpsertTrustedKey creates or updates the trusted key
+func (r *Router) UpsertTrustedKey(key ops.SiteKey, trustedKey storage.TrustedKey) error {
+	client, err := r.RemoteClient(key.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.UpsertTrustedKey(key, trustedKey)
+}
+
+// DeleteTrustedKey deletes the trusted key specified with name
+func (r *Router) DeleteTrustedKey(key ops.SiteKey, name string) error {
+	client, err := r.RemoteClient(key.SiteDomain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return client.DeleteTrustedKey(key, name)
+}
+
+// CreateFleetOperation creates a new operation that rolls out the change
+// across the clusters matching the selector in the request
+func (r *Router) CreateFleetOperation(ctx context.Context, req ops.CreateFleetOperationRequest) (*ops.FleetOperationKey, error) {
+	return r.Local.CreateFleetOperation(ctx, req)
+}
+
+// GetFleetOperation returns the fleet operation specified with key
+func (r *Router) GetFleetOperation(key ops.FleetOperationKey) (*storage.FleetOperation, error) {
+	return r.Local.GetFleetOperation(key)
+}
+
+// GetFleetOperations returns fleet operations of the specified account
+func (r *Router) GetFleetOperations(accountID string) ([]storage.FleetOperation, error) {
+	return r.Local.GetFleetOperations(accountID)
+}
+
 // GetClusterEnvironmentVariables retrieves the cluster runtime environment variables
 func (r *Router) GetClusterEnvironmentVariables(key ops.SiteKey) (storage.EnvironmentVariables, error) {
 	client, err := r.RemoteClient(key.SiteDomain)
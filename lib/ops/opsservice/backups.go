@@ -0,0 +1,66 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opsservice
+
+import (
+	"io"
+
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+)
+
+// GetBackupSchedules returns the list of configured backup schedules
+func (o *Operator) GetBackupSchedules(key ops.SiteKey) ([]storage.BackupSchedule, error) {
+	return o.backend().GetBackupSchedules()
+}
+
+// UpsertBackupSchedule creates or updates the backup schedule
+func (o *Operator) UpsertBackupSchedule(key ops.SiteKey, schedule storage.BackupSchedule) error {
+	if err := schedule.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(o.backend().UpsertBackupSchedule(schedule))
+}
+
+// DeleteBackupSchedule deletes the backup schedule specified with name.
+// Backups taken according to the schedule are kept
+func (o *Operator) DeleteBackupSchedule(key ops.SiteKey, name string) error {
+	return trace.Wrap(o.backend().DeleteBackupSchedule(name))
+}
+
+// GetBackups returns the list of backups stored in the cluster
+func (o *Operator) GetBackups(key ops.SiteKey) ([]storage.Backup, error) {
+	return o.backend().GetBackups()
+}
+
+// GetBackupData returns the tarball of the backup specified with name
+func (o *Operator) GetBackupData(key ops.SiteKey, name string) (io.ReadCloser, error) {
+	if o.cfg.Objects == nil {
+		return nil, trace.NotImplemented("backup storage is not configured")
+	}
+	backup, err := o.backend().GetBackup(name)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	reader, err := o.cfg.Objects.OpenBLOB(backup.SHA512)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return reader, nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/storage/clusterconfig"
+	"github.com/gravitational/gravity/lib/utils"
 
 	"github.com/gravitational/rigging"
 	"github.com/gravitational/trace"
@@ -41,6 +42,9 @@ func (o *Operator) CreateUpdateConfigOperation(ctx context.Context, req ops.Crea
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	if req.Concurrency < 0 {
+		return nil, trace.BadParameter("concurrency can not be negative")
+	}
 	cluster, err := o.openSite(req.ClusterKey)
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -151,17 +155,46 @@ func (s *site) createUpdateConfigOperation(ctx context.Context, req ops.CreateUp
 		Updated:    s.clock().UtcNow(),
 		State:      ops.OperationUpdateConfigInProgress,
 		UpdateConfig: &storage.UpdateConfigOperationState{
-			PrevConfig: prevConfig,
-			Config:     req.Config,
+			PrevConfig:  prevConfig,
+			Config:      req.Config,
+			Concurrency: req.Concurrency,
 		},
 	}
 	key, err := s.getOperationGroup().createSiteOperation(op)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	if !req.StartAgents {
+		return key, nil
+	}
+	opCtx, err := s.newOperationContext(op)
+	if err != nil {
+		return key, trace.Wrap(err)
+	}
+	defer opCtx.Close()
+	err = s.startUpdateConfigAgent(ctx, opCtx)
+	if err != nil {
+		if errReset := ops.FailOperationAndResetCluster(*key, s.service, err.Error()); errReset != nil {
+			s.WithError(errReset).WithField("operation", key).Warn("Failed to mark operation as failed.")
+		}
+		return key, trace.Wrap(err, "configuration update operation was created "+
+			"but the automatic update agent failed to start")
+	}
 	return key, nil
 }
 
+// startUpdateConfigAgent initializes the plan of the configuration update
+// operation on one of the master nodes and deploys agents to execute it
+func (s *site) startUpdateConfigAgent(ctx context.Context, opCtx *operationContext) error {
+	err := s.executeOnMaster(ctx, opCtx, func(commands utils.SSHCommands, agentDir string) utils.SSHCommands {
+		return commands.
+			C("%s update init-plan", constants.GravityBin).
+			C("%s agent deploy --leader=%s --node=%s", constants.GravityBin,
+				constants.RPCAgentUpdateConfigFunction, constants.RPCAgentSyncPlanFunction)
+	})
+	return trace.Wrap(err)
+}
+
 func getOrCreateClusterConfigMap(client corev1.ConfigMapInterface) (configmap *v1.ConfigMap, err error) {
 	configmap, err = client.Get(constants.ClusterConfigurationMap, metav1.GetOptions{})
 	if err != nil {
@@ -40,6 +40,9 @@ func (o *Operator) CreateUpdateEnvarsOperation(ctx context.Context, r ops.Create
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	if r.Concurrency < 0 {
+		return nil, trace.BadParameter("concurrency can not be negative")
+	}
 	cluster, err := o.openSite(r.ClusterKey)
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -148,8 +151,9 @@ func (s *site) createUpdateEnvarsOperation(ctx context.Context, req ops.CreateUp
 		Updated:    s.clock().UtcNow(),
 		State:      ops.OperationUpdateRuntimeEnvironInProgress,
 		UpdateEnviron: &storage.UpdateEnvarsOperationState{
-			PrevEnv: prevEnv,
-			Env:     req.Env,
+			PrevEnv:     prevEnv,
+			Env:         req.Env,
+			Concurrency: req.Concurrency,
 		},
 	}
 	key, err := s.getOperationGroup().createSiteOperation(op)
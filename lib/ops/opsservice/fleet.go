@@ -0,0 +1,92 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opsservice
+
+import (
+	"context"
+
+	"github.com/gravitational/gravity/lib/fleet"
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/storage/clusterconfig"
+
+	"github.com/gravitational/trace"
+)
+
+// CreateFleetOperation creates a new operation that rolls out the change
+// across the clusters matching the selector in the request.
+// The operation is executed by the fleet executor running in the Ops Center
+func (o *Operator) CreateFleetOperation(ctx context.Context, req ops.CreateFleetOperationRequest) (*ops.FleetOperationKey, error) {
+	if !o.isOpsCenter() {
+		return nil, trace.BadParameter("fleet operations can only be created in the Ops Center")
+	}
+	if req.Type == ops.FleetOperationUpdateConfig {
+		if _, err := clusterconfig.Unmarshal(req.Config); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	clusters, err := o.backend().GetSites(req.AccountID)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	operation, err := fleet.NewOperation(req, clusters)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if req.Type == ops.FleetOperationUpdate {
+		// Make sure the clusters can pull the application from the Ops Center
+		locator, err := loc.ParseLocator(req.App)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if _, err := o.cfg.Apps.GetApp(*locator); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	operation.Created = o.clock().UtcNow()
+	operation.Updated = operation.Created
+	operation.CreatedBy = storage.UserFromContext(ctx)
+	_, err = o.backend().CreateFleetOperation(*operation)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &ops.FleetOperationKey{
+		AccountID:   operation.AccountID,
+		OperationID: operation.ID,
+	}, nil
+}
+
+// GetFleetOperation returns the fleet operation specified with key
+func (o *Operator) GetFleetOperation(key ops.FleetOperationKey) (*storage.FleetOperation, error) {
+	if err := key.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	operation, err := o.backend().GetFleetOperation(key.OperationID)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if operation.AccountID != key.AccountID {
+		return nil, trace.NotFound("fleet operation %q not found", key.OperationID)
+	}
+	return operation, nil
+}
+
+// GetFleetOperations returns fleet operations of the specified account
+func (o *Operator) GetFleetOperations(accountID string) ([]storage.FleetOperation, error) {
+	return o.backend().GetFleetOperations(accountID)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package opsservice
+
+import (
+	"context"
+	"time"
+
+	apptest "github.com/gravitational/gravity/lib/app/service/test"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+	"gopkg.in/check.v1"
+)
+
+type FleetSuite struct {
+	services TestServices
+}
+
+var _ = check.Suite(&FleetSuite{})
+
+func (s *FleetSuite) SetUpTest(c *check.C) {
+	s.services = SetupTestServices(c)
+	_, err := s.services.Backend.CreateSite(storage.Site{
+		AccountID: defaults.SystemAccountID,
+		Domain:    "example.com",
+		Labels:    map[string]string{"env": "prod"},
+		Created:   time.Now(),
+	})
+	c.Assert(err, check.IsNil)
+}
+
+func (s *FleetSuite) TestUpdateRequiresExistingApp(c *check.C) {
+	req := ops.CreateFleetOperationRequest{
+		AccountID: defaults.SystemAccountID,
+		Type:      ops.FleetOperationUpdate,
+		Selector:  map[string]string{"env": "prod"},
+		App:       "gravitational.io/app:1.0.0",
+	}
+	_, err := s.services.Operator.CreateFleetOperation(context.TODO(), req)
+	c.Assert(trace.IsNotFound(err), check.Equals, true, check.Commentf("%v", err))
+	operations, err := s.services.Operator.GetFleetOperations(defaults.SystemAccountID)
+	c.Assert(err, check.IsNil)
+	c.Assert(operations, check.HasLen, 0)
+
+	apptest.CreateRuntimeApplication(s.services.Apps, c)
+	apptest.CreateDummyApplication(s.services.Apps, loc.MustParseLocator(req.App), c)
+	key, err := s.services.Operator.CreateFleetOperation(context.TODO(), req)
+	c.Assert(err, check.IsNil)
+	operation, err := s.services.Operator.GetFleetOperation(*key)
+	c.Assert(err, check.IsNil)
+	c.Assert(operation.App, check.Equals, req.App)
+}
@@ -33,6 +33,10 @@ func (s *site) createGarbageCollectOperation(ctx context.Context, req ops.Create
 		return nil, trace.Wrap(err, "garbage collection can only be started on an installed cluster")
 	}
 
+	if err := s.checkMaintenanceWindow(ops.MaintenanceGarbageCollect); err != nil {
+		return nil, trace.Wrap(err)
+	}
+
 	op := ops.SiteOperation{
 		ID:         uuid.New(),
 		AccountID:  s.key.AccountID,
@@ -0,0 +1,61 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opsservice
+
+import (
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+)
+
+// GetMaintenanceWindow returns the cluster maintenance window
+func (o *Operator) GetMaintenanceWindow(key ops.SiteKey) (storage.MaintenanceWindow, error) {
+	return o.backend().GetMaintenanceWindow()
+}
+
+// UpsertMaintenanceWindow creates or updates the cluster maintenance window
+func (o *Operator) UpsertMaintenanceWindow(key ops.SiteKey, window storage.MaintenanceWindow) error {
+	if err := window.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(o.backend().UpsertMaintenanceWindow(window))
+}
+
+// DeleteMaintenanceWindow deletes the cluster maintenance window
+func (o *Operator) DeleteMaintenanceWindow(key ops.SiteKey) error {
+	return trace.Wrap(o.backend().DeleteMaintenanceWindow())
+}
+
+// checkMaintenanceWindow returns an error if the operation described with
+// operationType is not allowed to start now according to the cluster
+// maintenance window.
+// If the window is configured to queue operations, the operation is allowed
+// to be created and is expected to wait for the window before it starts
+func (s *site) checkMaintenanceWindow(operationType string) error {
+	window, err := s.backend().GetMaintenanceWindow()
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil
+		}
+		return trace.Wrap(err)
+	}
+	if window.GetPolicy() == storage.MaintenancePolicyQueue {
+		return nil
+	}
+	return trace.Wrap(storage.CheckMaintenanceWindow(window, operationType, s.clock().UtcNow()))
+}
@@ -18,7 +18,6 @@ package opsservice
 
 import (
 	"github.com/gravitational/gravity/lib/constants"
-	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/ops/monitoring"
 	"github.com/gravitational/gravity/lib/storage"
@@ -27,7 +26,6 @@ import (
 	"github.com/gravitational/trace"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	kubelabels "k8s.io/apimachinery/pkg/labels"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
@@ -49,152 +47,33 @@ func (o *Operator) UpdateRetentionPolicy(req ops.UpdateRetentionPolicyRequest) e
 }
 
 // GetAlerts returns a list of configured monitoring alerts
-func (o *Operator) GetAlerts(key ops.SiteKey) (alerts []storage.Alert, err error) {
-	client, err := o.GetKubeClient()
-	if err != nil {
-		return nil, trace.Wrap(err)
-	}
-
-	labels := kubelabels.Set{
-		constants.MonitoringType: constants.MonitoringTypeAlert,
-	}
-	options := metav1.ListOptions{
-		LabelSelector: labels.String(),
-	}
-	configmaps, err := client.Core().ConfigMaps(defaults.MonitoringNamespace).List(options)
-	if err != nil {
-		return nil, trace.Wrap(rigging.ConvertError(err))
-	}
-
-	var errors []error
-	alerts = make([]storage.Alert, 0, len(configmaps.Items))
-	for _, config := range configmaps.Items {
-		data, ok := config.Data[constants.ResourceSpecKey]
-		if !ok {
-			continue
-		}
-		alert, err := storage.UnmarshalAlert([]byte(data))
-		if err != nil {
-			errors = append(errors, err)
-			continue
-		}
-		alerts = append(alerts, alert)
-	}
-
-	if len(errors) != 0 {
-		return nil, trace.NewAggregate(errors...)
-	}
-
-	return alerts, nil
+func (o *Operator) GetAlerts(key ops.SiteKey) ([]storage.Alert, error) {
+	return o.cfg.Monitoring.GetAlerts()
 }
 
 // UpdateAlert updates the specified monitoring alert
 func (o *Operator) UpdateAlert(key ops.SiteKey, alert storage.Alert) error {
-	client, err := o.GetKubeClient()
-	if err != nil {
-		return trace.Wrap(err)
-	}
-
-	data, err := storage.MarshalAlert(alert)
-	if err != nil {
-		return trace.Wrap(err)
-	}
-
-	labels := map[string]string{
-		constants.MonitoringType: constants.MonitoringTypeAlert,
-	}
-	return updateConfigMap(client.Core().ConfigMaps(defaults.MonitoringNamespace),
-		alert.GetName(), defaults.MonitoringNamespace, string(data), labels)
+	return o.cfg.Monitoring.UpdateAlert(alert)
 }
 
 // DeleteAlert deletes the specified monitoring alert
 func (o *Operator) DeleteAlert(key ops.SiteKey, name string) error {
-	client, err := o.GetKubeClient()
-	if err != nil {
-		return trace.Wrap(err)
-	}
-
-	labels := kubelabels.Set{
-		constants.MonitoringType: constants.MonitoringTypeAlert,
-	}
-	options := metav1.ListOptions{
-		LabelSelector: labels.String(),
-	}
-	configmaps, err := client.Core().ConfigMaps(defaults.MonitoringNamespace).List(options)
-	if err != nil {
-		return trace.Wrap(rigging.ConvertError(err))
-	}
-
-	var alert *v1.ConfigMap
-	for _, config := range configmaps.Items {
-		if config.Name == name {
-			alert = &config
-			break
-		}
-	}
-	if alert == nil {
-		return trace.NotFound("alert %q not found", name)
-	}
-
-	err = client.Core().ConfigMaps(defaults.MonitoringNamespace).Delete(name, nil)
-	return trace.Wrap(rigging.ConvertError(err))
+	return o.cfg.Monitoring.DeleteAlert(name)
 }
 
 // GetAlertTargets returns a list of configured monitoring alert targets
-func (o *Operator) GetAlertTargets(key ops.SiteKey) (targets []storage.AlertTarget, err error) {
-	client, err := o.GetKubeClient()
-	if err != nil {
-		return nil, trace.Wrap(err)
-	}
-
-	data, err := getConfigMap(client.Core().ConfigMaps(defaults.MonitoringNamespace),
-		constants.AlertTargetConfigMap)
-	if err != nil {
-		if trace.IsNotFound(err) {
-			return nil, trace.NotFound("alert target not found")
-		}
-		return nil, trace.Wrap(err)
-	}
-
-	target, err := storage.UnmarshalAlertTarget([]byte(data))
-	if err != nil {
-		return nil, trace.Wrap(err)
-	}
-
-	return []storage.AlertTarget{target}, nil
+func (o *Operator) GetAlertTargets(key ops.SiteKey) ([]storage.AlertTarget, error) {
+	return o.cfg.Monitoring.GetAlertTargets()
 }
 
 // UpdateAlertTarget updates the cluster monitoring alert target
 func (o *Operator) UpdateAlertTarget(key ops.SiteKey, target storage.AlertTarget) error {
-	client, err := o.GetKubeClient()
-	if err != nil {
-		return trace.Wrap(err)
-	}
-
-	data, err := storage.MarshalAlertTarget(target)
-	if err != nil {
-		return trace.Wrap(err)
-	}
-
-	labels := map[string]string{
-		constants.MonitoringType: constants.MonitoringTypeAlertTarget,
-	}
-	return updateConfigMap(client.Core().ConfigMaps(defaults.MonitoringNamespace),
-		constants.AlertTargetConfigMap, defaults.MonitoringNamespace, string(data), labels)
+	return o.cfg.Monitoring.UpdateAlertTarget(target)
 }
 
 // DeleteAlertTarget deletes the cluster monitoring alert target
 func (o *Operator) DeleteAlertTarget(key ops.SiteKey) error {
-	client, err := o.GetKubeClient()
-	if err != nil {
-		return trace.Wrap(err)
-	}
-
-	err = rigging.ConvertError(client.Core().ConfigMaps(defaults.MonitoringNamespace).Delete(constants.AlertTargetConfigMap, nil))
-	if trace.IsNotFound(err) {
-		return trace.NotFound("no alert targets found")
-	}
-	return trace.Wrap(err)
+	return o.cfg.Monitoring.DeleteAlertTarget()
 }
 
 func getConfigMap(client corev1.ConfigMapInterface, name string) (string, error) {
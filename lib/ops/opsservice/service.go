@@ -27,6 +27,7 @@ import (
 	"time"
 
 	appservice "github.com/gravitational/gravity/lib/app"
+	"github.com/gravitational/gravity/lib/blob"
 	"github.com/gravitational/gravity/lib/checks"
 	"github.com/gravitational/gravity/lib/clients"
 	"github.com/gravitational/gravity/lib/constants"
@@ -135,6 +136,9 @@ type Config struct {
 
 	// GetHelmClient is a factory method for creating a Helm client.
 	GetHelmClient helm.GetClientFunc
+
+	// Objects is the cluster BLOB storage used to keep cluster backups
+	Objects blob.Objects
 }
 
 // Operator implements Operator interface
@@ -0,0 +1,46 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opsservice
+
+import (
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/pack/signing"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+)
+
+// GetTrustedKeys returns the list of keys trusted to sign packages
+func (o *Operator) GetTrustedKeys(key ops.SiteKey) ([]storage.TrustedKey, error) {
+	return o.backend().GetTrustedKeys()
+}
+
+// UpsertTrustedKey creates or updates the trusted key
+func (o *Operator) UpsertTrustedKey(key ops.SiteKey, trustedKey storage.TrustedKey) error {
+	if err := trustedKey.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	if _, err := signing.ParsePublicKey(trustedKey.GetPublicKey()); err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(o.backend().UpsertTrustedKey(trustedKey))
+}
+
+// DeleteTrustedKey deletes the trusted key specified with name
+func (o *Operator) DeleteTrustedKey(key ops.SiteKey, name string) error {
+	return trace.Wrap(o.backend().DeleteTrustedKey(name))
+}
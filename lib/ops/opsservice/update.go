@@ -88,19 +88,8 @@ func (o *Operator) RotateTeleportConfig(req ops.RotateTeleportConfigRequest) (ma
 		return nil, nil, trace.Wrap(err)
 	}
 
-	operation, err := o.GetSiteOperation(req.Key)
-	if err != nil {
-		return nil, nil, trace.Wrap(err)
-	}
-
-	nodeProfile, err := o.getNodeProfile(*operation, req.Server)
-	if err != nil {
-		return nil, nil, trace.Wrap(err)
-	}
-
 	node := &ProvisionedServer{
-		Server:  req.Server,
-		Profile: *nodeProfile,
+		Server: req.Server,
 	}
 
 	cluster, err := o.openSite(req.Key.SiteKey())
@@ -124,6 +113,24 @@ func (o *Operator) RotateTeleportConfig(req ops.RotateTeleportConfigRequest) (ma
 		}
 	}
 
+	if req.DryRun {
+		if node.ClusterRole == string(schema.ServiceRoleMaster) {
+			masterConfig = &ops.RotatePackageResponse{Locator: *masterConfigPackage}
+		}
+		return masterConfig, &ops.RotatePackageResponse{Locator: *nodeConfigPackage}, nil
+	}
+
+	operation, err := o.GetSiteOperation(req.Key)
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+
+	nodeProfile, err := o.getNodeProfile(*operation, req.Server)
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	node.Profile = *nodeProfile
+
 	ctx, err := cluster.newOperationContext(*operation)
 	if err != nil {
 		return nil, nil, trace.Wrap(err)
@@ -356,6 +363,10 @@ func (s *site) createUpdateOperation(context context.Context, req ops.CreateSite
 		return nil, trace.Wrap(err)
 	}
 
+	if err := s.checkMaintenanceWindow(ops.MaintenanceUpdate); err != nil {
+		return nil, trace.Wrap(err)
+	}
+
 	op := ops.SiteOperation{
 		ID:          uuid.New(),
 		AccountID:   s.key.AccountID,
@@ -368,6 +379,8 @@ func (s *site) createUpdateOperation(context context.Context, req ops.CreateSite
 		Provisioner: installOperation.Provisioner,
 		Update: &storage.UpdateOperationState{
 			UpdatePackage: req.App,
+			Strategy:      req.Strategy,
+			DrainPolicy:   req.DrainPolicy,
 		},
 	}
 
@@ -427,6 +440,16 @@ func (s *site) createUpdateOperation(context context.Context, req ops.CreateSite
 }
 
 func (s *site) validateUpdateOperationRequest(req ops.CreateSiteAppUpdateOperationRequest, provisioner string) error {
+	if req.Strategy != nil {
+		if err := req.Strategy.Check(); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if req.DrainPolicy != nil {
+		if err := req.DrainPolicy.Check(); err != nil {
+			return trace.Wrap(err)
+		}
+	}
 	currentPackage, err := s.appPackage()
 	if err != nil {
 		return trace.Wrap(err)
@@ -449,6 +472,32 @@ func (s *site) validateUpdateOperationRequest(req ops.CreateSiteAppUpdateOperati
 
 // startUpdateAgent runs deploy procedure on one of the leader nodes
 func (s *site) startUpdateAgent(ctx context.Context, opCtx *operationContext, updateApp *app.Application) error {
+	gravityPackage, err := updateApp.Manifest.Dependencies.ByName(constants.GravityPackage)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = s.executeOnMaster(ctx, opCtx, func(commands utils.SSHCommands, agentDir string) utils.SSHCommands {
+		agentExecPath := filepath.Join(agentDir, constants.GravityBin)
+		secretsHostDir := filepath.Join(agentDir, defaults.SecretsDir)
+		return commands.
+			// extract new gravity version
+			C("rm -rf %s", secretsHostDir).
+			C("mkdir -p %s", secretsHostDir).
+			C("%s package export --file-mask=%o %s %s --ops-url=%s --insecure --quiet",
+				constants.GravityBin, defaults.SharedExecutableMask,
+				gravityPackage.String(), agentExecPath, defaults.GravityServiceURL).
+			C("%s update init-plan", agentExecPath).
+			// distribute agents and upgrade process
+			C("%s agent deploy --leader=%s --node=%s", agentExecPath,
+				constants.RPCAgentUpgradeFunction, constants.RPCAgentSyncPlanFunction)
+	})
+	return trace.Wrap(err)
+}
+
+// executeOnMaster executes the commands returned by the specified function
+// on one of the cluster master nodes over SSH.
+// The function is given the location of the RPC agent directory on that node
+func (s *site) executeOnMaster(ctx context.Context, opCtx *operationContext, commands func(utils.SSHCommands, string) utils.SSHCommands) error {
 	master, err := s.getTeleportServer(schema.ServiceLabelRole, string(schema.ServiceRoleMaster))
 	if err != nil {
 		return trace.Wrap(err)
@@ -462,10 +511,6 @@ func (s *site) startUpdateAgent(ctx context.Context, opCtx *operationContext, up
 		return trace.Wrap(err)
 	}
 	defer nodeClient.Close()
-	gravityPackage, err := updateApp.Manifest.Dependencies.ByName(constants.GravityPackage)
-	if err != nil {
-		return trace.Wrap(err)
-	}
 	// determine the server's state dir location
 	site, err := s.service.GetSite(s.key)
 	if err != nil {
@@ -479,19 +524,8 @@ func (s *site) startUpdateAgent(ctx context.Context, opCtx *operationContext, up
 	if err != nil {
 		return trace.Wrap(err)
 	}
-	serverStateDir := stateServer.StateDir()
-	agentExecPath := filepath.Join(state.GravityRPCAgentDir(serverStateDir), constants.GravityBin)
-	secretsHostDir := filepath.Join(state.GravityRPCAgentDir(serverStateDir), defaults.SecretsDir)
-	err = utils.NewSSHCommands(nodeClient.Client).
-		// extract new gravity version
-		C("rm -rf %s", secretsHostDir).
-		C("mkdir -p %s", secretsHostDir).
-		C("%s package export --file-mask=%o %s %s --ops-url=%s --insecure --quiet",
-			constants.GravityBin, defaults.SharedExecutableMask,
-			gravityPackage.String(), agentExecPath, defaults.GravityServiceURL).
-		C("%s update init-plan", agentExecPath).
-		// distribute agents and upgrade process
-		C("%s agent deploy --leader=upgrade --node=sync-plan", agentExecPath).
+	agentDir := state.GravityRPCAgentDir(stateServer.StateDir())
+	err = commands(utils.NewSSHCommands(nodeClient.Client), agentDir).
 		WithLogger(s.WithField("node", master.HostName())).
 		WithOutput(opCtx.recorder).
 		Run(ctx)
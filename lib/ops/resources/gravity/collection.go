@@ -26,6 +26,7 @@ import (
 
 	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/pack/signing"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/storage/clusterconfig"
 	"github.com/gravitational/gravity/lib/utils"
@@ -433,9 +434,10 @@ type smtpConfigCollection []storage.SMTPConfig
 // WriteText serializes collection in human-friendly text format
 func (r alertCollection) WriteText(w io.Writer) error {
 	t := goterm.NewTable(0, 10, 5, ' ', 0)
-	common.PrintTableHeader(t, []string{"Name", "Formula"})
+	common.PrintTableHeader(t, []string{"Name", "Formula", "Expression"})
 	for _, alert := range r {
-		fmt.Fprintf(t, "%v\t%v\n", alert.GetName(), alert.GetFormula())
+		fmt.Fprintf(t, "%v\t%v\t%v\n", alert.GetName(),
+			formatEmpty(alert.GetFormula()), formatEmpty(alert.GetExpr()))
 	}
 	_, err := io.WriteString(w, t.String())
 	return trace.Wrap(err)
@@ -681,6 +683,156 @@ type configCollection struct {
 	clusterconfig.Interface
 }
 
+// WriteText serializes collection in human-friendly text format
+func (r backupScheduleCollection) WriteText(w io.Writer) error {
+	t := goterm.NewTable(0, 10, 5, ' ', 0)
+	common.PrintTableHeader(t, []string{"Name", "Schedule", "Retention", "Timeout"})
+	for _, schedule := range r {
+		timeout := "-"
+		if schedule.GetTimeout() != 0 {
+			timeout = schedule.GetTimeout().String()
+		}
+		fmt.Fprintf(t, "%v\t%v\t%v\t%v\n", schedule.GetName(), schedule.GetSchedule(),
+			schedule.GetRetention(), timeout)
+	}
+	_, err := io.WriteString(w, t.String())
+	return trace.Wrap(err)
+}
+
+// WriteJSON serializes collection into JSON format
+func (r backupScheduleCollection) WriteJSON(w io.Writer) error {
+	return utils.WriteJSON(r, w)
+}
+
+// WriteYAML serializes collection into YAML format
+func (r backupScheduleCollection) WriteYAML(w io.Writer) error {
+	return utils.WriteYAML(r, w)
+}
+
+func (r backupScheduleCollection) ToMarshal() interface{} {
+	if len(r) == 1 {
+		return r[0]
+	}
+	return r
+}
+
+// Resources returns the resources collection in the generic format
+func (r backupScheduleCollection) Resources() (resources []teleservices.UnknownResource, err error) {
+	for _, item := range r {
+		resource, err := utils.ToUnknownResource(item)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		resources = append(resources, *resource)
+	}
+	return resources, nil
+}
+
+type backupScheduleCollection []storage.BackupSchedule
+
+// WriteText serializes collection in human-friendly text format
+func (r trustedKeyCollection) WriteText(w io.Writer) error {
+	t := goterm.NewTable(0, 10, 5, ' ', 0)
+	common.PrintTableHeader(t, []string{"Name", "Key ID"})
+	for _, key := range r {
+		keyID := "-"
+		if publicKey, err := signing.ParsePublicKey(key.GetPublicKey()); err == nil {
+			keyID = signing.KeyID(publicKey)
+		}
+		fmt.Fprintf(t, "%v\t%v\n", key.GetName(), keyID)
+	}
+	_, err := io.WriteString(w, t.String())
+	return trace.Wrap(err)
+}
+
+// WriteJSON serializes collection into JSON format
+func (r trustedKeyCollection) WriteJSON(w io.Writer) error {
+	return utils.WriteJSON(r, w)
+}
+
+// WriteYAML serializes collection into YAML format
+func (r trustedKeyCollection) WriteYAML(w io.Writer) error {
+	return utils.WriteYAML(r, w)
+}
+
+func (r trustedKeyCollection) ToMarshal() interface{} {
+	if len(r) == 1 {
+		return r[0]
+	}
+	return r
+}
+
+// Resources returns the resources collection in the generic format
+func (r trustedKeyCollection) Resources() (resources []teleservices.UnknownResource, err error) {
+	for _, item := range r {
+		resource, err := utils.ToUnknownResource(item)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		resources = append(resources, *resource)
+	}
+	return resources, nil
+}
+
+type trustedKeyCollection []storage.TrustedKey
+
+// WriteText serializes collection in human-friendly text format
+func (r *maintenanceWindowCollection) WriteText(w io.Writer) error {
+	t := goterm.NewTable(0, 10, 5, ' ', 0)
+	common.PrintTableHeader(t, []string{"Window", "Duration", "Blackout", "Reason"})
+	for _, window := range r.window.GetWindows() {
+		fmt.Fprintf(t, "%v\t%v\t-\t-\n", window.Start, window.Duration.Duration)
+	}
+	for _, blackout := range r.window.GetBlackouts() {
+		reason := blackout.Reason
+		if reason == "" {
+			reason = "-"
+		}
+		fmt.Fprintf(t, "-\t-\t%v - %v\t%v\n", blackout.Start, blackout.End, reason)
+	}
+	_, err := io.WriteString(w, t.String())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	status := "closed"
+	now := time.Now()
+	if r.window.IsOpen(now) {
+		status = "open"
+	} else if next := r.window.NextOpen(now); !next.IsZero() {
+		status = fmt.Sprintf("closed, opens at %v", next.UTC().Format(constants.HumanDateFormat))
+	}
+	_, err = fmt.Fprintf(w, "\nPolicy: %v\nStatus: %v\n", r.window.GetPolicy(), status)
+	return trace.Wrap(err)
+}
+
+// WriteJSON serializes collection into JSON format
+func (r *maintenanceWindowCollection) WriteJSON(w io.Writer) error {
+	return utils.WriteJSON(r, w)
+}
+
+// WriteYAML serializes collection into YAML format
+func (r *maintenanceWindowCollection) WriteYAML(w io.Writer) error {
+	return utils.WriteYAML(r, w)
+}
+
+// ToMarshal returns object that should be marshaled.
+func (r *maintenanceWindowCollection) ToMarshal() interface{} {
+	return r.window
+}
+
+// Resources returns the resources collection in the generic format
+func (r *maintenanceWindowCollection) Resources() ([]teleservices.UnknownResource, error) {
+	resource, err := utils.ToUnknownResource(r.window)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return []teleservices.UnknownResource{*resource}, nil
+}
+
+type maintenanceWindowCollection struct {
+	window storage.MaintenanceWindow
+}
+
 func formatCloudConfig(w io.Writer, config string) {
 	if config == "" {
 		return
@@ -696,3 +848,10 @@ func formatFeatureGates(features map[string]bool) string {
 	}
 	return strings.Join(result, ",")
 }
+
+func formatEmpty(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
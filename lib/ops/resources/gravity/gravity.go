@@ -230,6 +230,36 @@ func (r *Resources) Create(ctx context.Context, req resources.CreateRequest) err
 			return trace.Wrap(err)
 		}
 		r.Println("Updated auth gateway configuration")
+	case storage.KindBackupSchedule:
+		schedule, err := storage.UnmarshalBackupSchedule(req.Resource.Raw)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		err = r.Operator.UpsertBackupSchedule(r.cluster.Key(), schedule)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		r.Printf("Updated backup schedule %q\n", schedule.GetName())
+	case storage.KindMaintenanceWindow:
+		window, err := storage.UnmarshalMaintenanceWindow(req.Resource.Raw)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		err = r.Operator.UpsertMaintenanceWindow(r.cluster.Key(), window)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		r.Println("Updated maintenance window")
+	case storage.KindTrustedKey:
+		key, err := storage.UnmarshalTrustedKey(req.Resource.Raw)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		err = r.Operator.UpsertTrustedKey(r.cluster.Key(), key)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		r.Printf("Updated trusted key %q\n", key.GetName())
 	case storage.KindRuntimeEnvironment, storage.KindClusterConfiguration:
 		err := r.ClusterOperationHandler.UpdateResource(req)
 		return trace.Wrap(err)
@@ -389,6 +419,52 @@ func (r *Resources) GetCollection(req resources.ListRequest) (resources.Collecti
 			return nil, trace.Wrap(err)
 		}
 		return configCollection{Interface: config}, nil
+	case storage.KindBackupSchedule:
+		schedules, err := r.Operator.GetBackupSchedules(r.cluster.Key())
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		var filtered []storage.BackupSchedule
+		if req.Name != "" {
+			for i := range schedules {
+				if schedules[i].GetName() == req.Name {
+					filtered = append(filtered, schedules[i])
+					break
+				}
+			}
+			if len(filtered) == 0 {
+				return nil, trace.NotFound("backup schedule %q is not found", req.Name)
+			}
+		} else {
+			filtered = schedules
+		}
+		return backupScheduleCollection(filtered), nil
+	case storage.KindTrustedKey:
+		keys, err := r.Operator.GetTrustedKeys(r.cluster.Key())
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		var filtered []storage.TrustedKey
+		if req.Name != "" {
+			for i := range keys {
+				if keys[i].GetName() == req.Name {
+					filtered = append(filtered, keys[i])
+					break
+				}
+			}
+			if len(filtered) == 0 {
+				return nil, trace.NotFound("trusted key %q is not found", req.Name)
+			}
+		} else {
+			filtered = keys
+		}
+		return trustedKeyCollection(filtered), nil
+	case storage.KindMaintenanceWindow:
+		window, err := r.Operator.GetMaintenanceWindow(r.cluster.Key())
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return &maintenanceWindowCollection{window: window}, nil
 	case "":
 		return nil, trace.BadParameter("missing resource kind")
 	}
@@ -472,6 +548,30 @@ func (r *Resources) Remove(ctx context.Context, req resources.RemoveRequest) err
 			return trace.Wrap(err)
 		}
 		r.Println("Alert target has been deleted")
+	case storage.KindBackupSchedule:
+		if err := r.Operator.DeleteBackupSchedule(r.cluster.Key(), req.Name); err != nil {
+			if trace.IsNotFound(err) && req.Force {
+				return nil
+			}
+			return trace.Wrap(err)
+		}
+		r.Printf("Backup schedule %q has been deleted\n", req.Name)
+	case storage.KindMaintenanceWindow:
+		if err := r.Operator.DeleteMaintenanceWindow(r.cluster.Key()); err != nil {
+			if trace.IsNotFound(err) && req.Force {
+				return nil
+			}
+			return trace.Wrap(err)
+		}
+		r.Println("Maintenance window has been deleted")
+	case storage.KindTrustedKey:
+		if err := r.Operator.DeleteTrustedKey(r.cluster.Key(), req.Name); err != nil {
+			if trace.IsNotFound(err) && req.Force {
+				return nil
+			}
+			return trace.Wrap(err)
+		}
+		r.Printf("Trusted key %q has been deleted\n", req.Name)
 	case storage.KindRuntimeEnvironment, storage.KindClusterConfiguration:
 		err := r.ClusterOperationHandler.RemoveResource(req)
 		return trace.Wrap(err)
@@ -530,6 +630,12 @@ func Validate(resource storage.UnknownResource) (err error) {
 		_, err = storage.UnmarshalEnvironmentVariables(resource.Raw)
 	case storage.KindClusterConfiguration:
 		_, err = clusterconfig.Unmarshal(resource.Raw)
+	case storage.KindBackupSchedule:
+		_, err = storage.UnmarshalBackupSchedule(resource.Raw)
+	case storage.KindMaintenanceWindow:
+		_, err = storage.UnmarshalMaintenanceWindow(resource.Raw)
+	case storage.KindTrustedKey:
+		_, err = storage.UnmarshalTrustedKey(resource.Raw)
 	default:
 		return trace.NotImplemented("unsupported resource %q, supported are: %v",
 			resource.Kind, modules.GetResources().SupportedResources())
@@ -88,6 +88,9 @@ type CreateRequest struct {
 	// Confirmed defines whether the operation has been explicitly approved.
 	// This attribute is operation-specific
 	Confirmed bool
+	// Concurrency limits the number of nodes updated at the same time.
+	// This attribute is operation-specific
+	Concurrency int
 }
 
 // Check validates the request
@@ -141,6 +144,9 @@ type RemoveRequest struct {
 	// Confirmed defines whether the operation has been explicitly approved.
 	// This attribute is operation-specific
 	Confirmed bool
+	// Concurrency limits the number of nodes updated at the same time.
+	// This attribute is operation-specific
+	Concurrency int
 }
 
 // Check validates the request
@@ -158,6 +164,7 @@ func (r *RemoveRequest) Check() error {
 	case storage.KindSMTPConfig:
 	case storage.KindRuntimeEnvironment:
 	case storage.KindClusterConfiguration:
+	case storage.KindMaintenanceWindow:
 	default:
 		if r.Name == "" {
 			return trace.BadParameter("resource name is mandatory")
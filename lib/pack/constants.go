@@ -29,6 +29,9 @@ const (
 	AdvertiseIPLabel = "advertise-ip"
 	// OperationIDLabel contains ID of the operation the package was configured for
 	OperationIDLabel = "operation-id"
+	// DeltaBaseLabel marks an application package with container image layers
+	// removed and contains the locator of the cluster image it has been built against
+	DeltaBaseLabel = "delta-base"
 
 	// PurposeCA marks the planet certificate authority package
 	PurposeCA = "ca"
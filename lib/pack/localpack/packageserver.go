@@ -137,6 +137,7 @@ func (p *PackageServer) GetPackages(repository string) ([]pack.PackageEnvelope,
 			Manifest:      p.Manifest,
 			Created:       p.Created,
 			CreatedBy:     p.CreatedBy,
+			Signature:     p.Signature,
 		})
 	}
 
@@ -178,6 +179,7 @@ func (p *PackageServer) CreatePackage(loc loc.Locator, data io.Reader, options .
 		Manifest:      pkg.Manifest,
 		Created:       pkg.Created,
 		CreatedBy:     pkg.CreatedBy,
+		Signature:     pkg.Signature,
 	}
 
 	// check that the repository exists
@@ -225,6 +227,7 @@ func (p *PackageServer) UpsertPackage(loc loc.Locator, data io.Reader, options .
 		Manifest:      pkg.Manifest,
 		Created:       pkg.Created,
 		CreatedBy:     pkg.CreatedBy,
+		Signature:     pkg.Signature,
 	}
 
 	_, err = p.backend.CreateRepository(storage.NewRepository(loc.Repository))
@@ -474,5 +477,6 @@ func newEnvelope(loc loc.Locator, p *storage.Package) *pack.PackageEnvelope {
 		Manifest:      p.Manifest,
 		Created:       p.Created,
 		CreatedBy:     p.CreatedBy,
+		Signature:     p.Signature,
 	}
 }
@@ -53,3 +53,10 @@ func WithCreatedBy(createdBy string) PackageOption {
 		pkg.CreatedBy = createdBy
 	}
 }
+
+// WithSignature configures the detached signature of a package
+func WithSignature(signature *storage.PackageSignature) PackageOption {
+	return func(pkg *storage.Package) {
+		pkg.Signature = signature
+	}
+}
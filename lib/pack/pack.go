@@ -61,6 +61,8 @@ type PackageEnvelope struct {
 	Created time.Time `json:"created"`
 	// CreatedBy specifies the package creator
 	CreatedBy string `json:"created_by"`
+	// Signature is an optional detached signature of the package
+	Signature *storage.PackageSignature `json:"signature,omitempty"`
 }
 
 // HasLabel returns true if envelope has the requested label
@@ -140,6 +142,7 @@ func (p PackageEnvelope) ToPackagePtr() *storage.Package {
 		Hidden:        p.Hidden,
 		Encrypted:     p.Encrypted,
 		Manifest:      p.Manifest,
+		Signature:     p.Signature,
 	}
 }
 
@@ -203,6 +206,12 @@ func (s PackageSorter) Less(i, j int) bool {
 	return s[i].String() < s[j].String()
 }
 
+// Verifier verifies the authenticity of a package described by the envelope
+type Verifier interface {
+	// Verify returns an error if the package cannot be trusted
+	Verify(PackageEnvelope) error
+}
+
 // PackageOption is a function that can make attribute modifications to the specified package
 type PackageOption func(pkg *storage.Package)
 
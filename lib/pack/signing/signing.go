@@ -0,0 +1,232 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package signing implements detached package signatures.
+
+Packages are signed with Ed25519 keys. A signature covers the package
+locator and the SHA512 checksum of its contents, so it stays valid as the
+package is copied between package services as long as neither changes.
+
+Keys are expected in the PEM-encoded PKCS#8 (private) and PKIX (public)
+formats, for example as generated with:
+
+	openssl genpkey -algorithm ed25519 -out signing.key
+	openssl pkey -in signing.key -pubout -out signing.pub
+*/
+package signing
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ed25519"
+)
+
+// GenerateKey generates a new signing key pair and returns
+// both keys PEM-encoded
+func GenerateKey() (privateKeyPEM, publicKeyPEM []byte, err error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	privateKeyPEM, err = MarshalPrivateKey(private)
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	publicKeyPEM, err = MarshalPublicKey(public)
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	return privateKeyPEM, publicKeyPEM, nil
+}
+
+// MarshalPrivateKey encodes the private key as a PEM-encoded PKCS#8 block
+func MarshalPrivateKey(key ed25519.PrivateKey) ([]byte, error) {
+	seed, err := asn1.Marshal(key.Seed())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	der, err := asn1.Marshal(privateKeyInfo{
+		Algorithm:  algorithmIdentifier{Algorithm: oidEd25519},
+		PrivateKey: seed,
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: privateKeyType, Bytes: der}), nil
+}
+
+// MarshalPublicKey encodes the public key as a PEM-encoded PKIX block
+func MarshalPublicKey(key ed25519.PublicKey) ([]byte, error) {
+	der, err := asn1.Marshal(publicKeyInfo{
+		Algorithm: algorithmIdentifier{Algorithm: oidEd25519},
+		PublicKey: asn1.BitString{Bytes: key, BitLength: 8 * len(key)},
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: publicKeyType, Bytes: der}), nil
+}
+
+// ParsePrivateKey parses the PEM-encoded PKCS#8 Ed25519 private key
+func ParsePrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != privateKeyType {
+		return nil, trace.BadParameter("expected PEM-encoded %v block", privateKeyType)
+	}
+	var info privateKeyInfo
+	if _, err := asn1.Unmarshal(block.Bytes, &info); err != nil {
+		return nil, trace.BadParameter("failed to parse private key: %v", err)
+	}
+	if !info.Algorithm.Algorithm.Equal(oidEd25519) {
+		return nil, trace.BadParameter("unsupported private key algorithm %v, "+
+			"only Ed25519 keys are supported", info.Algorithm.Algorithm)
+	}
+	var seed []byte
+	if _, err := asn1.Unmarshal(info.PrivateKey, &seed); err != nil {
+		return nil, trace.BadParameter("failed to parse private key: %v", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, trace.BadParameter("invalid private key size %v", len(seed))
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// ParsePublicKey parses the PEM-encoded PKIX Ed25519 public key
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != publicKeyType {
+		return nil, trace.BadParameter("expected PEM-encoded %v block", publicKeyType)
+	}
+	var info publicKeyInfo
+	if _, err := asn1.Unmarshal(block.Bytes, &info); err != nil {
+		return nil, trace.BadParameter("failed to parse public key: %v", err)
+	}
+	if !info.Algorithm.Algorithm.Equal(oidEd25519) {
+		return nil, trace.BadParameter("unsupported public key algorithm %v, "+
+			"only Ed25519 keys are supported", info.Algorithm.Algorithm)
+	}
+	if len(info.PublicKey.Bytes) != ed25519.PublicKeySize {
+		return nil, trace.BadParameter("invalid public key size %v", len(info.PublicKey.Bytes))
+	}
+	return ed25519.PublicKey(info.PublicKey.Bytes), nil
+}
+
+// KeyID returns the identifier of the specified public key
+func KeyID(key ed25519.PublicKey) string {
+	hash := sha256.Sum256(key)
+	return hex.EncodeToString(hash[:keyIDSize])
+}
+
+// NewSigner returns a new signer for the PEM-encoded private key
+func NewSigner(privateKeyPEM []byte) (*Signer, error) {
+	key, err := ParsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &Signer{
+		key:   key,
+		keyID: KeyID(key.Public().(ed25519.PublicKey)),
+	}, nil
+}
+
+// Signer signs packages with a private key
+type Signer struct {
+	key   ed25519.PrivateKey
+	keyID string
+}
+
+// KeyID returns the identifier of the signing key
+func (r *Signer) KeyID() string {
+	return r.keyID
+}
+
+// Sign returns the signature of the package specified with locator
+// and the checksum of its contents
+func (r *Signer) Sign(locator loc.Locator, sha512 string) *storage.PackageSignature {
+	return &storage.PackageSignature{
+		KeyID:     r.keyID,
+		Signature: ed25519.Sign(r.key, message(locator, sha512)),
+	}
+}
+
+// SignPackages signs all packages in the specified package storage
+func (r *Signer) SignPackages(packages storage.Repositories) error {
+	repositories, err := packages.GetRepositories()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	for _, repository := range repositories {
+		items, err := packages.GetPackages(repository.GetName())
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		for _, pkg := range items {
+			pkg.Signature = r.Sign(pkg.Locator(), pkg.SHA512)
+			if _, err := packages.UpsertPackage(pkg); err != nil {
+				return trace.Wrap(err)
+			}
+			logrus.WithFields(logrus.Fields{
+				"package": pkg.Locator(),
+				"key-id":  r.keyID,
+			}).Debug("Signed package.")
+		}
+	}
+	return nil
+}
+
+// message returns the signed message for the package specified
+// with locator and checksum
+func message(locator loc.Locator, sha512 string) []byte {
+	return []byte(fmt.Sprintf("%v\n%v\n%v", messagePrefix, locator, sha512))
+}
+
+type algorithmIdentifier struct {
+	Algorithm asn1.ObjectIdentifier
+}
+
+type privateKeyInfo struct {
+	Version    int
+	Algorithm  algorithmIdentifier
+	PrivateKey []byte
+}
+
+type publicKeyInfo struct {
+	Algorithm algorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// oidEd25519 is the Ed25519 algorithm identifier as defined in RFC 8410
+var oidEd25519 = asn1.ObjectIdentifier{1, 3, 101, 112}
+
+const (
+	privateKeyType = "PRIVATE KEY"
+	publicKeyType  = "PUBLIC KEY"
+	// messagePrefix separates package signatures from other uses of the key
+	messagePrefix = "gravity package"
+	// keyIDSize is the number of public key hash bytes in the key ID
+	keyIDSize = 8
+)
@@ -0,0 +1,151 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signing
+
+import (
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+	"golang.org/x/crypto/ed25519"
+)
+
+// VerifierConfig defines the package signature verifier configuration
+type VerifierConfig struct {
+	// Keys lists the keys trusted to sign packages
+	Keys []storage.TrustedKey
+	// SkipRepositories lists repositories with packages that are generated
+	// locally and are not expected to be signed, e.g. the cluster repository
+	SkipRepositories []string
+}
+
+// CheckAndSetDefaults validates the verifier configuration
+func (r *VerifierConfig) CheckAndSetDefaults() error {
+	if len(r.Keys) == 0 {
+		return trace.BadParameter("at least one trusted key is required")
+	}
+	return nil
+}
+
+// NewVerifier returns a new verifier that accepts packages signed
+// with any of the configured trusted keys
+func NewVerifier(config VerifierConfig) (*Verifier, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	keys := make(map[string]ed25519.PublicKey, len(config.Keys))
+	for _, trustedKey := range config.Keys {
+		key, err := ParsePublicKey(trustedKey.GetPublicKey())
+		if err != nil {
+			return nil, trace.Wrap(err, "invalid trusted key %q", trustedKey.GetName())
+		}
+		keys[KeyID(key)] = key
+	}
+	return &Verifier{
+		VerifierConfig: config,
+		keys:           keys,
+	}, nil
+}
+
+// NewClusterVerifier returns a verifier for packages pulled into the cluster
+// specified with clusterName.
+// Returns nil if the cluster does not trust any keys in which case package
+// signatures are not verified
+func NewClusterVerifier(keys []storage.TrustedKey, clusterName string) (pack.Verifier, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	verifier, err := NewVerifier(VerifierConfig{
+		Keys:             keys,
+		SkipRepositories: []string{clusterName},
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return verifier, nil
+}
+
+// Verifier verifies package signatures against a set of trusted keys
+type Verifier struct {
+	// VerifierConfig is the verifier configuration
+	VerifierConfig
+	// keys maps key IDs to trusted public keys
+	keys map[string]ed25519.PublicKey
+}
+
+// Verify makes sure that the package described by the specified envelope
+// has been signed with one of the trusted keys.
+// Returns AccessDenied error if the package is not signed, has been signed
+// with an unknown key or the signature does not match the package
+func (r *Verifier) Verify(envelope pack.PackageEnvelope) error {
+	if utils.StringInSlice(r.SkipRepositories, envelope.Locator.Repository) {
+		return nil
+	}
+	signature := envelope.Signature
+	if signature == nil {
+		return trace.AccessDenied("package %v is not signed", envelope.Locator)
+	}
+	key, ok := r.keys[signature.KeyID]
+	if !ok {
+		return trace.AccessDenied("package %v is signed with untrusted key %v",
+			envelope.Locator, signature.KeyID)
+	}
+	if !ed25519.Verify(key, message(envelope.Locator, envelope.SHA512), signature.Signature) {
+		return trace.AccessDenied("package %v signature verification failed",
+			envelope.Locator)
+	}
+	return nil
+}
+
+// NewVerifyingReader returns a reader with the contents of the package
+// described by the specified envelope. Reading fails at the end of the stream
+// if the contents do not match the checksum the signature has been verified for
+func NewVerifyingReader(envelope pack.PackageEnvelope, rc io.ReadCloser) io.ReadCloser {
+	return &verifyingReader{
+		ReadCloser: rc,
+		envelope:   envelope,
+		hasher:     sha512.New(),
+	}
+}
+
+// Read reads the package contents and verifies the
+// checksum once the end of the stream has been reached
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hasher.Write(p[:n])
+	if err != io.EOF {
+		return n, err
+	}
+	checksum := fmt.Sprintf("%x", r.hasher.Sum(nil)[:sha512.Size/2])
+	if checksum != r.envelope.SHA512 {
+		return n, trace.CompareFailed("package %v checksum mismatch: expected %v, got %v",
+			r.envelope.Locator, r.envelope.SHA512, checksum)
+	}
+	return n, err
+}
+
+type verifyingReader struct {
+	io.ReadCloser
+	envelope pack.PackageEnvelope
+	hasher   hash.Hash
+}
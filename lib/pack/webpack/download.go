@@ -0,0 +1,383 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webpack
+
+import (
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/pack"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+)
+
+// newDownload returns a reader for the package file at the specified endpoint.
+//
+// If the connection fails, the download is resumed from the last received
+// byte using a range request. If checkpointDir is set, the received data
+// is also saved in that directory so that a download interrupted by a
+// restart resumes from where it stopped. The saved data is removed once
+// the download completes or if it has not been resumed for checkpointExpiry.
+// The size and hash of the data are verified against the package envelope
+func newDownload(client *Client, endpoint string, envelope pack.PackageEnvelope, checkpointDir string) (*download, error) {
+	d := &download{
+		client:   client,
+		endpoint: endpoint,
+		envelope: envelope,
+		hasher:   sha512.New(),
+		FieldLogger: log.WithFields(log.Fields{
+			"package": envelope.Locator,
+		}),
+	}
+	if checkpointDir == "" {
+		return d, nil
+	}
+	if err := d.openCheckpoint(checkpointDir); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return d, nil
+}
+
+// download is a resumable package download
+type download struct {
+	log.FieldLogger
+	client   *Client
+	endpoint string
+	envelope pack.PackageEnvelope
+	// hasher computes the hash of the downloaded data
+	hasher hash.Hash
+	// offset is the number of bytes received so far
+	offset int64
+	// body is the body of the current response, nil if not connected
+	body io.ReadCloser
+	// attempts is the number of consecutive failed attempts
+	attempts int
+	// checkpoint is the file with partially downloaded data
+	checkpoint *os.File
+	// replay reads the data saved in the checkpoint file
+	// before the download continues from the server
+	replay io.Reader
+	// done is set once the download has been verified
+	done bool
+}
+
+// Read reads the package data
+func (d *download) Read(p []byte) (int, error) {
+	for {
+		if d.done {
+			return 0, io.EOF
+		}
+		if d.replay != nil {
+			n, err := d.replay.Read(p)
+			d.hasher.Write(p[:n])
+			d.offset += int64(n)
+			if err == io.EOF {
+				d.replay = nil
+			} else if err != nil {
+				return n, trace.ConvertSystemError(err)
+			}
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		if d.body == nil && d.offset >= d.envelope.SizeBytes {
+			if err := d.verify(); err != nil {
+				return 0, trace.Wrap(err)
+			}
+			return 0, io.EOF
+		}
+		if d.body == nil {
+			if err := d.connect(); err != nil {
+				if !isRetryable(err) || !d.retry(err) {
+					return 0, trace.Wrap(err)
+				}
+				continue
+			}
+		}
+		n, err := d.body.Read(p)
+		if n > 0 {
+			d.received(p[:n])
+		}
+		if d.offset >= d.envelope.SizeBytes {
+			// verify the download and drop the checkpoint copy as soon
+			// as all data has been received, the reader might not be
+			// read until the end of the stream
+			d.disconnect()
+			if err := d.verify(); err != nil {
+				return n, trace.Wrap(err)
+			}
+			if n == 0 {
+				return 0, io.EOF
+			}
+			return n, nil
+		}
+		switch {
+		case err == io.EOF && n == 0:
+			// the server has closed the connection without sending
+			// any data, treat it as a failed attempt so that
+			// reconnects are limited and backed off
+			d.disconnect()
+			err = trace.ConnectionProblem(nil, "connection closed after %v of %v bytes",
+				d.offset, d.envelope.SizeBytes)
+			if !d.retry(err) {
+				return 0, trace.Wrap(err)
+			}
+		case err == io.EOF:
+			d.disconnect()
+		case err != nil:
+			d.disconnect()
+			if !d.retry(err) {
+				return n, trace.Wrap(err)
+			}
+		}
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+// Close closes the download. Partially downloaded data is
+// kept in the checkpoint file so the download can be resumed
+func (d *download) Close() error {
+	d.disconnect()
+	if d.checkpoint != nil {
+		d.checkpoint.Close()
+		d.checkpoint = nil
+	}
+	return nil
+}
+
+// connect requests the package data starting from the current offset
+func (d *download) connect() error {
+	req, err := http.NewRequest("GET", d.endpoint, nil)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	d.client.SetAuthHeader(req.Header)
+	if d.offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%v-", d.offset))
+		// only resume if the package has not changed
+		req.Header.Set("If-Range", etag(d.envelope.SHA512))
+	}
+	resp, err := d.client.HTTPClient().Do(req)
+	if err != nil {
+		return trace.ConnectionProblem(err, "failed to download %v", d.envelope.Locator)
+	}
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		start, err := parseContentRangeStart(resp.Header.Get("Content-Range"))
+		if err != nil {
+			resp.Body.Close()
+			return trace.Wrap(err)
+		}
+		if start != d.offset {
+			resp.Body.Close()
+			return trace.BadParameter("expected range starting at %v, got %v", d.offset, start)
+		}
+	case http.StatusOK:
+		if d.offset > 0 {
+			// the server sent the whole file, skip the part
+			// already received
+			d.Infof("Server does not support resuming, skipping %v bytes.", d.offset)
+			if _, err := io.CopyN(ioutil.Discard, resp.Body, d.offset); err != nil {
+				resp.Body.Close()
+				return trace.ConnectionProblem(err, "failed to download %v", d.envelope.Locator)
+			}
+		}
+	default:
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorSize))
+		return trace.ReadError(resp.StatusCode, data)
+	}
+	if d.offset > 0 {
+		d.Infof("Resuming download at %v of %v bytes.", d.offset, d.envelope.SizeBytes)
+	}
+	d.body = resp.Body
+	return nil
+}
+
+func (d *download) disconnect() {
+	if d.body != nil {
+		d.body.Close()
+		d.body = nil
+	}
+}
+
+// retry decides whether the download should be retried after the error
+// and waits before the next attempt
+func (d *download) retry(err error) bool {
+	d.attempts++
+	if d.attempts > downloadRetryAttempts {
+		return false
+	}
+	d.Warnf("Download failed at %v of %v bytes, retrying (attempt %v): %v.",
+		d.offset, d.envelope.SizeBytes, d.attempts, err)
+	time.Sleep(time.Duration(d.attempts) * downloadRetryPeriod)
+	return true
+}
+
+// received accounts for the data received from the server
+func (d *download) received(data []byte) {
+	d.attempts = 0
+	d.hasher.Write(data)
+	d.offset += int64(len(data))
+	if d.checkpoint == nil {
+		return
+	}
+	if _, err := d.checkpoint.Write(data); err != nil {
+		d.Warnf("Failed to save download checkpoint, download will not be resumable: %v.", err)
+		d.removeCheckpoint()
+	}
+}
+
+// verify checks the size and hash of the downloaded data
+func (d *download) verify() error {
+	defer d.removeCheckpoint()
+	if d.offset != d.envelope.SizeBytes {
+		return trace.BadParameter("downloaded %v bytes of %v, expected %v",
+			d.offset, d.envelope.Locator, d.envelope.SizeBytes)
+	}
+	hash := fmt.Sprintf("%x", d.hasher.Sum(nil)[:sha512.Size/2])
+	if hash != d.envelope.SHA512 {
+		return trace.BadParameter("checksum mismatch for %v: expected %v, got %v",
+			d.envelope.Locator, d.envelope.SHA512, hash)
+	}
+	d.done = true
+	return nil
+}
+
+// openCheckpoint opens the checkpoint file in the specified directory
+// and replays the data already downloaded
+func (d *download) openCheckpoint(dir string) error {
+	if err := os.MkdirAll(dir, defaults.PrivateDirMask); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	if err := pruneCheckpoints(dir, time.Now()); err != nil {
+		d.Warnf("Failed to remove expired download checkpoints: %v.", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%v%v", d.envelope.SHA512, checkpointSuffix))
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, defaults.PrivateFileMask)
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return trace.ConvertSystemError(err)
+	}
+	size := fi.Size()
+	if size > d.envelope.SizeBytes {
+		if err := f.Truncate(0); err != nil {
+			f.Close()
+			return trace.ConvertSystemError(err)
+		}
+		size = 0
+	}
+	if size > 0 {
+		d.Infof("Found %v bytes of previous download of %v.", size, d.envelope.Locator)
+		d.replay = io.LimitReader(f, size)
+	}
+	d.checkpoint = f
+	return nil
+}
+
+func (d *download) removeCheckpoint() {
+	if d.checkpoint == nil {
+		return
+	}
+	d.checkpoint.Close()
+	if err := os.Remove(d.checkpoint.Name()); err != nil && !os.IsNotExist(err) {
+		d.Warnf("Failed to remove download checkpoint: %v.", err)
+	}
+	d.checkpoint = nil
+}
+
+// pruneCheckpoints removes the checkpoints of the downloads
+// that have not been resumed for longer than checkpointExpiry
+func pruneCheckpoints(dir string, now time.Time) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	var errors []error
+	for _, fi := range files {
+		if fi.IsDir() || !strings.HasSuffix(fi.Name(), checkpointSuffix) {
+			continue
+		}
+		if now.Sub(fi.ModTime()) < checkpointExpiry {
+			continue
+		}
+		err := os.Remove(filepath.Join(dir, fi.Name()))
+		if err != nil && !os.IsNotExist(err) {
+			errors = append(errors, trace.ConvertSystemError(err))
+		}
+	}
+	return trace.NewAggregate(errors...)
+}
+
+// isRetryable returns true if the download can be retried after the error
+func isRetryable(err error) bool {
+	return !trace.IsNotFound(err) && !trace.IsAccessDenied(err) &&
+		!trace.IsBadParameter(err) && !trace.IsCompareFailed(err)
+}
+
+// parseContentRangeStart returns the first byte position of
+// the Content-Range header, e.g. "bytes 100-199/200"
+func parseContentRangeStart(header string) (int64, error) {
+	if !strings.HasPrefix(header, "bytes ") {
+		return 0, trace.BadParameter("unsupported Content-Range %q", header)
+	}
+	parts := strings.SplitN(strings.TrimPrefix(header, "bytes "), "-", 2)
+	start, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, trace.BadParameter("invalid Content-Range %q", header)
+	}
+	return start, nil
+}
+
+// etag returns the entity tag of the package file with the specified hash
+func etag(hash string) string {
+	return strconv.Quote(hash)
+}
+
+const (
+	// downloadRetryAttempts is the number of consecutive attempts
+	// to resume a failed download
+	downloadRetryAttempts = 10
+	// checkpointSuffix is the suffix of files with partially downloaded packages
+	checkpointSuffix = ".partial"
+	// checkpointExpiry is how long the partially downloaded packages
+	// are kept if the download is not resumed
+	checkpointExpiry = 24 * time.Hour
+	// maxErrorSize limits the size of the error response read from the server
+	maxErrorSize = 64 * 1024
+)
+
+// downloadRetryPeriod is the base period between attempts to resume a failed download
+var downloadRetryPeriod = time.Second
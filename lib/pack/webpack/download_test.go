@@ -0,0 +1,240 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webpack
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/pack"
+
+	"github.com/gravitational/roundtrip"
+	"github.com/gravitational/trace"
+	. "gopkg.in/check.v1"
+)
+
+func (s *WebpackSuite) TestResumesInterruptedDownload(c *C) {
+	envelope, data := s.createPackage(c)
+	transport := &flakyTransport{failAfter: int64(len(data) / 3)}
+	client := s.newClient(c, transport)
+
+	_, reader, err := client.ReadPackage(envelope.Locator)
+	c.Assert(err, IsNil)
+	defer reader.Close()
+	out, err := ioutil.ReadAll(reader)
+	c.Assert(err, IsNil)
+	c.Assert(bytes.Equal(out, data), Equals, true)
+	c.Assert(transport.getRanges(), DeepEquals, []string{"", fmt.Sprintf("bytes=%v-", len(data)/3)})
+}
+
+func (s *WebpackSuite) TestResumesDownloadFromCheckpoint(c *C) {
+	envelope, data := s.createPackage(c)
+	dir := c.MkDir()
+	transport := &flakyTransport{}
+	client := s.newClient(c, transport).WithCheckpointDir(dir)
+
+	// interrupt the first download half way
+	_, reader, err := client.ReadPackage(envelope.Locator)
+	c.Assert(err, IsNil)
+	_, err = io.CopyN(ioutil.Discard, reader, int64(len(data)/2))
+	c.Assert(err, IsNil)
+	c.Assert(reader.Close(), IsNil)
+
+	checkpoint := filepath.Join(dir, envelope.SHA512+checkpointSuffix)
+	fi, err := os.Stat(checkpoint)
+	c.Assert(err, IsNil)
+	c.Assert(fi.Size() >= int64(len(data)/2), Equals, true)
+
+	_, reader, err = client.ReadPackage(envelope.Locator)
+	c.Assert(err, IsNil)
+	defer reader.Close()
+	out, err := ioutil.ReadAll(reader)
+	c.Assert(err, IsNil)
+	c.Assert(bytes.Equal(out, data), Equals, true)
+
+	ranges := transport.getRanges()
+	c.Assert(ranges, HasLen, 2)
+	c.Assert(strings.HasPrefix(ranges[1], "bytes="), Equals, true)
+	_, err = os.Stat(checkpoint)
+	c.Assert(os.IsNotExist(err), Equals, true)
+}
+
+func (s *WebpackSuite) TestRejectsCorruptedCheckpoint(c *C) {
+	envelope, data := s.createPackage(c)
+	dir := c.MkDir()
+	checkpoint := filepath.Join(dir, envelope.SHA512+checkpointSuffix)
+	err := ioutil.WriteFile(checkpoint, make([]byte, len(data)/2), 0600)
+	c.Assert(err, IsNil)
+	client := s.newClient(c, &flakyTransport{}).WithCheckpointDir(dir)
+
+	_, reader, err := client.ReadPackage(envelope.Locator)
+	c.Assert(err, IsNil)
+	defer reader.Close()
+	_, err = ioutil.ReadAll(reader)
+	c.Assert(trace.IsBadParameter(err), Equals, true, Commentf("%v", err))
+	// corrupted checkpoint is removed so the next attempt starts over
+	_, err = os.Stat(checkpoint)
+	c.Assert(os.IsNotExist(err), Equals, true)
+
+	_, reader, err = client.ReadPackage(envelope.Locator)
+	c.Assert(err, IsNil)
+	defer reader.Close()
+	out, err := ioutil.ReadAll(reader)
+	c.Assert(err, IsNil)
+	c.Assert(bytes.Equal(out, data), Equals, true)
+}
+
+func (s *WebpackSuite) TestLimitsEmptyReconnects(c *C) {
+	envelope, data := s.createPackage(c)
+	transport := &flakyTransport{failAfter: int64(len(data) / 3), truncate: true}
+	client := s.newClient(c, transport)
+
+	_, reader, err := client.ReadPackage(envelope.Locator)
+	c.Assert(err, IsNil)
+	defer reader.Close()
+	_, err = ioutil.ReadAll(reader)
+	c.Assert(trace.IsConnectionProblem(err), Equals, true, Commentf("%v", err))
+	c.Assert(transport.getRanges(), HasLen, downloadRetryAttempts+1)
+}
+
+func (s *WebpackSuite) TestRemovesCheckpointOnceReceived(c *C) {
+	envelope, data := s.createPackage(c)
+	dir := c.MkDir()
+	client := s.newClient(c, &flakyTransport{}).WithCheckpointDir(dir)
+
+	_, reader, err := client.ReadPackage(envelope.Locator)
+	c.Assert(err, IsNil)
+	defer reader.Close()
+	// read the exact package size without waiting for the end of stream
+	out := make([]byte, len(data))
+	_, err = io.ReadFull(reader, out)
+	c.Assert(err, IsNil)
+	c.Assert(bytes.Equal(out, data), Equals, true)
+	_, err = os.Stat(filepath.Join(dir, envelope.SHA512+checkpointSuffix))
+	c.Assert(os.IsNotExist(err), Equals, true)
+}
+
+func (s *WebpackSuite) TestRemovesExpiredCheckpoints(c *C) {
+	envelope, data := s.createPackage(c)
+	dir := c.MkDir()
+	expired := filepath.Join(dir, "expired"+checkpointSuffix)
+	recent := filepath.Join(dir, "recent"+checkpointSuffix)
+	for _, path := range []string{expired, recent} {
+		c.Assert(ioutil.WriteFile(path, []byte("data"), 0600), IsNil)
+	}
+	modified := time.Now().Add(-checkpointExpiry - time.Minute)
+	c.Assert(os.Chtimes(expired, modified, modified), IsNil)
+	client := s.newClient(c, &flakyTransport{}).WithCheckpointDir(dir)
+
+	_, reader, err := client.ReadPackage(envelope.Locator)
+	c.Assert(err, IsNil)
+	defer reader.Close()
+	out, err := ioutil.ReadAll(reader)
+	c.Assert(err, IsNil)
+	c.Assert(bytes.Equal(out, data), Equals, true)
+	_, err = os.Stat(expired)
+	c.Assert(os.IsNotExist(err), Equals, true)
+	_, err = os.Stat(recent)
+	c.Assert(err, IsNil)
+}
+
+func (s *WebpackSuite) createPackage(c *C) (*pack.PackageEnvelope, []byte) {
+	c.Assert(s.packages.UpsertRepository("example.com", time.Time{}), IsNil)
+	data := make([]byte, 512*1024)
+	_, err := rand.Read(data)
+	c.Assert(err, IsNil)
+	envelope, err := s.packages.CreatePackage(
+		loc.MustParseLocator("example.com/package:0.0.1"), bytes.NewReader(data))
+	c.Assert(err, IsNil)
+	return envelope, data
+}
+
+func (s *WebpackSuite) newClient(c *C, transport http.RoundTripper) *Client {
+	downloadRetryPeriod = time.Millisecond
+	client, err := NewAuthenticatedClient(
+		s.webServer.URL, s.adminUser.GetName(), "admin-password",
+		roundtrip.HTTPClient(&http.Client{Transport: transport}))
+	c.Assert(err, IsNil)
+	return client
+}
+
+// flakyTransport records the ranges of package file requests and
+// optionally breaks the connection of the first one after failAfter bytes.
+// If truncate is set, the responses end early without an error instead
+// and all following responses are empty
+type flakyTransport struct {
+	sync.Mutex
+	failAfter int64
+	truncate  bool
+	ranges    []string
+}
+
+func (t *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil || req.Method != "GET" || !strings.HasSuffix(req.URL.Path, "/file") {
+		return resp, err
+	}
+	t.Lock()
+	defer t.Unlock()
+	t.ranges = append(t.ranges, req.Header.Get("Range"))
+	switch {
+	case len(t.ranges) == 1 && t.failAfter > 0:
+		resp.Body = &brokenBody{ReadCloser: resp.Body, remaining: t.failAfter, truncate: t.truncate}
+	case t.truncate:
+		resp.Body = &brokenBody{ReadCloser: resp.Body, truncate: true}
+	}
+	return resp, nil
+}
+
+func (t *flakyTransport) getRanges() []string {
+	t.Lock()
+	defer t.Unlock()
+	return t.ranges
+}
+
+// brokenBody fails after the specified number of bytes has been read,
+// or ends the stream if truncate is set
+type brokenBody struct {
+	io.ReadCloser
+	remaining int64
+	truncate  bool
+}
+
+func (b *brokenBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 && b.truncate {
+		return 0, io.EOF
+	}
+	if b.remaining <= 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
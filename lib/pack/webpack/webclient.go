@@ -38,6 +38,8 @@ const CurrentVersion = "pack/v1"
 
 type Client struct {
 	roundtrip.Client
+	// checkpointDir is the directory with partially downloaded packages
+	checkpointDir string
 }
 
 // NewAuthenticatedClient returns client authenticated as a user with given password
@@ -57,7 +59,16 @@ func NewClient(addr string, params ...roundtrip.ClientParam) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Client{*c}, nil
+	return &Client{Client: *c}, nil
+}
+
+// WithCheckpointDir returns a copy of this client that saves partially
+// downloaded packages in the specified directory so that a download
+// interrupted by a restart resumes from where it stopped
+func (c *Client) WithCheckpointDir(dir string) *Client {
+	client := *c
+	client.checkpointDir = dir
+	return &client
 }
 
 func (c *Client) PortalURL() string {
@@ -168,6 +179,13 @@ func (c *Client) createOrUpsertPackage(loc loc.Locator, data io.Reader, upsert b
 	if len(pkg.Manifest) > 0 {
 		values["manifest"] = []string{string(pkg.Manifest)}
 	}
+	if pkg.Signature != nil {
+		signatureJSON, err := json.Marshal(pkg.Signature)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		values["signature"] = []string{string(signatureJSON)}
+	}
 	out, err := c.PostForm(c.Endpoint("repositories", loc.Repository, "packages"), values, file)
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -217,12 +235,11 @@ func (c *Client) ReadPackage(loc loc.Locator) (*pack.PackageEnvelope, io.ReadClo
 	if err != nil {
 		return nil, nil, trace.Wrap(err, "failed to read package %s", loc.String())
 	}
-	re, err := c.Client.GetFile(context.TODO(), endpoint, url.Values{})
+	reader, err := newDownload(c, endpoint, *envelope, c.checkpointDir)
 	if err != nil {
 		return nil, nil, trace.Wrap(err)
 	}
-
-	return envelope, re.Body(), nil
+	return envelope, reader, nil
 }
 
 func (c *Client) ReadPackageEnvelope(loc loc.Locator) (*pack.PackageEnvelope, error) {
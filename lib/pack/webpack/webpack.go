@@ -169,7 +169,7 @@ func (s *Server) getPackageFile(w http.ResponseWriter, r *http.Request, p httpro
 		return trace.BadParameter(err.Error())
 	}
 
-	_, fileObject, err := service.ReadPackage(*loc)
+	envelope, fileObject, err := service.ReadPackage(*loc)
 	if err != nil {
 		return trace.Wrap(err)
 	}
@@ -180,7 +180,14 @@ func (s *Server) getPackageFile(w http.ResponseWriter, r *http.Request, p httpro
 		return trace.BadParameter("expected read seeker object")
 	}
 	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename=%v`, loc.String()))
-	http.ServeContent(w, r, loc.String(), time.Now(), readSeeker)
+	// the entity tag lets clients resume the download with a range
+	// request only if the package has not changed since
+	w.Header().Set("ETag", etag(envelope.SHA512))
+	modified := envelope.Created
+	if modified.IsZero() {
+		modified = time.Now()
+	}
+	http.ServeContent(w, r, loc.String(), modified, readSeeker)
 	return nil
 }
 
@@ -191,6 +198,7 @@ func (s *Server) createPackage(w http.ResponseWriter, r *http.Request, p httprou
 	var hiddenS string
 	var packageType string
 	var manifest string
+	var signatureJSON string
 
 	err := form.Parse(r,
 		form.FileSlice("package", &files),
@@ -199,6 +207,7 @@ func (s *Server) createPackage(w http.ResponseWriter, r *http.Request, p httprou
 		form.String("hidden", &hiddenS),
 		form.String("type", &packageType),
 		form.String("manifest", &manifest),
+		form.String("signature", &signatureJSON),
 	)
 	if err != nil {
 		return trace.Wrap(err)
@@ -246,6 +255,13 @@ func (s *Server) createPackage(w http.ResponseWriter, r *http.Request, p httprou
 	if manifest != "" {
 		opts = append(opts, pack.WithManifest(packageType, []byte(manifest)))
 	}
+	if signatureJSON != "" {
+		var signature storage.PackageSignature
+		if err := json.Unmarshal([]byte(signatureJSON), &signature); err != nil {
+			return trace.BadParameter("invalid package signature: %v", err)
+		}
+		opts = append(opts, pack.WithSignature(&signature))
+	}
 
 	var envelope *pack.PackageEnvelope
 	if upsert {
@@ -30,6 +30,7 @@ import (
 	"github.com/gravitational/gravity/lib/blob/fs"
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/pack"
 	"github.com/gravitational/gravity/lib/pack/localpack"
 	"github.com/gravitational/gravity/lib/pack/suite"
 	"github.com/gravitational/gravity/lib/storage"
@@ -50,6 +51,7 @@ func TestWebpack(t *testing.T) { TestingT(t) }
 type WebpackSuite struct {
 	server    *Server
 	backend   storage.Backend
+	packages  pack.PackageService
 	suite     suite.PackageSuite
 	webServer *httptest.Server
 	users     users.Identity
@@ -105,6 +107,7 @@ func (s *WebpackSuite) SetUpTest(c *C) {
 		Objects:     objects,
 	})
 	c.Assert(err, IsNil)
+	s.packages = service
 	webHandler, err := NewHandler(Config{
 		Users:    s.users,
 		Packages: service,
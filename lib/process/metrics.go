@@ -0,0 +1,403 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gravitational/gravity/lib/fsm"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/teleport/lib/reversetunnel"
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"github.com/sirupsen/logrus"
+)
+
+var apiRequestDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name: "gravity_api_request_duration_seconds",
+		Help: "Latency of API requests served by the cluster controller",
+	},
+	[]string{"handler", "method", "code"},
+)
+
+func init() {
+	prometheus.MustRegister(apiRequestDuration)
+}
+
+// ServeMetrics serves the process metrics in Prometheus exposition format
+func (p *Process) ServeMetrics(w http.ResponseWriter, r *http.Request) {
+	gatherers := prometheus.Gatherers{prometheus.DefaultGatherer}
+	if p.metrics != nil {
+		gatherers = append(gatherers, p.metrics)
+	}
+	families, err := gatherers.Gather()
+	if err != nil {
+		// Gather returns as many metrics as possible
+		// even if some of the collectors have failed
+		p.Warnf("Failed to gather some metrics: %v.", err)
+		if len(families) == 0 {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	contentType := expfmt.Negotiate(r.Header)
+	w.Header().Set("Content-Type", string(contentType))
+	encoder := expfmt.NewEncoder(w, contentType)
+	for _, family := range families {
+		if err := encoder.Encode(family); err != nil {
+			p.Warnf("Failed to encode metrics: %v.", err)
+			return
+		}
+	}
+}
+
+// instrumentHandler returns a handler that records the latency
+// of requests served by the provided handler under the specified name
+func instrumentHandler(name string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		handler.ServeHTTP(recorder, r)
+		apiRequestDuration.WithLabelValues(name, r.Method, strconv.Itoa(recorder.status)).
+			Observe(time.Since(start).Seconds())
+	})
+}
+
+// statusRecorder is the http.ResponseWriter that captures the response status code
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code and writes it to the underlying writer
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush flushes the underlying writer if it supports flushing
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hijacks the underlying connection, e.g. to serve a websocket
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, trace.BadParameter("response writer does not support hijacking")
+	}
+	r.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
+// CloseNotify returns the close notification channel of the underlying writer
+func (r *statusRecorder) CloseNotify() <-chan bool {
+	if notifier, ok := r.ResponseWriter.(http.CloseNotifier); ok {
+		return notifier.CloseNotify()
+	}
+	return make(chan bool)
+}
+
+// metricsCollector collects metrics describing the state of the
+// cluster controller when the metrics are scraped.
+// Metrics computed from the backend are cached for the refresh interval
+type metricsCollector struct {
+	logrus.FieldLogger
+	backend  storage.Backend
+	packages pack.PackageService
+	// tunnel returns the reverse tunnel server.
+	// Returns nil if the server has not been started
+	tunnel func() reversetunnel.Server
+	// refreshInterval is how often the metrics computed from the backend
+	// are refreshed. If unset, they are recomputed on every scrape
+	refreshInterval time.Duration
+	// clock is used to expire the cached metrics. Defaults to real clock
+	clock clockwork.Clock
+
+	mu sync.Mutex
+	// cached is the last set of metrics computed from the backend
+	cached []prometheus.Metric
+	// refreshed is the time the cached metrics have been computed
+	refreshed time.Time
+}
+
+var (
+	activeOperationsDesc = prometheus.NewDesc(
+		"gravity_active_operations",
+		"Number of operations that have not finished yet",
+		[]string{"type", "state"}, nil)
+	packageStoreSizeDesc = prometheus.NewDesc(
+		"gravity_package_store_size_bytes",
+		"Total size of packages in the package store",
+		nil, nil)
+	packageStoreCountDesc = prometheus.NewDesc(
+		"gravity_package_store_packages",
+		"Number of packages in the package store",
+		nil, nil)
+	reverseTunnelsDesc = prometheus.NewDesc(
+		"gravity_reverse_tunnel_connections",
+		"Number of clusters connected over reverse tunnels",
+		[]string{"status"}, nil)
+	phaseDurationDesc = prometheus.NewDesc(
+		"gravity_operation_phase_duration_seconds",
+		"Time it took to execute or roll back an operation phase",
+		[]string{"operation_type", "executor", "state"}, nil)
+	phaseAttemptsDesc = prometheus.NewDesc(
+		"gravity_operation_phase_attempts_total",
+		"Number of attempts to execute or roll back an operation phase",
+		[]string{"operation_type", "executor"}, nil)
+	// phaseDurationBuckets defines the phase duration histogram buckets: 1s to ~1h
+	phaseDurationBuckets = prometheus.ExponentialBuckets(1, 2, 13)
+)
+
+// Describe sends descriptions of the collected metrics to the provided channel
+func (c *metricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- activeOperationsDesc
+	ch <- packageStoreSizeDesc
+	ch <- packageStoreCountDesc
+	ch <- reverseTunnelsDesc
+	ch <- phaseDurationDesc
+	ch <- phaseAttemptsDesc
+}
+
+// Collect sends the collected metrics to the provided channel.
+// Metrics that fail to be collected are skipped
+func (c *metricsCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, metric := range c.backendMetrics() {
+		ch <- metric
+	}
+	c.collectTunnels(ch)
+}
+
+// backendMetrics returns the metrics computed from the backend.
+// The metrics are recomputed if they are older than the refresh interval
+func (c *metricsCollector) backendMetrics() []prometheus.Metric {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.clock == nil {
+		c.clock = clockwork.NewRealClock()
+	}
+	now := c.clock.Now()
+	if c.cached != nil && now.Sub(c.refreshed) < c.refreshInterval {
+		return c.cached
+	}
+	ch := make(chan prometheus.Metric)
+	doneCh := make(chan []prometheus.Metric)
+	go func() {
+		metrics := []prometheus.Metric{}
+		for metric := range ch {
+			metrics = append(metrics, metric)
+		}
+		doneCh <- metrics
+	}()
+	if err := c.collectOperations(ch); err != nil {
+		c.Warnf("Failed to collect operation metrics: %v.", trace.DebugReport(err))
+	}
+	if err := c.collectPackages(ch); err != nil {
+		c.Warnf("Failed to collect package metrics: %v.", trace.DebugReport(err))
+	}
+	close(ch)
+	c.cached = <-doneCh
+	c.refreshed = now
+	return c.cached
+}
+
+func (c *metricsCollector) collectOperations(ch chan<- prometheus.Metric) error {
+	clusters, err := c.backend.GetAllSites()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	type key struct{ opType, state string }
+	active := make(map[key]int)
+	phases := newPhaseMetrics()
+	for _, cluster := range clusters {
+		operations, err := c.backend.GetSiteOperations(cluster.Domain)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		for _, op := range operations {
+			if err := phases.addOperation(c.backend, op); err != nil {
+				return trace.Wrap(err)
+			}
+			if (*ops.SiteOperation)(&op).IsFinished() {
+				continue
+			}
+			active[key{op.Type, op.State}]++
+		}
+	}
+	for key, count := range active {
+		ch <- prometheus.MustNewConstMetric(activeOperationsDesc,
+			prometheus.GaugeValue, float64(count), key.opType, key.state)
+	}
+	phases.collect(ch)
+	return nil
+}
+
+// newPhaseMetrics returns a new empty set of phase metrics
+func newPhaseMetrics() *phaseMetrics {
+	return &phaseMetrics{
+		durations: make(map[phaseKey]*phaseHistogram),
+		attempts:  make(map[phaseKey]int),
+	}
+}
+
+// addOperation computes the durations and the number of attempts of the
+// phases of the specified operation from its plan changelog.
+// An attempt starts when the phase moves into the in-progress state and lasts
+// until the phase is completed, fails or is rolled back.
+// Operations without a plan are skipped
+func (r *phaseMetrics) addOperation(backend storage.Backend, op storage.SiteOperation) error {
+	plan, err := backend.GetOperationPlan(op.SiteDomain, op.ID)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil
+		}
+		return trace.Wrap(err)
+	}
+	changelog, err := backend.GetOperationPlanChangelog(op.SiteDomain, op.ID)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	executors := make(map[string]string)
+	for _, phase := range fsm.FlattenPlan(plan) {
+		executors[phase.ID] = phase.Executor
+	}
+	sort.SliceStable(changelog, func(i, j int) bool {
+		return changelog[i].Created.Before(changelog[j].Created)
+	})
+	started := make(map[string]time.Time)
+	for _, change := range changelog {
+		executor := executors[change.PhaseID]
+		switch change.NewState {
+		case storage.OperationPhaseStateInProgress:
+			started[change.PhaseID] = change.Created
+			r.attempts[phaseKey{opType: op.Type, executor: executor}]++
+		case storage.OperationPhaseStateCompleted,
+			storage.OperationPhaseStateFailed,
+			storage.OperationPhaseStateRolledBack:
+			start, ok := started[change.PhaseID]
+			if !ok {
+				continue
+			}
+			delete(started, change.PhaseID)
+			r.observe(phaseKey{opType: op.Type, executor: executor, state: change.NewState},
+				change.Created.Sub(start))
+		}
+	}
+	return nil
+}
+
+// observe records the duration of a phase attempt that finished
+// in the state given with the key
+func (r *phaseMetrics) observe(key phaseKey, duration time.Duration) {
+	histogram, ok := r.durations[key]
+	if !ok {
+		histogram = &phaseHistogram{buckets: make(map[float64]uint64)}
+		r.durations[key] = histogram
+	}
+	seconds := duration.Seconds()
+	histogram.count++
+	histogram.sum += seconds
+	for _, bound := range phaseDurationBuckets {
+		if seconds <= bound {
+			histogram.buckets[bound]++
+		}
+	}
+}
+
+// collect sends the phase metrics to the provided channel
+func (r *phaseMetrics) collect(ch chan<- prometheus.Metric) {
+	for key, histogram := range r.durations {
+		ch <- prometheus.MustNewConstHistogram(phaseDurationDesc,
+			histogram.count, histogram.sum, histogram.buckets,
+			key.opType, key.executor, key.state)
+	}
+	for key, count := range r.attempts {
+		ch <- prometheus.MustNewConstMetric(phaseAttemptsDesc,
+			prometheus.CounterValue, float64(count), key.opType, key.executor)
+	}
+}
+
+// phaseMetrics accumulates phase timing metrics computed
+// from the operation plan changelogs
+type phaseMetrics struct {
+	// durations maps phase attempts to the histogram of their durations
+	durations map[phaseKey]*phaseHistogram
+	// attempts counts phase attempts.
+	// The keys do not specify the state
+	attempts map[phaseKey]int
+}
+
+// phaseKey identifies phase metrics
+type phaseKey struct {
+	opType   string
+	executor string
+	state    string
+}
+
+// phaseHistogram is a histogram of phase durations
+type phaseHistogram struct {
+	count uint64
+	sum   float64
+	// buckets maps upper bucket bounds to cumulative counts
+	buckets map[float64]uint64
+}
+
+func (c *metricsCollector) collectPackages(ch chan<- prometheus.Metric) error {
+	var size int64
+	var count int
+	err := pack.ForeachPackage(c.packages, func(env pack.PackageEnvelope) error {
+		size += env.SizeBytes
+		count++
+		return nil
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	ch <- prometheus.MustNewConstMetric(packageStoreSizeDesc, prometheus.GaugeValue, float64(size))
+	ch <- prometheus.MustNewConstMetric(packageStoreCountDesc, prometheus.GaugeValue, float64(count))
+	return nil
+}
+
+func (c *metricsCollector) collectTunnels(ch chan<- prometheus.Metric) {
+	tunnel := c.tunnel()
+	if tunnel == nil {
+		return
+	}
+	connections := make(map[string]int)
+	for _, site := range tunnel.GetSites() {
+		connections[site.GetStatus()]++
+	}
+	for status, count := range connections {
+		ch <- prometheus.MustNewConstMetric(reverseTunnelsDesc,
+			prometheus.GaugeValue, float64(count), status)
+	}
+}
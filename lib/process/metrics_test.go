@@ -0,0 +1,250 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"time"
+
+	"github.com/gravitational/gravity/lib/blob/fs"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/pack/localpack"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/storage/keyval"
+
+	"github.com/gravitational/teleport/lib/reversetunnel"
+	"github.com/jonboulle/clockwork"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/check.v1"
+)
+
+type MetricsSuite struct{}
+
+var _ = check.Suite(&MetricsSuite{})
+
+func (s *MetricsSuite) TestCollectsClusterMetrics(c *check.C) {
+	backend, packages := newMetricsBackend(c)
+	for _, op := range []storage.SiteOperation{
+		{Type: ops.OperationUpdate, State: ops.OperationStateUpdateInProgress},
+		{Type: ops.OperationUpdate, State: ops.OperationStateCompleted},
+		{Type: ops.OperationGarbageCollect, State: ops.OperationStateFailed},
+	} {
+		op.AccountID = defaults.SystemAccountID
+		op.SiteDomain = "example.com"
+		_, err := backend.CreateSiteOperation(op)
+		c.Assert(err, check.IsNil)
+	}
+	locator := loc.MustParseLocator("gravitational.io/app:0.0.1")
+	c.Assert(packages.UpsertRepository(locator.Repository, time.Time{}), check.IsNil)
+	_, err := packages.CreatePackage(locator, bytes.NewBufferString("data"))
+	c.Assert(err, check.IsNil)
+
+	metrics := gatherMetrics(c, backend, packages)
+	active := metrics["gravity_active_operations"]
+	c.Assert(active, check.HasLen, 1)
+	c.Assert(active[0].GetGauge().GetValue(), check.Equals, float64(1))
+	c.Assert(labels(active[0]), check.DeepEquals, map[string]string{
+		"type":  ops.OperationUpdate,
+		"state": ops.OperationStateUpdateInProgress,
+	})
+	c.Assert(metrics["gravity_package_store_packages"][0].GetGauge().GetValue(), check.Equals, float64(1))
+	c.Assert(metrics["gravity_package_store_size_bytes"][0].GetGauge().GetValue(), check.Equals, float64(len("data")))
+}
+
+func (s *MetricsSuite) TestCollectsPhaseMetrics(c *check.C) {
+	backend, packages := newMetricsBackend(c)
+	op, err := backend.CreateSiteOperation(storage.SiteOperation{
+		AccountID:  defaults.SystemAccountID,
+		SiteDomain: "example.com",
+		Type:       ops.OperationUpdate,
+		State:      ops.OperationStateCompleted,
+	})
+	c.Assert(err, check.IsNil)
+	_, err = backend.CreateOperationPlan(storage.OperationPlan{
+		OperationID:   op.ID,
+		OperationType: op.Type,
+		AccountID:     op.AccountID,
+		ClusterName:   op.SiteDomain,
+		Phases: []storage.OperationPhase{
+			{ID: "/init", Executor: "init"},
+			{ID: "/masters", Phases: []storage.OperationPhase{
+				{ID: "/masters/node-1", Executor: "drain"},
+			}},
+		},
+	})
+	c.Assert(err, check.IsNil)
+	start := time.Date(2019, time.January, 7, 10, 0, 0, 0, time.UTC)
+	for _, change := range []struct {
+		phaseID string
+		state   string
+		offset  time.Duration
+	}{
+		{"/init", storage.OperationPhaseStateInProgress, 0},
+		{"/init", storage.OperationPhaseStateCompleted, 3 * time.Second},
+		{"/masters/node-1", storage.OperationPhaseStateInProgress, 5 * time.Second},
+		{"/masters/node-1", storage.OperationPhaseStateFailed, 10 * time.Second},
+		{"/masters/node-1", storage.OperationPhaseStateInProgress, 20 * time.Second},
+		{"/masters/node-1", storage.OperationPhaseStateCompleted, 120 * time.Second},
+	} {
+		_, err = backend.CreateOperationPlanChange(storage.PlanChange{
+			ClusterName: op.SiteDomain,
+			OperationID: op.ID,
+			PhaseID:     change.phaseID,
+			NewState:    change.state,
+			Created:     start.Add(change.offset),
+		})
+		c.Assert(err, check.IsNil)
+	}
+
+	metrics := gatherMetrics(c, backend, packages)
+	attempts := make(map[string]float64)
+	for _, metric := range metrics["gravity_operation_phase_attempts_total"] {
+		attempts[labels(metric)["executor"]] = metric.GetCounter().GetValue()
+	}
+	c.Assert(attempts, check.DeepEquals, map[string]float64{"init": 1, "drain": 2})
+	durations := make(map[string]float64)
+	for _, metric := range metrics["gravity_operation_phase_duration_seconds"] {
+		key := labels(metric)
+		c.Assert(key["operation_type"], check.Equals, ops.OperationUpdate)
+		c.Assert(metric.GetHistogram().GetSampleCount(), check.Equals, uint64(1))
+		durations[key["executor"]+"/"+key["state"]] = metric.GetHistogram().GetSampleSum()
+	}
+	c.Assert(durations, check.DeepEquals, map[string]float64{
+		"init/" + storage.OperationPhaseStateCompleted:  3,
+		"drain/" + storage.OperationPhaseStateFailed:    5,
+		"drain/" + storage.OperationPhaseStateCompleted: 100,
+	})
+}
+
+func (s *MetricsSuite) TestCachesBackendMetrics(c *check.C) {
+	backend, packages := newMetricsBackend(c)
+	clock := clockwork.NewFakeClock()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(&metricsCollector{
+		FieldLogger:     logrus.WithField("test", "metrics"),
+		backend:         backend,
+		packages:        packages,
+		tunnel:          func() reversetunnel.Server { return nil },
+		refreshInterval: time.Minute,
+		clock:           clock,
+	})
+	activeOperations := func() float64 {
+		families, err := registry.Gather()
+		c.Assert(err, check.IsNil)
+		var count float64
+		for _, family := range families {
+			if family.GetName() != "gravity_active_operations" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				count += metric.GetGauge().GetValue()
+			}
+		}
+		return count
+	}
+	c.Assert(activeOperations(), check.Equals, float64(0))
+
+	_, err := backend.CreateSiteOperation(storage.SiteOperation{
+		AccountID:  defaults.SystemAccountID,
+		SiteDomain: "example.com",
+		Type:       ops.OperationUpdate,
+		State:      ops.OperationStateUpdateInProgress,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(activeOperations(), check.Equals, float64(0),
+		check.Commentf("metrics should be served from cache"))
+
+	clock.Advance(time.Minute)
+	c.Assert(activeOperations(), check.Equals, float64(1),
+		check.Commentf("metrics should be refreshed"))
+}
+
+func (s *MetricsSuite) TestInstrumentsHandler(c *check.C) {
+	handler := instrumentHandler("test", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	var metric dto.Metric
+	histogram := apiRequestDuration.WithLabelValues("test", "GET", "404").(prometheus.Histogram)
+	c.Assert(histogram.Write(&metric), check.IsNil)
+	c.Assert(metric.GetHistogram().GetSampleCount(), check.Equals, uint64(1))
+}
+
+// newMetricsBackend returns a new backend with a single cluster
+// and a package service on top of it
+func newMetricsBackend(c *check.C) (storage.Backend, pack.PackageService) {
+	dir := c.MkDir()
+	backend, err := keyval.NewBolt(keyval.BoltConfig{Path: filepath.Join(dir, "bolt.db")})
+	c.Assert(err, check.IsNil)
+	objects, err := fs.New(dir)
+	c.Assert(err, check.IsNil)
+	packages, err := localpack.New(localpack.Config{
+		Backend:     backend,
+		UnpackedDir: filepath.Join(dir, defaults.UnpackedDir),
+		Objects:     objects,
+	})
+	c.Assert(err, check.IsNil)
+
+	_, err = backend.CreateAccount(storage.Account{
+		ID:  defaults.SystemAccountID,
+		Org: defaults.SystemAccountOrg,
+	})
+	c.Assert(err, check.IsNil)
+	_, err = backend.CreateSite(storage.Site{
+		AccountID: defaults.SystemAccountID,
+		Domain:    "example.com",
+		Created:   time.Now(),
+	})
+	c.Assert(err, check.IsNil)
+	return backend, packages
+}
+
+// gatherMetrics collects the cluster metrics and returns them by name
+func gatherMetrics(c *check.C, backend storage.Backend, packages pack.PackageService) map[string][]*dto.Metric {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(&metricsCollector{
+		FieldLogger: logrus.WithField("test", "metrics"),
+		backend:     backend,
+		packages:    packages,
+		tunnel:      func() reversetunnel.Server { return nil },
+	})
+	families, err := registry.Gather()
+	c.Assert(err, check.IsNil)
+
+	metrics := make(map[string][]*dto.Metric)
+	for _, family := range families {
+		metrics[family.GetName()] = family.GetMetric()
+	}
+	return metrics
+}
+
+func labels(metric *dto.Metric) map[string]string {
+	result := make(map[string]string)
+	for _, label := range metric.GetLabel() {
+		result[label.GetName()] = label.GetValue()
+	}
+	return result
+}
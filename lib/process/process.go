@@ -36,21 +36,27 @@ import (
 	apphandler "github.com/gravitational/gravity/lib/app/handler"
 	appservice "github.com/gravitational/gravity/lib/app/service"
 	"github.com/gravitational/gravity/lib/autoscale/aws"
+	"github.com/gravitational/gravity/lib/backup"
 	"github.com/gravitational/gravity/lib/blob"
+	blobchunked "github.com/gravitational/gravity/lib/blob/chunked"
 	blobclient "github.com/gravitational/gravity/lib/blob/client"
 	blobcluster "github.com/gravitational/gravity/lib/blob/cluster"
 	blobfs "github.com/gravitational/gravity/lib/blob/fs"
 	blobhandler "github.com/gravitational/gravity/lib/blob/handler"
+	blobs3 "github.com/gravitational/gravity/lib/blob/s3"
 	"github.com/gravitational/gravity/lib/clients"
 	cloudaws "github.com/gravitational/gravity/lib/cloudprovider/aws"
 	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/docker"
+	"github.com/gravitational/gravity/lib/fleet"
 	"github.com/gravitational/gravity/lib/helm"
 	"github.com/gravitational/gravity/lib/httplib"
 	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/localenv"
 	"github.com/gravitational/gravity/lib/modules"
 	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/ops/events"
 	"github.com/gravitational/gravity/lib/ops/monitoring"
 	"github.com/gravitational/gravity/lib/ops/opshandler"
 	"github.com/gravitational/gravity/lib/ops/opsroute"
@@ -69,6 +75,8 @@ import (
 	"github.com/gravitational/gravity/lib/users"
 	"github.com/gravitational/gravity/lib/users/usersservice"
 	"github.com/gravitational/gravity/lib/utils"
+	"github.com/gravitational/gravity/lib/vacuum/prune"
+	"github.com/gravitational/gravity/lib/vacuum/prune/opscenter"
 	web "github.com/gravitational/gravity/lib/webapi"
 	"github.com/gravitational/gravity/lib/webapi/ui"
 
@@ -90,6 +98,7 @@ import (
 	"github.com/gravitational/teleport"
 	"github.com/gravitational/trace"
 	"github.com/julienschmidt/httprouter"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/kubernetes"
@@ -129,6 +138,8 @@ type Process struct {
 	agentService   ops.AgentService
 	// handlers contains all initialized web handlers
 	handlers Handlers
+	// metrics is the registry of process-specific metrics collectors
+	metrics *prometheus.Registry
 	// rpcCreds holds generated RPC agents credentials
 	rpcCreds rpcCredentials
 	// authGatewayConfig is the current auth gateway configuration (basically,
@@ -209,7 +220,7 @@ func New(ctx context.Context, cfg processconfig.Config, tcfg telecfg.FileConfig)
 		return nil, trace.Wrap(err)
 	}
 
-	objects, err := blobfs.New(filepath.Join(cfg.DataDir, defaults.PackagesDir))
+	objects, err := newLocalObjects(filepath.Join(cfg.DataDir, defaults.PackagesDir), cfg.Pack.ChunkedStorage)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -232,14 +243,27 @@ func New(ctx context.Context, cfg processconfig.Config, tcfg telecfg.FileConfig)
 		return nil, trace.Wrap(err)
 	}
 
-	clusterObjects, err := blobcluster.New(blobcluster.Config{
-		Local:         objects,
-		Backend:       backend,
-		GetPeer:       peerPool.GetPeer,
-		ID:            processID,
-		AdvertiseAddr: fmt.Sprintf("https://%v", peerAddr.Addr),
-		// TODO: set WriteFactor to the number of controller instances
-	})
+	var clusterObjects blob.Objects
+	if cfg.Pack.S3 != nil {
+		// package data is shared by all instances via the object store
+		clusterObjects, err = newS3Objects(blobs3.Config{
+			Bucket:   cfg.Pack.S3.Bucket,
+			Prefix:   cfg.Pack.S3.Prefix,
+			Region:   cfg.Pack.S3.Region,
+			Endpoint: cfg.Pack.S3.Endpoint,
+			TempDir:  filepath.Join(cfg.DataDir, defaults.PackagesDir, "tmp"),
+		}, objects)
+	} else {
+		clusterObjects, err = blobcluster.New(blobcluster.Config{
+			Local:             objects,
+			Backend:           backend,
+			GetPeer:           peerPool.GetPeer,
+			ID:                processID,
+			AdvertiseAddr:     fmt.Sprintf("https://%v", peerAddr.Addr),
+			ReplicationFactor: cfg.Pack.ReplicationFactor,
+			// TODO: set WriteFactor to the number of controller instances
+		})
+	}
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -598,6 +622,175 @@ func (p *Process) startRegistrySynchronizer(ctx context.Context) error {
 	return nil
 }
 
+// startBackupScheduler registers a service that takes cluster backups
+// according to the configured backup schedules
+func (p *Process) startBackupScheduler() error {
+	scheduler, err := backup.New(backup.Config{
+		Operator:    p.operator,
+		Backend:     p.backend,
+		Apps:        p.applications,
+		Objects:     p.clusterObjects,
+		StateDir:    filepath.Join(p.cfg.DataDir, defaults.BackupsDir),
+		FieldLogger: p.WithField(trace.Component, "backup"),
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	p.RegisterClusterService(func(ctx context.Context) error {
+		localCtx := context.WithValue(ctx, constants.UserContext,
+			constants.ServiceBackupScheduler)
+		return trace.Wrap(scheduler.Run(localCtx))
+	})
+	return nil
+}
+
+// startBLOBScrubber starts a goroutine that periodically verifies
+// the integrity of the packages stored by this process
+func (p *Process) startBLOBScrubber(ctx context.Context) error {
+	scrubber, ok := p.clusterObjects.(blobcluster.Scrubber)
+	if !ok {
+		return nil
+	}
+	p.Info("Starting package storage scrubber.")
+	go func() {
+		ticker := time.NewTicker(defaults.BLOBScrubCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := p.scrubBLOBs(ctx, scrubber); err != nil {
+					p.Errorf("Failed to verify package storage: %v.",
+						trace.DebugReport(err))
+				}
+			case <-ctx.Done():
+				p.Info("Stopping package storage scrubber.")
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// scrubBLOBs verifies the packages stored by this process unless
+// they have been verified within the configured interval
+func (p *Process) scrubBLOBs(ctx context.Context, scrubber blobcluster.Scrubber) error {
+	last, err := p.backend.GetBLOBScrubReport(p.id)
+	if err != nil && !trace.IsNotFound(err) {
+		return trace.Wrap(err)
+	}
+	if last != nil && time.Since(last.Completed) < p.cfg.Pack.GetScrubInterval() {
+		return nil
+	}
+	report, err := scrubber.Scrub(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	localCtx := context.WithValue(ctx, constants.UserContext,
+		constants.ServiceBLOBScrubber)
+	events.Emit(localCtx, p.operator, events.PackagesVerified, events.Fields{
+		events.FieldPeer:      report.PeerID,
+		events.FieldChecked:   report.Checked,
+		events.FieldCorrupted: len(report.Corrupted),
+		events.FieldRepaired:  len(report.Repaired),
+	})
+	return nil
+}
+
+// startPackageGC starts a service that periodically removes unused
+// packages from the Ops Center package storage if enabled
+func (p *Process) startPackageGC() error {
+	config := p.cfg.Pack.GC
+	if !config.Enabled {
+		return nil
+	}
+	policy := opscenter.Policy{
+		KeepVersions: config.KeepVersions,
+		MaxAge:       config.MaxAge,
+	}
+	if err := policy.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	p.RegisterClusterService(func(ctx context.Context) error {
+		p.Info("Starting package garbage collector.")
+		ticker := time.NewTicker(config.GetInterval())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := p.collectPackages(ctx, policy); err != nil {
+					p.Errorf("Failed to remove unused packages: %v.",
+						trace.DebugReport(err))
+				}
+			case <-ctx.Done():
+				p.Info("Stopping package garbage collector.")
+				return nil
+			}
+		}
+	})
+	return nil
+}
+
+// collectPackages removes the packages not retained by the specified policy
+// and the BLOBs not referenced by any package
+func (p *Process) collectPackages(ctx context.Context, policy opscenter.Policy) error {
+	apps, err := opscenter.ClusterApps(p.operator)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	pruner, err := opscenter.New(opscenter.Config{
+		Config: prune.Config{
+			FieldLogger: p.WithField(trace.Component, "gc:opscenter"),
+			Silent:      localenv.Silent(true),
+		},
+		Packages: p.packages,
+		Objects:  p.clusterObjects,
+		Apps:     apps,
+		Policy:   policy,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	report, err := pruner.Collect(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if len(report.Packages) == 0 && len(report.BLOBs) == 0 {
+		return nil
+	}
+	if err := pruner.Remove(ctx, *report); err != nil {
+		return trace.Wrap(err)
+	}
+	localCtx := context.WithValue(ctx, constants.UserContext,
+		constants.ServicePackageGC)
+	events.Emit(localCtx, p.operator, events.PackagesPruned, events.Fields{
+		events.FieldRemoved:   len(report.Packages),
+		events.FieldReclaimed: report.SizeBytes(),
+	})
+	return nil
+}
+
+// startFleetExecutor registers a service that rolls out fleet operations
+// across the clusters connected to this Ops Center
+func (p *Process) startFleetExecutor(clusterClients *clients.ClusterClients) error {
+	executor, err := fleet.New(fleet.Config{
+		Backend:     p.backend,
+		Operator:    p.operator,
+		Packages:    p.packages,
+		Apps:        p.applications,
+		Clients:     clusterClients,
+		FieldLogger: p.WithField(trace.Component, "fleet"),
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	p.RegisterClusterService(func(ctx context.Context) error {
+		localCtx := context.WithValue(ctx, constants.UserContext,
+			constants.ServiceFleetExecutor)
+		return trace.Wrap(executor.Run(localCtx))
+	})
+	return nil
+}
+
 // startSiteStatusChecker periodically invokes app status hook; should be run in a goroutine
 func (p *Process) startSiteStatusChecker(ctx context.Context) error {
 	site, err := p.operator.GetLocalSite()
@@ -1166,7 +1359,9 @@ func (p *Process) initService(ctx context.Context) (err error) {
 		Backend: p.backend,
 	})
 
-	mon, err := monitoring.NewInfluxDB()
+	mon, err := monitoring.New(monitoring.Config{
+		Provider: p.cfg.MonitoringProvider,
+	})
 	if err != nil {
 		return trace.Wrap(err)
 	}
@@ -1212,6 +1407,7 @@ func (p *Process) initService(ctx context.Context) (err error) {
 		InstallLogFiles: p.cfg.InstallLogFiles,
 		LogForwarders:   logs,
 		AuditLog:        authClient,
+		Objects:         p.clusterObjects,
 	})
 	if err != nil {
 		return trace.Wrap(err)
@@ -1291,6 +1487,24 @@ func (p *Process) initService(ctx context.Context) (err error) {
 			return trace.Wrap(err)
 		}
 
+		if err := p.startBackupScheduler(); err != nil {
+			return trace.Wrap(err)
+		}
+
+		if err := p.startBLOBScrubber(p.context); err != nil {
+			return trace.Wrap(err)
+		}
+
+		if err := p.startPackageGC(); err != nil {
+			return trace.Wrap(err)
+		}
+
+		if p.mode != constants.ComponentSite {
+			if err := p.startFleetExecutor(clusterClients); err != nil {
+				return trace.Wrap(err)
+			}
+		}
+
 		if err := p.startElection(); err != nil {
 			return trace.Wrap(err)
 		}
@@ -1388,6 +1602,21 @@ func (p *Process) initService(ctx context.Context) (err error) {
 		Cluster: p.clusterObjects,
 		Local:   p.localObjects,
 	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	p.metrics = prometheus.NewRegistry()
+	err = p.metrics.Register(&metricsCollector{
+		FieldLogger:     p.FieldLogger,
+		backend:         p.backend,
+		packages:        p.packages,
+		tunnel:          p.ReverseTunnel,
+		refreshInterval: defaults.MetricsRefreshInterval,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
 
 	if seedConfig.Account != nil {
 		account := p.cfg.OpsCenter.SeedConfig.Account
@@ -1437,6 +1666,7 @@ func (p *Process) ServeHealth() error {
 	healthMux := &httprouter.Router{}
 	healthMux.HandlerFunc("GET", "/readyz", p.ReportReadiness)
 	healthMux.HandlerFunc("GET", "/healthz", p.ReportHealth)
+	healthMux.HandlerFunc("GET", "/metrics", p.ServeMetrics)
 	p.RegisterFunc("gravity.healthz", func() error {
 		p.Infof("Start healthcheck server on %v.", p.cfg.HealthAddr)
 		return trace.Wrap(http.ListenAndServe(p.cfg.HealthAddr.Addr, healthMux))
@@ -1507,10 +1737,10 @@ func (p *Process) initMux(ctx context.Context) error {
 		mux.Handler(method, "/v1/webapi/*webapi", p.handlers.WebProxy)
 		mux.Handler(method, "/portalapi/v1/*portalapi", http.StripPrefix("/portalapi/v1", p.handlers.WebAPI))
 		mux.Handler(method, "/sites/*rest", p.handlers.Proxy)
-		mux.Handler(method, "/pack/*packages", p.handlers.Packages)
-		mux.Handler(method, "/portal/*portal", p.handlers.Operator)
+		mux.Handler(method, "/pack/*packages", instrumentHandler("pack", p.handlers.Packages))
+		mux.Handler(method, "/portal/*portal", instrumentHandler("ops", p.handlers.Operator))
 		mux.Handler(method, "/t/*portal", p.handlers.Operator) // shortener for instructions tokens
-		mux.Handler(method, "/app/*apps", p.handlers.Apps)
+		mux.Handler(method, "/app/*apps", instrumentHandler("app", p.handlers.Apps))
 		mux.Handler(method, "/telekube/*rest", p.handlers.Apps)
 		mux.Handler(method, "/charts/*rest", p.handlers.Apps)
 		mux.Handler(method, "/objects/*rest", p.handlers.BLOB)
@@ -2177,6 +2407,60 @@ func initSelfSignedHTTPSCert(cfg *service.Config, hostname string) (err error) {
 	return nil
 }
 
+// newLocalObjects returns the local BLOB storage in the specified directory.
+// If chunked is set, objects are stored as deduplicated chunks, otherwise as
+// whole files. Objects stored in the other layout are migrated on start
+func newLocalObjects(dir string, chunked bool) (blob.Objects, error) {
+	fsObjects, err := blobfs.New(dir)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	chunkedDir, err := utils.IsDirectory(blobchunked.IndexDir(dir))
+	if err != nil && !trace.IsNotFound(err) {
+		return nil, trace.Wrap(err)
+	}
+	if !chunked && !chunkedDir {
+		return fsObjects, nil
+	}
+	chunkedObjects, err := blobchunked.New(blobchunked.Config{Path: dir})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	config := blobchunked.MigrateConfig{
+		From:        fsObjects,
+		To:          chunkedObjects,
+		FieldLogger: logrus.WithField(trace.Component, constants.ComponentBLOB),
+	}
+	objects := blob.Objects(chunkedObjects)
+	if !chunked {
+		config.From, config.To = chunkedObjects, fsObjects
+		objects = fsObjects
+	}
+	if _, err := blobchunked.Migrate(config); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return objects, nil
+}
+
+// newS3Objects returns the BLOB storage in the S3 bucket specified with config.
+// BLOBs stored locally before the switch to S3 are moved into the bucket
+// so the packages referencing them remain readable
+func newS3Objects(config blobs3.Config, local blob.Objects) (blob.Objects, error) {
+	objects, err := blobs3.New(config)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	_, err = blobchunked.Migrate(blobchunked.MigrateConfig{
+		From:        local,
+		To:          objects,
+		FieldLogger: logrus.WithField(trace.Component, constants.ComponentBLOB),
+	})
+	if err != nil {
+		return nil, trace.Wrap(err, "failed to move local packages to the S3 bucket")
+	}
+	return objects, nil
+}
+
 func isLegacyKubeVersion(version version.Info) bool {
 	return version.Major == constants.KubeLegacyVersion.Major && version.Minor == constants.KubeLegacyVersion.Minor
 }
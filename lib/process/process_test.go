@@ -17,10 +17,12 @@ limitations under the License.
 package process
 
 import (
+	"bytes"
 	"context"
 	"path/filepath"
 	"time"
 
+	"github.com/gravitational/gravity/lib/blob"
 	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/storage/keyval"
@@ -242,3 +244,23 @@ func (s *ProcessSuite) TestReverseTunnelsFromTrustedClusters(c *check.C) {
 		c.Assert(tunnels, check.DeepEquals, testCase.tunnels, check.Commentf(testCase.comment))
 	}
 }
+
+func (s *ProcessSuite) TestLocalObjectsMigration(c *check.C) {
+	dir := c.MkDir()
+	objects, err := newLocalObjects(dir, false)
+	c.Assert(err, check.IsNil)
+	data := []byte("package data")
+	envelope, err := objects.WriteBLOB(bytes.NewReader(data))
+	c.Assert(err, check.IsNil)
+
+	for _, chunked := range []bool{true, false} {
+		comment := check.Commentf("chunked: %v", chunked)
+		objects, err := newLocalObjects(dir, chunked)
+		c.Assert(err, check.IsNil, comment)
+		_, isChunked := objects.(blob.ChunkedObjects)
+		c.Assert(isChunked, check.Equals, chunked, comment)
+		hashes, err := objects.GetBLOBs()
+		c.Assert(err, check.IsNil, comment)
+		c.Assert(hashes, check.DeepEquals, []string{envelope.SHA512}, comment)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/defaults"
@@ -138,6 +139,11 @@ type Config struct {
 	// BackendType is a type of storage backend
 	BackendType string `yaml:"backend_type"`
 
+	// MonitoringProvider selects the cluster monitoring provider:
+	// influxdb or prometheus. If unspecified, the provider is detected
+	// from the monitoring services deployed in the cluster
+	MonitoringProvider string `yaml:"monitoring_provider"`
+
 	// ETCD provides etcd config options
 	ETCD keyval.ETCDConfig `yaml:"etcd"`
 
@@ -308,6 +314,71 @@ type PackageServiceConfig struct {
 
 	// ReadDir is an optional directory with extra packages
 	ReadDir string `yaml:"read_dir"`
+
+	// ChunkedStorage enables storing packages as content-defined
+	// chunks so the data shared between packages is stored once
+	ChunkedStorage bool `yaml:"chunked_storage"`
+
+	// ReplicationFactor is the number of Ops Center instances
+	// storing each package. If 0, packages are stored on all instances
+	ReplicationFactor int `yaml:"replication_factor"`
+
+	// ScrubInterval is the interval between verifications of the
+	// integrity of the stored packages
+	ScrubInterval time.Duration `yaml:"scrub_interval"`
+
+	// GC defines the garbage collection of unused packages
+	// in the Ops Center package storage
+	GC PackageGCConfig `yaml:"gc"`
+
+	// S3 configures an S3-compatible object store to keep package data in.
+	// If set, package data is not stored on the Ops Center nodes
+	S3 *S3Config `yaml:"s3"`
+}
+
+// S3Config defines an S3-compatible object store for package data
+type S3Config struct {
+	// Bucket is the bucket to store package data in
+	Bucket string `yaml:"bucket"`
+	// Prefix is the key prefix for package data within the bucket
+	Prefix string `yaml:"prefix"`
+	// Region is the bucket region
+	Region string `yaml:"region"`
+	// Endpoint is an optional endpoint of an S3-compatible
+	// object store, e.g. a Minio server
+	Endpoint string `yaml:"endpoint"`
+}
+
+// PackageGCConfig defines the garbage collection of unused packages
+// in the Ops Center package storage
+type PackageGCConfig struct {
+	// Enabled enables periodic garbage collection
+	Enabled bool `yaml:"enabled"`
+	// Interval is the interval between garbage collections
+	Interval time.Duration `yaml:"interval"`
+	// KeepVersions is the number of the latest versions
+	// of each application to retain
+	KeepVersions int `yaml:"keep_versions"`
+	// MaxAge is the minimum age of the application version
+	// eligible for removal
+	MaxAge time.Duration `yaml:"max_age"`
+}
+
+// GetInterval returns the interval between garbage collections
+func (c PackageGCConfig) GetInterval() time.Duration {
+	if c.Interval == 0 {
+		return defaults.PackageGCInterval
+	}
+	return c.Interval
+}
+
+// GetScrubInterval returns the interval between verifications
+// of the integrity of the stored packages
+func (p *PackageServiceConfig) GetScrubInterval() time.Duration {
+	if p.ScrubInterval == 0 {
+		return defaults.BLOBScrubInterval
+	}
+	return p.ScrubInterval
 }
 
 // PeerAddr returns peer address of the package service instance
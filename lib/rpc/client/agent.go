@@ -50,6 +50,14 @@ func (c *client) GravityCommand(ctx context.Context, log logrus.FieldLogger, w i
 	return trace.Wrap(err)
 }
 
+// ProgressWriter is an optional interface implemented by the output writer
+// passed to Command or GravityCommand to receive structured progress
+// reported by the remote command
+type ProgressWriter interface {
+	// WriteProgress receives the specified progress update
+	WriteProgress(*pb.Progress) error
+}
+
 // Validate validates the node against the specified manifest and profile.
 // Returns the list of failed probes
 func (c *client) Validate(ctx context.Context, req *validationpb.ValidateRequest) ([]*agentpb.Probe, error) {
@@ -115,6 +123,8 @@ func processStream(stream pb.IncomingMessageStream, log logrus.FieldLogger, out
 			err = trace.Wrap(streamCtx.processLogEntry(elem.LogEntry))
 		case *pb.Message_Error:
 			err = trace.Wrap(streamCtx.processError(elem.Error))
+		case *pb.Message_Progress:
+			err = trace.Wrap(streamCtx.processProgress(elem.Progress, out))
 		default:
 			err = trace.BadParameter("unexpected message %+v", msg.Element)
 		}
@@ -191,6 +201,34 @@ func (s *streamContext) processLogEntry(msg *pb.LogEntry) error {
 	return nil
 }
 
+func (s *streamContext) processProgress(msg *pb.Progress, out io.Writer) error {
+	fields := logrus.Fields{trace.Component: "rpc",
+		"seq":        msg.Seq,
+		"completion": msg.Completion,
+	}
+	for k, v := range msg.Fields {
+		fields[k] = v
+	}
+
+	entry := s.log.WithFields(fields)
+
+	switch msg.Level {
+	case pb.LogEntry_Debug:
+		entry.Debug(msg.Message)
+	case pb.LogEntry_Warn:
+		entry.Warning(msg.Message)
+	case pb.LogEntry_Error:
+		entry.Error(msg.Message)
+	default:
+		entry.Info(msg.Message)
+	}
+
+	if w, ok := out.(ProgressWriter); ok {
+		return trace.Wrap(w.WriteProgress(msg))
+	}
+	return nil
+}
+
 func (s *streamContext) processError(msg *pb.Error) error {
 	s.log.Error(msg.Message)
 	return nil
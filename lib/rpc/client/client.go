@@ -53,6 +53,12 @@ type Client interface {
 	CheckPorts(context.Context, *validationpb.CheckPortsRequest) (*validationpb.CheckPortsResponse, error)
 	// CheckBandwidth executes a network bandwidth test
 	CheckBandwidth(context.Context, *validationpb.CheckBandwidthRequest) (*validationpb.CheckBandwidthResponse, error)
+	// PutFile uploads the local file src to the path dst on the remote node
+	PutFile(ctx context.Context, log logrus.FieldLogger, src, dst string) error
+	// GetFile downloads the file src from the remote node to the local path dst
+	GetFile(ctx context.Context, log logrus.FieldLogger, src, dst string) error
+	// StatFile returns information about the file at path on the remote node
+	StatFile(ctx context.Context, path string) (*pb.FileInfo, error)
 	// Shutdown requests remote agent to shut down
 	Shutdown(context.Context) error
 	// Close will close communication with remote agent
@@ -0,0 +1,136 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"io"
+	"os"
+
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+)
+
+// PutFile uploads the local file src to the path dst on the remote node.
+// If a previous upload of the file has been interrupted, the transfer
+// is resumed from where it stopped
+func (c *client) PutFile(ctx context.Context, log logrus.FieldLogger, src, dst string) error {
+	info, err := pb.DescribeFile(src)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if !info.Exists {
+		return trace.NotFound("file %v not found", src)
+	}
+	partial, err := c.agent.StatFile(ctx, &pb.StatFileRequest{Path: dst, Partial: true})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	offset, err := resumeOffset(src, *partial, info.Size_)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if offset > 0 {
+		log.WithField("offset", offset).Info("Resuming upload.")
+	}
+	f, err := os.Open(src)
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	defer f.Close()
+	stream, err := c.agent.PutFile(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	checksum, err := pb.SendFile(f, pb.FileInfo{
+		Path:  dst,
+		Size_: info.Size_,
+		Mode:  info.Mode,
+	}, offset, stream.Send)
+	if err != nil && trace.Unwrap(err) != io.EOF {
+		return trace.Wrap(err)
+	}
+	// Any error from the server is reported here
+	result, err := stream.CloseAndRecv()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if result.Checksum != checksum {
+		return trace.CompareFailed("checksum mismatch for %v: expected %v but got %v",
+			dst, checksum, result.Checksum)
+	}
+	log.WithField("checksum", result.Checksum).Debugf("Uploaded %v to %v.", src, dst)
+	return nil
+}
+
+// GetFile downloads the file src from the remote node to the local path dst.
+// If a previous download of the file has been interrupted, the transfer
+// is resumed from where it stopped
+func (c *client) GetFile(ctx context.Context, log logrus.FieldLogger, src, dst string) error {
+	partial, err := pb.DescribeFile(pb.PartialPath(dst))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if partial.Size_ > 0 {
+		log.WithField("offset", partial.Size_).Info("Resuming download.")
+	}
+	stream, err := c.agent.GetFile(ctx, &pb.GetFileRequest{Path: src, Offset: partial.Size_})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	chunk, err := stream.Recv()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if chunk.Info == nil {
+		return trace.BadParameter("first chunk must describe the file")
+	}
+	info, err := pb.ReceiveFile(dst, chunk, stream.Recv)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	log.WithField("checksum", info.Checksum).Debugf("Downloaded %v to %v.", src, dst)
+	return nil
+}
+
+// StatFile returns information about the file at path on the remote node
+func (c *client) StatFile(ctx context.Context, path string) (*pb.FileInfo, error) {
+	info, err := c.agent.StatFile(ctx, &pb.StatFileRequest{Path: path})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return info, nil
+}
+
+// resumeOffset returns the offset to resume the upload of the file at path from
+// given the state of the partial upload.
+// Returns 0 if the partial upload does not match the beginning of the file
+func resumeOffset(path string, partial pb.FileInfo, size int64) (int64, error) {
+	if !partial.Exists || partial.Size_ == 0 || partial.Size_ > size {
+		return 0, nil
+	}
+	checksum, err := pb.FileChecksum(path, partial.Size_)
+	if err != nil {
+		return 0, trace.Wrap(err)
+	}
+	if checksum != partial.Checksum {
+		return 0, nil
+	}
+	return partial.Size_, nil
+}
@@ -0,0 +1,274 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rpc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/license/authority"
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+)
+
+// ReloadingCredentials returns server and client credentials read from the specified
+// secrets dir.
+// Unlike Credentials, the returned credentials are reloaded once the files
+// in secretsDir change which allows the credentials of a running agent to be rotated
+func ReloadingCredentials(secretsDir string) (server credentials.TransportCredentials, client credentials.TransportCredentials, err error) {
+	server, err = newReloadingCredentials(secretsDir, pb.Server, ServerCredentials)
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	client, err = ReloadingClientCredentials(secretsDir)
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	return server, client, nil
+}
+
+// ReloadingClientCredentials returns client credentials read from the specified
+// secrets dir that are reloaded once the files in secretsDir change
+func ReloadingClientCredentials(secretsDir string) (credentials.TransportCredentials, error) {
+	creds, err := newReloadingCredentials(secretsDir, pb.Client, ClientCredentials)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return creds, nil
+}
+
+// ReadCredentials reads the credentials archive from the specified secrets dir
+func ReadCredentials(secretsDir string) (utils.TLSArchive, error) {
+	archive := make(utils.TLSArchive)
+	for _, name := range []string{pb.Server, pb.Client} {
+		certPEM, err := ioutil.ReadFile(credentialsPath(secretsDir, name, pb.Cert))
+		if err != nil {
+			return nil, trace.ConvertSystemError(err)
+		}
+		keyPEM, err := ioutil.ReadFile(credentialsPath(secretsDir, name, pb.Key))
+		if err != nil {
+			return nil, trace.ConvertSystemError(err)
+		}
+		archive[name] = &authority.TLSKeyPair{CertPEM: certPEM, KeyPEM: keyPEM}
+	}
+	caPEM, err := ioutil.ReadFile(credentialsPath(secretsDir, pb.CA, pb.Cert))
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	archive[pb.CA] = &authority.TLSKeyPair{CertPEM: caPEM}
+	return archive, nil
+}
+
+// WriteCredentials writes the credentials from the specified archive into secretsDir.
+// Only the key pairs present in the archive are written
+func WriteCredentials(secretsDir string, archive utils.TLSArchive) error {
+	if err := os.MkdirAll(secretsDir, defaults.SharedDirMask); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	for name, keyPair := range archive {
+		if len(keyPair.KeyPEM) != 0 {
+			err := ioutil.WriteFile(credentialsPath(secretsDir, name, pb.Key),
+				keyPair.KeyPEM, defaults.GroupReadMask)
+			if err != nil {
+				return trace.ConvertSystemError(err)
+			}
+		}
+		if len(keyPair.CertPEM) != 0 {
+			err := ioutil.WriteFile(credentialsPath(secretsDir, name, pb.Cert),
+				keyPair.CertPEM, defaults.SharedReadMask)
+			if err != nil {
+				return trace.ConvertSystemError(err)
+			}
+		}
+	}
+	return nil
+}
+
+// CredentialsFiles returns the names of the files in the specified archive
+// as written by WriteCredentials, sorted
+func CredentialsFiles(archive utils.TLSArchive) (files []string) {
+	for name, keyPair := range archive {
+		if len(keyPair.KeyPEM) != 0 {
+			files = append(files, fmt.Sprintf("%s.%s", name, pb.Key))
+		}
+		if len(keyPair.CertPEM) != 0 {
+			files = append(files, fmt.Sprintf("%s.%s", name, pb.Cert))
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// TrustBundle returns a CA key pair that combines the certificate authorities
+// from the specified archives.
+// Credentials using the bundle accept certificates signed by any of the authorities
+func TrustBundle(archives ...utils.TLSArchive) (*authority.TLSKeyPair, error) {
+	var certs []string
+	for _, archive := range archives {
+		ca, err := archive.GetKeyPair(pb.CA)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		certs = append(certs, strings.TrimSpace(string(ca.CertPEM)))
+	}
+	return &authority.TLSKeyPair{
+		CertPEM: []byte(strings.Join(certs, "\n") + "\n"),
+	}, nil
+}
+
+func newReloadingCredentials(secretsDir, name string, load loadCredentialsFunc) (*reloadingCredentials, error) {
+	creds := &reloadingCredentials{
+		secretsDir: secretsDir,
+		paths: []string{
+			credentialsPath(secretsDir, name, pb.Cert),
+			credentialsPath(secretsDir, name, pb.Key),
+			credentialsPath(secretsDir, pb.CA, pb.Cert),
+		},
+		load: load,
+	}
+	if err := creds.reload(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return creds, nil
+}
+
+// ClientHandshake performs the client-side TLS handshake with the current credentials.
+// Implements credentials.TransportCredentials
+func (r *reloadingCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return r.current().ClientHandshake(ctx, authority, conn)
+}
+
+// ServerHandshake performs the server-side TLS handshake with the current credentials.
+// Implements credentials.TransportCredentials
+func (r *reloadingCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return r.current().ServerHandshake(conn)
+}
+
+// Info returns the protocol information.
+// Implements credentials.TransportCredentials
+func (r *reloadingCredentials) Info() credentials.ProtocolInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.creds.Info()
+}
+
+// Clone returns a copy of these credentials.
+// Implements credentials.TransportCredentials
+func (r *reloadingCredentials) Clone() credentials.TransportCredentials {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &reloadingCredentials{
+		secretsDir: r.secretsDir,
+		paths:      r.paths,
+		load:       r.load,
+		creds:      r.creds.Clone(),
+		version:    r.version,
+		serverName: r.serverName,
+	}
+}
+
+// OverrideServerName overrides the server name used to verify the server certificate.
+// Implements credentials.TransportCredentials
+func (r *reloadingCredentials) OverrideServerName(serverName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.serverName = serverName
+	return r.creds.OverrideServerName(serverName)
+}
+
+// current returns the up-to-date credentials.
+// If reloading fails, the previously loaded credentials are returned
+func (r *reloadingCredentials) current() credentials.TransportCredentials {
+	if err := r.reload(); err != nil {
+		log.WithError(err).Warnf("Failed to reload credentials from %v.", r.secretsDir)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.creds
+}
+
+func (r *reloadingCredentials) reload() error {
+	version, err := r.filesVersion()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.creds != nil && version == r.version {
+		return nil
+	}
+	creds, err := r.load(r.secretsDir)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if r.serverName != "" {
+		if err := creds.OverrideServerName(r.serverName); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if r.creds != nil {
+		log.WithField("dir", r.secretsDir).Info("Reloaded credentials.")
+	}
+	r.creds = creds
+	r.version = version
+	return nil
+}
+
+// filesVersion returns a value that changes whenever any of the credentials files change
+func (r *reloadingCredentials) filesVersion() (string, error) {
+	var version []string
+	for _, path := range r.paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return "", trace.ConvertSystemError(err)
+		}
+		version = append(version, fmt.Sprintf("%v:%v", fi.ModTime().UnixNano(), fi.Size()))
+	}
+	return strings.Join(version, ","), nil
+}
+
+// reloadingCredentials implements credentials.TransportCredentials
+// by delegating to the credentials loaded from the secrets directory.
+// The credentials are reloaded whenever any of the files change
+type reloadingCredentials struct {
+	secretsDir string
+	paths      []string
+	load       loadCredentialsFunc
+
+	mu         sync.Mutex
+	creds      credentials.TransportCredentials
+	version    string
+	serverName string
+}
+
+type loadCredentialsFunc func(secretsDir string) (credentials.TransportCredentials, error)
+
+func credentialsPath(secretsDir, name, ext string) string {
+	return filepath.Join(secretsDir, fmt.Sprintf("%s.%s", name, ext))
+}
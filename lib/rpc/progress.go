@@ -0,0 +1,174 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+
+	"github.com/gravitational/gravity/lib/constants"
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+)
+
+// ProgressReporter reports structured progress of a command
+// executed by the RPC agent
+type ProgressReporter interface {
+	// ReportProgress sends the specified progress update to the agent
+	ReportProgress(pb.Progress) error
+}
+
+// NewProgressReporter returns a reporter that forwards progress updates
+// to the RPC agent that started this process.
+// If the process has not been started by an agent, the returned reporter
+// discards all updates
+func NewProgressReporter() ProgressReporter {
+	progressOnce.Do(func() {
+		reporter = newProgressReporter()
+	})
+	return reporter
+}
+
+// NewProgress returns a progress printer that, in addition to the
+// specified progress, forwards all progress messages to the given reporter
+func NewProgress(progress utils.Progress, reporter ProgressReporter) utils.Progress {
+	return &remoteProgress{
+		Progress: progress,
+		reporter: reporter,
+	}
+}
+
+// ReportProgress reports the specified progress update with completion
+// and fields.
+// If progress forwards updates to the RPC agent, the update is sent to the agent
+// as-is, otherwise only the message is printed as a sub-step
+func ReportProgress(progress utils.Progress, update pb.Progress) {
+	if reporter, ok := progress.(ProgressReporter); ok {
+		// Progress reporting is best-effort and should not affect the command
+		reporter.ReportProgress(update)
+		return
+	}
+	progress.PrintSubStep("%v", update.Message)
+}
+
+// ReportProgress prints the message of the specified progress update
+// and forwards the complete update to the reporter.
+// Implements ProgressReporter
+func (r *remoteProgress) ReportProgress(update pb.Progress) error {
+	r.Progress.PrintSubStep("%v", update.Message)
+	if update.Level == pb.LogEntry_Debug {
+		update.Level = pb.LogEntry_Info
+	}
+	return trace.Wrap(r.reporter.ReportProgress(update))
+}
+
+// UpdateCurrentStep updates message printed for current step that is in progress
+func (r *remoteProgress) UpdateCurrentStep(message string, args ...interface{}) {
+	r.Progress.UpdateCurrentStep(message, args...)
+	r.report(pb.LogEntry_Info, message, args...)
+}
+
+// NextStep prints information about next step
+func (r *remoteProgress) NextStep(message string, args ...interface{}) {
+	r.Progress.NextStep(message, args...)
+	r.report(pb.LogEntry_Info, message, args...)
+}
+
+// PrintCurrentStep updates and prints current step
+func (r *remoteProgress) PrintCurrentStep(message string, args ...interface{}) {
+	r.Progress.PrintCurrentStep(message, args...)
+	r.report(pb.LogEntry_Info, message, args...)
+}
+
+// PrintSubStep outputs the message as a sub-step of the current step
+func (r *remoteProgress) PrintSubStep(message string, args ...interface{}) {
+	r.Progress.PrintSubStep(message, args...)
+	r.report(pb.LogEntry_Info, message, args...)
+}
+
+// PrintInfo outputs the specified info message
+func (r *remoteProgress) PrintInfo(message string, args ...interface{}) {
+	r.Progress.PrintInfo(message, args...)
+	r.report(pb.LogEntry_Info, message, args...)
+}
+
+// PrintWarn outputs the specified warning message
+func (r *remoteProgress) PrintWarn(err error, message string, args ...interface{}) {
+	r.Progress.PrintWarn(err, message, args...)
+	r.report(pb.LogEntry_Warn, message, args...)
+}
+
+func (r *remoteProgress) report(level pb.LogEntry_Level, message string, args ...interface{}) {
+	// Progress reporting is best-effort and should not affect the command
+	r.reporter.ReportProgress(pb.Progress{
+		Level:   level,
+		Message: fmt.Sprintf(message, args...),
+	})
+}
+
+type remoteProgress struct {
+	utils.Progress
+	reporter ProgressReporter
+}
+
+func newProgressReporter() ProgressReporter {
+	value := os.Getenv(constants.ProgressFDEnvVar)
+	if value == "" {
+		return nopReporter{}
+	}
+	// Make sure the descriptor is not inherited by processes
+	// this command starts
+	os.Unsetenv(constants.ProgressFDEnvVar)
+	fd, err := strconv.Atoi(value)
+	if err != nil {
+		return nopReporter{}
+	}
+	syscall.CloseOnExec(fd)
+	return &fileReporter{
+		encoder: json.NewEncoder(os.NewFile(uintptr(fd), "progress")),
+	}
+}
+
+// ReportProgress writes the progress update to the underlying file
+func (r *fileReporter) ReportProgress(progress pb.Progress) error {
+	r.Lock()
+	defer r.Unlock()
+	return trace.Wrap(r.encoder.Encode(progress))
+}
+
+type fileReporter struct {
+	sync.Mutex
+	encoder *json.Encoder
+}
+
+// ReportProgress discards the specified progress update
+func (nopReporter) ReportProgress(pb.Progress) error {
+	return nil
+}
+
+type nopReporter struct{}
+
+var (
+	progressOnce sync.Once
+	reporter     ProgressReporter
+)
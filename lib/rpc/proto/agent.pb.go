@@ -113,6 +113,7 @@ type Message struct {
 	//	*Message_ExecOutput
 	//	*Message_LogEntry
 	//	*Message_Error
+	//	*Message_Progress
 	Element isMessage_Element `protobuf_oneof:"element"`
 }
 
@@ -142,12 +143,16 @@ type Message_LogEntry struct {
 type Message_Error struct {
 	Error *Error `protobuf:"bytes,5,opt,name=error,oneof"`
 }
+type Message_Progress struct {
+	Progress *Progress `protobuf:"bytes,6,opt,name=progress,oneof"`
+}
 
 func (*Message_ExecStarted) isMessage_Element()   {}
 func (*Message_ExecCompleted) isMessage_Element() {}
 func (*Message_ExecOutput) isMessage_Element()    {}
 func (*Message_LogEntry) isMessage_Element()      {}
 func (*Message_Error) isMessage_Element()         {}
+func (*Message_Progress) isMessage_Element()      {}
 
 func (m *Message) GetElement() isMessage_Element {
 	if m != nil {
@@ -191,6 +196,13 @@ func (m *Message) GetError() *Error {
 	return nil
 }
 
+func (m *Message) GetProgress() *Progress {
+	if x, ok := m.GetElement().(*Message_Progress); ok {
+		return x.Progress
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*Message) XXX_OneofFuncs() (func(msg proto1.Message, b *proto1.Buffer) error, func(msg proto1.Message, tag, wire int, b *proto1.Buffer) (bool, error), func(msg proto1.Message) (n int), []interface{}) {
 	return _Message_OneofMarshaler, _Message_OneofUnmarshaler, _Message_OneofSizer, []interface{}{
@@ -199,6 +211,7 @@ func (*Message) XXX_OneofFuncs() (func(msg proto1.Message, b *proto1.Buffer) err
 		(*Message_ExecOutput)(nil),
 		(*Message_LogEntry)(nil),
 		(*Message_Error)(nil),
+		(*Message_Progress)(nil),
 	}
 }
 
@@ -231,6 +244,11 @@ func _Message_OneofMarshaler(msg proto1.Message, b *proto1.Buffer) error {
 		if err := b.EncodeMessage(x.Error); err != nil {
 			return err
 		}
+	case *Message_Progress:
+		_ = b.EncodeVarint(6<<3 | proto1.WireBytes)
+		if err := b.EncodeMessage(x.Progress); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("Message.Element has unexpected type %T", x)
@@ -281,6 +299,14 @@ func _Message_OneofUnmarshaler(msg proto1.Message, tag, wire int, b *proto1.Buff
 		err := b.DecodeMessage(msg)
 		m.Element = &Message_Error{msg}
 		return true, err
+	case 6: // element.progress
+		if wire != proto1.WireBytes {
+			return true, proto1.ErrInternalBadWireType
+		}
+		msg := new(Progress)
+		err := b.DecodeMessage(msg)
+		m.Element = &Message_Progress{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -315,6 +341,11 @@ func _Message_OneofSizer(msg proto1.Message) (n int) {
 		n += proto1.SizeVarint(5<<3 | proto1.WireBytes)
 		n += proto1.SizeVarint(uint64(s))
 		n += s
+	case *Message_Progress:
+		s := proto1.Size(x.Progress)
+		n += proto1.SizeVarint(6<<3 | proto1.WireBytes)
+		n += proto1.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -494,6 +525,60 @@ func (m *LogEntry) GetTraces() []string {
 	return nil
 }
 
+// Progress is sent when a running command reports its progress
+type Progress struct {
+	// Seq specifies the command ID. Unique only in the current call scope
+	Seq int32 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	// Completion specifies the completion percentage in the range 0-100
+	Completion int32 `protobuf:"varint,2,opt,name=completion,proto3" json:"completion,omitempty"`
+	// Message is the human-readable progress message
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// Level specifies the severity of the progress message
+	Level LogEntry_Level `protobuf:"varint,4,opt,name=level,proto3,enum=proto.LogEntry_Level" json:"level,omitempty"`
+	// Fields lists additional progress attributes
+	Fields map[string]string `protobuf:"bytes,5,rep,name=fields" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *Progress) Reset()                    { *m = Progress{} }
+func (m *Progress) String() string            { return proto1.CompactTextString(m) }
+func (*Progress) ProtoMessage()               {}
+func (*Progress) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{7} }
+
+func (m *Progress) GetSeq() int32 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *Progress) GetCompletion() int32 {
+	if m != nil {
+		return m.Completion
+	}
+	return 0
+}
+
+func (m *Progress) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Progress) GetLevel() LogEntry_Level {
+	if m != nil {
+		return m.Level
+	}
+	return LogEntry_Debug
+}
+
+func (m *Progress) GetFields() map[string]string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
 // PeerJoinRequest is request to join from a remote peer.
 type PeerJoinRequest struct {
 	// Addr is the peer address as host:port
@@ -507,7 +592,7 @@ type PeerJoinRequest struct {
 func (m *PeerJoinRequest) Reset()                    { *m = PeerJoinRequest{} }
 func (m *PeerJoinRequest) String() string            { return proto1.CompactTextString(m) }
 func (*PeerJoinRequest) ProtoMessage()               {}
-func (*PeerJoinRequest) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{7} }
+func (*PeerJoinRequest) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{8} }
 
 func (m *PeerJoinRequest) GetAddr() string {
 	if m != nil {
@@ -543,7 +628,7 @@ type PeerLeaveRequest struct {
 func (m *PeerLeaveRequest) Reset()                    { *m = PeerLeaveRequest{} }
 func (m *PeerLeaveRequest) String() string            { return proto1.CompactTextString(m) }
 func (*PeerLeaveRequest) ProtoMessage()               {}
-func (*PeerLeaveRequest) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{8} }
+func (*PeerLeaveRequest) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{9} }
 
 func (m *PeerLeaveRequest) GetAddr() string {
 	if m != nil {
@@ -566,6 +651,153 @@ func (m *PeerLeaveRequest) GetSystemInfo() []byte {
 	return nil
 }
 
+// FileInfo describes a file
+type FileInfo struct {
+	// Path is the absolute path to the file
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Size is the size of the file in bytes
+	Size_ int64 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	// Mode specifies the file permissions
+	Mode uint32 `protobuf:"varint,3,opt,name=mode,proto3" json:"mode,omitempty"`
+	// Checksum is the hex-encoded SHA256 checksum of the file contents
+	Checksum string `protobuf:"bytes,4,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	// Exists specifies whether the file exists
+	Exists bool `protobuf:"varint,5,opt,name=exists,proto3" json:"exists,omitempty"`
+}
+
+func (m *FileInfo) Reset()                    { *m = FileInfo{} }
+func (m *FileInfo) String() string            { return proto1.CompactTextString(m) }
+func (*FileInfo) ProtoMessage()               {}
+func (*FileInfo) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{10} }
+
+func (m *FileInfo) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *FileInfo) GetSize_() int64 {
+	if m != nil {
+		return m.Size_
+	}
+	return 0
+}
+
+func (m *FileInfo) GetMode() uint32 {
+	if m != nil {
+		return m.Mode
+	}
+	return 0
+}
+
+func (m *FileInfo) GetChecksum() string {
+	if m != nil {
+		return m.Checksum
+	}
+	return ""
+}
+
+func (m *FileInfo) GetExists() bool {
+	if m != nil {
+		return m.Exists
+	}
+	return false
+}
+
+// FileChunk is a part of a file transfer stream
+type FileChunk struct {
+	// Info describes the file being transferred.
+	// Only set on the first chunk of the stream and on the last chunk
+	// which also specifies the checksum of the file contents
+	Info *FileInfo `protobuf:"bytes,1,opt,name=info" json:"info,omitempty"`
+	// Offset specifies the position in the file the data starts at.
+	// Only set on the first chunk of the stream
+	Offset int64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Data is a part of the file contents
+	Data []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *FileChunk) Reset()                    { *m = FileChunk{} }
+func (m *FileChunk) String() string            { return proto1.CompactTextString(m) }
+func (*FileChunk) ProtoMessage()               {}
+func (*FileChunk) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{11} }
+
+func (m *FileChunk) GetInfo() *FileInfo {
+	if m != nil {
+		return m.Info
+	}
+	return nil
+}
+
+func (m *FileChunk) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *FileChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// GetFileRequest is a request to download a file
+type GetFileRequest struct {
+	// Path is the absolute path to the file
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Offset specifies the position in the file to resume the transfer from
+	Offset int64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+}
+
+func (m *GetFileRequest) Reset()                    { *m = GetFileRequest{} }
+func (m *GetFileRequest) String() string            { return proto1.CompactTextString(m) }
+func (*GetFileRequest) ProtoMessage()               {}
+func (*GetFileRequest) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{12} }
+
+func (m *GetFileRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *GetFileRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+// StatFileRequest is a request to describe a file
+type StatFileRequest struct {
+	// Path is the absolute path to the file
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Partial specifies whether to describe the incomplete upload of the file
+	Partial bool `protobuf:"varint,2,opt,name=partial,proto3" json:"partial,omitempty"`
+}
+
+func (m *StatFileRequest) Reset()                    { *m = StatFileRequest{} }
+func (m *StatFileRequest) String() string            { return proto1.CompactTextString(m) }
+func (*StatFileRequest) ProtoMessage()               {}
+func (*StatFileRequest) Descriptor() ([]byte, []int) { return fileDescriptorAgent, []int{13} }
+
+func (m *StatFileRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *StatFileRequest) GetPartial() bool {
+	if m != nil {
+		return m.Partial
+	}
+	return false
+}
+
 func init() {
 	proto1.RegisterType((*CommandArgs)(nil), "proto.CommandArgs")
 	proto1.RegisterType((*Message)(nil), "proto.Message")
@@ -574,8 +806,13 @@ func init() {
 	proto1.RegisterType((*Error)(nil), "proto.Error")
 	proto1.RegisterType((*ExecOutput)(nil), "proto.ExecOutput")
 	proto1.RegisterType((*LogEntry)(nil), "proto.LogEntry")
+	proto1.RegisterType((*Progress)(nil), "proto.Progress")
 	proto1.RegisterType((*PeerJoinRequest)(nil), "proto.PeerJoinRequest")
 	proto1.RegisterType((*PeerLeaveRequest)(nil), "proto.PeerLeaveRequest")
+	proto1.RegisterType((*FileInfo)(nil), "proto.FileInfo")
+	proto1.RegisterType((*FileChunk)(nil), "proto.FileChunk")
+	proto1.RegisterType((*GetFileRequest)(nil), "proto.GetFileRequest")
+	proto1.RegisterType((*StatFileRequest)(nil), "proto.StatFileRequest")
 	proto1.RegisterEnum("proto.ExecOutput_FD", ExecOutput_FD_name, ExecOutput_FD_value)
 	proto1.RegisterEnum("proto.LogEntry_Level", LogEntry_Level_name, LogEntry_Level_value)
 }
@@ -602,6 +839,14 @@ type AgentClient interface {
 	PeerJoin(ctx context.Context, in *PeerJoinRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
 	// PeerLeave receives a "leave" request from a peer and initiates its shutdown
 	PeerLeave(ctx context.Context, in *PeerLeaveRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	// PutFile uploads a file to the agent's node.
+	// The first chunk of the stream describes the file
+	PutFile(ctx context.Context, opts ...grpc.CallOption) (Agent_PutFileClient, error)
+	// GetFile downloads a file from the agent's node.
+	// The first chunk of the stream describes the file
+	GetFile(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (Agent_GetFileClient, error)
+	// StatFile returns information about a file on the agent's node
+	StatFile(ctx context.Context, in *StatFileRequest, opts ...grpc.CallOption) (*FileInfo, error)
 }
 
 type agentClient struct {
@@ -671,6 +916,81 @@ func (c *agentClient) PeerLeave(ctx context.Context, in *PeerLeaveRequest, opts
 	return out, nil
 }
 
+func (c *agentClient) PutFile(ctx context.Context, opts ...grpc.CallOption) (Agent_PutFileClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Agent_serviceDesc.Streams[1], c.cc, "/proto.Agent/PutFile", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &agentPutFileClient{stream}
+	return x, nil
+}
+
+type Agent_PutFileClient interface {
+	Send(*FileChunk) error
+	CloseAndRecv() (*FileInfo, error)
+	grpc.ClientStream
+}
+
+type agentPutFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *agentPutFileClient) Send(m *FileChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *agentPutFileClient) CloseAndRecv() (*FileInfo, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(FileInfo)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *agentClient) GetFile(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (Agent_GetFileClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Agent_serviceDesc.Streams[2], c.cc, "/proto.Agent/GetFile", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &agentGetFileClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Agent_GetFileClient interface {
+	Recv() (*FileChunk, error)
+	grpc.ClientStream
+}
+
+type agentGetFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *agentGetFileClient) Recv() (*FileChunk, error) {
+	m := new(FileChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *agentClient) StatFile(ctx context.Context, in *StatFileRequest, opts ...grpc.CallOption) (*FileInfo, error) {
+	out := new(FileInfo)
+	err := grpc.Invoke(ctx, "/proto.Agent/StatFile", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Agent service
 
 type AgentServer interface {
@@ -685,6 +1005,14 @@ type AgentServer interface {
 	PeerJoin(context.Context, *PeerJoinRequest) (*google_protobuf.Empty, error)
 	// PeerLeave receives a "leave" request from a peer and initiates its shutdown
 	PeerLeave(context.Context, *PeerLeaveRequest) (*google_protobuf.Empty, error)
+	// PutFile uploads a file to the agent's node.
+	// The first chunk of the stream describes the file
+	PutFile(Agent_PutFileServer) error
+	// GetFile downloads a file from the agent's node.
+	// The first chunk of the stream describes the file
+	GetFile(*GetFileRequest, Agent_GetFileServer) error
+	// StatFile returns information about a file on the agent's node
+	StatFile(context.Context, *StatFileRequest) (*FileInfo, error)
 }
 
 func RegisterAgentServer(s *grpc.Server, srv AgentServer) {
@@ -766,6 +1094,71 @@ func _Agent_PeerLeave_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Agent_PutFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AgentServer).PutFile(&agentPutFileServer{stream})
+}
+
+type Agent_PutFileServer interface {
+	SendAndClose(*FileInfo) error
+	Recv() (*FileChunk, error)
+	grpc.ServerStream
+}
+
+type agentPutFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *agentPutFileServer) SendAndClose(m *FileInfo) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *agentPutFileServer) Recv() (*FileChunk, error) {
+	m := new(FileChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Agent_GetFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetFileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AgentServer).GetFile(m, &agentGetFileServer{stream})
+}
+
+type Agent_GetFileServer interface {
+	Send(*FileChunk) error
+	grpc.ServerStream
+}
+
+type agentGetFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *agentGetFileServer) Send(m *FileChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Agent_StatFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).StatFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Agent/StatFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).StatFile(ctx, req.(*StatFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Agent_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto.Agent",
 	HandlerType: (*AgentServer)(nil),
@@ -782,6 +1175,10 @@ var _Agent_serviceDesc = grpc.ServiceDesc{
 			MethodName: "PeerLeave",
 			Handler:    _Agent_PeerLeave_Handler,
 		},
+		{
+			MethodName: "StatFile",
+			Handler:    _Agent_StatFile_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -789,6 +1186,16 @@ var _Agent_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Agent_Command_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "PutFile",
+			Handler:       _Agent_PutFile_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "GetFile",
+			Handler:       _Agent_GetFile_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "agent.proto",
 }
@@ -948,6 +1355,20 @@ func (m *Message_Error) MarshalTo(dAtA []byte) (int, error) {
 	}
 	return i, nil
 }
+func (m *Message_Progress) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.Progress != nil {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintAgent(dAtA, i, uint64(m.Progress.Size()))
+		n7, err := m.Progress.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n7
+	}
+	return i, nil
+}
 func (m *ExecStarted) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -1030,11 +1451,11 @@ func (m *ExecCompleted) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x1a
 		i++
 		i = encodeVarintAgent(dAtA, i, uint64(m.Error.Size()))
-		n7, err := m.Error.MarshalTo(dAtA[i:])
+		n8, err := m.Error.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n7
+		i += n8
 	}
 	return i, nil
 }
@@ -1173,7 +1594,7 @@ func (m *LogEntry) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
-func (m *PeerJoinRequest) Marshal() (dAtA []byte, err error) {
+func (m *Progress) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -1183,37 +1604,53 @@ func (m *PeerJoinRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *PeerJoinRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *Progress) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
 	_ = l
-	if len(m.Addr) > 0 {
-		dAtA[i] = 0xa
+	if m.Seq != 0 {
+		dAtA[i] = 0x8
 		i++
-		i = encodeVarintAgent(dAtA, i, uint64(len(m.Addr)))
-		i += copy(dAtA[i:], m.Addr)
+		i = encodeVarintAgent(dAtA, i, uint64(m.Seq))
 	}
-	if m.Config != nil {
-		dAtA[i] = 0x12
+	if m.Completion != 0 {
+		dAtA[i] = 0x10
 		i++
-		i = encodeVarintAgent(dAtA, i, uint64(m.Config.Size()))
-		n8, err := m.Config.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n8
+		i = encodeVarintAgent(dAtA, i, uint64(m.Completion))
 	}
-	if len(m.SystemInfo) > 0 {
+	if len(m.Message) > 0 {
 		dAtA[i] = 0x1a
 		i++
-		i = encodeVarintAgent(dAtA, i, uint64(len(m.SystemInfo)))
-		i += copy(dAtA[i:], m.SystemInfo)
+		i = encodeVarintAgent(dAtA, i, uint64(len(m.Message)))
+		i += copy(dAtA[i:], m.Message)
 	}
-	return i, nil
+	if m.Level != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintAgent(dAtA, i, uint64(m.Level))
+	}
+	if len(m.Fields) > 0 {
+		for k, _ := range m.Fields {
+			dAtA[i] = 0x2a
+			i++
+			v := m.Fields[k]
+			mapSize := 1 + len(k) + sovAgent(uint64(len(k))) + 1 + len(v) + sovAgent(uint64(len(v)))
+			i = encodeVarintAgent(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintAgent(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintAgent(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
+	return i, nil
 }
 
-func (m *PeerLeaveRequest) Marshal() (dAtA []byte, err error) {
+func (m *PeerJoinRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalTo(dAtA)
@@ -1223,7 +1660,7 @@ func (m *PeerLeaveRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *PeerLeaveRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *PeerJoinRequest) MarshalTo(dAtA []byte) (int, error) {
 	var i int
 	_ = i
 	var l int
@@ -1253,6 +1690,198 @@ func (m *PeerLeaveRequest) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *PeerLeaveRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PeerLeaveRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Addr) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintAgent(dAtA, i, uint64(len(m.Addr)))
+		i += copy(dAtA[i:], m.Addr)
+	}
+	if m.Config != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintAgent(dAtA, i, uint64(m.Config.Size()))
+		n10, err := m.Config.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n10
+	}
+	if len(m.SystemInfo) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintAgent(dAtA, i, uint64(len(m.SystemInfo)))
+		i += copy(dAtA[i:], m.SystemInfo)
+	}
+	return i, nil
+}
+
+func (m *FileInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FileInfo) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Path) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintAgent(dAtA, i, uint64(len(m.Path)))
+		i += copy(dAtA[i:], m.Path)
+	}
+	if m.Size_ != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintAgent(dAtA, i, uint64(m.Size_))
+	}
+	if m.Mode != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintAgent(dAtA, i, uint64(m.Mode))
+	}
+	if len(m.Checksum) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintAgent(dAtA, i, uint64(len(m.Checksum)))
+		i += copy(dAtA[i:], m.Checksum)
+	}
+	if m.Exists {
+		dAtA[i] = 0x28
+		i++
+		if m.Exists {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *FileChunk) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FileChunk) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Info != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintAgent(dAtA, i, uint64(m.Info.Size()))
+		n11, err := m.Info.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n11
+	}
+	if m.Offset != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintAgent(dAtA, i, uint64(m.Offset))
+	}
+	if len(m.Data) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintAgent(dAtA, i, uint64(len(m.Data)))
+		i += copy(dAtA[i:], m.Data)
+	}
+	return i, nil
+}
+
+func (m *GetFileRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetFileRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Path) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintAgent(dAtA, i, uint64(len(m.Path)))
+		i += copy(dAtA[i:], m.Path)
+	}
+	if m.Offset != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintAgent(dAtA, i, uint64(m.Offset))
+	}
+	return i, nil
+}
+
+func (m *StatFileRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StatFileRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Path) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintAgent(dAtA, i, uint64(len(m.Path)))
+		i += copy(dAtA[i:], m.Path)
+	}
+	if m.Partial {
+		dAtA[i] = 0x10
+		i++
+		if m.Partial {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
 func encodeFixed64Agent(dAtA []byte, offset int, v uint64) int {
 	dAtA[offset] = uint8(v)
 	dAtA[offset+1] = uint8(v >> 8)
@@ -1357,6 +1986,15 @@ func (m *Message_Error) Size() (n int) {
 	}
 	return n
 }
+func (m *Message_Progress) Size() (n int) {
+	var l int
+	_ = l
+	if m.Progress != nil {
+		l = m.Progress.Size()
+		n += 1 + l + sovAgent(uint64(l))
+	}
+	return n
+}
 func (m *ExecStarted) Size() (n int) {
 	var l int
 	_ = l
@@ -1453,6 +2091,33 @@ func (m *LogEntry) Size() (n int) {
 	return n
 }
 
+func (m *Progress) Size() (n int) {
+	var l int
+	_ = l
+	if m.Seq != 0 {
+		n += 1 + sovAgent(uint64(m.Seq))
+	}
+	if m.Completion != 0 {
+		n += 1 + sovAgent(uint64(m.Completion))
+	}
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovAgent(uint64(l))
+	}
+	if m.Level != 0 {
+		n += 1 + sovAgent(uint64(m.Level))
+	}
+	if len(m.Fields) > 0 {
+		for k, v := range m.Fields {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovAgent(uint64(len(k))) + 1 + len(v) + sovAgent(uint64(len(v)))
+			n += mapEntrySize + 1 + sovAgent(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
 func (m *PeerJoinRequest) Size() (n int) {
 	var l int
 	_ = l
@@ -1489,6 +2154,72 @@ func (m *PeerLeaveRequest) Size() (n int) {
 	return n
 }
 
+func (m *FileInfo) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovAgent(uint64(l))
+	}
+	if m.Size_ != 0 {
+		n += 1 + sovAgent(uint64(m.Size_))
+	}
+	if m.Mode != 0 {
+		n += 1 + sovAgent(uint64(m.Mode))
+	}
+	l = len(m.Checksum)
+	if l > 0 {
+		n += 1 + l + sovAgent(uint64(l))
+	}
+	if m.Exists {
+		n += 2
+	}
+	return n
+}
+
+func (m *FileChunk) Size() (n int) {
+	var l int
+	_ = l
+	if m.Info != nil {
+		l = m.Info.Size()
+		n += 1 + l + sovAgent(uint64(l))
+	}
+	if m.Offset != 0 {
+		n += 1 + sovAgent(uint64(m.Offset))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovAgent(uint64(l))
+	}
+	return n
+}
+
+func (m *GetFileRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovAgent(uint64(l))
+	}
+	if m.Offset != 0 {
+		n += 1 + sovAgent(uint64(m.Offset))
+	}
+	return n
+}
+
+func (m *StatFileRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovAgent(uint64(l))
+	}
+	if m.Partial {
+		n += 2
+	}
+	return n
+}
+
 func sovAgent(x uint64) (n int) {
 	for {
 		n++
@@ -1906,8 +2637,40 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 			}
 			m.Element = &Message_Error{v}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Progress", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &Progress{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Element = &Message_Progress{v}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
 			skippy, err := skipAgent(dAtA[iNdEx:])
 			if err != nil {
 				return err
@@ -2645,7 +3408,7 @@ func (m *LogEntry) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PeerJoinRequest) Unmarshal(dAtA []byte) error {
+func (m *Progress) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2668,15 +3431,53 @@ func (m *PeerJoinRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PeerJoinRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: Progress: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PeerJoinRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Progress: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Seq", wireType)
+			}
+			m.Seq = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Seq |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Completion", wireType)
+			}
+			m.Completion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Completion |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Addr", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2701,11 +3502,30 @@ func (m *PeerJoinRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Addr = string(dAtA[iNdEx:postIndex])
+			m.Message = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Level", wireType)
+			}
+			m.Level = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Level |= (LogEntry_Level(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Config", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Fields", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2729,18 +3549,22 @@ func (m *PeerJoinRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Config == nil {
-				m.Config = &RuntimeConfig{}
-			}
-			if err := m.Config.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SystemInfo", wireType)
+			var keykey uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				keykey |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			var byteLen int
+			var stringLenmapkey uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowAgent
@@ -2750,21 +3574,69 @@ func (m *PeerJoinRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				stringLenmapkey |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLenmapkey := int(stringLenmapkey)
+			if intStringLenmapkey < 0 {
 				return ErrInvalidLengthAgent
 			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
+			postStringIndexmapkey := iNdEx + intStringLenmapkey
+			if postStringIndexmapkey > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.SystemInfo = append(m.SystemInfo[:0], dAtA[iNdEx:postIndex]...)
-			if m.SystemInfo == nil {
-				m.SystemInfo = []byte{}
+			mapkey := string(dAtA[iNdEx:postStringIndexmapkey])
+			iNdEx = postStringIndexmapkey
+			if m.Fields == nil {
+				m.Fields = make(map[string]string)
+			}
+			if iNdEx < postIndex {
+				var valuekey uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowAgent
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					valuekey |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				var stringLenmapvalue uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowAgent
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					stringLenmapvalue |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				intStringLenmapvalue := int(stringLenmapvalue)
+				if intStringLenmapvalue < 0 {
+					return ErrInvalidLengthAgent
+				}
+				postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+				if postStringIndexmapvalue > l {
+					return io.ErrUnexpectedEOF
+				}
+				mapvalue := string(dAtA[iNdEx:postStringIndexmapvalue])
+				iNdEx = postStringIndexmapvalue
+				m.Fields[mapkey] = mapvalue
+			} else {
+				var mapvalue string
+				m.Fields[mapkey] = mapvalue
 			}
 			iNdEx = postIndex
 		default:
@@ -2788,7 +3660,7 @@ func (m *PeerJoinRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PeerLeaveRequest) Unmarshal(dAtA []byte) error {
+func (m *PeerJoinRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2811,10 +3683,10 @@ func (m *PeerLeaveRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PeerLeaveRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: PeerJoinRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PeerLeaveRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PeerJoinRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -2931,6 +3803,645 @@ func (m *PeerLeaveRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *PeerLeaveRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAgent
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PeerLeaveRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PeerLeaveRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Addr", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Addr = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Config", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Config == nil {
+				m.Config = &RuntimeConfig{}
+			}
+			if err := m.Config.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SystemInfo", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SystemInfo = append(m.SystemInfo[:0], dAtA[iNdEx:postIndex]...)
+			if m.SystemInfo == nil {
+				m.SystemInfo = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAgent(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthAgent
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FileInfo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAgent
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FileInfo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FileInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Path = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Size_", wireType)
+			}
+			m.Size_ = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Size_ |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mode", wireType)
+			}
+			m.Mode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Mode |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Checksum", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Checksum = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exists", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Exists = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAgent(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthAgent
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FileChunk) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAgent
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FileChunk: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FileChunk: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Info", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Info == nil {
+				m.Info = &FileInfo{}
+			}
+			if err := m.Info.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAgent(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthAgent
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetFileRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAgent
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetFileRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetFileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Path = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAgent(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthAgent
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StatFileRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAgent
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StatFileRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StatFileRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Path = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Partial", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Partial = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAgent(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthAgent
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipAgent(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -3039,54 +4550,69 @@ var (
 func init() { proto1.RegisterFile("agent.proto", fileDescriptorAgent) }
 
 var fileDescriptorAgent = []byte{
-	// 773 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x54, 0xdd, 0x6e, 0xe3, 0x44,
-	0x14, 0xf6, 0x4f, 0x9d, 0xc6, 0xc7, 0xdd, 0xd6, 0x8c, 0x96, 0x62, 0xa5, 0xa8, 0x04, 0x6b, 0x2f,
-	0x22, 0x01, 0x2e, 0x64, 0x11, 0xb0, 0x2b, 0xb8, 0xd8, 0x4d, 0x52, 0x05, 0x54, 0xb4, 0x68, 0xba,
-	0x88, 0xcb, 0xc8, 0xb1, 0x8f, 0x4d, 0x84, 0xed, 0xc9, 0xda, 0xe3, 0xd0, 0xbc, 0x03, 0x0f, 0xb0,
-	0xef, 0xc0, 0x8b, 0x70, 0xc9, 0x23, 0xa0, 0x72, 0xcf, 0x33, 0xa0, 0x19, 0x8f, 0x1b, 0x6f, 0x77,
-	0x8b, 0xc4, 0xcd, 0x5e, 0xe5, 0xfc, 0x7d, 0xe7, 0x1c, 0x7f, 0x5f, 0xe6, 0x80, 0x13, 0xa6, 0x58,
-	0xf0, 0x60, 0x5d, 0x32, 0xce, 0x88, 0x25, 0x7f, 0x06, 0x27, 0x29, 0x63, 0x69, 0x86, 0x67, 0xd2,
-	0x5b, 0xd6, 0xc9, 0x19, 0xe6, 0x6b, 0xbe, 0x6d, 0x6a, 0x06, 0x47, 0xf1, 0xaa, 0x8a, 0xd8, 0x06,
-	0x4b, 0x15, 0xf0, 0x7f, 0xd7, 0xc1, 0x99, 0xb0, 0x3c, 0x0f, 0x8b, 0xf8, 0x49, 0x99, 0x56, 0x84,
-	0xc0, 0x5e, 0x58, 0xa6, 0x95, 0xa7, 0x0f, 0xcd, 0x91, 0x4d, 0xa5, 0x4d, 0x3e, 0x84, 0x83, 0x0a,
-	0xb3, 0x64, 0x11, 0x35, 0x75, 0x9e, 0x31, 0xd4, 0x47, 0x7d, 0xea, 0x88, 0x98, 0x82, 0x92, 0x4f,
-	0xc0, 0xc4, 0x62, 0xe3, 0x99, 0x43, 0x73, 0xe4, 0x8c, 0x4f, 0x9a, 0xde, 0x41, 0xa7, 0x6f, 0x30,
-	0x2b, 0x36, 0xb3, 0x82, 0x97, 0x5b, 0x2a, 0xea, 0x06, 0x5f, 0x40, 0xbf, 0x0d, 0x10, 0x17, 0xcc,
-	0x5f, 0x70, 0xeb, 0xe9, 0x43, 0x7d, 0x64, 0x53, 0x61, 0x92, 0xfb, 0x60, 0x6d, 0xc2, 0xac, 0x46,
-	0x39, 0xc8, 0xa6, 0x8d, 0xf3, 0xd8, 0xf8, 0x4a, 0xf7, 0x5f, 0x1a, 0xb0, 0xff, 0x3d, 0x56, 0x55,
-	0x98, 0x22, 0xf9, 0x12, 0x0e, 0xf0, 0x0a, 0xa3, 0x45, 0xc5, 0xc3, 0x92, 0x63, 0x2c, 0x1b, 0x38,
-	0x63, 0xa2, 0x66, 0xcf, 0xae, 0x30, 0xba, 0x6c, 0x32, 0x73, 0x8d, 0x3a, 0xb8, 0x73, 0xc9, 0x37,
-	0x70, 0x28, 0x81, 0x11, 0xcb, 0xd7, 0x19, 0x0a, 0xa8, 0x21, 0xa1, 0xf7, 0x3b, 0xd0, 0x49, 0x9b,
-	0x9b, 0x6b, 0xf4, 0x1e, 0x76, 0x03, 0xe4, 0x73, 0x90, 0xdd, 0x16, 0xac, 0xe6, 0xeb, 0x9a, 0x7b,
-	0xa6, 0xc4, 0xbe, 0xd3, 0xc1, 0x3e, 0x93, 0x89, 0xb9, 0x46, 0x01, 0x6f, 0x3c, 0x12, 0x80, 0x9d,
-	0xb1, 0x74, 0x81, 0xe2, 0x93, 0xbd, 0x3d, 0x89, 0x39, 0x52, 0x98, 0x0b, 0x96, 0x4a, 0x26, 0xe6,
-	0x1a, 0xed, 0x67, 0xca, 0x26, 0x0f, 0xc0, 0xc2, 0xb2, 0x64, 0xa5, 0x67, 0xc9, 0xda, 0x83, 0xb6,
-	0xbf, 0x88, 0xcd, 0x35, 0xda, 0x24, 0x9f, 0xda, 0xb0, 0x8f, 0x19, 0xe6, 0x58, 0x70, 0x7f, 0x06,
-	0x4e, 0xe7, 0x9b, 0x05, 0xab, 0x15, 0xbe, 0x90, 0xa4, 0x58, 0x54, 0x98, 0x37, 0xca, 0x1a, 0x1d,
-	0x65, 0xdd, 0x9d, 0x6c, 0xb6, 0x54, 0xc6, 0x5f, 0xc2, 0xbd, 0x57, 0xbe, 0xff, 0x0d, 0x8d, 0x4e,
-	0xc0, 0xc6, 0xab, 0x15, 0x5f, 0x44, 0x2c, 0x6e, 0x24, 0xb2, 0x68, 0x5f, 0x04, 0x26, 0x2c, 0x46,
-	0xe2, 0xb7, 0x7b, 0x9b, 0xaf, 0xef, 0xad, 0xb6, 0xf6, 0x1f, 0x81, 0x25, 0x7d, 0xe2, 0xc1, 0x7e,
-	0xde, 0xa8, 0xa9, 0xe4, 0x6f, 0x5d, 0x72, 0x0c, 0x3d, 0x5e, 0x86, 0x11, 0xb6, 0xeb, 0x2a, 0xcf,
-	0xdf, 0x00, 0xec, 0x28, 0x7e, 0xc3, 0x6e, 0x0f, 0xc0, 0x48, 0x1a, 0x3d, 0x0f, 0x5f, 0xd1, 0xb3,
-	0x01, 0x04, 0xe7, 0x53, 0x6a, 0x24, 0xb1, 0xa0, 0x22, 0x0e, 0x79, 0x28, 0x77, 0x3c, 0xa0, 0xd2,
-	0xf6, 0xdf, 0x07, 0xe3, 0x7c, 0x4a, 0x00, 0x7a, 0x97, 0xcf, 0xa7, 0xcf, 0x7e, 0x7c, 0xee, 0x6a,
-	0xca, 0x9e, 0x51, 0xea, 0xea, 0xfe, 0x6f, 0x06, 0xf4, 0x5b, 0x9d, 0xfe, 0x63, 0xed, 0x87, 0xd0,
-	0x4b, 0x56, 0x98, 0xc5, 0xcd, 0xda, 0xbb, 0x97, 0xd0, 0x42, 0x83, 0x73, 0x99, 0x6d, 0x5e, 0x82,
-	0x2a, 0x25, 0x1f, 0x81, 0x95, 0xe1, 0x06, 0x33, 0xb9, 0xce, 0xe1, 0xf8, 0xdd, 0xdb, 0x98, 0x0b,
-	0x91, 0xa4, 0x4d, 0x4d, 0x87, 0x98, 0xbd, 0x2e, 0x31, 0x83, 0x47, 0xe0, 0x74, 0x7a, 0xff, 0xaf,
-	0x47, 0xf5, 0x19, 0x58, 0x72, 0x04, 0xb1, 0xc1, 0x9a, 0xe2, 0xb2, 0x4e, 0x5d, 0x8d, 0xf4, 0x61,
-	0xef, 0xdb, 0x22, 0x61, 0xae, 0x2e, 0xac, 0x9f, 0xc2, 0xb2, 0x70, 0x0d, 0x91, 0x96, 0xb2, 0xb9,
-	0xa6, 0xcf, 0xe1, 0xe8, 0x07, 0xc4, 0xf2, 0x3b, 0xb6, 0x2a, 0x28, 0xbe, 0xa8, 0xb1, 0xe2, 0xf2,
-	0xef, 0x15, 0xc7, 0xa5, 0x1a, 0x29, 0x6d, 0xf2, 0x31, 0xf4, 0x22, 0x56, 0x24, 0xab, 0xf4, 0xd6,
-	0x0b, 0xa3, 0x75, 0xc1, 0x57, 0x39, 0x4e, 0x64, 0x8e, 0xaa, 0x1a, 0xf2, 0x01, 0x38, 0xd5, 0xb6,
-	0xe2, 0x98, 0x2f, 0x56, 0x45, 0xc2, 0x94, 0x38, 0xd0, 0x84, 0xc4, 0x32, 0x7e, 0x0d, 0xae, 0x98,
-	0x7a, 0x81, 0xe1, 0x06, 0xdf, 0xde, 0xd8, 0xf1, 0x3f, 0x3a, 0x58, 0x4f, 0xc4, 0x9d, 0x25, 0x8f,
-	0xa1, 0x7f, 0xf9, 0x73, 0xcd, 0x63, 0xf6, 0x6b, 0x41, 0x8e, 0x83, 0xe6, 0xce, 0x06, 0xed, 0x9d,
-	0x0d, 0x66, 0xe2, 0xce, 0x0e, 0xee, 0x88, 0x93, 0x33, 0xd8, 0x6f, 0x8f, 0x25, 0x79, 0xfd, 0x3e,
-	0x0e, 0x0e, 0x55, 0x4c, 0x5d, 0xb7, 0x4f, 0x75, 0x31, 0xac, 0xe5, 0x98, 0x1c, 0xab, 0xec, 0x2d,
-	0xd2, 0xef, 0x1c, 0xf6, 0x35, 0xd8, 0x37, 0x4c, 0x91, 0xf7, 0x3a, 0xe0, 0x2e, 0x77, 0x77, 0xa1,
-	0x9f, 0xba, 0x7f, 0x5c, 0x9f, 0xea, 0x7f, 0x5e, 0x9f, 0xea, 0x7f, 0x5d, 0x9f, 0xea, 0x2f, 0xff,
-	0x3e, 0xd5, 0x96, 0x3d, 0x59, 0xf1, 0xf0, 0xdf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x8d, 0x75, 0x5e,
-	0x80, 0x70, 0x06, 0x00, 0x00,
+	// 1011 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x55, 0xdd, 0x6e, 0xe3, 0x44,
+	0x14, 0x8e, 0xe3, 0x38, 0xb1, 0x4f, 0xfa, 0x63, 0x46, 0x4b, 0x89, 0x52, 0x54, 0x8a, 0xd9, 0x8b,
+	0x4a, 0xb0, 0xe9, 0xd2, 0xae, 0x80, 0x5d, 0x2d, 0x42, 0xbb, 0x6d, 0x4a, 0x41, 0x45, 0x5b, 0x4d,
+	0x17, 0x71, 0x83, 0x14, 0xb9, 0xf1, 0xb1, 0x6b, 0xd5, 0xf6, 0x64, 0xed, 0x71, 0x68, 0xf7, 0x0d,
+	0x90, 0x78, 0x00, 0xde, 0x81, 0x07, 0x81, 0x4b, 0x1e, 0x01, 0x95, 0x47, 0xe0, 0x05, 0xd0, 0x8c,
+	0x67, 0x52, 0xa7, 0x4d, 0xf9, 0x11, 0x12, 0x57, 0x3e, 0xe7, 0xcc, 0xf9, 0x9b, 0xef, 0x3b, 0xe3,
+	0x03, 0x5d, 0x3f, 0xc2, 0x8c, 0x0f, 0x26, 0x39, 0xe3, 0x8c, 0x58, 0xf2, 0xd3, 0x5f, 0x8f, 0x18,
+	0x8b, 0x12, 0xdc, 0x96, 0xda, 0x69, 0x19, 0x6e, 0x63, 0x3a, 0xe1, 0x97, 0x95, 0x4f, 0x7f, 0x35,
+	0x88, 0x8b, 0x31, 0x9b, 0x62, 0xae, 0x0c, 0xde, 0x4f, 0x06, 0x74, 0xf7, 0x58, 0x9a, 0xfa, 0x59,
+	0xf0, 0x2c, 0x8f, 0x0a, 0x42, 0xa0, 0xe5, 0xe7, 0x51, 0xd1, 0x33, 0x36, 0xcd, 0x2d, 0x87, 0x4a,
+	0x99, 0xbc, 0x0b, 0x4b, 0x05, 0x26, 0xe1, 0x68, 0x5c, 0xf9, 0xf5, 0x9a, 0x9b, 0xc6, 0x96, 0x4d,
+	0xbb, 0xc2, 0xa6, 0x42, 0xc9, 0x03, 0x30, 0x31, 0x9b, 0xf6, 0xcc, 0x4d, 0x73, 0xab, 0xbb, 0xb3,
+	0x5e, 0xe5, 0x1e, 0xd4, 0xf2, 0x0e, 0x86, 0xd9, 0x74, 0x98, 0xf1, 0xfc, 0x92, 0x0a, 0xbf, 0xfe,
+	0x47, 0x60, 0x6b, 0x03, 0x71, 0xc1, 0x3c, 0xc7, 0xcb, 0x9e, 0xb1, 0x69, 0x6c, 0x39, 0x54, 0x88,
+	0xe4, 0x1e, 0x58, 0x53, 0x3f, 0x29, 0x51, 0x16, 0x72, 0x68, 0xa5, 0x3c, 0x69, 0x7e, 0x62, 0x78,
+	0x3f, 0x37, 0xa1, 0xf3, 0x15, 0x16, 0x85, 0x1f, 0x21, 0xf9, 0x18, 0x96, 0xf0, 0x02, 0xc7, 0xa3,
+	0x82, 0xfb, 0x39, 0xc7, 0x40, 0x26, 0xe8, 0xee, 0x10, 0x55, 0x7b, 0x78, 0x81, 0xe3, 0x93, 0xea,
+	0xe4, 0xb0, 0x41, 0xbb, 0x78, 0xad, 0x92, 0x4f, 0x61, 0x45, 0x06, 0x8e, 0x59, 0x3a, 0x49, 0x50,
+	0x84, 0x36, 0x65, 0xe8, 0xbd, 0x5a, 0xe8, 0x9e, 0x3e, 0x3b, 0x6c, 0xd0, 0x65, 0xac, 0x1b, 0xc8,
+	0x23, 0x90, 0xd9, 0x46, 0xac, 0xe4, 0x93, 0x92, 0xf7, 0x4c, 0x19, 0xfb, 0x46, 0x2d, 0xf6, 0x85,
+	0x3c, 0x38, 0x6c, 0x50, 0xc0, 0x99, 0x46, 0x06, 0xe0, 0x24, 0x2c, 0x1a, 0xa1, 0xb8, 0x72, 0xaf,
+	0x25, 0x63, 0x56, 0x55, 0xcc, 0x11, 0x8b, 0x24, 0x12, 0x87, 0x0d, 0x6a, 0x27, 0x4a, 0x26, 0xf7,
+	0xc1, 0xc2, 0x3c, 0x67, 0x79, 0xcf, 0x92, 0xbe, 0x4b, 0x3a, 0xbf, 0xb0, 0x1d, 0x36, 0x68, 0x75,
+	0x48, 0x1e, 0x80, 0x3d, 0xc9, 0x59, 0x94, 0x63, 0x51, 0xf4, 0xda, 0x73, 0x49, 0x8f, 0x95, 0x59,
+	0x24, 0xd5, 0x2e, 0xcf, 0x1d, 0xe8, 0x60, 0x82, 0x29, 0x66, 0xdc, 0x1b, 0x42, 0xb7, 0x06, 0x91,
+	0x20, 0xa1, 0xc0, 0x57, 0x12, 0x43, 0x8b, 0x0a, 0x71, 0x36, 0x08, 0xcd, 0xda, 0x20, 0xb8, 0xd7,
+	0x2c, 0x3b, 0x92, 0x48, 0xef, 0x14, 0x96, 0xe7, 0xe0, 0x5a, 0x90, 0x68, 0x1d, 0x1c, 0xbc, 0x88,
+	0xf9, 0x68, 0xcc, 0x82, 0x8a, 0x51, 0x8b, 0xda, 0xc2, 0xb0, 0xc7, 0x02, 0x24, 0x9e, 0xbe, 0xa6,
+	0x79, 0xfb, 0x9a, 0xea, 0x92, 0xde, 0x63, 0xb0, 0xa4, 0x4e, 0x7a, 0xd0, 0x49, 0x2b, 0xf2, 0xd5,
+	0xb4, 0x68, 0x95, 0xac, 0x41, 0x9b, 0xe7, 0xfe, 0x18, 0x75, 0xbb, 0x4a, 0xf3, 0xa6, 0x00, 0xd7,
+	0x8c, 0x2c, 0xe8, 0xed, 0x3e, 0x34, 0xc3, 0x8a, 0xfe, 0x95, 0x39, 0xfa, 0xab, 0x80, 0xc1, 0xc1,
+	0x3e, 0x6d, 0x86, 0x81, 0x80, 0x22, 0xf0, 0xb9, 0x2f, 0x7b, 0x5c, 0xa2, 0x52, 0xf6, 0xde, 0x86,
+	0xe6, 0xc1, 0x3e, 0x01, 0x68, 0x9f, 0xbc, 0xdc, 0x7f, 0xf1, 0xf5, 0x4b, 0xb7, 0xa1, 0xe4, 0x21,
+	0xa5, 0xae, 0xe1, 0xfd, 0xd0, 0x04, 0x5b, 0xd3, 0xfa, 0x17, 0x6d, 0xef, 0x42, 0x3b, 0x8c, 0x31,
+	0x09, 0xaa, 0xb6, 0xaf, 0x1f, 0x8e, 0x0e, 0x1d, 0x1c, 0xc8, 0x53, 0x29, 0x53, 0xe5, 0x4a, 0xde,
+	0x07, 0x2b, 0xc1, 0x29, 0x26, 0xb2, 0x9d, 0x95, 0x9d, 0x37, 0x6f, 0xc6, 0x1c, 0x89, 0x43, 0x5a,
+	0xf9, 0xd4, 0x80, 0x69, 0xd5, 0x81, 0xe9, 0x3f, 0x86, 0x6e, 0x2d, 0xf7, 0xbf, 0x7a, 0x83, 0x1f,
+	0x82, 0x25, 0x4b, 0x10, 0x07, 0xac, 0x7d, 0x3c, 0x2d, 0x23, 0xb7, 0x41, 0x6c, 0x68, 0x7d, 0x91,
+	0x85, 0xcc, 0x35, 0x84, 0xf4, 0x8d, 0x9f, 0x67, 0x6e, 0x93, 0x38, 0x8a, 0x36, 0xd7, 0xf4, 0xfe,
+	0x30, 0xc0, 0xd6, 0x03, 0xb9, 0x80, 0x85, 0x0d, 0x00, 0xf5, 0x16, 0x63, 0x96, 0xa9, 0x11, 0xa9,
+	0x59, 0xea, 0x00, 0x9a, 0xf3, 0x00, 0xce, 0xb0, 0x68, 0xfd, 0x03, 0x2c, 0xae, 0xd1, 0xb6, 0xe6,
+	0xd0, 0xd6, 0x9d, 0x2d, 0x42, 0xfb, 0xbf, 0x00, 0xc5, 0x61, 0xf5, 0x18, 0x31, 0xff, 0x92, 0xc5,
+	0x19, 0xc5, 0x57, 0x25, 0x16, 0x5c, 0x3e, 0xaa, 0x20, 0xc8, 0x55, 0xbc, 0x94, 0xc9, 0x07, 0xd0,
+	0x1e, 0xb3, 0x2c, 0x8c, 0xa3, 0x1b, 0xbf, 0x21, 0x5a, 0x66, 0x3c, 0x4e, 0x71, 0x4f, 0x9e, 0x51,
+	0xe5, 0x43, 0xde, 0x81, 0x6e, 0x71, 0x59, 0x70, 0x4c, 0x47, 0x71, 0x16, 0x32, 0x35, 0x92, 0x50,
+	0x99, 0x04, 0x05, 0x5e, 0x09, 0xae, 0xa8, 0x7a, 0x84, 0xfe, 0x14, 0xff, 0xc7, 0xb2, 0xaf, 0xc1,
+	0x3e, 0x88, 0x13, 0x14, 0xb2, 0x28, 0x37, 0xf1, 0xf9, 0x99, 0x2e, 0x27, 0x64, 0x61, 0x2b, 0xe2,
+	0xd7, 0x15, 0x4a, 0x26, 0x95, 0xb2, 0xb0, 0xa5, 0xe2, 0xa7, 0x20, 0xb2, 0x2d, 0x53, 0x29, 0x93,
+	0x3e, 0xd8, 0xe3, 0x33, 0x1c, 0x9f, 0x17, 0x65, 0x2a, 0x49, 0x75, 0xe8, 0x4c, 0x17, 0xc3, 0x8c,
+	0x17, 0x71, 0xc1, 0x0b, 0xf9, 0x53, 0xb4, 0xa9, 0xd2, 0xbc, 0x6f, 0xc1, 0x11, 0xb5, 0xf7, 0xce,
+	0xca, 0xec, 0x9c, 0xbc, 0x07, 0x2d, 0xd9, 0xa2, 0x31, 0xf7, 0x3b, 0xd4, 0xbd, 0x51, 0x79, 0x28,
+	0x32, 0xb1, 0x30, 0x2c, 0x90, 0xab, 0x7e, 0x94, 0xb6, 0xf0, 0xa5, 0x3f, 0x85, 0x95, 0xcf, 0x91,
+	0x8b, 0x04, 0x35, 0x38, 0x6f, 0xdd, 0xef, 0x8e, 0x8c, 0xde, 0x67, 0xb0, 0x7a, 0xc2, 0xfd, 0xbf,
+	0x0d, 0xef, 0x41, 0x67, 0xe2, 0xe7, 0x3c, 0xf6, 0x13, 0xb5, 0x5d, 0xb5, 0xba, 0xf3, 0xbd, 0x09,
+	0xd6, 0x33, 0xb1, 0xe5, 0xc9, 0x13, 0xb0, 0x4f, 0xce, 0x4a, 0x1e, 0xb0, 0xef, 0x32, 0xb2, 0x36,
+	0xa8, 0xb6, 0xfc, 0x40, 0x6f, 0xf9, 0xc1, 0x50, 0x6c, 0xf9, 0xfe, 0x1d, 0x76, 0xb2, 0x0d, 0x1d,
+	0xbd, 0xaa, 0xc9, 0xed, 0xed, 0xdc, 0x5f, 0x51, 0x36, 0xb5, 0x5b, 0x1f, 0x1a, 0xa2, 0x98, 0x1e,
+	0x5e, 0xb2, 0xa6, 0x1f, 0xca, 0xfc, 0x34, 0xdf, 0x59, 0xec, 0x29, 0x38, 0xb3, 0x11, 0x24, 0x6f,
+	0xd5, 0x82, 0xeb, 0x43, 0x79, 0x67, 0xf4, 0x00, 0x3a, 0xc7, 0xa5, 0x04, 0x8c, 0xb8, 0x35, 0xf6,
+	0x24, 0xbb, 0xfd, 0x9b, 0x7c, 0x6e, 0x19, 0xe4, 0x11, 0x74, 0x14, 0x3f, 0x44, 0xbf, 0xff, 0x79,
+	0xbe, 0xfa, 0xb7, 0xd2, 0x3c, 0x34, 0xc8, 0x2e, 0xd8, 0x9a, 0x97, 0xd9, 0xfd, 0x6e, 0x10, 0x75,
+	0xab, 0xd8, 0x73, 0xf7, 0x97, 0xab, 0x0d, 0xe3, 0xd7, 0xab, 0x0d, 0xe3, 0xb7, 0xab, 0x0d, 0xe3,
+	0xc7, 0xdf, 0x37, 0x1a, 0xa7, 0x6d, 0xe9, 0xb1, 0xfb, 0xe7, 0x00, 0xd2, 0xa7, 0x2f, 0xa4, 0x89,
+	0x09, 0x00, 0x00,
 }
@@ -0,0 +1,211 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proto
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+
+	"github.com/gravitational/gravity/lib/defaults"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+)
+
+// PartialPath returns the path of the incomplete transfer of the file at path
+func PartialPath(path string) string {
+	return path + PartialSuffix
+}
+
+// FileChecksum computes the hex-encoded SHA256 checksum of the first size bytes
+// of the file at path
+func FileChecksum(path string, size int64) (checksum string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", trace.ConvertSystemError(err)
+	}
+	defer f.Close()
+	hash := sha256.New()
+	n, err := io.CopyN(hash, f, size)
+	if err != nil && err != io.EOF {
+		return "", trace.ConvertSystemError(err)
+	}
+	if n != size {
+		return "", trace.BadParameter("file %v is shorter than %v bytes", path, size)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// StatFile returns information about the file at path including
+// the checksum of its contents.
+// If the file does not exist, the returned info is marked as such
+func StatFile(path string) (*FileInfo, error) {
+	info, err := DescribeFile(path)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if !info.Exists {
+		return info, nil
+	}
+	info.Checksum, err = FileChecksum(path, info.Size_)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return info, nil
+}
+
+// DescribeFile returns information about the file at path
+// without computing the checksum of its contents.
+// If the file does not exist, the returned info is marked as such
+func DescribeFile(path string) (*FileInfo, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &FileInfo{Path: path}, nil
+		}
+		return nil, trace.ConvertSystemError(err)
+	}
+	if !fi.Mode().IsRegular() {
+		return nil, trace.BadParameter("%v is not a regular file", path)
+	}
+	return &FileInfo{
+		Path:   path,
+		Size_:  fi.Size(),
+		Mode:   uint32(fi.Mode().Perm()),
+		Exists: true,
+	}, nil
+}
+
+// SendFile streams the contents of the file f starting at offset using the specified send function.
+// The first chunk carries the file information and the offset.
+// The checksum of the file contents is computed while the file is sent and
+// is carried with the file information in the last chunk of the stream.
+// Returns the checksum
+func SendFile(f io.ReadSeeker, info FileInfo, offset int64, send func(*FileChunk) error) (checksum string, err error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", trace.ConvertSystemError(err)
+	}
+	// Only the part of the file that has already been transferred
+	// is read up front
+	hash := sha256.New()
+	if _, err := io.CopyN(hash, f, offset); err != nil {
+		return "", trace.ConvertSystemError(err)
+	}
+	buf := make([]byte, defaults.RPCAgentFileChunkSize)
+	first := info
+	first.Checksum = ""
+	chunk := &FileChunk{Info: &first, Offset: offset}
+	for {
+		n, err := f.Read(buf)
+		if err != nil && err != io.EOF {
+			return "", trace.ConvertSystemError(err)
+		}
+		if n > 0 || chunk.Info != nil {
+			hash.Write(buf[:n])
+			chunk.Data = buf[:n]
+			if errSend := send(chunk); errSend != nil {
+				return "", trace.Wrap(errSend)
+			}
+			chunk = &FileChunk{}
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	info.Checksum = hex.EncodeToString(hash.Sum(nil))
+	if err := send(&FileChunk{Info: &info}); err != nil {
+		return "", trace.Wrap(err)
+	}
+	return info.Checksum, nil
+}
+
+// ReceiveFile receives the file stream into the partial file for the specified path
+// and moves it into place once the checksum of the received contents has been
+// verified. The partial file is removed if the checksum does not match.
+// chunk is the first chunk of the stream that specifies the offset to write at.
+// The remaining chunks are received with the specified recv function.
+// Returns the information about the received file
+func ReceiveFile(path string, chunk *FileChunk, recv func() (*FileChunk, error)) (*FileInfo, error) {
+	partialPath := PartialPath(path)
+	f, err := os.OpenFile(partialPath, os.O_RDWR|os.O_CREATE, defaults.PrivateFileMask)
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	info, checksum, err := receiveFile(f, chunk, recv)
+	if errClose := f.Close(); errClose != nil && err == nil {
+		err = trace.ConvertSystemError(errClose)
+	}
+	if err == nil && checksum != info.Checksum {
+		err = trace.CompareFailed("checksum mismatch for %v: expected %v but got %v",
+			path, info.Checksum, checksum)
+	}
+	if err != nil {
+		if trace.IsCompareFailed(err) {
+			if errRemove := os.Remove(partialPath); errRemove != nil && !os.IsNotExist(errRemove) {
+				log.WithError(errRemove).Warnf("Failed to remove %v.", partialPath)
+			}
+		}
+		return nil, trace.Wrap(err)
+	}
+	info.Path = path
+	info.Exists = true
+	if err := os.Chmod(partialPath, os.FileMode(info.Mode).Perm()); err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	if err := os.Rename(partialPath, path); err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	return info, nil
+}
+
+// receiveFile writes the contents of the file stream into f.
+// Returns the file information from the last chunk of the stream
+// and the checksum of the resulting file contents
+func receiveFile(f *os.File, chunk *FileChunk, recv func() (*FileChunk, error)) (info *FileInfo, checksum string, err error) {
+	if err := f.Truncate(chunk.Offset); err != nil {
+		return nil, "", trace.ConvertSystemError(err)
+	}
+	hash := sha256.New()
+	if _, err := io.CopyN(hash, f, chunk.Offset); err != nil {
+		return nil, "", trace.ConvertSystemError(err)
+	}
+	for {
+		if chunk.Info != nil {
+			info = chunk.Info
+		}
+		if _, err := f.Write(chunk.Data); err != nil {
+			return nil, "", trace.ConvertSystemError(err)
+		}
+		hash.Write(chunk.Data)
+		chunk, err = recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, "", trace.Wrap(err)
+		}
+	}
+	if info.Checksum == "" {
+		return nil, "", trace.BadParameter("file stream did not specify the checksum")
+	}
+	return info, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// PartialSuffix is appended to the name of a file while it is being transferred
+const PartialSuffix = ".partial"
@@ -153,9 +153,10 @@ func (srv *agentServer) command(req pb.CommandArgs, stream pb.Agent_CommandServe
 		err = trace.BadParameter("panic for command %+v: %v", req, r)
 	}()
 
-	err = srv.commandExecutor.exec(stream.Context(), stream, req.Args, makeRemoteLogger(stream, srv.FieldLogger))
+	out := &syncStream{OutgoingMessageStream: stream}
+	err = srv.commandExecutor.exec(stream.Context(), out, req.Args, makeRemoteLogger(out, srv.FieldLogger))
 	if err != nil {
-		stream.Send(pb.ErrorToMessage(err))
+		out.Send(pb.ErrorToMessage(err))
 		log.WithError(err).Error("command returned error")
 	} else {
 		log.Debug("completed OK")
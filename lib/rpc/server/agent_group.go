@@ -240,6 +240,18 @@ func (r errorPeer) GravityCommand(context.Context, log.FieldLogger, io.Writer, .
 	return trace.Wrap(r.error)
 }
 
+func (r errorPeer) PutFile(context.Context, log.FieldLogger, string, string) error {
+	return trace.Wrap(r.error)
+}
+
+func (r errorPeer) GetFile(context.Context, log.FieldLogger, string, string) error {
+	return trace.Wrap(r.error)
+}
+
+func (r errorPeer) StatFile(context.Context, string) (*pb.FileInfo, error) {
+	return nil, trace.Wrap(r.error)
+}
+
 func (r errorPeer) Validate(context.Context, *validationpb.ValidateRequest) ([]*agentpb.Probe, error) {
 	return nil, trace.Wrap(r.error)
 }
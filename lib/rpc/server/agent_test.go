@@ -18,11 +18,15 @@ package server
 
 import (
 	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/gravitational/gravity/lib/compare"
+	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/rpc/client"
 	pb "github.com/gravitational/gravity/lib/rpc/proto"
 	"github.com/gravitational/gravity/lib/storage"
@@ -68,6 +72,105 @@ func (r *S) TestClientExecutesCommandsRemotely(c *C) {
 	r.clientExecutesCommandsWithClient(c, clt, srv, cmd.output)
 }
 
+func (r *S) TestCommandReportsProgress(c *C) {
+	creds := TestCredentials(c)
+	log := r.WithField("test", "CommandReportsProgress")
+	listener := listen(c)
+	srv, err := New(Config{
+		Listener:        listener,
+		Credentials:     creds,
+		commandExecutor: execFunc(osExec),
+	}, log.WithField("server", listener.Addr()))
+	c.Assert(err, IsNil)
+	go srv.Serve()
+	defer withTestCtx(srv.Stop)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+	defer cancel()
+	clt, err := client.New(ctx,
+		client.Config{
+			ServerAddr:  srv.Addr().String(),
+			Credentials: creds.Client,
+		})
+	c.Assert(err, IsNil)
+	defer clt.Close()
+
+	script := `echo output; echo '{"completion":50,"message":"halfway","level":2,"fields":{"node":"node-1"}}' >&${GRAVITY_PROGRESS_FD}`
+	var out progressBuffer
+	err = clt.Command(ctx, log, &out, "sh", "-c", script)
+	c.Assert(err, IsNil)
+	c.Assert(out.String(), Equals, "output\n")
+	c.Assert(out.progress, DeepEquals, []pb.Progress{{
+		Seq:        1,
+		Completion: 50,
+		Message:    "halfway",
+		Level:      pb.LogEntry_Warn,
+		Fields:     map[string]string{"node": "node-1"},
+	}})
+}
+
+func (r *S) TestTransfersFiles(c *C) {
+	creds := TestCredentials(c)
+	log := r.WithField("test", "TransfersFiles")
+	listener := listen(c)
+	srv, err := New(Config{
+		Listener:    listener,
+		Credentials: creds,
+	}, log.WithField("server", listener.Addr()))
+	c.Assert(err, IsNil)
+	go srv.Serve()
+	defer withTestCtx(srv.Stop)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+	defer cancel()
+	clt, err := client.New(ctx,
+		client.Config{
+			ServerAddr:  srv.Addr().String(),
+			Credentials: creds.Client,
+		})
+	c.Assert(err, IsNil)
+	defer clt.Close()
+
+	dir := c.MkDir()
+	data := bytes.Repeat([]byte("0123456789"), defaults.RPCAgentFileChunkSize/4)
+	src := filepath.Join(dir, "src")
+	c.Assert(ioutil.WriteFile(src, data, 0640), IsNil)
+
+	comment := Commentf("resumes interrupted upload")
+	uploaded := filepath.Join(dir, "uploaded")
+	c.Assert(ioutil.WriteFile(pb.PartialPath(uploaded), data[:1000], 0600), IsNil)
+	c.Assert(clt.PutFile(ctx, log, src, uploaded), IsNil, comment)
+	assertFile(c, uploaded, data, 0640, comment)
+
+	comment = Commentf("restarts upload with mismatched partial file")
+	c.Assert(ioutil.WriteFile(pb.PartialPath(uploaded), []byte("invalid"), 0600), IsNil)
+	c.Assert(clt.PutFile(ctx, log, src, uploaded), IsNil, comment)
+	assertFile(c, uploaded, data, 0640, comment)
+
+	comment = Commentf("resumes interrupted download")
+	downloaded := filepath.Join(dir, "downloaded")
+	c.Assert(ioutil.WriteFile(pb.PartialPath(downloaded), data[:5000], 0600), IsNil)
+	c.Assert(clt.GetFile(ctx, log, src, downloaded), IsNil, comment)
+	assertFile(c, downloaded, data, 0640, comment)
+
+	comment = Commentf("discards corrupted download")
+	downloaded = filepath.Join(dir, "corrupted")
+	c.Assert(ioutil.WriteFile(pb.PartialPath(downloaded), []byte("invalid"), 0600), IsNil)
+	err = clt.GetFile(ctx, log, src, downloaded)
+	c.Assert(trace.IsCompareFailed(err), Equals, true, Commentf("expected checksum mismatch but got %v", err))
+	_, err = os.Stat(pb.PartialPath(downloaded))
+	c.Assert(os.IsNotExist(err), Equals, true, comment)
+	c.Assert(clt.GetFile(ctx, log, src, downloaded), IsNil, comment)
+	assertFile(c, downloaded, data, 0640, comment)
+
+	info, err := clt.StatFile(ctx, filepath.Join(dir, "missing"))
+	c.Assert(err, IsNil)
+	c.Assert(info.Exists, Equals, false)
+
+	err = clt.GetFile(ctx, log, "src", filepath.Join(dir, "relative"))
+	c.Assert(err, ErrorMatches, ".*expected absolute path.*")
+}
+
 func (r *S) TestAgentsConnectToController(c *C) {
 	creds := TestCredentials(c)
 	store := newPeerStore()
@@ -330,6 +433,31 @@ func (r *S) newPeer(c *C, config PeerConfig, serverAddr string, log log.FieldLog
 	)
 }
 
+// assertFile verifies that the file at path has the specified contents
+// and permissions and that no partial file has been left behind
+func assertFile(c *C, path string, data []byte, mode os.FileMode, comment CommentInterface) {
+	contents, err := ioutil.ReadFile(path)
+	c.Assert(err, IsNil, comment)
+	c.Assert(bytes.Equal(contents, data), Equals, true, comment)
+	fi, err := os.Stat(path)
+	c.Assert(err, IsNil, comment)
+	c.Assert(fi.Mode().Perm(), Equals, mode, comment)
+	_, err = os.Stat(pb.PartialPath(path))
+	c.Assert(os.IsNotExist(err), Equals, true, comment)
+}
+
+// WriteProgress records the specified progress update.
+// Implements client.ProgressWriter
+func (r *progressBuffer) WriteProgress(progress *pb.Progress) error {
+	r.progress = append(r.progress, *progress)
+	return nil
+}
+
+type progressBuffer struct {
+	bytes.Buffer
+	progress []pb.Progress
+}
+
 func (r rejectingStore) NewPeer(ctx context.Context, req pb.PeerJoinRequest, peer Peer) error {
 	return trace.AccessDenied("peer not authorized")
 }
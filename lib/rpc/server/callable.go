@@ -17,10 +17,17 @@ limitations under the License.
 package server
 
 import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
 	"os/exec"
+	"sync"
 	"sync/atomic"
 	"syscall"
+	"time"
 
+	"github.com/gravitational/gravity/lib/constants"
 	pb "github.com/gravitational/gravity/lib/rpc/proto"
 
 	"github.com/gravitational/trace"
@@ -31,6 +38,13 @@ import (
 const (
 	// ExitCodeUndefined specifies the value of the exit code when the real exit code is unknown
 	ExitCodeUndefined = -1
+
+	// progressFD is the file descriptor the command can report structured progress to
+	progressFD = 3
+
+	// progressDrainTimeout specifies the maximum amount of time to wait for the
+	// remaining progress updates after the command has exited
+	progressDrainTimeout = 5 * time.Second
 )
 
 func osExec(ctx context.Context, stream pb.OutgoingMessageStream, args []string, log log.FieldLogger) error {
@@ -46,7 +60,16 @@ func (c *osCommand) exec(ctx context.Context, stream pb.OutgoingMessageStream, a
 	cmd.Stdout = &streamWriter{stream, pb.ExecOutput_STDOUT, seq}
 	cmd.Stderr = &streamWriter{stream, pb.ExecOutput_STDERR, seq}
 
-	err := cmd.Start()
+	progress, err := newProgressReader(stream, seq, log)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer progress.Close()
+	cmd.ExtraFiles = []*os.File{progress.w}
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%v=%v", constants.ProgressFDEnvVar, progressFD))
+
+	err = cmd.Start()
+	progress.closeWriter()
 	if err != nil {
 		return trace.Wrap(err, "failed to start %v", cmd.Path)
 	}
@@ -56,6 +79,7 @@ func (c *osCommand) exec(ctx context.Context, stream pb.OutgoingMessageStream, a
 		Seq:  seq,
 	}}})
 	err = cmd.Wait()
+	progress.wait(ctx)
 	if err == nil {
 		err = stream.Send(&pb.Message{&pb.Message_ExecCompleted{&pb.ExecCompleted{Seq: seq}}})
 		return trace.Wrap(err)
@@ -105,6 +129,88 @@ func (s *streamWriter) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
+// newProgressReader returns a new reader that forwards the structured progress
+// reported by the command to the specified stream
+func newProgressReader(stream pb.OutgoingMessageStream, seq int32, log log.FieldLogger) (*progressReader, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	reader := &progressReader{
+		r:    r,
+		w:    w,
+		done: make(chan struct{}),
+	}
+	go reader.forward(stream, seq, log)
+	return reader, nil
+}
+
+// forward decodes progress updates written by the command
+// and sends them to the stream
+func (r *progressReader) forward(stream pb.OutgoingMessageStream, seq int32, log log.FieldLogger) {
+	defer close(r.done)
+	scanner := bufio.NewScanner(r.r)
+	for scanner.Scan() {
+		var progress pb.Progress
+		if err := json.Unmarshal(scanner.Bytes(), &progress); err != nil {
+			log.WithError(err).Warn("Failed to decode progress.")
+			continue
+		}
+		progress.Seq = seq
+		err := stream.Send(&pb.Message{Element: &pb.Message_Progress{Progress: &progress}})
+		if err != nil {
+			log.WithError(err).Warn("Failed to send progress.")
+		}
+	}
+}
+
+// closeWriter closes this process's copy of the write end of the pipe
+// so that the reader can detect when the command exits
+func (r *progressReader) closeWriter() {
+	r.w.Close()
+}
+
+// wait blocks until all remaining progress updates have been forwarded.
+// The command might have started processes that keep the pipe open
+// so the wait is capped with a timeout
+func (r *progressReader) wait(ctx context.Context) {
+	select {
+	case <-r.done:
+	case <-time.After(progressDrainTimeout):
+	case <-ctx.Done():
+	}
+}
+
+// Close closes the reader
+func (r *progressReader) Close() error {
+	r.w.Close()
+	return r.r.Close()
+}
+
+// progressReader reads structured progress reported by a running command
+type progressReader struct {
+	// r is the read end of the progress pipe
+	r *os.File
+	// w is the write end of the progress pipe passed to the command
+	w *os.File
+	// done is closed when the reader has stopped forwarding progress
+	done chan struct{}
+}
+
+// syncStream serializes access to the underlying stream as messages
+// are sent from multiple goroutines during command execution
+type syncStream struct {
+	sync.Mutex
+	pb.OutgoingMessageStream
+}
+
+// Send sends the specified message to the stream
+func (s *syncStream) Send(msg *pb.Message) error {
+	s.Lock()
+	defer s.Unlock()
+	return s.OutgoingMessageStream.Send(msg)
+}
+
 func (r execFunc) exec(ctx context.Context, stream pb.OutgoingMessageStream, args []string, logger log.FieldLogger) error {
 	return r(ctx, stream, args, logger)
 }
@@ -0,0 +1,119 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"os"
+	"path/filepath"
+
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+// PutFile receives a file from the stream and stores it on this node.
+// The data is written into a partial file first which is renamed into
+// place once the checksum of the received contents has been verified.
+// The first chunk of the stream specifies the file and the offset to resume
+// the transfer from
+func (srv *agentServer) PutFile(stream pb.Agent_PutFileServer) error {
+	chunk, err := stream.Recv()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if chunk.Info == nil {
+		return trace.BadParameter("first chunk must describe the file")
+	}
+	info := *chunk.Info
+	if !filepath.IsAbs(info.Path) {
+		return trace.BadParameter("expected absolute path but got %q", info.Path)
+	}
+	if chunk.Offset < 0 || chunk.Offset > info.Size_ {
+		return trace.BadParameter("invalid offset %v for file of size %v", chunk.Offset, info.Size_)
+	}
+	logger := srv.WithFields(log.Fields{
+		"request": "PutFile",
+		"path":    info.Path,
+		"offset":  chunk.Offset,
+	})
+	logger.Debug("Request received.")
+
+	result, err := pb.ReceiveFile(info.Path, chunk, stream.Recv)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	logger.Debug("Completed OK.")
+	return trace.Wrap(stream.SendAndClose(result))
+}
+
+// GetFile streams the contents of the file specified with req to the client.
+// The first chunk describes the file and the offset the data starts at,
+// the last chunk specifies the checksum of the file contents
+func (srv *agentServer) GetFile(req *pb.GetFileRequest, stream pb.Agent_GetFileServer) error {
+	if !filepath.IsAbs(req.Path) {
+		return trace.BadParameter("expected absolute path but got %q", req.Path)
+	}
+	logger := srv.WithFields(log.Fields{
+		"request": "GetFile",
+		"path":    req.Path,
+		"offset":  req.Offset,
+	})
+	logger.Debug("Request received.")
+
+	info, err := pb.DescribeFile(req.Path)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if !info.Exists {
+		return trace.NotFound("file %v not found", req.Path)
+	}
+	offset := req.Offset
+	if offset < 0 || offset > info.Size_ {
+		// Restart the transfer from the beginning
+		offset = 0
+	}
+	f, err := os.Open(req.Path)
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	defer f.Close()
+	checksum, err := pb.SendFile(f, *info, offset, stream.Send)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	logger.WithField("checksum", checksum).Debug("Completed OK.")
+	return nil
+}
+
+// StatFile returns information about the file specified with req.
+// If req.Partial is set, describes the incomplete upload of the file instead
+func (srv *agentServer) StatFile(ctx context.Context, req *pb.StatFileRequest) (*pb.FileInfo, error) {
+	if !filepath.IsAbs(req.Path) {
+		return nil, trace.BadParameter("expected absolute path but got %q", req.Path)
+	}
+	path := req.Path
+	if req.Partial {
+		path = pb.PartialPath(req.Path)
+	}
+	info, err := pb.StatFile(path)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return info, nil
+}
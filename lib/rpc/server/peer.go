@@ -281,6 +281,16 @@ type serverPeer struct {
 	creds      credentials.TransportCredentials
 }
 
+// NewRemotePeer returns a new peer for the agent listening on addr.
+// creds specifies the client credentials to connect to the agent with
+func NewRemotePeer(addr string, creds credentials.TransportCredentials) Peer {
+	return &remotePeer{
+		addr:             addr,
+		creds:            creds,
+		reconnectTimeout: defaults.RPCAgentBackoffThreshold,
+	}
+}
+
 // Addr returns the address of this peer.
 // Implements Peer
 func (r remotePeer) Addr() string {
@@ -366,6 +376,34 @@ func (r *peer) GravityCommand(ctx context.Context, log log.FieldLogger, out io.W
 	return trace.Wrap(r.Client.Client().GravityCommand(ctx, log, out, args...))
 }
 
+// PutFile uploads the local file src to the path dst on this peer
+func (r *peer) PutFile(ctx context.Context, log log.FieldLogger, src, dst string) error {
+	if r.Client == nil {
+		return trace.ConnectionProblem(nil, "%v not connected", r.Addr())
+	}
+	return trace.Wrap(r.Client.Client().PutFile(ctx, log, src, dst))
+}
+
+// GetFile downloads the file src from this peer to the local path dst
+func (r *peer) GetFile(ctx context.Context, log log.FieldLogger, src, dst string) error {
+	if r.Client == nil {
+		return trace.ConnectionProblem(nil, "%v not connected", r.Addr())
+	}
+	return trace.Wrap(r.Client.Client().GetFile(ctx, log, src, dst))
+}
+
+// StatFile returns information about the file at path on this peer
+func (r *peer) StatFile(ctx context.Context, path string) (*pb.FileInfo, error) {
+	if r.Client == nil {
+		return nil, trace.ConnectionProblem(nil, "%v not connected", r.Addr())
+	}
+	info, err := r.Client.Client().StatFile(ctx, path)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return info, nil
+}
+
 // GetSystemInfo queries remote system information
 func (r *peer) GetSystemInfo(ctx context.Context) (storage.System, error) {
 	if r.Client == nil {
@@ -0,0 +1,241 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/rpc"
+	"github.com/gravitational/gravity/lib/rpc/client"
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/license/authority"
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+)
+
+// RotateCredentials replaces the credentials of the running agents specified
+// with config.Nodes with config.Next.
+//
+// The rotation is performed in three steps, each of which is applied to all agents
+// before proceeding to the next: first, both the current and the new certificate
+// authorities are trusted, then agents switch to the key pairs issued by the new
+// certificate authority and, finally, the current certificate authority is revoked.
+//
+// Since validity of the current and new credentials overlaps during the rotation,
+// agents stay connected throughout the process.
+// The agents are expected to pick up the changes to the credentials
+// files without a restart (see rpc.ReloadingCredentials)
+func RotateCredentials(ctx context.Context, config RotateConfig) error {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+
+	bundle, err := rpc.TrustBundle(config.Current, config.Next)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	peers := make([]Peer, 0, len(config.Nodes))
+	for _, node := range config.Nodes {
+		peers = append(peers, NewRemotePeer(node.Addr, config.Credentials))
+	}
+	group, err := NewAgentGroup(AgentGroupConfig{FieldLogger: config.FieldLogger}, peers)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	group.Start()
+	defer group.Close(ctx)
+
+	rotator := &rotator{
+		RotateConfig: config,
+		group:        group,
+		bundle:       bundle,
+	}
+	for _, step := range rotator.steps() {
+		config.WithField("step", step.name).Info("Rotate credentials.")
+		if err := rotator.apply(ctx, step); err != nil {
+			return trace.Wrap(err, "failed to %v", step.name)
+		}
+	}
+	return nil
+}
+
+// RotateConfig describes the credentials rotation
+type RotateConfig struct {
+	// FieldLogger is the logger
+	log.FieldLogger
+	// Nodes lists the nodes running agents
+	Nodes []RotateNode
+	// Credentials specifies the client credentials to connect to agents.
+	// The credentials should pick up the changes to the local credentials
+	// files for the agents to stay reachable after the rotation
+	Credentials credentials.TransportCredentials
+	// Current specifies the credentials currently used by agents
+	Current utils.TLSArchive
+	// Next specifies the credentials to rotate to
+	Next utils.TLSArchive
+	// ConnectTimeout specifies the timeout to verify connectivity to an agent.
+	// Defaults to defaults.PeerConnectTimeout
+	ConnectTimeout time.Duration
+}
+
+// CheckAndSetDefaults validates this configuration object and sets defaults
+func (r *RotateConfig) CheckAndSetDefaults() error {
+	if len(r.Nodes) == 0 {
+		return trace.BadParameter("at least one node is required")
+	}
+	if r.Credentials == nil {
+		return trace.BadParameter("Credentials is required")
+	}
+	for _, name := range []string{pb.CA, pb.Server, pb.Client} {
+		if _, err := r.Current.GetKeyPair(name); err != nil {
+			return trace.Wrap(err, "invalid current credentials")
+		}
+		if _, err := r.Next.GetKeyPair(name); err != nil {
+			return trace.Wrap(err, "invalid new credentials")
+		}
+	}
+	if r.FieldLogger == nil {
+		r.FieldLogger = log.WithField(trace.Component, "rpc:rotate")
+	}
+	if r.ConnectTimeout == 0 {
+		r.ConnectTimeout = defaults.PeerConnectTimeout
+	}
+	return nil
+}
+
+// RotateNode describes a node with an agent
+type RotateNode struct {
+	// Addr specifies the agent's address
+	Addr string
+	// SecretsDir specifies the location of the agent's credentials on the node
+	SecretsDir string
+}
+
+func (r *rotator) steps() []rotateStep {
+	return []rotateStep{
+		{
+			name: "trust new certificate authority",
+			files: utils.TLSArchive{
+				pb.CA: r.bundle,
+			},
+			// Agents still use the current key pairs
+			verify: utils.TLSArchive{
+				pb.CA:     r.bundle,
+				pb.Client: r.Current[pb.Client],
+			},
+		},
+		{
+			name: "issue new key pairs",
+			files: utils.TLSArchive{
+				pb.Server: r.Next[pb.Server],
+				pb.Client: r.Next[pb.Client],
+			},
+			verify: utils.TLSArchive{
+				pb.CA:     r.bundle,
+				pb.Client: r.Next[pb.Client],
+			},
+		},
+		{
+			name: "revoke old certificate authority",
+			files: utils.TLSArchive{
+				pb.CA: r.Next[pb.CA],
+			},
+			verify: utils.TLSArchive{
+				pb.CA:     r.Next[pb.CA],
+				pb.Client: r.Next[pb.Client],
+			},
+		},
+	}
+}
+
+// apply distributes the files of the specified step to all agents
+// and verifies that the agents are reachable with the credentials
+// effective after the step
+func (r *rotator) apply(ctx context.Context, step rotateStep) error {
+	dir, err := ioutil.TempDir("", "rpcsecrets")
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := rpc.WriteCredentials(dir, step.files); err != nil {
+		return trace.Wrap(err)
+	}
+	verifyCreds, err := rpc.ClientCredentialsFromKeyPairs(*step.verify[pb.Client], *step.verify[pb.CA])
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	// Resolve the clients one at a time as concurrent waits on the group
+	// might miss peer updates
+	clients := make([]client.Client, 0, len(r.Nodes))
+	for _, node := range r.Nodes {
+		clients = append(clients, r.group.WithContext(ctx, node.Addr))
+	}
+	errCh := make(chan error, len(r.Nodes))
+	for i, node := range r.Nodes {
+		go func(node RotateNode, clt client.Client) {
+			logger := r.WithField("node", node.Addr)
+			for _, file := range rpc.CredentialsFiles(step.files) {
+				err := clt.PutFile(ctx, logger, filepath.Join(dir, file), filepath.Join(node.SecretsDir, file))
+				if err != nil {
+					errCh <- trace.Wrap(err, "failed to update credentials on %v", node.Addr)
+					return
+				}
+			}
+			errCh <- trace.Wrap(r.verify(ctx, node.Addr, verifyCreds))
+		}(node, clients[i])
+	}
+	return trace.Wrap(utils.CollectErrors(ctx, errCh))
+}
+
+// verify establishes a new connection to the agent at addr using the specified
+// credentials to make sure the agent accepts them
+func (r *rotator) verify(ctx context.Context, addr string, creds credentials.TransportCredentials) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ConnectTimeout)
+	defer cancel()
+	clt, err := client.New(ctx, client.Config{ServerAddr: addr, Credentials: creds})
+	if err != nil {
+		return trace.Wrap(err, "failed to connect to %v with new credentials", addr)
+	}
+	defer clt.Close()
+	_, err = clt.GetCurrentTime(ctx)
+	return trace.Wrap(err)
+}
+
+type rotator struct {
+	RotateConfig
+	group  *AgentGroup
+	bundle *authority.TLSKeyPair
+}
+
+type rotateStep struct {
+	// name identifies the step
+	name string
+	// files lists the credentials to distribute to agents
+	files utils.TLSArchive
+	// verify specifies the client credentials that agents
+	// are expected to accept after the step
+	verify utils.TLSArchive
+}
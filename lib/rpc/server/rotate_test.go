@@ -0,0 +1,100 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"time"
+
+	"github.com/gravitational/gravity/lib/rpc"
+	"github.com/gravitational/gravity/lib/rpc/client"
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"golang.org/x/net/context"
+	. "gopkg.in/check.v1"
+)
+
+func (r *S) TestRotatesCredentials(c *C) {
+	log := r.WithField("test", "RotatesCredentials")
+	current, err := rpc.GenerateAgentCredentials(nil, "test", true)
+	c.Assert(err, IsNil)
+	next, err := rpc.GenerateAgentCredentials(nil, "test", true)
+	c.Assert(err, IsNil)
+
+	var nodes []RotateNode
+	for i := 0; i < 2; i++ {
+		secretsDir := c.MkDir()
+		c.Assert(rpc.WriteCredentials(secretsDir, current), IsNil)
+		serverCreds, clientCreds, err := rpc.ReloadingCredentials(secretsDir)
+		c.Assert(err, IsNil)
+		listener := listen(c)
+		srv, err := New(Config{
+			Listener: listener,
+			Credentials: Credentials{
+				Server: serverCreds,
+				Client: clientCreds,
+			},
+		}, log.WithField("server", listener.Addr()))
+		c.Assert(err, IsNil)
+		go srv.Serve()
+		defer withTestCtx(srv.Stop)
+		nodes = append(nodes, RotateNode{
+			Addr:       srv.Addr().String(),
+			SecretsDir: secretsDir,
+		})
+	}
+	creds, err := rpc.ReloadingClientCredentials(nodes[0].SecretsDir)
+	c.Assert(err, IsNil)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
+	defer cancel()
+	err = RotateCredentials(ctx, RotateConfig{
+		FieldLogger: log,
+		Nodes:       nodes,
+		Credentials: creds,
+		Current:     current,
+		Next:        next,
+	})
+	c.Assert(err, IsNil)
+
+	for _, node := range nodes {
+		comment := Commentf("node %v", node.Addr)
+		archive, err := rpc.ReadCredentials(node.SecretsDir)
+		c.Assert(err, IsNil, comment)
+		for _, name := range []string{pb.CA, pb.Server, pb.Client} {
+			c.Assert(string(archive[name].CertPEM), Equals, string(next[name].CertPEM), comment)
+		}
+		c.Assert(canConnect(node.Addr, next), Equals, true, comment)
+		c.Assert(canConnect(node.Addr, current), Equals, false, comment)
+	}
+}
+
+func canConnect(addr string, archive utils.TLSArchive) bool {
+	creds, err := rpc.ClientCredentialsFromKeyPairs(*archive[pb.Client], *archive[pb.CA])
+	if err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+	defer cancel()
+	clt, err := client.New(ctx, client.Config{ServerAddr: addr, Credentials: creds})
+	if err != nil {
+		return false
+	}
+	defer clt.Close()
+	_, err = clt.GetCurrentTime(ctx)
+	return err == nil
+}
@@ -469,15 +469,44 @@ func (d Dependencies) GetApps() []loc.Locator {
 	return loc.Deduplicate(apps)
 }
 
+// Constrained returns all package and app dependencies that specify
+// a version constraint instead of an exact version
+func (d Dependencies) Constrained() (deps []Dependency) {
+	for _, dep := range append(d.Packages, d.Apps...) {
+		if dep.Constraint != "" {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
 // Dependency represents a package or app dependency
 type Dependency struct {
-	// Locator is dependency package locator
+	// Locator is dependency package locator.
+	//
+	// If the dependency specifies a version constraint, the locator
+	// has an empty version until the constraint is resolved
 	Locator loc.Locator
+	// Constraint is an optional version range constraint, e.g. "~1.4"
+	Constraint string
+}
+
+// IsResolved returns true if the dependency refers to an exact version
+func (d Dependency) IsResolved() bool {
+	return d.Constraint == ""
+}
+
+// String returns the dependency as it was specified in the manifest
+func (d Dependency) String() string {
+	if d.Constraint != "" {
+		return fmt.Sprintf("%v/%v:%v", d.Locator.Repository, d.Locator.Name, d.Constraint)
+	}
+	return d.Locator.String()
 }
 
 // MarshalJSON marshals dependency into a JSON string
 func (d *Dependency) MarshalJSON() ([]byte, error) {
-	bytes, err := json.Marshal(d.Locator.String())
+	bytes, err := json.Marshal(d.String())
 	return bytes, trace.Wrap(err)
 }
 
@@ -487,14 +516,45 @@ func (d *Dependency) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &locator); err != nil {
 		return trace.Wrap(err)
 	}
-	parsed, err := loc.ParseLocator(locator)
+	parsed, err := ParseDependency(locator)
 	if err != nil {
 		return trace.Wrap(err)
 	}
-	*d = Dependency{Locator: *parsed}
+	*d = *parsed
 	return nil
 }
 
+// ParseDependency parses a dependency specified either as an exact package
+// locator, e.g. "example.com/app:1.4.2", or as a package name followed by
+// a version constraint, e.g. "example.com/app:~1.4" or "example.com/app:>=2.0 <3.0"
+func ParseDependency(s string) (*Dependency, error) {
+	locator, err := loc.ParseLocator(s)
+	if err == nil {
+		return &Dependency{Locator: *locator}, nil
+	}
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return nil, trace.Wrap(err)
+	}
+	constraint, errConstraint := loc.ParseVersionConstraint(parts[1])
+	if errConstraint != nil {
+		return nil, trace.BadParameter("dependency %q is neither a valid "+
+			"package locator nor a version constraint: %v", s, errConstraint)
+	}
+	// validate the repository and name with a placeholder version
+	locator, err = loc.ParseLocator(fmt.Sprintf("%v:%v", parts[0], loc.ZeroVersion))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &Dependency{
+		Locator: loc.Locator{
+			Repository: locator.Repository,
+			Name:       locator.Name,
+		},
+		Constraint: constraint.String(),
+	}, nil
+}
+
 // Installer contains installer customizations
 type Installer struct {
 	// EULA describes the application end user license agreement
@@ -17,6 +17,7 @@ limitations under the License.
 package schema
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/gravitational/gravity/lib/compare"
@@ -524,6 +525,49 @@ metadata:
 	c.Assert(err, NotNil)
 }
 
+func (s *ManifestSuite) TestDependencyConstraints(c *C) {
+	bytes := []byte(`apiVersion: bundle.gravitational.io/v2
+kind: Bundle
+metadata:
+  name: myapp
+  resourceVersion: 1.0.0
+dependencies:
+  packages:
+    - gravitational.io/gravity:0.0.1
+  apps:
+    - gravitational.io/dns-app:~1.4
+    - gravitational.io/logging-app:>=2.0 <3.0`)
+	m, err := ParseManifestYAML(bytes)
+	c.Assert(err, IsNil)
+	compare.DeepCompare(c, m.Dependencies, Dependencies{
+		Packages: []Dependency{
+			{Locator: loc.MustParseLocator("gravitational.io/gravity:0.0.1")},
+		},
+		Apps: []Dependency{
+			{
+				Locator:    loc.Locator{Repository: "gravitational.io", Name: "dns-app"},
+				Constraint: "~1.4",
+			},
+			{
+				Locator:    loc.Locator{Repository: "gravitational.io", Name: "logging-app"},
+				Constraint: ">=2.0 <3.0",
+			},
+		},
+	})
+	c.Assert(m.Dependencies.Constrained(), HasLen, 2)
+
+	// constraints are preserved when the manifest is serialized back
+	data, err := json.Marshal(m.Dependencies)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, `{"packages":["gravitational.io/gravity:0.0.1"],`+
+		`"apps":["gravitational.io/dns-app:~1.4","gravitational.io/logging-app:\u003e=2.0 \u003c3.0"]}`)
+
+	for _, dep := range []string{"gravitational.io/dns-app:latest", "gravitational.io/dns-app", "dns-app:~1.4"} {
+		_, err = ParseDependency(dep)
+		c.Assert(err, NotNil, Commentf(dep))
+	}
+}
+
 func (s *ManifestSuite) TestInvalidWebConfig(c *C) {
 	bytes := []byte(`apiVersion: bundle.gravitational.io/v2
 kind: Bundle
@@ -195,6 +195,8 @@ type Cluster struct {
 	ActiveOperations []*ClusterOperation `json:"active_operations,omitempty"`
 	// Endpoints contains cluster and application endpoints.
 	Endpoints Endpoints `json:"endpoints"`
+	// PackageStorage describes the integrity of the stored packages
+	PackageStorage *PackageStorage `json:"package_storage,omitempty"`
 	// Extension is a cluster status extension
 	Extension `json:",inline,omitempty"`
 }
@@ -231,6 +233,41 @@ func (e ClusterEndpoints) WriteTo(w io.Writer) (n int64, err error) {
 	return n, trace.NewAggregate(errors...)
 }
 
+// PackageStorage describes the integrity of the packages
+// stored by the cluster package service peers
+type PackageStorage struct {
+	// Peers lists the results of the last verification on each peer
+	Peers []storage.BLOBScrubReport `json:"peers"`
+}
+
+// FromBLOBScrubReports returns the package storage status
+// from the specified verification reports
+func FromBLOBScrubReports(reports []storage.BLOBScrubReport) *PackageStorage {
+	if len(reports) == 0 {
+		return nil
+	}
+	return &PackageStorage{Peers: reports}
+}
+
+// WriteTo writes the package storage status to the provided writer.
+func (r PackageStorage) WriteTo(w io.Writer) (n int64, err error) {
+	var errors []error
+	errors = append(errors, fprintf(&n, w, "Package storage:\n"))
+	for _, peer := range r.Peers {
+		errors = append(errors, fprintf(&n, w, "    * %v:\tverified %v (%v packages",
+			peer.PeerID, peer.Completed.Format(constants.HumanDateFormat), peer.Checked))
+		if len(peer.Corrupted) != 0 {
+			errors = append(errors, fprintf(&n, w, ", %v corrupted, %v repaired",
+				len(peer.Corrupted), len(peer.Repaired)))
+		}
+		errors = append(errors, fprintf(&n, w, ")\n"))
+		for _, hash := range peer.Unrepaired() {
+			errors = append(errors, fprintf(&n, w, "        - unrepaired: %v\n", hash))
+		}
+	}
+	return n, trace.NewAggregate(errors...)
+}
+
 // ApplicationsEndpoints contains endpoints for multiple applications.
 type ApplicationsEndpoints struct {
 	// Endpoints lists the endpoints of all applications
@@ -0,0 +1,259 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/utils"
+
+	teleservices "github.com/gravitational/teleport/lib/services"
+	teleutils "github.com/gravitational/teleport/lib/utils"
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+)
+
+// BackupSchedule describes a schedule for periodic cluster backups
+type BackupSchedule interface {
+	// Resource provides common resource methods
+	teleservices.Resource
+	// CheckAndSetDefaults validates the schedule and sets defaults
+	CheckAndSetDefaults() error
+	// GetSchedule returns the schedule in cron format
+	GetSchedule() string
+	// GetRetention returns the number of backups to keep
+	GetRetention() int
+	// GetTimeout returns the backup hook timeout
+	GetTimeout() time.Duration
+}
+
+// NewBackupSchedule creates a new backup schedule resource
+func NewBackupSchedule(name string, spec BackupScheduleSpecV2) BackupSchedule {
+	return &BackupScheduleV2{
+		Kind:    KindBackupSchedule,
+		Version: teleservices.V2,
+		Metadata: teleservices.Metadata{
+			Name:      name,
+			Namespace: defaults.Namespace,
+		},
+		Spec: spec,
+	}
+}
+
+// BackupScheduleV2 represents the backup schedule resource
+type BackupScheduleV2 struct {
+	// Kind is the resource kind, "backupschedule"
+	Kind string `json:"kind"`
+	// Version is the resource version, "v2"
+	Version string `json:"version"`
+	// Metadata contains the schedule metadata
+	Metadata teleservices.Metadata `json:"metadata"`
+	// Spec is the backup schedule spec
+	Spec BackupScheduleSpecV2 `json:"spec"`
+}
+
+// BackupScheduleSpecV2 defines the backup schedule
+type BackupScheduleSpecV2 struct {
+	// Schedule specifies when to take backups in cron format
+	Schedule string `json:"schedule"`
+	// Retention is the number of most recent backups to keep
+	Retention int `json:"retention,omitempty"`
+	// Timeout overrides the backup hook timeout
+	Timeout teleservices.Duration `json:"timeout,omitempty"`
+}
+
+// GetName returns the schedule name
+func (b *BackupScheduleV2) GetName() string {
+	return b.Metadata.Name
+}
+
+// SetName sets the schedule name
+func (b *BackupScheduleV2) SetName(name string) {
+	b.Metadata.Name = name
+}
+
+// GetMetadata returns the schedule metadata
+func (b *BackupScheduleV2) GetMetadata() teleservices.Metadata {
+	return b.Metadata
+}
+
+// SetExpiry sets the schedule expiration time
+func (b *BackupScheduleV2) SetExpiry(expires time.Time) {
+	b.Metadata.SetExpiry(expires)
+}
+
+// Expiry returns the schedule expiration time
+func (b *BackupScheduleV2) Expiry() time.Time {
+	return b.Metadata.Expiry()
+}
+
+// SetTTL sets the schedule TTL
+func (b *BackupScheduleV2) SetTTL(clock clockwork.Clock, ttl time.Duration) {
+	b.Metadata.SetTTL(clock, ttl)
+}
+
+// GetSchedule returns the schedule in cron format
+func (b *BackupScheduleV2) GetSchedule() string {
+	return b.Spec.Schedule
+}
+
+// GetRetention returns the number of backups to keep
+func (b *BackupScheduleV2) GetRetention() int {
+	return b.Spec.Retention
+}
+
+// GetTimeout returns the backup hook timeout
+func (b *BackupScheduleV2) GetTimeout() time.Duration {
+	return b.Spec.Timeout.Duration
+}
+
+// CheckAndSetDefaults validates the schedule and sets defaults
+func (b *BackupScheduleV2) CheckAndSetDefaults() error {
+	if b.Metadata.Name == "" {
+		return trace.BadParameter("missing parameter Name")
+	}
+	if b.Spec.Schedule == "" {
+		return trace.BadParameter("missing parameter Schedule")
+	}
+	if _, err := utils.ParseCronSchedule(b.Spec.Schedule); err != nil {
+		return trace.Wrap(err)
+	}
+	if b.Spec.Retention < 0 {
+		return trace.BadParameter("retention must be >= 0")
+	}
+	if b.Spec.Retention == 0 {
+		b.Spec.Retention = defaults.BackupRetention
+	}
+	if b.Spec.Timeout.Duration < 0 {
+		return trace.BadParameter("timeout must be >= 0")
+	}
+	return nil
+}
+
+// BackupScheduleSpecV2Schema is JSON schema for the backup schedule spec
+const BackupScheduleSpecV2Schema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["schedule"],
+  "properties": {
+    "schedule": {"type": "string"},
+    "retention": {"type": "integer"},
+    "timeout": {"type": "string"}
+  }
+}`
+
+// GetBackupScheduleSchema returns the backup schedule schema for version V2
+func GetBackupScheduleSchema() string {
+	return fmt.Sprintf(teleservices.V2SchemaTemplate, teleservices.MetadataSchema,
+		BackupScheduleSpecV2Schema, "")
+}
+
+// UnmarshalBackupSchedule unmarshals backup schedule from JSON or YAML
+func UnmarshalBackupSchedule(data []byte) (BackupSchedule, error) {
+	if len(data) == 0 {
+		return nil, trace.BadParameter("missing backup schedule data")
+	}
+	jsonData, err := teleutils.ToJSON(data)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var header teleservices.ResourceHeader
+	err = json.Unmarshal(jsonData, &header)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	switch header.Version {
+	case teleservices.V2:
+		var schedule BackupScheduleV2
+		err := teleutils.UnmarshalWithSchema(GetBackupScheduleSchema(), &schedule, jsonData)
+		if err != nil {
+			return nil, trace.BadParameter(err.Error())
+		}
+		schedule.Metadata.CheckAndSetDefaults()
+		if err := schedule.CheckAndSetDefaults(); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return &schedule, nil
+	}
+	return nil, trace.BadParameter(
+		"%v resource version %q is not supported", KindBackupSchedule, header.Version)
+}
+
+// MarshalBackupSchedule marshals backup schedule into JSON
+func MarshalBackupSchedule(schedule BackupSchedule, opts ...teleservices.MarshalOption) ([]byte, error) {
+	return json.Marshal(schedule)
+}
+
+// Backup describes a cluster backup stored in the cluster BLOB storage
+type Backup struct {
+	// Name uniquely identifies the backup
+	Name string `json:"name"`
+	// Schedule is the name of the schedule that has produced this backup.
+	// Empty for backups taken manually
+	Schedule string `json:"schedule,omitempty"`
+	// Application is the application the backup hook was run for
+	Application loc.Locator `json:"application"`
+	// SHA512 is the hash of the backup tarball in the BLOB storage
+	SHA512 string `json:"sha512"`
+	// SizeBytes is the size of the backup tarball
+	SizeBytes int64 `json:"size_bytes"`
+	// Created is the time the backup was taken
+	Created time.Time `json:"created"`
+}
+
+// Check makes sure the backup record is valid
+func (b Backup) Check() error {
+	if b.Name == "" {
+		return trace.BadParameter("missing backup name")
+	}
+	if b.SHA512 == "" {
+		return trace.BadParameter("missing backup hash")
+	}
+	return nil
+}
+
+// String returns the backup's textual representation
+func (b Backup) String() string {
+	return fmt.Sprintf("Backup(Name=%v, Schedule=%v, Created=%v, Size=%v)",
+		b.Name, b.Schedule, b.Created.Format(constants.HumanDateFormat), b.SizeBytes)
+}
+
+// Backups defines the interface to manage backup schedules and
+// backups stored in the cluster
+type Backups interface {
+	// UpsertBackupSchedule creates or updates the backup schedule
+	UpsertBackupSchedule(BackupSchedule) error
+	// GetBackupSchedule returns the backup schedule by name
+	GetBackupSchedule(name string) (BackupSchedule, error)
+	// GetBackupSchedules returns all backup schedules
+	GetBackupSchedules() ([]BackupSchedule, error)
+	// DeleteBackupSchedule deletes the backup schedule by name
+	DeleteBackupSchedule(name string) error
+	// CreateBackup records a new backup
+	CreateBackup(Backup) (*Backup, error)
+	// GetBackup returns the backup record by name
+	GetBackup(name string) (*Backup, error)
+	// GetBackups returns all backup records sorted by creation time
+	GetBackups() ([]Backup, error)
+	// DeleteBackup deletes the backup record by name
+	DeleteBackup(name string) error
+}
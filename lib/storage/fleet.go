@@ -0,0 +1,120 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gravitational/trace"
+)
+
+// FleetOperation is an operation rolled out by the Ops Center across
+// multiple clusters selected by labels.
+//
+// Clusters are split into waves that are executed one after another
+// and each cluster is updated with a regular cluster operation
+type FleetOperation struct {
+	// ID is a unique operation ID
+	ID string `json:"id"`
+	// AccountID is the ID of the account the clusters belong to
+	AccountID string `json:"account_id"`
+	// Type is the type of the operation rolled out to the clusters,
+	// e.g. application update or configuration change
+	Type string `json:"type"`
+	// Created is the time the operation was created
+	Created time.Time `json:"created"`
+	// CreatedBy specifies the user who created the operation
+	CreatedBy string `json:"created_by,omitempty"`
+	// Updated is the time the operation was last updated
+	Updated time.Time `json:"updated"`
+	// State is the current operation state
+	State string `json:"state"`
+	// Message is an optional message describing the operation state
+	Message string `json:"message,omitempty"`
+	// Selector specifies the labels the clusters have been selected with
+	Selector map[string]string `json:"selector,omitempty"`
+	// App is the application package to update the clusters to
+	App string `json:"app,omitempty"`
+	// Config is the JSON-encoded cluster configuration to apply
+	Config []byte `json:"config,omitempty"`
+	// WaveSize is the maximum number of clusters in a single wave
+	WaveSize int `json:"wave_size"`
+	// Concurrency is the maximum number of clusters within a wave
+	// that are operated on at the same time
+	Concurrency int `json:"concurrency"`
+	// StopOnFailure specifies whether the rollout stops after the wave
+	// with a failed cluster operation
+	StopOnFailure bool `json:"stop_on_failure"`
+	// Clusters lists the selected clusters and the state of their operations
+	Clusters []FleetCluster `json:"clusters"`
+}
+
+// FleetCluster describes the state of a single cluster in a fleet operation
+type FleetCluster struct {
+	// Name is the name of the cluster
+	Name string `json:"name"`
+	// Wave is the index of the wave the cluster belongs to
+	Wave int `json:"wave"`
+	// OperationID is the ID of the operation created in the cluster
+	OperationID string `json:"operation_id,omitempty"`
+	// State is the state of the cluster in the fleet operation
+	State string `json:"state"`
+	// Message is an optional message describing the state,
+	// e.g. the reason of the failure
+	Message string `json:"message,omitempty"`
+}
+
+// Check makes sure the fleet operation is valid
+func (o FleetOperation) Check() error {
+	if o.ID == "" {
+		return trace.BadParameter("missing fleet operation ID")
+	}
+	if o.AccountID == "" {
+		return trace.BadParameter("missing fleet operation account ID")
+	}
+	if o.Type == "" {
+		return trace.BadParameter("missing fleet operation type")
+	}
+	for _, cluster := range o.Clusters {
+		if cluster.Name == "" {
+			return trace.BadParameter("missing cluster name in fleet operation %v", o.ID)
+		}
+	}
+	return nil
+}
+
+// String returns the fleet operation's textual representation
+func (o FleetOperation) String() string {
+	return fmt.Sprintf("FleetOperation(ID=%v, Type=%v, State=%v, Clusters=%v)",
+		o.ID, o.Type, o.State, len(o.Clusters))
+}
+
+// FleetOperations defines the interface to manage fleet operations
+type FleetOperations interface {
+	// CreateFleetOperation creates a new fleet operation
+	CreateFleetOperation(FleetOperation) (*FleetOperation, error)
+	// GetFleetOperation returns the fleet operation by ID
+	GetFleetOperation(id string) (*FleetOperation, error)
+	// GetFleetOperations returns fleet operations of the specified account
+	// sorted by time (latest operations come first)
+	GetFleetOperations(accountID string) ([]FleetOperation, error)
+	// UpdateFleetOperation updates the existing fleet operation
+	UpdateFleetOperation(FleetOperation) (*FleetOperation, error)
+	// DeleteFleetOperation deletes the fleet operation by ID
+	DeleteFleetOperation(id string) error
+}
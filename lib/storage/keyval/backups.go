@@ -0,0 +1,147 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keyval
+
+import (
+	"sort"
+
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+)
+
+func (b *backend) UpsertBackupSchedule(schedule storage.BackupSchedule) error {
+	if err := schedule.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	data, err := storage.MarshalBackupSchedule(schedule)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = b.upsertValBytes(b.key(backupSchedulesP, schedule.GetName()),
+		data, b.ttl(schedule.Expiry()))
+	return trace.Wrap(err)
+}
+
+func (b *backend) GetBackupSchedule(name string) (storage.BackupSchedule, error) {
+	data, err := b.getValBytes(b.key(backupSchedulesP, name))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil, trace.NotFound("backup schedule %q not found", name)
+		}
+		return nil, trace.Wrap(err)
+	}
+	schedule, err := storage.UnmarshalBackupSchedule(data)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return schedule, nil
+}
+
+func (b *backend) GetBackupSchedules() ([]storage.BackupSchedule, error) {
+	names, err := b.getKeys(b.key(backupSchedulesP))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var out []storage.BackupSchedule
+	for _, name := range names {
+		schedule, err := b.GetBackupSchedule(name)
+		if err != nil {
+			if trace.IsNotFound(err) {
+				continue
+			}
+			return nil, trace.Wrap(err)
+		}
+		out = append(out, schedule)
+	}
+	return out, nil
+}
+
+func (b *backend) DeleteBackupSchedule(name string) error {
+	err := b.deleteKey(b.key(backupSchedulesP, name))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.NotFound("backup schedule %q not found", name)
+		}
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+func (b *backend) CreateBackup(backup storage.Backup) (*storage.Backup, error) {
+	if err := backup.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if backup.Created.IsZero() {
+		backup.Created = b.Now().UTC()
+	}
+	err := b.createVal(b.key(backupsP, backup.Name), backup, forever)
+	if err != nil {
+		if trace.IsAlreadyExists(err) {
+			return nil, trace.AlreadyExists("backup %q already exists", backup.Name)
+		}
+		return nil, trace.Wrap(err)
+	}
+	return &backup, nil
+}
+
+func (b *backend) GetBackup(name string) (*storage.Backup, error) {
+	var backup storage.Backup
+	err := b.getVal(b.key(backupsP, name), &backup)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil, trace.NotFound("backup %q not found", name)
+		}
+		return nil, trace.Wrap(err)
+	}
+	utils.UTC(&backup.Created)
+	return &backup, nil
+}
+
+func (b *backend) GetBackups() ([]storage.Backup, error) {
+	names, err := b.getKeys(b.key(backupsP))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	out := make([]storage.Backup, 0, len(names))
+	for _, name := range names {
+		backup, err := b.GetBackup(name)
+		if err != nil {
+			if trace.IsNotFound(err) {
+				continue
+			}
+			return nil, trace.Wrap(err)
+		}
+		out = append(out, *backup)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Created.Before(out[j].Created)
+	})
+	return out, nil
+}
+
+func (b *backend) DeleteBackup(name string) error {
+	err := b.deleteKey(b.key(backupsP, name))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.NotFound("backup %q not found", name)
+		}
+		return trace.Wrap(err)
+	}
+	return nil
+}
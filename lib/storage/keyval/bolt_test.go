@@ -201,6 +201,10 @@ func (s *BSuite) TestObjectsCRUD(c *C) {
 	s.suite.ObjectsCRUD(c)
 }
 
+func (s *BSuite) TestBLOBScrubReportsCRUD(c *C) {
+	s.suite.BLOBScrubReportsCRUD(c)
+}
+
 func (s *BSuite) TestChangesetsCRUD(c *C) {
 	s.suite.ChangesetsCRUD(c)
 }
@@ -240,3 +244,19 @@ func (s *BSuite) TestClusterLogin(c *C) {
 func (s *BSuite) TestIndexFile(c *C) {
 	s.suite.IndexFile(c)
 }
+
+func (s *BSuite) TestBackupsCRUD(c *C) {
+	s.suite.BackupsCRUD(c)
+}
+
+func (s *BSuite) TestMaintenanceWindowCRUD(c *C) {
+	s.suite.MaintenanceWindowCRUD(c)
+}
+
+func (s *BSuite) TestTrustedKeysCRUD(c *C) {
+	s.suite.TrustedKeysCRUD(c)
+}
+
+func (s *BSuite) TestFleetOperationsCRUD(c *C) {
+	s.suite.FleetOperationsCRUD(c)
+}
@@ -77,6 +77,12 @@ const (
 	dnsP                        = "dns"
 	chartsP                     = "charts"
 	indexP                      = "index"
+	backupSchedulesP            = "backupschedules"
+	backupsP                    = "backups"
+	maintenanceWindowP          = "maintenancewindow"
+	blobScrubReportsP           = "blobscrubreports"
+	trustedKeysP                = "trustedkeys"
+	fleetOperationsP            = "fleetoperations"
 
 	// AllCollectionIDs identifies a collection without a specification (an ID)
 	AllCollectionIDs = "__all__"
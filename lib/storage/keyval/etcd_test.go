@@ -225,6 +225,10 @@ func (s *ESuite) TestObjectsCRUD(c *C) {
 	s.suite.ObjectsCRUD(c)
 }
 
+func (s *ESuite) TestBLOBScrubReportsCRUD(c *C) {
+	s.suite.BLOBScrubReportsCRUD(c)
+}
+
 func (s *ESuite) TestChangesetsCRUD(c *C) {
 	s.suite.ChangesetsCRUD(c)
 }
@@ -264,3 +268,19 @@ func (s *ESuite) TestClusterLogin(c *C) {
 func (s *ESuite) TestIndexFile(c *C) {
 	s.suite.IndexFile(c)
 }
+
+func (s *ESuite) TestBackupsCRUD(c *C) {
+	s.suite.BackupsCRUD(c)
+}
+
+func (s *ESuite) TestMaintenanceWindowCRUD(c *C) {
+	s.suite.MaintenanceWindowCRUD(c)
+}
+
+func (s *ESuite) TestTrustedKeysCRUD(c *C) {
+	s.suite.TrustedKeysCRUD(c)
+}
+
+func (s *ESuite) TestFleetOperationsCRUD(c *C) {
+	s.suite.FleetOperationsCRUD(c)
+}
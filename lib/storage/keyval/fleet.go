@@ -0,0 +1,114 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keyval
+
+import (
+	"sort"
+
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/utils"
+
+	"github.com/gravitational/trace"
+)
+
+func (b *backend) CreateFleetOperation(op storage.FleetOperation) (*storage.FleetOperation, error) {
+	if err := op.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if op.Created.IsZero() {
+		op.Created = b.Now().UTC()
+	}
+	if op.Updated.IsZero() {
+		op.Updated = op.Created
+	}
+	err := b.createVal(b.key(fleetOperationsP, op.ID), op, forever)
+	if err != nil {
+		if trace.IsAlreadyExists(err) {
+			return nil, trace.AlreadyExists("fleet operation %q already exists", op.ID)
+		}
+		return nil, trace.Wrap(err)
+	}
+	return &op, nil
+}
+
+func (b *backend) GetFleetOperation(id string) (*storage.FleetOperation, error) {
+	if id == "" {
+		return nil, trace.BadParameter("missing fleet operation ID")
+	}
+	var op storage.FleetOperation
+	err := b.getVal(b.key(fleetOperationsP, id), &op)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil, trace.NotFound("fleet operation %q not found", id)
+		}
+		return nil, trace.Wrap(err)
+	}
+	utils.UTC(&op.Created)
+	utils.UTC(&op.Updated)
+	return &op, nil
+}
+
+func (b *backend) GetFleetOperations(accountID string) ([]storage.FleetOperation, error) {
+	ids, err := b.getKeys(b.key(fleetOperationsP))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var out []storage.FleetOperation
+	for _, id := range ids {
+		op, err := b.GetFleetOperation(id)
+		if err != nil {
+			if trace.IsNotFound(err) {
+				continue
+			}
+			return nil, trace.Wrap(err)
+		}
+		if op.AccountID != accountID {
+			continue
+		}
+		out = append(out, *op)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Created.After(out[j].Created)
+	})
+	return out, nil
+}
+
+func (b *backend) UpdateFleetOperation(op storage.FleetOperation) (*storage.FleetOperation, error) {
+	if err := op.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	op.Updated = b.Now().UTC()
+	err := b.updateVal(b.key(fleetOperationsP, op.ID), op, forever)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil, trace.NotFound("fleet operation %q not found", op.ID)
+		}
+		return nil, trace.Wrap(err)
+	}
+	return &op, nil
+}
+
+func (b *backend) DeleteFleetOperation(id string) error {
+	err := b.deleteKey(b.key(fleetOperationsP, id))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.NotFound("fleet operation %q not found", id)
+		}
+		return trace.Wrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keyval
+
+import (
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+)
+
+func (b *backend) UpsertMaintenanceWindow(window storage.MaintenanceWindow) error {
+	if err := window.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	data, err := storage.MarshalMaintenanceWindow(window)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = b.upsertValBytes(b.key(systemP, maintenanceWindowP),
+		data, b.ttl(window.Expiry()))
+	return trace.Wrap(err)
+}
+
+func (b *backend) GetMaintenanceWindow() (storage.MaintenanceWindow, error) {
+	data, err := b.getValBytes(b.key(systemP, maintenanceWindowP))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil, trace.NotFound("maintenance window is not configured")
+		}
+		return nil, trace.Wrap(err)
+	}
+	window, err := storage.UnmarshalMaintenanceWindow(data)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return window, nil
+}
+
+func (b *backend) DeleteMaintenanceWindow() error {
+	err := b.deleteKey(b.key(systemP, maintenanceWindowP))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.NotFound("maintenance window is not configured")
+		}
+		return trace.Wrap(err)
+	}
+	return nil
+}
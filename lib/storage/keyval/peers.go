@@ -109,3 +109,54 @@ func (b *backend) DeleteObject(hash string) error {
 	err := b.deleteDir(b.key(objectsP, hash))
 	return trace.Wrap(err)
 }
+
+func (b *backend) UpsertBLOBScrubReport(r storage.BLOBScrubReport) error {
+	if err := r.Check(); err != nil {
+		return trace.Wrap(err)
+	}
+	err := b.upsertVal(b.key(blobScrubReportsP, r.PeerID), r, forever)
+	return trace.Wrap(err)
+}
+
+func (b *backend) GetBLOBScrubReport(peerID string) (*storage.BLOBScrubReport, error) {
+	var r storage.BLOBScrubReport
+	err := b.getVal(b.key(blobScrubReportsP, peerID), &r)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil, trace.NotFound("no scrub report for peer(%v)", peerID)
+		}
+		return nil, trace.Wrap(err)
+	}
+	return &r, nil
+}
+
+func (b *backend) DeleteBLOBScrubReport(peerID string) error {
+	err := b.deleteKey(b.key(blobScrubReportsP, peerID))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.NotFound("no scrub report for peer(%v)", peerID)
+		}
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+func (b *backend) GetBLOBScrubReports() ([]storage.BLOBScrubReport, error) {
+	ids, err := b.getKeys(b.key(blobScrubReportsP))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	sort.Strings(ids)
+	var out []storage.BLOBScrubReport
+	for _, id := range ids {
+		r, err := b.GetBLOBScrubReport(id)
+		if err != nil {
+			if trace.IsNotFound(err) {
+				continue
+			}
+			return nil, trace.Wrap(err)
+		}
+		out = append(out, *r)
+	}
+	return out, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keyval
+
+import (
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/gravitational/trace"
+)
+
+func (b *backend) UpsertTrustedKey(key storage.TrustedKey) error {
+	if err := key.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	data, err := storage.MarshalTrustedKey(key)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = b.upsertValBytes(b.key(trustedKeysP, key.GetName()),
+		data, b.ttl(key.Expiry()))
+	return trace.Wrap(err)
+}
+
+func (b *backend) GetTrustedKey(name string) (storage.TrustedKey, error) {
+	data, err := b.getValBytes(b.key(trustedKeysP, name))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil, trace.NotFound("trusted key %q not found", name)
+		}
+		return nil, trace.Wrap(err)
+	}
+	key, err := storage.UnmarshalTrustedKey(data)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return key, nil
+}
+
+func (b *backend) GetTrustedKeys() ([]storage.TrustedKey, error) {
+	names, err := b.getKeys(b.key(trustedKeysP))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var out []storage.TrustedKey
+	for _, name := range names {
+		key, err := b.GetTrustedKey(name)
+		if err != nil {
+			if trace.IsNotFound(err) {
+				continue
+			}
+			return nil, trace.Wrap(err)
+		}
+		out = append(out, key)
+	}
+	return out, nil
+}
+
+func (b *backend) DeleteTrustedKey(name string) error {
+	err := b.deleteKey(b.key(trustedKeysP, name))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.NotFound("trusted key %q not found", name)
+		}
+		return trace.Wrap(err)
+	}
+	return nil
+}
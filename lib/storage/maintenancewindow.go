@@ -0,0 +1,435 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/utils"
+
+	teleservices "github.com/gravitational/teleport/lib/services"
+	teleutils "github.com/gravitational/teleport/lib/utils"
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+)
+
+// MaintenanceWindow restricts the time when cluster update, garbage
+// collection and certificate rotation operations are allowed to start
+type MaintenanceWindow interface {
+	// Resource provides common resource methods
+	teleservices.Resource
+	// CheckAndSetDefaults validates the resource and sets defaults
+	CheckAndSetDefaults() error
+	// GetWindows returns the recurring windows when operations are allowed
+	GetWindows() []MaintenanceWindowSpec
+	// GetBlackouts returns the date ranges when operations are not allowed
+	GetBlackouts() []MaintenanceBlackout
+	// GetPolicy returns the action taken for operations outside of the window
+	GetPolicy() string
+	// IsOpen returns true if operations are allowed at the specified time
+	IsOpen(time.Time) bool
+	// NextOpen returns the earliest time starting from the specified time
+	// when operations are allowed. Returns zero time if the window never opens
+	NextOpen(time.Time) time.Time
+}
+
+// NewMaintenanceWindow creates a new maintenance window resource
+func NewMaintenanceWindow(name string, spec MaintenanceWindowSpecV2) MaintenanceWindow {
+	return &MaintenanceWindowV2{
+		Kind:    KindMaintenanceWindow,
+		Version: teleservices.V2,
+		Metadata: teleservices.Metadata{
+			Name:      name,
+			Namespace: defaults.Namespace,
+		},
+		Spec: spec,
+	}
+}
+
+// MaintenanceWindowV2 represents the maintenance window resource
+type MaintenanceWindowV2 struct {
+	// Kind is the resource kind, "maintenancewindow"
+	Kind string `json:"kind"`
+	// Version is the resource version, "v2"
+	Version string `json:"version"`
+	// Metadata contains the resource metadata
+	Metadata teleservices.Metadata `json:"metadata"`
+	// Spec is the maintenance window spec
+	Spec MaintenanceWindowSpecV2 `json:"spec"`
+	// location is the parsed time zone of the window
+	location *time.Location
+}
+
+// MaintenanceWindowSpecV2 defines the maintenance window
+type MaintenanceWindowSpecV2 struct {
+	// Timezone is the IANA time zone the windows and blackout dates
+	// are evaluated in. Defaults to UTC
+	Timezone string `json:"timezone,omitempty"`
+	// Windows lists recurring windows when operations are allowed.
+	// If empty, operations are allowed at any time outside of blackouts
+	Windows []MaintenanceWindowSpec `json:"windows,omitempty"`
+	// Blackouts lists date ranges when operations are not allowed
+	Blackouts []MaintenanceBlackout `json:"blackouts,omitempty"`
+	// Policy defines what happens to operations outside of the window:
+	// they are either refused or queued until the window opens
+	Policy string `json:"policy,omitempty"`
+}
+
+// MaintenanceWindowSpec defines a single recurring window
+type MaintenanceWindowSpec struct {
+	// Start specifies when the window opens in cron format
+	Start string `json:"start"`
+	// Duration specifies how long the window stays open
+	Duration teleservices.Duration `json:"duration"`
+	// schedule is the parsed start schedule
+	schedule *utils.CronSchedule
+}
+
+// MaintenanceBlackout defines the range of dates when operations are not allowed
+type MaintenanceBlackout struct {
+	// Start is the first date of the blackout in YYYY-MM-DD format
+	Start string `json:"start"`
+	// End is the last date of the blackout in YYYY-MM-DD format.
+	// Defaults to the start date
+	End string `json:"end,omitempty"`
+	// Reason optionally describes the blackout
+	Reason string `json:"reason,omitempty"`
+}
+
+// GetName returns the resource name
+func (w *MaintenanceWindowV2) GetName() string {
+	return w.Metadata.Name
+}
+
+// SetName sets the resource name
+func (w *MaintenanceWindowV2) SetName(name string) {
+	w.Metadata.Name = name
+}
+
+// GetMetadata returns the resource metadata
+func (w *MaintenanceWindowV2) GetMetadata() teleservices.Metadata {
+	return w.Metadata
+}
+
+// SetExpiry sets the resource expiration time
+func (w *MaintenanceWindowV2) SetExpiry(expires time.Time) {
+	w.Metadata.SetExpiry(expires)
+}
+
+// Expiry returns the resource expiration time
+func (w *MaintenanceWindowV2) Expiry() time.Time {
+	return w.Metadata.Expiry()
+}
+
+// SetTTL sets the resource TTL
+func (w *MaintenanceWindowV2) SetTTL(clock clockwork.Clock, ttl time.Duration) {
+	w.Metadata.SetTTL(clock, ttl)
+}
+
+// GetWindows returns the recurring windows when operations are allowed
+func (w *MaintenanceWindowV2) GetWindows() []MaintenanceWindowSpec {
+	return w.Spec.Windows
+}
+
+// GetBlackouts returns the date ranges when operations are not allowed
+func (w *MaintenanceWindowV2) GetBlackouts() []MaintenanceBlackout {
+	return w.Spec.Blackouts
+}
+
+// GetPolicy returns the action taken for operations outside of the window
+func (w *MaintenanceWindowV2) GetPolicy() string {
+	return w.Spec.Policy
+}
+
+// CheckAndSetDefaults validates the resource and sets defaults
+func (w *MaintenanceWindowV2) CheckAndSetDefaults() error {
+	if w.Metadata.Name == "" {
+		return trace.BadParameter("missing parameter Name")
+	}
+	var err error
+	w.location, err = time.LoadLocation(w.Spec.Timezone)
+	if err != nil {
+		return trace.BadParameter("invalid time zone %q: %v", w.Spec.Timezone, err)
+	}
+	for i, window := range w.Spec.Windows {
+		if window.Start == "" {
+			return trace.BadParameter("missing start of window %v", i+1)
+		}
+		w.Spec.Windows[i].schedule, err = utils.ParseCronSchedule(window.Start)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if window.Duration.Duration <= 0 {
+			return trace.BadParameter("duration of window %q must be positive", window.Start)
+		}
+	}
+	for i, blackout := range w.Spec.Blackouts {
+		if blackout.End == "" {
+			w.Spec.Blackouts[i].End = blackout.Start
+		}
+		start, end, err := w.Spec.Blackouts[i].dates(w.location)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if !end.After(start) {
+			return trace.BadParameter("blackout %v ends before it starts", blackout.Start)
+		}
+	}
+	switch w.Spec.Policy {
+	case "":
+		w.Spec.Policy = MaintenancePolicyRefuse
+	case MaintenancePolicyRefuse, MaintenancePolicyQueue:
+	default:
+		return trace.BadParameter("unsupported policy %q, supported are: %v",
+			w.Spec.Policy, []string{MaintenancePolicyRefuse, MaintenancePolicyQueue})
+	}
+	return nil
+}
+
+// IsOpen returns true if operations are allowed at the specified time
+func (w *MaintenanceWindowV2) IsOpen(now time.Time) bool {
+	if w.blackoutEnd(now) != nil {
+		return false
+	}
+	if len(w.Spec.Windows) == 0 {
+		return true
+	}
+	now = now.In(w.getLocation())
+	for _, window := range w.Spec.Windows {
+		schedule := window.getSchedule()
+		if schedule == nil {
+			continue
+		}
+		// the window is open if it has started within its duration
+		start := schedule.Next(now.Add(-window.Duration.Duration))
+		if !start.IsZero() && !start.After(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpen returns the earliest time starting from the specified time
+// when operations are allowed. Returns zero time if the window never opens
+func (w *MaintenanceWindowV2) NextOpen(now time.Time) time.Time {
+	t := now.In(w.getLocation())
+	for i := 0; i < maxMaintenanceWindowSearches; i++ {
+		if w.IsOpen(t) {
+			return t
+		}
+		var next time.Time
+		if end := w.blackoutEnd(t); end != nil {
+			// a window might already be open when the blackout ends
+			next = *end
+		} else {
+			for _, window := range w.Spec.Windows {
+				schedule := window.getSchedule()
+				if schedule == nil {
+					continue
+				}
+				start := schedule.Next(t)
+				if !start.IsZero() && (next.IsZero() || start.Before(next)) {
+					next = start
+				}
+			}
+		}
+		if next.IsZero() {
+			return time.Time{}
+		}
+		t = next
+	}
+	return time.Time{}
+}
+
+// String returns a textual representation of this maintenance window
+func (w *MaintenanceWindowV2) String() string {
+	return fmt.Sprintf("MaintenanceWindow(Name=%v, Windows=%v, Blackouts=%v, Policy=%v)",
+		w.Metadata.Name, len(w.Spec.Windows), len(w.Spec.Blackouts), w.Spec.Policy)
+}
+
+// blackoutEnd returns the end of the blackout the specified time falls
+// into or nil if the time is outside of any blackout
+func (w *MaintenanceWindowV2) blackoutEnd(now time.Time) *time.Time {
+	for _, blackout := range w.Spec.Blackouts {
+		start, end, err := blackout.dates(w.getLocation())
+		if err != nil {
+			continue
+		}
+		if !now.Before(start) && now.Before(end) {
+			return &end
+		}
+	}
+	return nil
+}
+
+func (w *MaintenanceWindowV2) getLocation() *time.Location {
+	if w.location == nil {
+		return time.UTC
+	}
+	return w.location
+}
+
+func (w MaintenanceWindowSpec) getSchedule() *utils.CronSchedule {
+	if w.schedule != nil {
+		return w.schedule
+	}
+	schedule, err := utils.ParseCronSchedule(w.Start)
+	if err != nil {
+		return nil
+	}
+	return schedule
+}
+
+// dates returns the start of the first and the end of the last day of the blackout
+func (b MaintenanceBlackout) dates(location *time.Location) (start, end time.Time, err error) {
+	start, err = time.ParseInLocation(blackoutDateFormat, b.Start, location)
+	if err != nil {
+		return start, end, trace.BadParameter("invalid blackout start date %q, expected YYYY-MM-DD", b.Start)
+	}
+	last := b.End
+	if last == "" {
+		last = b.Start
+	}
+	end, err = time.ParseInLocation(blackoutDateFormat, last, location)
+	if err != nil {
+		return start, end, trace.BadParameter("invalid blackout end date %q, expected YYYY-MM-DD", last)
+	}
+	return start, end.AddDate(0, 0, 1), nil
+}
+
+// CheckMaintenanceWindow returns an error if the operation described with
+// operationType cannot start at the specified time according to the window
+func CheckMaintenanceWindow(window MaintenanceWindow, operationType string, now time.Time) error {
+	if window.IsOpen(now) {
+		return nil
+	}
+	next := window.NextOpen(now)
+	if next.IsZero() {
+		return trace.AccessDenied("%v is not allowed outside of the maintenance window %q, "+
+			"the window does not open in the foreseeable future", operationType, window.GetName())
+	}
+	return trace.AccessDenied("%v is not allowed outside of the maintenance window %q, "+
+		"the next window opens at %v", operationType, window.GetName(),
+		next.UTC().Format(constants.HumanDateFormat))
+}
+
+// MaintenanceWindowSpecV2Schema is JSON schema for the maintenance window spec
+const MaintenanceWindowSpecV2Schema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "properties": {
+    "timezone": {"type": "string"},
+    "windows": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["start", "duration"],
+        "properties": {
+          "start": {"type": "string"},
+          "duration": {"type": "string"}
+        }
+      }
+    },
+    "blackouts": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["start"],
+        "properties": {
+          "start": {"type": "string"},
+          "end": {"type": "string"},
+          "reason": {"type": "string"}
+        }
+      }
+    },
+    "policy": {"type": "string"}
+  }
+}`
+
+// GetMaintenanceWindowSchema returns the maintenance window schema for version V2
+func GetMaintenanceWindowSchema() string {
+	return fmt.Sprintf(teleservices.V2SchemaTemplate, teleservices.MetadataSchema,
+		MaintenanceWindowSpecV2Schema, "")
+}
+
+// UnmarshalMaintenanceWindow unmarshals maintenance window from JSON or YAML
+func UnmarshalMaintenanceWindow(data []byte) (MaintenanceWindow, error) {
+	if len(data) == 0 {
+		return nil, trace.BadParameter("missing maintenance window data")
+	}
+	jsonData, err := teleutils.ToJSON(data)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var header teleservices.ResourceHeader
+	err = json.Unmarshal(jsonData, &header)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	switch header.Version {
+	case teleservices.V2:
+		var window MaintenanceWindowV2
+		err := teleutils.UnmarshalWithSchema(GetMaintenanceWindowSchema(), &window, jsonData)
+		if err != nil {
+			return nil, trace.BadParameter(err.Error())
+		}
+		window.Metadata.CheckAndSetDefaults()
+		if err := window.CheckAndSetDefaults(); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return &window, nil
+	}
+	return nil, trace.BadParameter(
+		"%v resource version %q is not supported", KindMaintenanceWindow, header.Version)
+}
+
+// MarshalMaintenanceWindow marshals maintenance window into JSON
+func MarshalMaintenanceWindow(window MaintenanceWindow, opts ...teleservices.MarshalOption) ([]byte, error) {
+	return json.Marshal(window)
+}
+
+// MaintenanceWindows defines the interface to manage the cluster maintenance window
+type MaintenanceWindows interface {
+	// UpsertMaintenanceWindow creates or updates the maintenance window
+	UpsertMaintenanceWindow(MaintenanceWindow) error
+	// GetMaintenanceWindow returns the maintenance window
+	GetMaintenanceWindow() (MaintenanceWindow, error)
+	// DeleteMaintenanceWindow deletes the maintenance window
+	DeleteMaintenanceWindow() error
+}
+
+const (
+	// MaintenancePolicyRefuse specifies that operations outside of the
+	// maintenance window are refused
+	MaintenancePolicyRefuse = "refuse"
+	// MaintenancePolicyQueue specifies that operations outside of the
+	// maintenance window wait for the window to open
+	MaintenancePolicyQueue = "queue"
+
+	// blackoutDateFormat is the format of blackout dates
+	blackoutDateFormat = "2006-01-02"
+
+	// maxMaintenanceWindowSearches limits the number of steps taken
+	// to find the next time the maintenance window opens
+	maxMaintenanceWindowSearches = 1000
+)
@@ -0,0 +1,147 @@
+/*
+Copyright 2019 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"time"
+
+	teleservices "github.com/gravitational/teleport/lib/services"
+	"github.com/gravitational/trace"
+	. "gopkg.in/check.v1"
+)
+
+type MaintenanceWindowSuite struct{}
+
+var _ = Suite(&MaintenanceWindowSuite{})
+
+func (*MaintenanceWindowSuite) TestParsesMaintenanceWindow(c *C) {
+	window, err := UnmarshalMaintenanceWindow([]byte(`kind: maintenancewindow
+version: v2
+metadata:
+  name: maintenance
+spec:
+  timezone: America/New_York
+  windows:
+  - start: "0 22 * * mon-fri"
+    duration: 6h
+  blackouts:
+  - start: 2019-12-20
+    end: 2020-01-02
+    reason: holidays
+  policy: queue`))
+	c.Assert(err, IsNil)
+	c.Assert(window.GetPolicy(), Equals, MaintenancePolicyQueue)
+	c.Assert(window.GetWindows(), HasLen, 1)
+	c.Assert(window.GetWindows()[0].Duration.Duration, Equals, 6*time.Hour)
+	c.Assert(window.GetBlackouts(), DeepEquals, []MaintenanceBlackout{
+		{Start: "2019-12-20", End: "2020-01-02", Reason: "holidays"},
+	})
+
+	for _, spec := range []string{
+		`{"windows": [{"start": "0 22 * *", "duration": "1h"}]}`,
+		`{"windows": [{"start": "0 22 * * *", "duration": "0s"}]}`,
+		`{"blackouts": [{"start": "12/20/2019"}]}`,
+		`{"blackouts": [{"start": "2019-12-20", "end": "2019-12-19"}]}`,
+		`{"timezone": "Mars/Olympus_Mons"}`,
+		`{"policy": "ignore"}`,
+	} {
+		_, err := UnmarshalMaintenanceWindow([]byte(`{"kind": "maintenancewindow", "version": "v2", ` +
+			`"metadata": {"name": "maintenance"}, "spec": ` + spec + `}`))
+		c.Assert(trace.IsBadParameter(err), Equals, true, Commentf("%v: %v", spec, err))
+	}
+}
+
+func (*MaintenanceWindowSuite) TestWindowIsOpen(c *C) {
+	window := newTestMaintenanceWindow(c)
+	testCases := []struct {
+		time     string
+		open     bool
+		nextOpen string
+		comment  string
+	}{
+		{
+			time:     "2019-12-02T12:00:00Z",
+			nextOpen: "2019-12-02T22:00:00Z",
+			comment:  "business hours on monday",
+		},
+		{
+			time:     "2019-12-02T22:00:00Z",
+			open:     true,
+			nextOpen: "2019-12-02T22:00:00Z",
+			comment:  "window opens",
+		},
+		{
+			time:     "2019-12-03T03:59:00Z",
+			open:     true,
+			nextOpen: "2019-12-03T03:59:00Z",
+			comment:  "window started the day before",
+		},
+		{
+			time:     "2019-12-03T04:00:00Z",
+			nextOpen: "2019-12-03T22:00:00Z",
+			comment:  "window closes",
+		},
+		{
+			time:     "2019-12-07T12:00:00Z",
+			nextOpen: "2019-12-09T22:00:00Z",
+			comment:  "no window on weekends",
+		},
+		{
+			time:     "2019-12-24T23:00:00Z",
+			nextOpen: "2019-12-26T00:00:00Z",
+			comment:  "blackout, window started on the last day of blackout is open after it ends",
+		},
+		{
+			time:     "2019-12-25T23:00:00Z",
+			nextOpen: "2019-12-26T00:00:00Z",
+			comment:  "last day of blackout",
+		},
+		{
+			time:     "2019-12-26T04:00:00Z",
+			nextOpen: "2019-12-26T22:00:00Z",
+			comment:  "window closes after blackout",
+		},
+	}
+	for _, tc := range testCases {
+		now, err := time.Parse(time.RFC3339, tc.time)
+		c.Assert(err, IsNil)
+		nextOpen, err := time.Parse(time.RFC3339, tc.nextOpen)
+		c.Assert(err, IsNil)
+		comment := Commentf(tc.comment)
+		c.Assert(window.IsOpen(now), Equals, tc.open, comment)
+		c.Assert(window.NextOpen(now).Equal(nextOpen), Equals, true,
+			Commentf("%v: expected %v, got %v", tc.comment, nextOpen, window.NextOpen(now)))
+		err = CheckMaintenanceWindow(window, "cluster update", now)
+		c.Assert(err == nil, Equals, tc.open, comment)
+		if err != nil {
+			c.Assert(trace.IsAccessDenied(err), Equals, true, comment)
+		}
+	}
+}
+
+func newTestMaintenanceWindow(c *C) MaintenanceWindow {
+	window := NewMaintenanceWindow("maintenance", MaintenanceWindowSpecV2{
+		Windows: []MaintenanceWindowSpec{
+			{Start: "0 22 * * mon-fri", Duration: teleservices.NewDuration(6 * time.Hour)},
+		},
+		Blackouts: []MaintenanceBlackout{
+			{Start: "2019-12-24", End: "2019-12-25", Reason: "holidays"},
+		},
+	})
+	c.Assert(window.CheckAndSetDefaults(), IsNil)
+	return window
+}
@@ -20,6 +20,8 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/gravitational/gravity/lib/defaults"
+
 	teleservices "github.com/gravitational/teleport/lib/services"
 	teleutils "github.com/gravitational/teleport/lib/utils"
 	"github.com/gravitational/trace"
@@ -33,6 +35,39 @@ type Alert interface {
 	CheckAndSetDefaults() error
 	// GetFormula returns the kapacitor formula
 	GetFormula() string
+	// GetExpr returns the Prometheus alerting rule expression
+	GetExpr() string
+	// GetFor returns how long the Prometheus alerting rule expression
+	// should hold before the alert fires
+	GetFor() string
+	// GetLabels returns labels attached to the Prometheus alert
+	GetLabels() map[string]string
+}
+
+// NewAlert returns a new monitoring alert resource
+func NewAlert(name string, spec AlertSpecV2) Alert {
+	return &AlertV2{
+		Kind:    KindAlert,
+		Version: teleservices.V2,
+		Metadata: teleservices.Metadata{
+			Name:      name,
+			Namespace: defaults.Namespace,
+		},
+		Spec: spec,
+	}
+}
+
+// NewAlertTarget returns a new monitoring alert target resource
+func NewAlertTarget(email string) AlertTarget {
+	return &AlertTargetV2{
+		Kind:    KindAlertTarget,
+		Version: teleservices.V2,
+		Metadata: teleservices.Metadata{
+			Name:      KindAlertTarget,
+			Namespace: defaults.Namespace,
+		},
+		Spec: AlertTargetSpecV2{Email: email},
+	}
 }
 
 // AlertV2 defines a monitoring alert
@@ -52,10 +87,25 @@ func (r *AlertV2) GetFormula() string {
 	return r.Spec.Formula
 }
 
+// GetExpr returns alert's Prometheus expression
+func (r *AlertV2) GetExpr() string {
+	return r.Spec.Expr
+}
+
+// GetFor returns the duration alert's Prometheus expression should hold before the alert fires
+func (r *AlertV2) GetFor() string {
+	return r.Spec.For
+}
+
+// GetLabels returns labels attached to the Prometheus alert
+func (r *AlertV2) GetLabels() map[string]string {
+	return r.Spec.Labels
+}
+
 // CheckAndSetDefaults checks validity of all parameters and sets defaults
 func (r *AlertV2) CheckAndSetDefaults() error {
-	if r.Spec.Formula == "" {
-		return trace.BadParameter("missing parameter Formula")
+	if r.Spec.Formula == "" && r.Spec.Expr == "" {
+		return trace.BadParameter("either Formula or Expr must be specified")
 	}
 
 	if r.Metadata.Name == "" {
@@ -101,19 +151,31 @@ func MarshalAlert(alert Alert, opts ...teleservices.MarshalOption) ([]byte, erro
 	return json.Marshal(alert)
 }
 
-// AlertSpecV2 defines a monitoring alert
+// AlertSpecV2 defines a monitoring alert.
+//
+// Formula defines the alert for the InfluxDB/Kapacitor monitoring provider,
+// while Expr, For and Labels define the alert for the Prometheus provider.
+// An alert can specify both to be supported by either provider
 type AlertSpecV2 struct {
 	// Formula defines a formula for kapacitor
-	Formula string `json:"formula"`
+	Formula string `json:"formula,omitempty"`
+	// Expr is the PromQL expression of the Prometheus alerting rule
+	Expr string `json:"expr,omitempty"`
+	// For specifies how long the expression should hold before the alert fires, e.g. 5m
+	For string `json:"for,omitempty"`
+	// Labels specifies additional labels attached to the alert, e.g. severity
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // AlertSpecV2Schema is JSON schema for a monitoring alert
 const AlertSpecV2Schema = `{
   "type": "object",
   "additionalProperties": false,
-  "required": ["formula"],
   "properties": {
-    "formula": {"type": "string"}
+    "formula": {"type": "string"},
+    "expr": {"type": "string"},
+    "for": {"type": "string"},
+    "labels": {"type": "object", "additionalProperties": {"type": "string"}}
   }
 }`
 
@@ -17,6 +17,7 @@ limitations under the License.
 package storage
 
 import (
+	"sort"
 	"time"
 
 	"github.com/gravitational/gravity/lib/loc"
@@ -81,8 +82,19 @@ type OperationPhase struct {
 	Requires []string `json:"requires,omitempty" yaml:"requires,omitempty"`
 	// Parallel enables parallel execution of sub-phases
 	Parallel bool `json:"parallel"`
+	// Concurrency limits the number of sub-phases of a parallel phase
+	// executed at the same time. Zero means no limit
+	Concurrency int `json:"concurrency,omitempty" yaml:"concurrency,omitempty"`
 	// Updated is the last phase update time
 	Updated time.Time `json:"updated,omitempty" yaml:"updated,omitempty"`
+	// Started is the time the most recent attempt to execute
+	// or roll back the phase has started
+	Started time.Time `json:"started,omitempty" yaml:"started,omitempty"`
+	// Finished is the time the most recent attempt has finished.
+	// Zero if the attempt is still running
+	Finished time.Time `json:"finished,omitempty" yaml:"finished,omitempty"`
+	// Attempts is the number of times the phase has been executed or rolled back
+	Attempts int `json:"attempts,omitempty" yaml:"attempts,omitempty"`
 	// Data is optional phase-specific data attached to the phase
 	Data *OperationPhaseData `json:"data,omitempty" yaml:"data,omitempty"`
 	// Error is the error that happened during phase execution
@@ -122,9 +134,20 @@ type OperationPhaseData struct {
 	// GarbageCollect specifies configuration specific to garbage collect operation
 	GarbageCollect *GarbageCollectOperationData `json:"garbage_collect,omitempty" yaml:"garbage_collect,omitempty"`
 	// Update specifies configuration specific to update operations
-	Update *UpdateOperationData `json:"update,omitempty" yaml:"garbage_collect,omitempty"`
+	Update *UpdateOperationData `json:"update,omitempty" yaml:"update,omitempty"`
 	// Install specifies configuration specific to install operation
 	Install *InstallOperationData `json:"install,omitempty" yaml:"install,omitempty"`
+	// HealthGate specifies configuration of the health gate phase
+	HealthGate *HealthGateData `json:"health_gate,omitempty" yaml:"health_gate,omitempty"`
+	// DrainPolicy specifies how the drain phase handles blocked evictions
+	DrainPolicy *DrainPolicy `json:"drain_policy,omitempty" yaml:"drain_policy,omitempty"`
+}
+
+// HealthGateData describes the health checks evaluated after
+// a batch of nodes has been updated
+type HealthGateData struct {
+	// Servers lists the nodes updated in the batch
+	Servers []Server `json:"servers" yaml:"servers"`
 }
 
 // ElectionChange describes changes to make to cluster elections
@@ -251,6 +274,35 @@ func (c PlanChangelog) Latest(phaseID string) *PlanChange {
 	return latest
 }
 
+// PhaseTiming returns the execution timing of the specified phase derived
+// from its state changes: the start time of the most recent attempt, the time
+// this attempt has finished (zero if it is still running) and the total number
+// of attempts to execute or roll back the phase
+func (c PlanChangelog) PhaseTiming(phaseID string) (started, finished time.Time, attempts int) {
+	var changes []PlanChange
+	for _, change := range c {
+		if change.PhaseID == phaseID {
+			changes = append(changes, change)
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Created.Before(changes[j].Created)
+	})
+	for _, change := range changes {
+		switch change.NewState {
+		case OperationPhaseStateInProgress:
+			attempts++
+			started = change.Created
+			finished = time.Time{}
+		case OperationPhaseStateCompleted, OperationPhaseStateFailed, OperationPhaseStateRolledBack:
+			if !started.IsZero() {
+				finished = change.Created
+			}
+		}
+	}
+	return started, finished, attempts
+}
+
 // HasSubphases returns true if the phase has 1 or more subphases
 func (p OperationPhase) HasSubphases() bool {
 	return len(p.Phases) > 0
@@ -295,6 +347,73 @@ func (p OperationPhase) GetLastUpdateTime() time.Time {
 	return last
 }
 
+// GetStartTime returns the time the phase has started.
+// For a phase with subphases, this is the earliest start time of its subphases
+func (p OperationPhase) GetStartTime() time.Time {
+	if len(p.Phases) == 0 {
+		return p.Started
+	}
+	var first time.Time
+	for _, phase := range p.Phases {
+		started := phase.GetStartTime()
+		if !started.IsZero() && (first.IsZero() || started.Before(first)) {
+			first = started
+		}
+	}
+	return first
+}
+
+// GetFinishTime returns the time the phase has finished.
+// For a phase with subphases, this is the latest finish time of its subphases
+// or zero if any of the subphases has not finished yet
+func (p OperationPhase) GetFinishTime() time.Time {
+	if len(p.Phases) == 0 {
+		return p.Finished
+	}
+	var last time.Time
+	for _, phase := range p.Phases {
+		finished := phase.GetFinishTime()
+		if finished.IsZero() {
+			return time.Time{}
+		}
+		if finished.After(last) {
+			last = finished
+		}
+	}
+	return last
+}
+
+// GetDuration returns the duration of the most recent attempt to execute
+// the phase. If the phase is still running, the duration is computed
+// relative to the specified current time. Zero if the phase has not started
+func (p OperationPhase) GetDuration(now time.Time) time.Duration {
+	started := p.GetStartTime()
+	if started.IsZero() {
+		return 0
+	}
+	finished := p.GetFinishTime()
+	if finished.IsZero() {
+		finished = now
+	}
+	return finished.Sub(started)
+}
+
+// GetAttempts returns the number of times the phase has been executed
+// or rolled back. For a phase with subphases, this is the maximum number
+// of attempts among its subphases
+func (p OperationPhase) GetAttempts() int {
+	if len(p.Phases) == 0 {
+		return p.Attempts
+	}
+	var attempts int
+	for _, phase := range p.Phases {
+		if phase.GetAttempts() > attempts {
+			attempts = phase.GetAttempts()
+		}
+	}
+	return attempts
+}
+
 // GetState returns the phase state based on the states of all its subphases
 func (p OperationPhase) GetState() string {
 	// if the phase doesn't have subphases, then just return its state from property
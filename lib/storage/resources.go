@@ -73,6 +73,13 @@ const (
 	KindRelease = "release"
 	// KindInvite defines the user invite token.
 	KindInvite = "invite"
+	// KindBackupSchedule defines the resource that manages scheduled cluster backups
+	KindBackupSchedule = "backupschedule"
+	// KindMaintenanceWindow defines the resource that restricts when
+	// automatic cluster operations are allowed to start
+	KindMaintenanceWindow = "maintenancewindow"
+	// KindTrustedKey defines the resource with a public key trusted to sign packages
+	KindTrustedKey = "trustedkey"
 )
 
 // CanonicalKind translates the specified kind to canonical form.
@@ -105,6 +112,12 @@ func CanonicalKind(kind string) string {
 		return KindClusterConfiguration
 	case KindAuthGateway, "gw":
 		return KindAuthGateway
+	case KindBackupSchedule, "backupschedules", "backup":
+		return KindBackupSchedule
+	case KindMaintenanceWindow, "maintenancewindows", "mw":
+		return KindMaintenanceWindow
+	case KindTrustedKey, "trustedkeys":
+		return KindTrustedKey
 	}
 	return kind
 }
@@ -171,6 +184,9 @@ var SupportedGravityResources = []string{
 	KindAuthGateway,
 	KindRuntimeEnvironment,
 	KindClusterConfiguration,
+	KindBackupSchedule,
+	KindMaintenanceWindow,
+	KindTrustedKey,
 }
 
 // SupportedGravityResourcesToRemove is a list of resources supported by
@@ -186,6 +202,9 @@ var SupportedGravityResourcesToRemove = []string{
 	KindTLSKeyPair,
 	KindRuntimeEnvironment,
 	KindClusterConfiguration,
+	KindBackupSchedule,
+	KindMaintenanceWindow,
+	KindTrustedKey,
 }
 
 // MetadataSchema is a copy of teleport/lib/services.MetadataSchema but with
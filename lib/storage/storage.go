@@ -880,6 +880,8 @@ type Package struct {
 	Encrypted bool `json:"encrypted"`
 	// Manifest defines the application manifest for an application package
 	Manifest []byte `json:"manifest"`
+	// Signature is an optional detached signature of the package
+	Signature *PackageSignature `json:"signature,omitempty"`
 	// Base refers to the package this application is based on
 	Base *Package `json:"base,omitempty"`
 }
@@ -1337,6 +1339,58 @@ type Objects interface {
 	DeleteObject(hash string) error
 }
 
+// BLOBScrubReport describes the results of verifying the integrity
+// of the objects stored by a package service peer
+type BLOBScrubReport struct {
+	// PeerID is the ID of the peer that verified its objects
+	PeerID string `json:"peer_id"`
+	// Started is the time the verification started
+	Started time.Time `json:"started"`
+	// Completed is the time the verification completed
+	Completed time.Time `json:"completed"`
+	// Checked is the number of verified objects
+	Checked int `json:"checked"`
+	// Corrupted lists the objects that failed verification
+	Corrupted []string `json:"corrupted,omitempty"`
+	// Repaired lists the corrupted objects fetched again from other peers
+	Repaired []string `json:"repaired,omitempty"`
+}
+
+// Check validates the report
+func (r *BLOBScrubReport) Check() error {
+	if r.PeerID == "" {
+		return trace.BadParameter("missing parameter PeerID")
+	}
+	return nil
+}
+
+// Unrepaired returns the corrupted objects that could not be repaired
+func (r BLOBScrubReport) Unrepaired() (out []string) {
+	for _, hash := range r.Corrupted {
+		if !utils.StringInSlice(r.Repaired, hash) {
+			out = append(out, hash)
+		}
+	}
+	return out
+}
+
+func (r BLOBScrubReport) String() string {
+	return fmt.Sprintf("scrub(peer=%v, checked=%v, corrupted=%v, repaired=%v)",
+		r.PeerID, r.Checked, len(r.Corrupted), len(r.Repaired))
+}
+
+// BLOBScrubReports stores the results of object integrity verification
+type BLOBScrubReports interface {
+	// UpsertBLOBScrubReport creates or updates the report of the peer
+	UpsertBLOBScrubReport(BLOBScrubReport) error
+	// GetBLOBScrubReport returns the report of the peer with the specified ID
+	GetBLOBScrubReport(peerID string) (*BLOBScrubReport, error)
+	// GetBLOBScrubReports returns the reports of all peers
+	GetBLOBScrubReports() ([]BLOBScrubReport, error)
+	// DeleteBLOBScrubReport deletes the report of the peer with the specified ID
+	DeleteBLOBScrubReport(peerID string) error
+}
+
 const (
 	// NodeTypeNode is a type of teleport node - SSH Node
 	NodeTypeNode = "node"
@@ -1474,12 +1528,17 @@ type Backend interface {
 	Migrations
 	Peers
 	Objects
+	BLOBScrubReports
 	PackageChangesets
 	Links
 	ClusterImport
 	LegacyRoles
 	SystemMetadata
 	Charts
+	Backups
+	MaintenanceWindows
+	TrustedKeys
+	FleetOperations
 }
 
 const (
@@ -2000,14 +2059,97 @@ type UpdateOperationState struct {
 	ServerUpdates []ServerUpdate `json:"server_updates,omitempty"`
 	// Manual specifies whether this update operation was created in manual mode
 	Manual bool `json:"manual"`
+	// Strategy optionally defines how regular nodes are updated
+	Strategy *UpdateStrategy `json:"strategy,omitempty"`
+	// DrainPolicy optionally defines how node drains handle pods
+	// whose eviction is blocked by pod disruption budgets
+	DrainPolicy *DrainPolicy `json:"drain_policy,omitempty"`
 }
 
+// UpdateStrategy defines how regular cluster nodes are updated.
+//
+// The first CanaryNodes nodes are updated first, followed by the rest of
+// the nodes in batches of BatchSize nodes. Nodes within a batch are updated
+// concurrently. After each batch, the cluster health is evaluated and the
+// operation is paused if the cluster is not healthy
+type UpdateStrategy struct {
+	// CanaryNodes is the number of nodes to update first
+	CanaryNodes int `json:"canary_nodes,omitempty"`
+	// BatchSize is the number of nodes to update at the same time after
+	// canary nodes have been updated
+	BatchSize int `json:"batch_size,omitempty"`
+}
+
+// Check validates this strategy
+func (r UpdateStrategy) Check() error {
+	if r.CanaryNodes < 0 {
+		return trace.BadParameter("number of canary nodes cannot be negative")
+	}
+	if r.BatchSize < 1 {
+		return trace.BadParameter("batch size should be at least 1")
+	}
+	return nil
+}
+
+// String returns a textual representation of this strategy
+func (r UpdateStrategy) String() string {
+	return fmt.Sprintf("UpdateStrategy(CanaryNodes=%v, BatchSize=%v)",
+		r.CanaryNodes, r.BatchSize)
+}
+
+// DrainPolicy defines how a node drain handles pods whose eviction
+// is blocked by pod disruption budgets.
+//
+// Blocked evictions are retried for up to Timeout after which
+// Action is taken
+type DrainPolicy struct {
+	// Action is the action to take once Timeout has elapsed
+	Action DrainAction `json:"action,omitempty"`
+	// Timeout is how long to retry blocked evictions
+	Timeout time.Duration `json:"timeout,omitempty"`
+}
+
+// Check validates this policy
+func (r DrainPolicy) Check() error {
+	switch r.Action {
+	case DrainActionWait, DrainActionForce, DrainActionAbort:
+	default:
+		return trace.BadParameter("unsupported drain action %q, supported are: %v",
+			r.Action, []DrainAction{DrainActionWait, DrainActionForce, DrainActionAbort})
+	}
+	if r.Timeout < 0 {
+		return trace.BadParameter("drain timeout cannot be negative")
+	}
+	return nil
+}
+
+// String returns a textual representation of this policy
+func (r DrainPolicy) String() string {
+	return fmt.Sprintf("DrainPolicy(Action=%v, Timeout=%v)", r.Action, r.Timeout)
+}
+
+// DrainAction defines the action to take for pods whose eviction
+// remains blocked by pod disruption budgets
+type DrainAction string
+
+const (
+	// DrainActionWait keeps retrying blocked evictions until the drain times out
+	DrainActionWait DrainAction = "wait"
+	// DrainActionForce deletes the pods bypassing their disruption budgets
+	DrainActionForce DrainAction = "force"
+	// DrainActionAbort fails the drain
+	DrainActionAbort DrainAction = "abort"
+)
+
 // UpdateEnvarsOperationState describes the state of the operation to update cluster environment variables.
 type UpdateEnvarsOperationState struct {
 	// PrevEnv specifies the previous environment state
 	PrevEnv map[string]string `json:"prev_env,omitempty"`
 	// Env defines new cluster environment variables
 	Env map[string]string `json:"env,omitempty"`
+	// Concurrency specifies the maximum number of regular nodes
+	// updated at the same time
+	Concurrency int `json:"concurrency,omitempty"`
 }
 
 // Package returns the update package locator
@@ -2025,6 +2167,9 @@ type UpdateConfigOperationState struct {
 	PrevConfig []byte `json:"prev_config,omitempty"`
 	// Config specifies the raw configuration resource
 	Config []byte `json:"config,omitempty"`
+	// Concurrency specifies the maximum number of regular nodes
+	// updated at the same time
+	Concurrency int `json:"concurrency,omitempty"`
 }
 
 // ServerUpdate represents server that is being updated
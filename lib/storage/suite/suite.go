@@ -47,6 +47,11 @@ import (
 
 var now = time.Date(2015, 11, 16, 1, 2, 3, 0, time.UTC)
 
+// testPublicKey is an Ed25519 public key used in trusted key tests
+const testPublicKey = `-----BEGIN PUBLIC KEY-----
+MCowBQYDK2VwAyEAvlB5x4dUKAhZp5pIA6mHhfKClBJkM5KIF/JeKsIFbro=
+-----END PUBLIC KEY-----`
+
 type StorageSuite struct {
 	Backend storage.Backend
 	Clock   clockwork.FakeClock
@@ -1662,6 +1667,50 @@ func (s *StorageSuite) ObjectsCRUD(c *C) {
 	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%#v", err))
 }
 
+// BLOBScrubReportsCRUD tests object integrity report operations
+func (s *StorageSuite) BLOBScrubReportsCRUD(c *C) {
+	out, err := s.Backend.GetBLOBScrubReports()
+	c.Assert(err, IsNil)
+	c.Assert(len(out), Equals, 0)
+
+	_, err = s.Backend.GetBLOBScrubReport("p1")
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%#v", err))
+
+	r1 := storage.BLOBScrubReport{
+		PeerID:    "p1",
+		Started:   s.Clock.Now().UTC(),
+		Completed: s.Clock.Now().UTC().Add(time.Minute),
+		Checked:   3,
+		Corrupted: []string{"object1", "object2"},
+		Repaired:  []string{"object1"},
+	}
+	c.Assert(s.Backend.UpsertBLOBScrubReport(r1), IsNil)
+	r2 := storage.BLOBScrubReport{PeerID: "p2", Checked: 1}
+	c.Assert(s.Backend.UpsertBLOBScrubReport(r2), IsNil)
+
+	report, err := s.Backend.GetBLOBScrubReport("p1")
+	c.Assert(err, IsNil)
+	c.Assert(*report, DeepEquals, r1)
+	c.Assert(report.Unrepaired(), DeepEquals, []string{"object2"})
+
+	r1.Corrupted, r1.Repaired = nil, nil
+	c.Assert(s.Backend.UpsertBLOBScrubReport(r1), IsNil)
+
+	out, err = s.Backend.GetBLOBScrubReports()
+	c.Assert(err, IsNil)
+	c.Assert(out, DeepEquals, []storage.BLOBScrubReport{r1, r2})
+
+	c.Assert(s.Backend.DeleteBLOBScrubReport("p2"), IsNil)
+	out, err = s.Backend.GetBLOBScrubReports()
+	c.Assert(err, IsNil)
+	c.Assert(out, DeepEquals, []storage.BLOBScrubReport{r1})
+	err = s.Backend.DeleteBLOBScrubReport("p2")
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%#v", err))
+
+	err = s.Backend.UpsertBLOBScrubReport(storage.BLOBScrubReport{})
+	c.Assert(trace.IsBadParameter(err), Equals, true)
+}
+
 func (s *StorageSuite) ChangesetsCRUD(c *C) {
 	// Create
 	changeset := storage.PackageChangeset{
@@ -1957,6 +2006,200 @@ func (s *StorageSuite) IndexFile(c *C) {
 	compare.DeepCompare(c, retrievedFile, updatedIndex2)
 }
 
+// BackupsCRUD tests backup schedules and backup records operations
+func (s *StorageSuite) BackupsCRUD(c *C) {
+	schedules, err := s.Backend.GetBackupSchedules()
+	c.Assert(err, IsNil)
+	c.Assert(len(schedules), Equals, 0)
+
+	schedule := storage.NewBackupSchedule("nightly", storage.BackupScheduleSpecV2{
+		Schedule: "0 2 * * *",
+	})
+	err = s.Backend.UpsertBackupSchedule(schedule)
+	c.Assert(err, IsNil)
+
+	out, err := s.Backend.GetBackupSchedule(schedule.GetName())
+	c.Assert(err, IsNil)
+	c.Assert(out.GetSchedule(), Equals, "0 2 * * *")
+	c.Assert(out.GetRetention(), Equals, defaults.BackupRetention)
+
+	schedules, err = s.Backend.GetBackupSchedules()
+	c.Assert(err, IsNil)
+	c.Assert(len(schedules), Equals, 1)
+
+	backup1 := storage.Backup{
+		Name:        "nightly-1",
+		Schedule:    schedule.GetName(),
+		Application: loc.MustParseLocator("example.com/app:0.0.1"),
+		SHA512:      "hash1",
+		SizeBytes:   1,
+		Created:     now,
+	}
+	backup2 := storage.Backup{
+		Name:        "manual",
+		Application: loc.MustParseLocator("example.com/app:0.0.1"),
+		SHA512:      "hash2",
+		SizeBytes:   2,
+		Created:     now.Add(time.Hour),
+	}
+	for _, backup := range []storage.Backup{backup2, backup1} {
+		_, err = s.Backend.CreateBackup(backup)
+		c.Assert(err, IsNil)
+	}
+
+	_, err = s.Backend.CreateBackup(backup1)
+	c.Assert(trace.IsAlreadyExists(err), Equals, true, Commentf("%#v", err))
+
+	backups, err := s.Backend.GetBackups()
+	c.Assert(err, IsNil)
+	compare.DeepCompare(c, backups, []storage.Backup{backup1, backup2})
+
+	err = s.Backend.DeleteBackup(backup1.Name)
+	c.Assert(err, IsNil)
+
+	_, err = s.Backend.GetBackup(backup1.Name)
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%#v", err))
+
+	err = s.Backend.DeleteBackupSchedule(schedule.GetName())
+	c.Assert(err, IsNil)
+
+	_, err = s.Backend.GetBackupSchedule(schedule.GetName())
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%#v", err))
+}
+
+// MaintenanceWindowCRUD tests maintenance window operations
+func (s *StorageSuite) MaintenanceWindowCRUD(c *C) {
+	_, err := s.Backend.GetMaintenanceWindow()
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%#v", err))
+
+	window := storage.NewMaintenanceWindow("maintenance", storage.MaintenanceWindowSpecV2{
+		Windows: []storage.MaintenanceWindowSpec{
+			{Start: "0 22 * * *", Duration: teleservices.NewDuration(6 * time.Hour)},
+		},
+	})
+	err = s.Backend.UpsertMaintenanceWindow(window)
+	c.Assert(err, IsNil)
+
+	out, err := s.Backend.GetMaintenanceWindow()
+	c.Assert(err, IsNil)
+	c.Assert(out.GetName(), Equals, "maintenance")
+	c.Assert(out.GetWindows()[0].Start, Equals, "0 22 * * *")
+	c.Assert(out.GetPolicy(), Equals, storage.MaintenancePolicyRefuse)
+
+	err = s.Backend.DeleteMaintenanceWindow()
+	c.Assert(err, IsNil)
+
+	_, err = s.Backend.GetMaintenanceWindow()
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%#v", err))
+}
+
+// TrustedKeysCRUD tests trusted key operations
+func (s *StorageSuite) TrustedKeysCRUD(c *C) {
+	keys, err := s.Backend.GetTrustedKeys()
+	c.Assert(err, IsNil)
+	c.Assert(len(keys), Equals, 0)
+
+	key := storage.NewTrustedKey("release", storage.TrustedKeySpecV2{
+		PublicKey: testPublicKey,
+	})
+	err = s.Backend.UpsertTrustedKey(key)
+	c.Assert(err, IsNil)
+
+	out, err := s.Backend.GetTrustedKey(key.GetName())
+	c.Assert(err, IsNil)
+	c.Assert(string(out.GetPublicKey()), Equals, testPublicKey)
+
+	err = s.Backend.UpsertTrustedKey(storage.NewTrustedKey("invalid", storage.TrustedKeySpecV2{
+		PublicKey: "not a key",
+	}))
+	c.Assert(trace.IsBadParameter(err), Equals, true, Commentf("%#v", err))
+
+	keys, err = s.Backend.GetTrustedKeys()
+	c.Assert(err, IsNil)
+	c.Assert(len(keys), Equals, 1)
+
+	err = s.Backend.DeleteTrustedKey(key.GetName())
+	c.Assert(err, IsNil)
+
+	_, err = s.Backend.GetTrustedKey(key.GetName())
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%#v", err))
+}
+
+// FleetOperationsCRUD tests fleet operations
+func (s *StorageSuite) FleetOperationsCRUD(c *C) {
+	ops, err := s.Backend.GetFleetOperations("account1")
+	c.Assert(err, IsNil)
+	c.Assert(len(ops), Equals, 0)
+
+	op1 := storage.FleetOperation{
+		ID:          "op1",
+		AccountID:   "account1",
+		Type:        "update",
+		Created:     now,
+		Updated:     now,
+		State:       "created",
+		Selector:    map[string]string{"env": "staging"},
+		App:         "example.com/app:0.0.2",
+		WaveSize:    1,
+		Concurrency: 1,
+		Clusters: []storage.FleetCluster{
+			{Name: "a.example.com", Wave: 0, State: "pending"},
+			{Name: "b.example.com", Wave: 1, State: "pending"},
+		},
+	}
+	op2 := storage.FleetOperation{
+		ID:        "op2",
+		AccountID: "account1",
+		Type:      "update_config",
+		Created:   now.Add(time.Hour),
+		Updated:   now.Add(time.Hour),
+		State:     "created",
+		Config:    []byte(`{"kind":"ClusterConfiguration"}`),
+	}
+	op3 := storage.FleetOperation{
+		ID:        "op3",
+		AccountID: "account2",
+		Type:      "update",
+		Created:   now,
+		Updated:   now,
+	}
+	for _, op := range []storage.FleetOperation{op1, op2, op3} {
+		_, err = s.Backend.CreateFleetOperation(op)
+		c.Assert(err, IsNil)
+	}
+
+	_, err = s.Backend.CreateFleetOperation(op1)
+	c.Assert(trace.IsAlreadyExists(err), Equals, true, Commentf("%#v", err))
+
+	out, err := s.Backend.GetFleetOperation(op1.ID)
+	c.Assert(err, IsNil)
+	compare.DeepCompare(c, out, &op1)
+
+	ops, err = s.Backend.GetFleetOperations("account1")
+	c.Assert(err, IsNil)
+	compare.DeepCompare(c, ops, []storage.FleetOperation{op2, op1})
+
+	op1.State = "in_progress"
+	op1.Clusters[0].State = "in_progress"
+	op1.Clusters[0].OperationID = "cluster-op1"
+	_, err = s.Backend.UpdateFleetOperation(op1)
+	c.Assert(err, IsNil)
+
+	out, err = s.Backend.GetFleetOperation(op1.ID)
+	c.Assert(err, IsNil)
+	c.Assert(out.State, Equals, "in_progress")
+	compare.DeepCompare(c, out.Clusters, op1.Clusters)
+
+	err = s.Backend.DeleteFleetOperation(op1.ID)
+	c.Assert(err, IsNil)
+
+	_, err = s.Backend.GetFleetOperation(op1.ID)
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%#v", err))
+
+	_, err = s.Backend.UpdateFleetOperation(op1)
+	c.Assert(trace.IsNotFound(err), Equals, true, Commentf("%#v", err))
+}
+
 func newIndex() *repo.IndexFile {
 	return &repo.IndexFile{
 		APIVersion: repo.APIVersionV1,
@@ -0,0 +1,200 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/gravitational/gravity/lib/defaults"
+
+	teleservices "github.com/gravitational/teleport/lib/services"
+	teleutils "github.com/gravitational/teleport/lib/utils"
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+)
+
+// TrustedKey is a public key trusted to sign packages pulled into the cluster
+type TrustedKey interface {
+	// Resource provides common resource methods
+	teleservices.Resource
+	// CheckAndSetDefaults validates the key and sets defaults
+	CheckAndSetDefaults() error
+	// GetPublicKey returns the PEM-encoded public key
+	GetPublicKey() []byte
+}
+
+// NewTrustedKey creates a new trusted key resource
+func NewTrustedKey(name string, spec TrustedKeySpecV2) TrustedKey {
+	return &TrustedKeyV2{
+		Kind:    KindTrustedKey,
+		Version: teleservices.V2,
+		Metadata: teleservices.Metadata{
+			Name:      name,
+			Namespace: defaults.Namespace,
+		},
+		Spec: spec,
+	}
+}
+
+// TrustedKeyV2 represents the trusted key resource
+type TrustedKeyV2 struct {
+	// Kind is the resource kind, "trustedkey"
+	Kind string `json:"kind"`
+	// Version is the resource version, "v2"
+	Version string `json:"version"`
+	// Metadata contains the key metadata
+	Metadata teleservices.Metadata `json:"metadata"`
+	// Spec is the trusted key spec
+	Spec TrustedKeySpecV2 `json:"spec"`
+}
+
+// TrustedKeySpecV2 defines the trusted key
+type TrustedKeySpecV2 struct {
+	// PublicKey is the PEM-encoded public key used to verify package signatures
+	PublicKey string `json:"public_key"`
+}
+
+// GetName returns the key name
+func (k *TrustedKeyV2) GetName() string {
+	return k.Metadata.Name
+}
+
+// SetName sets the key name
+func (k *TrustedKeyV2) SetName(name string) {
+	k.Metadata.Name = name
+}
+
+// GetMetadata returns the key metadata
+func (k *TrustedKeyV2) GetMetadata() teleservices.Metadata {
+	return k.Metadata
+}
+
+// SetExpiry sets the key expiration time
+func (k *TrustedKeyV2) SetExpiry(expires time.Time) {
+	k.Metadata.SetExpiry(expires)
+}
+
+// Expiry returns the key expiration time
+func (k *TrustedKeyV2) Expiry() time.Time {
+	return k.Metadata.Expiry()
+}
+
+// SetTTL sets the key TTL
+func (k *TrustedKeyV2) SetTTL(clock clockwork.Clock, ttl time.Duration) {
+	k.Metadata.SetTTL(clock, ttl)
+}
+
+// GetPublicKey returns the PEM-encoded public key
+func (k *TrustedKeyV2) GetPublicKey() []byte {
+	return []byte(k.Spec.PublicKey)
+}
+
+// CheckAndSetDefaults validates the key and sets defaults
+func (k *TrustedKeyV2) CheckAndSetDefaults() error {
+	if k.Metadata.Name == "" {
+		return trace.BadParameter("missing parameter Name")
+	}
+	if k.Spec.PublicKey == "" {
+		return trace.BadParameter("missing parameter PublicKey")
+	}
+	block, _ := pem.Decode([]byte(k.Spec.PublicKey))
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return trace.BadParameter("public key should be a PEM-encoded PUBLIC KEY block")
+	}
+	return nil
+}
+
+// TrustedKeySpecV2Schema is JSON schema for the trusted key spec
+const TrustedKeySpecV2Schema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["public_key"],
+  "properties": {
+    "public_key": {"type": "string"}
+  }
+}`
+
+// GetTrustedKeySchema returns the trusted key schema for version V2
+func GetTrustedKeySchema() string {
+	return fmt.Sprintf(teleservices.V2SchemaTemplate, teleservices.MetadataSchema,
+		TrustedKeySpecV2Schema, "")
+}
+
+// UnmarshalTrustedKey unmarshals trusted key from JSON or YAML
+func UnmarshalTrustedKey(data []byte) (TrustedKey, error) {
+	if len(data) == 0 {
+		return nil, trace.BadParameter("missing trusted key data")
+	}
+	jsonData, err := teleutils.ToJSON(data)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var header teleservices.ResourceHeader
+	err = json.Unmarshal(jsonData, &header)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	switch header.Version {
+	case teleservices.V2:
+		var key TrustedKeyV2
+		err := teleutils.UnmarshalWithSchema(GetTrustedKeySchema(), &key, jsonData)
+		if err != nil {
+			return nil, trace.BadParameter(err.Error())
+		}
+		key.Metadata.CheckAndSetDefaults()
+		if err := key.CheckAndSetDefaults(); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return &key, nil
+	}
+	return nil, trace.BadParameter(
+		"%v resource version %q is not supported", KindTrustedKey, header.Version)
+}
+
+// MarshalTrustedKey marshals trusted key into JSON
+func MarshalTrustedKey(key TrustedKey, opts ...teleservices.MarshalOption) ([]byte, error) {
+	return json.Marshal(key)
+}
+
+// PackageSignature is a detached signature of a package
+type PackageSignature struct {
+	// KeyID identifies the key that produced the signature
+	KeyID string `json:"key_id"`
+	// Signature is the signature of the package locator and checksum
+	Signature []byte `json:"signature"`
+}
+
+// String returns the signature's textual representation
+func (s PackageSignature) String() string {
+	return fmt.Sprintf("PackageSignature(KeyID=%v)", s.KeyID)
+}
+
+// TrustedKeys defines the interface to manage keys trusted
+// to sign packages
+type TrustedKeys interface {
+	// UpsertTrustedKey creates or updates the trusted key
+	UpsertTrustedKey(TrustedKey) error
+	// GetTrustedKey returns the trusted key by name
+	GetTrustedKey(name string) (TrustedKey, error)
+	// GetTrustedKeys returns all trusted keys
+	GetTrustedKeys() ([]TrustedKey, error)
+	// DeleteTrustedKey deletes the trusted key by name
+	DeleteTrustedKey(name string) error
+}
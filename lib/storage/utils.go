@@ -279,6 +279,8 @@ func DeepComparePhases(c *check.C, expected, actual OperationPhase) {
 		check.Commentf("field Requires on phase %v does not match", expected.ID))
 	c.Assert(expected.Parallel, check.Equals, actual.Parallel,
 		check.Commentf("field Parallel on phase %v does not match", expected.ID))
+	c.Assert(expected.Concurrency, check.Equals, actual.Concurrency,
+		check.Commentf("field Concurrency on phase %v does not match", expected.ID))
 	c.Assert(expected.Data, check.DeepEquals, actual.Data,
 		check.Commentf("field Data on phase %v does not match: %v", expected.ID,
 			compare.Diff(expected.Data, actual.Data)))
@@ -38,6 +38,15 @@ func (p *Phase) AddParallel(subs ...Phase) {
 	p.Add(subs...)
 }
 
+// SetConcurrency marks this phase for concurrent execution of its sub-phases
+// with at most concurrency sub-phases running at the same time.
+// Zero concurrency means no limit
+func (p *Phase) SetConcurrency(concurrency int) *Phase {
+	p.Parallel = true
+	p.Concurrency = concurrency
+	return p
+}
+
 // Add adds the specified sub-phases without dependency
 func (p *Phase) Add(subs ...Phase) {
 	p.Phases = append(p.Phases, Phases(subs).AsPhases()...)
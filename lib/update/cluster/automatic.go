@@ -71,7 +71,12 @@ func AutomaticUpgrade(ctx context.Context, localEnv, updateEnv *localenv.LocalEn
 	defer fsm.Close()
 
 	force := false
-	fsmErr := fsm.Run(ctx, force)
+	fsmErr := ops.WaitForMaintenanceWindow(ctx, clusterEnv.Operator,
+		ops.SiteKey{AccountID: operation.AccountID, SiteDomain: operation.SiteDomain},
+		ops.MaintenanceUpdate, log.StandardLogger())
+	if fsmErr == nil {
+		fsmErr = fsm.Run(ctx, force)
+	}
 	if fsmErr != nil {
 		log.Warnf("Failed to execute plan: %v.", fsmErr)
 		// fallthrough
@@ -296,15 +296,101 @@ func (r phaseBuilder) nodes(leadMaster storage.UpdateServer, nodes []storage.Upd
 		Description: "Update regular nodes",
 	})
 
-	for i, server := range nodes {
-		node := r.node(server.Server, &root, "Update system software on node %q")
-		node.AddSequential(r.commonNode(nodes[i], leadMaster, supportsTaints,
-			waitsForEndpoints(true))...)
-		root.AddParallel(node)
+	var strategy *storage.UpdateStrategy
+	if r.operation.Update != nil {
+		strategy = r.operation.Update.Strategy
+	}
+	if strategy == nil {
+		for i, server := range nodes {
+			node := r.node(server.Server, &root, "Update system software on node %q")
+			node.AddSequential(r.commonNode(nodes[i], leadMaster, supportsTaints,
+				waitsForEndpoints(true))...)
+			root.AddParallel(node)
+		}
+		return &root
+	}
+
+	for _, batch := range splitBatches(nodes, *strategy) {
+		phase := update.Phase{
+			ID:          root.ChildLiteral(batch.name),
+			Description: batch.description,
+		}
+		var servers []storage.Server
+		for i, server := range batch.nodes {
+			node := r.node(server.Server, &phase, "Update system software on node %q")
+			node.AddSequential(r.commonNode(batch.nodes[i], leadMaster, supportsTaints,
+				waitsForEndpoints(true))...)
+			phase.AddParallel(node)
+			servers = append(servers, server.Server)
+		}
+		phase.SetConcurrency(len(batch.nodes))
+		root.AddSequential(phase, update.Phase{
+			ID:          root.ChildLiteral(fmt.Sprintf("%v-health", batch.name)),
+			Executor:    healthGate,
+			Description: fmt.Sprintf("Verify cluster health after %v", strings.ToLower(batch.description)),
+			Data: &storage.OperationPhaseData{
+				ExecServer: &leadMaster.Server,
+				HealthGate: &storage.HealthGateData{Servers: servers},
+			},
+		})
 	}
 	return &root
 }
 
+// drainPolicy returns the policy for handling blocked evictions
+// configured for the operation
+func (r phaseBuilder) drainPolicy() *storage.DrainPolicy {
+	if r.operation.Update == nil {
+		return nil
+	}
+	return r.operation.Update.DrainPolicy
+}
+
+// splitBatches splits the nodes into batches according to the update strategy:
+// the canary batch (if requested) followed by batches of the configured size
+func splitBatches(nodes []storage.UpdateServer, strategy storage.UpdateStrategy) (batches []nodeBatch) {
+	if strategy.CanaryNodes > 0 && len(nodes) != 0 {
+		count := strategy.CanaryNodes
+		if count > len(nodes) {
+			count = len(nodes)
+		}
+		batches = append(batches, nodeBatch{
+			name:        "canary",
+			description: "Update canary nodes",
+			nodes:       nodes[:count],
+		})
+		nodes = nodes[count:]
+	}
+	size := strategy.BatchSize
+	if size <= 0 {
+		size = len(nodes)
+	}
+	for i := 0; len(nodes) != 0; i++ {
+		count := size
+		if count > len(nodes) {
+			count = len(nodes)
+		}
+		batches = append(batches, nodeBatch{
+			name:        fmt.Sprintf("batch-%v", i+1),
+			description: fmt.Sprintf("Update batch %v of nodes", i+1),
+			nodes:       nodes[:count],
+		})
+		nodes = nodes[count:]
+	}
+	return batches
+}
+
+// nodeBatch is a group of nodes updated together before the cluster
+// health is verified
+type nodeBatch struct {
+	// name is the batch phase name
+	name string
+	// description is the batch phase description
+	description string
+	// nodes lists the nodes in the batch
+	nodes []storage.UpdateServer
+}
+
 func (r phaseBuilder) etcdPlan(
 	leadMaster storage.Server,
 	otherMasters []storage.Server,
@@ -484,8 +570,9 @@ func (r phaseBuilder) commonNode(server, leadMaster storage.UpdateServer, suppor
 			Executor:    drainNode,
 			Description: fmt.Sprintf("Drain node %q", server.Hostname),
 			Data: &storage.OperationPhaseData{
-				Server:     &server.Server,
-				ExecServer: &leadMaster.Server,
+				Server:      &server.Server,
+				ExecServer:  &leadMaster.Server,
+				DrainPolicy: r.drainPolicy(),
 			}},
 		{
 			ID:          "system-upgrade",
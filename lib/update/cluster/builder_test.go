@@ -17,11 +17,15 @@ limitations under the License.
 package cluster
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/gravitational/gravity/lib/app"
 	apptest "github.com/gravitational/gravity/lib/app/service/test"
 	"github.com/gravitational/gravity/lib/archive"
 	"github.com/gravitational/gravity/lib/compare"
 	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/fsm"
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/ops/opsservice"
@@ -269,6 +273,184 @@ func (s *PlanSuite) TestCorrectlyDeterminesWhetherToUpdateEtcd(c *check.C) {
 	c.Assert(updateVersion, check.Equals, "3.3.3")
 }
 
+func (s *PlanSuite) TestUpdatesNodesInBatches(c *check.C) {
+	leadMaster := storage.UpdateServer{Server: storage.Server{
+		AdvertiseIP: "192.168.0.1",
+		Hostname:    "node-1",
+		ClusterRole: string(schema.ServiceRoleMaster),
+	}}
+	var nodes []storage.UpdateServer
+	for i := 2; i <= 5; i++ {
+		nodes = append(nodes, storage.UpdateServer{Server: storage.Server{
+			AdvertiseIP: fmt.Sprintf("192.168.0.%v", i),
+			Hostname:    fmt.Sprintf("node-%v", i),
+			ClusterRole: string(schema.ServiceRoleNode),
+		}})
+	}
+	builder := phaseBuilder{planConfig: planConfig{
+		operation: storage.SiteOperation{
+			Update: &storage.UpdateOperationState{
+				Strategy: &storage.UpdateStrategy{CanaryNodes: 1, BatchSize: 2},
+			},
+		},
+	}}
+
+	plan := storage.OperationPlan{Phases: update.Phases{
+		*builder.nodes(leadMaster, nodes, false),
+	}.AsPhases()}
+	update.ResolvePlan(&plan)
+
+	root := plan.Phases[0]
+	type batch struct {
+		id          string
+		requires    []string
+		concurrency int
+		nodes       []string
+	}
+	var batches []batch
+	for _, phase := range root.Phases {
+		b := batch{id: phase.ID, requires: phase.Requires, concurrency: phase.Concurrency}
+		if phase.Executor == healthGate {
+			for _, server := range phase.Data.HealthGate.Servers {
+				b.nodes = append(b.nodes, server.Hostname)
+			}
+		} else {
+			c.Assert(phase.Parallel, check.Equals, true)
+			for _, node := range phase.Phases {
+				b.nodes = append(b.nodes, node.ID)
+			}
+		}
+		batches = append(batches, b)
+	}
+	c.Assert(batches, check.DeepEquals, []batch{
+		{
+			id:          "/nodes/canary",
+			concurrency: 1,
+			nodes:       []string{"/nodes/canary/node-2"},
+		},
+		{
+			id:       "/nodes/canary-health",
+			requires: []string{"/nodes/canary"},
+			nodes:    []string{"node-2"},
+		},
+		{
+			id:          "/nodes/batch-1",
+			requires:    []string{"/nodes/canary-health"},
+			concurrency: 2,
+			nodes:       []string{"/nodes/batch-1/node-3", "/nodes/batch-1/node-4"},
+		},
+		{
+			id:       "/nodes/batch-1-health",
+			requires: []string{"/nodes/batch-1"},
+			nodes:    []string{"node-3", "node-4"},
+		},
+		{
+			id:          "/nodes/batch-2",
+			requires:    []string{"/nodes/batch-1-health"},
+			concurrency: 1,
+			nodes:       []string{"/nodes/batch-2/node-5"},
+		},
+		{
+			id:       "/nodes/batch-2-health",
+			requires: []string{"/nodes/batch-2"},
+			nodes:    []string{"node-5"},
+		},
+	})
+}
+
+func (s *PlanSuite) TestDrainPhasesUseOperationDrainPolicy(c *check.C) {
+	leadMaster := storage.UpdateServer{Server: storage.Server{
+		AdvertiseIP: "192.168.0.1",
+		Hostname:    "node-1",
+		ClusterRole: string(schema.ServiceRoleMaster),
+	}}
+	node := storage.UpdateServer{Server: storage.Server{
+		AdvertiseIP: "192.168.0.2",
+		Hostname:    "node-2",
+		ClusterRole: string(schema.ServiceRoleNode),
+	}}
+	policy := &storage.DrainPolicy{Action: storage.DrainActionAbort, Timeout: time.Minute}
+	builder := phaseBuilder{planConfig: planConfig{
+		operation: storage.SiteOperation{
+			Update: &storage.UpdateOperationState{DrainPolicy: policy},
+		},
+	}}
+
+	plan := storage.OperationPlan{Phases: update.Phases{
+		*builder.nodes(leadMaster, []storage.UpdateServer{node}, false),
+	}.AsPhases()}
+	update.ResolvePlan(&plan)
+
+	var drains int
+	for _, phase := range fsm.FlattenPlan(&plan) {
+		if phase.Executor == drainNode {
+			c.Assert(phase.Data.DrainPolicy, check.DeepEquals, policy)
+			drains++
+		}
+	}
+	c.Assert(drains, check.Equals, 1)
+}
+
+func (s *PlanSuite) TestPlanImpact(c *check.C) {
+	services := opsservice.SetupTestServices(c)
+	apptest.CreatePackage(services.Packages, loc.MustParseLocator("gravitational.io/planet:0.0.1"), nil, c)
+	apptest.CreateRuntimeApplication(services.Apps, c)
+	appLoc := loc.MustParseLocator("gravitational.io/app:2.0.0")
+	apptest.CreateApplicationFromData(services.Apps, appLoc, []*archive.Item{
+		archive.DirItem("resources"),
+		archive.ItemFromString("resources/app.yaml", updateHooksManifest),
+	}, c)
+	master := storage.Server{
+		AdvertiseIP: "192.168.0.1",
+		Hostname:    "node-1",
+		Role:        "node",
+		ClusterRole: string(schema.ServiceRoleMaster),
+	}
+	secretsLoc := loc.MustParseLocator("gravitational.io/planet-secrets:0.0.2")
+	configLoc := loc.MustParseLocator("gravitational.io/planet-config:0.0.2")
+	teleportLoc := loc.MustParseLocator("gravitational.io/teleport-node-config:0.0.2")
+	updates := &storage.UpdateOperationData{
+		Servers: []storage.UpdateServer{{
+			Server: master,
+			Runtime: storage.RuntimePackage{
+				SecretsPackage: &secretsLoc,
+				Update:         &storage.RuntimeUpdate{ConfigPackage: configLoc},
+			},
+			Teleport: storage.TeleportPackage{
+				Update: &storage.TeleportUpdate{NodeConfigPackage: teleportLoc},
+			},
+		}},
+	}
+	plan := storage.OperationPlan{
+		Phases: []storage.OperationPhase{
+			{ID: "/init", Executor: updateInit, Data: &storage.OperationPhaseData{Update: updates}},
+			{ID: "/pre-update", Executor: preUpdate, Data: &storage.OperationPhaseData{Package: &appLoc}},
+			{ID: "/masters", Phases: []storage.OperationPhase{
+				{ID: "/masters/node-1/drain", Executor: drainNode, Data: &storage.OperationPhaseData{Server: &master}},
+				{ID: "/masters/node-1/config", Executor: config, Data: &storage.OperationPhaseData{Update: updates}},
+			}},
+			{ID: "/app", Phases: []storage.OperationPhase{
+				{ID: "/app/app", Executor: updateApp, Data: &storage.OperationPhaseData{Package: &appLoc}},
+			}},
+		},
+	}
+
+	impact, err := GetPlanImpact(plan, services.Apps)
+	c.Assert(err, check.IsNil)
+	c.Assert(impact, compare.DeepEquals, &fsm.PlanImpact{
+		DrainedNodes: []storage.Server{master},
+		RotatedPackages: []fsm.RotatedPackage{
+			{Server: master, Package: secretsLoc},
+			{Server: master, Package: configLoc},
+			{Server: master, Package: teleportLoc},
+		},
+		Hooks: []fsm.PlanHook{
+			{Phase: "/pre-update", Package: appLoc, Hook: schema.HookBeforeUpdate},
+			{Phase: "/app/app", Package: appLoc, Hook: schema.HookUpdate},
+		},
+	})
+}
+
 func newTestPlan(c *check.C, p params) planConfig {
 	runtimeLoc := loc.MustParseLocator("gravitational.io/planet:2.0.0")
 	servers := []storage.Server{
@@ -476,3 +658,40 @@ systemOptions:
   dependencies:
     runtimePackage: gravitational.io/planet:2.0.0
 `
+
+const updateHooksManifest = `apiVersion: bundle.gravitational.io/v2
+kind: Bundle
+metadata:
+  name: app
+  resourceVersion: 2.0.0
+systemOptions:
+  runtime:
+    version: 0.0.1
+hooks:
+  preUpdate:
+    job: |
+      apiVersion: batch/v1
+      kind: Job
+      metadata:
+        name: pre-update
+      spec:
+        template:
+          spec:
+            containers:
+              - name: hook
+                image: quay.io/gravitational/debian-tall:0.0.1
+                command: ["/bin/echo", "Pre-update hook"]
+  update:
+    job: |
+      apiVersion: batch/v1
+      kind: Job
+      metadata:
+        name: update
+      spec:
+        template:
+          spec:
+            containers:
+              - name: hook
+                image: quay.io/gravitational/debian-tall:0.0.1
+                command: ["/bin/echo", "Update hook"]
+`
@@ -27,6 +27,7 @@ import (
 	"github.com/gravitational/gravity/lib/fsm"
 	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/pack"
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/update"
 	"github.com/gravitational/gravity/lib/users"
@@ -107,6 +108,7 @@ func newMachine(ctx context.Context, c Config) (*fsm.FSM, error) {
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	fsm.SetProgressHook(engine.reportProgress)
 	return fsm, nil
 }
 
@@ -169,6 +171,17 @@ func (f *engine) RunCommand(ctx context.Context, runner fsm.RemoteRunner, server
 	return runner.Run(ctx, server, args...)
 }
 
+// reportProgress creates a progress entry in the operator for the progress
+// reported by the phase executing on the specified server
+func (f *engine) reportProgress(ctx context.Context, p fsm.Params, server storage.Server, progress pb.Progress) error {
+	phase, err := fsm.FindPhase(&f.plan, p.PhaseID)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	entry := fsm.RemoteProgressEntry(f.plan, *phase, server, progress)
+	return trace.Wrap(f.Operator.CreateProgressEntry(f.Operation.Key(), entry))
+}
+
 // PreExecute is no-op for the update engine
 func (f *engine) PreExecute(ctx context.Context, p fsm.Params) error {
 	return nil
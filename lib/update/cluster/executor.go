@@ -53,6 +53,8 @@ const (
 	uncordonNode = "uncordon_node"
 	// endpoints is the phase to wait for system service endpoints
 	endpoints = "endpoints"
+	// healthGate is the phase to verify cluster health after a batch of nodes has been updated
+	healthGate = "health_gate"
 	// config is the phase that updates system configuration
 	config = "config"
 	// kubeletPermissions is the phase to add kubelet permissions
@@ -129,11 +131,13 @@ func fsmSpec(c Config) fsm.FSMSpecFunc {
 		case untaintNode:
 			return libphase.NewPhaseUntaint(p, c.Client, logger)
 		case drainNode:
-			return libphase.NewPhaseDrain(p, c.Client, logger)
+			return libphase.NewPhaseDrain(p, c.Operator, c.Client, logger)
 		case uncordonNode:
 			return libphase.NewPhaseUncordon(p, c.Client, logger)
 		case endpoints:
 			return libphase.NewPhaseEndpoints(p, c.Client, logger)
+		case healthGate:
+			return libphase.NewPhaseHealthGate(p, c.Operator, c.Apps, c.Client, logger)
 		case config:
 			return libphase.NewUpdatePhaseConfig(p, c.Operator, c.ClusterPackages, c.HostLocalPackages, remote, logger)
 		case kubeletPermissions:
@@ -28,6 +28,7 @@ import (
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/pack/signing"
 	"github.com/gravitational/gravity/lib/state"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/utils"
@@ -73,6 +74,9 @@ type updatePhaseBootstrap struct {
 	// ExecutorParams stores the phase parameters
 	fsm.ExecutorParams
 	remote fsm.Remote
+	// verifier verifies signatures of the pulled packages.
+	// Nil if the cluster does not trust any signing keys
+	verifier pack.Verifier
 }
 
 // NewUpdatePhaseBootstrap creates a new bootstrap phase executor
@@ -103,6 +107,14 @@ func NewUpdatePhaseBootstrap(
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	keys, err := operator.GetTrustedKeys(cluster.Key())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	verifier, err := signing.NewClusterVerifier(keys, cluster.Domain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
 	return &updatePhaseBootstrap{
 		Operator:         operator,
 		Backend:          backend,
@@ -118,6 +130,7 @@ func NewUpdatePhaseBootstrap(
 		FieldLogger:      logger,
 		ExecutorParams:   p,
 		remote:           remote,
+		verifier:         verifier,
 	}, nil
 }
 
@@ -190,8 +203,20 @@ func (p *updatePhaseBootstrap) configureNode() error {
 func (p *updatePhaseBootstrap) exportGravity(ctx context.Context) error {
 	p.Infof("Export gravity binary to %v.", p.GravityPath)
 	err := utils.CopyWithRetries(ctx, p.GravityPath, func() (io.ReadCloser, error) {
-		_, rc, err := p.Packages.ReadPackage(p.Plan.GravityPackage)
-		return rc, trace.Wrap(err)
+		env, rc, err := p.Packages.ReadPackage(p.Plan.GravityPackage)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if p.verifier == nil {
+			return rc, nil
+		}
+		if err := p.verifier.Verify(*env); err != nil {
+			rc.Close()
+			return nil, trace.Wrap(err)
+		}
+		// the binary is only moved into place if its contents
+		// match the checksum the signature has been verified for
+		return signing.NewVerifyingReader(*env, rc), nil
 	}, defaults.SharedExecutableMask)
 	return trace.Wrap(err)
 }
@@ -237,9 +262,10 @@ func (p *updatePhaseBootstrap) pullSystemUpdates() error {
 	for _, update := range updates {
 		p.Infof("Pulling package update: %v.", update)
 		_, err := appservice.PullPackage(appservice.PackagePullRequest{
-			SrcPack: p.Packages,
-			DstPack: p.LocalPackages,
-			Package: update,
+			SrcPack:  p.Packages,
+			DstPack:  p.LocalPackages,
+			Package:  update,
+			Verifier: p.verifier,
 		})
 		if err != nil && !trace.IsAlreadyExists(err) {
 			return trace.Wrap(err)
@@ -0,0 +1,233 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package phases
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/gravitational/gravity/lib/app"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/fsm"
+	"github.com/gravitational/gravity/lib/kubernetes"
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/schema"
+	"github.com/gravitational/gravity/lib/status"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/update"
+
+	"github.com/gravitational/satellite/agent/proto/agentpb"
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	kubeapi "k8s.io/client-go/kubernetes"
+)
+
+// phaseHealthGate is the executor that verifies the cluster health after
+// a batch of nodes has been updated
+type phaseHealthGate struct {
+	// Apps is the cluster apps service
+	Apps app.Applications
+	// Client is the cluster Kubernetes client
+	Client *kubeapi.Clientset
+	// GravityPackage is the gravity binary package to run the status hook with
+	GravityPackage loc.Locator
+	// Package is the cluster application package
+	Package loc.Locator
+	// ServiceUser is the user used for services and system storage
+	ServiceUser storage.OSUser
+	// Servers is the list of local cluster servers
+	Servers []storage.Server
+	// Batch is the list of servers updated in the batch
+	Batch []storage.Server
+	log.FieldLogger
+}
+
+// NewPhaseHealthGate returns a new executor for the health gate phase
+func NewPhaseHealthGate(
+	p fsm.ExecutorParams,
+	operator ops.Operator,
+	apps app.Applications,
+	client *kubeapi.Clientset,
+	logger log.FieldLogger,
+) (*phaseHealthGate, error) {
+	if p.Phase.Data == nil || p.Phase.Data.HealthGate == nil {
+		return nil, trace.NotFound("no servers specified for phase %q", p.Phase.ID)
+	}
+	cluster, err := operator.GetLocalSite()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &phaseHealthGate{
+		Apps:           apps,
+		Client:         client,
+		GravityPackage: p.Plan.GravityPackage,
+		Package:        cluster.App.Package,
+		ServiceUser:    cluster.ServiceUser,
+		Servers:        p.Plan.Servers,
+		Batch:          p.Phase.Data.HealthGate.Servers,
+		FieldLogger:    logger,
+	}, nil
+}
+
+// Execute waits for the updated nodes and the cluster to become healthy.
+// If the cluster does not become healthy within the allotted time, the
+// phase fails which pauses the operation until the problem is fixed
+// and the operation is resumed or rolled back
+func (p *phaseHealthGate) Execute(ctx context.Context) error {
+	var lastErr error
+	err := update.Retry(ctx, func() error {
+		lastErr = p.checkHealth(ctx)
+		if lastErr != nil {
+			p.Debugf("Health check failed: %v.", lastErr)
+		}
+		return trace.Wrap(lastErr)
+	}, defaults.HealthGateTimeout)
+	if err != nil {
+		if lastErr != nil {
+			err = lastErr
+		}
+		return trace.Wrap(err, "cluster is not healthy after updating %v, "+
+			"the operation has been paused: fix the issue and resume the "+
+			"operation with 'gravity plan resume' or roll it back", formatServers(p.Batch))
+	}
+	p.Infof("Cluster is healthy after updating %v.", formatServers(p.Batch))
+	return nil
+}
+
+// Rollback is a no-op for this phase
+func (p *phaseHealthGate) Rollback(context.Context) error {
+	return nil
+}
+
+// PreCheck makes sure this phase is being executed on a master node
+func (p *phaseHealthGate) PreCheck(context.Context) error {
+	return trace.Wrap(fsm.CheckMasterServer(p.Servers))
+}
+
+// PostCheck is no-op for this phase
+func (p *phaseHealthGate) PostCheck(context.Context) error {
+	return nil
+}
+
+func (p *phaseHealthGate) checkHealth(ctx context.Context) error {
+	if err := p.checkNodesReady(); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := p.checkAgentStatus(ctx); err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(p.checkStatusHook(ctx))
+}
+
+// checkNodesReady verifies that Kubernetes nodes of the batch are ready
+func (p *phaseHealthGate) checkNodesReady() error {
+	for _, server := range p.Batch {
+		node, err := kubernetes.GetNode(p.Client, server)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if !isNodeReady(*node) {
+			return trace.BadParameter("node %v is not ready", server.Hostname)
+		}
+	}
+	return nil
+}
+
+// checkAgentStatus verifies the health of the cluster and the batch
+// nodes as reported by the planet agents
+func (p *phaseHealthGate) checkAgentStatus(ctx context.Context) error {
+	agent, err := status.FromPlanetAgent(ctx, p.Servers)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	for _, server := range p.Batch {
+		for _, node := range agent.Nodes {
+			if node.AdvertiseIP != server.AdvertiseIP {
+				continue
+			}
+			if node.Status != status.NodeHealthy {
+				return trace.BadParameter("node %v is %v: %v", server.Hostname,
+					node.Status, node.FailedProbes)
+			}
+		}
+	}
+	if agent.GetSystemStatus() != agentpb.SystemStatus_Running {
+		return trace.BadParameter("cluster is %v", agent.SystemStatus)
+	}
+	return nil
+}
+
+// checkStatusHook runs the application status hook if the application defines one
+func (p *phaseHealthGate) checkStatusHook(ctx context.Context) error {
+	req := app.HookRunRequest{
+		Application:    p.Package,
+		GravityPackage: p.GravityPackage,
+		Hook:           schema.HookStatus,
+		ServiceUser:    p.ServiceUser,
+	}
+	_, err := app.CheckHasAppHook(p.Apps, req)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil
+		}
+		return trace.Wrap(err)
+	}
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	go streamHook(schema.HookStatus, reader, p.FieldLogger)
+	ref, err := app.StreamAppHook(ctx, p.Apps, req, writer)
+	if ref != nil {
+		// the health gate is retried so remove the hook job after each attempt
+		defer func() {
+			err := p.Apps.DeleteAppHookJob(ctx, app.DeleteAppHookJobRequest{
+				HookRef: *ref,
+				Cascade: true,
+			})
+			if err != nil {
+				p.Warnf("Failed to delete status hook %v: %v.",
+					ref, trace.DebugReport(err))
+			}
+		}()
+	}
+	if err != nil {
+		return trace.Wrap(err, "%v status hook failed", p.Package)
+	}
+	return nil
+}
+
+func isNodeReady(node v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func formatServers(servers []storage.Server) string {
+	if len(servers) == 1 {
+		return fmt.Sprintf("node %v", servers[0].Hostname)
+	}
+	hostnames := make([]string, 0, len(servers))
+	for _, server := range servers {
+		hostnames = append(hostnames, server.Hostname)
+	}
+	return fmt.Sprintf("nodes %v", hostnames)
+}
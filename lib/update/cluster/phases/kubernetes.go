@@ -23,6 +23,7 @@ import (
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/fsm"
 	"github.com/gravitational/gravity/lib/kubernetes"
+	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/update"
 
@@ -105,27 +106,39 @@ func (p *phaseUntaint) Rollback(context.Context) error {
 // phaseDrain defines the operation of draining a node
 type phaseDrain struct {
 	kubernetesOperation
+	// Operator is used to report drain progress
+	Operator ops.Operator
+	// Key identifies the update operation
+	Key ops.SiteOperationKey
+	// Policy defines how evictions blocked by disruption budgets are handled
+	Policy *storage.DrainPolicy
 }
 
 // NewPhaseDrain returns a new executor for draining a node
-func NewPhaseDrain(p fsm.ExecutorParams, client *kubeapi.Clientset, logger log.FieldLogger) (*phaseDrain, error) {
+func NewPhaseDrain(p fsm.ExecutorParams, operator ops.Operator, client *kubeapi.Clientset, logger log.FieldLogger) (*phaseDrain, error) {
 	op, err := newKubernetesOperation(p, client, logger)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
 	return &phaseDrain{
 		kubernetesOperation: *op,
+		Operator:            operator,
+		Key:                 fsm.OperationKey(p.Plan),
+		Policy:              p.Phase.Data.DrainPolicy,
 	}, nil
 }
 
 // Execute drains the specified node
 func (p *phaseDrain) Execute(ctx context.Context) error {
 	p.Infof("Drain %v.", p.Server)
-	ctx, cancel := context.WithTimeout(ctx, defaults.DrainTimeout)
-	defer cancel()
-	err := update.Retry(ctx, func() error {
-		return trace.Wrap(drain(ctx, p.Client, p.Server.KubeNodeID()))
-	}, defaults.DrainErrorTimeout)
+	err := update.Drain(ctx, update.DrainConfig{
+		Client:      p.Client,
+		Operator:    p.Operator,
+		Key:         p.Key,
+		Server:      p.Server,
+		Policy:      p.Policy,
+		FieldLogger: p.FieldLogger,
+	})
 	return trace.Wrap(err)
 }
 
@@ -288,11 +301,6 @@ func taint(ctx context.Context, client corev1.NodeInterface, node string, add ad
 	return nil
 }
 
-func drain(ctx context.Context, client *kubeapi.Clientset, node string) error {
-	err := kubernetes.Drain(ctx, client, node)
-	return trace.Wrap(err)
-}
-
 func uncordon(ctx context.Context, client corev1.NodeInterface, node string) error {
 	err := kubernetes.SetUnschedulable(ctx, client, node, false)
 	return trace.Wrap(err)
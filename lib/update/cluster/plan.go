@@ -23,11 +23,13 @@ import (
 	"io"
 	"io/ioutil"
 	"strconv"
+	"time"
 
 	"github.com/gravitational/gravity/lib/app"
 	"github.com/gravitational/gravity/lib/archive"
 	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/fsm"
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/localenv"
 	"github.com/gravitational/gravity/lib/ops"
@@ -77,14 +79,9 @@ func InitOperationPlan(
 		return nil, trace.Wrap(err)
 	}
 
-	dnsConfig := cluster.DNSConfig
-	if dnsConfig.IsEmpty() {
-		log.Info("Detecting DNS configuration.")
-		existingDNS, err := getExistingDNSConfig(localEnv.Packages)
-		if err != nil {
-			return nil, trace.Wrap(err, "failed to determine existing cluster DNS configuration")
-		}
-		dnsConfig = *existingDNS
+	dnsConfig, err := getDNSConfig(*cluster, localEnv.Packages)
+	if err != nil {
+		return nil, trace.Wrap(err)
 	}
 
 	plan, err = NewOperationPlan(PlanConfig{
@@ -92,7 +89,7 @@ func InitOperationPlan(
 		Apps:      clusterEnv.Apps,
 		Packages:  clusterEnv.ClusterPackages,
 		Client:    clusterEnv.Client,
-		DNSConfig: dnsConfig,
+		DNSConfig: *dnsConfig,
 		Operator:  clusterEnv.Operator,
 		Operation: operation,
 	})
@@ -108,6 +105,127 @@ func InitOperationPlan(
 	return plan, nil
 }
 
+// NewDryRunOperationPlan generates the plan for updating the cluster to the
+// specified application package without creating an operation.
+// strategy optionally defines how regular nodes are updated and drainPolicy
+// how node drains handle blocked evictions.
+// The resulting plan is not persisted and cannot be executed
+func NewDryRunOperationPlan(
+	localEnv *localenv.LocalEnvironment,
+	clusterEnv *localenv.ClusterEnvironment,
+	updatePackage loc.Locator,
+	strategy *storage.UpdateStrategy,
+	drainPolicy *storage.DrainPolicy,
+) (*storage.OperationPlan, error) {
+	cluster, err := clusterEnv.Operator.GetLocalSite()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	dnsConfig, err := getDNSConfig(*cluster, localEnv.Packages)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	plan, err := NewOperationPlan(PlanConfig{
+		Backend:   clusterEnv.Backend,
+		Apps:      clusterEnv.Apps,
+		Packages:  clusterEnv.ClusterPackages,
+		Client:    clusterEnv.Client,
+		DNSConfig: *dnsConfig,
+		Operator:  clusterEnv.Operator,
+		Operation: &storage.SiteOperation{
+			AccountID:  cluster.AccountID,
+			SiteDomain: cluster.Domain,
+			Type:       ops.OperationUpdate,
+			Created:    time.Now().UTC(),
+			State:      ops.OperationStateUpdateInProgress,
+			Update: &storage.UpdateOperationState{
+				UpdatePackage: updatePackage.String(),
+				Strategy:      strategy,
+				DrainPolicy:   drainPolicy,
+			},
+		},
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	return plan, nil
+}
+
+// GetPlanImpact returns the summary of changes the specified update
+// operation plan makes to the cluster
+func GetPlanImpact(plan storage.OperationPlan, apps app.Applications) (*fsm.PlanImpact, error) {
+	var impact fsm.PlanImpact
+	for _, phase := range fsm.FlattenPlan(&plan) {
+		if phase.Data == nil {
+			continue
+		}
+		if phase.Data.Update != nil {
+			for _, server := range phase.Data.Update.Servers {
+				if server.Runtime.SecretsPackage != nil {
+					impact.AddRotatedPackage(server.Server, *server.Runtime.SecretsPackage)
+				}
+				if server.Runtime.Update != nil {
+					impact.AddRotatedPackage(server.Server, server.Runtime.Update.ConfigPackage)
+				}
+				if server.Teleport.Update != nil {
+					impact.AddRotatedPackage(server.Server, server.Teleport.Update.NodeConfigPackage)
+				}
+			}
+		}
+		var hooks []schema.HookType
+		switch phase.Executor {
+		case drainNode:
+			if phase.Data.Server != nil {
+				impact.AddDrainedNode(*phase.Data.Server)
+			}
+		case preUpdate:
+			hooks = []schema.HookType{schema.HookBeforeUpdate}
+		case updateApp:
+			hooks = []schema.HookType{schema.HookNetworkUpdate, schema.HookUpdate, schema.HookUpdated}
+		}
+		if len(hooks) == 0 || phase.Data.Package == nil {
+			continue
+		}
+		if phase.Data.Package.Name == constants.BootstrapConfigPackage {
+			// resources of the bootstrap application are created
+			// directly instead of running its hooks
+			continue
+		}
+		application, err := apps.GetApp(*phase.Data.Package)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		for _, hook := range hooks {
+			if application.Manifest.HasHook(hook) {
+				impact.Hooks = append(impact.Hooks, fsm.PlanHook{
+					Phase:   phase.ID,
+					Package: application.Package,
+					Hook:    hook,
+				})
+			}
+		}
+	}
+	return &impact, nil
+}
+
+// getDNSConfig returns the DNS configuration of the specified cluster.
+// Clusters installed with older versions do not have it recorded in which
+// case it is detected from the installed planet package
+func getDNSConfig(cluster ops.Site, packages pack.PackageService) (*storage.DNSConfig, error) {
+	if !cluster.DNSConfig.IsEmpty() {
+		return &cluster.DNSConfig, nil
+	}
+	log.Info("Detecting DNS configuration.")
+	dnsConfig, err := getExistingDNSConfig(packages)
+	if err != nil {
+		return nil, trace.Wrap(err, "failed to determine existing cluster DNS configuration")
+	}
+	return dnsConfig, nil
+}
+
 // NewOperationPlan generates a new plan for the provided operation
 func NewOperationPlan(config PlanConfig) (*storage.OperationPlan, error) {
 	if err := config.checkAndSetDefaults(); err != nil {
@@ -70,6 +70,9 @@ func newOperationPlan(
 	servers []storage.Server,
 ) (*storage.OperationPlan, error) {
 	builder := rollingupdate.Builder{App: app.Package}
+	if operation.UpdateConfig != nil {
+		builder.Concurrency = operation.UpdateConfig.Concurrency
+	}
 	updates, err := rollingupdate.RuntimeConfigUpdates(app.Manifest, operator, operation.Key(), servers)
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -0,0 +1,122 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/kubernetes"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/cenkalti/backoff"
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+	kubeapi "k8s.io/client-go/kubernetes"
+)
+
+// DrainConfig defines the configuration for draining a node
+// as a part of an operation
+type DrainConfig struct {
+	// Client is the Kubernetes API client
+	Client *kubeapi.Clientset
+	// Operator is used to report drain progress
+	Operator ops.Operator
+	// Key identifies the operation the drain is a part of
+	Key ops.SiteOperationKey
+	// Server is the server to drain
+	Server storage.Server
+	// Policy optionally defines how evictions blocked by pod disruption
+	// budgets are handled
+	Policy *storage.DrainPolicy
+	// FieldLogger is used for logging. Drain outcome is logged using it
+	// so it is expected to write to the operation log
+	log.FieldLogger
+}
+
+// Drain drains the node specified with config.
+//
+// Pods with evictions blocked by pod disruption budgets are reported in the
+// operation progress and handled according to the drain policy.
+// The outcome of the drain is recorded with the configured logger
+func Drain(ctx context.Context, config DrainConfig) error {
+	ctx, cancel := context.WithTimeout(ctx, defaults.DrainTimeout)
+	defer cancel()
+	var policy storage.DrainPolicy
+	if config.Policy != nil {
+		policy = *config.Policy
+	}
+	err := Retry(ctx, func() error {
+		result, err := kubernetes.DrainNode(ctx, kubernetes.DrainConfig{
+			Client:    config.Client,
+			NodeName:  config.Server.KubeNodeID(),
+			Policy:    policy,
+			OnBlocked: config.reportBlocked,
+		})
+		if err != nil {
+			if result != nil {
+				config.Warnf("Failed to drain node %v: %v.", config.Server.Hostname, result)
+			}
+			if kubernetes.IsDrainBlockedError(err) {
+				// retrying will not help if the policy is to abort
+				return &backoff.PermanentError{Err: err}
+			}
+			return trace.Wrap(err)
+		}
+		config.Infof("Drained node %v: %v.", config.Server.Hostname, result)
+		return nil
+	}, defaults.DrainErrorTimeout)
+	return trace.Wrap(err)
+}
+
+// reportBlocked reports the evictions blocked by pod disruption
+// budgets in the operation progress
+func (r DrainConfig) reportBlocked(blocked []kubernetes.BlockedEviction) {
+	message := fmt.Sprintf("Draining node %v", r.Server.Hostname)
+	if len(blocked) != 0 {
+		evictions := make([]string, 0, len(blocked))
+		for _, eviction := range blocked {
+			evictions = append(evictions, eviction.String())
+		}
+		message = fmt.Sprintf("%v, evictions are blocked: %v", message, strings.Join(evictions, "; "))
+		r.Warnf("Evictions on node %v are blocked: %v.", r.Server.Hostname, strings.Join(evictions, "; "))
+	}
+	if r.Operator == nil {
+		return
+	}
+	entry := ops.ProgressEntry{
+		SiteDomain:  r.Key.SiteDomain,
+		OperationID: r.Key.OperationID,
+		State:       ops.ProgressStateInProgress,
+		Message:     message,
+		Created:     time.Now().UTC(),
+	}
+	// preserve the completion of the operation
+	progress, err := r.Operator.GetSiteOperationProgress(r.Key)
+	if err == nil {
+		entry.Completion = progress.Completion
+		entry.Step = progress.Step
+	}
+	err = r.Operator.CreateProgressEntry(r.Key, entry)
+	if err != nil {
+		r.WithError(err).Warn("Failed to create progress entry.")
+	}
+}
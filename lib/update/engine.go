@@ -24,6 +24,7 @@ import (
 	"github.com/gravitational/gravity/lib/fsm"
 	"github.com/gravitational/gravity/lib/localenv"
 	"github.com/gravitational/gravity/lib/ops"
+	pb "github.com/gravitational/gravity/lib/rpc/proto"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/utils"
 
@@ -46,6 +47,7 @@ func NewMachine(ctx context.Context, config Config, engine *Engine) (*fsm.FSM, e
 		return nil, trace.Wrap(err)
 	}
 	machine.SetPreExec(engine.UpdateProgress)
+	machine.SetProgressHook(engine.ReportProgress)
 	return machine, nil
 }
 
@@ -107,6 +109,21 @@ func (r *Engine) UpdateProgress(ctx context.Context, params fsm.Params) error {
 	return nil
 }
 
+// ReportProgress creates a progress entry in the operator for the progress
+// reported by the phase executing on the specified server
+func (r *Engine) ReportProgress(ctx context.Context, params fsm.Params, server storage.Server, progress pb.Progress) error {
+	plan, err := r.GetPlan()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	phase, err := fsm.FindPhase(plan, params.PhaseID)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	entry := fsm.RemoteProgressEntry(*plan, *phase, server, progress)
+	return trace.Wrap(r.operator.CreateProgressEntry(r.Operation.Key(), entry))
+}
+
 // Complete marks the operation as either completed or failed based
 // on the state of the operation plan
 func (r *Engine) Complete(fsmErr error) error {
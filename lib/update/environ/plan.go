@@ -67,6 +67,9 @@ func newOperationPlan(
 	servers []storage.Server,
 ) (*storage.OperationPlan, error) {
 	builder := rollingupdate.Builder{App: app.Package}
+	if operation.UpdateEnviron != nil {
+		builder.Concurrency = operation.UpdateEnviron.Concurrency
+	}
 	configUpdates, err := rollingupdate.RuntimeConfigUpdates(app.Manifest, operator, operation.Key(), servers)
 	if err != nil {
 		return nil, trace.Wrap(err)
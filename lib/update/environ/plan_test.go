@@ -638,6 +638,49 @@ func (S) TestMultiNodePlan(c *C) {
 	})
 }
 
+func (S) TestUpdatesNodesConcurrently(c *C) {
+	operation := ops.SiteOperation{
+		ID:         "1",
+		AccountID:  "0",
+		Type:       ops.OperationUpdateRuntimeEnviron,
+		SiteDomain: "cluster",
+		UpdateEnviron: &storage.UpdateEnvarsOperationState{
+			Concurrency: 2,
+		},
+	}
+	servers := []storage.Server{
+		{Hostname: "node-1", Role: "node", ClusterRole: string(schema.ServiceRoleMaster)},
+		{Hostname: "node-2", Role: "knode", ClusterRole: string(schema.ServiceRoleNode)},
+		{Hostname: "node-3", Role: "knode", ClusterRole: string(schema.ServiceRoleNode)},
+	}
+	runtimeLoc := loc.Locator{Repository: "foo", Name: "runtime", Version: "0.0.1"}
+	app := app.Application{
+		Package: loc.MustParseLocator("gravitational.io/app:0.0.1"),
+		Manifest: schema.Manifest{
+			NodeProfiles: schema.NodeProfiles{
+				{Name: "node", ServiceRole: "master"},
+				{Name: "knode", ServiceRole: "node"},
+			},
+			SystemOptions: &schema.SystemOptions{
+				Dependencies: schema.SystemDependencies{
+					Runtime: &schema.Dependency{Locator: runtimeLoc},
+				},
+			},
+		},
+	}
+
+	plan, err := newOperationPlan(app, storage.DefaultDNSConfig, testOperator, operation, servers)
+	c.Assert(err, IsNil)
+	nodes := plan.Phases[len(plan.Phases)-1]
+	c.Assert(nodes.ID, Equals, "/nodes")
+	c.Assert(nodes.Parallel, Equals, true)
+	c.Assert(nodes.Concurrency, Equals, 2)
+	c.Assert(nodes.Phases, HasLen, 2)
+	for _, node := range nodes.Phases {
+		c.Assert(node.Requires, HasLen, 0, Commentf("node phase %v", node.ID))
+	}
+}
+
 func (r testRotator) RotatePlanetConfig(ops.RotatePlanetConfigRequest) (*ops.RotatePackageResponse, error) {
 	return &ops.RotatePackageResponse{Locator: r.runtimeConfigPackage}, nil
 }
@@ -111,7 +111,14 @@ func (r Builder) Nodes(servers []storage.UpdateServer, master storage.Server, ro
 	for i, server := range servers {
 		node := r.node(server.Hostname, nodeTextFormat, server.Hostname)
 		node.AddSequential(r.common(servers[i], &master)...)
-		root.AddSequential(node)
+		if r.Concurrency > 1 {
+			root.AddParallel(node)
+		} else {
+			root.AddSequential(node)
+		}
+	}
+	if r.Concurrency > 1 {
+		root.SetConcurrency(r.Concurrency)
 	}
 	return &root
 }
@@ -211,6 +218,10 @@ func (r Builder) node(id, format string, args ...interface{}) update.Phase {
 type Builder struct {
 	// App specifies the cluster application
 	App loc.Locator
+	// Concurrency specifies the maximum number of regular nodes
+	// updated at the same time. Nodes are updated one by one if
+	// the value is less than 2
+	Concurrency int
 }
 
 // setLeaderElection creates a phase that will change the leader election state in the cluster
@@ -42,7 +42,7 @@ func (r *dispatcher) Dispatch(config Config, params fsm.ExecutorParams, remote f
 	case libphase.Elections:
 		return libphase.NewElections(params, config.Operator, logger)
 	case libphase.Drain:
-		return libphase.NewDrain(params, config.Client, logger)
+		return libphase.NewDrain(params, config.Operator, config.Client, logger)
 	case libphase.Taint:
 		return libphase.NewTaint(params, config.Client, logger)
 	case libphase.Untaint:
@@ -18,17 +18,15 @@ package phases
 
 import (
 	"context"
-	"time"
 
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/fsm"
 	libfsm "github.com/gravitational/gravity/lib/fsm"
 	"github.com/gravitational/gravity/lib/kubernetes"
+	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/update"
-	"github.com/gravitational/gravity/lib/utils"
 
-	"github.com/cenkalti/backoff"
 	"github.com/gravitational/trace"
 	log "github.com/sirupsen/logrus"
 	"k8s.io/api/core/v1"
@@ -92,24 +90,30 @@ func (*untainter) Rollback(context.Context) error {
 }
 
 // NewDrain returns a new executor for draining a node
-func NewDrain(params libfsm.ExecutorParams, client *kubeapi.Clientset, logger log.FieldLogger) (*drainer, error) {
+func NewDrain(params libfsm.ExecutorParams, operator ops.Operator, client *kubeapi.Clientset, logger log.FieldLogger) (*drainer, error) {
 	op, err := newKubernetesOperation(params, client, logger)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
 	return &drainer{
 		kubernetesOperation: *op,
+		operator:            operator,
+		key:                 libfsm.OperationKey(params.Plan),
+		policy:              params.Phase.Data.DrainPolicy,
 	}, nil
 }
 
 // Execute drains the specified node
 func (p *drainer) Execute(ctx context.Context) error {
 	p.Infof("Drain %v.", p.Server)
-	ctx, cancel := context.WithTimeout(ctx, defaults.DrainTimeout)
-	defer cancel()
-	err := retry(ctx, func() error {
-		return trace.Wrap(drain(ctx, p.Client, p.Server.KubeNodeID()))
-	}, defaults.DrainErrorTimeout)
+	err := update.Drain(ctx, update.DrainConfig{
+		Client:      p.Client,
+		Operator:    p.operator,
+		Key:         p.key,
+		Server:      p.Server,
+		Policy:      p.policy,
+		FieldLogger: p.FieldLogger,
+	})
 	return trace.Wrap(err)
 }
 
@@ -234,22 +238,11 @@ func taint(ctx context.Context, client corev1.NodeInterface, node string, add ad
 	return nil
 }
 
-func drain(ctx context.Context, client *kubeapi.Clientset, node string) error {
-	err := kubernetes.Drain(ctx, client, node)
-	return trace.Wrap(err)
-}
-
 func uncordon(ctx context.Context, client corev1.NodeInterface, node string) error {
 	err := kubernetes.SetUnschedulable(ctx, client, node, false)
 	return trace.Wrap(err)
 }
 
-func retry(ctx context.Context, fn func() error, timeout time.Duration) error {
-	b := backoff.NewExponentialBackOff()
-	b.MaxElapsedTime = timeout
-	return trace.Wrap(utils.RetryWithInterval(ctx, b, fn))
-}
-
 // tainter defines the operation of adding a taint to the node
 type tainter struct {
 	kubernetesOperation
@@ -263,6 +256,12 @@ type untainter struct {
 // drainer defines the operation of draining a node
 type drainer struct {
 	kubernetesOperation
+	// operator is used to report drain progress
+	operator ops.Operator
+	// key identifies the operation
+	key ops.SiteOperationKey
+	// policy defines how evictions blocked by disruption budgets are handled
+	policy *storage.DrainPolicy
 }
 
 // uncordoner defines the operation of uncordoning a node
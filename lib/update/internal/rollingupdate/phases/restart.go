@@ -22,7 +22,9 @@ import (
 	libapp "github.com/gravitational/gravity/lib/app/service"
 	libfsm "github.com/gravitational/gravity/lib/fsm"
 	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/pack/signing"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/update"
 	"github.com/gravitational/gravity/lib/update/system"
@@ -35,7 +37,7 @@ import (
 // the environment variables update
 func NewRestart(
 	params libfsm.ExecutorParams,
-	operator localClusterGetter,
+	operator trustedKeysGetter,
 	operationID string,
 	apps appGetter,
 	backend storage.Backend,
@@ -55,12 +57,21 @@ func NewRestart(
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	keys, err := operator.GetTrustedKeys(cluster.Key())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	verifier, err := signing.NewClusterVerifier(keys, cluster.Domain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
 	return &restart{
 		FieldLogger:   logger,
 		operationID:   operationID,
 		backend:       backend,
 		packages:      packages,
 		localPackages: localPackages,
+		verifier:      verifier,
 		serviceUser:   cluster.ServiceUser,
 		update:        params.Phase.Data.Update.Servers[0],
 	}, nil
@@ -123,9 +134,10 @@ func (r *restart) pullUpdates() error {
 	for _, update := range updates {
 		r.Infof("Pulling package update: %v.", update)
 		_, err := libapp.PullPackage(libapp.PackagePullRequest{
-			SrcPack: r.packages,
-			DstPack: r.localPackages,
-			Package: update,
+			SrcPack:  r.packages,
+			DstPack:  r.localPackages,
+			Package:  update,
+			Verifier: r.verifier,
 		})
 		if err != nil && !trace.IsAlreadyExists(err) {
 			return trace.Wrap(err)
@@ -140,7 +152,17 @@ type restart struct {
 	backend       storage.Backend
 	packages      pack.PackageService
 	localPackages update.LocalPackageService
-	update        storage.UpdateServer
-	serviceUser   storage.OSUser
-	operationID   string
+	// verifier optionally verifies signatures of the pulled packages
+	verifier    pack.Verifier
+	update      storage.UpdateServer
+	serviceUser storage.OSUser
+	operationID string
+}
+
+// trustedKeysGetter fetches the local cluster and the keys it trusts
+// to sign packages
+type trustedKeysGetter interface {
+	localClusterGetter
+	// GetTrustedKeys returns the list of keys trusted to sign packages
+	GetTrustedKeys(ops.SiteKey) ([]storage.TrustedKey, error)
 }
@@ -0,0 +1,216 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gravitational/trace"
+)
+
+// CronSchedule is a parsed cron expression in the standard five-field
+// format: minute, hour, day of month, month and day of week.
+//
+// Each field accepts '*', single values, ranges (1-5), steps (*/15, 1-30/2)
+// and comma-separated lists of these. Descriptors @yearly, @monthly,
+// @weekly, @daily and @hourly are also supported.
+type CronSchedule struct {
+	expr   string
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	// domAny and dowAny are set when the respective field is unrestricted.
+	// As in traditional cron, if both day fields are restricted, the time
+	// matches when either of them matches
+	domAny bool
+	dowAny bool
+}
+
+// ParseCronSchedule parses the provided cron expression
+func ParseCronSchedule(expr string) (*CronSchedule, error) {
+	spec := strings.TrimSpace(expr)
+	if descriptor, ok := cronDescriptors[spec]; ok {
+		spec = descriptor
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, trace.BadParameter(
+			"expected 5 fields in cron expression %q, got %v", expr, len(fields))
+	}
+	schedule := CronSchedule{expr: expr}
+	var err error
+	for i, target := range []*uint64{&schedule.minute, &schedule.hour,
+		&schedule.dom, &schedule.month, &schedule.dow} {
+		*target, err = parseCronField(fields[i], cronBounds[i])
+		if err != nil {
+			return nil, trace.Wrap(err, "invalid cron expression %q", expr)
+		}
+	}
+	// Sunday can be specified as either 0 or 7
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+	schedule.domAny = fields[2] == "*"
+	schedule.dowAny = fields[4] == "*"
+	return &schedule, nil
+}
+
+// String returns the original cron expression
+func (s CronSchedule) String() string {
+	return s.expr
+}
+
+// Matches returns true if the specified time (truncated to minutes)
+// matches the schedule
+func (s CronSchedule) Matches(t time.Time) bool {
+	return bitSet(s.minute, t.Minute()) && bitSet(s.hour, t.Hour()) &&
+		bitSet(s.month, int(t.Month())) && s.dayMatches(t)
+}
+
+// Next returns the earliest time strictly after t that matches the schedule.
+// Returns zero time if no such time exists within the next few years which
+// can only happen for impossible dates like February 30
+func (s CronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(cronSearchYears, 0, 0)
+	for t.Before(limit) {
+		if !bitSet(s.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !bitSet(s.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !bitSet(s.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s CronSchedule) dayMatches(t time.Time) bool {
+	dom := bitSet(s.dom, t.Day())
+	dow := bitSet(s.dow, int(t.Weekday()))
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func parseCronField(field string, bounds cronBound) (bits uint64, err error) {
+	for _, part := range strings.Split(field, ",") {
+		var min, max, step int
+		rangeAndStep := strings.SplitN(part, "/", 2)
+		switch {
+		case rangeAndStep[0] == "*":
+			min, max = bounds.min, bounds.max
+		case strings.Contains(rangeAndStep[0], "-"):
+			ends := strings.SplitN(rangeAndStep[0], "-", 2)
+			if min, err = parseCronValue(ends[0], bounds); err != nil {
+				return 0, trace.Wrap(err)
+			}
+			if max, err = parseCronValue(ends[1], bounds); err != nil {
+				return 0, trace.Wrap(err)
+			}
+		default:
+			if min, err = parseCronValue(rangeAndStep[0], bounds); err != nil {
+				return 0, trace.Wrap(err)
+			}
+			max = min
+		}
+		step = 1
+		if len(rangeAndStep) == 2 {
+			step, err = strconv.Atoi(rangeAndStep[1])
+			if err != nil || step <= 0 {
+				return 0, trace.BadParameter("invalid step in %q", part)
+			}
+			if rangeAndStep[0] != "*" && min == max {
+				// "5/10" means "starting at 5 every 10"
+				max = bounds.max
+			}
+		}
+		if min > max {
+			return 0, trace.BadParameter("invalid range in %q", part)
+		}
+		for i := min; i <= max; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(value string, bounds cronBound) (int, error) {
+	if n, ok := bounds.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, trace.BadParameter("invalid value %q", value)
+	}
+	if n < bounds.min || n > bounds.max {
+		return 0, trace.BadParameter("value %v is out of range [%v-%v]",
+			n, bounds.min, bounds.max)
+	}
+	return n, nil
+}
+
+func bitSet(bits uint64, i int) bool {
+	return bits&(1<<uint(i)) != 0
+}
+
+// cronBound defines the range of valid values for a cron field
+type cronBound struct {
+	min, max int
+	names    map[string]int
+}
+
+var cronBounds = []cronBound{
+	{min: 0, max: 59},
+	{min: 0, max: 23},
+	{min: 1, max: 31},
+	{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronSearchYears limits how far into the future Next looks for a match
+const cronSearchYears = 5
@@ -0,0 +1,115 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"time"
+
+	"gopkg.in/check.v1"
+)
+
+type CronSuite struct{}
+
+var _ = check.Suite(&CronSuite{})
+
+func (s *CronSuite) TestNext(c *check.C) {
+	// 2018-06-15 is a Friday
+	from := time.Date(2018, time.June, 15, 10, 30, 45, 0, time.UTC)
+	testCases := []struct {
+		expr     string
+		expected time.Time
+		comment  string
+	}{
+		{
+			expr:     "* * * * *",
+			expected: time.Date(2018, time.June, 15, 10, 31, 0, 0, time.UTC),
+			comment:  "every minute",
+		},
+		{
+			expr:     "0 2 * * *",
+			expected: time.Date(2018, time.June, 16, 2, 0, 0, 0, time.UTC),
+			comment:  "daily at 2am",
+		},
+		{
+			expr:     "*/15 * * * *",
+			expected: time.Date(2018, time.June, 15, 10, 45, 0, 0, time.UTC),
+			comment:  "every 15 minutes",
+		},
+		{
+			expr:     "0 9-17 * * mon-fri",
+			expected: time.Date(2018, time.June, 15, 11, 0, 0, 0, time.UTC),
+			comment:  "business hours",
+		},
+		{
+			expr:     "30 1 * * 0",
+			expected: time.Date(2018, time.June, 17, 1, 30, 0, 0, time.UTC),
+			comment:  "sundays",
+		},
+		{
+			expr:     "30 1 * * 7",
+			expected: time.Date(2018, time.June, 17, 1, 30, 0, 0, time.UTC),
+			comment:  "sundays specified as 7",
+		},
+		{
+			expr:     "0 0 1 jan *",
+			expected: time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC),
+			comment:  "new year",
+		},
+		{
+			expr:     "@monthly",
+			expected: time.Date(2018, time.July, 1, 0, 0, 0, 0, time.UTC),
+			comment:  "descriptor",
+		},
+		{
+			expr:     "0 0 20 * 1",
+			expected: time.Date(2018, time.June, 18, 0, 0, 0, 0, time.UTC),
+			comment:  "either day of month or day of week",
+		},
+		{
+			expr:     "0 0 30 2 *",
+			expected: time.Time{},
+			comment:  "impossible date",
+		},
+	}
+	for _, tc := range testCases {
+		comment := check.Commentf(tc.comment)
+		schedule, err := ParseCronSchedule(tc.expr)
+		c.Assert(err, check.IsNil, comment)
+		c.Assert(schedule.Next(from), check.DeepEquals, tc.expected, comment)
+		if !tc.expected.IsZero() {
+			c.Assert(schedule.Matches(tc.expected), check.Equals, true, comment)
+		}
+	}
+}
+
+func (s *CronSuite) TestParseErrors(c *check.C) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	} {
+		_, err := ParseCronSchedule(expr)
+		c.Assert(err, check.NotNil, check.Commentf(expr))
+	}
+}
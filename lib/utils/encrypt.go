@@ -46,9 +46,20 @@ func EncryptPGP(data io.Reader, passphrase string) (io.ReadCloser, error) {
 	return pr, nil
 }
 
-// DecryptPGP returns a stream with "data" decrypted by the provided passphrase
+// DecryptPGP returns a stream with "data" decrypted by the provided passphrase.
+//
+// The integrity of the data is only verified once the stream has been read
+// to the end, so the callers must drain the stream before using the data
 func DecryptPGP(data io.Reader, passphrase string) (io.Reader, error) {
+	// openpgp keeps prompting for the passphrase until the message is
+	// decrypted so fail on the second attempt to avoid looping forever
+	// when the passphrase is wrong
+	prompted := false
 	promptFn := func(_ []openpgp.Key, _ bool) ([]byte, error) {
+		if prompted {
+			return nil, trace.AccessDenied("invalid passphrase")
+		}
+		prompted = true
 		return []byte(passphrase), nil
 	}
 
@@ -57,5 +68,21 @@ func DecryptPGP(data io.Reader, passphrase string) (io.Reader, error) {
 		return nil, trace.Wrap(err)
 	}
 
-	return md.UnverifiedBody, nil
+	return &verifiedReader{md: md}, nil
+}
+
+// verifiedReader returns an error at the end of the decrypted stream
+// if the message has failed the signature check
+type verifiedReader struct {
+	md *openpgp.MessageDetails
+}
+
+// Read reads the decrypted data. The integrity check errors are returned
+// by the underlying reader at the end of the stream
+func (r *verifiedReader) Read(p []byte) (int, error) {
+	n, err := r.md.UnverifiedBody.Read(p)
+	if err == io.EOF && r.md.SignatureError != nil {
+		return n, trace.Wrap(r.md.SignatureError)
+	}
+	return n, err
 }
@@ -269,6 +269,10 @@ func ConvertS3Error(err error) error {
 	switch awsErr.Code() {
 	case s3.ErrCodeNoSuchKey, s3.ErrCodeNoSuchBucket:
 		return trace.NotFound(awsErr.Message())
+	case "NotFound":
+		// HEAD requests for missing objects have no body
+		// so the error code is derived from the status code
+		return trace.NotFound("object not found")
 	}
 	return err
 }
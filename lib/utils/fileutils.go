@@ -26,6 +26,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/defaults"
@@ -369,6 +370,30 @@ func RemoveContents(dir string) error {
 	return nil
 }
 
+// RemoveOlderThan removes the children of dir last modified before
+// the specified time. If the dir does not exist, RemoveOlderThan
+// returns nil (no error)
+func RemoveOlderThan(dir string, before time.Time) error {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		err = trace.ConvertSystemError(err)
+		if trace.IsNotFound(err) {
+			return nil
+		}
+		return trace.Wrap(err)
+	}
+	for _, info := range infos {
+		if !info.ModTime().Before(before) {
+			continue
+		}
+		err = os.RemoveAll(filepath.Join(dir, info.Name()))
+		if err != nil {
+			return trace.ConvertSystemError(err)
+		}
+	}
+	return nil
+}
+
 // OpenFile opens the file at the provided path in a+ mode
 func OpenFile(path string) (*os.File, error) {
 	return os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, defaults.SharedReadMask)
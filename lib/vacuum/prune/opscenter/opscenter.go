@@ -0,0 +1,481 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opscenter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gravitational/gravity/lib/blob"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/schema"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/vacuum/prune"
+
+	"github.com/coreos/go-semver/semver"
+	"github.com/dustin/go-humanize"
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	log "github.com/sirupsen/logrus"
+)
+
+// New creates a new cleaner of unused packages in the Ops Center package storage
+func New(config Config) (*cleanup, error) {
+	if err := config.checkAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &cleanup{
+		Config: config,
+	}, nil
+}
+
+func (r *Config) checkAndSetDefaults() error {
+	if r.Packages == nil {
+		return trace.BadParameter("package service is required")
+	}
+	if err := r.Policy.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	if r.FieldLogger == nil {
+		r.FieldLogger = log.WithField(trace.Component, "gc:opscenter")
+	}
+	if r.Clock == nil {
+		r.Clock = clockwork.NewRealClock()
+	}
+	return nil
+}
+
+// Config describes configuration for the cleaner of unused packages
+// in the Ops Center package storage
+type Config struct {
+	// Config specifies the common pruner configuration
+	prune.Config
+	// Packages specifies the package service to prune
+	Packages packageService
+	// Objects specifies the optional BLOB storage the package service
+	// keeps package data in. If specified, BLOBs not referenced by
+	// any package are removed as well
+	Objects blob.Objects
+	// Apps lists applications installed on the clusters connected
+	// to the Ops Center. These applications and their dependencies
+	// are never removed
+	Apps []storage.Application
+	// Policy specifies the application retention policy
+	Policy Policy
+	// Clock specifies the time source
+	Clock clockwork.Clock
+}
+
+// Policy defines which versions of applications published in the Ops Center
+// are retained.
+//
+// An application version is removed if it is not used by any of the connected
+// clusters, is not among the latest KeepVersions versions of the application
+// and is older than MaxAge
+type Policy struct {
+	// KeepVersions is the number of the latest versions of each application to retain
+	KeepVersions int
+	// MaxAge specifies the minimum age of the application version eligible
+	// for removal. If unspecified, the age is not taken into account
+	MaxAge time.Duration
+}
+
+// CheckAndSetDefaults validates the policy and sets defaults
+func (r *Policy) CheckAndSetDefaults() error {
+	if r.KeepVersions < 0 {
+		return trace.BadParameter("number of versions to keep cannot be negative")
+	}
+	if r.MaxAge < 0 {
+		return trace.BadParameter("maximum age cannot be negative")
+	}
+	if r.KeepVersions == 0 {
+		r.KeepVersions = defaults.PackageGCKeepVersions
+	}
+	return nil
+}
+
+// packageService defines the subset of package APIs as required for pruning
+type packageService interface {
+	GetRepositories() ([]string, error)
+	GetPackages(respository string) ([]pack.PackageEnvelope, error)
+	DeletePackage(loc.Locator) error
+}
+
+// Report describes the packages and BLOBs eligible for removal
+type Report struct {
+	// Packages lists the packages to remove
+	Packages []Item
+	// BLOBs lists the BLOBs not referenced by any package
+	BLOBs []blob.Envelope
+}
+
+// Item describes a package to remove
+type Item struct {
+	// Locator identifies the package
+	Locator loc.Locator
+	// SizeBytes is the package size in bytes
+	SizeBytes int64
+	// Reason describes why the package is removed
+	Reason string
+}
+
+// SizeBytes returns the total size of the packages and BLOBs in the report
+func (r Report) SizeBytes() (size int64) {
+	for _, item := range r.Packages {
+		size += item.SizeBytes
+	}
+	for _, envelope := range r.BLOBs {
+		size += envelope.SizeBytes
+	}
+	return size
+}
+
+// Prune removes application versions not retained by the configured policy
+// along with the packages only these applications depend on and the BLOBs
+// not referenced by any package
+func (r *cleanup) Prune(ctx context.Context) error {
+	report, err := r.Collect(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(r.Remove(ctx, *report))
+}
+
+// Remove removes the packages and BLOBs listed in the specified report.
+// Only displays the report in dry-run mode
+func (r *cleanup) Remove(ctx context.Context, report Report) error {
+	for _, item := range report.Packages {
+		r.PrintStep("Deleting package %v (%v, %v).", item.Locator,
+			item.Reason, humanize.Bytes(uint64(item.SizeBytes)))
+		if r.DryRun {
+			continue
+		}
+		r.WithField("package", item.Locator).Infof("Delete package: %v.", item.Reason)
+		err := r.Packages.DeletePackage(item.Locator)
+		if err != nil && !trace.IsNotFound(err) {
+			return trace.Wrap(err)
+		}
+	}
+	for _, envelope := range report.BLOBs {
+		r.PrintStep("Deleting BLOB %v not referenced by any package (%v).",
+			envelope.SHA512, humanize.Bytes(uint64(envelope.SizeBytes)))
+		if r.DryRun {
+			continue
+		}
+		r.WithField("blob", envelope.SHA512).Info("Delete BLOB not referenced by any package.")
+		err := r.Objects.DeleteBLOB(envelope.SHA512)
+		if err != nil && !trace.IsNotFound(err) {
+			return trace.Wrap(err)
+		}
+	}
+	r.PrintStep("Reclaimed %v in %v packages and %v BLOBs.",
+		humanize.Bytes(uint64(report.SizeBytes())), len(report.Packages), len(report.BLOBs))
+	return nil
+}
+
+// ClusterApps returns the applications installed on the clusters
+// known to the Ops Center including the Ops Center itself
+func ClusterApps(operator clusterLister) (apps []storage.Application, err error) {
+	accounts, err := operator.GetAccounts()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	for _, account := range accounts {
+		clusters, err := operator.GetSites(account.ID)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		for _, cluster := range clusters {
+			apps = append(apps, storage.Application{
+				Locator:  cluster.App.Package,
+				Manifest: cluster.App.Manifest,
+			})
+		}
+	}
+	return apps, nil
+}
+
+// clusterLister defines the subset of operator APIs as required
+// to collect applications used by clusters
+type clusterLister interface {
+	GetAccounts() ([]ops.Account, error)
+	GetSites(accountID string) ([]ops.Site, error)
+}
+
+// Collect computes the packages and BLOBs eligible for removal
+// without removing anything
+func (r *cleanup) Collect(ctx context.Context) (*Report, error) {
+	packages, err := r.getPackages()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	apps, skipped, err := r.getApps(packages)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	// Applications used by clusters and applications that cannot be
+	// considered for removal are kept along with their dependencies
+	pinned := append(skipped, r.Apps...)
+	retained := r.retain(apps, pinned)
+	required := r.mark(apps, retained, pinned)
+
+	var report Report
+	for _, app := range apps.sorted() {
+		if _, ok := retained[app.Locator]; ok {
+			continue
+		}
+		report.Packages = append(report.Packages, Item{
+			Locator:   app.Locator,
+			SizeBytes: app.SizeBytes,
+			Reason:    r.reason(app),
+		})
+		for _, dependency := range appPackages(app.Locator, *app.manifest) {
+			pkg, exists := packages[dependency]
+			if !exists || required[dependency] {
+				continue
+			}
+			// the dependency might be shared by several removed applications
+			required[dependency] = true
+			report.Packages = append(report.Packages, Item{
+				Locator:   pkg.Locator,
+				SizeBytes: pkg.SizeBytes,
+				Reason:    fmt.Sprintf("only used by %v", app.Locator),
+			})
+		}
+	}
+
+	if r.Objects != nil {
+		report.BLOBs, err = r.collectBLOBs(packages)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	return &report, nil
+}
+
+// getPackages returns all packages in the package service
+func (r *cleanup) getPackages() (map[loc.Locator]pack.PackageEnvelope, error) {
+	repositories, err := r.Packages.GetRepositories()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	packages := make(map[loc.Locator]pack.PackageEnvelope)
+	for _, repository := range repositories {
+		envelopes, err := r.Packages.GetPackages(repository)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		for _, envelope := range envelopes {
+			packages[envelope.Locator] = envelope
+		}
+	}
+	return packages, nil
+}
+
+// getApps returns the application packages grouped by application.
+// Applications with invalid versions are returned separately as skipped: they
+// are never deleted and neither are their dependencies.
+// Returns an error if any application manifest cannot be parsed as
+// the dependencies of the application are unknown
+func (r *cleanup) getApps(packages map[loc.Locator]pack.PackageEnvelope) (apps appMap, skipped []storage.Application, err error) {
+	apps = make(appMap)
+	for _, envelope := range packages {
+		if envelope.Type == "" || len(envelope.Manifest) == 0 {
+			continue
+		}
+		manifest, err := schema.ParseManifestYAMLNoValidate(envelope.Manifest)
+		if err != nil {
+			return nil, nil, trace.Wrap(err, "failed to parse manifest of application %v, "+
+				"its dependencies cannot be determined", envelope.Locator)
+		}
+		version, err := envelope.Locator.SemVer()
+		if err != nil {
+			r.WithField("package", envelope.Locator).WithError(err).Warn(
+				"Invalid application version, will not delete it or its dependencies.")
+			skipped = append(skipped, storage.Application{
+				Locator:  envelope.Locator,
+				Manifest: *manifest,
+			})
+			continue
+		}
+		key := envelope.Locator.ZeroVersion()
+		apps[key] = append(apps[key], app{
+			PackageEnvelope: envelope,
+			version:         *version,
+			manifest:        manifest,
+		})
+	}
+	for _, versions := range apps {
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[j].version.LessThan(versions[i].version)
+		})
+	}
+	return apps, skipped, nil
+}
+
+// retain returns the set of application packages retained by the policy
+// or pinned by the specified applications
+func (r *cleanup) retain(apps appMap, pinned []storage.Application) map[loc.Locator]struct{} {
+	retained := make(map[loc.Locator]struct{})
+	var queue []loc.Locator
+	for _, versions := range apps {
+		for i, app := range versions {
+			if i < r.Policy.KeepVersions || !r.expired(app) {
+				queue = append(queue, app.Locator)
+			}
+		}
+	}
+	for _, app := range pinned {
+		r.WithField("app", app.Locator).Debug("Application pinned.")
+		queue = append(queue, app.Locator)
+		queue = append(queue, appDependencies(app.Manifest)...)
+	}
+	// retain the applications the retained applications depend on
+	for len(queue) != 0 {
+		locator := queue[0]
+		queue = queue[1:]
+		if _, ok := retained[locator]; ok {
+			continue
+		}
+		retained[locator] = struct{}{}
+		if app, ok := apps.get(locator); ok {
+			queue = append(queue, appDependencies(*app.manifest)...)
+		}
+	}
+	return retained
+}
+
+// mark returns the set of packages the retained and pinned applications depend on
+func (r *cleanup) mark(apps appMap, retained map[loc.Locator]struct{}, pinned []storage.Application) map[loc.Locator]bool {
+	required := make(map[loc.Locator]bool)
+	for locator := range retained {
+		required[locator] = true
+		if app, ok := apps.get(locator); ok {
+			for _, dependency := range appPackages(locator, *app.manifest) {
+				required[dependency] = true
+			}
+		}
+	}
+	for _, app := range pinned {
+		for _, dependency := range appPackages(app.Locator, app.Manifest) {
+			required[dependency] = true
+		}
+	}
+	return required
+}
+
+// collectBLOBs returns the BLOBs not referenced by any of the specified packages
+func (r *cleanup) collectBLOBs(packages map[loc.Locator]pack.PackageEnvelope) (orphaned []blob.Envelope, err error) {
+	referenced := make(map[string]struct{}, len(packages))
+	for _, envelope := range packages {
+		referenced[envelope.SHA512] = struct{}{}
+	}
+	hashes, err := r.Objects.GetBLOBs()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	for _, hash := range hashes {
+		if _, ok := referenced[hash]; ok {
+			continue
+		}
+		envelope, err := r.Objects.GetBLOBEnvelope(hash)
+		if err != nil {
+			if trace.IsNotFound(err) {
+				continue
+			}
+			return nil, trace.Wrap(err)
+		}
+		// the package might still be uploading
+		if r.Clock.Now().Sub(envelope.Modified) < defaults.OrphanedBLOBGracePeriod {
+			continue
+		}
+		orphaned = append(orphaned, *envelope)
+	}
+	return orphaned, nil
+}
+
+// expired returns true if the specified application version
+// is old enough to be removed
+func (r *cleanup) expired(app app) bool {
+	return r.Clock.Now().Sub(app.Created) >= r.Policy.MaxAge
+}
+
+func (r *cleanup) reason(app app) string {
+	if r.Policy.MaxAge == 0 {
+		return fmt.Sprintf("not among %v latest versions", r.Policy.KeepVersions)
+	}
+	return fmt.Sprintf("not among %v latest versions and older than %v",
+		r.Policy.KeepVersions, r.Policy.MaxAge)
+}
+
+// appDependencies returns the applications the application with
+// the specified manifest depends on
+func appDependencies(manifest schema.Manifest) []loc.Locator {
+	dependencies := manifest.Dependencies.GetApps()
+	if base := manifest.Base(); base != nil {
+		dependencies = append(dependencies, *base)
+	}
+	return dependencies
+}
+
+// appPackages returns the packages the specified application depends on
+// including its resources package
+func appPackages(locator loc.Locator, manifest schema.Manifest) []loc.Locator {
+	return append(manifest.AllPackageDependencies(), loc.Locator{
+		Repository: locator.Repository,
+		Name:       fmt.Sprintf("%v-resources", locator.Name),
+		Version:    locator.Version,
+	})
+}
+
+// appMap maps applications to their versions sorted from the latest
+type appMap map[loc.Locator][]app
+
+func (r appMap) get(locator loc.Locator) (*app, bool) {
+	for _, app := range r[locator.ZeroVersion()] {
+		if app.Locator.IsEqualTo(locator) {
+			return &app, true
+		}
+	}
+	return nil, false
+}
+
+// sorted returns all application versions in a stable order
+func (r appMap) sorted() (apps []app) {
+	for _, versions := range r {
+		apps = append(apps, versions...)
+	}
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].Locator.String() < apps[j].Locator.String()
+	})
+	return apps
+}
+
+type app struct {
+	pack.PackageEnvelope
+	version  semver.Version
+	manifest *schema.Manifest
+}
+
+type cleanup struct {
+	Config
+}
@@ -0,0 +1,304 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opscenter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gravitational/gravity/lib/blob/fs"
+	"github.com/gravitational/gravity/lib/loc"
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/schema"
+	"github.com/gravitational/gravity/lib/storage"
+
+	"github.com/jonboulle/clockwork"
+	. "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOpsCenter(t *testing.T) { TestingT(t) }
+
+type S struct {
+	clock clockwork.FakeClock
+}
+
+var _ = Suite(&S{})
+
+func (s *S) SetUpTest(c *C) {
+	s.clock = clockwork.NewFakeClockAt(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func (s *S) TestKeepsLatestVersions(c *C) {
+	packages := testPackages{
+		newPackage("gravitational.io/planet:0.0.1"),
+		newPackage("gravitational.io/planet:0.0.2"),
+		newPackage("gravitational.io/shared:0.0.1"),
+		newApp(c, "gravitational.io/app:0.0.1", s.clock.Now(), "gravitational.io/planet:0.0.1", "gravitational.io/shared:0.0.1"),
+		newApp(c, "gravitational.io/app:0.0.2", s.clock.Now(), "gravitational.io/planet:0.0.1", "gravitational.io/shared:0.0.1"),
+		newApp(c, "gravitational.io/app:0.0.3", s.clock.Now(), "gravitational.io/planet:0.0.2", "gravitational.io/shared:0.0.1"),
+		newPackage("gravitational.io/unrelated:0.0.1"),
+	}
+
+	pruner, err := New(Config{
+		Packages: &packages,
+		Policy:   Policy{KeepVersions: 1},
+		Clock:    s.clock,
+	})
+	c.Assert(err, IsNil)
+	c.Assert(pruner.Prune(context.TODO()), IsNil)
+
+	c.Assert(packages.locators(), DeepEquals, []string{
+		"gravitational.io/app:0.0.3",
+		"gravitational.io/planet:0.0.2",
+		"gravitational.io/shared:0.0.1",
+		"gravitational.io/unrelated:0.0.1",
+	})
+}
+
+func (s *S) TestKeepsApplicationsUsedByClusters(c *C) {
+	packages := testPackages{
+		newPackage("gravitational.io/planet:0.0.1"),
+		newPackage("gravitational.io/planet:0.0.2"),
+		newApp(c, "gravitational.io/runtime:0.0.1", s.clock.Now(), "gravitational.io/planet:0.0.1"),
+		newApp(c, "gravitational.io/runtime:0.0.2", s.clock.Now(), "gravitational.io/planet:0.0.2"),
+		newApp(c, "gravitational.io/app:0.0.1", s.clock.Now()),
+		newApp(c, "gravitational.io/app:0.0.2", s.clock.Now()),
+	}
+	clusterApp := packages[4]
+	clusterApp.manifest.SetBase(loc.MustParseLocator("gravitational.io/runtime:0.0.1"))
+
+	pruner, err := New(Config{
+		Packages: &packages,
+		Apps: []storage.Application{{
+			Locator:  clusterApp.Locator,
+			Manifest: *clusterApp.manifest,
+		}},
+		Policy: Policy{KeepVersions: 1},
+		Clock:  s.clock,
+	})
+	c.Assert(err, IsNil)
+	report, err := pruner.Collect(context.TODO())
+	c.Assert(err, IsNil)
+	c.Assert(report.Packages, HasLen, 0)
+}
+
+func (s *S) TestKeepsDependenciesOfSkippedApplications(c *C) {
+	invalidVersion := newApp(c, "gravitational.io/legacy:0.0.1", s.clock.Now(), "gravitational.io/planet:0.0.1")
+	invalidVersion.Locator.Version = "latest"
+	packages := testPackages{
+		newPackage("gravitational.io/planet:0.0.1"),
+		newPackage("gravitational.io/planet:0.0.2"),
+		newApp(c, "gravitational.io/app:0.0.1", s.clock.Now(), "gravitational.io/planet:0.0.1"),
+		newApp(c, "gravitational.io/app:0.0.2", s.clock.Now(), "gravitational.io/planet:0.0.2"),
+		invalidVersion,
+	}
+
+	pruner, err := New(Config{
+		Packages: &packages,
+		Policy:   Policy{KeepVersions: 1},
+		Clock:    s.clock,
+	})
+	c.Assert(err, IsNil)
+	c.Assert(pruner.Prune(context.TODO()), IsNil)
+
+	c.Assert(packages.locators(), DeepEquals, []string{
+		"gravitational.io/app:0.0.2",
+		"gravitational.io/legacy:latest",
+		"gravitational.io/planet:0.0.1",
+		"gravitational.io/planet:0.0.2",
+	})
+}
+
+func (s *S) TestAbortsOnInvalidManifest(c *C) {
+	invalidManifest := newApp(c, "gravitational.io/broken:0.0.1", s.clock.Now())
+	invalidManifest.Manifest = []byte("{invalid")
+	packages := testPackages{
+		newPackage("gravitational.io/planet:0.0.1"),
+		newApp(c, "gravitational.io/app:0.0.1", s.clock.Now(), "gravitational.io/planet:0.0.1"),
+		newApp(c, "gravitational.io/app:0.0.2", s.clock.Now()),
+		invalidManifest,
+	}
+
+	pruner, err := New(Config{
+		Packages: &packages,
+		Policy:   Policy{KeepVersions: 1},
+		Clock:    s.clock,
+	})
+	c.Assert(err, IsNil)
+	c.Assert(pruner.Prune(context.TODO()), NotNil)
+	c.Assert(packages, HasLen, 4)
+}
+
+func (s *S) TestKeepsRecentVersions(c *C) {
+	packages := testPackages{
+		newApp(c, "gravitational.io/app:0.0.1", s.clock.Now().Add(-48*time.Hour)),
+		newApp(c, "gravitational.io/app:0.0.2", s.clock.Now().Add(-time.Hour)),
+		newApp(c, "gravitational.io/app:0.0.3", s.clock.Now()),
+	}
+
+	pruner, err := New(Config{
+		Packages: &packages,
+		Policy:   Policy{KeepVersions: 1, MaxAge: 24 * time.Hour},
+		Clock:    s.clock,
+	})
+	c.Assert(err, IsNil)
+	c.Assert(pruner.Prune(context.TODO()), IsNil)
+
+	c.Assert(packages.locators(), DeepEquals, []string{
+		"gravitational.io/app:0.0.2",
+		"gravitational.io/app:0.0.3",
+	})
+}
+
+func (s *S) TestNoopIfDryRun(c *C) {
+	packages := testPackages{
+		newApp(c, "gravitational.io/app:0.0.1", s.clock.Now()),
+		newApp(c, "gravitational.io/app:0.0.2", s.clock.Now()),
+	}
+
+	pruner, err := New(Config{
+		Packages: &packages,
+		Policy:   Policy{KeepVersions: 1},
+		Clock:    s.clock,
+	})
+	pruner.DryRun = true
+	c.Assert(err, IsNil)
+	c.Assert(pruner.Prune(context.TODO()), IsNil)
+
+	c.Assert(packages.locators(), DeepEquals, []string{
+		"gravitational.io/app:0.0.1",
+		"gravitational.io/app:0.0.2",
+	})
+}
+
+func (s *S) TestRemovesOrphanedBLOBs(c *C) {
+	objects, err := fs.New(c.MkDir())
+	c.Assert(err, IsNil)
+	defer objects.Close()
+
+	used, err := objects.WriteBLOB(bytes.NewBufferString("used"))
+	c.Assert(err, IsNil)
+	orphaned, err := objects.WriteBLOB(bytes.NewBufferString("orphaned"))
+	c.Assert(err, IsNil)
+
+	pkg := newPackage("gravitational.io/planet:0.0.1")
+	pkg.SHA512 = used.SHA512
+	packages := testPackages{pkg}
+
+	pruner, err := New(Config{
+		Packages: &packages,
+		Objects:  objects,
+		Clock:    clockwork.NewFakeClockAt(time.Now()),
+	})
+	c.Assert(err, IsNil)
+	report, err := pruner.Collect(context.TODO())
+	c.Assert(err, IsNil)
+	c.Assert(report.BLOBs, HasLen, 0, Commentf("Recently written BLOBs should be kept."))
+
+	pruner.Clock = clockwork.NewFakeClockAt(time.Now().Add(2 * time.Hour))
+	c.Assert(pruner.Prune(context.TODO()), IsNil)
+
+	hashes, err := objects.GetBLOBs()
+	c.Assert(err, IsNil)
+	c.Assert(hashes, DeepEquals, []string{used.SHA512})
+	_, err = objects.GetBLOBEnvelope(orphaned.SHA512)
+	c.Assert(err, NotNil)
+}
+
+func newPackage(locator string) testPackage {
+	return testPackage{
+		PackageEnvelope: pack.PackageEnvelope{
+			Locator: loc.MustParseLocator(locator),
+		},
+	}
+}
+
+func newApp(c *C, locator string, created time.Time, dependencies ...string) testPackage {
+	pkg := newPackage(locator)
+	pkg.Type = string(storage.AppUser)
+	pkg.Created = created
+	pkg.manifest = &schema.Manifest{
+		Header: schema.Header{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       schema.KindBundle,
+				APIVersion: schema.APIVersionV2,
+			},
+			Metadata: schema.Metadata{
+				Name:            pkg.Locator.Name,
+				ResourceVersion: pkg.Locator.Version,
+			},
+		},
+	}
+	for _, dependency := range dependencies {
+		pkg.manifest.Dependencies.Packages = append(pkg.manifest.Dependencies.Packages,
+			schema.Dependency{Locator: loc.MustParseLocator(dependency)})
+	}
+	var err error
+	pkg.Manifest, err = json.Marshal(pkg.manifest)
+	c.Assert(err, IsNil)
+	return pkg
+}
+
+func (r testPackages) GetRepositories() (repositories []string, err error) {
+	seen := make(map[string]bool)
+	for _, pkg := range r {
+		if !seen[pkg.Locator.Repository] {
+			seen[pkg.Locator.Repository] = true
+			repositories = append(repositories, pkg.Locator.Repository)
+		}
+	}
+	return repositories, nil
+}
+
+func (r testPackages) GetPackages(repository string) (envelopes []pack.PackageEnvelope, err error) {
+	for _, pkg := range r {
+		if pkg.Locator.Repository == repository {
+			envelopes = append(envelopes, pkg.PackageEnvelope)
+		}
+	}
+	return envelopes, nil
+}
+
+func (r *testPackages) DeletePackage(locator loc.Locator) error {
+	for i := range *r {
+		if (*r)[i].Locator.IsEqualTo(locator) {
+			*r = append((*r)[:i], (*r)[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r testPackages) locators() (locators []string) {
+	for _, pkg := range r {
+		locators = append(locators, pkg.Locator.String())
+	}
+	sort.Strings(locators)
+	return locators
+}
+
+type testPackages []testPackage
+
+type testPackage struct {
+	pack.PackageEnvelope
+	manifest *schema.Manifest
+}
@@ -321,6 +321,11 @@ func pullApp(env *localenv.LocalEnvironment, appPackage loc.Locator, portalURL s
 		return trace.Wrap(err)
 	}
 
+	verifier, err := localClusterVerifier(env)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
 	req := service.AppPullRequest{
 		SrcPack:  remotePackages,
 		DstPack:  env.Packages,
@@ -330,6 +335,7 @@ func pullApp(env *localenv.LocalEnvironment, appPackage loc.Locator, portalURL s
 		Labels:   labels,
 		Progress: env.Reporter,
 		Upsert:   force,
+		Verifier: verifier,
 	}
 
 	if _, err = service.PullApp(req); err != nil {
@@ -363,6 +369,16 @@ func pushApp(env *localenv.LocalEnvironment, appPackage loc.Locator, portalURL s
 		return trace.Wrap(err)
 	}
 
+	operator, err := env.OperatorService(portalURL)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	verifier, err := clusterVerifier(operator)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
 	req := service.AppPullRequest{
 		SrcPack:  env.Packages,
 		DstPack:  remotePackages,
@@ -370,6 +386,7 @@ func pushApp(env *localenv.LocalEnvironment, appPackage loc.Locator, portalURL s
 		DstApp:   remoteApps,
 		Package:  appPackage,
 		Progress: env.Reporter,
+		Verifier: verifier,
 	}
 
 	if _, err = service.PullApp(req); err != nil {
@@ -18,33 +18,44 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/gravitational/gravity/lib/app"
 	"github.com/gravitational/gravity/lib/app/hooks"
 	"github.com/gravitational/gravity/lib/archive"
+	libbackup "github.com/gravitational/gravity/lib/backup"
+	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/localenv"
 	"github.com/gravitational/gravity/lib/schema"
 	"github.com/gravitational/gravity/lib/utils"
 
 	dockerarchive "github.com/docker/docker/pkg/archive"
+	"github.com/dustin/go-humanize"
 	teleutils "github.com/gravitational/teleport/lib/utils"
 	"github.com/gravitational/trace"
 	v1 "k8s.io/api/core/v1"
 )
 
-func backup(env *localenv.LocalEnvironment, tarball string, timeout time.Duration, follow, silent bool) (err error) {
+func backup(env *localenv.LocalEnvironment, location, passphrase string, timeout time.Duration, follow, silent bool) (err error) {
 	ctx := context.Background()
+	target, name, err := libbackup.ParseTarget(location, passphrase)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer target.Close()
 	// if we're streaming logs to stdout, no much sense in showing our progress indicator
 	noProgress := silent || follow
 	progress := utils.NewProgress(ctx, "backup", 2, noProgress)
 	defer progress.Stop()
-	progress.NextStep("backing up to %v", tarball)
+	progress.NextStep("backing up to %v", location)
 	return runBackupRestore(env, "backup",
 		func(env *localenv.LocalEnvironment, backupPath string, req *app.HookRunRequest) error {
 			req.Hook = schema.HookBackup
@@ -72,33 +83,73 @@ func backup(env *localenv.LocalEnvironment, tarball string, timeout time.Duratio
 					log.Errorf("failed to remove backup directory %s: %v", backupPath, err)
 				}
 			}()
-			err = compressDirectory(backupPath, tarball)
+			tarball, err := dockerarchive.Tar(backupPath, dockerarchive.Gzip)
 			if err != nil {
-				return trace.Wrap(err)
+				return trace.Wrap(err, "failed to compress the backup directory %v", backupPath)
+			}
+			defer tarball.Close()
+			err = target.Put(ctx, name, tarball)
+			if err != nil {
+				return trace.Wrap(err, "failed to write backup to %v", target)
 			}
-			progress.NextStep("backup is written to %v", tarball)
+			progress.NextStep("backup is written to %v", location)
 			return nil
 		})
 }
 
-func restore(env *localenv.LocalEnvironment, tarball string, timeout time.Duration, follow, silent bool) error {
+func restore(env *localenv.LocalEnvironment, location, passphrase string, timeout time.Duration, follow, silent bool) error {
+	target, name, err := libbackup.ParseTarget(location, passphrase)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer target.Close()
+	tarball, err := target.Get(context.TODO(), name)
+	if err != nil {
+		return trace.Wrap(err, "failed to open the tarball %q with backed up data", location)
+	}
+	defer tarball.Close()
+	return restoreFrom(env, tarball, location, timeout, follow, silent)
+}
+
+// restoreFromCluster restores the application state from the backup
+// with the specified name stored in the cluster
+func restoreFromCluster(env *localenv.LocalEnvironment, name string, timeout time.Duration, follow, silent bool) error {
+	operator, err := env.SiteOperator()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	cluster, err := operator.GetLocalSite()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	tarball, err := operator.GetBackupData(cluster.Key(), name)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer tarball.Close()
+	return restoreFrom(env, tarball, name, timeout, follow, silent)
+}
+
+// restoreFrom restores the application state from the provided backup tarball
+func restoreFrom(env *localenv.LocalEnvironment, tarball io.Reader, source string, timeout time.Duration, follow, silent bool) error {
 	ctx := context.Background()
 	// if we're streaming logs to stdout, no much sense in showing our progress indicator
 	noProgress := silent || follow
 	progress := utils.NewProgress(ctx, "restore", 2, noProgress)
 	defer progress.Stop()
-	progress.NextStep("restoring from %v", tarball)
+	progress.NextStep("restoring from %v", source)
 	return runBackupRestore(env, "restore",
 		func(env *localenv.LocalEnvironment, backupPath string, req *app.HookRunRequest) error {
-			f, err := os.Open(tarball)
-			if err != nil {
-				return trace.Wrap(err, "failed to open the tarball %q with backed up data", tarball)
-			}
-			defer f.Close()
-			err = dockerarchive.Untar(f, backupPath, archive.DefaultOptions())
+			err := dockerarchive.Untar(tarball, backupPath, archive.DefaultOptions())
 			if err != nil {
 				return trace.Wrap(err)
 			}
+			// untar can stop before the end of the stream, read the rest
+			// so that encrypted backups are checked for integrity
+			if _, err = io.Copy(ioutil.Discard, tarball); err != nil {
+				os.RemoveAll(backupPath)
+				return trace.Wrap(err, "failed to verify backup %v", source)
+			}
 			defer func() {
 				if err = os.RemoveAll(backupPath); err != nil {
 					log.Errorf("failed to remove restore directory %s: %v", backupPath, err)
@@ -116,11 +167,54 @@ func restore(env *localenv.LocalEnvironment, tarball string, timeout time.Durati
 			if err != nil {
 				return trace.Wrap(err)
 			}
-			progress.NextStep("restored from %v", tarball)
+			progress.NextStep("restored from %v", source)
 			return nil
 		})
 }
 
+// listBackups displays backups stored in the cluster
+func listBackups(env *localenv.LocalEnvironment, format constants.Format) error {
+	operator, err := env.SiteOperator()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	cluster, err := operator.GetLocalSite()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	backups, err := operator.GetBackups(cluster.Key())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	switch format {
+	case constants.EncodingJSON:
+		bytes, err := json.MarshalIndent(backups, "", "  ")
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		fmt.Println(string(bytes))
+	default:
+		if len(backups) == 0 {
+			fmt.Println("No backups found")
+			return nil
+		}
+		w := new(tabwriter.Writer)
+		w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+		fmt.Fprintf(w, "Name\tSchedule\tApplication\tSize\tCreated\n")
+		fmt.Fprintf(w, "----\t--------\t-----------\t----\t-------\n")
+		for _, b := range backups {
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n",
+				b.Name,
+				b.Schedule,
+				b.Application,
+				humanize.Bytes(uint64(b.SizeBytes)),
+				b.Created.Format(constants.HumanDateFormatSeconds))
+		}
+		w.Flush()
+	}
+	return nil
+}
+
 func runBackupRestore(env *localenv.LocalEnvironment, operation string,
 	fn func(env *localenv.LocalEnvironment, backupPath string, req *app.HookRunRequest) error) (err error) {
 
@@ -174,19 +268,21 @@ func runBackupRestore(env *localenv.LocalEnvironment, operation string,
 	return trace.Wrap(err)
 }
 
-func compressDirectory(dir, outputTarball string) error {
-	archive, err := dockerarchive.Tar(dir, dockerarchive.Gzip)
-	if err != nil {
-		return trace.Wrap(err, "failed to compress the backup directory %v", dir)
+// readPassphrase returns the backup encryption passphrase from the specified
+// file or an empty string if the path is empty
+func readPassphrase(path string) (string, error) {
+	if path == "" {
+		return "", nil
 	}
-	defer archive.Close()
-	f, err := os.Create(outputTarball)
+	data, err := ioutil.ReadFile(path)
 	if err != nil {
-		return trace.ConvertSystemError(err)
+		return "", trace.ConvertSystemError(err)
 	}
-	defer f.Close()
-	_, err = io.Copy(f, archive)
-	return trace.Wrap(err)
+	passphrase := strings.TrimSpace(string(data))
+	if passphrase == "" {
+		return "", trace.BadParameter("passphrase file %v is empty", path)
+	}
+	return passphrase, nil
 }
 
 // getStreamingWriter returns appropriate writer based on silent/follow flags
@@ -33,12 +33,12 @@ import (
 )
 
 // resetConfig executes the loop to reset cluster configuration to defaults
-func resetConfig(ctx context.Context, localEnv, updateEnv *localenv.LocalEnvironment, manual, confirmed bool) error {
+func resetConfig(ctx context.Context, localEnv, updateEnv *localenv.LocalEnvironment, manual, confirmed bool, concurrency int) error {
 	config := libclusterconfig.NewEmpty()
-	return trace.Wrap(updateConfig(ctx, localEnv, updateEnv, config, manual, confirmed))
+	return trace.Wrap(updateConfig(ctx, localEnv, updateEnv, config, manual, confirmed, concurrency))
 }
 
-func updateConfig(ctx context.Context, localEnv, updateEnv *localenv.LocalEnvironment, config libclusterconfig.Interface, manual, confirmed bool) error {
+func updateConfig(ctx context.Context, localEnv, updateEnv *localenv.LocalEnvironment, config libclusterconfig.Interface, manual, confirmed bool, concurrency int) error {
 	if err := validateCloudConfig(localEnv, config); err != nil {
 		return trace.Wrap(err)
 	}
@@ -57,7 +57,7 @@ func updateConfig(ctx context.Context, localEnv, updateEnv *localenv.LocalEnviro
 			return nil
 		}
 	}
-	updater, err := newConfigUpdater(ctx, localEnv, updateEnv, config)
+	updater, err := newConfigUpdater(ctx, localEnv, updateEnv, config, concurrency)
 	if err != nil {
 		return trace.Wrap(err)
 	}
@@ -70,14 +70,15 @@ func updateConfig(ctx context.Context, localEnv, updateEnv *localenv.LocalEnviro
 	return nil
 }
 
-func newConfigUpdater(ctx context.Context, localEnv, updateEnv *localenv.LocalEnvironment, config libclusterconfig.Interface) (*update.Updater, error) {
+func newConfigUpdater(ctx context.Context, localEnv, updateEnv *localenv.LocalEnvironment, config libclusterconfig.Interface, concurrency int) (*update.Updater, error) {
 	configBytes, err := libclusterconfig.Marshal(config)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
 	init := configInitializer{
-		resource: configBytes,
-		config:   config,
+		resource:    configBytes,
+		config:      config,
+		concurrency: concurrency,
 	}
 	return newUpdater(ctx, localEnv, updateEnv, init)
 }
@@ -148,6 +149,43 @@ func getConfigUpdater(localEnv, updateEnv *localenv.LocalEnvironment, operation
 	return updater, nil
 }
 
+// initConfigOperationPlan creates the plan for the specified configuration update
+// operation and replicates it to the local backend
+func initConfigOperationPlan(localEnv, updateEnv *localenv.LocalEnvironment, clusterEnv *localenv.ClusterEnvironment, cluster ops.Site, operation ops.SiteOperation) error {
+	if operation.UpdateConfig == nil {
+		return trace.BadParameter("operation %v is missing the configuration", operation)
+	}
+	config, err := libclusterconfig.Unmarshal(operation.UpdateConfig.Config)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	plan, err := clusterconfig.NewOperationPlan(clusterEnv.Operator, clusterEnv.Apps, operation, config, cluster.ClusterState.Servers)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = update.SyncOperationPlan(clusterEnv.Backend, updateEnv.Backend, *plan,
+		(storage.SiteOperation)(operation))
+	return trace.Wrap(err)
+}
+
+// executeAutomaticConfigUpdate executes the configuration update operation
+// after the plan has been initialized and the agents deployed
+func executeAutomaticConfigUpdate(ctx context.Context, localEnv, updateEnv *localenv.LocalEnvironment, args []string) error {
+	operation, err := storage.GetLastOperation(updateEnv.Backend)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if operation.Type != ops.OperationUpdateConfig {
+		return trace.BadParameter("expected configuration update operation but got %q", operation.Type)
+	}
+	updater, err := getConfigUpdater(localEnv, updateEnv, (ops.SiteOperation)(*operation))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer updater.Close()
+	return trace.Wrap(updater.Run(ctx, false))
+}
+
 func (r configInitializer) validatePreconditions(*localenv.LocalEnvironment, ops.Operator, ops.Site) error {
 	return nil
 }
@@ -155,8 +193,9 @@ func (r configInitializer) validatePreconditions(*localenv.LocalEnvironment, ops
 func (r configInitializer) newOperation(operator ops.Operator, cluster ops.Site) (*ops.SiteOperationKey, error) {
 	key, err := operator.CreateUpdateConfigOperation(context.TODO(),
 		ops.CreateUpdateConfigOperationRequest{
-			ClusterKey: cluster.Key(),
-			Config:     r.resource,
+			ClusterKey:  cluster.Key(),
+			Config:      r.resource,
+			Concurrency: r.concurrency,
 		},
 	)
 	if err != nil {
@@ -221,6 +260,9 @@ func (configInitializer) updateDeployRequest(req deployAgentsRequest) deployAgen
 type configInitializer struct {
 	resource []byte
 	config   libclusterconfig.Interface
+	// concurrency specifies the maximum number of regular nodes
+	// updated at the same time
+	concurrency int
 }
 
 func validateCloudConfig(localEnv *localenv.LocalEnvironment, config libclusterconfig.Interface) error {
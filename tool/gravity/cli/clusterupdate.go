@@ -18,6 +18,7 @@ package cli
 
 import (
 	"context"
+	"time"
 
 	"github.com/gravitational/gravity/lib/app"
 	"github.com/gravitational/gravity/lib/constants"
@@ -58,10 +59,12 @@ func updateTrigger(
 	localEnv *localenv.LocalEnvironment,
 	updateEnv *localenv.LocalEnvironment,
 	updatePackage string,
+	strategy *storage.UpdateStrategy,
+	drainPolicy *storage.DrainPolicy,
 	manual, block, noValidateVersion bool,
 ) error {
 	ctx := context.TODO()
-	updater, err := newClusterUpdater(ctx, localEnv, updateEnv, updatePackage, manual, block, noValidateVersion)
+	updater, err := newClusterUpdater(ctx, localEnv, updateEnv, updatePackage, strategy, drainPolicy, manual, block, noValidateVersion)
 	if err != nil {
 		return trace.Wrap(err)
 	}
@@ -71,6 +74,10 @@ func updateTrigger(
 		return nil
 	}
 	if !manual {
+		err = waitForMaintenanceWindow(ctx, localEnv, ops.MaintenanceUpdate)
+		if err != nil {
+			return trace.Wrap(err)
+		}
 		err = updater.Run(ctx, false)
 		return trace.Wrap(err)
 	}
@@ -78,15 +85,51 @@ func updateTrigger(
 	return nil
 }
 
+// updateDryRun generates the plan of the update operation to the specified
+// application package and displays it along with the summary of changes
+// it would make to the cluster. No operation is created.
+// If planFile is not empty, the plan is also saved to the specified file
+func updateDryRun(localEnv *localenv.LocalEnvironment, updatePackage string, strategy *storage.UpdateStrategy, drainPolicy *storage.DrainPolicy, planFile string) error {
+	clusterEnv, err := localEnv.NewClusterEnvironment()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if clusterEnv.Client == nil {
+		return trace.BadParameter("this operation can only be executed on one of the master nodes")
+	}
+	cluster, err := clusterEnv.Operator.GetLocalSite()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	init := &clusterInitializer{updatePackage: updatePackage}
+	err = init.validatePreconditions(localEnv, clusterEnv.Operator, *cluster)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	plan, err := clusterupdate.NewDryRunOperationPlan(localEnv, clusterEnv, init.updateLoc, strategy, drainPolicy)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	impact, err := clusterupdate.GetPlanImpact(*plan, clusterEnv.Apps)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(outputDryRunPlan(*plan, *impact, planFile))
+}
+
 func newClusterUpdater(
 	ctx context.Context,
 	localEnv, updateEnv *localenv.LocalEnvironment,
 	updatePackage string,
+	strategy *storage.UpdateStrategy,
+	drainPolicy *storage.DrainPolicy,
 	manual, block, noValidateVersion bool,
 ) (updater, error) {
 	unattended := !manual && !block
 	init := &clusterInitializer{
 		updatePackage: updatePackage,
+		strategy:      strategy,
+		drainPolicy:   drainPolicy,
 		unattended:    unattended,
 	}
 	updater, err := newUpdater(ctx, localEnv, updateEnv, init)
@@ -188,9 +231,11 @@ func (r *clusterInitializer) validatePreconditions(localEnv *localenv.LocalEnvir
 
 func (r clusterInitializer) newOperation(operator ops.Operator, cluster ops.Site) (*ops.SiteOperationKey, error) {
 	return operator.CreateSiteAppUpdateOperation(context.TODO(), ops.CreateSiteAppUpdateOperationRequest{
-		AccountID:  cluster.AccountID,
-		SiteDomain: cluster.Domain,
-		App:        r.updateLoc.String(),
+		AccountID:   cluster.AccountID,
+		SiteDomain:  cluster.Domain,
+		App:         r.updateLoc.String(),
+		Strategy:    r.strategy,
+		DrainPolicy: r.drainPolicy,
 	})
 }
 
@@ -248,7 +293,50 @@ func (r clusterInitializer) updateDeployRequest(req deployAgentsRequest) deployA
 type clusterInitializer struct {
 	updateLoc     loc.Locator
 	updatePackage string
-	unattended    bool
+	// strategy optionally defines how regular nodes are updated
+	strategy *storage.UpdateStrategy
+	// drainPolicy defines how blocked evictions are handled during node drains
+	drainPolicy *storage.DrainPolicy
+	unattended  bool
+}
+
+// newUpdateStrategy returns the node update strategy for the specified
+// number of canary nodes and batch size.
+// Returns nil if neither has been specified
+func newUpdateStrategy(canaryNodes, batchSize int) (*storage.UpdateStrategy, error) {
+	if canaryNodes == 0 && batchSize == 0 {
+		return nil, nil
+	}
+	if batchSize == 0 {
+		batchSize = 1
+	}
+	strategy := &storage.UpdateStrategy{
+		CanaryNodes: canaryNodes,
+		BatchSize:   batchSize,
+	}
+	if err := strategy.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return strategy, nil
+}
+
+// newDrainPolicy returns the drain policy for the specified action and timeout
+func newDrainPolicy(action string, timeout time.Duration) (*storage.DrainPolicy, error) {
+	policy := &storage.DrainPolicy{
+		Action:  storage.DrainAction(action),
+		Timeout: timeout,
+	}
+	if err := policy.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return policy, nil
+}
+
+// drainActions lists the supported drain policy actions
+var drainActions = []string{
+	string(storage.DrainActionWait),
+	string(storage.DrainActionForce),
+	string(storage.DrainActionAbort),
 }
 
 const (
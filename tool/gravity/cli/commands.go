@@ -72,6 +72,12 @@ type Application struct {
 	UpdatePlanInitCmd UpdatePlanInitCmd
 	// PlanDisplayCmd displays plan of an operation
 	PlanDisplayCmd PlanDisplayCmd
+	// PlanExportCmd exports plan of an operation
+	PlanExportCmd PlanExportCmd
+	// PlanDiffCmd compares two exported operation plans
+	PlanDiffCmd PlanDiffCmd
+	// PlanTimelineCmd displays execution timeline of an operation
+	PlanTimelineCmd PlanTimelineCmd
 	// PlanExecuteCmd executes a phase of an active operation
 	PlanExecuteCmd PlanExecuteCmd
 	// PlanRollbackCmd rolls back a phase of an active operation
@@ -98,8 +104,12 @@ type Application struct {
 	StatusCmd StatusCmd
 	// StatusResetCmd resets the cluster to active state
 	StatusResetCmd StatusResetCmd
-	// BackupCmd launches app backup hook
+	// BackupCmd combines subcommands for cluster backups
 	BackupCmd BackupCmd
+	// BackupCreateCmd launches app backup hook
+	BackupCreateCmd BackupCreateCmd
+	// BackupListCmd lists backups stored in the cluster
+	BackupListCmd BackupListCmd
 	// RestoreCmd launches app restore hook
 	RestoreCmd RestoreCmd
 	// CheckCmd checks that the host satisfies app manifest requirements
@@ -160,6 +170,14 @@ type Application struct {
 	OpsListCmd OpsListCmd
 	// OpsAgentCmd launches install agent
 	OpsAgentCmd OpsAgentCmd
+	// FleetCmd combines subcommands for fleet operations
+	FleetCmd FleetCmd
+	// FleetUpdateCmd updates a fleet of clusters to a new application version
+	FleetUpdateCmd FleetUpdateCmd
+	// FleetConfigCmd updates the configuration of a fleet of clusters
+	FleetConfigCmd FleetConfigCmd
+	// FleetStatusCmd displays the status of fleet operations
+	FleetStatusCmd FleetStatusCmd
 	// PackCmd combines subcommands for package service
 	PackCmd PackCmd
 	// PackImportCmd imports package into cluster
@@ -232,12 +250,16 @@ type Application struct {
 	RPCAgentInstallCmd RPCAgentInstallCmd
 	// RPCAgentRunCmd runs RPC agent
 	RPCAgentRunCmd RPCAgentRunCmd
+	// RPCAgentCopyCmd copies files to or from RPC agents
+	RPCAgentCopyCmd RPCAgentCopyCmd
 	// SystemCmd combines system subcommands
 	SystemCmd SystemCmd
 	// SystemRotateCertsCmd renews cluster certificates on local node
 	SystemRotateCertsCmd SystemRotateCertsCmd
 	// SystemExportCACmd exports cluster CA
 	SystemExportCACmd SystemExportCACmd
+	// SystemRotateRPCCredsCmd rotates the credentials of cluster RPC agents
+	SystemRotateRPCCredsCmd SystemRotateRPCCredsCmd
 	// SystemUninstallCmd uninstalls all gravity services from local node
 	SystemUninstallCmd SystemUninstallCmd
 	// SystemPullUpdatesCmd pulls updates for system packages
@@ -266,6 +288,8 @@ type Application struct {
 	SystemReportCmd SystemReportCmd
 	// SystemStateDirCmd shows local state directory
 	SystemStateDirCmd SystemStateDirCmd
+	// SystemConvertAlertsCmd converts alerts between monitoring providers
+	SystemConvertAlertsCmd SystemConvertAlertsCmd
 	// SystemDevicemapperCmd combines devicemapper related subcommands
 	SystemDevicemapperCmd SystemDevicemapperCmd
 	// SystemDevicemapperMountCmd configures devicemapper environment
@@ -286,6 +310,8 @@ type Application struct {
 	SystemGCJournalCmd SystemGCJournalCmd
 	// SystemGCPackageCmd removes unused packages
 	SystemGCPackageCmd SystemGCPackageCmd
+	// SystemGCOpsCenterCmd removes unused packages from the Ops Center
+	SystemGCOpsCenterCmd SystemGCOpsCenterCmd
 	// SystemGCRegistryCmd removes unused docker images
 	SystemGCRegistryCmd SystemGCRegistryCmd
 	// GarbageCollectCmd prunes unused resources (package/journal files/docker images)
@@ -429,6 +455,10 @@ type JoinCmd struct {
 	Force *bool
 	// OperationID is the ID of the operation created via UI
 	OperationID *string
+	// DryRun displays the operation plan without creating the operation
+	DryRun *bool
+	// PlanFile optionally specifies the file to save the dry-run plan to
+	PlanFile *string
 }
 
 // AutoJoinCmd uses cloud provider info to join existing cluster
@@ -482,6 +512,33 @@ type PlanDisplayCmd struct {
 	Output *constants.Format
 }
 
+// PlanExportCmd exports plan of a specific operation
+type PlanExportCmd struct {
+	*kingpin.CmdClause
+	// Format is the export format, json or yaml
+	Format *constants.Format
+	// File is the optional file to write the plan to
+	File *string
+}
+
+// PlanTimelineCmd displays execution timeline of a specific operation
+type PlanTimelineCmd struct {
+	*kingpin.CmdClause
+	// Output is output format
+	Output *constants.Format
+}
+
+// PlanDiffCmd compares two operation plans exported with PlanExportCmd
+type PlanDiffCmd struct {
+	*kingpin.CmdClause
+	// OldPlan is the path to the first plan file
+	OldPlan *string
+	// NewPlan is the path to the second plan file
+	NewPlan *string
+	// Output is output format
+	Output *constants.Format
+}
+
 // PlanExecuteCmd executes a phase of an active operation
 type PlanExecuteCmd struct {
 	*kingpin.CmdClause
@@ -566,6 +623,20 @@ type UpdateTriggerCmd struct {
 	Block *bool
 	// SkipVersionCheck suppresses version mismatch errors
 	SkipVersionCheck *bool
+	// DryRun displays the operation plan without creating the operation
+	DryRun *bool
+	// PlanFile optionally specifies the file to save the dry-run plan to
+	PlanFile *string
+	// CanaryNodes is the number of regular nodes to update first
+	CanaryNodes *int
+	// BatchSize is the number of regular nodes to update at the same time
+	BatchSize *int
+	// DrainPolicy is the action to take for pods whose eviction remains
+	// blocked by disruption budgets
+	DrainPolicy *string
+	// DrainTimeout is how long to retry blocked evictions before taking
+	// the DrainPolicy action
+	DrainTimeout *time.Duration
 }
 
 // UpdateUploadCmd uploads new app version to local cluster
@@ -620,6 +691,20 @@ type UpgradeCmd struct {
 	Resume *bool
 	// SkipVersionCheck suppresses version mismatch errors
 	SkipVersionCheck *bool
+	// DryRun displays the operation plan without creating the operation
+	DryRun *bool
+	// PlanFile optionally specifies the file to save the dry-run plan to
+	PlanFile *string
+	// CanaryNodes is the number of regular nodes to update first
+	CanaryNodes *int
+	// BatchSize is the number of regular nodes to update at the same time
+	BatchSize *int
+	// DrainPolicy is the action to take for pods whose eviction remains
+	// blocked by disruption budgets
+	DrainPolicy *string
+	// DrainTimeout is how long to retry blocked evictions before taking
+	// the DrainPolicy action
+	DrainTimeout *time.Duration
 }
 
 // StatusCmd displays cluster status
@@ -642,22 +727,40 @@ type StatusResetCmd struct {
 	*kingpin.CmdClause
 }
 
-// BackupCmd launches app backup hook
+// BackupCmd combines subcommands for cluster backups
 type BackupCmd struct {
 	*kingpin.CmdClause
+}
+
+// BackupCreateCmd launches app backup hook
+type BackupCreateCmd struct {
+	*kingpin.CmdClause
 	// Tarball is backup tarball name
 	Tarball *string
+	// PassphraseFile is the file with the passphrase to encrypt the backup with
+	PassphraseFile *string
 	// Timeout is operation timeout
 	Timeout *time.Duration
 	// Follow tails operation logs
 	Follow *bool
 }
 
+// BackupListCmd lists backups stored in the cluster
+type BackupListCmd struct {
+	*kingpin.CmdClause
+	// Format is the output format
+	Format *constants.Format
+}
+
 // RestoreCmd launches app restore hook
 type RestoreCmd struct {
 	*kingpin.CmdClause
 	// Tarball is tarball to restore from
 	Tarball *string
+	// Name is the name of the backup stored in the cluster to restore from
+	Name *string
+	// PassphraseFile is the file with the passphrase to decrypt the backup with
+	PassphraseFile *string
 	// Timeout is operation timeout
 	Timeout *time.Duration
 	// Follow tails operation logs
@@ -991,6 +1094,54 @@ type OpsAgentCmd struct {
 	CloudProvider *string
 }
 
+// FleetCmd combines subcommands for fleet operations
+type FleetCmd struct {
+	*kingpin.CmdClause
+}
+
+// FleetUpdateCmd updates a fleet of clusters to a new application version
+type FleetUpdateCmd struct {
+	*kingpin.CmdClause
+	// App is the application package to update the clusters to
+	App *string
+	// OpsCenterURL is the Ops Center URL
+	OpsCenterURL *string
+	// Selector selects the clusters by labels
+	Selector *configure.KeyVal
+	// WaveSize is the maximum number of clusters in a wave
+	WaveSize *int
+	// Concurrency is the maximum number of clusters updated at the same time
+	Concurrency *int
+	// StopOnFailure stops the rollout after the wave with a failed cluster
+	StopOnFailure *bool
+}
+
+// FleetConfigCmd updates the configuration of a fleet of clusters
+type FleetConfigCmd struct {
+	*kingpin.CmdClause
+	// Path is the path to the cluster configuration resource
+	Path *string
+	// OpsCenterURL is the Ops Center URL
+	OpsCenterURL *string
+	// Selector selects the clusters by labels
+	Selector *configure.KeyVal
+	// WaveSize is the maximum number of clusters in a wave
+	WaveSize *int
+	// Concurrency is the maximum number of clusters updated at the same time
+	Concurrency *int
+	// StopOnFailure stops the rollout after the wave with a failed cluster
+	StopOnFailure *bool
+}
+
+// FleetStatusCmd displays the status of fleet operations
+type FleetStatusCmd struct {
+	*kingpin.CmdClause
+	// OperationID is the ID of the fleet operation to display
+	OperationID *string
+	// OpsCenterURL is the Ops Center URL
+	OpsCenterURL *string
+}
+
 // PackCmd combines subcommands for package service
 type PackCmd struct {
 	*kingpin.CmdClause
@@ -1301,6 +1452,15 @@ type RPCAgentRunCmd struct {
 	Args *[]string
 }
 
+// RPCAgentCopyCmd copies files to or from RPC agents
+type RPCAgentCopyCmd struct {
+	*kingpin.CmdClause
+	// Source is the file to copy, either local path or <node-addr>:<path>
+	Source *string
+	// Destination is the path to copy to, either local path or <node-addr>:<path>
+	Destination *string
+}
+
 // SystemCmd combines system subcommands
 type SystemCmd struct {
 	*kingpin.CmdClause
@@ -1317,6 +1477,22 @@ type SystemRotateCertsCmd struct {
 	CAPath *string
 }
 
+// SystemRotateRPCCredsCmd rotates the credentials of cluster RPC agents
+type SystemRotateRPCCredsCmd struct {
+	*kingpin.CmdClause
+}
+
+// SystemConvertAlertsCmd converts alerts between monitoring providers
+type SystemConvertAlertsCmd struct {
+	*kingpin.CmdClause
+	// From is the monitoring provider to convert alerts from
+	From *string
+	// To is the monitoring provider to convert alerts to
+	To *string
+	// DryRun only displays the alerts that would be converted
+	DryRun *bool
+}
+
 // SystemExportCACmd exports cluster CA
 type SystemExportCACmd struct {
 	*kingpin.CmdClause
@@ -1527,6 +1703,20 @@ type SystemGCPackageCmd struct {
 	Cluster *bool
 }
 
+// SystemGCOpsCenterCmd removes unused packages from the Ops Center package storage
+type SystemGCOpsCenterCmd struct {
+	*kingpin.CmdClause
+	// DryRun displays the packages to be removed
+	// without actually removing anything
+	DryRun *bool
+	// KeepVersions is the number of the latest versions
+	// of each application to retain
+	KeepVersions *int
+	// MaxAge is the minimum age of the application version
+	// eligible for removal
+	MaxAge *time.Duration
+}
+
 // SystemGCRegistryCmd removes unused docker images
 type SystemGCRegistryCmd struct {
 	*kingpin.CmdClause
@@ -1616,6 +1806,9 @@ type ResourceCreateCmd struct {
 	Manual *bool
 	// Confirmed suppresses confirmation prompt
 	Confirmed *bool
+	// Concurrency limits the number of nodes updated at the same time
+	// if the resource is managed with the help of a cluster operation
+	Concurrency *int
 }
 
 // ResourceRemoveCmd removes specified resource
@@ -1635,6 +1828,9 @@ type ResourceRemoveCmd struct {
 	Manual *bool
 	// Confirmed suppresses confirmation prompt
 	Confirmed *bool
+	// Concurrency limits the number of nodes updated at the same time
+	// if the resource is managed with the help of a cluster operation
+	Concurrency *int
 }
 
 // ResourceGetCmd shows specified resource
@@ -472,6 +472,10 @@ type JoinConfig struct {
 	Phase string
 	// OperationID is ID of existing join operation
 	OperationID string
+	// DryRun displays the operation plan without joining the cluster
+	DryRun bool
+	// PlanFile optionally specifies the file to save the dry-run plan to
+	PlanFile string
 }
 
 // NewJoinConfig populates join configuration from the provided CLI application
@@ -491,6 +495,8 @@ func NewJoinConfig(g *Application) JoinConfig {
 		Manual:        *g.JoinCmd.Manual,
 		Phase:         *g.JoinCmd.Phase,
 		OperationID:   *g.JoinCmd.OperationID,
+		DryRun:        *g.JoinCmd.DryRun,
+		PlanFile:      *g.JoinCmd.PlanFile,
 	}
 }
 
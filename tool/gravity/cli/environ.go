@@ -36,6 +36,7 @@ func updateEnviron(
 	localEnv, updateEnv *localenv.LocalEnvironment,
 	env storage.EnvironmentVariables,
 	manual, confirmed bool,
+	concurrency int,
 ) error {
 	if !confirmed {
 		if manual {
@@ -52,7 +53,7 @@ func updateEnviron(
 			return nil
 		}
 	}
-	updater, err := newEnvironUpdater(ctx, localEnv, updateEnv, env)
+	updater, err := newEnvironUpdater(ctx, localEnv, updateEnv, env, concurrency)
 	if err != nil {
 		return trace.Wrap(err)
 	}
@@ -65,9 +66,10 @@ func updateEnviron(
 	return nil
 }
 
-func newEnvironUpdater(ctx context.Context, localEnv, updateEnv *localenv.LocalEnvironment, environ storage.EnvironmentVariables) (*update.Updater, error) {
+func newEnvironUpdater(ctx context.Context, localEnv, updateEnv *localenv.LocalEnvironment, environ storage.EnvironmentVariables, concurrency int) (*update.Updater, error) {
 	init := environInitializer{
-		environ: environ,
+		environ:     environ,
+		concurrency: concurrency,
 	}
 	return newUpdater(ctx, localEnv, updateEnv, init)
 }
@@ -145,8 +147,9 @@ func (r environInitializer) validatePreconditions(*localenv.LocalEnvironment, op
 func (r environInitializer) newOperation(operator ops.Operator, cluster ops.Site) (*ops.SiteOperationKey, error) {
 	key, err := operator.CreateUpdateEnvarsOperation(context.TODO(),
 		ops.CreateUpdateEnvarsOperationRequest{
-			ClusterKey: cluster.Key(),
-			Env:        r.environ.GetKeyValues(),
+			ClusterKey:  cluster.Key(),
+			Env:         r.environ.GetKeyValues(),
+			Concurrency: r.concurrency,
 		},
 	)
 	if err != nil {
@@ -210,6 +213,9 @@ func (environInitializer) updateDeployRequest(req deployAgentsRequest) deployAge
 
 type environInitializer struct {
 	environ storage.EnvironmentVariables
+	// concurrency specifies the maximum number of regular nodes
+	// updated at the same time
+	concurrency int
 }
 
 const (
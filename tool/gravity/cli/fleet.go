@@ -0,0 +1,177 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"text/tabwriter"
+
+	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/defaults"
+	"github.com/gravitational/gravity/lib/localenv"
+	"github.com/gravitational/gravity/lib/ops"
+	"github.com/gravitational/gravity/lib/ops/opsclient"
+	"github.com/gravitational/gravity/lib/storage"
+	"github.com/gravitational/gravity/lib/storage/clusterconfig"
+
+	"github.com/gravitational/trace"
+)
+
+// fleetParams combines the parameters common to all fleet operations
+type fleetParams struct {
+	// opsCenterURL is the URL of the Ops Center to create the operation with.
+	// If unspecified, the local cluster is used
+	opsCenterURL string
+	// selector selects the clusters by labels
+	selector map[string]string
+	// waveSize is the maximum number of clusters in a wave
+	waveSize int
+	// concurrency is the maximum number of clusters updated at the same time
+	concurrency int
+	// stopOnFailure stops the rollout after the wave with a failed cluster
+	stopOnFailure bool
+}
+
+// fleetUpdate starts the update of the selected clusters to the specified application
+func fleetUpdate(env *localenv.LocalEnvironment, params fleetParams, appPackage string) error {
+	return createFleetOperation(env, params, ops.CreateFleetOperationRequest{
+		Type: ops.FleetOperationUpdate,
+		App:  appPackage,
+	})
+}
+
+// fleetUpdateConfig starts the configuration change of the selected clusters
+// with the cluster configuration resource from the specified file
+func fleetUpdateConfig(env *localenv.LocalEnvironment, params fleetParams, path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	config, err := clusterconfig.Unmarshal(data)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	configBytes, err := clusterconfig.Marshal(config)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return createFleetOperation(env, params, ops.CreateFleetOperationRequest{
+		Type:   ops.FleetOperationUpdateConfig,
+		Config: configBytes,
+	})
+}
+
+func createFleetOperation(env *localenv.LocalEnvironment, params fleetParams, req ops.CreateFleetOperationRequest) error {
+	operator, err := fleetOperator(env, params.opsCenterURL)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	req.AccountID = defaults.SystemAccountID
+	req.Selector = params.selector
+	req.WaveSize = params.waveSize
+	req.Concurrency = params.concurrency
+	req.StopOnFailure = params.stopOnFailure
+	key, err := operator.CreateFleetOperation(context.TODO(), req)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	operation, err := operator.GetFleetOperation(*key)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	env.Printf("Fleet operation %v created for %v cluster(s).\n",
+		operation.ID, len(operation.Clusters))
+	env.Printf("Use 'gravity ops fleet status %v' to monitor its progress.\n", operation.ID)
+	return nil
+}
+
+// fleetStatus displays the status of the fleet operation with the specified ID
+// or lists all fleet operations if the ID is empty
+func fleetStatus(env *localenv.LocalEnvironment, opsCenterURL, operationID string) error {
+	operator, err := fleetOperator(env, opsCenterURL)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if operationID == "" {
+		operations, err := operator.GetFleetOperations(defaults.SystemAccountID)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		printFleetOperations(operations)
+		return nil
+	}
+	operation, err := operator.GetFleetOperation(ops.FleetOperationKey{
+		AccountID:   defaults.SystemAccountID,
+		OperationID: operationID,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	printFleetOperation(*operation)
+	return nil
+}
+
+func printFleetOperations(operations []storage.FleetOperation) {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+	fmt.Fprintf(w, "ID\tType\tState\tClusters\tCreated\n")
+	fmt.Fprintf(w, "--\t----\t-----\t--------\t-------\n")
+	for _, operation := range operations {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n",
+			operation.ID,
+			operation.Type,
+			operation.State,
+			len(operation.Clusters),
+			operation.Created.Format(constants.HumanDateFormatSeconds))
+	}
+	w.Flush()
+}
+
+func printFleetOperation(operation storage.FleetOperation) {
+	fmt.Printf("Operation:\t%v\n", operation.ID)
+	fmt.Printf("Type:\t\t%v\n", operation.Type)
+	fmt.Printf("State:\t\t%v\n", operation.State)
+	if operation.Message != "" {
+		fmt.Printf("Message:\t%v\n", operation.Message)
+	}
+	fmt.Println()
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+	fmt.Fprintf(w, "Cluster\tWave\tState\tOperation\tMessage\n")
+	fmt.Fprintf(w, "-------\t----\t-----\t---------\t-------\n")
+	for _, cluster := range operation.Clusters {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n",
+			cluster.Name,
+			cluster.Wave+1,
+			cluster.State,
+			cluster.OperationID,
+			cluster.Message)
+	}
+	w.Flush()
+}
+
+// fleetOperator returns the operator for the Ops Center with the specified URL
+// or the local cluster operator if the URL is empty
+func fleetOperator(env *localenv.LocalEnvironment, opsCenterURL string) (*opsclient.Client, error) {
+	if opsCenterURL == "" {
+		return env.SiteOperator()
+	}
+	return env.OperatorService(opsCenterURL)
+}
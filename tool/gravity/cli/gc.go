@@ -30,6 +30,7 @@ import (
 	"github.com/gravitational/gravity/lib/vacuum"
 	"github.com/gravitational/gravity/lib/vacuum/prune"
 	"github.com/gravitational/gravity/lib/vacuum/prune/journal"
+	"github.com/gravitational/gravity/lib/vacuum/prune/opscenter"
 	"github.com/gravitational/gravity/lib/vacuum/prune/pack"
 	"github.com/gravitational/gravity/lib/vacuum/prune/registry"
 
@@ -58,6 +59,10 @@ func garbageCollect(env *localenv.LocalEnvironment, manual, confirmed bool) erro
 
 	ctx := context.TODO()
 	if !manual {
+		err = waitForMaintenanceWindow(ctx, env, ops.MaintenanceGarbageCollect)
+		if err != nil {
+			return trace.Wrap(err)
+		}
 		err = collector.Run(ctx, false)
 		return trace.Wrap(err)
 	}
@@ -390,6 +395,39 @@ func removeUnusedPackages(env *localenv.LocalEnvironment, dryRun, pruneClusterPa
 	return nil
 }
 
+func removeUnusedOpsCenterPackages(env *localenv.LocalEnvironment, dryRun bool, policy opscenter.Policy) error {
+	operator, err := env.SiteOperator()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	apps, err := opscenter.ClusterApps(operator)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	packages, err := env.ClusterPackages()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	pruner, err := opscenter.New(opscenter.Config{
+		Config: prune.Config{
+			DryRun:      dryRun,
+			FieldLogger: logrus.WithField(trace.Component, "gc:opscenter"),
+			Silent:      env.Silent,
+		},
+		Packages: packages,
+		Apps:     apps,
+		Policy:   policy,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	return trace.Wrap(pruner.Prune(context.TODO()))
+}
+
 func collectRemoteApplications(operator ops.Operator, clusterKey ops.SiteKey) (remoteApps []storage.Application, err error) {
 	accounts, err := operator.GetAccounts()
 	if err != nil {
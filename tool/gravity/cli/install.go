@@ -115,6 +115,14 @@ func Join(env, joinEnv *localenv.LocalEnvironment, j JoinConfig) error {
 		return trace.Wrap(err)
 	}
 
+	if j.DryRun {
+		plan, impact, err := peer.DryRun()
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		return trace.Wrap(outputDryRunPlan(*plan, *impact, j.PlanFile))
+	}
+
 	err = peer.Init()
 	if err != nil {
 		return trace.Wrap(err)
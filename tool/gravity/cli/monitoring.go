@@ -0,0 +1,73 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"github.com/gravitational/gravity/lib/httplib"
+	"github.com/gravitational/gravity/lib/localenv"
+	"github.com/gravitational/gravity/lib/ops/monitoring"
+
+	"github.com/gravitational/trace"
+	"k8s.io/client-go/kubernetes"
+)
+
+// convertAlerts converts the alerts and the alert target configured
+// with one monitoring provider to another
+func convertAlerts(env *localenv.LocalEnvironment, from, to string, dryRun bool) error {
+	if from == to {
+		return trace.BadParameter("source and target monitoring providers are the same: %v", from)
+	}
+	client, _, err := httplib.GetClusterKubeClient(env.DNS.Addr())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	getClient := func() (kubernetes.Interface, error) {
+		return client, nil
+	}
+	source, err := monitoring.New(monitoring.Config{Provider: from, GetClient: getClient})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	target, err := monitoring.New(monitoring.Config{Provider: to, GetClient: getClient})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	result, err := monitoring.ConvertAlerts(monitoring.ConvertConfig{
+		From:   source,
+		To:     target,
+		DryRun: dryRun,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	verb := "Converted"
+	if dryRun {
+		verb = "Would convert"
+	}
+	for _, alert := range result.Converted {
+		env.Printf("%v alert %v.\n", verb, alert.GetName())
+	}
+	if result.Target != nil {
+		env.Printf("%v alert target %v.\n", verb, result.Target.GetEmail())
+	}
+	for _, skipped := range result.Skipped {
+		env.Printf("Skipped alert %v: %v.\n", skipped.Alert.GetName(), skipped.Reason)
+	}
+	env.Printf("%v %v alert(s) from %v to %v, skipped %v.\n", verb,
+		len(result.Converted), from, to, len(result.Skipped))
+	return nil
+}
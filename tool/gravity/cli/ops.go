@@ -23,15 +23,18 @@ import (
 	_ "net/http/pprof"
 	"strings"
 
+	"github.com/gravitational/gravity/lib/app/delta"
 	"github.com/gravitational/gravity/lib/app/docker"
 	appservice "github.com/gravitational/gravity/lib/app/service"
 	"github.com/gravitational/gravity/lib/constants"
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/install"
+	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/localenv"
 	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/pack"
 	"github.com/gravitational/gravity/lib/pack/encryptedpack"
+	"github.com/gravitational/gravity/lib/pack/signing"
 	"github.com/gravitational/gravity/lib/state"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/users"
@@ -136,6 +139,10 @@ func uploadUpdate(env *localenv.LocalEnvironment, opsURL string) error {
 		return trace.Wrap(err)
 	}
 
+	// Delta installers omit the container image layers the cluster
+	// already has, restore them from the cluster packages
+	tarballPackages = delta.NewPackages(tarballPackages, clusterPackages)
+
 	clusterApps, err := defaultEnv.SiteApps()
 	if err != nil {
 		return trace.Wrap(err)
@@ -153,13 +160,47 @@ func uploadUpdate(env *localenv.LocalEnvironment, opsURL string) error {
 		return trace.Wrap(err)
 	}
 
+	envelope, err := tarballPackages.ReadPackageEnvelope(*appPackage)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if delta.IsDelta(*envelope) {
+		base, err := loc.ParseLocator(envelope.RuntimeLabels[pack.DeltaBaseLabel])
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		_, err = clusterPackages.ReadPackageEnvelope(*base)
+		if err != nil {
+			if trace.IsNotFound(err) {
+				return trace.NotFound("%v is a delta update built against %v "+
+					"which is not present in the cluster, please upload the full "+
+					"installer tarball instead", appPackage, base)
+			}
+			return trace.Wrap(err)
+		}
+		env.PrintStep("Restoring delta application against %v", base)
+	}
+
+	keys, err := clusterOperator.GetTrustedKeys(cluster.Key())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	verifier, err := signing.NewClusterVerifier(keys, cluster.Domain)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if verifier != nil {
+		env.PrintStep("Verifying package signatures against %v trusted key(s)", len(keys))
+	}
+
 	env.PrintStep("Importing application %v v%v", appPackage.Name, appPackage.Version)
 	_, err = appservice.PullApp(appservice.AppPullRequest{
-		SrcPack: tarballPackages,
-		SrcApp:  tarballApps,
-		DstPack: clusterPackages,
-		DstApp:  clusterApps,
-		Package: *appPackage,
+		SrcPack:  tarballPackages,
+		SrcApp:   tarballApps,
+		DstPack:  clusterPackages,
+		DstApp:   clusterApps,
+		Package:  *appPackage,
+		Verifier: verifier,
 	})
 	if err != nil {
 		if !trace.IsAlreadyExists(err) {
@@ -211,6 +252,44 @@ func uploadUpdate(env *localenv.LocalEnvironment, opsURL string) error {
 	return nil
 }
 
+// clusterVerifier returns the verifier for packages pulled into the cluster
+// managed by the specified operator.
+// Returns nil if the cluster does not trust any keys
+func clusterVerifier(operator ops.Operator) (pack.Verifier, error) {
+	cluster, err := operator.GetLocalSite()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	keys, err := operator.GetTrustedKeys(cluster.Key())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	verifier, err := signing.NewClusterVerifier(keys, cluster.Domain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return verifier, nil
+}
+
+// localClusterVerifier returns the verifier for packages pulled into
+// the local cluster.
+// Returns nil if the command is not running inside a cluster
+func localClusterVerifier(env *localenv.LocalEnvironment) (pack.Verifier, error) {
+	operator, err := env.SiteOperator()
+	if err != nil {
+		if !trace.IsNotFound(err) {
+			return nil, trace.Wrap(err)
+		}
+		log.Debugf("Not inside a cluster, package signatures are not verified: %v.", err)
+		return nil, nil
+	}
+	verifier, err := clusterVerifier(operator)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return verifier, nil
+}
+
 // getRegistries returns a list of registry addresses in the cluster
 func getRegistries(ctx context.Context, env *localenv.LocalEnvironment, servers []storage.Server) ([]string, error) {
 	// in planets before certain version registry was running only on active master
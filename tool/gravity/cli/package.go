@@ -317,11 +317,22 @@ func pushPackage(app *localenv.LocalEnvironment, loc loc.Locator, opsCenterURL s
 		return trace.Wrap(err)
 	}
 
+	operator, err := app.OperatorService(opsCenterURL)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	verifier, err := clusterVerifier(operator)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
 	req := service.PackagePullRequest{
 		SrcPack:  app.Packages,
 		DstPack:  dstPackages,
 		Package:  loc,
 		Progress: app.Reporter,
+		Verifier: verifier,
 	}
 
 	if _, err = service.PullPackage(req); err != nil {
@@ -340,6 +351,11 @@ func pullPackage(app *localenv.LocalEnvironment, loc loc.Locator, opsCenterURL s
 		return trace.Wrap(err)
 	}
 
+	verifier, err := localClusterVerifier(app)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
 	req := service.PackagePullRequest{
 		SrcPack:  sourcePackages,
 		DstPack:  app.Packages,
@@ -347,6 +363,7 @@ func pullPackage(app *localenv.LocalEnvironment, loc loc.Locator, opsCenterURL s
 		Labels:   labels,
 		Progress: app.Reporter,
 		Upsert:   force,
+		Verifier: verifier,
 	}
 
 	if _, err = service.PullPackage(req); err != nil {
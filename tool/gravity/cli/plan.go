@@ -17,12 +17,18 @@ limitations under the License.
 package cli
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/gravitational/gravity/lib/constants"
+	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/fsm"
 	"github.com/gravitational/gravity/lib/localenv"
 	"github.com/gravitational/gravity/lib/ops"
@@ -53,6 +59,9 @@ func initUpdateOperationPlan(localEnv, updateEnv *localenv.LocalEnvironment) err
 	if err != nil {
 		return trace.Wrap(err)
 	}
+	if operation.Type == ops.OperationUpdateConfig {
+		return trace.Wrap(initConfigOperationPlan(localEnv, updateEnv, clusterEnv, *cluster, *operation))
+	}
 	_, err = clusterupdate.InitOperationPlan(ctx, localEnv, updateEnv, clusterEnv, operation.Key())
 	if err != nil {
 		return trace.Wrap(err)
@@ -61,48 +70,121 @@ func initUpdateOperationPlan(localEnv, updateEnv *localenv.LocalEnvironment) err
 }
 
 func displayOperationPlan(localEnv, updateEnv, joinEnv *localenv.LocalEnvironment, operationID string, format constants.Format) error {
-	op, err := getLastOperation(localEnv, updateEnv, joinEnv, operationID)
+	plan, err := getOperationPlan(localEnv, updateEnv, joinEnv, operationID)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	return outputPlan(*plan, format)
+}
+
+// exportOperationPlan outputs the plan of the specified operation in the
+// given format either to the specified file or to stdout if no file is given
+func exportOperationPlan(localEnv, updateEnv, joinEnv *localenv.LocalEnvironment, operationID string, format constants.Format, path string) error {
+	plan, err := getOperationPlan(localEnv, updateEnv, joinEnv, operationID)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if path == "" {
+		return trace.Wrap(formatPlan(os.Stdout, *plan, format))
+	}
+	return trace.Wrap(writePlanFile(path, *plan, format))
+}
+
+// displayOperationTimeline outputs the execution timeline of the specified
+// operation with start times, durations and attempts of individual phases
+func displayOperationTimeline(localEnv, updateEnv, joinEnv *localenv.LocalEnvironment, operationID string, format constants.Format) error {
+	plan, err := getOperationPlan(localEnv, updateEnv, joinEnv, operationID)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	now := time.Now().UTC()
+	switch format {
+	case constants.EncodingText:
+		fsm.FormatPlanTimeline(os.Stdout, *plan, now)
+	case constants.EncodingJSON:
+		bytes, err := json.MarshalIndent(fsm.PlanTimeline(*plan, now), "", "    ")
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		fmt.Println(string(bytes))
+	default:
+		return trace.BadParameter("unknown output format %q", format)
+	}
+	return nil
+}
+
+// diffOperationPlans compares operation plans previously exported to files
+// oldPath and newPath and outputs the differences
+func diffOperationPlans(oldPath, newPath string, format constants.Format) error {
+	oldPlan, err := readPlanFile(oldPath)
 	if err != nil {
 		return trace.Wrap(err)
 	}
+	newPlan, err := readPlanFile(newPath)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	diff, err := fsm.DiffPlans(*oldPlan, *newPlan)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	switch format {
+	case constants.EncodingText:
+		fsm.FormatPlanDiffText(os.Stdout, *diff, oldPath, newPath)
+	case constants.EncodingJSON:
+		bytes, err := json.MarshalIndent(diff, "", "    ")
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		fmt.Println(string(bytes))
+	default:
+		return trace.BadParameter("unknown output format %q", format)
+	}
+	return nil
+}
+
+func getOperationPlan(localEnv, updateEnv, joinEnv *localenv.LocalEnvironment, operationID string) (*storage.OperationPlan, error) {
+	op, err := getLastOperation(localEnv, updateEnv, joinEnv, operationID)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
 	if op.IsCompleted() {
-		return displayClusterOperationPlan(localEnv, op.Key(), format)
+		return getClusterOperationPlan(localEnv, op.Key())
 	}
 	switch op.Type {
 	case ops.OperationInstall:
-		return displayInstallOperationPlan(op.Key(), format)
+		return getInstallOperationPlan(op.Key())
 	case ops.OperationExpand:
-		return displayExpandOperationPlan(joinEnv, op.Key(), format)
+		return getExpandOperationPlan(joinEnv, op.Key())
 	case ops.OperationUpdate:
-		return displayUpdateOperationPlan(localEnv, updateEnv, op.Key(), format)
+		return getUpdateOperationPlan(localEnv, updateEnv, op.Key())
 	case ops.OperationUpdateRuntimeEnviron:
-		return displayUpdateOperationPlan(localEnv, updateEnv, op.Key(), format)
+		return getUpdateOperationPlan(localEnv, updateEnv, op.Key())
 	case ops.OperationUpdateConfig:
-		return displayUpdateOperationPlan(localEnv, updateEnv, op.Key(), format)
+		return getUpdateOperationPlan(localEnv, updateEnv, op.Key())
 	case ops.OperationGarbageCollect:
-		return displayClusterOperationPlan(localEnv, op.Key(), format)
+		return getClusterOperationPlan(localEnv, op.Key())
 	default:
-		return trace.BadParameter("unknown operation type %q", op.Type)
+		return nil, trace.BadParameter("unknown operation type %q", op.Type)
 	}
 }
 
-func displayClusterOperationPlan(env *localenv.LocalEnvironment, opKey ops.SiteOperationKey, format constants.Format) error {
+func getClusterOperationPlan(env *localenv.LocalEnvironment, opKey ops.SiteOperationKey) (*storage.OperationPlan, error) {
 	clusterEnv, err := env.NewClusterEnvironment()
 	if err != nil {
-		return trace.Wrap(err)
+		return nil, trace.Wrap(err)
 	}
 	plan, err := clusterEnv.Operator.GetOperationPlan(opKey)
 	if err != nil {
-		return trace.Wrap(err)
+		return nil, trace.Wrap(err)
 	}
-	err = outputPlan(*plan, format)
-	return trace.Wrap(err)
+	return plan, nil
 }
 
-func displayUpdateOperationPlan(localEnv, updateEnv *localenv.LocalEnvironment, opKey ops.SiteOperationKey, format constants.Format) error {
+func getUpdateOperationPlan(localEnv, updateEnv *localenv.LocalEnvironment, opKey ops.SiteOperationKey) (*storage.OperationPlan, error) {
 	plan, err := fsm.GetOperationPlan(updateEnv.Backend, opKey.SiteDomain, opKey.OperationID)
 	if err != nil {
-		return trace.Wrap(err)
+		return nil, trace.Wrap(err)
 	}
 	reconciledPlan, err := tryReconcilePlan(context.TODO(), localEnv, updateEnv, *plan)
 	if err != nil {
@@ -110,20 +192,16 @@ func displayUpdateOperationPlan(localEnv, updateEnv *localenv.LocalEnvironment,
 	} else {
 		plan = reconciledPlan
 	}
-	err = outputPlan(*plan, format)
-	if err != nil {
-		return trace.Wrap(err)
-	}
-	return nil
+	return plan, nil
 }
 
-func displayInstallOperationPlan(opKey ops.SiteOperationKey, format constants.Format) error {
+func getInstallOperationPlan(opKey ops.SiteOperationKey) (*storage.OperationPlan, error) {
 	wizardEnv, err := localenv.NewRemoteEnvironment()
 	if err != nil {
-		return trace.Wrap(err)
+		return nil, trace.Wrap(err)
 	}
 	if wizardEnv.Operator == nil {
-		return trace.NotFound(`could not retrieve install operation plan.
+		return nil, trace.NotFound(`could not retrieve install operation plan.
 
 If you have not launched the installation, or it has been started moments ago,
 the plan may not be initialized yet.
@@ -135,35 +213,29 @@ was run.`)
 	plan, err := wizardEnv.Operator.GetOperationPlan(opKey)
 	if err != nil {
 		if trace.IsNotFound(err) {
-			return trace.NotFound(
+			return nil, trace.NotFound(
 				"Install operation plan hasn't been initialized yet.")
 		}
-		return trace.Wrap(err)
-	}
-	log.Debug("Showing install operation plan retrieved from wizard process.")
-	err = outputPlan(*plan, format)
-	if err != nil {
-		return trace.Wrap(err)
+		return nil, trace.Wrap(err)
 	}
-	return nil
+	log.Debug("Retrieved install operation plan from wizard process.")
+	return plan, nil
 }
 
-// displayExpandOperationPlan shows plan of the join operation from the local join backend
-func displayExpandOperationPlan(joinEnv *localenv.LocalEnvironment, opKey ops.SiteOperationKey, format constants.Format) error {
+// getExpandOperationPlan returns plan of the join operation from the local join backend
+func getExpandOperationPlan(joinEnv *localenv.LocalEnvironment, opKey ops.SiteOperationKey) (*storage.OperationPlan, error) {
 	plan, err := fsm.GetOperationPlan(joinEnv.Backend, opKey.SiteDomain, opKey.OperationID)
 	if err != nil {
-		return trace.Wrap(err)
+		return nil, trace.Wrap(err)
 	}
-	log.Debug("Showing join operation plan retrieved from local join backend.")
-	return outputPlan(*plan, format)
+	log.Debug("Retrieved join operation plan from local join backend.")
+	return plan, nil
 }
 
 func outputPlan(plan storage.OperationPlan, format constants.Format) (err error) {
 	switch format {
-	case constants.EncodingYAML:
-		err = fsm.FormatOperationPlanYAML(os.Stdout, plan)
-	case constants.EncodingJSON:
-		err = fsm.FormatOperationPlanJSON(os.Stdout, plan)
+	case constants.EncodingYAML, constants.EncodingJSON:
+		err = formatPlan(os.Stdout, plan, format)
 	case constants.EncodingText:
 		fsm.FormatOperationPlanText(os.Stdout, plan)
 		err = explainPlan(plan.Phases)
@@ -177,6 +249,67 @@ func outputPlan(plan storage.OperationPlan, format constants.Format) (err error)
 	return nil
 }
 
+// formatPlan serializes the plan to w in the specified format
+func formatPlan(w io.Writer, plan storage.OperationPlan, format constants.Format) error {
+	switch format {
+	case constants.EncodingYAML:
+		return trace.Wrap(fsm.FormatOperationPlanYAML(w, plan))
+	case constants.EncodingJSON:
+		return trace.Wrap(fsm.FormatOperationPlanJSON(w, plan))
+	default:
+		return trace.BadParameter("unsupported export format %q, expected json or yaml", format)
+	}
+}
+
+// writePlanFile saves the plan to the file at path in the specified format
+func writePlanFile(path string, plan storage.OperationPlan, format constants.Format) error {
+	var buf bytes.Buffer
+	if err := formatPlan(&buf, plan, format); err != nil {
+		return trace.Wrap(err)
+	}
+	err := ioutil.WriteFile(path, buf.Bytes(), defaults.SharedReadMask)
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	return nil
+}
+
+// readPlanFile reads the plan exported in either JSON or YAML format from the file at path
+func readPlanFile(path string) (*storage.OperationPlan, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	defer f.Close()
+	plan, err := fsm.ReadOperationPlan(f)
+	if err != nil {
+		return nil, trace.Wrap(err, "failed to read plan from %v", path)
+	}
+	return plan, nil
+}
+
+// outputDryRunPlan displays the plan generated in dry-run mode along
+// with the summary of changes it would make to the cluster.
+// If planFile is not empty, the plan is also saved to the specified file
+func outputDryRunPlan(plan storage.OperationPlan, impact fsm.PlanImpact, planFile string) error {
+	fsm.FormatOperationPlanText(os.Stdout, plan)
+	fmt.Println()
+	fsm.FormatPlanImpactText(os.Stdout, impact)
+	fmt.Println()
+	if planFile != "" {
+		format := constants.EncodingYAML
+		if filepath.Ext(planFile) == ".json" {
+			format = constants.EncodingJSON
+		}
+		if err := writePlanFile(planFile, plan, format); err != nil {
+			return trace.Wrap(err)
+		}
+		fmt.Printf("Operation plan has been saved to %v.\n", planFile)
+	}
+	fmt.Println("This is a dry run, no operation has been created.")
+	return nil
+}
+
 func explainPlan(phases []storage.OperationPhase) (err error) {
 	for _, phase := range phases {
 		if phase.State == storage.OperationPhaseStateFailed {
@@ -26,7 +26,9 @@ import (
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/modules"
+	"github.com/gravitational/gravity/lib/ops/monitoring"
 	"github.com/gravitational/gravity/lib/schema"
+	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/utils"
 	"github.com/gravitational/gravity/tool/common"
 
@@ -115,6 +117,8 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.JoinCmd.Resume = g.JoinCmd.Flag("resume", "Resume joining from last failed step").Bool()
 	g.JoinCmd.Force = g.JoinCmd.Flag("force", "Force phase execution").Bool()
 	g.JoinCmd.OperationID = g.JoinCmd.Flag("operation-id", "ID of the operation that was created via UI").Hidden().String()
+	g.JoinCmd.DryRun = g.JoinCmd.Flag("dry-run", "Display the operation plan and its impact without joining the cluster").Bool()
+	g.JoinCmd.PlanFile = g.JoinCmd.Flag("plan-file", "Save the plan generated with --dry-run to the specified file (JSON for .json files, YAML otherwise)").String()
 
 	g.AutoJoinCmd.CmdClause = g.Command("autojoin", "Use cloud provider data to join a node to existing cluster")
 	g.AutoJoinCmd.ClusterName = g.AutoJoinCmd.Arg("cluster-name", "Cluster name used for discovery").Required().String()
@@ -140,6 +144,18 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.PlanDisplayCmd.CmdClause = g.PlanCmd.Command("display", "Display a plan for an ongoing operation").Default()
 	g.PlanDisplayCmd.Output = common.Format(g.PlanDisplayCmd.Flag("output", "Output format for the plan, text, json or yaml").Short('o').Default(string(constants.EncodingText)))
 
+	g.PlanExportCmd.CmdClause = g.PlanCmd.Command("export", "Export a plan of an operation in a machine-readable format")
+	g.PlanExportCmd.Format = common.Format(g.PlanExportCmd.Flag("format", "Export format, json or yaml").Default(string(constants.EncodingYAML)))
+	g.PlanExportCmd.File = g.PlanExportCmd.Flag("file", "Write the plan to the specified file instead of stdout").Short('f').String()
+
+	g.PlanTimelineCmd.CmdClause = g.PlanCmd.Command("timeline", "Display execution timeline of an operation with per-phase timing")
+	g.PlanTimelineCmd.Output = common.Format(g.PlanTimelineCmd.Flag("output", "Output format for the timeline, text or json").Short('o').Default(string(constants.EncodingText)))
+
+	g.PlanDiffCmd.CmdClause = g.PlanCmd.Command("diff", "Compare two exported operation plans phase by phase")
+	g.PlanDiffCmd.OldPlan = g.PlanDiffCmd.Arg("plan-a", "Path to the first plan file").Required().String()
+	g.PlanDiffCmd.NewPlan = g.PlanDiffCmd.Arg("plan-b", "Path to the second plan file").Required().String()
+	g.PlanDiffCmd.Output = common.Format(g.PlanDiffCmd.Flag("output", "Output format for the differences, text or json").Short('o').Default(string(constants.EncodingText)))
+
 	g.PlanExecuteCmd.CmdClause = g.PlanCmd.Command("execute", "Execute specified operation phase")
 	g.PlanExecuteCmd.Phase = g.PlanExecuteCmd.Flag("phase", "Phase ID to execute").String()
 	g.PlanExecuteCmd.Force = g.PlanExecuteCmd.Flag("force", "Force execution of specified phase").Bool()
@@ -169,6 +185,12 @@ func RegisterCommands(app *kingpin.Application) *Application {
 		Default("true").
 		Bool()
 	g.UpdateTriggerCmd.SkipVersionCheck = g.UpdateTriggerCmd.Flag("skip-version-check", "Bypass version compatibility check").Hidden().Bool()
+	g.UpdateTriggerCmd.DryRun = g.UpdateTriggerCmd.Flag("dry-run", "Display the operation plan and its impact without starting the operation").Bool()
+	g.UpdateTriggerCmd.PlanFile = g.UpdateTriggerCmd.Flag("plan-file", "Save the plan generated with --dry-run to the specified file (JSON for .json files, YAML otherwise)").String()
+	g.UpdateTriggerCmd.CanaryNodes = g.UpdateTriggerCmd.Flag("canary-nodes", "Number of regular nodes to update first and verify cluster health before updating the rest").Int()
+	g.UpdateTriggerCmd.BatchSize = g.UpdateTriggerCmd.Flag("batch-size", "Number of regular nodes to update at the same time, cluster health is verified after each batch").Int()
+	g.UpdateTriggerCmd.DrainPolicy = g.UpdateTriggerCmd.Flag("drain-policy", fmt.Sprintf("Action to take for pods whose eviction remains blocked by pod disruption budgets: %v", drainActions)).Default(string(storage.DrainActionWait)).Enum(drainActions...)
+	g.UpdateTriggerCmd.DrainTimeout = g.UpdateTriggerCmd.Flag("drain-timeout", "How long to retry evictions blocked by pod disruption budgets before taking the drain policy action").Default(defaults.DrainBlockedTimeout.String()).Duration()
 
 	g.UpdatePlanInitCmd.CmdClause = g.UpdateCmd.Command("init-plan", "Initialize operation plan").Hidden()
 
@@ -185,6 +207,12 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.UpgradeCmd.Force = g.UpgradeCmd.Flag("force", "Force phase execution even if pre-conditions are not satisfied").Bool()
 	g.UpgradeCmd.Resume = g.UpgradeCmd.Flag("resume", "Resume upgrade from the last failed step").Bool()
 	g.UpgradeCmd.SkipVersionCheck = g.UpgradeCmd.Flag("skip-version-check", "Bypass version compatibility check").Hidden().Bool()
+	g.UpgradeCmd.DryRun = g.UpgradeCmd.Flag("dry-run", "Display the operation plan and its impact without starting the operation").Bool()
+	g.UpgradeCmd.PlanFile = g.UpgradeCmd.Flag("plan-file", "Save the plan generated with --dry-run to the specified file (JSON for .json files, YAML otherwise)").String()
+	g.UpgradeCmd.CanaryNodes = g.UpgradeCmd.Flag("canary-nodes", "Number of regular nodes to update first and verify cluster health before updating the rest").Int()
+	g.UpgradeCmd.BatchSize = g.UpgradeCmd.Flag("batch-size", "Number of regular nodes to update at the same time, cluster health is verified after each batch").Int()
+	g.UpgradeCmd.DrainPolicy = g.UpgradeCmd.Flag("drain-policy", fmt.Sprintf("Action to take for pods whose eviction remains blocked by pod disruption budgets: %v", drainActions)).Default(string(storage.DrainActionWait)).Enum(drainActions...)
+	g.UpgradeCmd.DrainTimeout = g.UpgradeCmd.Flag("drain-timeout", "How long to retry evictions blocked by pod disruption budgets before taking the drain policy action").Default(defaults.DrainBlockedTimeout.String()).Duration()
 
 	g.UpdateUploadCmd.CmdClause = g.UpdateCmd.Command("upload", "Upload update package to locally running site").Hidden()
 	g.UpdateUploadCmd.OpsCenterURL = g.UpdateUploadCmd.Flag("ops-url", "Optional OpsCenter URL to upload new packages to (defaults to local gravity site)").Default(defaults.GravityServiceURL).String()
@@ -212,9 +240,14 @@ func RegisterCommands(app *kingpin.Application) *Application {
 
 	// backup
 	g.BackupCmd.CmdClause = g.Command("backup", "Backup the local application state")
-	g.BackupCmd.Tarball = g.BackupCmd.Arg("to", "Tarball to create with results of the backup hook").Required().String()
-	g.BackupCmd.Timeout = g.BackupCmd.Flag("timeout", "Active deadline for the backup job, in Go duration format (e.g. 30s, 5m, etc.). If not specified, the value from manifest is used. If that is not specified as well, the default value of 20 minutes is used").Duration()
-	g.BackupCmd.Follow = g.BackupCmd.Flag("follow", "Output backup job logs to the stdout").Bool()
+	g.BackupCreateCmd.CmdClause = g.BackupCmd.Command("create", "Backup the local application state to a tarball").Default()
+	g.BackupCreateCmd.Tarball = g.BackupCreateCmd.Arg("to", "Tarball to create with results of the backup hook: local path or URL, e.g. file:///mnt/backups/backup.tar.gz, s3://bucket/backup.tar.gz or sftp://user@host/backups/backup.tar.gz").Required().String()
+	g.BackupCreateCmd.PassphraseFile = g.BackupCreateCmd.Flag("passphrase-file", "File with the passphrase to encrypt the backup with").String()
+	g.BackupCreateCmd.Timeout = g.BackupCreateCmd.Flag("timeout", "Active deadline for the backup job, in Go duration format (e.g. 30s, 5m, etc.). If not specified, the value from manifest is used. If that is not specified as well, the default value of 20 minutes is used").Duration()
+	g.BackupCreateCmd.Follow = g.BackupCreateCmd.Flag("follow", "Output backup job logs to the stdout").Bool()
+
+	g.BackupListCmd.CmdClause = g.BackupCmd.Command("ls", "List backups taken according to the configured backup schedules")
+	g.BackupListCmd.Format = common.Format(g.BackupListCmd.Flag("output", "Output format, text or json").Short('o').Default(string(constants.EncodingText)))
 
 	g.CheckCmd.CmdClause = g.Command("check", "check host environment to match manifest")
 	g.CheckCmd.ManifestFile = g.CheckCmd.Arg("manifest", "application manifest in YAML format").Default(defaults.ManifestFileName).String()
@@ -223,7 +256,9 @@ func RegisterCommands(app *kingpin.Application) *Application {
 
 	// restore
 	g.RestoreCmd.CmdClause = g.Command("restore", "Restore state of the local application from a previously taken backup")
-	g.RestoreCmd.Tarball = g.RestoreCmd.Arg("from", "Tarball with backup data to restore from").Required().String()
+	g.RestoreCmd.Tarball = g.RestoreCmd.Arg("from", "Tarball with backup data to restore from: local path or URL, e.g. file:///mnt/backups/backup.tar.gz, s3://bucket/backup.tar.gz or sftp://user@host/backups/backup.tar.gz").String()
+	g.RestoreCmd.PassphraseFile = g.RestoreCmd.Flag("passphrase-file", "File with the passphrase to decrypt the backup with").String()
+	g.RestoreCmd.Name = g.RestoreCmd.Flag("name", "Name of the backup stored in the cluster to restore from, see 'gravity backup ls'").String()
 	g.RestoreCmd.Follow = g.RestoreCmd.Flag("follow", "Output restore job logs to the stdout").Bool()
 	g.RestoreCmd.Timeout = g.RestoreCmd.Flag("timeout", fmt.Sprintf("Maximum time a restore job is active. Defaults to the value from the manifest or %v if unspecified", defaults.HookJobDeadline)).Duration()
 
@@ -385,6 +420,28 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.OpsAgentCmd.ServiceGID = g.OpsAgentCmd.Flag("service-gid", fmt.Sprintf("Service group ID for planet. %q group will created and used if none specified", defaults.ServiceUserGroup)).Default(defaults.ServiceGroupID).OverrideDefaultFromEnvar(constants.ServiceGroupEnvVar).String()
 	g.OpsAgentCmd.CloudProvider = g.OpsAgentCmd.Flag("cloud-provider", "Cloud provider integration e.g. 'generic', 'aws'. If not set, autodetect environment").String()
 
+	g.FleetCmd.CmdClause = g.OpsCmd.Command("fleet", "Roll out changes across multiple clusters connected to the Ops Center")
+
+	g.FleetUpdateCmd.CmdClause = g.FleetCmd.Command("update", "Update the selected clusters to a new application version")
+	g.FleetUpdateCmd.App = g.FleetUpdateCmd.Arg("app", "Application package to update the clusters to").Required().String()
+	g.FleetUpdateCmd.OpsCenterURL = g.FleetUpdateCmd.Flag("ops-url", "Ops Center URL. Defaults to the local cluster").String()
+	g.FleetUpdateCmd.Selector = configure.KeyValParam(g.FleetUpdateCmd.Flag("selector", "Select clusters with the specified labels as key=value pairs"))
+	g.FleetUpdateCmd.WaveSize = g.FleetUpdateCmd.Flag("wave-size", "Maximum number of clusters in a wave. Defaults to all selected clusters").Int()
+	g.FleetUpdateCmd.Concurrency = g.FleetUpdateCmd.Flag("concurrency", "Maximum number of clusters updated at the same time. Defaults to the wave size").Int()
+	g.FleetUpdateCmd.StopOnFailure = g.FleetUpdateCmd.Flag("stop-on-failure", "Stop the rollout after the wave with a failed cluster").Bool()
+
+	g.FleetConfigCmd.CmdClause = g.FleetCmd.Command("config", "Update the configuration of the selected clusters")
+	g.FleetConfigCmd.Path = g.FleetConfigCmd.Arg("path", "Path to the cluster configuration resource").Required().String()
+	g.FleetConfigCmd.OpsCenterURL = g.FleetConfigCmd.Flag("ops-url", "Ops Center URL. Defaults to the local cluster").String()
+	g.FleetConfigCmd.Selector = configure.KeyValParam(g.FleetConfigCmd.Flag("selector", "Select clusters with the specified labels as key=value pairs"))
+	g.FleetConfigCmd.WaveSize = g.FleetConfigCmd.Flag("wave-size", "Maximum number of clusters in a wave. Defaults to all selected clusters").Int()
+	g.FleetConfigCmd.Concurrency = g.FleetConfigCmd.Flag("concurrency", "Maximum number of clusters updated at the same time. Defaults to the wave size").Int()
+	g.FleetConfigCmd.StopOnFailure = g.FleetConfigCmd.Flag("stop-on-failure", "Stop the rollout after the wave with a failed cluster").Bool()
+
+	g.FleetStatusCmd.CmdClause = g.FleetCmd.Command("status", "Display the status of fleet operations")
+	g.FleetStatusCmd.OperationID = g.FleetStatusCmd.Arg("operation-id", "ID of the fleet operation. Lists all fleet operations if unspecified").String()
+	g.FleetStatusCmd.OpsCenterURL = g.FleetStatusCmd.Flag("ops-url", "Ops Center URL. Defaults to the local cluster").String()
+
 	// operations on packages
 	g.PackCmd.CmdClause = g.Command("package", "operations on gravity system packages")
 
@@ -560,6 +617,10 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.RPCAgentRunCmd.CmdClause = g.RPCAgentCmd.Command("run", "run RPC agent").Hidden()
 	g.RPCAgentRunCmd.Args = g.RPCAgentRunCmd.Arg("arg", "additional arguments").Strings()
 
+	g.RPCAgentCopyCmd.CmdClause = g.RPCAgentCmd.Command("cp", "copy a file to or from a node running RPC agent, remote paths are specified as <node-addr>:<absolute-path>")
+	g.RPCAgentCopyCmd.Source = g.RPCAgentCopyCmd.Arg("src", "file to copy").Required().String()
+	g.RPCAgentCopyCmd.Destination = g.RPCAgentCopyCmd.Arg("dst", "path to copy the file to").Required().String()
+
 	g.SystemCmd.CmdClause = g.Command("system", "operations on system components")
 
 	g.SystemRotateCertsCmd.CmdClause = g.SystemCmd.Command("rotate-certs", "Renew cluster certificates on a node").Hidden()
@@ -571,6 +632,8 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.SystemExportCACmd.ClusterName = g.SystemExportCACmd.Arg("cluster-name", "Name of the local cluster").Required().String()
 	g.SystemExportCACmd.CAPath = g.SystemExportCACmd.Arg("path", "File path to export CA at").Required().String()
 
+	g.SystemRotateRPCCredsCmd.CmdClause = g.SystemCmd.Command("rotate-rpc-creds", "Rotate credentials of the running RPC agents, must be run on a master node")
+
 	g.SystemUninstallCmd.CmdClause = g.SystemCmd.Command("uninstall", "uninstall gravity from the host").Hidden()
 	g.SystemUninstallCmd.Confirmed = g.SystemUninstallCmd.Flag("confirm", "confirm uninstall").Bool()
 
@@ -636,6 +699,11 @@ func RegisterCommands(app *kingpin.Application) *Application {
 
 	g.SystemStateDirCmd.CmdClause = g.SystemCmd.Command("state-dir", "show where all gravity data is stored on the node").Hidden()
 
+	g.SystemConvertAlertsCmd.CmdClause = g.SystemCmd.Command("convert-alerts", "Convert alerts and alert targets between monitoring providers")
+	g.SystemConvertAlertsCmd.From = g.SystemConvertAlertsCmd.Flag("from", "Monitoring provider to convert alerts from").Default(monitoring.ProviderInfluxDB).Enum(monitoring.ProviderInfluxDB, monitoring.ProviderPrometheus)
+	g.SystemConvertAlertsCmd.To = g.SystemConvertAlertsCmd.Flag("to", "Monitoring provider to convert alerts to").Default(monitoring.ProviderPrometheus).Enum(monitoring.ProviderInfluxDB, monitoring.ProviderPrometheus)
+	g.SystemConvertAlertsCmd.DryRun = g.SystemConvertAlertsCmd.Flag("dry-run", "Only display the alerts that would be converted").Bool()
+
 	// manage docker devicemapper environment
 	g.SystemDevicemapperCmd.CmdClause = g.SystemCmd.Command("devicemapper", "manage docker devicemapper environment").Hidden()
 	g.SystemDevicemapperMountCmd.CmdClause = g.SystemDevicemapperCmd.Command("mount", "configure devicemapper environment").Hidden()
@@ -677,6 +745,11 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.SystemGCPackageCmd.DryRun = g.SystemGCPackageCmd.Flag("dry-run", "Only list packages to remove w/o removing them").Bool()
 	g.SystemGCPackageCmd.Cluster = g.SystemGCPackageCmd.Flag("cluster", "Whether to prune cluster packages").Bool()
 
+	g.SystemGCOpsCenterCmd.CmdClause = systemGCCmd.Command("opscenter", "Prune application versions not used by connected clusters from the Ops Center package storage.")
+	g.SystemGCOpsCenterCmd.DryRun = g.SystemGCOpsCenterCmd.Flag("dry-run", "Only list packages to remove w/o removing them").Bool()
+	g.SystemGCOpsCenterCmd.KeepVersions = g.SystemGCOpsCenterCmd.Flag("keep-versions", "Number of the latest versions of each application to keep").Default(strconv.Itoa(defaults.PackageGCKeepVersions)).Int()
+	g.SystemGCOpsCenterCmd.MaxAge = g.SystemGCOpsCenterCmd.Flag("max-age", "Only remove application versions older than the specified duration, e.g. 720h").Duration()
+
 	g.SystemGCRegistryCmd.CmdClause = systemGCCmd.Command("registry", "Prune unused docker images on this node.")
 	g.SystemGCRegistryCmd.Confirm = g.SystemGCRegistryCmd.Flag("confirm", "Confirm to remove unrelated docker").Bool()
 	g.SystemGCRegistryCmd.DryRun = g.SystemGCRegistryCmd.Flag("dry-run", "Only list docker images to remove w/o removing them").Bool()
@@ -709,6 +782,7 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.ResourceCreateCmd.User = g.ResourceCreateCmd.Flag("user", "user to create resource for, defaults to currently logged in user").String()
 	g.ResourceCreateCmd.Manual = g.ResourceCreateCmd.Flag("manual", "manually execute operation phases").Short('m').Bool()
 	g.ResourceCreateCmd.Confirmed = g.ResourceCreateCmd.Flag("confirm", "do not ask for confirmation").Bool()
+	g.ResourceCreateCmd.Concurrency = g.ResourceCreateCmd.Flag("concurrency", "maximum number of regular nodes updated at the same time, if the resource triggers a cluster update").Int()
 
 	// remove one or many resources
 	g.ResourceRemoveCmd.CmdClause = g.ResourceCmd.Command("rm", fmt.Sprintf("Remove a configuration resource, e.g. gravity resource rm oidc google. Supported resources are: %v", modules.GetResources().SupportedResourcesToRemove()))
@@ -719,6 +793,7 @@ func RegisterCommands(app *kingpin.Application) *Application {
 	g.ResourceRemoveCmd.User = g.ResourceRemoveCmd.Flag("user", "user to remove resource for, defaults to currently logged in user").String()
 	g.ResourceRemoveCmd.Manual = g.ResourceRemoveCmd.Flag("manual", "manually execute operation phases").Short('m').Bool()
 	g.ResourceRemoveCmd.Confirmed = g.ResourceRemoveCmd.Flag("confirm", "do not ask for confirmation").Bool()
+	g.ResourceRemoveCmd.Concurrency = g.ResourceRemoveCmd.Flag("concurrency", "maximum number of regular nodes updated at the same time, if removing the resource triggers a cluster update").Int()
 
 	// get resources returns resources
 	g.ResourceGetCmd.CmdClause = g.ResourceCmd.Command("get", fmt.Sprintf("Get configuration resources, e.g. gravity get oidc. Supported resources are: %v",
@@ -46,8 +46,9 @@ type LocalEnvironmentFactory interface {
 // upsert controls whether the resource is expected to exist.
 // manual controls whether the operation is created in manual mode if resource creation is implemented
 // as a cluster operation.
-// confirmed specifies if the user has explicitly approved the operation
-func createResource(env *localenv.LocalEnvironment, factory LocalEnvironmentFactory, filename string, upsert bool, user string, manual, confirmed bool) error {
+// confirmed specifies if the user has explicitly approved the operation.
+// concurrency limits the number of nodes updated at the same time by the operation
+func createResource(env *localenv.LocalEnvironment, factory LocalEnvironmentFactory, filename string, upsert bool, user string, manual, confirmed bool, concurrency int) error {
 	operator, err := env.SiteOperator()
 	if err != nil {
 		return trace.Wrap(err)
@@ -70,10 +71,11 @@ func createResource(env *localenv.LocalEnvironment, factory LocalEnvironmentFact
 	control := resources.NewControl(gravityResources)
 	err = resources.ForEach(reader, func(resource storage.UnknownResource) error {
 		req := resources.CreateRequest{
-			Upsert:    upsert,
-			Owner:     user,
-			Manual:    manual,
-			Confirmed: confirmed,
+			Upsert:      upsert,
+			Owner:       user,
+			Manual:      manual,
+			Confirmed:   confirmed,
+			Concurrency: concurrency,
 		}
 		return trace.Wrap(control.Create(context.TODO(), bytes.NewReader(resource.Raw), req))
 	})
@@ -88,6 +90,7 @@ func removeResource(
 	force bool,
 	user string,
 	manual, confirmed bool,
+	concurrency int,
 ) error {
 	operator, err := env.SiteOperator()
 	if err != nil {
@@ -104,12 +107,13 @@ func removeResource(
 		return trace.Wrap(err)
 	}
 	req := resources.RemoveRequest{
-		Kind:      kind,
-		Name:      name,
-		Force:     force,
-		Owner:     user,
-		Manual:    manual,
-		Confirmed: confirmed,
+		Kind:        kind,
+		Name:        name,
+		Force:       force,
+		Owner:       user,
+		Manual:      manual,
+		Confirmed:   confirmed,
+		Concurrency: concurrency,
 	}
 	err = resources.NewControl(gravityResources).Remove(context.TODO(), req)
 	return trace.Wrap(err)
@@ -163,9 +167,9 @@ func (r clusterOperationHandler) RemoveResource(req resources.RemoveRequest) err
 	switch req.Kind {
 	case storage.KindRuntimeEnvironment:
 		env := storage.NewEnvironment(nil)
-		return trace.Wrap(updateEnviron(context.TODO(), localEnv, updateEnv, env, req.Manual, req.Confirmed))
+		return trace.Wrap(updateEnviron(context.TODO(), localEnv, updateEnv, env, req.Manual, req.Confirmed, req.Concurrency))
 	case storage.KindClusterConfiguration:
-		return trace.Wrap(resetConfig(context.TODO(), localEnv, updateEnv, req.Manual, req.Confirmed))
+		return trace.Wrap(resetConfig(context.TODO(), localEnv, updateEnv, req.Manual, req.Confirmed, req.Concurrency))
 	}
 	// unreachable
 	return trace.BadParameter("unknown resource kind %q", req.Kind)
@@ -194,14 +198,14 @@ func (r clusterOperationHandler) UpdateResource(req resources.CreateRequest) err
 			return trace.Wrap(err)
 		}
 		return trace.Wrap(updateEnviron(context.TODO(), localEnv, updateEnv,
-			env, req.Manual, req.Confirmed))
+			env, req.Manual, req.Confirmed, req.Concurrency))
 	case storage.KindClusterConfiguration:
 		config, err := clusterconfig.Unmarshal(req.Resource.Raw)
 		if err != nil {
 			return trace.Wrap(err)
 		}
 		return trace.Wrap(updateConfig(context.TODO(), localEnv, updateEnv,
-			config, req.Manual, req.Confirmed))
+			config, req.Manual, req.Confirmed, req.Concurrency))
 	}
 	// unreachable
 	return trace.BadParameter("unknown resource kind %q", req.Resource.Kind)
@@ -18,6 +18,7 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
@@ -28,6 +29,7 @@ import (
 	"github.com/gravitational/gravity/lib/defaults"
 	"github.com/gravitational/gravity/lib/loc"
 	"github.com/gravitational/gravity/lib/localenv"
+	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/pack"
 	"github.com/gravitational/gravity/lib/state"
 	"github.com/gravitational/gravity/lib/users"
@@ -48,6 +50,15 @@ type rotateOptions struct {
 }
 
 func rotateCertificates(env *localenv.LocalEnvironment, o rotateOptions) (err error) {
+	err = waitForMaintenanceWindow(context.TODO(), env, ops.MaintenanceRotateCertificates)
+	if err != nil {
+		if !trace.IsConnectionProblem(err) {
+			return trace.Wrap(err)
+		}
+		// certificates are often rotated because the expired ones
+		// prevent the cluster from functioning
+		log.Warnf("Failed to check the maintenance window: %v.", trace.DebugReport(err))
+	}
 	var archive utils.TLSArchive
 	if o.caPath != "" {
 		archive, err = readCertAuthorityFromFile(o.caPath)
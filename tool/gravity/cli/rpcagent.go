@@ -22,6 +22,7 @@ import (
 	"io"
 	"net"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -32,8 +33,10 @@ import (
 	"github.com/gravitational/gravity/lib/localenv"
 	"github.com/gravitational/gravity/lib/pack"
 	"github.com/gravitational/gravity/lib/rpc"
+	rpcclient "github.com/gravitational/gravity/lib/rpc/client"
 	pb "github.com/gravitational/gravity/lib/rpc/proto"
 	rpcserver "github.com/gravitational/gravity/lib/rpc/server"
+	"github.com/gravitational/gravity/lib/state"
 	"github.com/gravitational/gravity/lib/storage"
 	"github.com/gravitational/gravity/lib/update"
 	clusterupdate "github.com/gravitational/gravity/lib/update/cluster"
@@ -96,7 +99,7 @@ func startAgent() (rpcserver.Server, error) {
 		return nil, trace.Wrap(err)
 	}
 
-	serverCreds, clientCreds, err := rpc.Credentials(secretsDir)
+	serverCreds, clientCreds, err := rpc.ReloadingCredentials(secretsDir)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -126,8 +129,9 @@ func startAgent() (rpcserver.Server, error) {
 type agentFunc func(ctx context.Context, localEnv, upgradeEnv *localenv.LocalEnvironment, args []string) error
 
 var agentFunctions map[string]agentFunc = map[string]agentFunc{
-	constants.RPCAgentUpgradeFunction:  executeAutomaticUpgrade,
-	constants.RPCAgentSyncPlanFunction: executeSyncOperationPlan,
+	constants.RPCAgentUpgradeFunction:      executeAutomaticUpgrade,
+	constants.RPCAgentSyncPlanFunction:     executeSyncOperationPlan,
+	constants.RPCAgentUpdateConfigFunction: executeAutomaticConfigUpdate,
 }
 
 func rpcAgentDeploy(localEnv, updateEnv *localenv.LocalEnvironment, leaderParams, nodeParams string) error {
@@ -329,6 +333,157 @@ func rpcAgentShutdown(env *localenv.LocalEnvironment) error {
 	return trace.Wrap(err)
 }
 
+func rpcAgentCopy(env *localenv.LocalEnvironment, src, dst string) error {
+	srcAddr, srcPath, err := parseAgentPath(src)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	dstAddr, dstPath, err := parseAgentPath(dst)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if (srcAddr == "") == (dstAddr == "") {
+		return trace.BadParameter("exactly one of source or destination must be a remote path in the form <node-addr>:<absolute-path>")
+	}
+	addr := srcAddr
+	if addr == "" {
+		addr = dstAddr
+	}
+	creds, err := fsm.GetClientCredentials()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaults.AgentConnectTimeout)
+	defer cancel()
+	clt, err := rpcclient.New(ctx, rpcclient.Config{ServerAddr: rpc.AgentAddr(addr), Credentials: creds})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer clt.Close()
+	logger := logrus.WithField("node", addr)
+	if srcAddr != "" {
+		dstPath, err = localCopyPath(dstPath, srcPath)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		err = clt.GetFile(context.TODO(), logger, srcPath, dstPath)
+	} else {
+		err = clt.PutFile(context.TODO(), logger, srcPath, dstPath)
+	}
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	env.Printf("Copied %v to %v.\n", src, dst)
+	return nil
+}
+
+// parseAgentPath splits the path specified as <node-addr>:<path> into
+// address and path parts.
+// Local paths are returned with an empty address.
+// Returns an error if the remote path is not absolute
+func parseAgentPath(path string) (addr, filePath string, err error) {
+	if strings.HasPrefix(path, "/") || strings.HasPrefix(path, ".") {
+		return "", path, nil
+	}
+	index := strings.Index(path, ":/")
+	if index != -1 {
+		return path[:index], path[index+1:], nil
+	}
+	if index := strings.LastIndex(path, ":"); index != -1 {
+		return "", "", trace.BadParameter("remote path must be absolute: %q", path[index+1:])
+	}
+	return "", path, nil
+}
+
+// localCopyPath returns the absolute local path to copy the remote file
+// srcPath to. If path specifies an existing directory, the file is placed into it
+func localCopyPath(path, srcPath string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	fi, err := os.Stat(path)
+	if err == nil && fi.IsDir() {
+		return filepath.Join(path, filepath.Base(srcPath)), nil
+	}
+	return path, nil
+}
+
+func rotateRPCCredentials(env *localenv.LocalEnvironment) error {
+	clusterEnv, err := env.NewClusterEnvironment()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	operator, err := env.SiteOperator()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	cluster, err := operator.GetLocalSite()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	secretsDir, err := fsm.AgentSecretsDir()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	current, err := rpc.ReadCredentials(secretsDir)
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return trace.NotFound("no agent credentials found in %v, "+
+				"make sure agents are running with 'gravity agent deploy'", secretsDir)
+		}
+		return trace.Wrap(err)
+	}
+	creds, err := fsm.GetClientCredentials()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	nodes := make([]rpcserver.RotateNode, 0, len(cluster.ClusterState.Servers))
+	hosts := make([]string, 0, len(cluster.ClusterState.Servers))
+	for _, server := range cluster.ClusterState.Servers {
+		nodes = append(nodes, rpcserver.RotateNode{
+			Addr:       rpc.AgentAddr(server.AdvertiseIP),
+			SecretsDir: filepath.Join(state.GravityRPCAgentDir(server.StateDir()), defaults.SecretsDir),
+		})
+		hosts = append(hosts, server.AdvertiseIP)
+	}
+	next, err := rpc.GenerateAgentCredentials(hosts, cluster.Domain, false)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	env.Printf("Rotating credentials of agents on %v nodes.\n", len(nodes))
+	ctx, cancel := context.WithTimeout(context.Background(), defaults.AgentDeployTimeout)
+	defer cancel()
+	err = rpcserver.RotateCredentials(ctx, rpcserver.RotateConfig{
+		FieldLogger: logrus.WithField(trace.Component, "rpc:rotate"),
+		Nodes:       nodes,
+		Credentials: creds,
+		Current:     current,
+		Next:        next,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	// Replace the revoked credentials in the agent credentials package if it exists
+	secretsPackage := loc.Locator{
+		Repository: cluster.Domain,
+		Name:       defaults.RPCAgentSecretsPackage,
+		Version:    getGravityPackage().Version,
+	}
+	_, err = clusterEnv.ClusterPackages.ReadPackageEnvelope(secretsPackage)
+	if err == nil {
+		_, err = rpc.GenerateAgentCredentialsPackage(clusterEnv.ClusterPackages, secretsPackage, next)
+	}
+	if err != nil && !trace.IsNotFound(err) {
+		return trace.Wrap(err)
+	}
+	env.Println("Agent credentials rotated.")
+	return nil
+}
+
 func executeAutomaticUpgrade(ctx context.Context, localEnv, upgradeEnv *localenv.LocalEnvironment, args []string) error {
 	return trace.Wrap(clusterupdate.AutomaticUpgrade(ctx, localEnv, upgradeEnv))
 }
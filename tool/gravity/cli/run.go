@@ -38,6 +38,7 @@ import (
 	"github.com/gravitational/gravity/lib/schema"
 	"github.com/gravitational/gravity/lib/systemservice"
 	"github.com/gravitational/gravity/lib/utils"
+	"github.com/gravitational/gravity/lib/vacuum/prune/opscenter"
 
 	"github.com/gravitational/configure/cstrings"
 	teleutils "github.com/gravitational/teleport/lib/utils"
@@ -96,6 +97,8 @@ func InitAndCheck(g *Application, cmd string) error {
 		g.RPCAgentRunCmd.FullCommand(),
 		g.PlanCmd.FullCommand(),
 		g.PlanDisplayCmd.FullCommand(),
+		g.PlanExportCmd.FullCommand(),
+		g.PlanTimelineCmd.FullCommand(),
 		g.PlanExecuteCmd.FullCommand(),
 		g.PlanRollbackCmd.FullCommand(),
 		g.PlanResumeCmd.FullCommand(),
@@ -170,6 +173,8 @@ func InitAndCheck(g *Application, cmd string) error {
 		g.RPCAgentShutdownCmd.FullCommand(),
 		g.RPCAgentInstallCmd.FullCommand(),
 		g.RPCAgentRunCmd.FullCommand(),
+		g.RPCAgentCopyCmd.FullCommand(),
+		g.SystemRotateRPCCredsCmd.FullCommand(),
 		g.SystemServiceInstallCmd.FullCommand(),
 		g.SystemServiceUninstallCmd.FullCommand(),
 		g.EnterCmd.FullCommand(),
@@ -177,6 +182,8 @@ func InitAndCheck(g *Application, cmd string) error {
 		g.UpdatePlanInitCmd.FullCommand(),
 		g.PlanCmd.FullCommand(),
 		g.PlanDisplayCmd.FullCommand(),
+		g.PlanExportCmd.FullCommand(),
+		g.PlanTimelineCmd.FullCommand(),
 		g.PlanExecuteCmd.FullCommand(),
 		g.PlanRollbackCmd.FullCommand(),
 		g.PlanResumeCmd.FullCommand(),
@@ -186,7 +193,7 @@ func InitAndCheck(g *Application, cmd string) error {
 		g.AutoJoinCmd.FullCommand(),
 		g.SystemDevicemapperMountCmd.FullCommand(),
 		g.SystemDevicemapperUnmountCmd.FullCommand(),
-		g.BackupCmd.FullCommand(),
+		g.BackupCreateCmd.FullCommand(),
 		g.RestoreCmd.FullCommand(),
 		g.GarbageCollectCmd.FullCommand(),
 		g.SystemGCRegistryCmd.FullCommand(),
@@ -233,6 +240,8 @@ func Execute(g *Application, cmd string, extraArgs []string) error {
 		return initCluster(*g.SiteInitCmd.ConfigPath, *g.SiteInitCmd.InitPath)
 	case g.SiteStatusCmd.FullCommand():
 		return statusSite()
+	case g.PlanDiffCmd.FullCommand():
+		return diffOperationPlans(*g.PlanDiffCmd.OldPlan, *g.PlanDiffCmd.NewPlan, *g.PlanDiffCmd.Output)
 	}
 
 	localEnv, err := g.LocalEnv(cmd)
@@ -340,9 +349,22 @@ func Execute(g *Application, cmd string, extraArgs []string) error {
 	case g.UpdateCheckCmd.FullCommand():
 		return updateCheck(localEnv, *g.UpdateCheckCmd.App)
 	case g.UpdateTriggerCmd.FullCommand():
+		strategy, err := newUpdateStrategy(*g.UpdateTriggerCmd.CanaryNodes, *g.UpdateTriggerCmd.BatchSize)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		drainPolicy, err := newDrainPolicy(*g.UpdateTriggerCmd.DrainPolicy, *g.UpdateTriggerCmd.DrainTimeout)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if *g.UpdateTriggerCmd.DryRun {
+			return updateDryRun(localEnv, *g.UpdateTriggerCmd.App, strategy, drainPolicy, *g.UpdateTriggerCmd.PlanFile)
+		}
 		return updateTrigger(localEnv,
 			updateEnv,
 			*g.UpdateTriggerCmd.App,
+			strategy,
+			drainPolicy,
 			*g.UpdateTriggerCmd.Manual,
 			*g.UpdateTriggerCmd.Block,
 			*g.UpdateTriggerCmd.SkipVersionCheck,
@@ -350,6 +372,17 @@ func Execute(g *Application, cmd string, extraArgs []string) error {
 	case g.UpdatePlanInitCmd.FullCommand():
 		return initUpdateOperationPlan(localEnv, updateEnv)
 	case g.UpgradeCmd.FullCommand():
+		strategy, err := newUpdateStrategy(*g.UpgradeCmd.CanaryNodes, *g.UpgradeCmd.BatchSize)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		drainPolicy, err := newDrainPolicy(*g.UpgradeCmd.DrainPolicy, *g.UpgradeCmd.DrainTimeout)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if *g.UpgradeCmd.DryRun {
+			return updateDryRun(localEnv, *g.UpgradeCmd.App, strategy, drainPolicy, *g.UpgradeCmd.PlanFile)
+		}
 		if *g.UpgradeCmd.Resume {
 			*g.UpgradeCmd.Phase = fsm.RootPhase
 		}
@@ -365,6 +398,8 @@ func Execute(g *Application, cmd string, extraArgs []string) error {
 		return updateTrigger(localEnv,
 			updateEnv,
 			*g.UpgradeCmd.App,
+			strategy,
+			drainPolicy,
 			*g.UpgradeCmd.Manual,
 			*g.UpgradeCmd.Block,
 			*g.UpgradeCmd.SkipVersionCheck,
@@ -399,6 +434,12 @@ func Execute(g *Application, cmd string, extraArgs []string) error {
 	case g.PlanDisplayCmd.FullCommand():
 		return displayOperationPlan(localEnv, updateEnv, joinEnv,
 			*g.PlanCmd.OperationID, *g.PlanDisplayCmd.Output)
+	case g.PlanExportCmd.FullCommand():
+		return exportOperationPlan(localEnv, updateEnv, joinEnv,
+			*g.PlanCmd.OperationID, *g.PlanExportCmd.Format, *g.PlanExportCmd.File)
+	case g.PlanTimelineCmd.FullCommand():
+		return displayOperationTimeline(localEnv, updateEnv, joinEnv,
+			*g.PlanCmd.OperationID, *g.PlanTimelineCmd.Output)
 	case g.PlanCompleteCmd.FullCommand():
 		return completeOperationPlan(localEnv, updateEnv, joinEnv, *g.PlanCmd.OperationID)
 	case g.LeaveCmd.FullCommand():
@@ -648,6 +689,26 @@ func Execute(g *Application, cmd string, extraArgs []string) error {
 			*g.OpsDisconnectCmd.OpsCenterURL)
 	case g.OpsListCmd.FullCommand():
 		return listOpsCenters(localEnv)
+	case g.FleetUpdateCmd.FullCommand():
+		return fleetUpdate(localEnv, fleetParams{
+			opsCenterURL:  *g.FleetUpdateCmd.OpsCenterURL,
+			selector:      *g.FleetUpdateCmd.Selector,
+			waveSize:      *g.FleetUpdateCmd.WaveSize,
+			concurrency:   *g.FleetUpdateCmd.Concurrency,
+			stopOnFailure: *g.FleetUpdateCmd.StopOnFailure,
+		}, *g.FleetUpdateCmd.App)
+	case g.FleetConfigCmd.FullCommand():
+		return fleetUpdateConfig(localEnv, fleetParams{
+			opsCenterURL:  *g.FleetConfigCmd.OpsCenterURL,
+			selector:      *g.FleetConfigCmd.Selector,
+			waveSize:      *g.FleetConfigCmd.WaveSize,
+			concurrency:   *g.FleetConfigCmd.Concurrency,
+			stopOnFailure: *g.FleetConfigCmd.StopOnFailure,
+		}, *g.FleetConfigCmd.Path)
+	case g.FleetStatusCmd.FullCommand():
+		return fleetStatus(localEnv,
+			*g.FleetStatusCmd.OpsCenterURL,
+			*g.FleetStatusCmd.OperationID)
 	case g.UserCreateCmd.FullCommand():
 		return createUser(localEnv,
 			*g.UserCreateCmd.OpsCenterURL,
@@ -699,6 +760,8 @@ func Execute(g *Application, cmd string, extraArgs []string) error {
 		return exportCertificateAuthority(localEnv,
 			*g.SystemExportCACmd.ClusterName,
 			*g.SystemExportCACmd.CAPath)
+	case g.SystemRotateRPCCredsCmd.FullCommand():
+		return rotateRPCCredentials(localEnv)
 	case g.SystemReinstallCmd.FullCommand():
 		return systemReinstall(localEnv,
 			*g.SystemReinstallCmd.Package,
@@ -729,15 +792,37 @@ func Execute(g *Application, cmd string, extraArgs []string) error {
 			*g.SystemRollbackCmd.WithStatus)
 	case g.SystemStepDownCmd.FullCommand():
 		return stepDown(localEnv)
-	case g.BackupCmd.FullCommand():
+	case g.BackupCreateCmd.FullCommand():
+		passphrase, err := readPassphrase(*g.BackupCreateCmd.PassphraseFile)
+		if err != nil {
+			return trace.Wrap(err)
+		}
 		return backup(localEnv,
-			*g.BackupCmd.Tarball,
-			*g.BackupCmd.Timeout,
-			*g.BackupCmd.Follow,
+			*g.BackupCreateCmd.Tarball,
+			passphrase,
+			*g.BackupCreateCmd.Timeout,
+			*g.BackupCreateCmd.Follow,
 			*g.Silent)
+	case g.BackupListCmd.FullCommand():
+		return listBackups(localEnv, *g.BackupListCmd.Format)
 	case g.RestoreCmd.FullCommand():
+		if *g.RestoreCmd.Name != "" {
+			return restoreFromCluster(localEnv,
+				*g.RestoreCmd.Name,
+				*g.RestoreCmd.Timeout,
+				*g.RestoreCmd.Follow,
+				*g.Silent)
+		}
+		if *g.RestoreCmd.Tarball == "" {
+			return trace.BadParameter("either backup tarball or --name must be specified")
+		}
+		passphrase, err := readPassphrase(*g.RestoreCmd.PassphraseFile)
+		if err != nil {
+			return trace.Wrap(err)
+		}
 		return restore(localEnv,
 			*g.RestoreCmd.Tarball,
+			passphrase,
 			*g.RestoreCmd.Timeout,
 			*g.RestoreCmd.Follow,
 			*g.Silent)
@@ -777,6 +862,11 @@ func Execute(g *Application, cmd string, extraArgs []string) error {
 			*g.SystemReportCmd.Compressed)
 	case g.SystemStateDirCmd.FullCommand():
 		return printStateDir()
+	case g.SystemConvertAlertsCmd.FullCommand():
+		return convertAlerts(localEnv,
+			*g.SystemConvertAlertsCmd.From,
+			*g.SystemConvertAlertsCmd.To,
+			*g.SystemConvertAlertsCmd.DryRun)
 	case g.SystemEnablePromiscModeCmd.FullCommand():
 		return enablePromiscMode(localEnv, *g.SystemEnablePromiscModeCmd.Iface)
 	case g.SystemDisablePromiscModeCmd.FullCommand():
@@ -795,6 +885,13 @@ func Execute(g *Application, cmd string, extraArgs []string) error {
 		return removeUnusedPackages(localEnv,
 			*g.SystemGCPackageCmd.DryRun,
 			*g.SystemGCPackageCmd.Cluster)
+	case g.SystemGCOpsCenterCmd.FullCommand():
+		return removeUnusedOpsCenterPackages(localEnv,
+			*g.SystemGCOpsCenterCmd.DryRun,
+			opscenter.Policy{
+				KeepVersions: *g.SystemGCOpsCenterCmd.KeepVersions,
+				MaxAge:       *g.SystemGCOpsCenterCmd.MaxAge,
+			})
 	case g.SystemGCRegistryCmd.FullCommand():
 		return removeUnusedImages(localEnv,
 			*g.SystemGCRegistryCmd.DryRun,
@@ -832,7 +929,8 @@ func Execute(g *Application, cmd string, extraArgs []string) error {
 			*g.ResourceCreateCmd.Upsert,
 			*g.ResourceCreateCmd.User,
 			*g.ResourceCreateCmd.Manual,
-			*g.ResourceCreateCmd.Confirmed)
+			*g.ResourceCreateCmd.Confirmed,
+			*g.ResourceCreateCmd.Concurrency)
 	case g.ResourceRemoveCmd.FullCommand():
 		return removeResource(localEnv, g,
 			*g.ResourceRemoveCmd.Kind,
@@ -840,7 +938,8 @@ func Execute(g *Application, cmd string, extraArgs []string) error {
 			*g.ResourceRemoveCmd.Force,
 			*g.ResourceRemoveCmd.User,
 			*g.ResourceRemoveCmd.Manual,
-			*g.ResourceRemoveCmd.Confirmed)
+			*g.ResourceRemoveCmd.Confirmed,
+			*g.ResourceRemoveCmd.Concurrency)
 	case g.ResourceGetCmd.FullCommand():
 		return getResources(localEnv,
 			*g.ResourceGetCmd.Kind,
@@ -859,6 +958,10 @@ func Execute(g *Application, cmd string, extraArgs []string) error {
 			*g.RPCAgentRunCmd.Args)
 	case g.RPCAgentShutdownCmd.FullCommand():
 		return rpcAgentShutdown(localEnv)
+	case g.RPCAgentCopyCmd.FullCommand():
+		return rpcAgentCopy(localEnv,
+			*g.RPCAgentCopyCmd.Source,
+			*g.RPCAgentCopyCmd.Destination)
 	case g.CheckCmd.FullCommand():
 		return checkManifest(localEnv,
 			*g.CheckCmd.ManifestFile,
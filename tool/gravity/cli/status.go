@@ -32,6 +32,7 @@ import (
 	"github.com/gravitational/gravity/lib/ops"
 	"github.com/gravitational/gravity/lib/schema"
 	statusapi "github.com/gravitational/gravity/lib/status"
+	"github.com/gravitational/gravity/lib/storage"
 
 	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
@@ -55,6 +56,7 @@ func status(env *localenv.LocalEnvironment, printOptions printOptions) error {
 
 	status, err := statusOnce(context.TODO(), operator, printOptions.operationID)
 	if err == nil {
+		collectPackageStorageStatus(clusterEnv.Backend, status)
 		err = printStatus(operator, clusterStatus{*status, nil}, printOptions)
 		return trace.Wrap(err)
 	} else {
@@ -168,6 +170,20 @@ func statusOnce(ctx context.Context, operator ops.Operator, operationID string)
 	return status, nil
 }
 
+// collectPackageStorageStatus adds the results of the last package
+// integrity verification to the cluster status
+func collectPackageStorageStatus(backend storage.Backend, status *statusapi.Status) {
+	if status.Cluster == nil {
+		return
+	}
+	reports, err := backend.GetBLOBScrubReports()
+	if err != nil {
+		log.WithError(err).Warn("Failed to query package storage status.")
+		return
+	}
+	status.Cluster.PackageStorage = statusapi.FromBLOBScrubReports(reports)
+}
+
 // printStatus calls an appropriate "print" method based on the printing options
 func printStatus(operator ops.Operator, status clusterStatus, printOptions printOptions) error {
 	switch {
@@ -308,6 +324,9 @@ func printClusterStatus(cluster statusapi.Cluster, w io.Writer) {
 		printOperation(cluster.Operation, w)
 	}
 	cluster.Endpoints.Cluster.WriteTo(w)
+	if cluster.PackageStorage != nil {
+		cluster.PackageStorage.WriteTo(w)
+	}
 }
 
 func printOperation(operation *statusapi.ClusterOperation, w io.Writer) {
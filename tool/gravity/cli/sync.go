@@ -104,13 +104,18 @@ func appSyncEnv(env *localenv.LocalEnvironment, imageEnv *localenv.ImageEnvironm
 		if err != nil {
 			return trace.Wrap(err)
 		}
+		verifier, err := localClusterVerifier(env)
+		if err != nil {
+			return trace.Wrap(err)
+		}
 		_, err = service.PullApp(service.AppPullRequest{
-			SrcPack: imageEnv.Packages,
-			DstPack: clusterPackages,
-			SrcApp:  imageEnv.Apps,
-			DstApp:  clusterApps,
-			Package: imageEnv.Manifest.Locator(),
-			Upsert:  true,
+			SrcPack:  imageEnv.Packages,
+			DstPack:  clusterPackages,
+			SrcApp:   imageEnv.Apps,
+			DstApp:   clusterApps,
+			Package:  imageEnv.Manifest.Locator(),
+			Upsert:   true,
+			Verifier: verifier,
 		})
 		if err != nil {
 			return trace.Wrap(err)
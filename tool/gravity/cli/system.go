@@ -67,6 +67,11 @@ func systemPullUpdates(env *localenv.LocalEnvironment, opsCenterURL string, runt
 		return trace.Wrap(err)
 	}
 
+	verifier, err := localClusterVerifier(env)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
 	for _, req := range reqs {
 		log := log.WithField("package", req.installedPackage)
 		log.Info("Checking for update.")
@@ -80,12 +85,12 @@ func systemPullUpdates(env *localenv.LocalEnvironment, opsCenterURL string, runt
 		}
 		log.WithField("update", update).Info("Pulling update.")
 		env.Printf("Pulling update %v\n.", update)
-		err = pullUpdate(env.Packages, remotePackages, env.Reporter, *update)
+		err = pullUpdate(env.Packages, remotePackages, env.Reporter, verifier, *update)
 		if err != nil {
 			return trace.Wrap(err)
 		}
 		if update.ConfigPackage != nil {
-			err = pullUpdate(env.Packages, remotePackages, env.Reporter,
+			err = pullUpdate(env.Packages, remotePackages, env.Reporter, verifier,
 				*update.ConfigPackage)
 			if err != nil {
 				return trace.Wrap(err)
@@ -367,12 +372,13 @@ func applyUpdates(env *localenv.LocalEnvironment, updates []storage.PackageUpdat
 	return trace.NewAggregate(errors...)
 }
 
-func pullUpdate(localPackages, remotePackages pack.PackageService, reporter pack.ProgressReporter, update storage.PackageUpdate) error {
+func pullUpdate(localPackages, remotePackages pack.PackageService, reporter pack.ProgressReporter, verifier pack.Verifier, update storage.PackageUpdate) error {
 	pullReq := appservice.PackagePullRequest{
 		SrcPack:  remotePackages,
 		DstPack:  localPackages,
 		Package:  update.To,
 		Progress: reporter,
+		Verifier: verifier,
 	}
 	_, err := appservice.PullPackage(pullReq)
 	if err != nil && !trace.IsAlreadyExists(err) {
@@ -149,6 +149,8 @@ func (g *Application) isUpdateCommand(cmd string) bool {
 	switch cmd {
 	case g.PlanCmd.FullCommand(),
 		g.PlanDisplayCmd.FullCommand(),
+		g.PlanExportCmd.FullCommand(),
+		g.PlanTimelineCmd.FullCommand(),
 		g.PlanExecuteCmd.FullCommand(),
 		g.PlanRollbackCmd.FullCommand(),
 		g.PlanResumeCmd.FullCommand(),
@@ -173,6 +175,8 @@ func (g *Application) isExpandCommand(cmd string) bool {
 	case g.JoinCmd.FullCommand(), g.AutoJoinCmd.FullCommand(),
 		g.PlanCmd.FullCommand(),
 		g.PlanDisplayCmd.FullCommand(),
+		g.PlanExportCmd.FullCommand(),
+		g.PlanTimelineCmd.FullCommand(),
 		g.PlanExecuteCmd.FullCommand(),
 		g.PlanRollbackCmd.FullCommand(),
 		g.PlanCompleteCmd.FullCommand(),
@@ -18,9 +18,11 @@ package cli
 
 import (
 	"context"
+	"io/ioutil"
 
 	"github.com/gravitational/gravity/lib/app/service"
 	"github.com/gravitational/gravity/lib/builder"
+	"github.com/gravitational/gravity/lib/pack/signing"
 	"github.com/gravitational/gravity/lib/utils"
 
 	"github.com/gravitational/trace"
@@ -44,10 +46,26 @@ type BuildParameters struct {
 	Silent bool
 	// Insecure turns on insecure verify mode
 	Insecure bool
+	// SignKeyPath is the path to the private key to sign packages with
+	SignKeyPath string
+	// DeltaFrom is the installer tarball or the locator of the
+	// cluster image to build a delta installer against
+	DeltaFrom string
 }
 
 // build builds an installer tarball according to the provided parameters
 func build(ctx context.Context, params BuildParameters, req service.VendorRequest) (err error) {
+	var signer *signing.Signer
+	if params.SignKeyPath != "" {
+		keyPEM, err := ioutil.ReadFile(params.SignKeyPath)
+		if err != nil {
+			return trace.ConvertSystemError(err)
+		}
+		signer, err = signing.NewSigner(keyPEM)
+		if err != nil {
+			return trace.Wrap(err, "failed to load signing key from %v", params.SignKeyPath)
+		}
+	}
 	installerBuilder, err := builder.New(builder.Config{
 		Context:          ctx,
 		StateDir:         params.StateDir,
@@ -59,6 +77,8 @@ func build(ctx context.Context, params BuildParameters, req service.VendorReques
 		SkipVersionCheck: params.SkipVersionCheck,
 		VendorReq:        req,
 		Progress:         utils.NewProgress(ctx, "Build", 6, params.Silent),
+		Signer:           signer,
+		DeltaFrom:        params.DeltaFrom,
 	})
 	if err != nil {
 		return trace.Wrap(err)
@@ -41,6 +41,8 @@ type Application struct {
 	ListCmd ListCmd
 	// PullCmd downloads app installer from Ops Center
 	PullCmd PullCmd
+	// MirrorCmd mirrors applications from Ops Center
+	MirrorCmd MirrorCmd
 }
 
 // VersionCmd outputs the binary version
@@ -79,6 +81,11 @@ type BuildCmd struct {
 	Parallel *int
 	// Quiet allows to suppress console output
 	Quiet *bool
+	// SignKey is the path to the private key to sign packages with
+	SignKey *string
+	// DeltaFrom is the installer tarball or the locator of the
+	// cluster image to build a delta installer against
+	DeltaFrom *string
 }
 
 type ListCmd struct {
@@ -103,3 +110,22 @@ type PullCmd struct {
 	// Quiet allows to suppress console output
 	Quiet *bool
 }
+
+// MirrorCmd mirrors applications with their dependencies from Ops Center
+// into a local directory or another Ops Center
+type MirrorCmd struct {
+	*kingpin.CmdClause
+	// Source is the address of the Ops Center to mirror applications from
+	Source *string
+	// Destination is the local directory or the address of the Ops Center
+	// to mirror applications into
+	Destination *string
+	// Apps selects applications to mirror
+	Apps *[]string
+	// Labels selects applications with the specified labels
+	Labels *map[string]string
+	// Runtimes additionally mirrors runtimes
+	Runtimes *bool
+	// DryRun only displays what would be mirrored
+	DryRun *bool
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2018 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"strings"
+
+	appbase "github.com/gravitational/gravity/lib/app"
+	"github.com/gravitational/gravity/lib/app/mirror"
+	"github.com/gravitational/gravity/lib/localenv"
+	"github.com/gravitational/gravity/lib/pack"
+	"github.com/gravitational/gravity/lib/pack/signing"
+
+	"github.com/gravitational/trace"
+)
+
+// mirrorParams defines the parameters of the mirror command
+type mirrorParams struct {
+	// Source is the address of the Ops Center to mirror applications from
+	Source string
+	// Destination is the local directory or the address of the Ops Center
+	// to mirror applications into
+	Destination string
+	// Apps selects applications to mirror in the name[:constraint] format
+	Apps []string
+	// Labels selects applications with the specified labels
+	Labels map[string]string
+	// Runtimes additionally mirrors runtime applications
+	Runtimes bool
+	// DryRun only displays what would be mirrored
+	DryRun bool
+}
+
+// mirrorApps mirrors applications with their dependencies
+// from the source Ops Center into the destination
+func mirrorApps(ctx context.Context, env *localenv.LocalEnvironment, params mirrorParams) error {
+	filter := mirror.Filter{
+		Labels:   params.Labels,
+		Runtimes: params.Runtimes,
+	}
+	for _, app := range params.Apps {
+		selector, err := mirror.ParseSelector(app)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		filter.Apps = append(filter.Apps, *selector)
+	}
+
+	srcPack, err := env.PackageService(params.Source)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	srcApp, err := env.AppService(params.Source, localenv.AppConfig{})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	var dstPack pack.PackageService
+	var dstApp appbase.Applications
+	var verifier pack.Verifier
+	if isURL(params.Destination) {
+		dstPack, err = env.PackageService(params.Destination)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		dstApp, err = env.AppService(params.Destination, localenv.AppConfig{})
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		verifier, err = destinationVerifier(env, params.Destination)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	} else {
+		// The local directory is a regular state directory so it can be
+		// used as a package source by gravity and tele commands
+		dstEnv, err := localenv.NewLocalEnvironment(localenv.LocalEnvironmentArgs{
+			StateDir: params.Destination,
+		})
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		defer dstEnv.Close()
+		dstPack = dstEnv.Packages
+		dstApp, err = dstEnv.AppServiceLocal(localenv.AppConfig{})
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	}
+
+	m, err := mirror.New(mirror.Config{
+		SrcPack:  srcPack,
+		SrcApp:   srcApp,
+		DstPack:  dstPack,
+		DstApp:   dstApp,
+		Filter:   filter,
+		Progress: env.Reporter,
+		Verifier: verifier,
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	plan, err := m.Plan(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	for _, locator := range plan.Existing {
+		env.PrintStep("Application %v is already mirrored", locator)
+	}
+	if plan.IsEmpty() {
+		env.PrintStep("Nothing to mirror")
+		return nil
+	}
+	for _, locator := range plan.Apps {
+		env.PrintStep("Will mirror application %v", locator)
+	}
+	for _, locator := range plan.Packages {
+		env.PrintStep("Will mirror package %v", locator)
+	}
+	if params.DryRun {
+		return nil
+	}
+
+	err = m.Run(ctx, *plan)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	env.PrintStep("Mirrored %v application(s) and %v package(s) into %v",
+		len(plan.Apps), len(plan.Packages), params.Destination)
+	return nil
+}
+
+// destinationVerifier returns the verifier for packages mirrored into
+// the Ops Center with the specified address.
+// Returns nil if the Ops Center does not trust any keys
+func destinationVerifier(env *localenv.LocalEnvironment, opsCenterURL string) (pack.Verifier, error) {
+	operator, err := env.OperatorService(opsCenterURL)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	cluster, err := operator.GetLocalSite()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	keys, err := operator.GetTrustedKeys(cluster.Key())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	verifier, err := signing.NewClusterVerifier(keys, cluster.Domain)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return verifier, nil
+}
+
+// isURL returns true if the specified mirror destination is an Ops Center address
+func isURL(destination string) bool {
+	return strings.HasPrefix(destination, "https://") || strings.HasPrefix(destination, "http://")
+}
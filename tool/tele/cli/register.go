@@ -54,6 +54,8 @@ func RegisterCommands(app *kingpin.Application) Application {
 	tele.BuildCmd.SkipVersionCheck = tele.BuildCmd.Flag("skip-version-check", "Skip version compatibility check").Hidden().Bool()
 	tele.BuildCmd.Parallel = tele.BuildCmd.Flag("parallel", "Specifies the number of concurrent tasks. If < 0, the number of tasks is not restricted, if unspecified, then tasks are capped at the number of logical CPU cores").Int()
 	tele.BuildCmd.Quiet = tele.BuildCmd.Flag("quiet", "Suppress any extra output to stdout").Short('q').Bool()
+	tele.BuildCmd.SignKey = tele.BuildCmd.Flag("sign-key", "Path to the PEM-encoded Ed25519 private key to sign all packages in the image with").String()
+	tele.BuildCmd.DeltaFrom = tele.BuildCmd.Flag("delta-from", "Path to the installer tarball or locator of the previous cluster image version to build a delta update against").String()
 
 	tele.ListCmd.CmdClause = app.Command("ls", "Display a list of user applications published in remote Ops Center")
 	tele.ListCmd.Runtimes = tele.ListCmd.Flag("runtimes", "Show only runtimes").Short('r').Hidden().Bool()
@@ -66,5 +68,13 @@ func RegisterCommands(app *kingpin.Application) Application {
 	tele.PullCmd.Force = tele.PullCmd.Flag("force", "Overwrite existing tarball").Short('f').Bool()
 	tele.PullCmd.Quiet = tele.PullCmd.Flag("quiet", "Suppress any extra output to stdout").Short('q').Bool()
 
+	tele.MirrorCmd.CmdClause = app.Command("mirror", "Mirror applications with their dependencies from Ops Center into a local directory or another Ops Center")
+	tele.MirrorCmd.Source = tele.MirrorCmd.Arg("source", "Address of the Ops Center to mirror applications from").Required().String()
+	tele.MirrorCmd.Destination = tele.MirrorCmd.Arg("destination", "Local directory or address of the Ops Center to mirror applications into").Required().String()
+	tele.MirrorCmd.Apps = tele.MirrorCmd.Flag("app", "Application to mirror as <name> or <name>:<version constraint>, e.g. 'app:>=1.0 <2.0'. Can be specified multiple times, mirrors all applications if unspecified").Strings()
+	tele.MirrorCmd.Labels = tele.MirrorCmd.Flag("label", "Only mirror applications with the specified label as key=value. Can be specified multiple times").StringMap()
+	tele.MirrorCmd.Runtimes = tele.MirrorCmd.Flag("runtimes", "Also mirror runtimes when no applications are specified").Bool()
+	tele.MirrorCmd.DryRun = tele.MirrorCmd.Flag("dry-run", "Only display what would be mirrored").Bool()
+
 	return tele
 }
@@ -59,6 +59,8 @@ func Run(tele Application) error {
 			SkipVersionCheck: *tele.BuildCmd.SkipVersionCheck,
 			Silent:           *tele.BuildCmd.Quiet,
 			Insecure:         *tele.Insecure,
+			SignKeyPath:      *tele.BuildCmd.SignKey,
+			DeltaFrom:        *tele.BuildCmd.DeltaFrom,
 		}, service.VendorRequest{
 			PackageName:            *tele.BuildCmd.Name,
 			PackageVersion:         *tele.BuildCmd.Version,
@@ -102,6 +104,15 @@ func Run(tele Application) error {
 		return list(*env,
 			*tele.ListCmd.All,
 			*tele.ListCmd.Format)
+	case tele.MirrorCmd.FullCommand():
+		return mirrorApps(context.Background(), env, mirrorParams{
+			Source:      *tele.MirrorCmd.Source,
+			Destination: *tele.MirrorCmd.Destination,
+			Apps:        *tele.MirrorCmd.Apps,
+			Labels:      *tele.MirrorCmd.Labels,
+			Runtimes:    *tele.MirrorCmd.Runtimes,
+			DryRun:      *tele.MirrorCmd.DryRun,
+		})
 	}
 
 	return trace.NotFound("unknown command %v", cmd)